@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/providers"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+)
+
+func newChaosCmd(ct clients.ContainerTasks, l hclog.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "chaos [enable|disable] [resource]",
+		Short: "Enable or disable the network faults for a chaos resource",
+		Long:  `Enable or disable the network faults for a chaos resource`,
+		Example: `
+  # Remove the faults injected by chaos.slow_db
+  shipyard chaos disable chaos.slow_db
+
+  # Inject the faults again
+  shipyard chaos enable chaos.slow_db
+		`,
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			action := args[0]
+			if action != "enable" && action != "disable" {
+				return fmt.Errorf("Unknown action %s, action must be enable or disable", action)
+			}
+
+			sc := config.New()
+			err := sc.FromJSON(utils.StatePath())
+			if err != nil {
+				return fmt.Errorf("No resources are running, start a stack with 'shipyard run [blueprint]'")
+			}
+
+			r, err := sc.FindResource(args[1])
+			if err != nil {
+				return xerrors.Errorf("Unable to find resource %s: %w", args[1], err)
+			}
+
+			ch, ok := r.(*config.Chaos)
+			if !ok {
+				return fmt.Errorf("Resource %s is not a chaos resource", args[1])
+			}
+
+			p := providers.NewChaos(ch, ct, l)
+
+			if action == "enable" {
+				err = p.Enable()
+			} else {
+				err = p.Disable()
+			}
+
+			if err != nil {
+				return err
+			}
+
+			// store the setting in the state
+			ch.Enabled = action == "enable"
+
+			return sc.ToJSON(utils.StatePath())
+		},
+	}
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupChaos(state string) (*cobra.Command, *mocks.MockContainerTasks, func()) {
+	mt := &mocks.MockContainerTasks{}
+	mt.On("FindContainerIDs", mock.Anything, mock.Anything).Return([]string{"abc"}, nil)
+	mt.On("ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	return newChaosCmd(mt, hclog.NewNullLogger()), mt, setupState(state)
+}
+
+func TestChaosDisableRemovesFaultsAndUpdatesState(t *testing.T) {
+	c, mt, cleanup := setupChaos(chaosState)
+	defer cleanup()
+
+	c.SetArgs([]string{"disable", "chaos.slow"})
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	mt.AssertCalled(t, "FindContainerIDs", "slow-consul", config.TypeChaos)
+	cmd := getCalls(&mt.Mock, "ExecuteCommand")[0].Arguments[1].([]string)
+	assert.Contains(t, cmd[2], "tc qdisc del")
+
+	sc := config.New()
+	err = sc.FromJSON(utils.StatePath())
+	require.NoError(t, err)
+
+	r, err := sc.FindResource("chaos.slow")
+	require.NoError(t, err)
+	assert.False(t, r.(*config.Chaos).Enabled)
+}
+
+func TestChaosEnableAppliesFaults(t *testing.T) {
+	c, mt, cleanup := setupChaos(chaosState)
+	defer cleanup()
+
+	c.SetArgs([]string{"enable", "chaos.slow"})
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	cmd := getCalls(&mt.Mock, "ExecuteCommand")[0].Arguments[1].([]string)
+	assert.Contains(t, cmd[2], "netem delay 200ms")
+}
+
+func TestChaosWithInvalidActionReturnsError(t *testing.T) {
+	c, _, cleanup := setupChaos(chaosState)
+	defer cleanup()
+
+	c.SetArgs([]string{"pause", "chaos.slow"})
+
+	err := c.Execute()
+	assert.Error(t, err)
+}
+
+func TestChaosWithNonChaosResourceReturnsError(t *testing.T) {
+	c, _, cleanup := setupChaos(chaosState)
+	defer cleanup()
+
+	c.SetArgs([]string{"enable", "container.consul"})
+
+	err := c.Execute()
+	assert.Error(t, err)
+}
+
+func TestChaosReturnsErrorWhenCommandFails(t *testing.T) {
+	c, mt, cleanup := setupChaos(chaosState)
+	defer cleanup()
+
+	removeOn(&mt.Mock, "ExecuteCommand")
+	mt.On("ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
+
+	c.SetArgs([]string{"enable", "chaos.slow"})
+
+	err := c.Execute()
+	assert.Error(t, err)
+}
+
+var chaosState = `
+{
+  "blueprint": null,
+  "resources": [
+	{
+      "name": "dc1",
+      "status": "applied",
+      "subnet": "10.15.0.0/16",
+      "type": "network"
+	},
+	{
+      "name": "consul",
+      "status": "applied",
+	  "type": "container",
+	  "networks": [{
+		"name": "network.dc1"
+	  }]
+	},
+	{
+      "name": "slow",
+      "status": "applied",
+	  "type": "chaos",
+	  "targets": ["network.dc1"],
+	  "latency": "200ms",
+	  "enabled": true
+	}
+  ]
+}
+`
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/server"
+	"github.com/shipyard-run/shipyard/pkg/shipyard"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+func newDaemonCmd(e shipyard.Engine, bp clients.Getter, ct clients.ContainerTasks, l hclog.Logger) *cobra.Command {
+	var addr string
+	var enableMetrics bool
+
+	daemonCmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Run Shipyard as a daemon exposing a HTTP API",
+		Long: `Run Shipyard as a daemon exposing a HTTP API, the API allows blueprints
+to be applied and destroyed, and the status and logs of resources to be queried
+by remote clients`,
+		Example: `
+  # Start the API server on the default address
+  shipyard daemon
+
+  # Apply a blueprint using the API, the token is written to
+  # $HOME/.shipyard/daemon.token when the daemon starts
+  curl -XPOST localhost:9876/v1/apply \
+    -H "Content-Type: application/json" \
+    -H "Authorization: Bearer $(cat ~/.shipyard/daemon.token)" \
+    -d '{"source": "github.com/shipyard-run/blueprints//vault-k8s"}'
+
+  # View the dashboard in the browser
+  open http://localhost:9876
+
+  # Start the API server and expose Prometheus metrics on /metrics
+  shipyard daemon --metrics
+
+  # Check the status of the environment
+  curl localhost:9876/v1/status
+	`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			token, err := server.WriteToken(utils.DaemonTokenPath())
+			if err != nil {
+				return err
+			}
+
+			s := server.New(e, bp, ct, l)
+			s.RequireToken(token)
+			if enableMetrics {
+				s.EnableMetrics()
+			}
+
+			cmd.Println("Starting Shipyard API server on", addr)
+			return s.ListenAndServe(addr)
+		},
+	}
+
+	daemonCmd.Flags().StringVarP(&addr, "address", "", "localhost:9876", "Address the API server listens on")
+
+	daemonCmd.Flags().BoolVarP(&enableMetrics, "metrics", "", false, "Expose Prometheus metrics on the /metrics endpoint")
+
+	return daemonCmd
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/server"
+	"github.com/shipyard-run/shipyard/pkg/shipyard"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+func newDashboardCmd(e shipyard.Engine, bp clients.Getter, ct clients.ContainerTasks, bc clients.System, l hclog.Logger) *cobra.Command {
+	var addr string
+	var noBrowser bool
+
+	dashboardCmd := &cobra.Command{
+		Use:   "dashboard",
+		Short: "Open a web dashboard for the current environment",
+		Long: `Open a web dashboard for the current environment, the dashboard shows
+the status of resources, the dependencies between them, links to exposed ports,
+and the logs for running containers`,
+		Example: `
+  # Start the dashboard and open it in the browser
+  shipyard dashboard
+
+  # Start the dashboard on a different port without opening the browser
+  shipyard dashboard --address localhost:8080 --no-browser
+	`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			token, err := server.WriteToken(utils.DaemonTokenPath())
+			if err != nil {
+				return err
+			}
+
+			s := server.New(e, bp, ct, l)
+			s.RequireToken(token)
+
+			// listen before opening the browser so the page is available
+			// as soon as the browser requests it
+			li, err := net.Listen("tcp", addr)
+			if err != nil {
+				return fmt.Errorf("Unable to start dashboard: %s", err)
+			}
+
+			uri := fmt.Sprintf("http://%s", li.Addr().String())
+			cmd.Println("Dashboard running at", uri)
+
+			if !noBrowser {
+				err := bc.OpenBrowser(uri)
+				if err != nil {
+					l.Error("Unable to open browser", "error", err)
+				}
+			}
+
+			return http.Serve(li, s.Handler())
+		},
+	}
+
+	dashboardCmd.Flags().StringVarP(&addr, "address", "", "localhost:9876", "Address the dashboard listens on")
+	dashboardCmd.Flags().BoolVarP(&noBrowser, "no-browser", "", false, "Do not open the dashboard in the browser")
+
+	return dashboardCmd
+}
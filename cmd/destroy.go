@@ -2,35 +2,48 @@ package cmd
 
 import (
 	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/shipyard"
 	"github.com/spf13/cobra"
 )
 
-var destroyCmd = &cobra.Command{
-	Use:   "destroy [file]",
-	Short: "Destroy the current stack or file",
-	Long: `Destroy the current stack or file. 
+func newDestroyCmd(e shipyard.Engine) *cobra.Command {
+	opts := shipyard.DefaultOptions()
+
+	destroyCmd := &cobra.Command{
+		Use:   "destroy [file]",
+		Short: "Destroy the current stack or file",
+		Long: `Destroy the current stack or file.
 	If the optional parameter "file" is passed then only the resources contained
 	in the file will be destroyed`,
-	Example: `yard destroy`,
-	Run: func(cmd *cobra.Command, args []string) {
-		dst := ""
-		if len(args) > 0 {
-			dst = args[0]
-		}
-
-		// When destroying a stack all the config
-		// which is created with apply is copied
-		// to the state folder
-		var err error
-		if dst == "" {
-			err = engine.Destroy(dst, true)
-		} else {
-			err = engine.Destroy(dst, false)
-		}
-
-		if err != nil {
-			hclog.Default().Error("Unable to destroy stack", "error", err)
-			return
-		}
-	},
+		Example: `yard destroy
+
+	# Destroy a cluster and the resources which depend on it
+	yard destroy --target k8s_cluster.k3s`,
+		Run: func(cmd *cobra.Command, args []string) {
+			dst := ""
+			if len(args) > 0 {
+				dst = args[0]
+			}
+
+			// When destroying a stack all the config
+			// which is created with apply is copied
+			// to the state folder
+			var err error
+			if dst == "" {
+				err = e.DestroyWithOptions(dst, true, opts)
+			} else {
+				err = e.DestroyWithOptions(dst, false, opts)
+			}
+
+			if err != nil {
+				hclog.Default().Error("Unable to destroy stack", "error", err)
+				return
+			}
+		},
+	}
+
+	destroyCmd.Flags().IntVarP(&opts.Parallelism, "parallelism", "", shipyard.DefaultParallelism, "The number of resources which are destroyed at the same time, 0 does not limit the number")
+	destroyCmd.Flags().StringArrayVarP(&opts.Targets, "target", "", nil, "Only destroy the given resource and the resources which depend on it e.g. --target k8s_cluster.k3s, wildcards such as container.* are supported, can be specified multiple times")
+
+	return destroyCmd
 }
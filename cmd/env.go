@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 	"runtime"
 
 	"github.com/shipyard-run/shipyard/pkg/config"
@@ -36,7 +35,7 @@ func newEnvCmd(e shipyard.Engine) *cobra.Command {
 			err := c.FromJSON(utils.StatePath())
 			if err != nil {
 				fmt.Println("Unable to load state", err)
-				os.Exit(1)
+				exit(1)
 			}
 
 			if c.Blueprint != nil && len(c.Blueprint.Environment) > 0 {
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"time"
@@ -134,7 +135,7 @@ func createK8sShell(r config.Resource, dt clients.ContainerTasks, pod, container
 
 	// start a tools container
 	i := config.Image{Name: "shipyardrun/ingress:latest"}
-	err := dt.PullImage(i, false)
+	err := dt.PullImage(context.Background(), i, false)
 	if err != nil {
 		return xerrors.Errorf("Could pull ingress image. Error: %w", err)
 	}
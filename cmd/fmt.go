@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+func newFmtCmd() *cobra.Command {
+	var check bool
+
+	fmtCmd := &cobra.Command{
+		Use:   "fmt [file] | [directory]",
+		Short: "Format the configuration files for a blueprint",
+		Long: `Rewrite the configuration files for a blueprint to the canonical format,
+blocks are sorted by resource type and attributes are aligned. The names of any
+files which have been changed are printed.`,
+		Example: `
+  # Format the blueprint in the current folder
+  shipyard fmt
+
+  # Check the files are formatted without changing them
+  shipyard fmt --check ./my-stack
+	`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dst := "./"
+			if len(args) == 1 {
+				dst = args[0]
+			}
+
+			files, err := formatFiles(dst)
+			if err != nil {
+				return err
+			}
+
+			unformatted := 0
+			for _, f := range files {
+				src, err := ioutil.ReadFile(f)
+				if err != nil {
+					return err
+				}
+
+				out, err := config.Format(src, f)
+				if err != nil {
+					if writeParseError(cmd.ErrOrStderr(), err) {
+						return fmt.Errorf("Unable to format %s, the file contains errors", f)
+					}
+
+					return fmt.Errorf("Unable to format %s: %s", f, err)
+				}
+
+				if bytes.Equal(src, out) {
+					continue
+				}
+
+				unformatted++
+				fmt.Fprintln(cmd.OutOrStdout(), f)
+
+				if check {
+					continue
+				}
+
+				err = ioutil.WriteFile(f, out, 0644)
+				if err != nil {
+					return fmt.Errorf("Unable to write %s: %s", f, err)
+				}
+			}
+
+			if check && unformatted > 0 {
+				return fmt.Errorf("%d file(s) are not formatted, run shipyard fmt to format them", unformatted)
+			}
+
+			return nil
+		},
+	}
+
+	fmtCmd.Flags().BoolVarP(&check, "check", "", false, "Check the files are formatted without changing them, returns an error if any files need formatting")
+
+	return fmtCmd
+}
+
+// formatFiles returns the config files to format for a file or folder
+func formatFiles(dst string) ([]string, error) {
+	s, err := os.Stat(dst)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.IsDir() {
+		if config.IsEncryptedFile(dst) {
+			return nil, fmt.Errorf("Unable to format %s, encrypted files can not be formatted", dst)
+		}
+
+		return []string{dst}, nil
+	}
+
+	files := []string{}
+	for _, p := range []string{"*.hcl", "*.yard"} {
+		f, err := filepath.Glob(filepath.Join(dst, p))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, ff := range f {
+			// encrypted files are skipped as the contents are not HCL
+			if !config.IsEncryptedFile(ff) {
+				files = append(files, ff)
+			}
+		}
+	}
+
+	return files, nil
+}
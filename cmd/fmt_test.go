@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupFmt(t *testing.T, contents string) (*cobra.Command, *bytes.Buffer, string, func()) {
+	dir, err := ioutil.TempDir("", "")
+	assert.NoError(t, err)
+
+	f := filepath.Join(dir, "config.hcl")
+	ioutil.WriteFile(f, []byte(contents), os.ModePerm)
+
+	out := bytes.NewBufferString("")
+
+	c := newFmtCmd()
+	c.SetOut(out)
+	c.SetErr(bytes.NewBufferString(""))
+	c.SetArgs([]string{dir})
+
+	return c, out, f, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+func TestFmtWritesFormattedFiles(t *testing.T) {
+	c, out, f, cleanup := setupFmt(t, fmtUnformatted)
+	defer cleanup()
+
+	err := c.Execute()
+	assert.NoError(t, err)
+
+	assert.Contains(t, out.String(), f)
+
+	d, err := ioutil.ReadFile(f)
+	assert.NoError(t, err)
+	assert.Equal(t, fmtFormatted, string(d))
+}
+
+func TestFmtCheckReturnsErrorAndDoesNotWrite(t *testing.T) {
+	c, out, f, cleanup := setupFmt(t, fmtUnformatted)
+	defer cleanup()
+
+	c.Flags().Set("check", "true")
+
+	err := c.Execute()
+	assert.Error(t, err)
+
+	assert.Contains(t, out.String(), f)
+
+	d, err := ioutil.ReadFile(f)
+	assert.NoError(t, err)
+	assert.Equal(t, fmtUnformatted, string(d))
+}
+
+func TestFmtCheckWithFormattedFilesSucceeds(t *testing.T) {
+	c, out, _, cleanup := setupFmt(t, fmtFormatted)
+	defer cleanup()
+
+	c.Flags().Set("check", "true")
+
+	err := c.Execute()
+	assert.NoError(t, err)
+
+	assert.Empty(t, out.String())
+}
+
+const fmtUnformatted = `network "local" {
+subnet="10.5.0.0/16"
+}
+`
+
+const fmtFormatted = `network "local" {
+  subnet = "10.5.0.0/16"
+}
+`
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+func newGraphCmd() *cobra.Command {
+	var variables []string
+	var varsFile string
+	var format string
+
+	graphCmd := &cobra.Command{
+		Use:   "graph [file] | [directory]",
+		Short: "Output the dependency graph for a blueprint",
+		Long: `Output the dependency graph for a blueprint and the current stack as DOT or Mermaid. Nodes are
+labeled with the type, name, and status of the resource, edges point from a resource to the resources which depend on it`,
+		Example: `
+  # Render the graph for the blueprint in the current folder with Graphviz
+  shipyard graph | dot -Tsvg > graph.svg
+
+  # Output the graph as a Mermaid flowchart
+  shipyard graph --format mermaid ./my-stack
+	`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dst := "./"
+			if len(args) == 1 {
+				dst = args[0]
+			}
+
+			c, err := parseConfig(dst, variables, varsFile, config.ParseOptions{})
+			if err != nil {
+				return err
+			}
+
+			// merge with the state so the graph contains the same resources as run
+			sc, err := loadState()
+			if err != nil {
+				return err
+			}
+
+			sc.Merge(c)
+
+			d, err := sc.DoYaLikeDAGs()
+			if err != nil {
+				return fmt.Errorf("Unable to create dependency graph: %s", err)
+			}
+
+			d.TransitiveReduction()
+
+			return config.WriteGraph(cmd.OutOrStdout(), d, format)
+		},
+	}
+
+	graphCmd.Flags().StringArrayVarP(&variables, "var", "", nil, "Set a value for a variable defined in the blueprint e.g. --var name=value, can be specified multiple times")
+	graphCmd.Flags().StringVarP(&varsFile, "vars-file", "", "", "Load values for variables defined in the blueprint from a file, values set with --var take precedence")
+	graphCmd.Flags().StringVarP(&format, "format", "", config.GraphFormatDOT, "Output format for the graph, dot or mermaid")
+
+	return graphCmd
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphWritesMermaidForBlueprint(t *testing.T) {
+	home := os.Getenv("HOME")
+	dir, err := ioutil.TempDir("", "")
+	assert.NoError(t, err)
+	os.Setenv("HOME", dir)
+	defer func() {
+		os.Setenv("HOME", home)
+		os.RemoveAll(dir)
+	}()
+
+	ioutil.WriteFile(filepath.Join(dir, "config.hcl"), []byte(planConfig), os.ModePerm)
+
+	out := bytes.NewBufferString("")
+
+	c := newGraphCmd()
+	c.SetOut(out)
+	c.SetArgs([]string{"--format", "mermaid", dir})
+
+	err = c.Execute()
+	assert.NoError(t, err)
+
+	assert.Contains(t, out.String(), `r0["container.consul<br/>(pending_creation)"]`)
+	assert.Contains(t, out.String(), `r1["network.cloud<br/>(pending_creation)"]`)
+	assert.Contains(t, out.String(), "r1 --> r0")
+}
+
+func TestGraphReturnsErrorWhenStateCannotBeLoaded(t *testing.T) {
+	home := os.Getenv("HOME")
+	dir, err := ioutil.TempDir("", "")
+	assert.NoError(t, err)
+	os.Setenv("HOME", dir)
+	defer func() {
+		os.Setenv("HOME", home)
+		os.RemoveAll(dir)
+	}()
+
+	os.MkdirAll(utils.StateDir(), os.ModePerm)
+	ioutil.WriteFile(utils.StatePath(), []byte("{"), os.ModePerm)
+	ioutil.WriteFile(filepath.Join(dir, "config.hcl"), []byte(planConfig), os.ModePerm)
+
+	c := newGraphCmd()
+	c.SetOut(bytes.NewBufferString(""))
+	c.SetArgs([]string{dir})
+
+	err = c.Execute()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Unable to load state")
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	gojson "encoding/json"
+	"fmt"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+func newOutputCmd() *cobra.Command {
+	outputCmd := &cobra.Command{
+		Use:   "output [name]",
+		Short: "Show the outputs defined by the blueprint",
+		Long: `Show the outputs defined by the blueprint in JSON format, when a name
+is specified only the value of that output is shown`,
+		Example: `
+  # Show all outputs
+  shipyard output
+
+  # Show a single output
+  shipyard output consul_addr
+	`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := config.New()
+			err := c.FromJSON(utils.StatePath())
+			if err != nil {
+				return fmt.Errorf("Unable to load state: %s", err)
+			}
+
+			if len(args) == 1 {
+				o, err := c.FindOutput(args[0])
+				if err != nil {
+					return err
+				}
+
+				if o.Sensitive {
+					fmt.Fprintln(cmd.OutOrStdout(), config.RedactedValue)
+					return nil
+				}
+
+				// strings are printed without quotes so they can be used in scripts
+				if s, ok := o.Value.(string); ok {
+					fmt.Fprintln(cmd.OutOrStdout(), config.Redact(s))
+					return nil
+				}
+
+				return printJSON(cmd, o.Value)
+			}
+
+			outputs := map[string]interface{}{}
+			for _, o := range c.Outputs {
+				if o.Sensitive {
+					outputs[o.Name] = config.RedactedValue
+					continue
+				}
+
+				outputs[o.Name] = o.Value
+			}
+
+			return printJSON(cmd, outputs)
+		},
+	}
+
+	return outputCmd
+}
+
+func printJSON(cmd *cobra.Command, v interface{}) error {
+	d, err := gojson.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), config.Redact(string(d)))
+
+	return nil
+}
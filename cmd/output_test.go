@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupOutput(t *testing.T, state string) (*cobra.Command, *bytes.Buffer, func()) {
+	home := os.Getenv("HOME")
+	dir, err := ioutil.TempDir("", "")
+	assert.NoError(t, err)
+	os.Setenv("HOME", dir)
+
+	if state != "" {
+		os.MkdirAll(utils.StateDir(), os.ModePerm)
+		ioutil.WriteFile(utils.StatePath(), []byte(state), os.ModePerm)
+	}
+
+	out := bytes.NewBufferString("")
+	c := newOutputCmd()
+	c.SetOut(out)
+
+	return c, out, func() {
+		os.Setenv("HOME", home)
+		os.RemoveAll(dir)
+	}
+}
+
+func TestOutputShowsAllOutputsAsJSON(t *testing.T) {
+	c, out, cleanup := setupOutput(t, outputState)
+	defer cleanup()
+
+	err := c.Execute()
+	assert.NoError(t, err)
+
+	assert.JSONEq(t, `{"addr": "http://localhost:8500", "ports": [8500, 8501], "token": "(sensitive value)"}`, out.String())
+}
+
+func TestOutputShowsRawStringForSingleOutput(t *testing.T) {
+	c, out, cleanup := setupOutput(t, outputState)
+	defer cleanup()
+	c.SetArgs([]string{"addr"})
+
+	err := c.Execute()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "http://localhost:8500\n", out.String())
+}
+
+func TestOutputRedactsSensitiveOutputs(t *testing.T) {
+	c, out, cleanup := setupOutput(t, outputState)
+	defer cleanup()
+
+	err := c.Execute()
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), `"token": "(sensitive value)"`)
+
+	c, out, cleanup = setupOutput(t, outputState)
+	defer cleanup()
+	c.SetArgs([]string{"token"})
+
+	err = c.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "(sensitive value)\n", out.String())
+}
+
+func TestOutputWithUnknownNameReturnsError(t *testing.T) {
+	c, _, cleanup := setupOutput(t, outputState)
+	defer cleanup()
+	c.SetArgs([]string{"nope"})
+
+	err := c.Execute()
+	assert.Error(t, err)
+}
+
+func TestOutputWithNoStateReturnsError(t *testing.T) {
+	c, _, cleanup := setupOutput(t, "")
+	defer cleanup()
+
+	err := c.Execute()
+	assert.Error(t, err)
+}
+
+var outputState = `
+{
+  "blueprint": null,
+  "resources": [],
+  "outputs": [
+    {"name": "addr", "value": "http://localhost:8500"},
+    {"name": "ports", "value": [8500, 8501]},
+    {"name": "token", "value": 1234, "sensitive": true}
+  ]
+}
+`
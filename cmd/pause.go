@@ -3,7 +3,6 @@ package cmd
 import (
 	"context"
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/docker/docker/api/types"
@@ -28,7 +27,7 @@ var pauseCmd = &cobra.Command{
 		c, err := clients.NewDocker()
 		if err != nil {
 			fmt.Println("Unable to connect to Docker daemon", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		filters := filters.NewArgs()
@@ -47,7 +46,7 @@ var pauseCmd = &cobra.Command{
 			err := c.ContainerStop(context.Background(), con.ID, &sd)
 			if err != nil {
 				fmt.Println("Unable to stop container", con.Names[0], err)
-				os.Exit(1)
+				exit(1)
 			}
 		}
 	},
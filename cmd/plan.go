@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	gojson "encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+func newPlanCmd() *cobra.Command {
+	var variables []string
+	var varsFile string
+
+	planCmd := &cobra.Command{
+		Use:   "plan [file] | [directory]",
+		Short: "Show the changes run will make to the current stack",
+		Long: `Compare the configuration for a blueprint with the state of the current stack and show
+the resources which run will create or replace. No resources are changed.
+
+Run does not update existing resources, changed attributes are shown but are only applied when
+the resource is replaced e.g. with run --replace. Resources which are no longer in the configuration
+are kept until they are removed with destroy`,
+		Example: `
+  # Show the changes for the blueprint in the current folder
+  shipyard plan
+
+  # Show the changes setting the value of a variable
+  shipyard plan --var consul_version=1.8.0 ./my-stack
+	`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dst := "./"
+			if len(args) == 1 {
+				dst = args[0]
+			}
+
+			c, err := parseConfig(dst, variables, varsFile, config.ParseOptions{})
+			if err != nil {
+				return err
+			}
+
+			sc, err := loadState()
+			if err != nil {
+				return err
+			}
+
+			changes, err := config.Plan(sc, c)
+			if err != nil {
+				return fmt.Errorf("Unable to compare configuration with state: %s", err)
+			}
+
+			writePlan(cmd.OutOrStdout(), changes)
+
+			return nil
+		},
+	}
+
+	planCmd.Flags().StringArrayVarP(&variables, "var", "", nil, "Set a value for a variable defined in the blueprint e.g. --var name=value, can be specified multiple times")
+	planCmd.Flags().StringVarP(&varsFile, "vars-file", "", "", "Load values for variables defined in the blueprint from a file, values set with --var take precedence")
+
+	return planCmd
+}
+
+func writePlan(w io.Writer, changes []config.Change) {
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "No changes, the stack matches the configuration")
+		return
+	}
+
+	counts := map[config.ChangeAction]int{}
+
+	fmt.Fprintln(w)
+	for _, c := range changes {
+		counts[c.Action]++
+
+		symbol := ""
+		note := ""
+		switch c.Action {
+		case config.ChangeCreate:
+			symbol = fmt.Sprintf(Green, "  +")
+		case config.ChangeReplace:
+			symbol = fmt.Sprintf(Yellow, "-/+")
+		case config.ChangeNotApplied:
+			symbol = fmt.Sprintf(Red, "  !")
+			note = " (not applied, use run --replace to re-create the resource)"
+		case config.ChangeKept:
+			symbol = "  ="
+			note = " (not in the configuration, use destroy to remove)"
+		}
+
+		fmt.Fprintf(w, " %s %s.%s%s\n", symbol, c.Resource.Info().Type, c.Resource.Info().Name, note)
+
+		for _, a := range c.Attributes {
+			fmt.Fprintf(w, "       %s: %s => %s\n", a.Name, planValue(a.Old), planValue(a.New))
+		}
+	}
+
+	fmt.Fprintf(
+		w,
+		"\nPlan: %d to create, %d to replace, %d changed but not applied, %d kept\n",
+		counts[config.ChangeCreate], counts[config.ChangeReplace], counts[config.ChangeNotApplied], counts[config.ChangeKept],
+	)
+}
+
+func planValue(v interface{}) string {
+	if v == nil {
+		return "(not set)"
+	}
+
+	d, _ := gojson.Marshal(v)
+	return string(d)
+}
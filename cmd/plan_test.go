@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupPlan(t *testing.T, contents, state string) (*cobra.Command, *bytes.Buffer, func()) {
+	home := os.Getenv("HOME")
+	dir, err := ioutil.TempDir("", "")
+	assert.NoError(t, err)
+	os.Setenv("HOME", dir)
+
+	if state != "" {
+		os.MkdirAll(utils.StateDir(), os.ModePerm)
+		ioutil.WriteFile(utils.StatePath(), []byte(state), os.ModePerm)
+	}
+
+	bp := filepath.Join(dir, "blueprint")
+	os.MkdirAll(bp, os.ModePerm)
+	ioutil.WriteFile(filepath.Join(bp, "config.hcl"), []byte(contents), os.ModePerm)
+
+	out := bytes.NewBufferString("")
+
+	c := newPlanCmd()
+	c.SetOut(out)
+	c.SetArgs([]string{bp})
+
+	return c, out, func() {
+		os.Setenv("HOME", home)
+		os.RemoveAll(dir)
+	}
+}
+
+func TestPlanWithNoStateShowsAllResourcesCreated(t *testing.T) {
+	c, out, cleanup := setupPlan(t, planConfig, "")
+	defer cleanup()
+
+	err := c.Execute()
+	assert.NoError(t, err)
+
+	assert.Contains(t, out.String(), "network.cloud")
+	assert.Contains(t, out.String(), "container.consul")
+	assert.Contains(t, out.String(), "Plan: 2 to create, 0 to replace, 0 changed but not applied, 0 kept")
+}
+
+func TestPlanShowsChangedAttributes(t *testing.T) {
+	c, out, cleanup := setupPlan(t, planConfig, planState)
+	defer cleanup()
+
+	err := c.Execute()
+	assert.NoError(t, err)
+
+	assert.Contains(t, out.String(), `image: {"name":"consul:1.7.2"} => {"name":"consul:1.8.0"}`)
+	assert.Contains(t, out.String(), "Plan: 1 to create, 0 to replace, 1 changed but not applied, 1 kept")
+}
+
+const planConfig = `
+network "cloud" {
+	subnet = "10.5.0.0/16"
+}
+
+container "consul" {
+	image {
+		name = "consul:1.8.0"
+	}
+
+	network {
+		name = "network.cloud"
+	}
+}
+`
+
+const planState = `
+{
+  "blueprint": null,
+  "resources": [
+    {
+      "name": "consul",
+      "type": "container",
+      "status": "applied",
+      "depends_on": ["network.cloud"],
+      "networks": [{"name": "network.cloud"}],
+      "image": {"name": "consul:1.7.2"}
+    },
+    {
+      "name": "old",
+      "type": "network",
+      "status": "applied",
+      "subnet": "10.6.0.0/16"
+    }
+  ]
+}
+`
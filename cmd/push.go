@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -82,7 +83,7 @@ func pushK8sCluster(image string, c *config.K8sCluster, ct clients.ContainerTask
 
 	for _, id := range ids {
 		log.Info("Pushing to container", "id", id, "image", image)
-		err = cl.ImportLocalDockerImages(utils.ImageVolumeName, id, []config.Image{config.Image{Name: strings.Trim(image, " ")}}, force)
+		err = cl.ImportLocalDockerImages(context.Background(), utils.ImageVolumeName, id, []config.Image{config.Image{Name: strings.Trim(image, " ")}}, force)
 		if err != nil {
 			return xerrors.Errorf("Error pushing image: %w ", err)
 		}
@@ -92,7 +93,7 @@ func pushK8sCluster(image string, c *config.K8sCluster, ct clients.ContainerTask
 }
 
 func pushNomadCluster(image string, c *config.NomadCluster, ct clients.ContainerTasks, ht clients.Nomad, log hclog.Logger, force bool) error {
-	cl := providers.NewNomadCluster(c, ct, ht, log)
+	cl := providers.NewNomadCluster(c, ct, ht, nil, log)
 
 	// get the id of the cluster
 	ids, err := cl.Lookup()
@@ -102,7 +103,7 @@ func pushNomadCluster(image string, c *config.NomadCluster, ct clients.Container
 
 	for _, id := range ids {
 		log.Info("Pushing to container", "id", id, "image", image)
-		err = cl.ImportLocalDockerImages(utils.ImageVolumeName, id, []config.Image{config.Image{Name: strings.Trim(image, " ")}}, force)
+		err = cl.ImportLocalDockerImages(context.Background(), utils.ImageVolumeName, id, []config.Image{config.Image{Name: strings.Trim(image, " ")}}, force)
 		if err != nil {
 			return xerrors.Errorf("Error pushing image: %w ", err)
 		}
@@ -3,7 +3,6 @@ package cmd
 import (
 	"context"
 	"fmt"
-	"os"
 
 	"time"
 
@@ -34,13 +33,13 @@ var resumeCmd = &cobra.Command{
 		c, err := clients.NewDocker()
 		if err != nil {
 			l.Error("Unable to connect to Docker daemon", "error", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		cl, err := getContainers(c, "exited")
 		if err != nil {
 			l.Error("Unable to get container status", "error", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		// start the containers
@@ -48,7 +47,7 @@ var resumeCmd = &cobra.Command{
 			err := c.ContainerStart(context.Background(), con.ID, types.ContainerStartOptions{})
 			if err != nil {
 				l.Error("Unable to start container", "name", con.Names[0], "error", err)
-				os.Exit(1)
+				exit(1)
 			}
 		}
 
@@ -57,7 +56,7 @@ var resumeCmd = &cobra.Command{
 		_, err = checkStatus(c)
 		if err != nil {
 			l.Error("Uable to check health of containers", "error", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		// get the health checks from the config and test
@@ -65,7 +64,7 @@ var resumeCmd = &cobra.Command{
 		err = con.FromJSON(utils.StatePath())
 		if err != nil {
 			l.Error("Unable to load state", "error", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		for _, res := range con.Resources {
@@ -79,7 +78,7 @@ var resumeCmd = &cobra.Command{
 					err := healthCheckHelm(co)
 					if err != nil {
 						l.Error("Unable to check health of helm chart", "error", err)
-						os.Exit(1)
+						exit(1)
 					}
 				}
 			case config.TypeK8sConfig:
@@ -91,7 +90,7 @@ var resumeCmd = &cobra.Command{
 					err := healthCheckK8sConfig(co)
 					if err != nil {
 						l.Error("Unable to check health of k8s_config chart", "error", err)
-						os.Exit(1)
+						exit(1)
 					}
 				}
 			}
@@ -2,9 +2,12 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/config"
 	"github.com/shipyard-run/shipyard/pkg/shipyard"
+	"github.com/shipyard-run/shipyard/pkg/tracing"
 
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
@@ -25,6 +28,9 @@ var engineClients *shipyard.Clients
 
 var version string
 
+// shutdownTracing flushes and closes the trace exporter created by Execute
+var shutdownTracing = func() {}
+
 func init() {
 	// setup dependencies
 	var err error
@@ -47,10 +53,17 @@ func init() {
 	rootCmd.AddCommand(pauseCmd)
 	rootCmd.AddCommand(resumeCmd)
 	rootCmd.AddCommand(newGetCmd(engineClients.Getter))
-	rootCmd.AddCommand(destroyCmd)
+	rootCmd.AddCommand(newDestroyCmd(engine))
 	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(newOutputCmd())
+	rootCmd.AddCommand(newValidateCmd())
+	rootCmd.AddCommand(newPlanCmd())
+	rootCmd.AddCommand(newGraphCmd())
+	rootCmd.AddCommand(newFmtCmd())
+	rootCmd.AddCommand(newSchemaCmd())
 	rootCmd.AddCommand(newPurgeCmd(engineClients.Docker, engineClients.ImageLog, logger))
 	rootCmd.AddCommand(taintCmd)
+	rootCmd.AddCommand(newChaosCmd(engineClients.ContainerTasks, logger))
 	rootCmd.AddCommand(newExecCmd(engineClients.ContainerTasks))
 	rootCmd.AddCommand(versionCmd)
 	//rootCmd.AddCommand(exposeCmd)
@@ -61,6 +74,8 @@ func init() {
 	//rootCmd.AddCommand(upgradeCmd)
 	rootCmd.AddCommand(uninstallCmd)
 	rootCmd.AddCommand(newPushCmd(engineClients.ContainerTasks, engineClients.Kubernetes, engineClients.HTTP, engineClients.Nomad, logger))
+	rootCmd.AddCommand(newDaemonCmd(engine, engineClients.Getter, engineClients.ContainerTasks, logger))
+	rootCmd.AddCommand(newDashboardCmd(engine, engineClients.Getter, engineClients.ContainerTasks, engineClients.Browser, logger))
 }
 
 func configure() {
@@ -89,5 +104,26 @@ func configure() {
 // Execute the root command
 func Execute(v string) error {
 	version = v
+	config.Version = v
+
+	// configure tracing, when OTEL_EXPORTER_OTLP_ENDPOINT is not set
+	// this is a no-op
+	shutdown, err := tracing.Setup(version)
+	if err != nil {
+		logger.Error("Unable to configure tracing", "error", err)
+	} else {
+		shutdownTracing = shutdown
+	}
+
+	defer shutdownTracing()
+
 	return rootCmd.Execute()
 }
+
+// exit shuts down tracing before exiting with the given code, deferred
+// functions do not run on os.Exit so commands must use exit to ensure
+// their spans are exported
+func exit(code int) {
+	shutdownTracing()
+	os.Exit(code)
+}
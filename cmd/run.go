@@ -1,10 +1,12 @@
 package cmd
 
 import (
+	gojson "encoding/json"
 	"fmt"
 	"os"
 	"runtime"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -19,9 +21,21 @@ import (
 	markdown "github.com/MichaelMure/go-term-markdown"
 )
 
+// runFlags are the values of the flags for the run command
+type runFlags struct {
+	noOpen      bool
+	force       bool
+	variables   []string
+	varsFile    string
+	maxDepth    int
+	permissive  bool
+	parallelism int
+	targets     []string
+	replace     []string
+}
+
 func newRunCmd(e shipyard.Engine, bp clients.Getter, hc clients.HTTP, bc clients.System, l hclog.Logger) *cobra.Command {
-	var noOpen bool
-	var force bool
+	f := &runFlags{}
 	runCmd := &cobra.Command{
 		Use:   "run [file] [directory] ...",
 		Short: "Run the supplied stack configuration",
@@ -35,20 +49,62 @@ func newRunCmd(e shipyard.Engine, bp clients.Getter, hc clients.HTTP, bc clients
   
   # Create a stack from a blueprint in GitHub
   shipyard run github.com/shipyard-run/blueprints//vault-k8s
+
+  # Create a stack from a tagged version of a blueprint in GitHub
+  shipyard run github.com/shipyard-run/blueprints//vault-k8s?ref=v0.1.0
+
+  # Create a stack from a blueprint stored as an OCI artifact
+  shipyard run oci://ghcr.io/shipyard-run/blueprints/vault-k8s:v0.1.0
+
+  # Override the default value of a variable defined in the blueprint
+  shipyard run --var consul_version=1.7.2 ./my-stack
+
+  # Set the values of variables from a file
+  shipyard run --vars-file ./dev.vars ./my-stack
+
+  # Only create a container and the resources it depends on
+  shipyard run --target container.web ./my-stack
+
+  # Re-create a cluster and the resources which depend on it
+  shipyard run --replace k8s_cluster.k3s ./my-stack
 	`,
 		Args:         cobra.ArbitraryArgs,
-		RunE:         newRunCmdFunc(e, bp, hc, bc, &noOpen, &force, l),
+		RunE:         newRunCmdFunc(e, bp, hc, bc, f, l),
 		SilenceUsage: true,
 	}
-	runCmd.Flags().BoolVarP(&noOpen, "no-browser", "", false, "When set to true Shipyard does not open the browser windows defined in the blueprint")
-	runCmd.Flags().BoolVarP(&force, "force-update", "", false, "When set to true Shipyard will ignore cached images or files and will download all resources")
+	runCmd.Flags().BoolVarP(&f.noOpen, "no-browser", "", false, "When set to true Shipyard does not open the browser windows defined in the blueprint")
+	runCmd.Flags().BoolVarP(&f.force, "force-update", "", false, "When set to true Shipyard will ignore cached images or files and will download all resources")
+	runCmd.Flags().StringArrayVarP(&f.variables, "var", "", nil, "Set a value for a variable defined in the blueprint e.g. --var name=value, can be specified multiple times")
+	runCmd.Flags().StringVarP(&f.varsFile, "vars-file", "", "", "Load values for variables defined in the blueprint from a file, values set with --var take precedence")
+	runCmd.Flags().IntVarP(&f.maxDepth, "max-depth", "", 0, "The number of levels of sub folders which are searched for config files")
+	runCmd.Flags().BoolVarP(&f.permissive, "permissive", "", false, "When set to true unknown attributes and blocks in the config are reported as warnings rather than errors")
+	runCmd.Flags().IntVarP(&f.parallelism, "parallelism", "", shipyard.DefaultParallelism, "The number of resources which are created at the same time, 0 does not limit the number")
+	runCmd.Flags().StringArrayVarP(&f.targets, "target", "", nil, "Only create the given resource and its dependencies e.g. --target container.web, wildcards such as container.* are supported, can be specified multiple times")
+	runCmd.Flags().StringArrayVarP(&f.replace, "replace", "", nil, "Destroy and create the given resource and the resources which depend on it e.g. --replace k8s_cluster.k3s, can be specified multiple times")
 
 	return runCmd
 }
 
-func newRunCmdFunc(e shipyard.Engine, bp clients.Getter, hc clients.HTTP, bc clients.System, noOpen *bool, force *bool, l hclog.Logger) func(cmd *cobra.Command, args []string) error {
+func newRunCmdFunc(e shipyard.Engine, bp clients.Getter, hc clients.HTTP, bc clients.System, f *runFlags, l hclog.Logger) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
-		if *force == true {
+		vars, err := parseVariables(f.variables, f.varsFile)
+		if err != nil {
+			return err
+		}
+
+		config.ResetParseWarnings()
+		opts := shipyard.Options{
+			Parallelism: f.parallelism,
+			Targets:     f.targets,
+			Replace:     f.replace,
+			Parse: config.ParseOptions{
+				MaxFolderDepth: f.maxDepth,
+				Permissive:     f.permissive,
+				ForceUpdate:    f.force,
+			},
+		}
+
+		if f.force == true {
 			bp.SetForce(true)
 			e.GetClients().ContainerTasks.SetForcePull(true)
 		}
@@ -106,13 +162,19 @@ func newRunCmdFunc(e shipyard.Engine, bp clients.Getter, hc clients.HTTP, bc cli
 		}
 
 		// Load the files
-		res, err := e.Apply(dst)
+		res, err := e.ApplyWithOptions(dst, vars, opts)
+		writeParseWarnings(cmd.ErrOrStderr())
 		if err != nil {
+			// show the location of any errors in the config
+			if writeParseError(cmd.ErrOrStderr(), err) {
+				return fmt.Errorf("Unable to apply blueprint, the configuration contains errors")
+			}
+
 			return fmt.Errorf("Unable to apply blueprint: %s", err)
 		}
 
 		// do not open the browser windows
-		if *noOpen == false {
+		if f.noOpen == false {
 
 			browserList := []string{}
 
@@ -190,6 +252,26 @@ func newRunCmdFunc(e shipyard.Engine, bp clients.Getter, hc clients.HTTP, bc cli
 
 		}
 
+		// show any outputs defined in the blueprint
+		if len(e.Outputs()) > 0 {
+			cmd.Println("")
+			cmd.Println("Outputs:")
+			cmd.Println("")
+
+			for _, o := range e.Outputs() {
+				if o.Sensitive {
+					cmd.Printf("%s = %s\n", o.Name, config.RedactedValue)
+					continue
+				}
+
+				d, _ := gojson.Marshal(o.Value)
+				cmd.Printf("%s = %s\n", o.Name, config.Redact(string(d)))
+			}
+
+			cmd.Println("")
+			cmd.Println("Use `shipyard output` to show the outputs in JSON format")
+		}
+
 		// if we have a blueprint show the header
 		if e.Blueprint() != nil {
 			cmd.Println("")
@@ -253,3 +335,30 @@ func bluePrintInState() bool {
 
 	return sc.Blueprint != nil
 }
+
+// parseVariables returns the values set with --var, when file is not
+// empty the values in the variables file are loaded first and any
+// values set with --var replace them
+func parseVariables(vars []string, file string) (map[string]string, error) {
+	out := map[string]string{}
+
+	if file != "" {
+		fv, err := config.ParseVariablesFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		out = fv
+	}
+
+	for _, v := range vars {
+		parts := strings.SplitN(v, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("Invalid variable %s, variables must be specified as name=value", v)
+		}
+
+		out[parts[0]] = parts[1]
+	}
+
+	return out, nil
+}
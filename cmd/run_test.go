@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -41,8 +44,9 @@ func setupRun(t *testing.T) (*cobra.Command, *mocks.Engine, *clientmocks.Getter,
 	}
 
 	mockEngine := &mocks.Engine{}
-	mockEngine.On("Apply", mock.Anything).Return(nil, nil)
+	mockEngine.On("ApplyWithOptions", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
 	mockEngine.On("GetClients", mock.Anything).Return(clients)
+	mockEngine.On("Outputs").Return(nil)
 	mockEngine.On("Blueprint").Return(&config.Blueprint{BrowserWindows: []string{"http://localhost", "http://localhost2"}})
 
 	return newRunCmd(mockEngine, mockGetter, mockHTTP, mockBrowser, hclog.Default()), mockEngine, mockGetter, mockHTTP, mockBrowser
@@ -58,6 +62,80 @@ func TestRunSetsForceOnGetter(t *testing.T) {
 	mg.AssertCalled(t, "SetForce", true)
 }
 
+func TestRunPassesVariablesToEngine(t *testing.T) {
+	rf, me, _, _, _ := setupRun(t)
+	rf.SetArgs([]string{"/tmp"})
+	rf.Flags().Set("var", "version=1.7.2")
+	rf.Flags().Set("var", "args=a=b")
+
+	err := rf.Execute()
+	assert.NoError(t, err)
+
+	me.AssertCalled(t, "ApplyWithOptions", "/tmp", map[string]string{"version": "1.7.2", "args": "a=b"}, mock.Anything)
+}
+
+func TestRunPassesOptionsToEngine(t *testing.T) {
+	rf, me, _, _, _ := setupRun(t)
+	rf.SetArgs([]string{"/tmp"})
+	rf.Flags().Set("parallelism", "2")
+	rf.Flags().Set("target", "container.web")
+	rf.Flags().Set("replace", "k8s_cluster.k3s")
+
+	err := rf.Execute()
+	assert.NoError(t, err)
+
+	opts := shipyard.Options{Parallelism: 2, Targets: []string{"container.web"}, Replace: []string{"k8s_cluster.k3s"}}
+	me.AssertCalled(t, "ApplyWithOptions", "/tmp", map[string]string{}, opts)
+}
+
+func TestRunPassesParseOptionsToEngine(t *testing.T) {
+	rf, me, _, _, _ := setupRun(t)
+	rf.SetArgs([]string{"/tmp"})
+	rf.Flags().Set("max-depth", "3")
+	rf.Flags().Set("permissive", "true")
+	rf.Flags().Set("force-update", "true")
+
+	err := rf.Execute()
+	assert.NoError(t, err)
+
+	opts := shipyard.Options{
+		Parallelism: shipyard.DefaultParallelism,
+		Parse:       config.ParseOptions{MaxFolderDepth: 3, Permissive: true, ForceUpdate: true},
+	}
+	me.AssertCalled(t, "ApplyWithOptions", "/tmp", map[string]string{}, opts)
+}
+
+func TestRunPassesVariablesFileToEngine(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	f := filepath.Join(dir, "dev.vars")
+	err = ioutil.WriteFile(f, []byte("version = \"1.7.2\"\nreplicas = 3\n"), 0644)
+	assert.NoError(t, err)
+
+	rf, me, _, _, _ := setupRun(t)
+	rf.SetArgs([]string{"/tmp"})
+	rf.Flags().Set("vars-file", f)
+	rf.Flags().Set("var", "version=1.8.0")
+
+	err = rf.Execute()
+	assert.NoError(t, err)
+
+	me.AssertCalled(t, "ApplyWithOptions", "/tmp", map[string]string{"version": "1.8.0", "replicas": "3"}, mock.Anything)
+}
+
+func TestRunWithInvalidVariableReturnsError(t *testing.T) {
+	rf, me, _, _, _ := setupRun(t)
+	rf.SetArgs([]string{"/tmp"})
+	rf.Flags().Set("var", "version")
+
+	err := rf.Execute()
+	assert.Error(t, err)
+
+	me.AssertNotCalled(t, "ApplyWithOptions", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestRunPreflightsSystem(t *testing.T) {
 	rf, _, _, _, mb := setupRun(t)
 	rf.SetArgs([]string{"/tmp"})
@@ -75,7 +153,7 @@ func TestRunSetsDestinationFromArgsWhenPresent(t *testing.T) {
 	err := rf.Execute()
 	assert.NoError(t, err)
 
-	me.AssertCalled(t, "Apply", "/tmp")
+	me.AssertCalled(t, "ApplyWithOptions", "/tmp", map[string]string{}, mock.Anything)
 }
 
 func TestRunSetsDestinationToDownloadedBlueprintFromArgsWhenRemote(t *testing.T) {
@@ -85,7 +163,7 @@ func TestRunSetsDestinationToDownloadedBlueprintFromArgsWhenRemote(t *testing.T)
 	err := rf.Execute()
 	assert.NoError(t, err)
 
-	me.AssertCalled(t, "Apply", filepath.Join(utils.ShipyardHome(), "blueprints/github.com/shipyard-run/blueprints/vault-k8s"))
+	me.AssertCalled(t, "ApplyWithOptions", filepath.Join(utils.ShipyardHome(), "blueprints/github.com/shipyard-run/blueprints/vault-k8s"), map[string]string{}, mock.Anything)
 }
 
 func TestRunFetchesBlueprint(t *testing.T) {
@@ -111,6 +189,31 @@ func TestRunFetchesBlueprintErrorReturnsError(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestRunWithConfigErrorWritesDiagnostics(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	f := filepath.Join(dir, "config.hcl")
+	ioutil.WriteFile(f, []byte("container \"web\" {\n  imag = \"nginx\"\n}\n"), os.ModePerm)
+	perr := config.ParseHCLFile(f, config.New(), nil)
+
+	rf, me, _, _, _ := setupRun(t)
+	rf.SetArgs([]string{dir})
+
+	stderr := bytes.NewBufferString("")
+	rf.SetErr(stderr)
+
+	removeOn(&me.Mock, "ApplyWithOptions")
+	me.On("ApplyWithOptions", mock.Anything, mock.Anything, mock.Anything).Return(nil, perr)
+
+	err = rf.Execute()
+	assert.Error(t, err)
+
+	assert.Contains(t, stderr.String(), f+" line 2")
+	assert.Contains(t, stderr.String(), `imag = "nginx"`)
+}
+
 func TestRunOpensBrowserWindow(t *testing.T) {
 	rf, _, _, mh, mb := setupRun(t)
 	rf.SetArgs([]string{"/tmp"})
@@ -126,7 +229,7 @@ func TestRunOpensBrowserWindowForResources(t *testing.T) {
 	rf, me, _, mh, mb := setupRun(t)
 	rf.SetArgs([]string{"/tmp"})
 
-	removeOn(&me.Mock, "Apply")
+	removeOn(&me.Mock, "ApplyWithOptions")
 
 	d := config.NewDocs("test")
 	d.OpenInBrowser = true
@@ -146,7 +249,7 @@ func TestRunOpensBrowserWindowForResources(t *testing.T) {
 	c2 := config.NewContainer("test2")
 	c2.Ports = []config.Port{config.Port{OpenInBrowser: ""}}
 
-	me.On("Apply", mock.Anything).Return(
+	me.On("ApplyWithOptions", mock.Anything, mock.Anything, mock.Anything).Return(
 		[]config.Resource{d, i, c, d2, i2, c2},
 		nil,
 	)
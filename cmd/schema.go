@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+func newSchemaCmd() *cobra.Command {
+	var output string
+
+	schemaCmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Print a JSON Schema for the blueprint resources",
+		Long: `Print a JSON Schema describing all the resource types which can be defined in
+a blueprint, the schema can be used by editors to provide completion and validation
+for blueprints written using the JSON or YAML syntax`,
+		Example: `
+  # Print the schema
+  shipyard schema
+
+  # Write the schema to a file
+  shipyard schema --output ./shipyard.schema.json
+	`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s, err := config.Schema()
+			if err != nil {
+				return fmt.Errorf("Unable to generate schema: %s", err)
+			}
+
+			if output != "" {
+				return ioutil.WriteFile(output, s, 0644)
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), string(s))
+
+			return nil
+		},
+	}
+
+	schemaCmd.Flags().StringVarP(&output, "output", "o", "", "Write the schema to the given file rather than stdout")
+
+	return schemaCmd
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"bytes"
+	gojson "encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaWritesSchemaToStdout(t *testing.T) {
+	out := bytes.NewBufferString("")
+
+	c := newSchemaCmd()
+	c.SetOut(out)
+	c.SetArgs([]string{})
+
+	err := c.Execute()
+	assert.NoError(t, err)
+
+	s := map[string]interface{}{}
+	err = gojson.Unmarshal(out.Bytes(), &s)
+	assert.NoError(t, err)
+	assert.Contains(t, s["properties"], "container")
+}
+
+func TestSchemaWritesSchemaToFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	f := filepath.Join(dir, "schema.json")
+	out := bytes.NewBufferString("")
+
+	c := newSchemaCmd()
+	c.SetOut(out)
+	c.SetArgs([]string{"--output", f})
+
+	err = c.Execute()
+	assert.NoError(t, err)
+
+	assert.Empty(t, out.String())
+	assert.FileExists(t, f)
+}
@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/hokaccha/go-prettyjson"
 	"github.com/shipyard-run/shipyard/pkg/config"
@@ -40,17 +39,18 @@ var statusCmd = &cobra.Command{
 		err := c.FromJSON(utils.StatePath())
 		if err != nil {
 			fmt.Println("Unable to load state", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		if json {
 			s, err := prettyjson.Marshal(c)
 			if err != nil {
 				fmt.Println("Unable to load state", err)
-				os.Exit(1)
+				exit(1)
 			}
 
-			fmt.Println(string(s))
+			// the state keeps the real values of secrets
+			fmt.Println(config.Redact(string(s)))
 		} else {
 
 			createdCount := 0
@@ -70,7 +70,12 @@ var statusCmd = &cobra.Command{
 				default:
 					pendingCount++
 				}
-				fmt.Printf(" [ %s ] %s.%s\n", status, r.Info().Type, r.Info().Name)
+				module := ""
+				if r.Info().Module != "" {
+					module = fmt.Sprintf(" (module.%s)", r.Info().Module)
+				}
+
+				fmt.Printf(" [ %s ] %s.%s%s\n", status, r.Info().Type, r.Info().Name, module)
 			}
 
 			fmt.Println()
@@ -21,7 +21,7 @@ var uninstallCmd = &cobra.Command{
 		err := os.RemoveAll(utils.ShipyardHome())
 		if err != nil {
 			fmt.Println("Error: Unable to remove Shipyard configuration", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		// remove the binary
@@ -29,13 +29,13 @@ var uninstallCmd = &cobra.Command{
 		cf, err := filepath.Abs(ep)
 		if err != nil {
 			fmt.Println("Error: Unable to remove Shipyard application", err)
-			os.Exit(1)
+			exit(1)
 		}
 		fmt.Println("Removing Shipyard application from", cf)
 		err = os.Remove(cf)
 		if err != nil {
 			fmt.Println("Error: Unable to remove Shipyard application", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		fmt.Println("")
@@ -1,7 +1,107 @@
 package cmd
 
-import "github.com/hashicorp/go-hclog"
+import (
+	"io"
+	"os"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/mattn/go-isatty"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"golang.org/x/xerrors"
+)
 
 func createLogger() hclog.Logger {
-	return hclog.New(&hclog.LoggerOptions{Level: hclog.Debug, Color: hclog.AutoColor})
+	// hclog can only detect a terminal when writing directly to a file,
+	// check stderr before wrapping it with the redacting writer
+	color := hclog.ColorOff
+	if isatty.IsTerminal(os.Stderr.Fd()) || isatty.IsCygwinTerminal(os.Stderr.Fd()) {
+		color = hclog.ForceColor
+	}
+
+	return hclog.New(&hclog.LoggerOptions{
+		Level:  hclog.Debug,
+		Color:  color,
+		Output: config.NewRedactWriter(os.Stderr),
+	})
+}
+
+// parseConfig parses the blueprint file or folder at dst with the values for
+// variables set with --var and --vars-file and links the references between
+// resources
+func parseConfig(dst string, variables []string, varsFile string, opts config.ParseOptions) (*config.Config, error) {
+	vars, err := parseVariables(variables, varsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	c := config.New()
+	if utils.IsHCLFile(dst) {
+		err = config.ParseHCLFileWithOptions(dst, c, vars, opts)
+	} else {
+		err = config.ParseFolderWithOptions(dst, c, vars, opts)
+	}
+
+	if err != nil {
+		return nil, xerrors.Errorf("Unable to parse configuration: %w", err)
+	}
+
+	err = config.ParseReferences(c)
+	if err != nil {
+		return nil, xerrors.Errorf("Unable to parse configuration: %w", err)
+	}
+
+	return c, nil
+}
+
+// loadState loads the state for the current stack, an empty config is
+// returned when nothing has been created yet
+func loadState() (*config.Config, error) {
+	sc := config.New()
+
+	err := sc.FromJSON(utils.StatePath())
+	if err != nil && err != config.StateNotFoundError {
+		return nil, xerrors.Errorf("Unable to load state: %w", err)
+	}
+
+	return sc, nil
+}
+
+// writeParseError writes the diagnostics for a config error to w along with
+// the source which caused the error, returns false if err is not a config error
+func writeParseError(w io.Writer, err error) bool {
+	pe := config.ParseError{}
+	if !xerrors.As(err, &pe) {
+		return false
+	}
+
+	color := false
+	if f, ok := w.(*os.File); ok {
+		color = isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+	}
+
+	pe.WriteDiagnostics(w, 0, color)
+
+	return true
+}
+
+// writeParseWarnings writes any warnings found when parsing the config to w
+// along with the source which caused the warning
+func writeParseWarnings(w io.Writer) {
+	writeWarnings(w, config.ParseWarnings())
+}
+
+// writeWarnings writes the warnings to w along with the source which
+// caused the warning
+func writeWarnings(w io.Writer, pe config.ParseError) {
+	if len(pe.Diagnostics) == 0 {
+		return
+	}
+
+	color := false
+	if f, ok := w.(*os.File); ok {
+		color = isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+	}
+
+	pe.WriteDiagnostics(w, 0, color)
 }
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+func newValidateCmd() *cobra.Command {
+	var variables []string
+	var varsFile string
+	var maxDepth int
+	var permissive bool
+
+	validateCmd := &cobra.Command{
+		Use:   "validate [file] | [directory]",
+		Short: "Validate the configuration for a blueprint",
+		Long: `Validate the configuration for a blueprint without creating any resources,
+checks that the config can be parsed and that all references between resources are valid.
+Problems which are likely to be mistakes, such as unused networks and variables, are
+reported as warnings`,
+		Example: `
+  # Validate the blueprint in the current folder
+  shipyard validate
+
+  # Validate a blueprint setting the value of a variable
+  shipyard validate --var consul_version=1.8.0 ./my-stack
+	`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dst := "./"
+			if len(args) == 1 {
+				dst = args[0]
+			}
+
+			config.ResetParseWarnings()
+
+			opts := config.ParseOptions{MaxFolderDepth: maxDepth, Permissive: permissive}
+			c, err := parseConfig(dst, variables, varsFile, opts)
+
+			writeParseWarnings(cmd.ErrOrStderr())
+
+			if err != nil {
+				if writeParseError(cmd.ErrOrStderr(), err) {
+					return fmt.Errorf("The configuration contains errors")
+				}
+
+				return err
+			}
+
+			// lint warnings do not cause validation to fail
+			files := []string{dst}
+			if !utils.IsHCLFile(dst) {
+				files, err = config.ConfigFiles(dst, maxDepth)
+				if err != nil {
+					return err
+				}
+			}
+
+			warnings, err := config.Lint(c, files)
+			if err != nil {
+				return fmt.Errorf("Unable to lint configuration: %s", err)
+			}
+
+			writeWarnings(cmd.ErrOrStderr(), warnings)
+
+			errs := c.Validate()
+			for _, e := range errs {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Error: %s\n", e)
+			}
+
+			if len(errs) > 0 {
+				return fmt.Errorf("The configuration contains %d error(s)", len(errs))
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), "The configuration is valid")
+
+			return nil
+		},
+	}
+
+	validateCmd.Flags().StringArrayVarP(&variables, "var", "", nil, "Set a value for a variable defined in the blueprint e.g. --var name=value, can be specified multiple times")
+	validateCmd.Flags().StringVarP(&varsFile, "vars-file", "", "", "Load values for variables defined in the blueprint from a file, values set with --var take precedence")
+	validateCmd.Flags().IntVarP(&maxDepth, "max-depth", "", 0, "The number of levels of sub folders which are searched for config files")
+	validateCmd.Flags().BoolVarP(&permissive, "permissive", "", false, "When set to true unknown attributes and blocks in the config are reported as warnings rather than errors")
+
+	return validateCmd
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupValidate(t *testing.T, contents string) (*cobra.Command, *bytes.Buffer, *bytes.Buffer, func()) {
+	dir, err := ioutil.TempDir("", "")
+	assert.NoError(t, err)
+
+	ioutil.WriteFile(filepath.Join(dir, "config.hcl"), []byte(contents), os.ModePerm)
+
+	out := bytes.NewBufferString("")
+	errOut := bytes.NewBufferString("")
+
+	c := newValidateCmd()
+	c.SetOut(out)
+	c.SetErr(errOut)
+	c.SetArgs([]string{dir})
+
+	return c, out, errOut, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+func TestValidateWithValidConfigSucceeds(t *testing.T) {
+	c, out, _, cleanup := setupValidate(t, validateConfig)
+	defer cleanup()
+
+	err := c.Execute()
+	assert.NoError(t, err)
+
+	assert.Contains(t, out.String(), "The configuration is valid")
+}
+
+func TestValidateWithUnknownAttributeReturnsError(t *testing.T) {
+	c, _, errOut, cleanup := setupValidate(t, validateConfig+validateUnknownAttribute)
+	defer cleanup()
+
+	err := c.Execute()
+	assert.Error(t, err)
+
+	assert.Contains(t, errOut.String(), `Did you mean "entrypoint"?`)
+}
+
+func TestValidatePermissiveWithUnknownAttributeWritesWarning(t *testing.T) {
+	c, out, errOut, cleanup := setupValidate(t, validateConfig+validateUnknownAttribute)
+	defer cleanup()
+
+	c.Flags().Set("permissive", "true")
+
+	err := c.Execute()
+	assert.NoError(t, err)
+
+	assert.Contains(t, errOut.String(), "Warning: Unsupported argument")
+	assert.Contains(t, out.String(), "The configuration is valid")
+}
+
+const validateUnknownAttribute = `
+container "vault" {
+	image {
+		name = "vault"
+	}
+
+	entrypont = ["vault"]
+}
+`
+
+func TestValidateWithInvalidReferencesReturnsError(t *testing.T) {
+	c, _, _, cleanup := setupValidate(t, validateConfig+`
+container "vault" {
+	image {
+		name = "vault"
+	}
+
+	depends_on = ["container.missing"]
+}
+`)
+	defer cleanup()
+
+	err := c.Execute()
+	assert.Error(t, err)
+
+	// depends_on is checked when the config is parsed
+	assert.Contains(t, err.Error(), "container.vault depends on container.missing which does not exist")
+}
+
+func TestValidateWithParseErrorWritesDiagnostics(t *testing.T) {
+	c, _, errOut, cleanup := setupValidate(t, `
+network "local" {
+	subnt = "10.5.0.0/16"
+}
+`)
+	defer cleanup()
+
+	err := c.Execute()
+	assert.Error(t, err)
+
+	assert.Contains(t, errOut.String(), "line 3")
+}
+
+func TestValidateSetsVariables(t *testing.T) {
+	c, _, errOut, cleanup := setupValidate(t, `
+variable "subnet" {
+	default = "10.5.0.0/16"
+}
+
+network "local" {
+	subnet = var.subnet
+}
+`)
+	defer cleanup()
+
+	c.Flags().Set("var", "subnet=invalid")
+
+	err := c.Execute()
+	assert.Error(t, err)
+
+	assert.Contains(t, errOut.String(), "network.local: invalid subnet invalid")
+}
+
+const validateConfig = `
+network "local" {
+	subnet = "10.5.0.0/16"
+}
+
+container "consul" {
+	image {
+		name = "consul:1.7.2"
+	}
+
+	network {
+		name = "network.local"
+	}
+}
+`
+
+func TestValidateWritesLintWarnings(t *testing.T) {
+	c, out, errOut, cleanup := setupValidate(t, validateConfig+`
+network "unused" {
+	subnet = "10.6.0.0/16"
+}
+`)
+	defer cleanup()
+
+	err := c.Execute()
+	assert.NoError(t, err)
+
+	assert.Contains(t, errOut.String(), "Warning: Unused network")
+	assert.Contains(t, out.String(), "The configuration is valid")
+}
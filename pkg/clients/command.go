@@ -1,7 +1,9 @@
 package clients
 
 import (
+	"os"
 	"os/exec"
+	"path/filepath"
 	"time"
 
 	"github.com/hashicorp/go-hclog"
@@ -9,6 +11,15 @@ import (
 
 type Command interface {
 	Execute(string, ...string) error
+	// Start a process in the background and return the process id, the
+	// output of the process is written to the log file
+	Start(command string, args []string, env []string, workingDir string, logFile string) (int, error)
+	// Kill the process with the given id
+	Kill(pid int) error
+	// Running returns true when the process with the given id is running
+	Running(pid int) bool
+	// StartTime returns the time the process with the given id was started
+	StartTime(pid int) (string, error)
 }
 
 // Command executes local commands
@@ -57,3 +68,53 @@ func (c *CommandImpl) Execute(command string, args ...string) error {
 
 	return nil
 }
+
+// Start the given command in the background, the process is not stopped
+// when shipyard exits
+func (c *CommandImpl) Start(command string, args []string, env []string, workingDir string, logFile string) (int, error) {
+	err := os.MkdirAll(filepath.Dir(logFile), os.ModePerm)
+	if err != nil {
+		return 0, err
+	}
+
+	f, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	cmd := exec.Command(command, args...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Dir = workingDir
+	cmd.Stdout = f
+	cmd.Stderr = f
+
+	setProcessGroup(cmd)
+
+	err = cmd.Start()
+	if err != nil {
+		return 0, err
+	}
+
+	// wait in the background so that the process does not become a zombie
+	// if it exits before shipyard
+	go cmd.Wait()
+
+	return cmd.Process.Pid, nil
+}
+
+// Kill the process with the given id and any processes it started
+func (c *CommandImpl) Kill(pid int) error {
+	return killProcessGroup(pid)
+}
+
+// Running returns true when the process with the given id is running
+func (c *CommandImpl) Running(pid int) bool {
+	return processRunning(pid)
+}
+
+// StartTime returns the time the process with the given id was started,
+// process ids are reused so this identifies the process along with the id
+func (c *CommandImpl) StartTime(pid int) (string, error) {
+	return processStartTime(pid)
+}
@@ -1,10 +1,16 @@
 package clients
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func setupExecute(t *testing.T) Command {
@@ -17,3 +23,33 @@ func TestExecuteWithBasicParams(t *testing.T) {
 
 	e.Execute("ls")
 }
+
+func TestStartAndKillBackgroundProcess(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip()
+	}
+
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	e := setupExecute(t)
+
+	pid, err := e.Start("sh", []string{"-c", "echo $MESSAGE && sleep 30"}, []string{"MESSAGE=hello"}, dir, filepath.Join(dir, "logs", "out.log"))
+	require.NoError(t, err)
+	assert.True(t, e.Running(pid))
+
+	st, err := e.StartTime(pid)
+	require.NoError(t, err)
+	assert.NotEmpty(t, st)
+
+	assert.Eventually(t, func() bool {
+		d, _ := ioutil.ReadFile(filepath.Join(dir, "logs", "out.log"))
+		return string(d) == "hello\n"
+	}, 5*time.Second, 50*time.Millisecond)
+
+	err = e.Kill(pid)
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool { return !e.Running(pid) }, 5*time.Second, 50*time.Millisecond)
+}
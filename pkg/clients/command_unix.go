@@ -0,0 +1,41 @@
+//go:build !windows
+// +build !windows
+
+package clients
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// setProcessGroup starts the process in a new process group so that the
+// process and any children can be stopped together
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+func killProcessGroup(pid int) error {
+	// a negative pid sends the signal to the process group
+	err := syscall.Kill(-pid, syscall.SIGTERM)
+	if err == syscall.ESRCH {
+		return nil
+	}
+
+	return err
+}
+
+func processRunning(pid int) bool {
+	// signal 0 checks the process exists without sending a signal
+	return syscall.Kill(pid, syscall.Signal(0)) == nil
+}
+
+func processStartTime(pid int) (string, error) {
+	out, err := exec.Command("ps", "-o", "lstart=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,40 @@
+//go:build windows
+// +build windows
+
+package clients
+
+import (
+	"os"
+	"os/exec"
+)
+
+// setProcessGroup is not required on Windows
+func setProcessGroup(cmd *exec.Cmd) {}
+
+func killProcessGroup(pid int) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		// process is not running
+		return nil
+	}
+
+	return p.Kill()
+}
+
+func processRunning(pid int) bool {
+	// FindProcess opens a handle to the process on Windows and fails when
+	// the process does not exist
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+
+	p.Release()
+	return true
+}
+
+// processStartTime is not supported on Windows, an empty start time
+// matches any process
+func processStartTime(pid int) (string, error) {
+	return "", nil
+}
@@ -1,6 +1,7 @@
 package clients
 
 import (
+	"context"
 	"io"
 
 	"github.com/shipyard-run/shipyard/pkg/config"
@@ -33,7 +34,16 @@ type ContainerTasks interface {
 	// authenticate with the registry before pulling the image.
 	// If the force parameter is set then PullImage will pull regardless of the image already
 	// being cached locally.
-	PullImage(image config.Image, force bool) error
+	// The context is the parent of the span for the pull.
+	PullImage(ctx context.Context, image config.Image, force bool) error
+	// BuildContainer builds a Docker image using the Dockerfile and context folder
+	// defined in the config, the image is tagged with the tag from the config.
+	// If successful BuildContainer returns the name of the image.
+	// The context is the parent of the span for the build.
+	BuildContainer(ctx context.Context, config *config.ContainerBuild) (string, error)
+	// PushImage tags a local image with the address of the registry and
+	// pushes it to the registry, the name of the pushed image is returned
+	PushImage(image, registry string) (string, error)
 	// FindContainerIDs returns the Container IDs for the given identifier
 	FindContainerIDs(name string, typeName config.ResourceType) ([]string, error)
 	// ContainerLogs attaches to the container and streams the logs to the returned
@@ -42,6 +52,9 @@ type ContainerTasks interface {
 	ContainerLogs(id string, stdOut, stdErr bool) (io.ReadCloser, error)
 	// CopyFromContainer allows the copying of a file from a container
 	CopyFromContainer(id, src, dst string) error
+	// CopyToContainer copies a local file or folder into the folder dst in a
+	// running container
+	CopyToContainer(id, src, dst string) error
 	// CopyLocaDockerImageToVolume copies the docker images to the docker volume as a
 	// compressed archive.
 	// the path in the docker volume where the archive is created is returned
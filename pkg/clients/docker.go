@@ -3,14 +3,19 @@ package clients
 import (
 	"context"
 	"io"
+	"net/http"
+	"os"
 	"time"
 
+	"github.com/docker/cli/cli/connhelper"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/network"
 	volumetypes "github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"golang.org/x/xerrors"
 )
 
 // Docker defines an interface for a Docker client
@@ -47,13 +52,21 @@ type Docker interface {
 	VolumeRemove(ctx context.Context, volumeID string, force bool) error
 
 	ImagePull(ctx context.Context, refStr string, options types.ImagePullOptions) (io.ReadCloser, error)
+	ImageBuild(ctx context.Context, buildContext io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error)
+	ImageTag(ctx context.Context, source, target string) error
+	ImagePush(ctx context.Context, image string, options types.ImagePushOptions) (io.ReadCloser, error)
 	ImageList(ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, error)
 	ImageSave(ctx context.Context, imageIDs []string) (io.ReadCloser, error)
 	ImageRemove(ctx context.Context, imageID string, options types.ImageRemoveOptions) ([]types.ImageDeleteResponseItem, error)
 }
 
-// NewDocker creates a new Docker client
+// NewDocker creates a new Docker client, when Podman is the container engine
+// and DOCKER_HOST is not set the client uses the Docker compatible API of Podman
 func NewDocker() (Docker, error) {
+	if os.Getenv("DOCKER_HOST") == "" && utils.GetContainerEngine() == utils.EnginePodman {
+		return NewDockerForHost(utils.GetDockerHost())
+	}
+
 	cli, err := client.NewEnvClient()
 	if err != nil {
 		return nil, err
@@ -61,3 +74,30 @@ func NewDocker() (Docker, error) {
 
 	return cli, nil
 }
+
+// NewDockerForHost creates a new Docker client for the engine at host,
+// ssh:// hosts are reached by running docker system dial-stdio over ssh
+// which requires Docker 18.09 or later on the remote machine
+func NewDockerForHost(host string) (Docker, error) {
+	helper, err := connhelper.GetConnectionHelper(host)
+	if err != nil {
+		return nil, xerrors.Errorf("Invalid Docker host %s: %w", host, err)
+	}
+
+	opts := []func(*client.Client) error{client.FromEnv}
+	if helper != nil {
+		opts = append(opts,
+			client.WithHTTPClient(&http.Client{Transport: &http.Transport{DialContext: helper.Dialer}}),
+			client.WithHost(helper.Host),
+		)
+	} else {
+		opts = append(opts, client.WithHost(host))
+	}
+
+	cli, err := client.NewClientWithOpts(opts...)
+	if err != nil {
+		return nil, xerrors.Errorf("Unable to create Docker client for %s: %w", host, err)
+	}
+
+	return cli, nil
+}
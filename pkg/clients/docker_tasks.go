@@ -4,14 +4,18 @@ import (
 	"archive/tar"
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	gosignal "os/signal"
 	"path"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/docker/docker/api/types"
@@ -25,21 +29,30 @@ import (
 	"github.com/hashicorp/go-hclog"
 	"github.com/shipyard-run/shipyard/pkg/clients/streams"
 	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/tracing"
 	"github.com/shipyard-run/shipyard/pkg/utils"
+	"go.opentelemetry.io/otel/api/kv"
+	"go.opentelemetry.io/otel/api/trace"
 	"golang.org/x/xerrors"
 )
 
 // DockerTasks is a concrete implementation of ContainerTasks which uses the Docker SDK
 type DockerTasks struct {
-	c     Docker
-	il    ImageLog
-	force bool
-	l     hclog.Logger
+	c      Docker
+	il     ImageLog
+	force  bool
+	l      hclog.Logger
+	puller *imagePuller
+
+	// built contains the images built by BuildContainer, these only
+	// exist in the local cache and can not be pulled
+	builtMutex sync.Mutex
+	built      map[string]bool
 }
 
 // NewDockerTasks creates a DockerTasks with the given Docker client
 func NewDockerTasks(c Docker, il ImageLog, l hclog.Logger) *DockerTasks {
-	return &DockerTasks{c: c, il: il, l: l}
+	return &DockerTasks{c: c, il: il, l: l, puller: newImagePuller(), built: map[string]bool{}}
 }
 
 // SetForcePull sets a global override for the DockerTasks, when set to true
@@ -99,6 +112,12 @@ func (d *DockerTasks) CreateContainer(c *config.Container) (string, error) {
 			t = mount.TypeTmpfs
 		}
 
+		// volume resources are referenced as volume.[name]
+		source := vc.Source
+		if t == mount.TypeVolume && strings.HasPrefix(source, "volume.") {
+			source = utils.FQDNVolumeName(strings.TrimPrefix(source, "volume."))
+		}
+
 		// if we have a bind type mount then ensure that the local folder exists or
 		// an error will be raised when creating
 		if t == mount.TypeBind {
@@ -117,7 +136,7 @@ func (d *DockerTasks) CreateContainer(c *config.Container) (string, error) {
 		// create the mount
 		mounts = append(mounts, mount.Mount{
 			Type:   t,
-			Source: vc.Source,
+			Source: source,
 			Target: vc.Destination,
 		})
 	}
@@ -132,6 +151,20 @@ func (d *DockerTasks) CreateContainer(c *config.Container) (string, error) {
 	// is this a privlidged container
 	hc.Privileged = c.Privileged
 
+	// custom DNS servers
+	hc.DNS = c.DNS
+
+	// resource constraints
+	if c.Resources != nil {
+		hc.Resources = createResources(c.Resources)
+	}
+
+	// use the host network, the hostname can not be set in host mode
+	if c.HostNetwork {
+		hc.NetworkMode = container.NetworkMode("host")
+		dc.Hostname = ""
+	}
+
 	// are we attaching the container to a sidecar network?
 	for _, n := range c.Networks {
 		net, err := c.FindDependentResource(n.Name)
@@ -173,7 +206,7 @@ func (d *DockerTasks) CreateContainer(c *config.Container) (string, error) {
 	// first remove the container from the bridge network if we are adding custom networks
 	// all containers should have custom networks
 	// only add networks if we are not adding the container network
-	if len(c.Networks) > 0 && !hc.NetworkMode.IsContainer() {
+	if len(c.Networks) > 0 && !hc.NetworkMode.IsContainer() && !hc.NetworkMode.IsHost() {
 		err := d.c.NetworkDisconnect(context.Background(), "bridge", cont.ID, true)
 		if err != nil {
 			return "", xerrors.Errorf("Unable to remove container from the default bridge network: %w", err)
@@ -225,24 +258,46 @@ func (d *DockerTasks) CreateContainer(c *config.Container) (string, error) {
 	return cont.ID, nil
 }
 
-// PullImage pulls a Docker image from a remote repo
-func (d *DockerTasks) PullImage(image config.Image, force bool) error {
+// PullImage pulls a Docker image from a remote repo, each unique image is
+// only pulled once even when requested by multiple resources in parallel
+func (d *DockerTasks) PullImage(ctx context.Context, image config.Image, force bool) error {
+	return d.puller.Pull(pullKey(image, force || d.force), func() error {
+		return d.pullImage(ctx, image, force)
+	})
+}
+
+// pullKey returns the key used to share a pull between concurrent requests,
+// requests only share a pull when they use the same credentials and force
+// flag as the result of the pull can depend on them
+func pullKey(image config.Image, force bool) string {
+	auth := sha256.Sum256([]byte(image.Username + ":" + image.Password))
+
+	return fmt.Sprintf("%s/%x/%t", makeImageCanonical(image.Name), auth, force)
+}
+
+func (d *DockerTasks) pullImage(ctx context.Context, image config.Image, force bool) error {
 	in := makeImageCanonical(image.Name)
 
+	ctx, span := tracing.Tracer().Start(ctx, "pull_image", trace.WithAttributes(kv.String("image", in)))
+	defer span.End()
+
 	args := filters.NewArgs()
 	args.Add("reference", image.Name)
 
 	// only pull if image is not in current registry so check to see if the image is present
-	// if force then skil this check
-	if !force && !d.force {
-		sum, err := d.c.ImageList(context.Background(), types.ImageListOptions{Filters: args})
+	// if force then skip this check, unless the image was built locally
+	if !force && !d.force || d.isBuilt(in) {
+		sum, err := d.c.ImageList(ctx, types.ImageListOptions{Filters: args})
 		if err != nil {
-			return xerrors.Errorf("unable to list images in local Docker cache: %w", err)
+			err = xerrors.Errorf("unable to list images in local Docker cache: %w", err)
+			tracing.RecordError(ctx, span, err)
+			return err
 		}
 
 		// if we have images do not pull
 		if len(sum) > 0 {
 			d.l.Debug("Image exists in local cache", "image", image.Name)
+			span.SetAttributes(kv.Bool("cached", true))
 
 			return nil
 		}
@@ -258,9 +313,19 @@ func (d *DockerTasks) PullImage(image config.Image, force bool) error {
 
 	d.l.Debug("Pulling image", "image", image.Name)
 
-	out, err := d.c.ImagePull(context.Background(), in, ipo)
+	out, err := d.c.ImagePull(ctx, in, ipo)
 	if err != nil {
-		return xerrors.Errorf("Error pulling image: %w", err)
+		err = xerrors.Errorf("Error pulling image: %w", err)
+		tracing.RecordError(ctx, span, err)
+		return err
+	}
+	defer out.Close()
+
+	// the pull is not complete until the stream has been read
+	err = readPullProgress(in, out, d.l)
+	if err != nil {
+		tracing.RecordError(ctx, span, err)
+		return err
 	}
 
 	// update the image log
@@ -269,13 +334,130 @@ func (d *DockerTasks) PullImage(image config.Image, force bool) error {
 		d.l.Error("Unable to add image name to cache", "error", err)
 	}
 
-	// write the output to /dev/null
-	// TODO this stuff needs to be logged correctly
-	io.Copy(ioutil.Discard, out)
-
 	return nil
 }
 
+// BuildContainer builds a Docker image from the Dockerfile and context
+// folder defined in the config, images are always rebuilt so that changes
+// to the context are picked up, Docker's layer cache means that unchanged
+// images are built quickly
+func (d *DockerTasks) BuildContainer(ctx context.Context, c *config.ContainerBuild) (string, error) {
+	d.l.Info("Building image", "ref", c.Name, "context", c.Context, "dockerfile", c.Dockerfile, "tag", c.Tag)
+
+	ctx, span := tracing.Tracer().Start(ctx, "build_image", trace.WithAttributes(kv.String("image", c.Tag)))
+	defer span.End()
+
+	dockerfile := c.Dockerfile
+	if dockerfile == "" {
+		dockerfile = "Dockerfile"
+	}
+
+	buildContext, err := tarBuildContext(c.Context, dockerfile)
+	if err != nil {
+		err = xerrors.Errorf("Unable to create build context from %s: %w", c.Context, err)
+		tracing.RecordError(ctx, span, err)
+		return "", err
+	}
+	defer buildContext.Close()
+
+	resp, err := d.c.ImageBuild(ctx, buildContext, types.ImageBuildOptions{
+		Dockerfile: dockerfile,
+		Tags:       []string{c.Tag},
+		Remove:     true,
+	})
+	if err != nil {
+		err = xerrors.Errorf("Error building image: %w", err)
+		tracing.RecordError(ctx, span, err)
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	// the build is not complete until the stream has been read
+	err = readBuildOutput(c.Tag, resp.Body, d.l)
+	if err != nil {
+		tracing.RecordError(ctx, span, err)
+		return "", err
+	}
+
+	// add the image to the log so that it is removed by purge
+	err = d.il.Log(c.Tag, ImageTypeDocker)
+	if err != nil {
+		d.l.Error("Unable to add image name to cache", "error", err)
+	}
+
+	// built images only exist locally and must not be pulled
+	d.builtMutex.Lock()
+	d.built[makeImageCanonical(c.Tag)] = true
+	d.builtMutex.Unlock()
+
+	return c.Tag, nil
+}
+
+func (d *DockerTasks) isBuilt(image string) bool {
+	d.builtMutex.Lock()
+	defer d.builtMutex.Unlock()
+
+	return d.built[image]
+}
+
+// PushImage tags a local image with the address of the registry and pushes
+// it to the registry e.g. consul:1.8.1 is pushed as localhost:5000/consul:1.8.1,
+// the host of images from other registries is replaced
+func (d *DockerTasks) PushImage(image, registry string) (string, error) {
+	target := registryImageName(image, registry)
+	d.l.Debug("Pushing image", "image", image, "target", target)
+
+	err := d.c.ImageTag(context.Background(), image, target)
+	if err != nil {
+		return "", xerrors.Errorf("Unable to tag image %s: %w", image, err)
+	}
+
+	// the registry does not require authentication but Docker requires the
+	// auth header to be set
+	out, err := d.c.ImagePush(context.Background(), target, types.ImagePushOptions{RegistryAuth: "e30="})
+	if err != nil {
+		return "", xerrors.Errorf("Unable to push image %s: %w", target, err)
+	}
+	defer out.Close()
+
+	// the push is not complete until the stream has been read
+	dec := json.NewDecoder(out)
+	for {
+		m := pullMessage{}
+		err := dec.Decode(&m)
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			io.Copy(ioutil.Discard, out)
+			break
+		}
+
+		if m.Error != "" {
+			return "", fmt.Errorf("Error pushing image %s: %s", target, m.Error)
+		}
+	}
+
+	return target, nil
+}
+
+// registryImageName returns the name of the image in the registry, the
+// registry host is removed from images which include it and the latest
+// tag is added to images without a tag
+func registryImageName(image, registry string) string {
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		image = parts[1]
+	}
+
+	if !strings.Contains(image, "@") && !strings.Contains(path.Base(image), ":") {
+		image += ":latest"
+	}
+
+	return fmt.Sprintf("%s/%s", registry, image)
+}
+
 // FindContainerIDs returns the Container IDs for the given identifier
 func (d *DockerTasks) FindContainerIDs(containerName string, typeName config.ResourceType) ([]string, error) {
 	fullName := utils.FQDN(containerName, string(typeName))
@@ -394,6 +576,25 @@ func (d *DockerTasks) CopyFromContainer(id, src, dst string) error {
 	return nil
 }
 
+// CopyToContainer copies a file or folder from the local machine into the
+// folder dst in a running container, the folder is created if it does not
+// exist
+func (d *DockerTasks) CopyToContainer(id, src, dst string) error {
+	d.l.Debug("Copying file to", "id", id, "src", src, "dst", dst)
+
+	// entries in the archive contain the full destination path so that
+	// missing folders are created when the archive is extracted
+	tr := tarFiles(filepath.Dir(src), src, dst, nil)
+	defer tr.Close()
+
+	err := d.c.CopyToContainer(context.Background(), id, "/", tr, types.CopyToContainerOptions{})
+	if err != nil {
+		return xerrors.Errorf("Unable to copy %s to container %s: %w", src, id, err)
+	}
+
+	return nil
+}
+
 // CopyLocalDockerImageToVolume writes multiple Docker images to a Docker volume as a compressed archive
 // returns the filename of the archive and an error if one occured
 func (d *DockerTasks) CopyLocalDockerImageToVolume(images []string, volume string, force bool) ([]string, error) {
@@ -402,7 +603,7 @@ func (d *DockerTasks) CopyLocalDockerImageToVolume(images []string, volume strin
 	savedImages := []string{}
 
 	// make sure we have the alpine image needed to copy
-	err := d.PullImage(config.Image{Name: "alpine:latest"}, false)
+	err := d.PullImage(context.Background(), config.Image{Name: "alpine:latest"}, false)
 	if err != nil {
 		return nil, xerrors.Errorf("Unable pull alpine:latest for importing images: %w", err)
 	}
@@ -766,6 +967,32 @@ func createPublishedPorts(ps []config.Port) publishedPorts {
 	return pp
 }
 
+// createResources converts config.Resources to Docker resources, the CPU
+// limit is a share of a CPU where 1024 is one CPU
+func createResources(r *config.Resources) container.Resources {
+	dr := container.Resources{}
+
+	if r.CPU > 0 {
+		dr.CPUPeriod = 100000
+		dr.CPUQuota = int64(r.CPU) * 100000 / 1024
+	}
+
+	if len(r.CPUPin) > 0 {
+		cpus := []string{}
+		for _, c := range r.CPUPin {
+			cpus = append(cpus, fmt.Sprintf("%d", c))
+		}
+
+		dr.CpusetCpus = strings.Join(cpus, ",")
+	}
+
+	if r.Memory > 0 {
+		dr.Memory = int64(r.Memory) * 1024 * 1024
+	}
+
+	return dr
+}
+
 // credentials are a json string and need to be base64 encoded
 func createRegistryAuth(username, password string) string {
 	return base64.StdEncoding.EncodeToString(
@@ -274,6 +274,51 @@ func TestContainerAttachesVolumeMounts(t *testing.T) {
 	assert.Equal(t, mount.TypeBind, hc.Mounts[0].Type)
 }
 
+func TestContainerSetsDNSServers(t *testing.T) {
+	cc, _, _, md, mic := createContainerConfig()
+	cc.DNS = []string{"10.5.0.53"}
+
+	err := setupContainer(t, cc, md, mic)
+	assert.NoError(t, err)
+
+	params := getCalls(&md.Mock, "ContainerCreate")[0].Arguments
+	hc := params[2].(*container.HostConfig)
+
+	assert.Equal(t, []string{"10.5.0.53"}, hc.DNS)
+}
+
+func TestContainerUsesHostNetwork(t *testing.T) {
+	cc, _, _, md, mic := createContainerConfig()
+	cc.HostNetwork = true
+
+	err := setupContainer(t, cc, md, mic)
+	assert.NoError(t, err)
+
+	params := getCalls(&md.Mock, "ContainerCreate")[0].Arguments
+	dc := params[1].(*container.Config)
+	hc := params[2].(*container.HostConfig)
+
+	assert.True(t, hc.NetworkMode.IsHost())
+	assert.Empty(t, dc.Hostname)
+	md.AssertNotCalled(t, "NetworkConnect", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestContainerSetsResources(t *testing.T) {
+	cc, _, _, md, mic := createContainerConfig()
+	cc.Resources = &config.Resources{CPU: 2048, CPUPin: []int{1, 2}, Memory: 512}
+
+	err := setupContainer(t, cc, md, mic)
+	assert.NoError(t, err)
+
+	params := getCalls(&md.Mock, "ContainerCreate")[0].Arguments
+	hc := params[2].(*container.HostConfig)
+
+	assert.Equal(t, int64(100000), hc.CPUPeriod)
+	assert.Equal(t, int64(200000), hc.CPUQuota)
+	assert.Equal(t, "1,2", hc.CpusetCpus)
+	assert.Equal(t, int64(512*1024*1024), hc.Memory)
+}
+
 func TestContainerCreatesDirectoryForVolume(t *testing.T) {
 	tmpFolder := fmt.Sprintf("%s/%d", utils.ShipyardTemp(), time.Now().UnixNano())
 	defer os.RemoveAll(tmpFolder)
@@ -301,6 +346,21 @@ func TestContainerDoesNotCreatesDirectoryForVolumeWhenNotBind(t *testing.T) {
 	assert.NoDirExists(t, tmpFolder)
 }
 
+func TestContainerMountsVolumeResource(t *testing.T) {
+	cc, _, _, md, mic := createContainerConfig()
+	cc.Volumes[0].Source = "volume.data"
+	cc.Volumes[0].Type = "volume"
+
+	err := setupContainer(t, cc, md, mic)
+	assert.NoError(t, err)
+
+	params := getCalls(&md.Mock, "ContainerCreate")[0].Arguments
+	hc := params[2].(*container.HostConfig)
+
+	assert.Equal(t, "data.volume.shipyard.run", hc.Mounts[0].Source)
+	assert.Equal(t, mount.TypeVolume, hc.Mounts[0].Type)
+}
+
 func TestContainerPublishesPorts(t *testing.T) {
 	cc, _, _, md, mic := createContainerConfig()
 
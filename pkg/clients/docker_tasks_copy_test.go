@@ -0,0 +1,77 @@
+package clients
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupCopyToContainer(t *testing.T) (string, *mocks.MockDocker, func()) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+
+	os.MkdirAll(filepath.Join(dir, "config", "tls"), os.ModePerm)
+	ioutil.WriteFile(filepath.Join(dir, "config", "consul.hcl"), []byte("server = true"), os.ModePerm)
+	ioutil.WriteFile(filepath.Join(dir, "config", "tls", "ca.pem"), []byte("cert"), os.ModePerm)
+
+	md := &mocks.MockDocker{}
+	md.On("CopyToContainer", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	return dir, md, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+func TestCopyToContainerCopiesFolderToDestination(t *testing.T) {
+	dir, md, cleanup := setupCopyToContainer(t)
+	defer cleanup()
+
+	// the archive is streamed so it must be read before the copy returns
+	names := []string{}
+	removeOn(&md.Mock, "CopyToContainer")
+	md.On("CopyToContainer", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		tr := tar.NewReader(args.Get(3).(io.Reader))
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+
+			require.NoError(t, err)
+			names = append(names, hdr.Name)
+		}
+	}).Return(nil)
+
+	p := NewDockerTasks(md, &mocks.ImageLog{}, hclog.NewNullLogger())
+
+	err := p.CopyToContainer("abc", filepath.Join(dir, "config"), "/etc/consul")
+	assert.NoError(t, err)
+
+	md.AssertCalled(t, "CopyToContainer", mock.Anything, "abc", "/", mock.Anything, mock.Anything)
+
+	assert.Contains(t, names, "etc/consul/config/consul.hcl")
+	assert.Contains(t, names, "etc/consul/config/tls/ca.pem")
+}
+
+func TestCopyToContainerReturnsErrorWhenCopyFails(t *testing.T) {
+	dir, md, cleanup := setupCopyToContainer(t)
+	defer cleanup()
+
+	removeOn(&md.Mock, "CopyToContainer")
+	md.On("CopyToContainer", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
+
+	p := NewDockerTasks(md, &mocks.ImageLog{}, hclog.NewNullLogger())
+
+	err := p.CopyToContainer("abc", filepath.Join(dir, "config", "consul.hcl"), "/etc/consul")
+	assert.Error(t, err)
+}
@@ -0,0 +1,143 @@
+package clients
+
+import (
+	"archive/tar"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupImageBuild(t *testing.T, output string) (*config.ContainerBuild, *mocks.MockDocker, *mocks.ImageLog, func()) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+
+	ioutil.WriteFile(filepath.Join(dir, "Dockerfile"), []byte("FROM alpine"), os.ModePerm)
+	os.MkdirAll(filepath.Join(dir, "src"), os.ModePerm)
+	ioutil.WriteFile(filepath.Join(dir, "src", "main.go"), []byte("package main"), os.ModePerm)
+
+	cb := config.NewContainerBuild("app")
+	cb.Context = dir
+
+	md := &mocks.MockDocker{}
+	md.On("ImageBuild", mock.Anything, mock.Anything, mock.Anything).Return(
+		types.ImageBuildResponse{Body: ioutil.NopCloser(strings.NewReader(output))},
+		nil,
+	)
+
+	mic := &mocks.ImageLog{}
+	mic.On("Log", mock.Anything, mock.Anything).Return(nil)
+
+	return cb, md, mic, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+// captureBuildContext replaces the ImageBuild mock with one which reads
+// the names of the files in the build context, the context is streamed so
+// it must be read before ImageBuild returns
+func captureBuildContext(t *testing.T, md *mocks.MockDocker, output string) *[]string {
+	files := []string{}
+
+	removeOn(&md.Mock, "ImageBuild")
+	md.On("ImageBuild", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		tr := tar.NewReader(args.Get(1).(io.Reader))
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+
+			require.NoError(t, err)
+			files = append(files, hdr.Name)
+		}
+	}).Return(
+		types.ImageBuildResponse{Body: ioutil.NopCloser(strings.NewReader(output))},
+		nil,
+	)
+
+	return &files
+}
+
+func TestBuildContainerBuildsImageWithContext(t *testing.T) {
+	cb, md, mic, cleanup := setupImageBuild(t, `{"stream":"Step 1/1 : FROM alpine"}`)
+	defer cleanup()
+
+	files := captureBuildContext(t, md, `{"stream":"Step 1/1 : FROM alpine"}`)
+
+	p := NewDockerTasks(md, mic, hclog.NewNullLogger())
+
+	name, err := p.BuildContainer(context.Background(), cb)
+	assert.NoError(t, err)
+	assert.Equal(t, "shipyard.run/build/app:latest", name)
+
+	params := getCalls(&md.Mock, "ImageBuild")[0].Arguments
+	assert.Equal(t, types.ImageBuildOptions{Dockerfile: "Dockerfile", Tags: []string{cb.Tag}, Remove: true}, params[2])
+
+	// check the context contains the files
+	assert.ElementsMatch(t, []string{"Dockerfile", "src", "src/main.go"}, *files)
+
+	mic.AssertCalled(t, "Log", cb.Tag, ImageTypeDocker)
+}
+
+func TestBuildContainerDoesNotSendFilesInDockerIgnore(t *testing.T) {
+	cb, md, mic, cleanup := setupImageBuild(t, "")
+	defer cleanup()
+
+	os.MkdirAll(filepath.Join(cb.Context, "node_modules", "lib"), os.ModePerm)
+	ioutil.WriteFile(filepath.Join(cb.Context, "node_modules", "lib", "index.js"), []byte(""), os.ModePerm)
+	ioutil.WriteFile(filepath.Join(cb.Context, "src", "main_test.go"), []byte("package main"), os.ModePerm)
+	ioutil.WriteFile(filepath.Join(cb.Context, ".dockerignore"), []byte("node_modules\n**/*_test.go\nDockerfile\n.dockerignore\n"), os.ModePerm)
+
+	files := captureBuildContext(t, md, "")
+
+	p := NewDockerTasks(md, mic, hclog.NewNullLogger())
+
+	_, err := p.BuildContainer(context.Background(), cb)
+	assert.NoError(t, err)
+
+	// the Dockerfile and .dockerignore are always sent
+	assert.ElementsMatch(t, []string{".dockerignore", "Dockerfile", "src", "src/main.go"}, *files)
+}
+
+func TestBuildContainerReturnsErrorWhenBuildFails(t *testing.T) {
+	cb, md, mic, cleanup := setupImageBuild(t, `{"error":"unknown instruction: FORM"}`)
+	defer cleanup()
+
+	p := NewDockerTasks(md, mic, hclog.NewNullLogger())
+
+	_, err := p.BuildContainer(context.Background(), cb)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown instruction")
+}
+
+func TestBuildContainerDoesNotPullBuiltImage(t *testing.T) {
+	cb, md, mic, cleanup := setupImageBuild(t, "")
+	defer cleanup()
+
+	md.On("ImageList", mock.Anything, mock.Anything).Return([]types.ImageSummary{{ID: "abc"}}, nil)
+
+	p := NewDockerTasks(md, mic, hclog.NewNullLogger())
+	p.SetForcePull(true)
+
+	_, err := p.BuildContainer(context.Background(), cb)
+	assert.NoError(t, err)
+
+	// built images are only checked in the local cache
+	err = p.PullImage(context.Background(), config.Image{Name: cb.Tag}, true)
+	assert.NoError(t, err)
+
+	md.AssertCalled(t, "ImageList", mock.Anything, mock.Anything)
+	md.AssertNotCalled(t, "ImagePull", mock.Anything, mock.Anything, mock.Anything)
+}
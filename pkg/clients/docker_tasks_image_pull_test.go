@@ -1,6 +1,7 @@
 package clients
 
 import (
+	"context"
 	"encoding/base64"
 	"io/ioutil"
 	"strings"
@@ -11,8 +12,13 @@ import (
 	"github.com/hashicorp/go-hclog"
 	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
 	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/tracing"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	exporttrace "go.opentelemetry.io/otel/sdk/export/trace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 func setupImagePullMocks() (*mocks.MockDocker, *mocks.ImageLog) {
@@ -43,7 +49,7 @@ func setupImagePull(t *testing.T, cc config.Image, md *mocks.MockDocker, mic *mo
 	p := NewDockerTasks(md, mic, hclog.NewNullLogger())
 
 	// create the container
-	err := p.PullImage(cc, force)
+	err := p.PullImage(context.Background(), cc, force)
 	assert.NoError(t, err)
 
 	return
@@ -119,3 +125,61 @@ func TestPullImageAlwaysWhenForce(t *testing.T) {
 	md.AssertCalled(t, "ImagePull", mock.Anything, mock.Anything, mock.Anything)
 	mic.AssertCalled(t, "Log", mock.Anything, mock.Anything)
 }
+
+func TestPullImageForcePullsImageWhichHasBeenPulled(t *testing.T) {
+	cc, md, mic := createImagePullConfig()
+
+	p := NewDockerTasks(md, mic, hclog.NewNullLogger())
+	err := p.PullImage(context.Background(), cc, false)
+	assert.NoError(t, err)
+
+	err = p.PullImage(context.Background(), cc, true)
+	assert.NoError(t, err)
+
+	md.AssertNumberOfCalls(t, "ImagePull", 2)
+}
+
+func TestPullImageReturnsErrorFromPullStream(t *testing.T) {
+	cc, md, mic := createImagePullConfig()
+	removeOn(&md.Mock, "ImagePull")
+	md.On("ImagePull", mock.Anything, mock.Anything, mock.Anything).Return(
+		ioutil.NopCloser(strings.NewReader(`{"error":"manifest unknown"}`)),
+		nil,
+	)
+
+	p := NewDockerTasks(md, mic, hclog.NewNullLogger())
+	err := p.PullImage(context.Background(), cc, false)
+	assert.Error(t, err)
+
+	mic.AssertNotCalled(t, "Log", mock.Anything, mock.Anything)
+}
+
+type spanRecorder struct {
+	spans []*exporttrace.SpanData
+}
+
+func (s *spanRecorder) ExportSpan(ctx context.Context, d *exporttrace.SpanData) {
+	s.spans = append(s.spans, d)
+}
+
+func TestPullImageSpanIsChildOfContext(t *testing.T) {
+	sr := &spanRecorder{}
+	tp, err := sdktrace.NewProvider(sdktrace.WithSyncer(sr))
+	assert.NoError(t, err)
+
+	global.SetTraceProvider(tp)
+	defer global.SetTraceProvider(trace.NoopProvider{})
+
+	cc, md, mic := createImagePullConfig()
+	p := NewDockerTasks(md, mic, hclog.NewNullLogger())
+
+	ctx, span := tracing.Tracer().Start(context.Background(), "provider.create")
+	err = p.PullImage(ctx, cc, false)
+	assert.NoError(t, err)
+	span.End()
+
+	assert.Len(t, sr.spans, 2)
+	assert.Equal(t, "pull_image", sr.spans[0].Name)
+	assert.Equal(t, span.SpanContext().SpanID, sr.spans[0].ParentSpanID)
+	assert.Equal(t, span.SpanContext().TraceID, sr.spans[0].SpanContext.TraceID)
+}
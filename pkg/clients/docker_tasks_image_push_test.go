@@ -0,0 +1,64 @@
+package clients
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupImagePush(output string) *mocks.MockDocker {
+	md := &mocks.MockDocker{}
+	md.On("ImageTag", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	md.On("ImagePush", mock.Anything, mock.Anything, mock.Anything).Return(
+		ioutil.NopCloser(strings.NewReader(output)),
+		nil,
+	)
+
+	return md
+}
+
+func TestPushImageTagsAndPushesImage(t *testing.T) {
+	md := setupImagePush(`{"status":"Pushed"}`)
+	p := NewDockerTasks(md, &mocks.ImageLog{}, hclog.NewNullLogger())
+
+	name, err := p.PushImage("consul:1.8.1", "localhost:5000")
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost:5000/consul:1.8.1", name)
+
+	md.AssertCalled(t, "ImageTag", mock.Anything, "consul:1.8.1", "localhost:5000/consul:1.8.1")
+	md.AssertCalled(t, "ImagePush", mock.Anything, "localhost:5000/consul:1.8.1", mock.Anything)
+}
+
+func TestPushImageReturnsErrorWhenStreamContainsError(t *testing.T) {
+	md := setupImagePush(`{"error":"denied"}`)
+	p := NewDockerTasks(md, &mocks.ImageLog{}, hclog.NewNullLogger())
+
+	_, err := p.PushImage("consul:1.8.1", "localhost:5000")
+	assert.Error(t, err)
+}
+
+func TestPushImageReturnsErrorWhenTagFails(t *testing.T) {
+	md := setupImagePush("")
+	removeOn(&md.Mock, "ImageTag")
+	md.On("ImageTag", mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
+
+	p := NewDockerTasks(md, &mocks.ImageLog{}, hclog.NewNullLogger())
+
+	_, err := p.PushImage("consul:1.8.1", "localhost:5000")
+	assert.Error(t, err)
+	md.AssertNotCalled(t, "ImagePush", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRegistryImageNameReplacesRegistryHost(t *testing.T) {
+	assert.Equal(t, "localhost:5000/consul:latest", registryImageName("consul", "localhost:5000"))
+	assert.Equal(t, "localhost:5000/hashicorp/consul:1.8", registryImageName("hashicorp/consul:1.8", "localhost:5000"))
+	assert.Equal(t, "localhost:5000/org/app:v1", registryImageName("ghcr.io/org/app:v1", "localhost:5000"))
+	assert.Equal(t, "localhost:5000/app:v1", registryImageName("localhost:5001/app:v1", "localhost:5000"))
+}
+
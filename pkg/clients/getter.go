@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/hashicorp/go-getter"
+	"github.com/shipyard-run/shipyard/pkg/utils"
 	"golang.org/x/xerrors"
 )
 
@@ -74,6 +75,7 @@ func (g *GetterImpl) Get(uri, dst string) error {
 		Dst:     dst,
 		Pwd:     pwd,
 		Mode:    getter.ClientModeAny,
+		Getters: utils.Getters(),
 		Options: []getter.ClientOption{},
 	}
 
@@ -5,6 +5,8 @@ import (
 	"sync"
 
 	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
 	"golang.org/x/xerrors"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart/loader"
@@ -12,6 +14,7 @@ import (
 	"helm.sh/helm/v3/pkg/cli/values"
 	"helm.sh/helm/v3/pkg/getter"
 	"helm.sh/helm/v3/pkg/kube"
+	"helm.sh/helm/v3/pkg/repo"
 )
 
 var helmLock sync.Mutex
@@ -23,6 +26,11 @@ func init() {
 
 type Helm interface {
 	Create(kubeConfig, name, namespace, chartPath, valuesPath string, valuesString map[string]string) error
+	// CreateFromRepository installs the chart with the given version from a
+	// Helm repository, the latest version is installed when version is empty
+	CreateFromRepository(kubeConfig, name, namespace string, repo *config.HelmRepository, chart, version, valuesPath string, valuesString map[string]string) error
+	// UpdateRepository downloads the index for a Helm repository
+	UpdateRepository(repo *config.HelmRepository) error
 	Destroy(kubeConfig, name, namespace string) error
 }
 
@@ -35,6 +43,46 @@ func NewHelm(l hclog.Logger) Helm {
 }
 
 func (h *HelmImpl) Create(kubeConfig, name, namespace, chartPath, valuesPath string, valuesString map[string]string) error {
+	return h.install(kubeConfig, name, namespace, chartPath, action.ChartPathOptions{}, valuesPath, valuesString)
+}
+
+// CreateFromRepository installs a chart from a Helm repository, downloaded
+// charts are cached in the shipyard helm folder
+func (h *HelmImpl) CreateFromRepository(kubeConfig, name, namespace string, repo *config.HelmRepository, chart, version, valuesPath string, valuesString map[string]string) error {
+	cpo := action.ChartPathOptions{
+		RepoURL:  repo.URL,
+		Username: repo.Username,
+		Password: repo.Password,
+		Version:  version,
+	}
+
+	return h.install(kubeConfig, name, namespace, chart, cpo, valuesPath, valuesString)
+}
+
+// UpdateRepository downloads the index for the repository, an error is
+// returned when the repository can not be reached
+func (h *HelmImpl) UpdateRepository(r *config.HelmRepository) error {
+	settings := helmSettings()
+
+	cr, err := repo.NewChartRepository(
+		&repo.Entry{Name: r.Name, URL: r.URL, Username: r.Username, Password: r.Password},
+		getter.All(settings),
+	)
+	if err != nil {
+		return xerrors.Errorf("Invalid Helm repository %s: %w", r.URL, err)
+	}
+
+	cr.CachePath = settings.RepositoryCache
+
+	_, err = cr.DownloadIndexFile()
+	if err != nil {
+		return xerrors.Errorf("Unable to download index for Helm repository %s: %w", r.URL, err)
+	}
+
+	return nil
+}
+
+func (h *HelmImpl) install(kubeConfig, name, namespace, chartPath string, cpo action.ChartPathOptions, valuesPath string, valuesString map[string]string) error {
 	// set the kubeclient for Helm
 	s := kube.GetConfig(kubeConfig, "default", namespace)
 	cfg := &action.Configuration{}
@@ -49,9 +97,10 @@ func (h *HelmImpl) Create(kubeConfig, name, namespace, chartPath, valuesPath str
 	client := action.NewInstall(cfg)
 	client.ReleaseName = name
 	client.Namespace = namespace
+	client.ChartPathOptions = cpo
 
-	settings := cli.EnvSettings{}
-	p := getter.All(&settings)
+	settings := helmSettings()
+	p := getter.All(settings)
 	vo := values.Options{}
 	vo.StringValues = []string{}
 
@@ -66,7 +115,7 @@ func (h *HelmImpl) Create(kubeConfig, name, namespace, chartPath, valuesPath str
 	}
 
 	h.log.Debug("Creating chart from config", "ref", name, "path", chartPath)
-	cp, err := client.ChartPathOptions.LocateChart(chartPath, &settings)
+	cp, err := client.ChartPathOptions.LocateChart(chartPath, settings)
 	if err != nil {
 		return xerrors.Errorf("Error locating chart: %w", err)
 	}
@@ -97,6 +146,12 @@ func (h *HelmImpl) Create(kubeConfig, name, namespace, chartPath, valuesPath str
 	return nil
 }
 
+// helmSettings returns the settings for the Helm client, charts and
+// repository indexes are cached in the shipyard helm folder
+func helmSettings() *cli.EnvSettings {
+	return &cli.EnvSettings{RepositoryCache: utils.GetHelmLocalFolder("cache")}
+}
+
 // Destroy removes an installed Helm chart from the system
 func (h *HelmImpl) Destroy(kubeConfig, name, namespace string) error {
 	s := kube.GetConfig(kubeConfig, "default", namespace)
@@ -2,7 +2,10 @@ package clients
 
 import (
 	"fmt"
+	"io/ioutil"
+	"net"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/go-hclog"
@@ -15,6 +18,13 @@ type HTTP interface {
 	// If it is not possible to contact the URI or if any status other than 200 is returned
 	// by the upstream, then the URI is retried until the timeout elapses.
 	HealthCheckHTTP(uri string, timeout time.Duration) error
+	// HealthCheckHTTPResponse makes a HTTP request to the given URI using method
+	// and returns a nil error when the status code is one of codes and the
+	// response body contains body. The URI is retried until the timeout elapses.
+	HealthCheckHTTPResponse(uri, method string, codes []int, body string, timeout time.Duration) error
+	// HealthCheckTCP attempts to open a TCP connection to the given address,
+	// the connection is retried until the timeout elapses
+	HealthCheckTCP(address string, timeout time.Duration) error
 	// Do executes a HTTP request and returns the response
 	Do(r *http.Request) (*http.Response, error)
 }
@@ -49,6 +59,87 @@ func (h *HTTPImpl) HealthCheckHTTP(address string, timeout time.Duration) error
 	}
 }
 
+// HealthCheckHTTPResponse makes a HTTP request to the given URI and checks
+// the status code and body of the response
+func (h *HTTPImpl) HealthCheckHTTPResponse(address, method string, codes []int, body string, timeout time.Duration) error {
+	h.l.Debug("Performing health check for address", "address", address, "method", method, "codes", codes)
+	st := time.Now()
+	for {
+		if time.Now().Sub(st) > timeout {
+			h.l.Error("Timeout wating for HTTP healthcheck", "address", address)
+
+			return fmt.Errorf("Timeout waiting for HTTP healthcheck %s", address)
+		}
+
+		if h.checkResponse(address, method, codes, body) {
+			h.l.Debug("Health check complete", "address", address)
+			return nil
+		}
+
+		// backoff
+		time.Sleep(h.backoff)
+	}
+}
+
+func (h *HTTPImpl) checkResponse(address, method string, codes []int, body string) bool {
+	rq, err := http.NewRequest(method, address, nil)
+	if err != nil {
+		return false
+	}
+
+	resp, err := http.DefaultClient.Do(rq)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	codeOK := false
+	for _, c := range codes {
+		if resp.StatusCode == c {
+			codeOK = true
+		}
+	}
+
+	if !codeOK {
+		h.l.Debug("Health check status code does not match", "address", address, "status", resp.StatusCode)
+		return false
+	}
+
+	if body == "" {
+		return true
+	}
+
+	d, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return false
+	}
+
+	return strings.Contains(string(d), body)
+}
+
+// HealthCheckTCP attempts to open a TCP connection to the given address
+func (h *HTTPImpl) HealthCheckTCP(address string, timeout time.Duration) error {
+	h.l.Debug("Performing TCP health check for address", "address", address)
+	st := time.Now()
+	for {
+		if time.Now().Sub(st) > timeout {
+			h.l.Error("Timeout wating for TCP healthcheck", "address", address)
+
+			return fmt.Errorf("Timeout waiting for TCP healthcheck %s", address)
+		}
+
+		conn, err := net.DialTimeout("tcp", address, time.Second)
+		if err == nil {
+			conn.Close()
+			h.l.Debug("Health check complete", "address", address)
+			return nil
+		}
+
+		// backoff
+		time.Sleep(h.backoff)
+	}
+}
+
 // Do executes a HTTP request and returns the response
 func (h *HTTPImpl) Do(r *http.Request) (*http.Response, error) {
 	return http.DefaultClient.Do(r)
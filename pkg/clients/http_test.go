@@ -3,6 +3,7 @@ package clients
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -55,3 +56,41 @@ func TestHTTPHealthErrorsOnClientError(t *testing.T) {
 	assert.Error(t, err)
 	assert.Len(t, *reqs, 0)
 }
+
+func TestHTTPHealthResponseMatchesStatusAndBody(t *testing.T) {
+	url, reqs, cleanup := testSetupHTTPBasicServer(http.StatusAccepted, `{"status": "ok"}`)
+	defer cleanup()
+
+	c := NewHTTP(1*time.Millisecond, hclog.NewNullLogger())
+
+	err := c.HealthCheckHTTPResponse(url, http.MethodHead, []int{http.StatusOK, http.StatusAccepted}, "", 10*time.Millisecond)
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodHead, (*reqs)[0].Method)
+
+	err = c.HealthCheckHTTPResponse(url, http.MethodGet, []int{http.StatusAccepted}, `"ok"`, 10*time.Millisecond)
+	assert.NoError(t, err)
+}
+
+func TestHTTPHealthResponseRetriesWhenBodyDoesNotMatch(t *testing.T) {
+	url, reqs, cleanup := testSetupHTTPBasicServer(http.StatusOK, `{"status": "starting"}`)
+	defer cleanup()
+
+	c := NewHTTP(1*time.Millisecond, hclog.NewNullLogger())
+
+	err := c.HealthCheckHTTPResponse(url, http.MethodGet, []int{http.StatusOK}, `"ok"`, 10*time.Millisecond)
+	assert.Error(t, err)
+	assert.Greater(t, len(*reqs), 1)
+}
+
+func TestHTTPHealthTCPConnects(t *testing.T) {
+	url, _, cleanup := testSetupHTTPBasicServer(http.StatusOK, "")
+	defer cleanup()
+
+	c := NewHTTP(1*time.Millisecond, hclog.NewNullLogger())
+
+	err := c.HealthCheckTCP(strings.TrimPrefix(url, "http://"), 10*time.Millisecond)
+	assert.NoError(t, err)
+
+	err = c.HealthCheckTCP("127.0.0.2:19091", 10*time.Millisecond)
+	assert.Error(t, err)
+}
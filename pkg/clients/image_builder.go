@@ -0,0 +1,188 @@
+package clients
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/builder/dockerignore"
+	"github.com/docker/docker/pkg/fileutils"
+	"github.com/hashicorp/go-hclog"
+	"golang.org/x/xerrors"
+)
+
+// buildMessage is a single message from the Docker image build stream
+type buildMessage struct {
+	Stream string `json:"stream"`
+	Error  string `json:"error"`
+}
+
+// readBuildOutput reads the Docker image build stream logging the output of
+// each build step, an error is returned if the stream contains an error message
+func readBuildOutput(image string, r io.Reader, l hclog.Logger) error {
+	start := time.Now()
+
+	dec := json.NewDecoder(r)
+	for {
+		m := buildMessage{}
+		err := dec.Decode(&m)
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			// the stream is not in the expected format, there is no output
+			// to report but the stream must still be read to complete the build
+			l.Debug("Unable to read image build output", "image", image, "error", err)
+			io.Copy(ioutil.Discard, r)
+			return nil
+		}
+
+		if m.Error != "" {
+			return fmt.Errorf("Error building image %s: %s", image, m.Error)
+		}
+
+		if s := strings.TrimSpace(m.Stream); s != "" {
+			l.Debug("Building image", "image", image, "output", s)
+		}
+	}
+
+	l.Debug("Image build complete", "image", image, "duration", time.Since(start))
+
+	return nil
+}
+
+// tarBuildContext returns a tar archive containing the files in the folder
+// which is sent to Docker as the build context. Files matching the patterns
+// in the .dockerignore file are not added, the Dockerfile and .dockerignore
+// are always sent as Docker reads them from the context.
+func tarBuildContext(folder, dockerfile string) (io.ReadCloser, error) {
+	excludes, err := readDockerIgnore(folder)
+	if err != nil {
+		return nil, err
+	}
+
+	keep := []string{dockerignoreFile, filepath.ToSlash(filepath.Clean(dockerfile))}
+	for _, k := range keep {
+		if m, _ := fileutils.Matches(k, excludes); m {
+			excludes = append(excludes, "!"+k)
+		}
+	}
+
+	pm, err := fileutils.NewPatternMatcher(excludes)
+	if err != nil {
+		return nil, xerrors.Errorf("Invalid pattern in %s: %w", dockerignoreFile, err)
+	}
+
+	return tarFiles(folder, folder, "", pm), nil
+}
+
+const dockerignoreFile = ".dockerignore"
+
+// readDockerIgnore returns the exclude patterns from the .dockerignore file
+// in the folder, no patterns are returned when the file does not exist
+func readDockerIgnore(folder string) ([]string, error) {
+	f, err := os.Open(filepath.Join(folder, dockerignoreFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return dockerignore.ReadAll(f)
+}
+
+// tarFiles returns a tar archive containing the file or folder src, the
+// names in the archive are relative to root and start with prefix. Files
+// with a path relative to root which match excludes are not added, excludes
+// can be nil. The archive is written as it is read so the files are never
+// held in memory, any error creating the archive is returned by Read.
+func tarFiles(root, src, prefix string, excludes *fileutils.PatternMatcher) io.ReadCloser {
+	pr, pw := io.Pipe()
+
+	go func() {
+		tw := tar.NewWriter(pw)
+
+		err := filepath.Walk(src, func(path string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			rel, err := filepath.Rel(root, path)
+			if err != nil || rel == "." {
+				return err
+			}
+
+			if excludes != nil {
+				skip, err := excludes.Matches(rel)
+				if err != nil {
+					return err
+				}
+
+				// a folder can only be skipped when no files in it can be
+				// added back with an exception
+				if skip && fi.IsDir() && !excludes.Exclusions() {
+					return filepath.SkipDir
+				}
+
+				if skip {
+					return nil
+				}
+			}
+
+			// symlinks are added as links, all other non regular files are ignored
+			link := ""
+			if fi.Mode()&os.ModeSymlink != 0 {
+				link, err = os.Readlink(path)
+				if err != nil {
+					return err
+				}
+			} else if !fi.Mode().IsRegular() && !fi.IsDir() {
+				return nil
+			}
+
+			hdr, err := tar.FileInfoHeader(fi, link)
+			if err != nil {
+				return err
+			}
+
+			// paths in the archive always use forward slashes
+			hdr.Name = strings.TrimPrefix(filepath.ToSlash(filepath.Join(prefix, rel)), "/")
+
+			err = tw.WriteHeader(hdr)
+			if err != nil {
+				return err
+			}
+
+			if !fi.Mode().IsRegular() {
+				return nil
+			}
+
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			_, err = io.Copy(tw, f)
+			return err
+		})
+
+		if err == nil {
+			err = tw.Close()
+		}
+
+		pw.CloseWithError(err)
+	}()
+
+	return pr
+}
@@ -0,0 +1,121 @@
+package clients
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// imagePuller ensures that an image is only pulled once at a time, resources
+// are created in parallel and many resources can reference the same image.
+// Concurrent requests with the same key wait for the in progress pull and
+// share its result. Results are not cached, later requests pull the image
+// again.
+type imagePuller struct {
+	mutex    sync.Mutex
+	inflight map[string]*pullCall
+}
+
+type pullCall struct {
+	done chan struct{}
+	err  error
+}
+
+func newImagePuller() *imagePuller {
+	return &imagePuller{inflight: map[string]*pullCall{}}
+}
+
+// Pull executes f for the key unless a pull for the key is in progress,
+// in which case it waits for the pull to complete and returns its result
+func (p *imagePuller) Pull(key string, f func() error) error {
+	p.mutex.Lock()
+
+	if c, ok := p.inflight[key]; ok {
+		p.mutex.Unlock()
+		<-c.done
+
+		return c.err
+	}
+
+	c := &pullCall{done: make(chan struct{})}
+	p.inflight[key] = c
+	p.mutex.Unlock()
+
+	c.err = f()
+
+	p.mutex.Lock()
+	delete(p.inflight, key)
+	p.mutex.Unlock()
+
+	close(c.done)
+
+	return c.err
+}
+
+// pullMessage is a single progress message from the Docker image pull stream
+type pullMessage struct {
+	ID             string `json:"id"`
+	Status         string `json:"status"`
+	Error          string `json:"error"`
+	ProgressDetail struct {
+		Current int64 `json:"current"`
+		Total   int64 `json:"total"`
+	} `json:"progressDetail"`
+}
+
+// progressInterval is the minimum time between progress log messages
+var progressInterval = 5 * time.Second
+
+// readPullProgress reads the Docker image pull stream logging the progress of
+// the download, an error is returned if the stream contains an error message
+func readPullProgress(image string, r io.Reader, l hclog.Logger) error {
+	start := time.Now()
+	last := start
+	layers := map[string]pullMessage{}
+
+	dec := json.NewDecoder(r)
+	for {
+		m := pullMessage{}
+		err := dec.Decode(&m)
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			// the stream is not in the expected format, there is no progress
+			// to report but the stream must still be read to complete the pull
+			l.Debug("Unable to read image pull progress", "image", image, "error", err)
+			io.Copy(ioutil.Discard, r)
+			return nil
+		}
+
+		if m.Error != "" {
+			return fmt.Errorf("Error pulling image %s: %s", image, m.Error)
+		}
+
+		if m.ID != "" && m.ProgressDetail.Total > 0 {
+			layers[m.ID] = m
+		}
+
+		if time.Since(last) >= progressInterval {
+			last = time.Now()
+
+			var current, total int64
+			for _, lm := range layers {
+				current += lm.ProgressDetail.Current
+				total += lm.ProgressDetail.Total
+			}
+
+			l.Info("Pulling image", "image", image, "progress", fmt.Sprintf("%dMB/%dMB", current/1000000, total/1000000))
+		}
+	}
+
+	l.Debug("Image pull complete", "image", image, "duration", time.Since(start))
+
+	return nil
+}
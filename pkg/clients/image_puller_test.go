@@ -0,0 +1,101 @@
+package clients
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImagePullerPullsConcurrentRequestsOnce(t *testing.T) {
+	p := newImagePuller()
+
+	var calls int32
+	start := make(chan struct{})
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+
+			err := p.Pull("consul", func() error {
+				atomic.AddInt32(&calls, 1)
+				time.Sleep(50 * time.Millisecond)
+				return nil
+			})
+			assert.NoError(t, err)
+		}()
+	}
+
+	close(start)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls)
+}
+
+func TestImagePullerPullsAgainWhenPullHasCompleted(t *testing.T) {
+	p := newImagePuller()
+	calls := 0
+
+	f := func() error { calls++; return nil }
+	p.Pull("consul", f)
+	p.Pull("consul", f)
+	p.Pull("vault", f)
+
+	assert.Equal(t, 3, calls)
+}
+
+func TestImagePullerRetriesFailedPulls(t *testing.T) {
+	p := newImagePuller()
+	calls := 0
+
+	err := p.Pull("consul", func() error { calls++; return fmt.Errorf("boom") })
+	assert.Error(t, err)
+
+	err = p.Pull("consul", func() error { calls++; return nil })
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, calls)
+}
+
+func TestReadPullProgressReturnsStreamError(t *testing.T) {
+	stream := `{"status":"Pulling from library/consul","id":"1.6.1"}
+{"errorDetail":{"message":"unauthorized"},"error":"unauthorized"}`
+
+	err := readPullProgress("consul", strings.NewReader(stream), hclog.NewNullLogger())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unauthorized")
+}
+
+func TestReadPullProgressCompletesWithValidStream(t *testing.T) {
+	stream := `{"status":"Downloading","progressDetail":{"current":100,"total":200},"id":"abc"}
+{"status":"Pull complete","progressDetail":{},"id":"abc"}`
+
+	err := readPullProgress("consul", strings.NewReader(stream), hclog.NewNullLogger())
+	assert.NoError(t, err)
+}
+
+func TestReadPullProgressIgnoresInvalidStream(t *testing.T) {
+	err := readPullProgress("consul", strings.NewReader("hello world"), hclog.NewNullLogger())
+	assert.NoError(t, err)
+}
+
+func TestPullKeyDiffersForCredentialsAndForce(t *testing.T) {
+	img := config.Image{Name: "consul:1.6.1"}
+	auth := config.Image{Name: "consul:1.6.1", Username: "nic", Password: "secret"}
+	other := config.Image{Name: "consul:1.6.1", Username: "nic", Password: "other"}
+
+	assert.Equal(t, pullKey(img, false), pullKey(config.Image{Name: "consul:1.6.1"}, false))
+	assert.NotEqual(t, pullKey(img, false), pullKey(img, true))
+	assert.NotEqual(t, pullKey(img, false), pullKey(auth, false))
+	assert.NotEqual(t, pullKey(auth, false), pullKey(other, false))
+	assert.NotContains(t, pullKey(auth, false), "secret")
+}
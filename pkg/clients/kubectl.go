@@ -6,12 +6,18 @@ import (
 	"path"
 	"path/filepath"
 	"time"
+	"unicode/utf8"
 
 	"github.com/hashicorp/go-hclog"
 	"golang.org/x/xerrors"
 	"helm.sh/helm/v3/pkg/kube"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/clientcmd"
@@ -21,15 +27,32 @@ import (
 type Kubernetes interface {
 	SetConfig(string) error
 	GetPods(string) (*v1.PodList, error)
+	GetService(name, namespace string) (*v1.Service, error)
 	HealthCheckPods(selectors []string, timeout time.Duration) error
+	// WaitForCRDs waits until the custom resource definitions with the
+	// given names are established
+	WaitForCRDs(names []string, timeout time.Duration) error
+	// WaitForJobs waits until the jobs matching the selectors have
+	// completed, an error is returned if a job fails
+	WaitForJobs(selectors []string, timeout time.Duration) error
 	Apply(files []string, waitUntilReady bool) error
 	Delete(files []string) error
+	CreateNamespace(name string, labels, annotations map[string]string) error
+	DeleteNamespace(name string) error
+	// CreateSecret creates a Secret or replaces the data of an existing Secret
+	CreateSecret(namespace, name, secretType string, data map[string][]byte) error
+	DeleteSecret(namespace, name string) error
+	// CreateConfigMap creates a ConfigMap or replaces the data of an existing
+	// ConfigMap, values which are not valid UTF-8 are stored as binary data
+	CreateConfigMap(namespace, name string, data map[string][]byte) error
+	DeleteConfigMap(namespace, name string) error
 }
 
 // KubernetesImpl is a concrete implementation of a Kubernetes client
 type KubernetesImpl struct {
 	clientset  *kubernetes.Clientset
 	client     corev1.CoreV1Interface
+	dynamic    dynamic.Interface
 	configPath string
 	timeout    time.Duration
 	l          hclog.Logger
@@ -73,8 +96,14 @@ func (k *KubernetesImpl) setConfig() error {
 		return err
 	}
 
+	dc, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
 	k.clientset = clientset
 	k.client = clientset.CoreV1()
+	k.dynamic = dc
 
 	return nil
 }
@@ -92,6 +121,138 @@ func (k *KubernetesImpl) GetPods(selector string) (*v1.PodList, error) {
 	return pl, nil
 }
 
+// GetService returns the Kubernetes service with the given name and namespace
+func (k *KubernetesImpl) GetService(name, namespace string) (*v1.Service, error) {
+	return k.client.Services(namespace).Get(name, metav1.GetOptions{})
+}
+
+// CreateNamespace creates a namespace with the given labels and annotations,
+// the labels and annotations of an existing namespace are updated
+func (k *KubernetesImpl) CreateNamespace(name string, labels, annotations map[string]string) error {
+	ns, err := k.client.Namespaces().Get(name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		_, err = k.client.Namespaces().Create(&v1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels, Annotations: annotations},
+		})
+
+		return err
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if ns.Labels == nil {
+		ns.Labels = map[string]string{}
+	}
+
+	if ns.Annotations == nil {
+		ns.Annotations = map[string]string{}
+	}
+
+	for k, v := range labels {
+		ns.Labels[k] = v
+	}
+
+	for k, v := range annotations {
+		ns.Annotations[k] = v
+	}
+
+	_, err = k.client.Namespaces().Update(ns)
+	return err
+}
+
+// DeleteNamespace deletes a namespace, it is not an error if the namespace
+// does not exist
+func (k *KubernetesImpl) DeleteNamespace(name string) error {
+	err := k.client.Namespaces().Delete(name, &metav1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+// CreateSecret creates a Secret or replaces the data of an existing Secret
+func (k *KubernetesImpl) CreateSecret(namespace, name, secretType string, data map[string][]byte) error {
+	sec, err := k.client.Secrets(namespace).Get(name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		_, err = k.client.Secrets(namespace).Create(&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Type:       v1.SecretType(secretType),
+			Data:       data,
+		})
+
+		return err
+	}
+
+	if err != nil {
+		return err
+	}
+
+	sec.Data = data
+	_, err = k.client.Secrets(namespace).Update(sec)
+	return err
+}
+
+// DeleteSecret deletes a Secret, it is not an error if the Secret does not
+// exist
+func (k *KubernetesImpl) DeleteSecret(namespace, name string) error {
+	err := k.client.Secrets(namespace).Delete(name, &metav1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+// CreateConfigMap creates a ConfigMap or replaces the data of an existing
+// ConfigMap, values which are not valid UTF-8 are stored as binary data
+func (k *KubernetesImpl) CreateConfigMap(namespace, name string, data map[string][]byte) error {
+	text := map[string]string{}
+	binary := map[string][]byte{}
+
+	for key, v := range data {
+		if utf8.Valid(v) {
+			text[key] = string(v)
+			continue
+		}
+
+		binary[key] = v
+	}
+
+	cm, err := k.client.ConfigMaps(namespace).Get(name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		_, err = k.client.ConfigMaps(namespace).Create(&v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Data:       text,
+			BinaryData: binary,
+		})
+
+		return err
+	}
+
+	if err != nil {
+		return err
+	}
+
+	cm.Data = text
+	cm.BinaryData = binary
+	_, err = k.client.ConfigMaps(namespace).Update(cm)
+	return err
+}
+
+// DeleteConfigMap deletes a ConfigMap, it is not an error if the ConfigMap
+// does not exist
+func (k *KubernetesImpl) DeleteConfigMap(namespace, name string) error {
+	err := k.client.ConfigMaps(namespace).Delete(name, &metav1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
 // Apply Kubernetes YAML files at path
 // if waitUntilReady is true then the client will block until all resources have been created
 func (k *KubernetesImpl) Apply(files []string, waitUntilReady bool) error {
@@ -203,6 +364,99 @@ func (k *KubernetesImpl) healthCheckSingle(selector string, timeout time.Duratio
 	return nil
 }
 
+var crdResource = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// WaitForCRDs waits until the custom resource definitions are established
+func (k *KubernetesImpl) WaitForCRDs(names []string, timeout time.Duration) error {
+	for _, n := range names {
+		k.l.Debug("Waiting for custom resource definition", "name", n)
+
+		st := time.Now()
+		for {
+			if time.Now().Sub(st) > timeout {
+				return fmt.Errorf("Timeout waiting for custom resource definition %s to be established", n)
+			}
+
+			// Get may return an error if the API server is not available or
+			// the CRD has not been created
+			crd, err := k.dynamic.Resource(crdResource).Get(n, metav1.GetOptions{})
+			if err == nil && hasCondition(crd.Object, "Established") {
+				break
+			}
+
+			// backoff
+			time.Sleep(2 * time.Second)
+		}
+	}
+
+	return nil
+}
+
+// WaitForJobs waits until all the jobs matching the selectors have completed
+func (k *KubernetesImpl) WaitForJobs(selectors []string, timeout time.Duration) error {
+	for _, s := range selectors {
+		k.l.Debug("Waiting for jobs", "selector", s)
+
+		st := time.Now()
+		for {
+			if time.Now().Sub(st) > timeout {
+				return fmt.Errorf("Timeout waiting for jobs %s to complete", s)
+			}
+
+			jl, err := k.clientset.BatchV1().Jobs("").List(metav1.ListOptions{LabelSelector: s})
+			if err == nil && len(jl.Items) > 0 {
+				complete := true
+				for _, j := range jl.Items {
+					for _, c := range j.Status.Conditions {
+						if c.Type == batchv1.JobFailed && c.Status == v1.ConditionTrue {
+							return fmt.Errorf("Job %s in namespace %s failed: %s", j.Name, j.Namespace, c.Message)
+						}
+					}
+
+					if !jobComplete(j) {
+						complete = false
+						k.l.Debug("Job not complete", "job", j.Name, "namespace", j.Namespace)
+					}
+				}
+
+				if complete {
+					break
+				}
+			}
+
+			// backoff
+			time.Sleep(2 * time.Second)
+		}
+	}
+
+	return nil
+}
+
+func jobComplete(j batchv1.Job) bool {
+	for _, c := range j.Status.Conditions {
+		if c.Type == batchv1.JobComplete && c.Status == v1.ConditionTrue {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasCondition returns true when the status of an object contains the
+// condition with the status True
+func hasCondition(obj map[string]interface{}, condition string) bool {
+	conditions, _, _ := unstructured.NestedSlice(obj, "status", "conditions")
+
+	for _, c := range conditions {
+		cm, ok := c.(map[string]interface{})
+		if ok && cm["type"] == condition && cm["status"] == "True" {
+			return true
+		}
+	}
+
+	return false
+}
+
 func buildFileList(files []string) ([]string, error) {
 	allFiles := make([]string, 0)
 
@@ -2,6 +2,10 @@ package clients
 
 import (
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 // TODO: implement these tests
@@ -14,6 +18,47 @@ func TestApply(t *testing.T) {
 	t.Skip()
 }
 
+func TestCreateConfigMapStoresBinaryValues(t *testing.T) {
+	k := &KubernetesImpl{client: fake.NewSimpleClientset().CoreV1()}
+
+	err := k.CreateConfigMap("default", "config", map[string][]byte{"app.json": []byte("{}"), "cert.der": []byte{0xff, 0xfe}})
+	assert.NoError(t, err)
+
+	cm, err := k.client.ConfigMaps("default").Get("config", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", cm.Data["app.json"])
+	assert.Equal(t, []byte{0xff, 0xfe}, cm.BinaryData["cert.der"])
+
+	err = k.CreateConfigMap("default", "config", map[string][]byte{"app.json": []byte("[]")})
+	assert.NoError(t, err)
+
+	cm, err = k.client.ConfigMaps("default").Get("config", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"app.json": "[]"}, cm.Data)
+	assert.Len(t, cm.BinaryData, 0)
+
+	err = k.DeleteConfigMap("default", "config")
+	assert.NoError(t, err)
+
+	err = k.DeleteConfigMap("default", "config")
+	assert.NoError(t, err)
+}
+
+func TestCreateSecretUpdatesExistingSecret(t *testing.T) {
+	k := &KubernetesImpl{client: fake.NewSimpleClientset().CoreV1()}
+
+	err := k.CreateSecret("default", "db", "Opaque", map[string][]byte{"password": []byte("abc")})
+	assert.NoError(t, err)
+
+	err = k.CreateSecret("default", "db", "Opaque", map[string][]byte{"password": []byte("123")})
+	assert.NoError(t, err)
+
+	sec, err := k.client.Secrets("default").Get("db", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Opaque", string(sec.Type))
+	assert.Equal(t, []byte("123"), sec.Data["password"])
+}
+
 const guestbookManifest = `
 apiVersion: v1
 kind: Service
@@ -0,0 +1,21 @@
+package clients
+
+import (
+	"bytes"
+
+	"k8s.io/cli-runtime/pkg/kustomize"
+	"sigs.k8s.io/kustomize/pkg/fs"
+)
+
+// BuildKustomize runs kustomize build for the kustomization in the folder
+// path and returns the generated Kubernetes config
+func BuildKustomize(path string) ([]byte, error) {
+	out := &bytes.Buffer{}
+
+	err := kustomize.RunKustomizeBuild(out, fs.MakeRealFS(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
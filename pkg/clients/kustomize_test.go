@@ -0,0 +1,48 @@
+package clients
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const kustomization = `
+namePrefix: dev-
+resources:
+- config.yaml
+`
+
+const kustomizeConfig = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+data:
+  key: value
+`
+
+func TestBuildKustomizeBuildsKustomization(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ioutil.WriteFile(filepath.Join(dir, "kustomization.yaml"), []byte(kustomization), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "config.yaml"), []byte(kustomizeConfig), 0644)
+
+	d, err := BuildKustomize(dir)
+	assert.NoError(t, err)
+	assert.Contains(t, string(d), "name: dev-app")
+}
+
+func TestBuildKustomizeReturnsErrorWhenNoKustomization(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = BuildKustomize(dir)
+	assert.Error(t, err)
+}
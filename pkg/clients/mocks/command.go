@@ -0,0 +1,31 @@
+package mocks
+
+import "github.com/stretchr/testify/mock"
+
+// MockCommand is a mock implementation of the Command client
+// interface
+type MockCommand struct {
+	mock.Mock
+}
+
+func (m *MockCommand) Execute(command string, args ...string) error {
+	return m.Called(command, args).Error(0)
+}
+
+func (m *MockCommand) Start(command string, args []string, env []string, workingDir string, logFile string) (int, error) {
+	a := m.Called(command, args, env, workingDir, logFile)
+	return a.Int(0), a.Error(1)
+}
+
+func (m *MockCommand) Kill(pid int) error {
+	return m.Called(pid).Error(0)
+}
+
+func (m *MockCommand) Running(pid int) bool {
+	return m.Called(pid).Bool(0)
+}
+
+func (m *MockCommand) StartTime(pid int) (string, error) {
+	a := m.Called(pid)
+	return a.String(0), a.Error(1)
+}
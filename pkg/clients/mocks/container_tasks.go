@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"context"
 	"io"
 
 	"github.com/shipyard-run/shipyard/pkg/config"
@@ -39,12 +40,18 @@ func (m *MockContainerTasks) RemoveVolume(name string) error {
 	return args.Error(0)
 }
 
-func (m *MockContainerTasks) PullImage(i config.Image, f bool) error {
+func (m *MockContainerTasks) PullImage(ctx context.Context, i config.Image, f bool) error {
 	args := m.Called(i, f)
 
 	return args.Error(0)
 }
 
+func (m *MockContainerTasks) BuildContainer(ctx context.Context, c *config.ContainerBuild) (string, error) {
+	args := m.Called(c)
+
+	return args.String(0), args.Error(1)
+}
+
 func (m *MockContainerTasks) FindContainerIDs(name string, typeName config.ResourceType) ([]string, error) {
 	args := m.Called(name, typeName)
 
@@ -71,6 +78,18 @@ func (d *MockContainerTasks) CopyFromContainer(id, src, dst string) error {
 	return args.Error(0)
 }
 
+func (d *MockContainerTasks) PushImage(image, registry string) (string, error) {
+	args := d.Called(image, registry)
+
+	return args.String(0), args.Error(1)
+}
+
+func (d *MockContainerTasks) CopyToContainer(id, src, dst string) error {
+	args := d.Called(id, src, dst)
+
+	return args.Error(0)
+}
+
 func (d *MockContainerTasks) CopyLocalDockerImageToVolume(images []string, volume string, force bool) ([]string, error) {
 	args := d.Called(images, volume, force)
 
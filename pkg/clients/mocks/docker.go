@@ -215,6 +215,32 @@ func (m *MockDocker) ImageSave(ctx context.Context, imageIDs []string) (io.ReadC
 	return nil, args.Error(1)
 }
 
+func (m *MockDocker) ImageBuild(ctx context.Context, buildContext io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error) {
+	args := m.Called(ctx, buildContext, options)
+
+	if br, ok := args.Get(0).(types.ImageBuildResponse); ok {
+		return br, args.Error(1)
+	}
+
+	return types.ImageBuildResponse{}, args.Error(1)
+}
+
+func (m *MockDocker) ImageTag(ctx context.Context, source, target string) error {
+	args := m.Called(ctx, source, target)
+
+	return args.Error(0)
+}
+
+func (m *MockDocker) ImagePush(ctx context.Context, image string, options types.ImagePushOptions) (io.ReadCloser, error) {
+	args := m.Called(ctx, image, options)
+
+	if rc, ok := args.Get(0).(io.ReadCloser); ok {
+		return rc, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
 func (m *MockDocker) ImageList(ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, error) {
 	args := m.Called(ctx, options)
 
@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"github.com/shipyard-run/shipyard/pkg/config"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -14,6 +15,18 @@ func (h *MockHelm) Create(kubeConfig, name, namespace, chartPath, valuesPath str
 	return args.Error(0)
 }
 
+func (h *MockHelm) CreateFromRepository(kubeConfig, name, namespace string, repo *config.HelmRepository, chart, version, valuesPath string, valueString map[string]string) error {
+	args := h.Called(kubeConfig, name, namespace, repo, chart, version, valuesPath, valueString)
+
+	return args.Error(0)
+}
+
+func (h *MockHelm) UpdateRepository(repo *config.HelmRepository) error {
+	args := h.Called(repo)
+
+	return args.Error(0)
+}
+
 func (h *MockHelm) Destroy(kubeConfig, name, namespace string) error {
 	args := h.Called(kubeConfig, name, namespace)
 
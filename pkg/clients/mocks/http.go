@@ -21,6 +21,18 @@ func (m *MockHTTP) HealthCheckHTTP(uri string, timeout time.Duration) error {
 	return args.Error(0)
 }
 
+func (m *MockHTTP) HealthCheckHTTPResponse(uri, method string, codes []int, body string, timeout time.Duration) error {
+	args := m.Called(uri, method, codes, body, timeout)
+
+	return args.Error(0)
+}
+
+func (m *MockHTTP) HealthCheckTCP(address string, timeout time.Duration) error {
+	args := m.Called(address, timeout)
+
+	return args.Error(0)
+}
+
 func (m *MockHTTP) Do(r *http.Request) (*http.Response, error) {
 	args := m.Called(r)
 
@@ -27,6 +27,16 @@ func (m *MockKubernetes) GetPods(selector string) (*v1.PodList, error) {
 	return nil, args.Error(1)
 }
 
+func (m *MockKubernetes) GetService(name, namespace string) (*v1.Service, error) {
+	args := m.Called(name, namespace)
+
+	if s, ok := args.Get(0).(*v1.Service); ok {
+		return s, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
 func (m *MockKubernetes) Apply(files []string, waitUntilReady bool) error {
 	args := m.Called(files, waitUntilReady)
 
@@ -39,6 +49,54 @@ func (m *MockKubernetes) Delete(files []string) error {
 	return args.Error(0)
 }
 
+func (m *MockKubernetes) CreateNamespace(name string, labels, annotations map[string]string) error {
+	args := m.Called(name, labels, annotations)
+
+	return args.Error(0)
+}
+
+func (m *MockKubernetes) DeleteNamespace(name string) error {
+	args := m.Called(name)
+
+	return args.Error(0)
+}
+
+func (m *MockKubernetes) CreateSecret(namespace, name, secretType string, data map[string][]byte) error {
+	args := m.Called(namespace, name, secretType, data)
+
+	return args.Error(0)
+}
+
+func (m *MockKubernetes) DeleteSecret(namespace, name string) error {
+	args := m.Called(namespace, name)
+
+	return args.Error(0)
+}
+
+func (m *MockKubernetes) CreateConfigMap(namespace, name string, data map[string][]byte) error {
+	args := m.Called(namespace, name, data)
+
+	return args.Error(0)
+}
+
+func (m *MockKubernetes) DeleteConfigMap(namespace, name string) error {
+	args := m.Called(namespace, name)
+
+	return args.Error(0)
+}
+
+func (m *MockKubernetes) WaitForCRDs(names []string, timeout time.Duration) error {
+	args := m.Called(names, timeout)
+
+	return args.Error(0)
+}
+
+func (m *MockKubernetes) WaitForJobs(selectors []string, timeout time.Duration) error {
+	args := m.Called(selectors, timeout)
+
+	return args.Error(0)
+}
+
 func (m *MockKubernetes) HealthCheckPods(selectors []string, timeout time.Duration) error {
 	args := m.Called(selectors, timeout)
 
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/docker/docker/api/types"
+	"github.com/shipyard-run/shipyard/pkg/utils"
 )
 
 const (
@@ -58,13 +59,19 @@ func (b *SystemImpl) Preflight() (string, error) {
 	output := ""
 
 	// check docker
+	engine := "Docker"
+	engineError := "* Unable to connect to Docker, ensure Docker is installed and running.\n"
+	if utils.GetContainerEngine() == utils.EnginePodman {
+		engine = "Podman"
+		engineError = fmt.Sprintf("* Unable to connect to Podman at %s, ensure the podman.socket service is running.\n", utils.GetPodmanSock())
+	}
 
 	if checkDocker() != nil {
-		output += fmt.Sprintf(" [ %s ] Docker\n", fmt.Sprintf(Red, " ERROR "))
-		errors += "* Unable to connect to Docker, ensure Docker is installed and running.\n"
+		output += fmt.Sprintf(" [ %s ] %s\n", fmt.Sprintf(Red, " ERROR "), engine)
+		errors += engineError
 		dockerPass = false
 	} else {
-		output += fmt.Sprintf(" [ %s ] Docker\n", fmt.Sprintf(Green, "  OK   "))
+		output += fmt.Sprintf(" [ %s ] %s\n", fmt.Sprintf(Green, "  OK   "), engine)
 	}
 
 	if checkGit() != nil {
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/shipyard-run/shipyard/pkg/utils"
+)
+
+// TypeAWS is the resource string for a AWS resource
+const TypeAWS ResourceType = "aws"
+
+// AWS runs LocalStack to simulate AWS services such as S3, SQS and DynamoDB.
+// The credentials and endpoint for the services can be referenced by other
+// resources e.g. aws.local.env.AWS_ENDPOINT_URL
+type AWS struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	Networks []NetworkAttachment `hcl:"network,block" json:"networks,omitempty"` // networks to attach LocalStack to
+
+	// Services to start e.g. ["s3", "sqs"], all services are started when
+	// not set
+	Services []string `hcl:"services,optional" json:"services,omitempty"`
+
+	// Version of LocalStack to run, defaults to 0.12.5
+	Version string `hcl:"version,optional" json:"version,omitempty"`
+
+	// Port is the port on the local machine the services are exposed on
+	Port int `hcl:"port,optional" json:"port"`
+
+	// Region is the AWS region reported by the services, defaults to us-east-1
+	Region string `hcl:"region,optional" json:"region,omitempty"`
+
+	// Bootstrap is a script which is run in the LocalStack container once the
+	// services have started, the awslocal command can be used to create
+	// resources e.g. awslocal s3 mb s3://bucket
+	Bootstrap string `hcl:"bootstrap,optional" json:"bootstrap,omitempty"`
+
+	// Address is the endpoint for the services on the local machine
+	// e.g. http://localhost:4566, InternalAddress is the endpoint used by
+	// resources attached to the same network
+	Address         string `json:"address"`
+	InternalAddress string `json:"internal_address" mapstructure:"internal_address"`
+
+	// Env contains the environment variables used by the AWS SDKs and CLI to
+	// connect to the services from resources attached to the same network
+	Env map[string]string `json:"env"`
+}
+
+// NewAWS creates a new AWS config resource
+func NewAWS(name string) *AWS {
+	return &AWS{
+		ResourceInfo: ResourceInfo{Name: name, Type: TypeAWS, Status: PendingCreation},
+		Version:      "0.12.5",
+		Port:         4566,
+		Region:       "us-east-1",
+	}
+}
+
+// setAddress sets the addresses and environment variables for the services
+func (a *AWS) setAddress() {
+	a.Address = fmt.Sprintf("http://localhost:%d", a.Port)
+	a.InternalAddress = fmt.Sprintf("http://%s:4566", utils.FQDN(a.Name, string(a.Type)))
+
+	// LocalStack accepts any credentials
+	a.Env = map[string]string{
+		"AWS_ACCESS_KEY_ID":     "test",
+		"AWS_SECRET_ACCESS_KEY": "test",
+		"AWS_DEFAULT_REGION":    a.Region,
+		"AWS_ENDPOINT_URL":      a.InternalAddress,
+	}
+}
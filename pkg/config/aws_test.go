@@ -0,0 +1,64 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAWSCreatesCorrectly(t *testing.T) {
+	c, dir, cleanup := setupTestConfig(t, awsValid)
+	defer cleanup()
+
+	r, err := c.FindResource("aws.local")
+	require.NoError(t, err)
+
+	a := r.(*AWS)
+	assert.Equal(t, []string{"s3", "sqs"}, a.Services)
+	assert.Equal(t, "eu-west-1", a.Region)
+	assert.Equal(t, "http://localhost:4566", a.Address)
+	assert.Equal(t, "http://local.aws.shipyard.run:4566", a.InternalAddress)
+	assert.Equal(t, filepath.Join(dir, "scripts/init.sh"), a.Bootstrap)
+	assert.Equal(t, "eu-west-1", a.Env["AWS_DEFAULT_REGION"])
+	assert.Contains(t, a.DependsOn, "network.local")
+}
+
+func TestAWSEnvCanBeReferenced(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, awsValid)
+	defer cleanup()
+
+	r, err := c.FindResource("container.app")
+	require.NoError(t, err)
+
+	assert.Contains(t, r.(*Container).Environment, KV{Key: "AWS_ENDPOINT_URL", Value: "http://local.aws.shipyard.run:4566"})
+	assert.Contains(t, r.Info().DependsOn, "aws.local")
+}
+
+const awsValid = `
+network "local" {
+	subnet = "10.6.0.0/16"
+}
+
+aws "local" {
+	services  = ["s3", "sqs"]
+	region    = "eu-west-1"
+	bootstrap = "./scripts/init.sh"
+
+	network {
+		name = "network.local"
+	}
+}
+
+container "app" {
+	image {
+		name = "app:latest"
+	}
+
+	env {
+		key   = "AWS_ENDPOINT_URL"
+		value = aws.local.env.AWS_ENDPOINT_URL
+	}
+}
+`
@@ -3,8 +3,27 @@ package config
 import (
 	"fmt"
 	"net/url"
+	"strings"
+
+	"github.com/hashicorp/go-version"
 )
 
+// Version is the version of the Shipyard binary, blueprints which set
+// shipyard_version are checked against this version when they are parsed.
+// Development builds which do not have a semantic version are not checked.
+var Version = "dev"
+
+// VersionError is returned when the version of Shipyard does not match
+// the version constraint of a blueprint
+type VersionError struct {
+	Constraint string
+	Version    string
+}
+
+func (v VersionError) Error() string {
+	return fmt.Sprintf("This blueprint requires Shipyard version %s, the current version is %s. Please upgrade Shipyard using the upgrade command", v.Constraint, v.Version)
+}
+
 // Blueprint defines a stack blueprint for defining yard configs
 type Blueprint struct {
 	Title          string   `hcl:"title,optional" json:"title,omitempty"`
@@ -13,6 +32,67 @@ type Blueprint struct {
 	Intro          string   `hcl:"intro,optional" json:"intro,omitempty"`
 	BrowserWindows []string `hcl:"browser_windows,optional" json:"browser_windows,omitempty" mapstructure:"browser_windows"`
 	Environment    []KV     `hcl:"env,block" json:"environment,omitempty"`
+
+	// ShipyardVersion is a version constraint for the Shipyard binary
+	// which can run the blueprint e.g. ">= 0.1.0"
+	ShipyardVersion string `hcl:"shipyard_version,optional" json:"shipyard_version,omitempty" mapstructure:"shipyard_version"`
+
+	// Sources are remote blueprints which are fetched and parsed with
+	// the config in the blueprint folder
+	Sources []BlueprintSource `hcl:"source,block" json:"sources,omitempty"`
+}
+
+// CheckVersion returns an error when the given version of Shipyard does not
+// satisfy the blueprint's version constraint
+func (b *Blueprint) CheckVersion(v string) error {
+	if b.ShipyardVersion == "" {
+		return nil
+	}
+
+	c, err := version.NewConstraint(b.ShipyardVersion)
+	if err != nil {
+		return fmt.Errorf("Invalid shipyard_version constraint %q: %s", b.ShipyardVersion, err)
+	}
+
+	sv, err := version.NewVersion(v)
+	if err != nil {
+		// development builds can run any blueprint
+		return nil
+	}
+
+	if !c.Check(sv) {
+		return VersionError{Constraint: b.ShipyardVersion, Version: v}
+	}
+
+	return nil
+}
+
+// BlueprintSource is a remote blueprint, the URL can be any location
+// supported by go-getter such as a git repository or a HTTP tarball, or
+// an OCI artifact in a container registry e.g. oci://ghcr.io/org/blueprint
+type BlueprintSource struct {
+	Name string `hcl:"name,label" json:"name"`
+	URL  string `hcl:"url" json:"url"`
+
+	// Ref is the git reference or the OCI tag to fetch
+	Ref string `hcl:"ref,optional" json:"ref,omitempty"`
+}
+
+// Address returns the go-getter address for the source including the ref
+func (s BlueprintSource) Address() string {
+	if s.Ref == "" {
+		return s.URL
+	}
+
+	if strings.HasPrefix(s.URL, "oci://") {
+		return fmt.Sprintf("%s:%s", s.URL, s.Ref)
+	}
+
+	if strings.Contains(s.URL, "?") {
+		return fmt.Sprintf("%s&ref=%s", s.URL, url.QueryEscape(s.Ref))
+	}
+
+	return fmt.Sprintf("%s?ref=%s", s.URL, url.QueryEscape(s.Ref))
 }
 
 // Validate the Blueprint and return errors
@@ -1,9 +1,15 @@
 package config
 
 import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/shipyard-run/shipyard/pkg/utils"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
 )
 
 func setupBlueprints(t *testing.T, contents string) (*Config, func()) {
@@ -11,7 +17,7 @@ func setupBlueprints(t *testing.T, contents string) (*Config, func()) {
 	createNamedFile(t, dir, "*.yard", contents)
 
 	c := &Config{}
-	err := ParseFolder(dir, c)
+	err := ParseFolder(dir, c, nil)
 	assert.NoError(t, err)
 
 	return c, cleanup
@@ -72,3 +78,252 @@ browser_windows = [
 	"https://www.something.com",
 ]
 `
+
+func TestBlueprintSourceAddressAddsRef(t *testing.T) {
+	s := BlueprintSource{URL: "github.com/org/blueprint//subdir", Ref: "v1.2"}
+	assert.Equal(t, "github.com/org/blueprint//subdir?ref=v1.2", s.Address())
+
+	s = BlueprintSource{URL: "git::https://example.com/bp.git?depth=1", Ref: "main"}
+	assert.Equal(t, "git::https://example.com/bp.git?depth=1&ref=main", s.Address())
+
+	s = BlueprintSource{URL: "oci://ghcr.io/org/blueprint", Ref: "v1.2"}
+	assert.Equal(t, "oci://ghcr.io/org/blueprint:v1.2", s.Address())
+
+	s = BlueprintSource{URL: "https://example.com/bp.tar.gz"}
+	assert.Equal(t, "https://example.com/bp.tar.gz", s.Address())
+}
+
+func TestBlueprintSourcesAreFetchedAndParsed(t *testing.T) {
+	home := createTempDirectory(t)
+	defer removeTestFiles(t, home)
+
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", home)
+	defer os.Setenv("HOME", oldHome)
+
+	// the remote source is a local folder which is fetched by go-getter
+	src := createTempDirectory(t)
+	defer removeTestFiles(t, src)
+	createNamedFile(t, src, "*.hcl", blueprintSourceConfig)
+	createNamedFile(t, src, "*.yard", `title = "remote"`)
+
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+	createNamedFile(t, dir, "*.yard", fmt.Sprintf(blueprintWithSource, src))
+
+	c := New()
+	err := ParseFolder(dir, c, nil)
+	assert.NoError(t, err)
+
+	_, err = c.FindResource("network.remote")
+	assert.NoError(t, err)
+
+	// the blueprint for the folder is not replaced by the source
+	assert.Equal(t, "with source", c.Blueprint.Title)
+	assert.Len(t, c.Blueprint.Sources, 1)
+
+	// the source is cached in the shipyard home folder
+	_, err = os.Stat(utils.GetBlueprintLocalFolder(src))
+	assert.NoError(t, err)
+}
+
+func TestBlueprintSourcesAreFetchedAgainWithForceUpdate(t *testing.T) {
+	home := createTempDirectory(t)
+	defer removeTestFiles(t, home)
+
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", home)
+	defer os.Setenv("HOME", oldHome)
+
+	src := createTempDirectory(t)
+	defer removeTestFiles(t, src)
+	createNamedFile(t, src, "*.hcl", blueprintSourceConfig)
+	createNamedFile(t, src, "*.yard", `title = "remote"`)
+
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+	createNamedFile(t, dir, "*.yard", fmt.Sprintf(blueprintWithSource, src))
+
+	err := ParseFolder(dir, New(), nil)
+	assert.NoError(t, err)
+
+	// replace the cached source with stale files, local sources are
+	// symlinked by go-getter so the cache is replaced with a folder
+	cache := utils.GetBlueprintLocalFolder(src)
+	os.RemoveAll(cache)
+	os.MkdirAll(cache, os.ModePerm)
+	err = ioutil.WriteFile(filepath.Join(cache, "stale.hcl"), []byte(`network "stale" { subnet = "10.9.0.0/16" }`), os.ModePerm)
+	assert.NoError(t, err)
+
+	c := New()
+	err = ParseFolder(dir, c, nil)
+	assert.NoError(t, err)
+
+	_, err = c.FindResource("network.stale")
+	assert.NoError(t, err)
+
+	c = New()
+	err = ParseFolderWithOptions(dir, c, nil, ParseOptions{ForceUpdate: true})
+	assert.NoError(t, err)
+
+	_, err = c.FindResource("network.stale")
+	assert.Error(t, err)
+
+	_, err = c.FindResource("network.remote")
+	assert.NoError(t, err)
+}
+
+func TestBlueprintSourceWhichCanNotBeFetchedReturnsError(t *testing.T) {
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+	createNamedFile(t, dir, "*.yard", fmt.Sprintf(blueprintWithSource, "/does/not/exist"))
+
+	c := New()
+	err := ParseFolder(dir, c, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Unable to fetch source remote")
+}
+
+var blueprintWithSource = `
+title = "with source"
+
+source "remote" {
+  url = "%s"
+}
+`
+
+var blueprintSourceConfig = `
+network "remote" {
+  subnet = "10.9.0.0/16"
+}
+`
+
+func TestBlueprintCheckVersion(t *testing.T) {
+	bp := &Blueprint{ShipyardVersion: ">= 0.1.0, < 0.2.0"}
+
+	assert.NoError(t, bp.CheckVersion("0.1.5"))
+	assert.IsType(t, VersionError{}, bp.CheckVersion("0.2.0"))
+	assert.IsType(t, VersionError{}, bp.CheckVersion("0.0.31"))
+
+	// development builds are not checked
+	assert.NoError(t, bp.CheckVersion("dev"))
+
+	assert.NoError(t, (&Blueprint{}).CheckVersion("0.0.1"))
+}
+
+func TestBlueprintCheckVersionWithInvalidConstraintReturnsError(t *testing.T) {
+	bp := &Blueprint{ShipyardVersion: "newer please"}
+
+	err := bp.CheckVersion("0.1.0")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid shipyard_version")
+}
+
+func TestParseFolderWithUnsupportedVersionFailsBeforeDecoding(t *testing.T) {
+	Version = "0.1.0"
+	defer func() { Version = "dev" }()
+
+	dir, cleanup := createTestFiles(t, blueprintNewerResource)
+	defer cleanup()
+	createNamedFile(t, dir, "*.yard", `shipyard_version = ">= 9.0.0"`)
+
+	c := New()
+	err := ParseFolder(dir, c, nil)
+	assert.Error(t, err)
+
+	ve := VersionError{}
+	assert.True(t, xerrors.As(err, &ve))
+	assert.Equal(t, ">= 9.0.0", ve.Constraint)
+	assert.Equal(t, "0.1.0", ve.Version)
+}
+
+var blueprintNewerResource = `
+container "consul" {
+  image {
+    name = "consul"
+  }
+
+  feature_from_the_future = true
+}
+`
+
+func TestParseFolderWithUnsupportedVersionFailsBeforeReadingFiles(t *testing.T) {
+	Version = "0.1.0"
+	defer func() { Version = "dev" }()
+
+	dir, cleanup := createTestFiles(t, blueprintNewerBlock)
+	defer cleanup()
+	createNamedFile(t, dir, "*.yard", `
+shipyard_version = ">= 9.0.0"
+title = var.future_title
+feature_from_the_future = true
+`)
+
+	c := New()
+	err := ParseFolder(dir, c, nil)
+	assert.Error(t, err)
+
+	ve := VersionError{}
+	assert.True(t, xerrors.As(err, &ve))
+	assert.Equal(t, ">= 9.0.0", ve.Constraint)
+}
+
+var blueprintNewerBlock = `
+block_from_the_future "consul" {
+  name = "consul"
+}
+`
+
+func TestParseFolderWithMultipleYardFilesReturnsError(t *testing.T) {
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+
+	a := createNamedFile(t, dir, "a*.yard", `title = "a"`)
+	b := createNamedFile(t, dir, "b*.yard", `title = "b"`)
+
+	c := New()
+	err := ParseFolder(dir, c, nil)
+	assert.Error(t, err)
+
+	me := MultipleBlueprintsError{}
+	assert.True(t, xerrors.As(err, &me))
+	assert.Equal(t, []string{a, b}, me.Files)
+}
+
+func TestParseFolderYardFileTakesPrecedenceOverMarkdown(t *testing.T) {
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+
+	createNamedFile(t, dir, "a*.md", "---\ntitle: markdown\n---\n# Blueprint")
+	createNamedFile(t, dir, "b*.yard", `title = "yard"`)
+
+	c := New()
+	err := ParseFolder(dir, c, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "yard", c.Blueprint.Title)
+}
+
+func TestParseFolderIgnoresMarkdownWithoutFrontmatter(t *testing.T) {
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+
+	createNamedFile(t, dir, "a*.md", "# Notes")
+	createNamedFile(t, dir, "b*.md", "---\ntitle: markdown\n---\n# Blueprint")
+
+	c := New()
+	err := ParseFolder(dir, c, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "markdown", c.Blueprint.Title)
+}
+
+func TestParseFolderWithMultipleMarkdownBlueprintsReturnsError(t *testing.T) {
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+
+	createNamedFile(t, dir, "a*.md", "---\ntitle: a\n---\n")
+	createNamedFile(t, dir, "b*.md", "---\ntitle: b\n---\n")
+
+	c := New()
+	err := ParseFolder(dir, c, nil)
+	assert.True(t, xerrors.As(err, &MultipleBlueprintsError{}))
+}
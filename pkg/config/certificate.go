@@ -0,0 +1,79 @@
+package config
+
+import (
+	"path/filepath"
+)
+
+// TypeCertificateCA is the resource string for a CertificateCA resource
+const TypeCertificateCA ResourceType = "certificate_ca"
+
+// TypeCertificateLeaf is the resource string for a CertificateLeaf resource
+const TypeCertificateLeaf ResourceType = "certificate_leaf"
+
+// CertificateCA generates a self signed certificate authority, the
+// certificate and key are written to the output folder as <name>.cert and
+// <name>.key
+type CertificateCA struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	// Output is the folder the certificate and key are written to, defaults
+	// to the certs folder in the blueprint data directory
+	Output string `hcl:"output,optional" json:"output"`
+
+	// Cert and Key are the paths of the generated files, they can be
+	// referenced by other resources e.g. certificate_ca.root.cert
+	Cert string `json:"cert"`
+	Key  string `json:"key"`
+}
+
+// NewCertificateCA creates a new CertificateCA config resource
+func NewCertificateCA(name string) *CertificateCA {
+	return &CertificateCA{ResourceInfo: ResourceInfo{Name: name, Type: TypeCertificateCA, Status: PendingCreation}}
+}
+
+// CertificateLeaf generates a certificate signed by a certificate authority
+// which is valid for the given DNS names and IP addresses, the certificate
+// and key are written to the output folder as <name>.cert and <name>.key
+type CertificateLeaf struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	// CACert and CAKey are the paths to the certificate authority used to
+	// sign the certificate e.g. certificate_ca.root.cert
+	CACert string `hcl:"ca_cert" json:"ca_cert" mapstructure:"ca_cert"`
+	CAKey  string `hcl:"ca_key" json:"ca_key" mapstructure:"ca_key"`
+
+	DNSNames    []string `hcl:"dns_names,optional" json:"dns_names,omitempty" mapstructure:"dns_names"`
+	IPAddresses []string `hcl:"ip_addresses,optional" json:"ip_addresses,omitempty" mapstructure:"ip_addresses"`
+
+	// Output is the folder the certificate and key are written to, defaults
+	// to the certs folder in the blueprint data directory
+	Output string `hcl:"output,optional" json:"output"`
+
+	// Cert and Key are the paths of the generated files
+	Cert string `json:"cert"`
+	Key  string `json:"key"`
+}
+
+// NewCertificateLeaf creates a new CertificateLeaf config resource
+func NewCertificateLeaf(name string) *CertificateLeaf {
+	return &CertificateLeaf{ResourceInfo: ResourceInfo{Name: name, Type: TypeCertificateLeaf, Status: PendingCreation}}
+}
+
+// certificateOutput returns the folder certificates defined in file are
+// written to when the output attribute is not set
+func (p *parser) certificateOutput(output, file string) string {
+	if output != "" {
+		return ensureAbsolute(output, file)
+	}
+
+	folder := p.folder
+	if folder == "" {
+		folder = filepath.Dir(file)
+	}
+
+	return filepath.Join(blueprintDataDir(folder), "certs")
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCertificateCreatesCorrectly(t *testing.T) {
+	c, dir, cleanup := setupTestConfig(t, certificateValid)
+	defer cleanup()
+
+	ca, err := c.FindResource("certificate_ca.root")
+	assert.NoError(t, err)
+
+	out := filepath.Join(blueprintDataDir(dir), "certs")
+	assert.Equal(t, out, ca.(*CertificateCA).Output)
+	assert.Equal(t, filepath.Join(out, "root.cert"), ca.(*CertificateCA).Cert)
+	assert.Equal(t, filepath.Join(out, "root.key"), ca.(*CertificateCA).Key)
+
+	cl, err := c.FindResource("certificate_leaf.consul")
+	assert.NoError(t, err)
+
+	assert.Equal(t, ca.(*CertificateCA).Cert, cl.(*CertificateLeaf).CACert)
+	assert.Equal(t, ca.(*CertificateCA).Key, cl.(*CertificateLeaf).CAKey)
+	assert.Equal(t, filepath.Join(dir, "certs", "consul.cert"), cl.(*CertificateLeaf).Cert)
+	assert.Contains(t, cl.Info().DependsOn, "certificate_ca.root")
+}
+
+const certificateValid = `
+certificate_ca "root" {}
+
+certificate_leaf "consul" {
+	ca_cert = certificate_ca.root.cert
+	ca_key  = certificate_ca.root.key
+
+	dns_names    = ["consul.container.shipyard.run"]
+	ip_addresses = ["127.0.0.1"]
+
+	output = "./certs"
+}
+`
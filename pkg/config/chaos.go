@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// TypeChaos is the resource string for a Chaos resource
+const TypeChaos ResourceType = "chaos"
+
+// Chaos injects network faults into containers, latency, packet loss and
+// bandwidth limits are applied to all the interfaces of the targets. Faults
+// can be enabled and disabled with the chaos command.
+type Chaos struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	// Targets are containers or networks, faults are applied to all the
+	// containers attached to a network
+	Targets []string `hcl:"targets" json:"targets"`
+
+	// Latency is the delay added to outgoing packets e.g. 200ms
+	Latency string `hcl:"latency,optional" json:"latency,omitempty"`
+	// Jitter is the random variation of the latency e.g. 20ms
+	Jitter string `hcl:"jitter,optional" json:"jitter,omitempty"`
+
+	// Loss is the percentage of outgoing packets which are dropped
+	Loss float64 `hcl:"loss,optional" json:"loss,omitempty"`
+
+	// Bandwidth limits the rate of outgoing traffic e.g. 1mbit
+	Bandwidth string `hcl:"bandwidth,optional" json:"bandwidth,omitempty"`
+
+	// Enabled applies the faults when the resource is created, defaults to
+	// true
+	Enabled bool `hcl:"enabled,optional" json:"enabled"`
+}
+
+// NewChaos creates a new Chaos config resource
+func NewChaos(name string) *Chaos {
+	return &Chaos{ResourceInfo: ResourceInfo{Name: name, Type: TypeChaos, Status: PendingCreation}, Enabled: true}
+}
+
+// Validate the Chaos resource and return errors
+func (c *Chaos) Validate() []error {
+	errs := []error{}
+
+	if c.Latency == "" && c.Loss == 0 && c.Bandwidth == "" {
+		errs = append(errs, fmt.Errorf("at least one of latency, loss or bandwidth must be set"))
+	}
+
+	if c.Latency != "" {
+		if _, err := time.ParseDuration(c.Latency); err != nil {
+			errs = append(errs, fmt.Errorf("invalid latency %s, latency must be a duration e.g. 200ms", c.Latency))
+		}
+	}
+
+	if c.Jitter != "" {
+		if c.Latency == "" {
+			errs = append(errs, fmt.Errorf("jitter can only be set when latency is set"))
+		} else if _, err := time.ParseDuration(c.Jitter); err != nil {
+			errs = append(errs, fmt.Errorf("invalid jitter %s, jitter must be a duration e.g. 20ms", c.Jitter))
+		}
+	}
+
+	if c.Loss < 0 || c.Loss > 100 {
+		errs = append(errs, fmt.Errorf("invalid loss %v, loss must be a percentage between 0 and 100", c.Loss))
+	}
+
+	return errs
+}
+
+// containersOnNetworks returns the containers attached to any of the given
+// networks, faults for a network target are applied to these containers
+func (c *Config) containersOnNetworks(networks []string) []string {
+	containers := []string{}
+	targets := map[string]bool{}
+	for _, n := range networks {
+		targets[n] = true
+	}
+
+	for _, r := range c.Resources {
+		co, ok := r.(*Container)
+		if !ok {
+			continue
+		}
+
+		for _, n := range co.Networks {
+			if targets[n.Name] {
+				containers = append(containers, ResourceID(co))
+				break
+			}
+		}
+	}
+
+	return containers
+}
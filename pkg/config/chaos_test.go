@@ -0,0 +1,58 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChaosCreatesCorrectly(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, chaosValid)
+	defer cleanup()
+
+	r, err := c.FindResource("chaos.slow")
+	require.NoError(t, err)
+
+	ch := r.(*Chaos)
+	assert.Equal(t, "200ms", ch.Latency)
+	assert.Equal(t, 2.5, ch.Loss)
+	assert.True(t, ch.Enabled)
+	assert.Contains(t, ch.DependsOn, "network.local")
+	assert.Contains(t, ch.DependsOn, "container.db")
+}
+
+func TestChaosValidate(t *testing.T) {
+	ch := NewChaos("slow")
+	ch.Latency = "200ms"
+	assert.Len(t, ch.Validate(), 0)
+
+	ch.Latency = ""
+	assert.Len(t, ch.Validate(), 1)
+
+	ch.Jitter = "10ms"
+	ch.Loss = 101
+	assert.Len(t, ch.Validate(), 2)
+}
+
+const chaosValid = `
+network "local" {
+	subnet = "10.6.0.0/16"
+}
+
+container "db" {
+	image {
+		name = "postgres:14"
+	}
+
+	network {
+		name = "network.local"
+	}
+}
+
+chaos "slow" {
+	targets = ["network.local"]
+	latency = "200ms"
+	loss    = 2.5
+}
+`
@@ -0,0 +1,385 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"sigs.k8s.io/yaml"
+)
+
+// TypeCompose is the resource string for a Compose resource
+const TypeCompose ResourceType = "compose"
+
+// Compose imports the services, networks and volumes defined in a
+// docker-compose file, services are added to the config as containers and
+// networks which define a subnet are added as networks
+type Compose struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	// File is the path to the docker-compose file
+	File string `hcl:"file" json:"file"`
+
+	// Network is attached to services which do not define any networks
+	// e.g. network.local
+	Network string `hcl:"network,optional" json:"network,omitempty"`
+}
+
+// NewCompose creates a new Compose config resource
+func NewCompose(name string) *Compose {
+	return &Compose{ResourceInfo: ResourceInfo{Name: name, Type: TypeCompose, Status: PendingCreation}}
+}
+
+// composeFile is the subset of the docker-compose file format which can be
+// converted to resources
+type composeFile struct {
+	Services map[string]composeService  `json:"services"`
+	Networks map[string]*composeNetwork `json:"networks"`
+}
+
+type composeService struct {
+	Image       string        `json:"image"`
+	Build       interface{}   `json:"build"`
+	Entrypoint  interface{}   `json:"entrypoint"`
+	Command     interface{}   `json:"command"`
+	Environment interface{}   `json:"environment"`
+	Ports       []interface{} `json:"ports"`
+	Volumes     []interface{} `json:"volumes"`
+	Networks    interface{}   `json:"networks"`
+	DependsOn   interface{}   `json:"depends_on"`
+	Privileged  bool          `json:"privileged"`
+}
+
+type composeNetwork struct {
+	External bool `json:"external"`
+	IPAM     struct {
+		Config []struct {
+			Subnet string `json:"subnet"`
+		} `json:"config"`
+	} `json:"ipam"`
+}
+
+// composeResources reads the compose file and returns the resources for
+// the services and networks it defines, services which are built from a
+// Dockerfile also return a container_build resource
+func composeResources(cm *Compose) ([]Resource, error) {
+	d, err := ioutil.ReadFile(cm.File)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read compose file %s: %s", cm.File, err)
+	}
+
+	cf := composeFile{}
+	err = yaml.Unmarshal(d, &cf)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to parse compose file %s: %s", cm.File, err)
+	}
+
+	resources := []Resource{}
+
+	for _, n := range sortedKeys(cf.Networks) {
+		cn := cf.Networks[n]
+
+		// external networks must be defined in the blueprint
+		if cn != nil && cn.External {
+			continue
+		}
+
+		if cn == nil || len(cn.IPAM.Config) == 0 || cn.IPAM.Config[0].Subnet == "" {
+			return nil, fmt.Errorf("Unable to import network %s from compose file %s, networks must define a subnet using ipam config", n, cm.File)
+		}
+
+		nw := NewNetwork(n)
+		nw.Subnet = cn.IPAM.Config[0].Subnet
+		resources = append(resources, nw)
+	}
+
+	for _, n := range sortedKeys(cf.Services) {
+		r, err := composeServiceResources(cm, n, cf.Services[n])
+		if err != nil {
+			return nil, fmt.Errorf("Unable to import service %s from compose file %s: %s", n, cm.File, err)
+		}
+
+		resources = append(resources, r...)
+	}
+
+	return resources, nil
+}
+
+// composeServiceResources converts a service to a container
+func composeServiceResources(cm *Compose, name string, s composeService) ([]Resource, error) {
+	resources := []Resource{}
+	dir := filepath.Dir(cm.File)
+
+	co := NewContainer(name)
+	co.Image.Name = s.Image
+	co.Privileged = s.Privileged
+	co.Entrypoint = composeCommand(s.Entrypoint)
+	co.Command = composeCommand(s.Command)
+	co.Depends = append(co.Depends, cm.Depends...)
+
+	if s.Build != nil {
+		cb := NewContainerBuild(name)
+
+		switch b := s.Build.(type) {
+		case string:
+			cb.Context = b
+		case map[string]interface{}:
+			cb.Context, _ = b["context"].(string)
+			if df, ok := b["dockerfile"].(string); ok {
+				cb.Dockerfile = df
+			}
+		}
+
+		if cb.Context == "" {
+			return nil, fmt.Errorf("build must define a context")
+		}
+
+		cb.Context = ensureAbsolute(cb.Context, cm.File)
+		cb.Depends = append(cb.Depends, cm.Depends...)
+
+		// compose tags the built image with the image name when set
+		if s.Image != "" {
+			cb.Tag = s.Image
+		}
+
+		co.Image.Name = cb.Tag
+		co.Depends = append(co.Depends, fmt.Sprintf("%s.%s", TypeContainerBuild, name))
+
+		resources = append(resources, cb)
+	}
+
+	if co.Image.Name == "" {
+		return nil, fmt.Errorf("services must define an image or build")
+	}
+
+	env, err := composeEnvironment(s.Environment)
+	if err != nil {
+		return nil, err
+	}
+	co.Environment = env
+
+	for _, p := range s.Ports {
+		port, err := composePort(p)
+		if err != nil {
+			return nil, err
+		}
+
+		co.Ports = append(co.Ports, port)
+	}
+
+	for _, v := range s.Volumes {
+		vol, ok, err := composeVolume(v, dir)
+		if err != nil {
+			return nil, err
+		}
+
+		// anonymous volumes are not supported
+		if ok {
+			co.Volumes = append(co.Volumes, vol)
+		}
+	}
+
+	for _, n := range composeNames(s.Networks) {
+		co.Networks = append(co.Networks, NetworkAttachment{Name: fmt.Sprintf("%s.%s", TypeNetwork, n)})
+	}
+
+	if len(co.Networks) == 0 && cm.Network != "" {
+		co.Networks = append(co.Networks, NetworkAttachment{Name: cm.Network})
+	}
+
+	for _, d := range composeNames(s.DependsOn) {
+		co.Depends = append(co.Depends, fmt.Sprintf("%s.%s", TypeContainer, d))
+	}
+
+	return append(resources, co), nil
+}
+
+// composeCommand converts a command which can be a string or a list
+func composeCommand(v interface{}) []string {
+	switch c := v.(type) {
+	case string:
+		return strings.Fields(c)
+	case []interface{}:
+		out := []string{}
+		for _, i := range c {
+			out = append(out, fmt.Sprintf("%v", i))
+		}
+
+		return out
+	}
+
+	return nil
+}
+
+// composeEnvironment converts environment variables defined as a map or a
+// list of KEY=value strings, variables without a value are read from the
+// environment
+func composeEnvironment(v interface{}) ([]KV, error) {
+	env := []KV{}
+
+	switch e := v.(type) {
+	case nil:
+	case map[string]interface{}:
+		for _, k := range sortedKeys(e) {
+			val := ""
+			if e[k] != nil {
+				val = fmt.Sprintf("%v", e[k])
+			}
+
+			env = append(env, KV{Key: k, Value: val})
+		}
+	case []interface{}:
+		for _, i := range e {
+			parts := strings.SplitN(fmt.Sprintf("%v", i), "=", 2)
+			if len(parts) == 1 {
+				parts = append(parts, os.Getenv(parts[0]))
+			}
+
+			env = append(env, KV{Key: parts[0], Value: parts[1]})
+		}
+	default:
+		return nil, fmt.Errorf("environment must be a map or a list")
+	}
+
+	return env, nil
+}
+
+// composePort converts a port defined using the short syntax
+// [ip:][host:]container[/protocol] or the long syntax
+func composePort(v interface{}) (Port, error) {
+	p := Port{}
+
+	switch pv := v.(type) {
+	case float64:
+		p.Local = fmt.Sprintf("%d", int(pv))
+	case string:
+		spec := pv
+		if i := strings.Index(spec, "/"); i > 0 {
+			p.Protocol = spec[i+1:]
+			spec = spec[:i]
+		}
+
+		parts := strings.Split(spec, ":")
+		p.Local = parts[len(parts)-1]
+		if len(parts) > 1 {
+			p.Host = parts[len(parts)-2]
+		}
+	case map[string]interface{}:
+		if t, ok := pv["target"]; ok {
+			p.Local = fmt.Sprintf("%v", t)
+		}
+
+		if h, ok := pv["published"]; ok {
+			p.Host = fmt.Sprintf("%v", h)
+		}
+
+		if pr, ok := pv["protocol"].(string); ok {
+			p.Protocol = pr
+		}
+	}
+
+	if p.Local == "" || strings.Contains(p.Local, "-") || strings.Contains(p.Host, "-") {
+		return p, fmt.Errorf("invalid port %v, port ranges are not supported", v)
+	}
+
+	p.Remote = p.Local
+
+	return p, nil
+}
+
+// composeVolume converts a volume defined using the short syntax
+// source:destination[:mode] or the long syntax, bind mounts are relative to
+// the folder containing the compose file. Returns false for anonymous
+// volumes which only define a destination.
+func composeVolume(v interface{}, dir string) (Volume, bool, error) {
+	vol := Volume{}
+
+	switch vv := v.(type) {
+	case string:
+		parts := strings.Split(vv, ":")
+		if len(parts) == 1 {
+			return vol, false, nil
+		}
+
+		vol.Source = parts[0]
+		vol.Destination = parts[1]
+	case map[string]interface{}:
+		vol.Source, _ = vv["source"].(string)
+		vol.Destination, _ = vv["target"].(string)
+		vol.Type, _ = vv["type"].(string)
+	default:
+		return vol, false, fmt.Errorf("invalid volume %v", v)
+	}
+
+	if vol.Destination == "" {
+		return vol, false, fmt.Errorf("invalid volume %v, volumes must define a target", v)
+	}
+
+	if vol.Source == "" {
+		return vol, false, nil
+	}
+
+	// paths are bind mounts, other sources are named volumes
+	switch {
+	case strings.HasPrefix(vol.Source, "~"):
+		vol.Source = filepath.Join(utils.HomeFolder(), vol.Source[1:])
+	case strings.HasPrefix(vol.Source, "."):
+		vol.Source = filepath.Join(dir, vol.Source)
+	case !filepath.IsAbs(vol.Source) && vol.Type == "":
+		vol.Type = "volume"
+	}
+
+	return vol, true, nil
+}
+
+// composeNames returns the names from a list or the keys of a map, used
+// for the networks and depends_on attributes
+func composeNames(v interface{}) []string {
+	switch n := v.(type) {
+	case []interface{}:
+		out := []string{}
+		for _, i := range n {
+			out = append(out, fmt.Sprintf("%v", i))
+		}
+
+		return out
+	case map[string]interface{}:
+		return sortedKeys(n)
+	}
+
+	return nil
+}
+
+// sortedKeys returns the keys of a map with string keys in order
+func sortedKeys(m interface{}) []string {
+	keys := []string{}
+
+	switch mv := m.(type) {
+	case map[string]interface{}:
+		for k := range mv {
+			keys = append(keys, k)
+		}
+	case map[string]composeService:
+		for k := range mv {
+			keys = append(keys, k)
+		}
+	case map[string]*composeNetwork:
+		for k := range mv {
+			keys = append(keys, k)
+		}
+	case map[string]string:
+		for k := range mv {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
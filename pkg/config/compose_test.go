@@ -0,0 +1,114 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComposeCreatesResources(t *testing.T) {
+	c, err := setupFunctionConfig(t, map[string]string{"app/docker-compose.yml": composeFileValid}, composeValid)
+	require.NoError(t, err)
+
+	n, err := c.FindResource("network.backend")
+	require.NoError(t, err)
+	assert.Equal(t, "10.7.0.0/16", n.(*Network).Subnet)
+
+	r, err := c.FindResource("container.consul")
+	require.NoError(t, err)
+
+	co := r.(*Container)
+	assert.Equal(t, "consul:1.8.0", co.Image.Name)
+	assert.Equal(t, []string{"consul", "agent", "-dev"}, co.Command)
+	assert.Equal(t, []KV{{Key: "CONSUL_BIND_INTERFACE", Value: "eth0"}}, co.Environment)
+	assert.Equal(t, []Port{{Local: "8500", Remote: "8500", Host: "18500"}, {Local: "8600", Remote: "8600", Host: "8600", Protocol: "udp"}}, co.Ports)
+	assert.Equal(t, []NetworkAttachment{{Name: "network.backend"}}, co.Networks)
+
+	require.Len(t, co.Volumes, 2)
+	assert.True(t, filepath.IsAbs(co.Volumes[0].Source))
+	assert.Equal(t, "config", filepath.Base(co.Volumes[0].Source))
+	assert.Equal(t, Volume{Source: "consul_data", Destination: "/consul/data", Type: "volume"}, co.Volumes[1])
+}
+
+func TestComposeCreatesBuildForService(t *testing.T) {
+	c, err := setupFunctionConfig(t, map[string]string{"app/docker-compose.yml": composeFileValid}, composeValid)
+	require.NoError(t, err)
+
+	b, err := c.FindResource("container_build.api")
+	require.NoError(t, err)
+	assert.Equal(t, "Dockerfile.dev", b.(*ContainerBuild).Dockerfile)
+	assert.Equal(t, "src", filepath.Base(b.(*ContainerBuild).Context))
+
+	r, err := c.FindResource("container.api")
+	require.NoError(t, err)
+
+	co := r.(*Container)
+	assert.Equal(t, b.(*ContainerBuild).Tag, co.Image.Name)
+	assert.Equal(t, []NetworkAttachment{{Name: "network.local"}}, co.Networks)
+	assert.Contains(t, co.Depends, "container.consul")
+	assert.Contains(t, co.Depends, "container_build.api")
+	assert.Equal(t, []KV{{Key: "MODE", Value: "dev"}}, co.Environment)
+}
+
+func TestComposeWithNetworkWithoutSubnetReturnsError(t *testing.T) {
+	_, err := setupFunctionConfig(t, map[string]string{"app/docker-compose.yml": `
+services:
+  web:
+    image: nginx
+networks:
+  default: {}
+`}, composeValid)
+	assert.Error(t, err)
+}
+
+func TestComposeFilesAreNotReadAsConfig(t *testing.T) {
+	_, err := setupFunctionConfig(t, map[string]string{"app/docker-compose.yml": composeFileValid, "compose.yaml": composeFileValid}, composeValid)
+	assert.NoError(t, err)
+}
+
+const composeValid = `
+network "local" {
+	subnet = "10.5.0.0/16"
+}
+
+compose "app" {
+	file    = "./app/docker-compose.yml"
+	network = "network.local"
+}
+`
+
+const composeFileValid = `
+version: "3.8"
+services:
+  consul:
+    image: consul:1.8.0
+    command: consul agent -dev
+    environment:
+      CONSUL_BIND_INTERFACE: eth0
+    ports:
+      - "18500:8500"
+      - "8600:8600/udp"
+    volumes:
+      - ./config:/consul/config
+      - consul_data:/consul/data
+      - /tmp/anonymous
+    networks:
+      - backend
+  api:
+    build:
+      context: ./src
+      dockerfile: Dockerfile.dev
+    environment:
+      - MODE=dev
+    depends_on:
+      - consul
+networks:
+  backend:
+    ipam:
+      config:
+        - subnet: 10.7.0.0/16
+volumes:
+  consul_data: {}
+`
@@ -53,6 +53,18 @@ type ResourceInfo struct {
 	Status Status `json:"status,omitempty"`
 	// DependsOn is a list of objects which must exist before this resource can be applied
 	DependsOn []string `json:"depends_on,omitempty"`
+	// Module is the name of the module which defined the resource, resources in
+	// nested modules are namespaced with the names of the parent modules e.g. monitoring.k8s
+	Module string `json:"module,omitempty"`
+	// Retries is the number of times creating the resource is retried when it fails,
+	// when not set the default for the resource type is used
+	Retries *int `json:"retries,omitempty"`
+	// RetryInterval is the time to wait before the first retry, the interval doubles
+	// with each retry
+	RetryInterval string `json:"retry_interval,omitempty"`
+	// OnCreate and OnDestroy are the lifecycle hooks for the resource
+	OnCreate  []Hook `json:"on_create,omitempty"`
+	OnDestroy []Hook `json:"on_destroy,omitempty"`
 
 	// parent container
 	Config *Config `json:"-"`
@@ -62,8 +74,10 @@ func (r *ResourceInfo) Info() *ResourceInfo {
 	return r
 }
 
+// FindDependentResource returns the resource with the given name, resources
+// in the same module as r are found before resources outside of the module
 func (r *ResourceInfo) FindDependentResource(name string) (Resource, error) {
-	return r.Config.FindResource(name)
+	return r.Config.findInModule(r.Module, name)
 }
 
 func (r *ResourceInfo) AddChild(c Resource) {
@@ -78,6 +92,7 @@ func (r *ResourceInfo) AddChild(c Resource) {
 type Config struct {
 	Blueprint *Blueprint `json:"blueprint"`
 	Resources []Resource `json:"resources"`
+	Outputs   []*Output  `json:"outputs,omitempty"`
 }
 
 // ResourceNotFoundError is thrown when a resource could not be found
@@ -110,29 +125,127 @@ func New() *Config {
 }
 
 // FindResource returns the resource for the given name
-// name is defined with the convention [type].[name]
+// name is defined with the convention [type].[name], resources defined in a
+// module use the convention module.[module].[type].[name]
 // if a resource can not be found resource will be null and an
 // error will be returned
 //
 // e.g. to find a cluster named k3s
 // r, err := c.FindResource("cluster.k3s")
+//
+// When no resource outside of a module matches [type].[name], a resource
+// defined in a module is returned if exactly one module defines it
 func (c *Config) FindResource(name string) (Resource, error) {
-	parts := strings.Split(name, ".")
+	module, typ, n := splitResourceID(name)
 
-	for _, r := range c.Resources {
-		if r.Info().Type == ResourceType(parts[0]) && r.Info().Name == parts[1] {
-			return r, nil
+	if r := c.findExact(module, typ, n); r != nil {
+		return r, nil
+	}
+
+	if module == "" {
+		var found Resource
+		for _, r := range c.Resources {
+			if r.Info().Type == ResourceType(typ) && r.Info().Name == n {
+				if found != nil {
+					return nil, ResourceNotFoundError{name}
+				}
+
+				found = r
+			}
+		}
+
+		if found != nil {
+			return found, nil
 		}
 	}
 
 	return nil, ResourceNotFoundError{name}
 }
 
+// FindDependency returns the resource referenced by name in the dependencies
+// of r, resources in the same module as r are found before resources in
+// parent modules or outside of a module
+func (c *Config) FindDependency(r Resource, name string) (Resource, error) {
+	return c.findInModule(r.Info().Module, name)
+}
+
+func (c *Config) findInModule(module, name string) (Resource, error) {
+	m, typ, n := splitResourceID(name)
+	if m == "" {
+		for module != "" {
+			if r := c.findExact(module, typ, n); r != nil {
+				return r, nil
+			}
+
+			// search the parent module
+			i := strings.LastIndex(module, ".")
+			if i < 0 {
+				break
+			}
+
+			module = module[:i]
+		}
+	}
+
+	return c.FindResource(name)
+}
+
+func (c *Config) findExact(module, typ, name string) Resource {
+	for _, r := range c.Resources {
+		if r.Info().Module == module && r.Info().Type == ResourceType(typ) && r.Info().Name == name {
+			return r
+		}
+	}
+
+	return nil
+}
+
+// ResourceID returns the id of a resource in the form [type].[name], or
+// module.[module].[type].[name] when the resource is defined in a module
+func ResourceID(r Resource) string {
+	if r.Info().Module != "" {
+		return fmt.Sprintf("module.%s.%s.%s", r.Info().Module, r.Info().Type, r.Info().Name)
+	}
+
+	return fmt.Sprintf("%s.%s", r.Info().Type, r.Info().Name)
+}
+
+// splitResourceID returns the module, type, and name from a resource id
+func splitResourceID(id string) (string, string, string) {
+	parts := strings.Split(id, ".")
+
+	if len(parts) > 3 && parts[0] == string(TypeModule) {
+		return strings.Join(parts[1:len(parts)-2], "."), parts[len(parts)-2], parts[len(parts)-1]
+	}
+
+	if len(parts) < 2 {
+		return "", parts[0], ""
+	}
+
+	return "", parts[0], parts[1]
+}
+
+// validResourceID returns true when id is in the form [type].[name] or
+// module.[module].[type].[name]
+func validResourceID(id string) bool {
+	parts := strings.Split(id, ".")
+	for _, p := range parts {
+		if p == "" {
+			return false
+		}
+	}
+
+	if parts[0] == string(TypeModule) {
+		return len(parts) > 3
+	}
+
+	return len(parts) == 2
+}
+
 // AddResource adds a given resource to the resource list
 // if the resource already exists an error will be returned
 func (c *Config) AddResource(r Resource) error {
-	rf, err := c.FindResource(fmt.Sprintf("%s.%s", r.Info().Type, r.Info().Name))
-	if err == nil && rf != nil {
+	if c.findExact(r.Info().Module, string(r.Info().Type), r.Info().Name) != nil {
 		return ResourceExistsError{r.Info().Name}
 	}
 
@@ -179,7 +292,7 @@ func (c *Config) DoYaLikeDAGs() (*dag.AcyclicGraph, error) {
 	for _, resource := range c.Resources {
 		hasDeps := false
 		for _, d := range resource.Info().DependsOn {
-			dependency, err := c.FindResource(d)
+			dependency, err := c.FindDependency(resource, d)
 			if err != nil {
 				return nil, err
 			}
@@ -0,0 +1,31 @@
+package config
+
+// TypeConsulConfig is the resource string for a ConsulConfig resource
+const TypeConsulConfig ResourceType = "consul_config"
+
+// ConsulConfig writes config entries such as service-defaults,
+// service-intentions or proxy-defaults to a Consul agent, entries are
+// written once the agent is reachable
+type ConsulConfig struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	// Address is the HTTP address of the Consul agent e.g. http://localhost:8500
+	Address string `hcl:"address" json:"address"`
+
+	// Token is the ACL token used to write the entries
+	Token string `hcl:"token,optional" json:"token,omitempty" sensitive:"true"`
+
+	// Entries are HCL or JSON files which contain a single config entry
+	Entries []string `hcl:"entries" json:"entries"`
+
+	// Timeout is the maximum time to wait for the agent and to write the
+	// entries, defaults to 60s
+	Timeout string `hcl:"timeout,optional" json:"timeout,omitempty"`
+}
+
+// NewConsulConfig creates a new ConsulConfig config resource
+func NewConsulConfig(name string) *ConsulConfig {
+	return &ConsulConfig{ResourceInfo: ResourceInfo{Name: name, Type: TypeConsulConfig, Status: PendingCreation}, Timeout: "60s"}
+}
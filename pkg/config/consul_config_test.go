@@ -0,0 +1,38 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsulConfigCreatesCorrectly(t *testing.T) {
+	c, dir, cleanup := setupTestConfig(t, consulConfigValid)
+	defer cleanup()
+
+	r, err := c.FindResource("consul_config.mesh")
+	require.NoError(t, err)
+
+	cc := r.(*ConsulConfig)
+	assert.Equal(t, "http://localhost:8500", cc.Address)
+	assert.Equal(t, []string{filepath.Join(dir, "config/web.hcl")}, cc.Entries)
+	assert.Equal(t, "60s", cc.Timeout)
+	assert.Contains(t, cc.DependsOn, "container.consul")
+}
+
+const consulConfigValid = `
+container "consul" {
+	image {
+		name = "consul:1.9.0"
+	}
+}
+
+consul_config "mesh" {
+	address = "http://localhost:8500"
+	entries = ["./config/web.hcl"]
+
+	depends_on = ["container.consul"]
+}
+`
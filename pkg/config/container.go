@@ -1,5 +1,7 @@
 package config
 
+import "fmt"
+
 // TypeContainer is the resource string for a Container resource
 const TypeContainer ResourceType = "container"
 
@@ -12,20 +14,29 @@ type Container struct {
 
 	Networks []NetworkAttachment `hcl:"network,block" json:"networks,omitempty"` // Attach to the correct network // only when Image is specified
 
-	Image       Image    `hcl:"image,block" json:"image"`                        // image to use for the container
-	Entrypoint  []string `hcl:"entrypoint,optional" json:"entrypoint,omitempty"` // entrypoint to use when starting the container
-	Command     []string `hcl:"command,optional" json:"command,omitempty"`       // command to use when starting the container
-	Environment []KV     `hcl:"env,block" json:"environment,omitempty"`          // environment variables to set when starting the container
-	Volumes     []Volume `hcl:"volume,block" json:"volumes,omitempty"`           // volumes to attach to the container
-	Ports       []Port   `hcl:"port,block" json:"ports,omitempty"`               // ports to expose
+	Image       Image       `hcl:"image,block" json:"image"`                                                 // image to use for the container
+	Entrypoint  []string    `hcl:"entrypoint,optional" json:"entrypoint,omitempty"`                          // entrypoint to use when starting the container
+	Command     []string    `hcl:"command,optional" json:"command,omitempty"`                                // command to use when starting the container
+	Environment []KV        `hcl:"env,block" json:"environment,omitempty"`                                   // environment variables to set when starting the container
+	Volumes     []Volume    `hcl:"volume,block" json:"volumes,omitempty"`                                    // volumes to attach to the container
+	Ports       []Port      `hcl:"port,block" json:"ports,omitempty"`                                        // ports to expose
+	PortRanges  []PortRange `hcl:"port_range,block" json:"port_ranges,omitempty" mapstructure:"port_ranges"` // ranges of ports to expose
 
 	Privileged bool `hcl:"privileged,optional" json:"privileged,omitempty"` // run the container in priviledged mode?
 
+	DNS []string `hcl:"dns,optional" json:"dns,omitempty"` // DNS servers used by the container e.g. the address of a dns resource
+
+	HostNetwork bool `hcl:"host_network,optional" json:"host_network,omitempty"` // use the network of the host rather than attaching to networks
+
 	// resource constraints
 	Resources *Resources `hcl:"resources,block" json:"resources,omitempty"` // resource constraints for the container
 
 	// health checks for the container
 	HealthCheck *HealthCheck `hcl:"health_check,block" json:"health_check,omitempty"`
+
+	// DockerHost is the address of the Docker engine the container is created on
+	// e.g. tcp://10.5.0.2:2376 or ssh://user@host, defaults to the local engine
+	DockerHost string `hcl:"docker_host,optional" json:"docker_host,omitempty" mapstructure:"docker_host"`
 }
 
 // NewContainer returns a new Container resource with the correct default options
@@ -57,9 +68,19 @@ type Volume struct {
 type KV struct {
 	Key   string `hcl:"key" json:"key"`
 	Value string `hcl:"value" json:"value"`
+	// Sensitive values are redacted from logs and output
+	Sensitive bool `hcl:"sensitive,optional" json:"sensitive,omitempty"`
 }
 
 // Validate the config
-func (c *Container) Validate() error {
+func (c *Container) Validate() []error {
+	if c.Image.Name == "" {
+		return []error{fmt.Errorf("image name must be specified")}
+	}
+
+	if err := validateDockerHost(c.DockerHost); err != nil {
+		return []error{err}
+	}
+
 	return nil
 }
@@ -0,0 +1,29 @@
+package config
+
+import "fmt"
+
+// TypeContainerBuild is the resource string for a ContainerBuild resource
+const TypeContainerBuild ResourceType = "container_build"
+
+// ContainerBuild builds a Docker image from a local Dockerfile, containers
+// and clusters can use the image by referencing the tag
+// e.g. container_build.app.tag
+type ContainerBuild struct {
+	// embedded type holding name, etc
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	Context    string `hcl:"context" json:"context"`                          // path to the folder used as the build context
+	Dockerfile string `hcl:"dockerfile,optional" json:"dockerfile,omitempty"` // path to the Dockerfile relative to the context, defaults to Dockerfile
+	Tag        string `hcl:"tag,optional" json:"tag,omitempty"`               // name and tag for the built image
+}
+
+// NewContainerBuild returns a new ContainerBuild resource with the correct default options
+func NewContainerBuild(name string) *ContainerBuild {
+	return &ContainerBuild{
+		ResourceInfo: ResourceInfo{Name: name, Type: TypeContainerBuild, Status: PendingCreation},
+		Dockerfile:   "Dockerfile",
+		Tag:          fmt.Sprintf("shipyard.run/build/%s:latest", name),
+	}
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainerBuildCreatesCorrectly(t *testing.T) {
+	c, dir, cleanup := setupTestConfig(t, containerBuildValid)
+	defer cleanup()
+
+	cb, err := c.FindResource("container_build.app")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "app", cb.Info().Name)
+	assert.Equal(t, TypeContainerBuild, cb.Info().Type)
+	assert.Equal(t, PendingCreation, cb.Info().Status)
+
+	assert.Equal(t, filepath.Join(dir, "src"), cb.(*ContainerBuild).Context)
+	assert.Equal(t, "Dockerfile", cb.(*ContainerBuild).Dockerfile)
+	assert.Equal(t, "shipyard.run/build/app:latest", cb.(*ContainerBuild).Tag)
+}
+
+func TestContainerReferencingBuildDependsOnBuild(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, containerBuildValid)
+	defer cleanup()
+
+	co, err := c.FindResource("container.app")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "app:dev", co.(*Container).Image.Name)
+	assert.Contains(t, co.Info().DependsOn, "container_build.app")
+	assert.Contains(t, co.Info().DependsOn, "container_build.dev")
+}
+
+const containerBuildValid = `
+container_build "app" {
+	context = "./src"
+}
+
+container_build "dev" {
+	context    = "./src"
+	dockerfile = "Dockerfile.dev"
+	tag        = "app:dev"
+}
+
+container "app" {
+	image {
+		name = container_build.dev.tag
+	}
+
+	depends_on = ["container_build.app"]
+}
+`
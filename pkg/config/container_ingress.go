@@ -24,9 +24,18 @@ type ContainerIngress struct {
 	// Remote - This is the destination port for the target container
 	// Host   - The port to expose on localhost, this can be different from the Local container port.
 	Ports []Port `hcl:"port,block" json:"ports,omitempty"`
+
+	// Protocol is the type of traffic proxied by the ingress, http or tcp,
+	// tcp passes raw connections through to the target e.g. databases
+	Protocol string `hcl:"protocol,optional" json:"protocol,omitempty"`
 }
 
 // NewContainerIngress creates a new ingress for standard docker containers with the correct defaults
 func NewContainerIngress(name string) *ContainerIngress {
 	return &ContainerIngress{ResourceInfo: ResourceInfo{Name: name, Type: TypeContainerIngress, Status: PendingCreation}}
 }
+
+// Validate the ContainerIngress and return errors
+func (i *ContainerIngress) Validate() []error {
+	return validateIngressProtocol(i.Protocol)
+}
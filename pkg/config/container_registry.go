@@ -0,0 +1,48 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/shipyard-run/shipyard/pkg/utils"
+)
+
+// TypeContainerRegistry is the resource string for a ContainerRegistry resource
+const TypeContainerRegistry ResourceType = "container_registry"
+
+// ContainerRegistry runs a local Docker registry, images are pushed to the
+// registry when it is created so they can be pulled without access to the
+// internet
+type ContainerRegistry struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	Networks []NetworkAttachment `hcl:"network,block" json:"networks,omitempty"` // networks to attach the registry to
+
+	// Port is the port on the local machine the registry is exposed on
+	Port int `hcl:"port,optional" json:"port"`
+
+	// Images are local images which are pushed to the registry, images
+	// which do not exist locally are pulled first
+	Images []Image `hcl:"image,block" json:"images,omitempty"`
+
+	// Address is the address of the registry on the local machine
+	// e.g. localhost:5000, InternalAddress is the address used by
+	// resources attached to the same network
+	Address         string `json:"address"`
+	InternalAddress string `json:"internal_address" mapstructure:"internal_address"`
+}
+
+// NewContainerRegistry creates a new ContainerRegistry config resource
+func NewContainerRegistry(name string) *ContainerRegistry {
+	return &ContainerRegistry{
+		ResourceInfo: ResourceInfo{Name: name, Type: TypeContainerRegistry, Status: PendingCreation},
+		Port:         5000,
+	}
+}
+
+// setAddress sets the addresses of the registry
+func (r *ContainerRegistry) setAddress() {
+	r.Address = fmt.Sprintf("localhost:%d", r.Port)
+	r.InternalAddress = fmt.Sprintf("%s:5000", utils.FQDN(r.Name, string(r.Type)))
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainerRegistryCreatesCorrectly(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, containerRegistryValid)
+	defer cleanup()
+
+	cr, err := c.FindResource("container_registry.local")
+	assert.NoError(t, err)
+
+	assert.Equal(t, 5001, cr.(*ContainerRegistry).Port)
+	assert.Equal(t, "localhost:5001", cr.(*ContainerRegistry).Address)
+	assert.Equal(t, "local.container_registry.shipyard.run:5000", cr.(*ContainerRegistry).InternalAddress)
+	assert.Equal(t, "consul:1.8.1", cr.(*ContainerRegistry).Images[0].Name)
+	assert.Contains(t, cr.Info().DependsOn, "network.onprem")
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "localhost:5001/consul:1.8.1", co.(*Container).Image.Name)
+	assert.Contains(t, co.Info().DependsOn, "container_registry.local")
+}
+
+const containerRegistryValid = `
+network "onprem" {
+	subnet = "10.6.0.0/16"
+}
+
+container_registry "local" {
+	port = 5001
+
+	network {
+		name = "network.onprem"
+	}
+
+	image {
+		name = "consul:1.8.1"
+	}
+}
+
+container "consul" {
+	image {
+		name = "${container_registry.local.address}/consul:1.8.1"
+	}
+}
+`
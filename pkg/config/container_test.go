@@ -18,6 +18,137 @@ func TestContainerCreatesCorrectly(t *testing.T) {
 	assert.Equal(t, PendingCreation, co.Info().Status)
 }
 
+func TestContainerWithSidecarCreatesSidecar(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, containerDefault+containerSidecar)
+	defer cleanup()
+
+	s, err := c.FindResource("sidecar.envoy")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "container.consul", s.(*Sidecar).Target)
+	assert.Equal(t, "envoyproxy/envoy:v1.14.3", s.(*Sidecar).Image.Name)
+	assert.Contains(t, s.Info().DependsOn, "container.consul")
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, "consul", co.(*Container).Image.Name)
+}
+
+func TestContainerWithSidecarSettingTargetReturnsError(t *testing.T) {
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+
+	createNamedFile(t, dir, "*.hcl", `
+container "consul" {
+	image {
+		name = "consul"
+	}
+
+	sidecar "envoy" {
+		target = "container.other"
+
+		image {
+			name = "envoyproxy/envoy:v1.14.3"
+		}
+	}
+}
+`)
+
+	err := ParseFolder(dir, &Config{}, nil)
+	assert.Error(t, err)
+}
+
+func TestContainerWithPortRangeAddsPorts(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, containerPortRange)
+	defer cleanup()
+
+	co, err := c.FindResource("container.rtp")
+	assert.NoError(t, err)
+
+	ports := co.(*Container).Ports
+	assert.Len(t, ports, 4)
+	assert.Equal(t, "80", ports[0].Local)
+	assert.Equal(t, "10000", ports[1].Local)
+	assert.Equal(t, "10000", ports[1].Host)
+	assert.Equal(t, "udp", ports[1].Protocol)
+	assert.Equal(t, "10002", ports[3].Local)
+}
+
+func TestContainerWithInvalidPortRangeReturnsError(t *testing.T) {
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+
+	createNamedFile(t, dir, "*.hcl", `
+container "rtp" {
+	image {
+		name = "consul"
+	}
+
+	port_range {
+		range = "10002-10000"
+	}
+}
+`)
+
+	err := ParseFolder(dir, &Config{}, nil)
+	assert.Error(t, err)
+}
+
+func TestParsePortRange(t *testing.T) {
+	start, end, err := parsePortRange("8000-8010")
+	assert.NoError(t, err)
+	assert.Equal(t, 8000, start)
+	assert.Equal(t, 8010, end)
+
+	_, _, err = parsePortRange("8000")
+	assert.Error(t, err)
+
+	_, _, err = parsePortRange("a-8010")
+	assert.Error(t, err)
+
+	_, _, err = parsePortRange("0-10")
+	assert.Error(t, err)
+
+	_, _, err = parsePortRange("1000-70000")
+	assert.Error(t, err)
+
+	_, _, err = parsePortRange("1000-3000")
+	assert.Error(t, err)
+}
+
+const containerPortRange = `
+container "rtp" {
+	image {
+		name = "consul"
+	}
+
+	port {
+		local = "80"
+		remote = "80"
+	}
+
+	port_range {
+		range = "10000-10002"
+		enable_host = true
+		protocol = "udp"
+	}
+}
+`
+
+const containerSidecar = `
+container "consul" {
+	image {
+		name = "consul"
+	}
+
+	sidecar "envoy" {
+		image {
+			name = "envoyproxy/envoy:v1.14.3"
+		}
+	}
+}
+`
+
 const containerDefault = `
 network "test" {
 	subnet = "10.0.0.0/24"
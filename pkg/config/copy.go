@@ -0,0 +1,27 @@
+package config
+
+// TypeCopy is the resource string for a Copy resource
+const TypeCopy ResourceType = "copy"
+
+// Copy copies files or folders from the local machine into a running
+// container, sidecar or the server node of a cluster
+type Copy struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	// Target is the resource to copy the files to e.g. container.consul
+	Target string `hcl:"target" json:"target"`
+
+	// Source is the local file or folder to copy
+	Source string `hcl:"source" json:"source"`
+
+	// Destination is the folder in the target the source is copied to, the
+	// folder is created if it does not exist
+	Destination string `hcl:"destination" json:"destination"`
+}
+
+// NewCopy creates a new Copy config resource
+func NewCopy(name string) *Copy {
+	return &Copy{ResourceInfo: ResourceInfo{Name: name, Type: TypeCopy, Status: PendingCreation}}
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopyCreatesCorrectly(t *testing.T) {
+	c, dir, cleanup := setupTestConfig(t, copyValid)
+	defer cleanup()
+
+	cp, err := c.FindResource("copy.config")
+	assert.NoError(t, err)
+
+	assert.Equal(t, filepath.Join(dir, "config"), cp.(*Copy).Source)
+	assert.Equal(t, "/etc/consul", cp.(*Copy).Destination)
+	assert.Contains(t, cp.Info().DependsOn, "container.consul")
+}
+
+const copyValid = `
+container "consul" {
+	image {
+		name = "consul:1.8.1"
+	}
+}
+
+copy "config" {
+	target      = "container.consul"
+	source      = "./config"
+	destination = "/etc/consul"
+}
+`
@@ -0,0 +1,203 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/gocty"
+)
+
+// blockInstance is a single instance of a block which has been
+// expanded using count or for_each
+type blockInstance struct {
+	block *hcl.Block
+	// variables are added to the context when decoding the instance
+	// e.g. count.index or each.key
+	variables map[string]cty.Value
+}
+
+// metaSchema contains the meta arguments which can be set on any resource
+// but are not decoded into the resource
+var metaSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "count"},
+		{Name: "for_each"},
+		{Name: "enabled"},
+		{Name: "disabled"},
+		{Name: "retries"},
+		{Name: "retry_interval"},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "on_create"},
+		{Type: "on_destroy"},
+	},
+}
+
+// expandBlock returns the instances for a block, blocks which do not set count
+// or for_each return a single instance. Instances are named using the index for
+// count, or the key for for_each, e.g. container "web" { count = 2 } creates
+// container.web-0 and container.web-1. Blocks which are not enabled return
+// no instances.
+func (p *parser) expandBlock(b *hcl.Block) ([]blockInstance, error) {
+	// variables, locals and data sources are decoded before
+	// the context is created and can not be expanded
+	if b.Type == string(TypeVariable) || b.Type == string(TypeLocals) || b.Type == string(TypeData) {
+		return []blockInstance{{block: b}}, nil
+	}
+
+	content, body, diag := b.Body.PartialContent(metaSchema)
+	if diag.HasErrors() {
+		return nil, fmt.Errorf("Unable to decode %s: %s", b.Type, diag.Error())
+	}
+
+	enabled, err := p.blockEnabled(b, content)
+	if err != nil || !enabled {
+		return nil, err
+	}
+
+	countAttr, hasCount := content.Attributes["count"]
+	forEachAttr, hasForEach := content.Attributes["for_each"]
+
+	if !hasCount && !hasForEach {
+		if len(content.Attributes) == 0 && len(content.Blocks) == 0 {
+			return []blockInstance{{block: b}}, nil
+		}
+
+		// remove the meta arguments so that the block can be decoded
+		nb := *b
+		nb.Body = body
+
+		return []blockInstance{{block: &nb}}, nil
+	}
+
+	if len(b.Labels) == 0 || b.Type == string(TypeOutput) {
+		return nil, fmt.Errorf("count and for_each can only be used with resources, found in %s block", b.Type)
+	}
+
+	if hasCount && hasForEach {
+		return nil, fmt.Errorf("Resource %s.%s can not set both count and for_each", b.Type, b.Labels[0])
+	}
+
+	instances := []blockInstance{}
+
+	if hasCount {
+		v, diag := countAttr.Expr.Value(p.ctx)
+		if diag.HasErrors() {
+			return nil, fmt.Errorf("Invalid count for %s.%s: %s", b.Type, b.Labels[0], diag.Error())
+		}
+
+		var count int
+		err := gocty.FromCtyValue(v, &count)
+		if err != nil || count < 0 {
+			return nil, fmt.Errorf("Invalid count for %s.%s, count must be a whole number greater than or equal to 0", b.Type, b.Labels[0])
+		}
+
+		for i := 0; i < count; i++ {
+			instances = append(instances, blockInstance{
+				block: instanceBlock(b, body, fmt.Sprintf("%s-%d", b.Labels[0], i)),
+				variables: map[string]cty.Value{
+					"count": cty.ObjectVal(map[string]cty.Value{"index": cty.NumberIntVal(int64(i))}),
+				},
+			})
+		}
+
+		return instances, nil
+	}
+
+	v, diag := forEachAttr.Expr.Value(p.ctx)
+	if diag.HasErrors() {
+		return nil, fmt.Errorf("Invalid for_each for %s.%s: %s", b.Type, b.Labels[0], diag.Error())
+	}
+
+	if v.IsNull() || !v.IsKnown() {
+		return nil, fmt.Errorf("Invalid for_each for %s.%s, value must be a map or a set of strings", b.Type, b.Labels[0])
+	}
+
+	t := v.Type()
+	if !t.IsMapType() && !t.IsObjectType() && !t.IsSetType() && !t.IsListType() && !t.IsTupleType() {
+		return nil, fmt.Errorf("Invalid for_each for %s.%s, value must be a map or a set of strings", b.Type, b.Labels[0])
+	}
+
+	for it := v.ElementIterator(); it.Next(); {
+		k, ev := it.Element()
+
+		// for sets and lists the key is the value
+		if !t.IsMapType() && !t.IsObjectType() {
+			if ev.Type() != cty.String {
+				return nil, fmt.Errorf("Invalid for_each for %s.%s, value must be a map or a set of strings", b.Type, b.Labels[0])
+			}
+
+			k = ev
+		}
+
+		instances = append(instances, blockInstance{
+			block: instanceBlock(b, body, fmt.Sprintf("%s-%s", b.Labels[0], k.AsString())),
+			variables: map[string]cty.Value{
+				"each": cty.ObjectVal(map[string]cty.Value{"key": k, "value": ev}),
+			},
+		})
+	}
+
+	return instances, nil
+}
+
+// blockEnabled evaluates the enabled or disabled meta arguments for a block,
+// blocks are enabled unless either argument is set
+func (p *parser) blockEnabled(b *hcl.Block, content *hcl.BodyContent) (bool, error) {
+	enabledAttr, hasEnabled := content.Attributes["enabled"]
+	disabledAttr, hasDisabled := content.Attributes["disabled"]
+
+	if hasEnabled && hasDisabled {
+		return false, fmt.Errorf("%s %s can not set both enabled and disabled", b.Type, strings.Join(b.Labels, "."))
+	}
+
+	attr := enabledAttr
+	if hasDisabled {
+		attr = disabledAttr
+	}
+
+	if attr == nil {
+		return true, nil
+	}
+
+	v, diag := attr.Expr.Value(p.ctx)
+	if diag.HasErrors() {
+		return false, fmt.Errorf("Invalid %s for %s %s: %s", attr.Name, b.Type, strings.Join(b.Labels, "."), diag.Error())
+	}
+
+	v, err := convert.Convert(v, cty.Bool)
+	if err != nil || v.IsNull() || !v.IsKnown() {
+		return false, fmt.Errorf("Invalid %s for %s %s, value must be true or false", attr.Name, b.Type, strings.Join(b.Labels, "."))
+	}
+
+	if hasDisabled {
+		return v.False(), nil
+	}
+
+	return v.True(), nil
+}
+
+// instanceBlock returns a copy of the block with the given name and body, the
+// body must not contain the meta arguments so that it can be decoded. The
+// original block is not modified as it is used to create every instance.
+func instanceBlock(b *hcl.Block, body hcl.Body, name string) *hcl.Block {
+	nb := *b
+	nb.Labels = []string{name}
+	nb.Body = body
+
+	return &nb
+}
+
+// setInstanceVariables sets the count and each variables in the
+// context, passing nil removes them
+func (p *parser) setInstanceVariables(vars map[string]cty.Value) {
+	delete(p.ctx.Variables, "count")
+	delete(p.ctx.Variables, "each")
+
+	for k, v := range vars {
+		p.ctx.Variables[k] = v
+	}
+}
@@ -0,0 +1,227 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountCreatesIndexedResources(t *testing.T) {
+	c, err := setupVariableConfig(t, nil, countContainer)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, c.ResourceCount())
+
+	for i, n := range []string{"container.web-0", "container.web-1", "container.web-2"} {
+		co, err := c.FindResource(n)
+		assert.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("web-%d", i), co.(*Container).Environment[0].Value)
+	}
+}
+
+func TestCountUsesVariables(t *testing.T) {
+	c, err := setupVariableConfig(t, map[string]string{"replicas": "5"}, countContainer)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 5, c.ResourceCount())
+}
+
+func TestCountZeroCreatesNoResources(t *testing.T) {
+	c, err := setupVariableConfig(t, map[string]string{"replicas": "0"}, countContainer)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 0, c.ResourceCount())
+}
+
+func TestCountInvalidReturnsError(t *testing.T) {
+	_, err := setupVariableConfig(t, map[string]string{"replicas": "-1"}, countContainer)
+	assert.Error(t, err)
+}
+
+func TestForEachMapCreatesNamedResources(t *testing.T) {
+	c, err := setupVariableConfig(t, nil, forEachMap)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, c.ResourceCount())
+
+	co, err := c.FindResource("container.consul-server")
+	assert.NoError(t, err)
+	assert.Equal(t, "consul:1.7.2", co.(*Container).Image.Name)
+
+	co, err = c.FindResource("container.consul-client")
+	assert.NoError(t, err)
+	assert.Equal(t, "consul:1.6.1", co.(*Container).Image.Name)
+}
+
+func TestForEachSetCreatesNamedResources(t *testing.T) {
+	c, err := setupVariableConfig(t, nil, forEachSet)
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("network.net-a")
+	assert.NoError(t, err)
+	assert.Equal(t, "net-a", co.Info().Name)
+
+	_, err = c.FindResource("network.net-b")
+	assert.NoError(t, err)
+}
+
+func TestCountAndForEachReturnsError(t *testing.T) {
+	_, err := setupVariableConfig(t, nil, countAndForEach)
+	assert.Error(t, err)
+}
+
+func TestCountDoesNotLeakIntoOtherBlocks(t *testing.T) {
+	_, err := setupVariableConfig(t, nil, countContainer, countLeak)
+	assert.Error(t, err)
+}
+
+func TestEnabledFalseSkipsResource(t *testing.T) {
+	c, err := setupVariableConfig(t, map[string]string{"docs": "false"}, enabledDocs)
+	assert.NoError(t, err)
+
+	_, err = c.FindResource("docs.docs")
+	assert.Error(t, err)
+
+	_, err = c.FindOutput("docs")
+	assert.Error(t, err)
+
+	_, err = c.FindResource("container.consul")
+	assert.Error(t, err)
+}
+
+func TestEnabledTrueCreatesResource(t *testing.T) {
+	c, err := setupVariableConfig(t, nil, enabledDocs)
+	assert.NoError(t, err)
+
+	_, err = c.FindResource("docs.docs")
+	assert.NoError(t, err)
+
+	_, err = c.FindOutput("docs")
+	assert.NoError(t, err)
+
+	_, err = c.FindResource("container.consul")
+	assert.NoError(t, err)
+}
+
+func TestEnabledWithCountCreatesInstances(t *testing.T) {
+	c, err := setupVariableConfig(t, nil, `
+network "net" {
+	enabled = true
+	count = 2
+
+	subnet = "10.0.0.0/16"
+}
+`)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, c.ResourceCount())
+}
+
+func TestEnabledAndDisabledReturnsError(t *testing.T) {
+	_, err := setupVariableConfig(t, nil, `
+network "net" {
+	enabled = true
+	disabled = true
+
+	subnet = "10.0.0.0/16"
+}
+`)
+	assert.Error(t, err)
+}
+
+func TestEnabledInvalidValueReturnsError(t *testing.T) {
+	_, err := setupVariableConfig(t, nil, `
+network "net" {
+	enabled = "maybe"
+
+	subnet = "10.0.0.0/16"
+}
+`)
+	assert.Error(t, err)
+}
+
+const countContainer = `
+variable "replicas" {
+	type = number
+	default = 3
+}
+
+container "web" {
+	count = var.replicas
+
+	image {
+		name = "nginx"
+	}
+
+	env {
+		key = "NAME"
+		value = "web-${count.index}"
+	}
+}
+`
+
+const countLeak = `
+container "other" {
+	image {
+		name = "nginx:${count.index}"
+	}
+}
+`
+
+const forEachMap = `
+container "consul" {
+	for_each = {
+		server = "1.7.2"
+		client = "1.6.1"
+	}
+
+	image {
+		name = "consul:${each.value}"
+	}
+}
+`
+
+const forEachSet = `
+network "net" {
+	for_each = ["a", "b"]
+
+	subnet = "10.0.0.0/16"
+}
+`
+
+const countAndForEach = `
+network "net" {
+	count = 1
+	for_each = ["a"]
+
+	subnet = "10.0.0.0/16"
+}
+`
+
+const enabledDocs = `
+variable "docs" {
+	type = bool
+	default = true
+}
+
+docs "docs" {
+	enabled = var.docs
+
+	path = "./docs"
+	port = 8080
+}
+
+container "consul" {
+	disabled = !var.docs
+
+	image {
+		name = "consul:1.7.2"
+	}
+}
+
+output "docs" {
+	enabled = var.docs
+	value = "http://localhost:8080"
+}
+`
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// TypeData is the block type for data sources, data blocks have two labels
+// the type of the data source and the name e.g. data "docker_image" "consul"
+const TypeData ResourceType = "data"
+
+// DataSourceFunc reads the current state of a data source from the runtime,
+// attributes contains the values of the attributes defined in the data block,
+// the returned values are exposed to expressions as data.type.name.attribute
+type DataSourceFunc func(attributes map[string]string) (map[string]interface{}, error)
+
+var dataSources = map[string]DataSourceFunc{}
+var dataSourcesLock = sync.Mutex{}
+
+// RegisterDataSource registers the function used to read data blocks of the
+// given type
+func RegisterDataSource(t string, f DataSourceFunc) {
+	dataSourcesLock.Lock()
+	defer dataSourcesLock.Unlock()
+
+	dataSources[t] = f
+}
+
+func getDataSource(t string) (DataSourceFunc, bool) {
+	dataSourcesLock.Lock()
+	defer dataSourcesLock.Unlock()
+
+	f, ok := dataSources[t]
+	return f, ok
+}
+
+// setDataSources reads any data blocks and adds the results to the context
+// as data.type.name, data blocks can reference variables and locals
+func (p *parser) setDataSources(blocks []fileBlock) error {
+	values := map[string]map[string]cty.Value{}
+	defined := map[string]string{}
+
+	for _, fb := range blocks {
+		b := fb.block
+		if b.Type != string(TypeData) {
+			continue
+		}
+
+		key := fmt.Sprintf("%s.%s", b.Labels[0], b.Labels[1])
+		if f, ok := defined[key]; ok {
+			return fmt.Errorf("Data source %s is defined more than once, defined in file %s and %s", key, f, fb.file)
+		}
+
+		defined[key] = fb.file
+
+		v, err := p.readDataSource(b)
+		if err != nil {
+			return fmt.Errorf("Unable to read data source %s defined in file %s: %s", key, fb.file, err)
+		}
+
+		if values[b.Labels[0]] == nil {
+			values[b.Labels[0]] = map[string]cty.Value{}
+		}
+
+		values[b.Labels[0]][b.Labels[1]] = v
+	}
+
+	types := map[string]cty.Value{}
+	for t, v := range values {
+		types[t] = cty.ObjectVal(v)
+	}
+
+	p.ctx.Variables[string(TypeData)] = cty.ObjectVal(types)
+
+	return nil
+}
+
+// readDataSource evaluates the attributes of a data block and reads the
+// data source
+func (p *parser) readDataSource(b *hcl.Block) (cty.Value, error) {
+	f, ok := getDataSource(b.Labels[0])
+	if !ok {
+		return cty.NilVal, fmt.Errorf("data source type %s is not supported", b.Labels[0])
+	}
+
+	attrs, diag := b.Body.JustAttributes()
+	if diag.HasErrors() {
+		return cty.NilVal, newParseError(diag)
+	}
+
+	values := map[string]string{}
+	for n, a := range attrs {
+		v, diag := a.Expr.Value(p.ctx)
+		if diag.HasErrors() {
+			return cty.NilVal, newParseError(diag)
+		}
+
+		sv, err := convert.Convert(v, cty.String)
+		if err != nil || sv.IsNull() || !sv.IsKnown() {
+			return cty.NilVal, fmt.Errorf("attribute %s must be a string", n)
+		}
+
+		values[n] = sv.AsString()
+	}
+
+	d, err := f(values)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	return jsonValue(d)
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupDataSource(t *testing.T) *map[string]string {
+	received := &map[string]string{}
+
+	RegisterDataSource("test_image", func(attrs map[string]string) (map[string]interface{}, error) {
+		*received = attrs
+
+		if attrs["name"] == "error" {
+			return nil, fmt.Errorf("boom")
+		}
+
+		return map[string]interface{}{"present": true, "id": "abc123"}, nil
+	})
+
+	t.Cleanup(func() {
+		dataSourcesLock.Lock()
+		delete(dataSources, "test_image")
+		dataSourcesLock.Unlock()
+	})
+
+	return received
+}
+
+func TestDataSourceIsUsedInExpressions(t *testing.T) {
+	received := setupDataSource(t)
+
+	c, err := setupVariableConfig(t, nil, dataImage)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "consul:1.7.2", (*received)["name"])
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, "abc123", co.(*Container).Image.Name)
+	assert.Equal(t, "true", co.(*Container).Environment[0].Value)
+}
+
+func TestDataSourceIsNotAddedAsResource(t *testing.T) {
+	setupDataSource(t)
+
+	c, err := setupVariableConfig(t, nil, dataImage)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, c.ResourceCount())
+}
+
+func TestDataSourceWithUnknownTypeReturnsError(t *testing.T) {
+	_, err := setupVariableConfig(t, nil, dataUnknown)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "data source type nope is not supported")
+}
+
+func TestDataSourceErrorIsReturned(t *testing.T) {
+	setupDataSource(t)
+
+	_, err := setupVariableConfig(t, map[string]string{"version": "error"}, dataImage)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+}
+
+func TestDataSourceDefinedTwiceReturnsError(t *testing.T) {
+	setupDataSource(t)
+
+	_, err := setupVariableConfig(t, nil, dataImage, dataDuplicate)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Data source test_image.consul is defined more than once")
+}
+
+const dataImage = `
+variable "version" {
+	default = "1.7.2"
+}
+
+locals {
+	image = var.version == "error" ? "error" : "consul:${var.version}"
+}
+
+data "test_image" "consul" {
+	name = local.image
+}
+
+container "consul" {
+	image {
+		name = data.test_image.consul.id
+	}
+
+	env {
+		key = "PRESENT"
+		value = data.test_image.consul.present
+	}
+}
+`
+
+const dataDuplicate = `
+data "test_image" "consul" {
+	name = "consul:1.7.2"
+}
+`
+
+const dataUnknown = `
+data "nope" "consul" {
+	name = "consul:1.7.2"
+}
+`
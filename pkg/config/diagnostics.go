@@ -0,0 +1,80 @@
+package config
+
+import (
+	"io"
+
+	"github.com/hashicorp/hcl2/hcl"
+)
+
+// ParseError is returned when a config file can not be parsed or decoded,
+// it contains the diagnostics from the HCL parser which include the location
+// of each problem
+type ParseError struct {
+	Diagnostics hcl.Diagnostics
+
+	// files contains the source for the diagnostics so that
+	// snippets can be shown
+	files map[string]*hcl.File
+}
+
+// newParseError creates a ParseError for the given diagnostics, the source for any
+// files which are not passed is read from disk
+func newParseError(diag hcl.Diagnostics, files ...*hcl.File) ParseError {
+	p := ParseError{Diagnostics: diag, files: map[string]*hcl.File{}}
+
+	for _, f := range files {
+		if f != nil && f.Body != nil {
+			p.files[f.Body.MissingItemRange().Filename] = f
+		}
+	}
+
+	for _, d := range diag {
+		if d.Subject == nil {
+			continue
+		}
+
+		if _, ok := p.files[d.Subject.Filename]; ok {
+			continue
+		}
+
+		// the source is only used to show snippets, errors reading the
+		// file are ignored
+		if f, err := parseHCL(d.Subject.Filename); err == nil {
+			p.files[d.Subject.Filename] = f
+		}
+	}
+
+	return p
+}
+
+func (p ParseError) Error() string {
+	return p.Diagnostics.Error()
+}
+
+// Range returns the location of the first error, nil is returned when
+// the location is not known
+func (p ParseError) Range() *hcl.Range {
+	for _, d := range p.Diagnostics {
+		if d.Severity == hcl.DiagError && d.Subject != nil {
+			return d.Subject
+		}
+	}
+
+	return nil
+}
+
+// Filename returns the file containing the first error
+func (p ParseError) Filename() string {
+	if r := p.Range(); r != nil {
+		return r.Filename
+	}
+
+	return ""
+}
+
+// WriteDiagnostics writes the diagnostics to w along with a snippet of the source
+// for each problem, lines are wrapped at width unless width is 0
+func (p ParseError) WriteDiagnostics(w io.Writer, width uint, color bool) error {
+	dw := hcl.NewDiagnosticTextWriter(w, p.files, width, color)
+	return dw.WriteDiagnostics(p.Diagnostics)
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
+)
+
+func TestParseErrorContainsLocationForDecodeError(t *testing.T) {
+	dir := createTempDirectory(t)
+	defer removeTestFiles(t, dir)
+
+	f := createTestFile(t, dir, diagnosticsUnknownAttribute)
+
+	err := ParseHCLFile(f, New(), nil)
+
+	pe := ParseError{}
+	assert.True(t, xerrors.As(err, &pe))
+	assert.Equal(t, f, pe.Filename())
+	assert.Equal(t, 7, pe.Range().Start.Line)
+	assert.Equal(t, 2, pe.Range().Start.Column)
+}
+
+func TestParseErrorContainsLocationForSyntaxError(t *testing.T) {
+	dir := createTempDirectory(t)
+	defer removeTestFiles(t, dir)
+
+	f := createTestFile(t, dir, "container \"web\" {\n  image = \n}\n")
+
+	err := ParseHCLFile(f, New(), nil)
+
+	pe := ParseError{}
+	assert.True(t, xerrors.As(err, &pe))
+	assert.Equal(t, f, pe.Filename())
+	assert.Equal(t, 2, pe.Range().Start.Line)
+}
+
+func TestParseErrorWritesSnippet(t *testing.T) {
+	dir := createTempDirectory(t)
+	defer removeTestFiles(t, dir)
+
+	f := createTestFile(t, dir, diagnosticsUnknownAttribute)
+
+	err := ParseHCLFile(f, New(), nil)
+
+	pe := ParseError{}
+	assert.True(t, xerrors.As(err, &pe))
+
+	out := bytes.NewBufferString("")
+	err = pe.WriteDiagnostics(out, 0, false)
+	assert.NoError(t, err)
+
+	assert.Contains(t, out.String(), "Error: Unsupported argument")
+	assert.Contains(t, out.String(), "on "+f+" line 7")
+	assert.Contains(t, out.String(), `7: 	imag = "nginx"`)
+}
+
+const diagnosticsUnknownAttribute = `
+container "web" {
+	image {
+		name = "nginx"
+	}
+
+	imag = "nginx"
+}
+`
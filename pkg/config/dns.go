@@ -0,0 +1,64 @@
+package config
+
+import "fmt"
+
+// TypeDNS is the resource string for a DNS resource
+const TypeDNS ResourceType = "dns"
+
+// DNS runs a DNS server attached to a network, containers can be resolved
+// using their name and the domain e.g. web.shipyard.local and additional
+// records can be defined. Containers use the server by setting the dns
+// attribute to the address of the server e.g. dns = [dns.local.address]
+type DNS struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	// Networks to attach the server to, the first network must set a static
+	// ip_address which is the address containers use to query the server
+	Networks []NetworkAttachment `hcl:"network,block" json:"networks,omitempty"`
+
+	// Domain records are served for, defaults to shipyard.local
+	Domain string `hcl:"domain,optional" json:"domain,omitempty"`
+
+	// Upstream servers queries for other domains are forwarded to
+	Upstream []string `hcl:"upstream,optional" json:"upstream,omitempty"`
+
+	// Records are additional A records served by the server
+	Records []DNSRecord `hcl:"record,block" json:"records,omitempty"`
+
+	// Address is the IP address of the server
+	Address string `json:"address"`
+}
+
+// DNSRecord is an A record served by a DNS resource
+type DNSRecord struct {
+	// Name is the fully qualified name e.g. api.shipyard.local
+	Name string `hcl:"name" json:"name"`
+	IP   string `hcl:"ip" json:"ip"`
+}
+
+// NewDNS creates a new DNS config resource
+func NewDNS(name string) *DNS {
+	return &DNS{
+		ResourceInfo: ResourceInfo{Name: name, Type: TypeDNS, Status: PendingCreation},
+		Domain:       "shipyard.local",
+		Upstream:     []string{"8.8.8.8", "8.8.4.4"},
+	}
+}
+
+// setAddress sets the address of the server from the first network
+func (d *DNS) setAddress() {
+	if len(d.Networks) > 0 {
+		d.Address = d.Networks[0].IPAddress
+	}
+}
+
+// Validate the DNS and return errors
+func (d *DNS) Validate() []error {
+	if d.Address == "" {
+		return []error{fmt.Errorf("the first network must set ip_address so containers can use the server")}
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDNSCreatesCorrectly(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, dnsValid)
+	defer cleanup()
+
+	r, err := c.FindResource("dns.local")
+	require.NoError(t, err)
+
+	d := r.(*DNS)
+	assert.Equal(t, "example.local", d.Domain)
+	assert.Equal(t, "10.6.0.53", d.Address)
+	assert.Equal(t, []DNSRecord{{Name: "api.example.local", IP: "10.6.0.100"}}, d.Records)
+	assert.Contains(t, d.DependsOn, "network.local")
+	assert.Len(t, d.Validate(), 0)
+}
+
+func TestDNSAddressCanBeReferenced(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, dnsValid)
+	defer cleanup()
+
+	r, err := c.FindResource("container.app")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"10.6.0.53"}, r.(*Container).DNS)
+}
+
+func TestDNSWithoutAddressFailsValidation(t *testing.T) {
+	d := NewDNS("local")
+	d.Networks = []NetworkAttachment{{Name: "network.local"}}
+	d.setAddress()
+
+	assert.Len(t, d.Validate(), 1)
+}
+
+const dnsValid = `
+network "local" {
+	subnet = "10.6.0.0/16"
+}
+
+dns "local" {
+	domain = "example.local"
+
+	network {
+		name       = "network.local"
+		ip_address = "10.6.0.53"
+	}
+
+	record {
+		name = "api.example.local"
+		ip   = "10.6.0.100"
+	}
+}
+
+container "app" {
+	image {
+		name = "app:latest"
+	}
+
+	dns = [dns.local.address]
+}
+`
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/shipyard-run/shipyard/pkg/utils"
+)
+
+// TypeDockerVolume is the resource string for a DockerVolume resource
+const TypeDockerVolume ResourceType = "volume"
+
+// DockerVolume is a named Docker volume, containers mount the volume by
+// setting the source of a volume block to volume.[name] and the type to
+// volume
+type DockerVolume struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	// KeepOnDestroy leaves the volume and its data in place when the
+	// resource is destroyed so that it can be used by the next run
+	KeepOnDestroy bool `hcl:"keep_on_destroy,optional" json:"keep_on_destroy,omitempty" mapstructure:"keep_on_destroy"`
+
+	// output parameters
+
+	// VolumeName is the name of the volume in Docker
+	VolumeName string `hcl:"volume_name,optional" json:"volume_name,omitempty" mapstructure:"volume_name"`
+}
+
+// NewDockerVolume creates a new DockerVolume config resource
+func NewDockerVolume(name string) *DockerVolume {
+	return &DockerVolume{
+		ResourceInfo: ResourceInfo{Name: name, Type: TypeDockerVolume, Status: PendingCreation},
+		VolumeName:   utils.FQDNVolumeName(name),
+	}
+}
+
+// ensureAbsoluteVolumes makes the source of bind mounts absolute, the
+// source of a volume mount is the name of a Docker volume
+func ensureAbsoluteVolumes(vols []Volume, file string) {
+	for i, v := range vols {
+		if v.Type == "volume" || v.Type == "tmpfs" {
+			continue
+		}
+
+		vols[i].Source = ensureAbsolute(v.Source, file)
+	}
+}
+
+// volumeReferences returns the volume resources mounted by vols
+func volumeReferences(vols []Volume) []string {
+	refs := []string{}
+	for _, v := range vols {
+		if v.Type == "volume" && strings.HasPrefix(v.Source, string(TypeDockerVolume)+".") {
+			refs = append(refs, v.Source)
+		}
+	}
+
+	return refs
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDockerVolumeCreatesCorrectly(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, dockerVolumeValid)
+	defer cleanup()
+
+	r, err := c.FindResource("volume.data")
+	assert.NoError(t, err)
+
+	v := r.(*DockerVolume)
+	assert.True(t, v.KeepOnDestroy)
+	assert.Equal(t, "data.volume.shipyard.run", v.VolumeName)
+}
+
+func TestContainerDependsOnVolume(t *testing.T) {
+	c, dir, cleanup := setupTestConfig(t, dockerVolumeValid)
+	defer cleanup()
+
+	r, err := c.FindResource("container.postgres")
+	assert.NoError(t, err)
+
+	co := r.(*Container)
+	assert.Contains(t, co.DependsOn, "volume.data")
+
+	// only bind mounts are relative to the file
+	assert.Equal(t, "volume.data", co.Volumes[0].Source)
+	assert.Equal(t, filepath.Join(dir, "init"), co.Volumes[1].Source)
+}
+
+const dockerVolumeValid = `
+volume "data" {
+	keep_on_destroy = true
+}
+
+container "postgres" {
+	image {
+		name = "postgres:13"
+	}
+
+	volume {
+		source      = "volume.data"
+		destination = "/var/lib/postgresql/data"
+		type        = "volume"
+	}
+
+	volume {
+		source      = "./init"
+		destination = "/docker-entrypoint-initdb.d"
+	}
+}
+`
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDynamicBlocksAreExpanded(t *testing.T) {
+	c, err := setupVariableConfig(t, nil, dynamicContainer)
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+
+	cc := co.(*Container)
+	assert.Len(t, cc.Ports, 3)
+	assert.Equal(t, "8500", cc.Ports[0].Local)
+	assert.Equal(t, "18502", cc.Ports[2].Host)
+
+	assert.Len(t, cc.Volumes, 2)
+	assert.Equal(t, "/config", cc.Volumes[0].Destination)
+	assert.Equal(t, "/data", cc.Volumes[1].Destination)
+
+	// static blocks are decoded along with dynamic blocks
+	assert.Len(t, cc.Environment, 1)
+}
+
+func TestDynamicBlockIteratorIsNotAReference(t *testing.T) {
+	c, err := setupVariableConfig(t, nil, dynamicNetwork)
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+
+	cc := co.(*Container)
+	assert.Len(t, cc.Networks, 2)
+	assert.Equal(t, "network.b", cc.Networks[1].Name)
+}
+
+const dynamicContainer = `
+variable "ports" {
+	default = [8500, 8501, 8502]
+}
+
+variable "volumes" {
+	default = {
+		"/config" = "./config"
+		"/data" = "./data"
+	}
+}
+
+container "consul" {
+	image {
+		name = "consul:1.7.2"
+	}
+
+	dynamic "port" {
+		for_each = var.ports
+
+		content {
+			local = port.value
+			remote = port.value
+			host = port.value + 10000
+		}
+	}
+
+	dynamic "volume" {
+		for_each = var.volumes
+		iterator = v
+
+		content {
+			source = v.value
+			destination = v.key
+		}
+	}
+
+	env {
+		key = "NAME"
+		value = "consul"
+	}
+}
+`
+
+const dynamicNetwork = `
+container "consul" {
+	image {
+		name = "consul:1.7.2"
+	}
+
+	dynamic "network" {
+		for_each = ["network.a", "network.b"]
+
+		content {
+			name = network.value
+		}
+	}
+}
+`
@@ -1,5 +1,16 @@
 package config
 
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/shipyard-run/shipyard/pkg/utils"
+)
+
 // TypeExecLocal is the resource string for a LocalExec resource
 const TypeExecLocal ResourceType = "exec_local"
 
@@ -9,7 +20,8 @@ type ExecLocal struct {
 
 	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
 
-	// Either Script or Command must be specified
+	// Either Script or Command must be specified, Script can be the path
+	// to a script or an inline script defined using a heredoc
 	Script    string   `hcl:"script,optional" json:"script,omitempty"` // Path to a script to execute
 	Command   string   `hcl:"cmd,optional" json:"cmd,omitempty"`       // Command to execute
 	Arguments []string `hcl:"args,optional" json:"args,omitempty"`     // only used when combined with Command
@@ -21,3 +33,32 @@ type ExecLocal struct {
 func NewExecLocal(name string) *ExecLocal {
 	return &ExecLocal{ResourceInfo: ResourceInfo{Name: name, Type: TypeExecLocal, Status: PendingCreation}}
 }
+
+// isInlineScript returns true when a script attribute contains the script
+// rather than the path to a script, inline scripts are defined using a
+// heredoc so always contain a new line
+func isInlineScript(s string) bool {
+	return strings.Contains(s, "\n")
+}
+
+// writeInlineScript writes an inline script to the shipyard temp folder and
+// returns the path, the file name is based on the content so the same script
+// is always written to the same path
+func writeInlineScript(name, script string) (string, error) {
+	dir := filepath.Join(utils.ShipyardTemp(), "scripts")
+
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return "", err
+	}
+
+	// scripts without an interpreter are run using sh
+	if !strings.HasPrefix(script, "#!") {
+		script = "#!/bin/sh\n" + script
+	}
+
+	h := sha256.Sum256([]byte(script))
+	out := filepath.Join(dir, fmt.Sprintf("%x-%s.sh", h[:8], name))
+
+	return out, ioutil.WriteFile(out, []byte(script), 0755)
+}
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"io/ioutil"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -20,6 +23,36 @@ func TestExecLocalCreatesCorrectly(t *testing.T) {
 	// assert.Equal(t, dir+"/scripts/setup_vault.sh", ExecLocal(*ex).Script)
 }
 
+func TestExecLocalWritesInlineScript(t *testing.T) {
+	defer setupTemplateHome(t)()
+
+	c, _, cleanup := setupTestConfig(t, execLocalInline)
+	defer cleanup()
+
+	ex, err := c.FindResource("exec_local.setup_vault")
+	assert.NoError(t, err)
+
+	script := ex.(*ExecLocal).Script
+	assert.True(t, strings.HasPrefix(script, os.Getenv("HOME")))
+
+	fi, err := os.Stat(script)
+	assert.NoError(t, err)
+	assert.NotZero(t, fi.Mode()&0100)
+
+	d, err := ioutil.ReadFile(script)
+	assert.NoError(t, err)
+	assert.Equal(t, "#!/bin/sh\nvault status\nvault secrets list\n", string(d))
+}
+
+var execLocalInline = `
+exec_local "setup_vault" {
+  script = <<EOF
+vault status
+vault secrets list
+EOF
+}
+`
+
 var execLocalRelative = `
 exec_local "setup_vault" {
   script = "./scripts/setup_vault.sh"
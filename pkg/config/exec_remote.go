@@ -16,7 +16,7 @@ type ExecRemote struct {
 	Target string `hcl:"target,optional" json:"target,omitempty"` // Attach to a running target and exec
 
 	// Either Script or Command must be specified
-	//Script    string   `hcl:"script,optional" json:"script,omitempty"` // Path to a script to execute
+	Script           string   `hcl:"script,optional" json:"script,omitempty"`                       // Inline script to execute using sh, paths are not supported
 	Command          string   `hcl:"cmd,optional" json:"cmd,omitempty"`                             // Command to execute
 	Arguments        []string `hcl:"args,optional" json:"args,omitempty"`                           // only used when combined with Command
 	WorkingDirectory string   `hcl:"working_directory,optional" json:"working_directory,omitempty"` // Working directory to exectute commands
@@ -11,7 +11,7 @@ func setupTestConfig(t *testing.T, contents string) (*Config, string, func()) {
 	createNamedFile(t, dir, "*.hcl", contents)
 
 	c := &Config{}
-	err := ParseFolder(dir, c)
+	err := ParseFolder(dir, c, nil)
 	assert.NoError(t, err)
 
 	err = ParseReferences(c)
@@ -37,6 +37,26 @@ func TestExecRemoteCreatesCorrectly(t *testing.T) {
 	assert.Equal(t, dir+"/scripts", ex.(*ExecRemote).Volumes[0].Source)
 }
 
+func TestExecRemoteWithScriptPathReturnsError(t *testing.T) {
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+	createNamedFile(t, dir, "*.hcl", execRemoteScriptPath)
+
+	c := New()
+	err := ParseFolder(dir, c, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "use cmd to run a script file")
+}
+
+func TestExecRemoteWithInlineScriptCreatesCorrectly(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, execRemoteInlineScript)
+	defer cleanup()
+
+	ex, err := c.FindResource("exec_remote.setup_vault")
+	assert.NoError(t, err)
+	assert.Equal(t, "vault status\nvault secrets list\n", ex.(*ExecRemote).Script)
+}
+
 var execRemoteRelative = `
 network "cloud" {
 	subnet = "192.158.32.12"
@@ -58,3 +78,23 @@ exec_remote "setup_vault" {
   }
 }
 `
+
+var execRemoteScriptPath = `
+exec_remote "setup_vault" {
+  target = "container.vault"
+  script = "./scripts/setup_vault.sh"
+}
+`
+
+var execRemoteInlineScript = `
+exec_remote "setup_vault" {
+  image {
+    name = "hashicorp/vault:latest"
+  }
+
+  script = <<EOF
+vault status
+vault secrets list
+EOF
+}
+`
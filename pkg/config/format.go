@@ -0,0 +1,141 @@
+package config
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+	"github.com/hashicorp/hcl2/hclwrite"
+)
+
+// formatOrder is the canonical order for the blocks in a formatted file,
+// blocks which are not in the list are placed at the end of the file
+var formatOrder = []ResourceType{
+	TypeVariable,
+	TypeLocals,
+	TypeData,
+	TypeRandomPassword,
+	TypeRandomID,
+	TypeNetwork,
+	TypeNetworkRoute,
+	TypeDockerVolume,
+	TypeDNS,
+	TypeTrafficCapture,
+	TypeImageCache,
+	TypeContainerRegistry,
+	TypeCertificateCA,
+	TypeCertificateLeaf,
+	TypeK8sCluster,
+	TypeK8sClusterExternal,
+	TypeNomadCluster,
+	TypeContainerBuild,
+	TypeContainer,
+	TypeSidecar,
+	TypeVault,
+	TypeAWS,
+	TypeMinIO,
+	TypeMockAPI,
+	TypeContainerIngress,
+	TypeIngress,
+	TypeLoadBalancer,
+	TypeK8sIngress,
+	TypeNomadIngress,
+	TypeLocalIngress,
+	TypeK8sNamespace,
+	TypeK8sSecret,
+	TypeK8sConfigMap,
+	TypeK8sConfig,
+	TypeKustomize,
+	TypeHelmRepository,
+	TypeHelm,
+	TypeNomadJob,
+	TypeConsulConfig,
+	TypeGitOps,
+	TypeK8sWait,
+	TypeHTTPCheck,
+	TypeChaos,
+	TypeTemplate,
+	TypeCopy,
+	TypeExecLocal,
+	TypeExecRemote,
+	TypeLocalService,
+	TypeSQLExec,
+	TypeDocs,
+	TypeModule,
+	TypeCompose,
+	TypeService,
+	TypeNullResource,
+	TypeOutput,
+}
+
+// formatChunk is the source for a block including any comments before it
+type formatChunk struct {
+	order int
+	src   []byte
+}
+
+// Format returns the canonical format for the source of a config file.
+// Blocks are sorted by type, blocks of the same type keep their original
+// order, comments before a block are moved with the block. Indentation and
+// alignment of attributes is fixed using hclwrite.
+func Format(src []byte, filename string) ([]byte, error) {
+	f, diag := hclsyntax.ParseConfig(src, filename, hcl.Pos{Line: 1, Column: 1})
+	if diag.HasErrors() {
+		return nil, newParseError(diag, f)
+	}
+
+	body := f.Body.(*hclsyntax.Body)
+
+	// files with top level attributes such as blueprint files are
+	// only formatted, not sorted
+	if len(body.Attributes) > 0 || len(body.Blocks) == 0 {
+		return formatSource(src), nil
+	}
+
+	chunks := []formatChunk{}
+	start := 0
+
+	for _, b := range body.Blocks {
+		end := b.Range().End.Byte
+
+		chunks = append(chunks, formatChunk{
+			order: blockOrder(b.Type),
+			src:   bytes.TrimSpace(src[start:end]),
+		})
+
+		start = end
+	}
+
+	sort.SliceStable(chunks, func(i, j int) bool {
+		return chunks[i].order < chunks[j].order
+	})
+
+	out := [][]byte{}
+	for _, c := range chunks {
+		out = append(out, c.src)
+	}
+
+	// keep any comments after the last block
+	if trailing := bytes.TrimSpace(src[start:]); len(trailing) > 0 {
+		out = append(out, trailing)
+	}
+
+	return formatSource(bytes.Join(out, []byte("\n\n"))), nil
+}
+
+func formatSource(src []byte) []byte {
+	out := hclwrite.Format(src)
+	return []byte(strings.TrimSpace(string(out)) + "\n")
+}
+
+func blockOrder(t string) int {
+	for i, ft := range formatOrder {
+		if string(ft) == t {
+			return i
+		}
+	}
+
+	return len(formatOrder)
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
+)
+
+func TestFormatAlignsAttributes(t *testing.T) {
+	out, err := Format([]byte(formatUnaligned), "test.hcl")
+	assert.NoError(t, err)
+
+	assert.Equal(t, formatAligned, string(out))
+}
+
+func TestFormatSortsBlocksByTypeAndKeepsComments(t *testing.T) {
+	out, err := Format([]byte(formatUnsorted), "test.hcl")
+	assert.NoError(t, err)
+
+	assert.Equal(t, formatSorted, string(out))
+}
+
+func TestFormatIsIdempotent(t *testing.T) {
+	out, err := Format([]byte(formatUnsorted), "test.hcl")
+	assert.NoError(t, err)
+
+	out2, err := Format(out, "test.hcl")
+	assert.NoError(t, err)
+
+	assert.Equal(t, string(out), string(out2))
+}
+
+func TestFormatDoesNotSortFilesWithAttributes(t *testing.T) {
+	out, err := Format([]byte("title = \"test\"\nauthor=\"nic\"\n"), "test.yard")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "title  = \"test\"\nauthor = \"nic\"\n", string(out))
+}
+
+func TestFormatWithInvalidSourceReturnsParseError(t *testing.T) {
+	_, err := Format([]byte("container \"web\" {\n"), "test.hcl")
+	assert.Error(t, err)
+
+	assert.True(t, xerrors.As(err, &ParseError{}))
+}
+
+const formatUnaligned = `container "consul" {
+    image {
+    name = "consul:1.7.2"
+    }
+  command = ["consul", "agent"]
+  privileged = true
+}
+`
+
+const formatAligned = `container "consul" {
+  image {
+    name = "consul:1.7.2"
+  }
+  command    = ["consul", "agent"]
+  privileged = true
+}
+`
+
+const formatUnsorted = `# Consul server
+container "consul" {
+  image {
+    name = "consul:1.7.2"
+  }
+}
+
+output "addr" {
+  value = "localhost"
+}
+
+// the network for the stack
+network "local" {
+  subnet = "10.5.0.0/16"
+}
+
+variable "version" {
+  default = "1.7.2"
+}
+# end of file
+`
+
+const formatSorted = `variable "version" {
+  default = "1.7.2"
+}
+
+// the network for the stack
+network "local" {
+  subnet = "10.5.0.0/16"
+}
+
+# Consul server
+container "consul" {
+  image {
+    name = "consul:1.7.2"
+  }
+}
+
+output "addr" {
+  value = "localhost"
+}
+
+# end of file
+`
@@ -0,0 +1,378 @@
+package config
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+)
+
+// setFileFunctions sets the functions in the context which read files, relative
+// paths used by these functions are resolved from the folder containing file
+func (p *parser) setFileFunctions(file string) {
+	p.ctx.Functions["file"] = newFileFunc(file)
+	p.ctx.Functions["templatefile"] = newTemplateFileFunc(p.ctx, file)
+	p.ctx.Functions["template_file"] = p.ctx.Functions["templatefile"]
+
+	folder := p.folder
+	if folder == "" && file != "" {
+		folder = filepath.Dir(file)
+	}
+
+	p.ctx.Functions["data_dir"] = newDataDirFunc(folder)
+	p.ctx.Functions["data"] = newDataFunc(folder)
+}
+
+// blueprintDataDir returns the persistent data directory for the blueprint
+// in folder e.g. $HOME/.shipyard/data/vault-k8s-1a2b3c4d, the hash of the
+// path ensures blueprints in folders with the same name do not share data
+func blueprintDataDir(folder string) string {
+	abs, _ := filepath.Abs(folder)
+	h := sha256.Sum256([]byte(abs))
+
+	return filepath.Join(utils.DataDir(), fmt.Sprintf("%s-%x", filepath.Base(abs), h[:4]))
+}
+
+// newDataDirFunc creates a function which returns the data directory for
+// the blueprint, the directory is created if it does not exist
+func newDataDirFunc(folder string) function.Function {
+	return function.New(&function.Spec{
+		Type: function.StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			d := blueprintDataDir(folder)
+
+			err := os.MkdirAll(d, os.ModePerm)
+			if err != nil {
+				return cty.NilVal, fmt.Errorf("Unable to create data directory %s: %s", d, err)
+			}
+
+			return cty.StringVal(d), nil
+		},
+	})
+}
+
+// newDataFunc creates a function which returns a named folder in the data
+// directory for the blueprint, the folder is created if it does not exist
+// and is not removed when the blueprint is destroyed
+func newDataFunc(folder string) function.Function {
+	return function.New(&function.Spec{
+		Params: []function.Parameter{
+			{
+				Name: "name",
+				Type: cty.String,
+			},
+		},
+		Type: function.StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			n := args[0].AsString()
+			if n == "" || n == "." || n == ".." || strings.ContainsAny(n, `/\`) {
+				return cty.NilVal, fmt.Errorf("Invalid data directory name %q, the name must not be empty or contain path separators", n)
+			}
+
+			d := filepath.Join(blueprintDataDir(folder), n)
+
+			err := os.MkdirAll(d, os.ModePerm)
+			if err != nil {
+				return cty.NilVal, fmt.Errorf("Unable to create data directory %s: %s", d, err)
+			}
+
+			return cty.StringVal(d), nil
+		},
+	})
+}
+
+// newFileFunc creates a function which returns the contents of a file as a string
+func newFileFunc(file string) function.Function {
+	return function.New(&function.Spec{
+		Params: []function.Parameter{
+			{
+				Name: "path",
+				Type: cty.String,
+			},
+		},
+		Type: function.StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			p := args[0].AsString()
+			if file != "" {
+				p = ensureAbsolute(p, file)
+			}
+
+			d, err := ioutil.ReadFile(p)
+			if err != nil {
+				return cty.NilVal, fmt.Errorf("Unable to read file %s: %s", p, err)
+			}
+
+			return cty.StringVal(string(d)), nil
+		},
+	})
+}
+
+// newTemplateFileFunc creates a function which renders a template using the
+// given variables, the rendered template is written to the shipyard temp
+// folder and the function returns the path of the rendered file. Templates
+// can use the functions in ctx.
+func newTemplateFileFunc(ctx *hcl.EvalContext, file string) function.Function {
+	return function.New(&function.Spec{
+		Params: []function.Parameter{
+			{
+				Name: "path",
+				Type: cty.String,
+			},
+			{
+				Name: "vars",
+				Type: cty.DynamicPseudoType,
+			},
+		},
+		Type: function.StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			p := args[0].AsString()
+			if file != "" {
+				p = ensureAbsolute(p, file)
+			}
+
+			vars := args[1]
+			if !vars.IsNull() && !vars.Type().IsMapType() && !vars.Type().IsObjectType() {
+				return cty.NilVal, fmt.Errorf("Invalid vars for template %s, vars must be a map", p)
+			}
+
+			d, err := ioutil.ReadFile(p)
+			if err != nil {
+				return cty.NilVal, fmt.Errorf("Unable to read template %s: %s", p, err)
+			}
+
+			expr, diag := hclsyntax.ParseTemplate(d, p, hcl.Pos{Line: 1, Column: 1})
+			if diag.HasErrors() {
+				return cty.NilVal, fmt.Errorf("Unable to parse template %s: %s", p, diag.Error())
+			}
+
+			// templates can only reference the variables passed to the function
+			tctx := &hcl.EvalContext{
+				Variables: map[string]cty.Value{},
+				Functions: map[string]function.Function{},
+			}
+
+			if !vars.IsNull() {
+				tctx.Variables = vars.AsValueMap()
+			}
+
+			for k, f := range ctx.Functions {
+				if k != "templatefile" && k != "template_file" {
+					tctx.Functions[k] = f
+				}
+			}
+
+			v, diag := expr.Value(tctx)
+			if diag.HasErrors() {
+				return cty.NilVal, fmt.Errorf("Unable to render template %s: %s", p, diag.Error())
+			}
+
+			v, err = convert.Convert(v, cty.String)
+			if err != nil || v.IsNull() {
+				return cty.NilVal, fmt.Errorf("Unable to render template %s, template must produce a string", p)
+			}
+
+			out, err := writeTemplate(p, v.AsString())
+			if err != nil {
+				return cty.NilVal, fmt.Errorf("Unable to write rendered template %s: %s", p, err)
+			}
+
+			return cty.StringVal(out), nil
+		},
+	})
+}
+
+// writeTemplate writes a rendered template to the shipyard temp folder, the
+// file name is based on the content so rendering the same template with the
+// same values always returns the same path
+func writeTemplate(path, data string) (string, error) {
+	dir := filepath.Join(utils.ShipyardTemp(), "templates")
+
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.Sum256([]byte(data))
+	out := filepath.Join(dir, fmt.Sprintf("%x-%s", h[:8], filepath.Base(path)))
+
+	return out, ioutil.WriteFile(out, []byte(data), 0644)
+}
+
+// JoinFunc concatenates the elements of a list of strings using a separator
+var JoinFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "separator",
+			Type: cty.String,
+		},
+		{
+			Name: "list",
+			Type: cty.List(cty.String),
+		},
+	},
+	Type: function.StaticReturnType(cty.String),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		items := []string{}
+
+		for it := args[1].ElementIterator(); it.Next(); {
+			_, v := it.Element()
+			if v.IsNull() {
+				return cty.NilVal, fmt.Errorf("Unable to join list, list contains a null value")
+			}
+
+			items = append(items, v.AsString())
+		}
+
+		return cty.StringVal(strings.Join(items, args[0].AsString())), nil
+	},
+})
+
+// SplitFunc divides a string into a list of strings using a separator
+var SplitFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "separator",
+			Type: cty.String,
+		},
+		{
+			Name: "str",
+			Type: cty.String,
+		},
+	},
+	Type: function.StaticReturnType(cty.List(cty.String)),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		parts := strings.Split(args[1].AsString(), args[0].AsString())
+
+		// splitting an empty string with an empty separator returns no parts
+		if len(parts) == 0 {
+			return cty.ListValEmpty(cty.String), nil
+		}
+
+		items := []cty.Value{}
+		for _, p := range parts {
+			items = append(items, cty.StringVal(p))
+		}
+
+		return cty.ListVal(items), nil
+	},
+})
+
+// TrimSpaceFunc removes leading and trailing whitespace from a string
+var TrimSpaceFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "str",
+			Type: cty.String,
+		},
+	},
+	Type: function.StaticReturnType(cty.String),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		return cty.StringVal(strings.TrimSpace(args[0].AsString())), nil
+	},
+})
+
+// ReplaceFunc replaces all occurrences of substr in a string, when substr is
+// wrapped in forward slashes it is treated as a regular expression
+var ReplaceFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "str",
+			Type: cty.String,
+		},
+		{
+			Name: "substr",
+			Type: cty.String,
+		},
+		{
+			Name: "replace",
+			Type: cty.String,
+		},
+	},
+	Type: function.StaticReturnType(cty.String),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		str := args[0].AsString()
+		substr := args[1].AsString()
+		replace := args[2].AsString()
+
+		if len(substr) > 1 && strings.HasPrefix(substr, "/") && strings.HasSuffix(substr, "/") {
+			re, err := regexp.Compile(substr[1 : len(substr)-1])
+			if err != nil {
+				return cty.NilVal, fmt.Errorf("Invalid regular expression %s: %s", substr, err)
+			}
+
+			return cty.StringVal(re.ReplaceAllString(str, replace)), nil
+		}
+
+		return cty.StringVal(strings.Replace(str, substr, replace, -1)), nil
+	},
+})
+
+// MergeFunc combines maps or objects into a single object, when more than one
+// argument defines the same key the value from the last argument is used
+var MergeFunc = function.New(&function.Spec{
+	VarParam: &function.Parameter{
+		Name:      "maps",
+		Type:      cty.DynamicPseudoType,
+		AllowNull: true,
+	},
+	Type: function.StaticReturnType(cty.DynamicPseudoType),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		out := map[string]cty.Value{}
+
+		for i, arg := range args {
+			if arg.IsNull() {
+				continue
+			}
+
+			t := arg.Type()
+			if !t.IsMapType() && !t.IsObjectType() {
+				return cty.NilVal, fmt.Errorf("Unable to merge argument %d, arguments must be maps or objects", i+1)
+			}
+
+			for k, v := range arg.AsValueMap() {
+				out[k] = v
+			}
+		}
+
+		return cty.ObjectVal(out), nil
+	},
+})
+
+// LengthFunc returns the number of elements in a collection or the number of
+// characters in a string
+var LengthFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name:             "value",
+			Type:             cty.DynamicPseudoType,
+			AllowDynamicType: true,
+		},
+	},
+	Type: function.StaticReturnType(cty.Number),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		v := args[0]
+		t := v.Type()
+
+		switch {
+		case t == cty.String:
+			return stdlib.Strlen(v)
+		case t.IsObjectType():
+			return cty.NumberIntVal(int64(len(t.AttributeTypes()))), nil
+		case t.IsListType() || t.IsMapType() || t.IsSetType() || t.IsTupleType():
+			return v.Length(), nil
+		}
+
+		return cty.NilVal, fmt.Errorf("Unable to get the length of %s, value must be a string or a collection", t.FriendlyName())
+	},
+})
@@ -0,0 +1,281 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+	"github.com/stretchr/testify/assert"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func setupFunctionConfig(t *testing.T, files map[string]string, contents ...string) (*Config, error) {
+	dir := createTempDirectory(t)
+	defer removeTestFiles(t, dir)
+
+	for name, data := range files {
+		p := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(p), os.ModePerm)
+
+		err := ioutil.WriteFile(p, []byte(data), os.ModePerm)
+		assert.NoError(t, err)
+	}
+
+	for _, x := range contents {
+		createTestFile(t, dir, x)
+	}
+
+	c := New()
+	err := ParseFolder(dir, c, nil)
+
+	return c, err
+}
+
+func TestFileReadsRelativeToConfig(t *testing.T) {
+	c, err := setupFunctionConfig(t, map[string]string{"scripts/setup.sh": "echo hello"}, fileContainer)
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, "echo hello", co.(*Container).Environment[0].Value)
+}
+
+func TestFileMissingReturnsError(t *testing.T) {
+	_, err := setupFunctionConfig(t, nil, fileContainer)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "setup.sh")
+}
+
+const fileContainer = `
+container "consul" {
+	image {
+		name = "consul:1.7.2"
+	}
+
+	env {
+		key = "SCRIPT"
+		value = file("./scripts/setup.sh")
+	}
+}
+`
+
+func setupTemplateHome(t *testing.T) func() {
+	home := os.Getenv("HOME")
+	os.Setenv("HOME", createTempDirectory(t))
+
+	return func() {
+		removeTestFiles(t, os.Getenv("HOME"))
+		os.Setenv("HOME", home)
+	}
+}
+
+func TestTemplateFileRendersTemplate(t *testing.T) {
+	defer setupTemplateHome(t)()
+
+	c, err := setupFunctionConfig(t, map[string]string{"config/consul.hcl.tmpl": templateConsul}, templateContainer)
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+
+	source := co.(*Container).Volumes[0].Source
+	assert.FileExists(t, source)
+
+	d, err := ioutil.ReadFile(source)
+	assert.NoError(t, err)
+	assert.Equal(t, "datacenter = \"dc2\"\nserver = true\n", string(d))
+}
+
+func TestTemplateFileReturnsSamePathForSameValues(t *testing.T) {
+	defer setupTemplateHome(t)()
+
+	c1, err := setupFunctionConfig(t, map[string]string{"config/consul.hcl.tmpl": templateConsul}, templateContainer)
+	assert.NoError(t, err)
+
+	c2, err := setupFunctionConfig(t, map[string]string{"config/consul.hcl.tmpl": templateConsul}, templateContainer)
+	assert.NoError(t, err)
+
+	assert.Equal(t, c1.Resources[0].(*Container).Volumes[0].Source, c2.Resources[0].(*Container).Volumes[0].Source)
+}
+
+func TestTemplateFileWithMissingVariableReturnsError(t *testing.T) {
+	defer setupTemplateHome(t)()
+
+	_, err := setupFunctionConfig(t, map[string]string{"config/consul.hcl.tmpl": "dc = ${missing}"}, templateContainer)
+	assert.Error(t, err)
+}
+
+const templateConsul = `datacenter = "${datacenter}"
+%{ if server }server = true
+%{ endif }`
+
+const templateContainer = `
+container "consul" {
+	image {
+		name = "consul:1.7.2"
+	}
+
+	volume {
+		source = templatefile("./config/consul.hcl.tmpl", {
+			datacenter = "dc2"
+			server = true
+		})
+		destination = "/config/consul.hcl"
+	}
+}
+`
+
+func evalTestExpression(t *testing.T, src string) (cty.Value, error) {
+	expr, diag := hclsyntax.ParseExpression([]byte(src), "test.hcl", hcl.Pos{Line: 1, Column: 1})
+	if diag.HasErrors() {
+		t.Fatalf("Unable to parse expression %s: %s", src, diag.Error())
+	}
+
+	v, diag := expr.Value(buildContext(""))
+	if diag.HasErrors() {
+		return cty.NilVal, diag
+	}
+
+	return v, nil
+}
+
+func TestStringFunctions(t *testing.T) {
+	tests := map[string]cty.Value{
+		`format("consul:%s", "1.7.2")`:          cty.StringVal("consul:1.7.2"),
+		`upper("consul")`:                       cty.StringVal("CONSUL"),
+		`lower("CONSUL")`:                       cty.StringVal("consul"),
+		`join(",", ["a", "b", "c"])`:            cty.StringVal("a,b,c"),
+		`split(",", "a,b,c")`:                   cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b"), cty.StringVal("c")}),
+		`split("", "")`:                         cty.ListValEmpty(cty.String),
+		`trimspace("  consul \n")`:              cty.StringVal("consul"),
+		`replace("consul-server", "-", "_")`:    cty.StringVal("consul_server"),
+		`replace("consul-1-7", "/[0-9]/", "x")`: cty.StringVal("consul-x-x"),
+	}
+
+	for in, out := range tests {
+		v, err := evalTestExpression(t, in)
+		assert.NoError(t, err, in)
+		assert.True(t, out.RawEquals(v), in)
+	}
+}
+
+func TestReplaceWithInvalidRegexReturnsError(t *testing.T) {
+	_, err := evalTestExpression(t, `replace("consul", "/[/", "x")`)
+	assert.Error(t, err)
+}
+
+func TestDockerFunctionsUseDockerHost(t *testing.T) {
+	dh := os.Getenv("DOCKER_HOST")
+	os.Setenv("DOCKER_HOST", "tcp://10.5.0.2:2376")
+	defer os.Setenv("DOCKER_HOST", dh)
+
+	v, err := evalTestExpression(t, `docker_host()`)
+	assert.NoError(t, err)
+	assert.Equal(t, "tcp://10.5.0.2:2376", v.AsString())
+
+	v, err = evalTestExpression(t, `"http://${docker_ip()}:8080/callback"`)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://10.5.0.2:8080/callback", v.AsString())
+}
+
+func TestCollectionFunctions(t *testing.T) {
+	tests := map[string]cty.Value{
+		`length(["a", "b"])`:                   cty.NumberIntVal(2),
+		`length("consul")`:                     cty.NumberIntVal(6),
+		`length({ a = 1 })`:                    cty.NumberIntVal(1),
+		`join(",", concat(["a"], ["b", "c"]))`: cty.StringVal("a,b,c"),
+		`merge({ a = 1 }, { a = 2, b = 3 })`:   cty.ObjectVal(map[string]cty.Value{"a": cty.NumberIntVal(2), "b": cty.NumberIntVal(3)}),
+		`range(3)`:                             cty.ListVal([]cty.Value{cty.NumberIntVal(0), cty.NumberIntVal(1), cty.NumberIntVal(2)}),
+		`max(1, 5, 3)`:                         cty.NumberIntVal(5),
+		`min(1, 5, 3)`:                         cty.NumberIntVal(1),
+		`abs(-3)`:                              cty.NumberIntVal(3),
+	}
+
+	for in, out := range tests {
+		v, err := evalTestExpression(t, in)
+		assert.NoError(t, err, in)
+		assert.True(t, out.Equals(v).True(), in)
+	}
+}
+
+func TestMergeWithInvalidArgumentReturnsError(t *testing.T) {
+	_, err := evalTestExpression(t, `merge({ a = 1 }, ["b"])`)
+	assert.Error(t, err)
+}
+
+func TestCollectionFunctionsComputeValues(t *testing.T) {
+	c, err := setupFunctionConfig(t, nil, collectionContainer)
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"consul", "consul-0", "consul-1"}, co.(*Container).Networks[0].Aliases)
+}
+
+const collectionContainer = `
+container "consul" {
+	image {
+		name = "consul:1.7.2"
+	}
+
+	network {
+		name = "network.local"
+		aliases = concat(["consul"], [for i in range(2): "consul-${i}"])
+	}
+}
+`
+
+func TestDataFunctionsCreateBlueprintDataDirectory(t *testing.T) {
+	home := createTempDirectory(t)
+	defer removeTestFiles(t, home)
+
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", home)
+	defer os.Setenv("HOME", oldHome)
+
+	c, err := setupFunctionConfig(t, nil, dataContainer)
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+
+	vols := co.(*Container).Volumes
+	assert.True(t, strings.HasPrefix(vols[0].Source, filepath.Join(home, ".shipyard", "data")))
+	assert.DirExists(t, vols[0].Source)
+
+	assert.Equal(t, filepath.Join(vols[0].Source, "certs"), vols[1].Source)
+	assert.DirExists(t, vols[1].Source)
+}
+
+func TestDataDirIsDifferentForEachFolder(t *testing.T) {
+	assert.NotEqual(t, blueprintDataDir("/stacks/a/consul"), blueprintDataDir("/stacks/b/consul"))
+	assert.Equal(t, blueprintDataDir("/stacks/a/consul"), blueprintDataDir("/stacks/a/consul"))
+	assert.True(t, strings.HasPrefix(filepath.Base(blueprintDataDir("/stacks/a/consul")), "consul-"))
+}
+
+func TestDataWithInvalidNameReturnsError(t *testing.T) {
+	_, err := evalTestExpression(t, `data("../certs")`)
+	assert.Error(t, err)
+}
+
+const dataContainer = `
+container "consul" {
+	image {
+		name = "consul"
+	}
+
+	volume {
+		source      = data_dir()
+		destination = "/data"
+	}
+
+	volume {
+		source      = data("certs")
+		destination = "/certs"
+	}
+}
+`
@@ -0,0 +1,85 @@
+package config
+
+import "fmt"
+
+// TypeGitOps is the resource string for a GitOps resource
+const TypeGitOps ResourceType = "gitops"
+
+// GitOps installs ArgoCD or Flux into a Kubernetes cluster and configures it
+// to sync the manifests at a path in a Git repository to the cluster
+type GitOps struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	// Cluster is the cluster to install the tool into e.g. k8s_cluster.k3s
+	Cluster string `hcl:"cluster" json:"cluster"`
+
+	// Tool is the GitOps tool to install, argocd or flux
+	Tool string `hcl:"tool" json:"tool"`
+
+	// Version of the Helm chart used to install the tool, the latest version
+	// is installed when not set
+	Version string `hcl:"version,optional" json:"version,omitempty"`
+
+	// Namespace the tool is installed to, defaults to argocd or flux-system
+	Namespace string `hcl:"namespace,optional" json:"namespace,omitempty"`
+
+	// Repository is the URL of the Git repository to sync
+	Repository string `hcl:"repository" json:"repository"`
+
+	// Revision is the branch, tag or commit to sync, defaults to HEAD for
+	// argocd and main for flux
+	Revision string `hcl:"revision,optional" json:"revision,omitempty"`
+
+	// Path is the folder in the repository containing the manifests
+	Path string `hcl:"path,optional" json:"path,omitempty"`
+
+	// TargetNamespace is the namespace the manifests are applied to,
+	// defaults to default
+	TargetNamespace string `hcl:"target_namespace,optional" json:"target_namespace,omitempty" mapstructure:"target_namespace"`
+
+	// Timeout is the maximum time to wait for the tool to start, defaults to 300s
+	Timeout string `hcl:"timeout,optional" json:"timeout,omitempty"`
+}
+
+// NewGitOps creates a new GitOps config resource
+func NewGitOps(name string) *GitOps {
+	return &GitOps{
+		ResourceInfo:    ResourceInfo{Name: name, Type: TypeGitOps, Status: PendingCreation},
+		Path:            ".",
+		TargetNamespace: "default",
+		Timeout:         "300s",
+	}
+}
+
+// setDefaults sets the namespace and revision for the tool
+func (g *GitOps) setDefaults() {
+	switch g.Tool {
+	case "argocd":
+		if g.Namespace == "" {
+			g.Namespace = "argocd"
+		}
+
+		if g.Revision == "" {
+			g.Revision = "HEAD"
+		}
+	case "flux":
+		if g.Namespace == "" {
+			g.Namespace = "flux-system"
+		}
+
+		if g.Revision == "" {
+			g.Revision = "main"
+		}
+	}
+}
+
+// Validate the GitOps resource and return errors
+func (g *GitOps) Validate() []error {
+	if g.Tool != "argocd" && g.Tool != "flux" {
+		return []error{fmt.Errorf("tool must be argocd or flux, got %s", g.Tool)}
+	}
+
+	return nil
+}
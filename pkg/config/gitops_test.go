@@ -0,0 +1,46 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitOpsCreatesCorrectly(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, gitOpsValid)
+	defer cleanup()
+
+	r, err := c.FindResource("gitops.app")
+	require.NoError(t, err)
+
+	g := r.(*GitOps)
+	assert.Equal(t, "flux", g.Tool)
+	assert.Equal(t, "flux-system", g.Namespace)
+	assert.Equal(t, "main", g.Revision)
+	assert.Equal(t, "./deploy", g.Path)
+	assert.Equal(t, "default", g.TargetNamespace)
+	assert.Contains(t, g.DependsOn, "k8s_cluster.k3s")
+}
+
+func TestGitOpsValidate(t *testing.T) {
+	g := NewGitOps("app")
+	g.Tool = "argocd"
+	assert.Len(t, g.Validate(), 0)
+
+	g.Tool = "jenkins"
+	assert.Len(t, g.Validate(), 1)
+}
+
+const gitOpsValid = `
+k8s_cluster "k3s" {
+	driver = "k3s"
+}
+
+gitops "app" {
+	cluster    = "k8s_cluster.k3s"
+	tool       = "flux"
+	repository = "https://github.com/shipyard-run/demo.git"
+	path       = "./deploy"
+}
+`
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/hashicorp/terraform/dag"
+)
+
+// GraphFormatDOT renders the dependency graph in the Graphviz DOT format
+const GraphFormatDOT = "dot"
+
+// GraphFormatMermaid renders the dependency graph as a Mermaid flowchart
+const GraphFormatMermaid = "mermaid"
+
+// graphEdge is a dependency between two resources, From must be created
+// before To
+type graphEdge struct {
+	From Resource
+	To   Resource
+}
+
+// WriteGraph writes the resources in the dependency graph d created by
+// DoYaLikeDAGs in the given format, nodes are labeled with the type, name,
+// and status of the resource
+func WriteGraph(w io.Writer, d *dag.AcyclicGraph, format string) error {
+	resources, edges := graphResources(d)
+
+	switch format {
+	case GraphFormatDOT:
+		fmt.Fprintln(w, "digraph shipyard {")
+		fmt.Fprintln(w, "  rankdir = \"LR\"")
+
+		for _, r := range resources {
+			fmt.Fprintf(w, "  %q [label=%q]\n", graphID(r), graphLabel(r, "\n"))
+		}
+
+		for _, e := range edges {
+			fmt.Fprintf(w, "  %q -> %q\n", graphID(e.From), graphID(e.To))
+		}
+
+		fmt.Fprintln(w, "}")
+	case GraphFormatMermaid:
+		fmt.Fprintln(w, "graph LR")
+
+		// resource ids can contain characters which are not valid in a
+		// mermaid id, nodes are numbered and the label carries the id
+		ids := map[string]string{}
+		for i, r := range resources {
+			ids[graphID(r)] = fmt.Sprintf("r%d", i)
+			fmt.Fprintf(w, "  %s[\"%s\"]\n", ids[graphID(r)], graphLabel(r, "<br/>"))
+		}
+
+		for _, e := range edges {
+			fmt.Fprintf(w, "  %s --> %s\n", ids[graphID(e.From)], ids[graphID(e.To)])
+		}
+	default:
+		return fmt.Errorf("Invalid graph format %s, format must be %s or %s", format, GraphFormatDOT, GraphFormatMermaid)
+	}
+
+	return nil
+}
+
+// graphResources returns the resources and the edges between them sorted by
+// id, the root Blueprint node and its edges are not part of the output
+func graphResources(d *dag.AcyclicGraph) ([]Resource, []graphEdge) {
+	resources := []Resource{}
+	for _, v := range d.Vertices() {
+		if r, ok := v.(Resource); ok {
+			resources = append(resources, r)
+		}
+	}
+
+	edges := []graphEdge{}
+	for _, e := range d.Edges() {
+		from, fok := e.Source().(Resource)
+		to, tok := e.Target().(Resource)
+		if fok && tok {
+			edges = append(edges, graphEdge{from, to})
+		}
+	}
+
+	sort.Slice(resources, func(i, j int) bool { return graphID(resources[i]) < graphID(resources[j]) })
+	sort.Slice(edges, func(i, j int) bool {
+		if graphID(edges[i].From) == graphID(edges[j].From) {
+			return graphID(edges[i].To) < graphID(edges[j].To)
+		}
+
+		return graphID(edges[i].From) < graphID(edges[j].From)
+	})
+
+	return resources, edges
+}
+
+func graphID(r Resource) string {
+	return ResourceID(r)
+}
+
+func graphLabel(r Resource, newLine string) string {
+	return fmt.Sprintf("%s%s(%s)", graphID(r), newLine, r.Info().Status)
+}
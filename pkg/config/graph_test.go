@@ -0,0 +1,77 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupGraphConfig() *Config {
+	c := New()
+
+	n := NewNetwork("cloud")
+	n.Status = Applied
+	c.AddResource(n)
+
+	co := NewContainer("consul")
+	co.DependsOn = []string{"network.cloud"}
+	co.Module = "consul"
+	c.AddResource(co)
+
+	return c
+}
+
+func TestWriteGraphDOTWritesNodesAndEdges(t *testing.T) {
+	d, err := setupGraphConfig().DoYaLikeDAGs()
+	require.NoError(t, err)
+
+	out := bytes.NewBufferString("")
+	err = WriteGraph(out, d, GraphFormatDOT)
+	require.NoError(t, err)
+
+	assert.Contains(t, out.String(), "digraph shipyard {")
+	assert.Contains(t, out.String(), `"network.cloud" [label="network.cloud\n(applied)"]`)
+	assert.Contains(t, out.String(), `"module.consul.container.consul" [label="module.consul.container.consul\n(pending_creation)"]`)
+	assert.Contains(t, out.String(), `"network.cloud" -> "module.consul.container.consul"`)
+	assert.NotContains(t, out.String(), "Blueprint")
+}
+
+func TestWriteGraphMermaidWritesNodesAndEdges(t *testing.T) {
+	d, err := setupGraphConfig().DoYaLikeDAGs()
+	require.NoError(t, err)
+
+	out := bytes.NewBufferString("")
+	err = WriteGraph(out, d, GraphFormatMermaid)
+	require.NoError(t, err)
+
+	assert.Contains(t, out.String(), "graph LR")
+	assert.Contains(t, out.String(), `r0["module.consul.container.consul<br/>(pending_creation)"]`)
+	assert.Contains(t, out.String(), `r1["network.cloud<br/>(applied)"]`)
+	assert.Contains(t, out.String(), "r1 --> r0")
+}
+
+func TestWriteGraphMermaidDoesNotCollideForSimilarNames(t *testing.T) {
+	c := New()
+	c.AddResource(NewContainer("web-1"))
+	c.AddResource(NewContainer("web_1"))
+
+	d, err := c.DoYaLikeDAGs()
+	require.NoError(t, err)
+
+	out := bytes.NewBufferString("")
+	err = WriteGraph(out, d, GraphFormatMermaid)
+	require.NoError(t, err)
+
+	assert.Contains(t, out.String(), `r0["container.web-1<br/>(pending_creation)"]`)
+	assert.Contains(t, out.String(), `r1["container.web_1<br/>(pending_creation)"]`)
+}
+
+func TestWriteGraphInvalidFormatReturnsError(t *testing.T) {
+	d, err := setupGraphConfig().DoYaLikeDAGs()
+	require.NoError(t, err)
+
+	err = WriteGraph(bytes.NewBufferString(""), d, "png")
+	assert.Error(t, err)
+}
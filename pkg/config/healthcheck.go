@@ -8,6 +8,7 @@ package config
 //    services 		= ["consul-consul"]                                              // does service exist and there are endpoints
 //    pods     		= ["component=server,app=consul", "component=client,app=consul"] // is the pod running and healthy
 //    nomad_jobs = ["redis"] 																										   // are the Nomad jobs running and healthy
+//    exec     		= ["consul", "members"]                                          // does the command exit with 0 in the container
 type HealthCheck struct {
 	Timeout   string   `hcl:"timeout" json:"timeout"`
 	HTTP      string   `hcl:"http,optional" json:"http,omitempty"`
@@ -15,4 +16,7 @@ type HealthCheck struct {
 	Services  []string `hcl:"services,optional" json:"services,omitempty"`
 	Pods      []string `hcl:"pods,optional" json:"pods,omitempty"`
 	NomadJobs []string `hcl:"nomad_jobs,optional" json:"nomad_jobs,omitempty" mapstructure:"nomad_jobs"`
+	// Exec is a command run in the container, or the server node of a cluster,
+	// the check passes when the command exits with 0
+	Exec []string `hcl:"exec,optional" json:"exec,omitempty"`
 }
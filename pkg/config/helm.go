@@ -9,8 +9,18 @@ type Helm struct {
 
 	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
 
-	Cluster      string            `hcl:"cluster" json:"cluster"`
-	Chart        string            `hcl:"chart" json:"chart"`
+	Cluster string `hcl:"cluster" json:"cluster"`
+	Chart   string `hcl:"chart" json:"chart"`
+
+	// Repository is the Helm repository to install the chart from, either
+	// a helm_repository resource e.g. helm_repository.hashicorp or the url
+	// of the repository. When set Chart is the name of the chart in the
+	// repository.
+	Repository string `hcl:"repository,optional" json:"repository,omitempty"`
+	// Version of the chart to install from the repository, defaults to the
+	// latest version
+	Version string `hcl:"version,optional" json:"version,omitempty"`
+
 	Values       string            `hcl:"values,optional" json:"values"`
 	ValuesString map[string]string `hcl:"values_string,optional" json:"values_string"`
 
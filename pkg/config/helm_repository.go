@@ -0,0 +1,25 @@
+package config
+
+// TypeHelmRepository is the resource string for a HelmRepository resource
+const TypeHelmRepository ResourceType = "helm_repository"
+
+// HelmRepository defines a Helm chart repository, helm resources which
+// reference the repository install charts from it
+type HelmRepository struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	// URL of the repository e.g. https://helm.releases.hashicorp.com
+	URL string `hcl:"url" json:"url"`
+
+	// Username and Password are used for repositories which require
+	// basic authentication
+	Username string `hcl:"username,optional" json:"username,omitempty"`
+	Password string `hcl:"password,optional" json:"password,omitempty" sensitive:"true"`
+}
+
+// NewHelmRepository creates a new HelmRepository config resource
+func NewHelmRepository(name string) *HelmRepository {
+	return &HelmRepository{ResourceInfo: ResourceInfo{Name: name, Type: TypeHelmRepository, Status: PendingCreation}}
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHelmRepositoryCreatesCorrectly(t *testing.T) {
+	defer ResetSensitive()
+
+	c, _, cleanup := setupTestConfig(t, helmRepositoryValid)
+	defer cleanup()
+
+	hr, err := c.FindResource("helm_repository.hashicorp")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "https://helm.releases.hashicorp.com", hr.(*HelmRepository).URL)
+	assert.True(t, IsSensitive("secret"))
+
+	h, err := c.FindResource("helm.consul")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "consul", h.(*Helm).Chart)
+	assert.Equal(t, "0.30.0", h.(*Helm).Version)
+	assert.Contains(t, h.Info().DependsOn, "helm_repository.hashicorp")
+}
+
+func TestHelmVersionWithoutRepositoryReturnsError(t *testing.T) {
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+
+	createNamedFile(t, dir, "*.hcl", `
+helm "consul" {
+	cluster = "k8s_cluster.k3s"
+	chart   = "./consul"
+	version = "0.30.0"
+}
+`)
+
+	err := ParseFolder(dir, &Config{}, nil)
+	assert.Error(t, err)
+}
+
+const helmRepositoryValid = `
+k8s_cluster "k3s" {
+	driver = "k3s"
+}
+
+helm_repository "hashicorp" {
+	url      = "https://helm.releases.hashicorp.com"
+	username = "admin"
+	password = "secret"
+}
+
+helm "consul" {
+	cluster    = "k8s_cluster.k3s"
+	repository = "helm_repository.hashicorp"
+	chart      = "consul"
+	version    = "0.30.0"
+}
+`
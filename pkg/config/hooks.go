@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl2/hcl"
+)
+
+// HookBefore runs a lifecycle hook before the resource is created or destroyed
+const HookBefore = "before"
+
+// HookAfter runs a lifecycle hook after the resource is created or destroyed
+const HookAfter = "after"
+
+// Hook is a command which is run on the local machine when a resource is
+// created or destroyed, set with the on_create and on_destroy blocks
+type Hook struct {
+	// When is either before or after, defaults to after
+	When string `hcl:"when,optional" json:"when,omitempty"`
+
+	Command   string   `hcl:"cmd" json:"cmd"`                      // Command to execute
+	Arguments []string `hcl:"args,optional" json:"args,omitempty"` // Arguments for the command
+}
+
+// Runs returns true when the hook runs at when
+func (h Hook) Runs(when string) bool {
+	if h.When == "" {
+		return when == HookAfter
+	}
+
+	return h.When == when
+}
+
+// blockHooks decodes the on_create and on_destroy blocks of a block, commands
+// which are relative paths e.g. ./seed.sh are relative to the file
+func (p *parser) blockHooks(b *hcl.Block, file string) ([]Hook, []Hook, error) {
+	if len(b.Labels) == 0 {
+		return nil, nil, nil
+	}
+
+	content, _, diag := b.Body.PartialContent(metaSchema)
+	if diag.HasErrors() {
+		return nil, nil, fmt.Errorf("Unable to decode %s: %s", b.Type, diag.Error())
+	}
+
+	hooks := map[string][]Hook{}
+	for _, hb := range content.Blocks {
+		h := Hook{}
+
+		err := p.decodeBody(hb, &h)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if h.When != "" && h.When != HookBefore && h.When != HookAfter {
+			return nil, nil, fmt.Errorf("Invalid when for %s in %s %s, when must be before or after", hb.Type, b.Type, strings.Join(b.Labels, "."))
+		}
+
+		if strings.HasPrefix(h.Command, ".") {
+			h.Command = ensureAbsolute(h.Command, file)
+		}
+
+		hooks[hb.Type] = append(hooks[hb.Type], h)
+	}
+
+	return hooks["on_create"], hooks["on_destroy"], nil
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHooksSetsHooksOnResource(t *testing.T) {
+	c, err := setupVariableConfig(t, nil, hooksContainer)
+	require.NoError(t, err)
+
+	co, err := c.FindResource("container.web")
+	require.NoError(t, err)
+
+	require.Len(t, co.Info().OnCreate, 2)
+	assert.Equal(t, HookBefore, co.Info().OnCreate[0].When)
+	assert.Equal(t, "mkdir", co.Info().OnCreate[0].Command)
+	assert.Equal(t, []string{"-p", "/tmp/web"}, co.Info().OnCreate[0].Arguments)
+	assert.Equal(t, "curl", co.Info().OnCreate[1].Command)
+
+	require.Len(t, co.Info().OnDestroy, 1)
+	assert.Equal(t, "rm", co.Info().OnDestroy[0].Command)
+}
+
+func TestHooksMakesRelativeCommandsAbsolute(t *testing.T) {
+	c, err := setupVariableConfig(t, nil, `
+container "web" {
+  image {
+    name = "nginx"
+  }
+
+  on_create {
+    cmd = "./seed.sh"
+  }
+}
+`)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, "./seed.sh", c.Resources[0].Info().OnCreate[0].Command)
+	assert.Contains(t, c.Resources[0].Info().OnCreate[0].Command, "seed.sh")
+}
+
+func TestHooksInvalidWhenReturnsError(t *testing.T) {
+	_, err := setupVariableConfig(t, nil, `
+container "web" {
+  image {
+    name = "nginx"
+  }
+
+  on_destroy {
+    when = "during"
+    cmd  = "rm"
+  }
+}
+`)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "when must be before or after")
+}
+
+func TestHookRunsDefaultsToAfter(t *testing.T) {
+	h := Hook{Command: "ls"}
+
+	assert.True(t, h.Runs(HookAfter))
+	assert.False(t, h.Runs(HookBefore))
+}
+
+func TestHooksAreLoadedFromState(t *testing.T) {
+	c, err := setupVariableConfig(t, nil, hooksContainer)
+	require.NoError(t, err)
+
+	d, err := json.Marshal(c)
+	require.NoError(t, err)
+
+	sc := New()
+	err = json.Unmarshal(d, sc)
+	require.NoError(t, err)
+
+	co, err := sc.FindResource("container.web")
+	require.NoError(t, err)
+
+	assert.Equal(t, c.Resources[0].Info().OnCreate, co.Info().OnCreate)
+	assert.Equal(t, c.Resources[0].Info().OnDestroy, co.Info().OnDestroy)
+}
+
+const hooksContainer = `
+container "web" {
+  image {
+    name = "nginx"
+  }
+
+  on_create {
+    when = "before"
+    cmd  = "mkdir"
+    args = ["-p", "/tmp/web"]
+  }
+
+  on_create {
+    cmd  = "curl"
+    args = ["-X", "POST", "http://localhost:8080/seed"]
+  }
+
+  on_destroy {
+    cmd  = "rm"
+    args = ["-rf", "/tmp/web"]
+  }
+}
+`
@@ -0,0 +1,38 @@
+package config
+
+// TypeHTTPCheck is the resource string for a HTTPCheck resource
+const TypeHTTPCheck ResourceType = "http_check"
+
+// HTTPCheck polls a HTTP or TCP endpoint until it is healthy, resources
+// which depend on the check are not created until it succeeds
+type HTTPCheck struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	// HTTP is the url of the endpoint to check e.g. http://localhost:8500/v1/status/leader
+	HTTP string `hcl:"http,optional" json:"http,omitempty"`
+	// TCP is the address to open a connection to e.g. localhost:5432
+	TCP string `hcl:"tcp,optional" json:"tcp,omitempty"`
+
+	// Method is the HTTP method used for the request, defaults to GET
+	Method string `hcl:"method,optional" json:"method,omitempty"`
+	// StatusCodes are the HTTP status codes which are considered healthy,
+	// defaults to 200
+	StatusCodes []int `hcl:"status_codes,optional" json:"status_codes,omitempty" mapstructure:"status_codes"`
+	// Body is a string which the response body must contain
+	Body string `hcl:"body,optional" json:"body,omitempty"`
+
+	// Timeout is the maximum time to wait for the endpoint, defaults to 30s
+	Timeout string `hcl:"timeout,optional" json:"timeout,omitempty"`
+}
+
+// NewHTTPCheck creates a new HTTPCheck config resource
+func NewHTTPCheck(name string) *HTTPCheck {
+	return &HTTPCheck{
+		ResourceInfo: ResourceInfo{Name: name, Type: TypeHTTPCheck, Status: PendingCreation},
+		Method:       "GET",
+		StatusCodes:  []int{200},
+		Timeout:      "30s",
+	}
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPCheckCreatesCorrectly(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, httpCheckValid)
+	defer cleanup()
+
+	r, err := c.FindResource("http_check.consul")
+	assert.NoError(t, err)
+
+	hc := r.(*HTTPCheck)
+	assert.Equal(t, "http://localhost:8500/v1/status/leader", hc.HTTP)
+	assert.Equal(t, []int{200, 204}, hc.StatusCodes)
+	assert.Equal(t, "GET", hc.Method)
+	assert.Equal(t, "30s", hc.Timeout)
+	assert.Contains(t, hc.DependsOn, "container.consul")
+}
+
+func TestHTTPCheckWithoutEndpointReturnsError(t *testing.T) {
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+
+	createNamedFile(t, dir, "*.hcl", httpCheckNoEndpoint)
+
+	err := ParseFolder(dir, &Config{}, nil)
+	assert.Error(t, err)
+}
+
+const httpCheckValid = `
+container "consul" {
+	image {
+		name = "consul:1.8.0"
+	}
+}
+
+http_check "consul" {
+	http         = "http://localhost:8500/v1/status/leader"
+	status_codes = [200, 204]
+
+	depends_on = ["container.consul"]
+}
+`
+
+const httpCheckNoEndpoint = `
+http_check "consul" {
+	timeout = "10s"
+}
+`
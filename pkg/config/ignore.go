@@ -0,0 +1,194 @@
+package config
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ignoreFile is the name of the file which contains patterns for files in a
+// folder that should not be parsed, patterns use the gitignore syntax
+const ignoreFile = ".shipyardignore"
+
+// ignoreRule is a single pattern from an ignore file
+type ignoreRule struct {
+	pattern *regexp.Regexp
+	// negate re-includes files matched by a previous pattern e.g. !keep.hcl
+	negate bool
+	// dirOnly patterns end in a / and only match directories
+	dirOnly bool
+	// anchored patterns contain a / and are matched against the path relative
+	// to the folder, other patterns are matched against the base name
+	anchored bool
+}
+
+// ignoreRules are the patterns from an ignore file in the order defined
+type ignoreRules []ignoreRule
+
+// readIgnoreFile reads the ignore file in a folder, when the folder does
+// not contain an ignore file no rules are returned
+func readIgnoreFile(folder string) (ignoreRules, error) {
+	f, err := os.Open(filepath.Join(folder, ignoreFile))
+	if os.IsNotExist(err) {
+		return ignoreRules{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	rules := ignoreRules{}
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		r := ignoreRule{}
+
+		if strings.HasPrefix(line, "!") {
+			r.negate = true
+			line = line[1:]
+		}
+
+		if strings.HasSuffix(line, "/") {
+			r.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+
+		if strings.Contains(line, "/") {
+			r.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+
+		p, err := regexp.Compile("^" + globToRegexp(line) + "$")
+		if err != nil {
+			return nil, err
+		}
+
+		r.pattern = p
+		rules = append(rules, r)
+	}
+
+	return rules, s.Err()
+}
+
+// ignored returns true when the path relative to the folder containing the
+// ignore file should be ignored, a file is also ignored when any of the
+// folders containing it are ignored
+func (ir ignoreRules) ignored(rel string, isDir bool) bool {
+	rel = filepath.ToSlash(rel)
+	parts := strings.Split(rel, "/")
+
+	for i := 1; i < len(parts); i++ {
+		if ir.match(strings.Join(parts[:i], "/"), true) {
+			return true
+		}
+	}
+
+	return ir.match(rel, isDir)
+}
+
+// match returns the result of the last rule which matches the path
+func (ir ignoreRules) match(rel string, isDir bool) bool {
+	ignored := false
+
+	for _, r := range ir {
+		if r.dirOnly && !isDir {
+			continue
+		}
+
+		p := rel
+		if !r.anchored {
+			p = filepath.Base(rel)
+		}
+
+		if r.pattern.MatchString(p) {
+			ignored = !r.negate
+		}
+	}
+
+	return ignored
+}
+
+// globToRegexp converts a gitignore glob to a regular expression,
+// ** matches any number of folders and * matches within a single folder
+func globToRegexp(glob string) string {
+	sb := strings.Builder{}
+
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			sb.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "/**"):
+			sb.WriteString("(/.*)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i:], ']')
+			if end < 0 {
+				sb.WriteString(`\[`)
+				continue
+			}
+
+			class := glob[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+
+			sb.WriteString("[" + class + "]")
+			i += end
+		case c == '\\' && i+1 < len(glob):
+			sb.WriteString(regexp.QuoteMeta(string(glob[i+1])))
+			i++
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	return sb.String()
+}
+
+// globFiles returns the files in the folder which match any of the patterns
+// and are not ignored by the rules, the rules are relative to root. The files
+// are sorted lexically by name.
+func globFiles(root, folder string, rules ignoreRules, patterns ...string) ([]string, error) {
+	files := []string{}
+
+	for _, p := range patterns {
+		f, err := filepath.Glob(filepath.Join(folder, p))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range f {
+			rel, err := filepath.Rel(root, file)
+			if err != nil {
+				return nil, err
+			}
+
+			if !rules.ignored(rel, false) {
+				files = append(files, file)
+			}
+		}
+	}
+
+	sort.Strings(files)
+
+	return files, nil
+}
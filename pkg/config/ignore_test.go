@@ -0,0 +1,100 @@
+package config
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupIgnoreRules(t *testing.T, contents string) ignoreRules {
+	dir := createTempDirectory(t)
+	defer removeTestFiles(t, dir)
+
+	err := ioutil.WriteFile(filepath.Join(dir, ignoreFile), []byte(contents), 0644)
+	assert.NoError(t, err)
+
+	rules, err := readIgnoreFile(dir)
+	assert.NoError(t, err)
+
+	return rules
+}
+
+func TestIgnoreRulesWithNoFileIgnoresNothing(t *testing.T) {
+	dir := createTempDirectory(t)
+	defer removeTestFiles(t, dir)
+
+	rules, err := readIgnoreFile(dir)
+	assert.NoError(t, err)
+
+	assert.False(t, rules.ignored("main.hcl", false))
+}
+
+func TestIgnoreRulesMatchPatterns(t *testing.T) {
+	rules := setupIgnoreRules(t, ignoreRulesFile)
+
+	tc := map[string]bool{
+		"main.hcl":                false,
+		"test.hcl":                true,
+		"sub/test.hcl":            true,
+		"fixture_a.hcl":           true,
+		"keep_fixture.hcl":        false,
+		"docs/index.hcl":          true,
+		"charts/consul/chart.hcl": true,
+		"charts.hcl":              false,
+		"build/out.hcl":           true,
+		"src/build/out.hcl":       false,
+		"a/b/c/generated.hcl":     true,
+	}
+
+	for p, expected := range tc {
+		assert.Equal(t, expected, rules.ignored(p, false), p)
+	}
+}
+
+func TestIgnoreRulesNegationReIncludesFile(t *testing.T) {
+	rules := setupIgnoreRules(t, ignoreRulesFile)
+
+	assert.True(t, rules.ignored("fixture_b.hcl", false))
+	assert.False(t, rules.ignored("fixture_keep.hcl", false))
+}
+
+func TestParseFolderSkipsIgnoredFiles(t *testing.T) {
+	dir := createTempDirectory(t)
+	defer removeTestFiles(t, dir)
+
+	createNamedFile(t, dir, "main*.hcl", ignoreNetwork)
+	createNamedFile(t, dir, "fixture*.hcl", "this is not valid hcl {")
+
+	err := ioutil.WriteFile(filepath.Join(dir, ignoreFile), []byte("fixture*.hcl\n"), 0644)
+	assert.NoError(t, err)
+
+	files, err := ConfigFiles(dir, 0)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	c := New()
+	err = ParseFolder(dir, c, nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, c.ResourceCount())
+}
+
+const ignoreRulesFile = `
+# test files
+test.hcl
+fixture*.hcl
+!fixture_keep.hcl
+
+docs/
+/charts
+/build/
+**/generated.hcl
+`
+
+const ignoreNetwork = `
+network "cloud" {
+	subnet = "10.0.0.0/16"
+}
+`
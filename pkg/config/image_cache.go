@@ -0,0 +1,47 @@
+package config
+
+// TypeImageCache is the resource string for an ImageCache resource
+const TypeImageCache ResourceType = "image_cache"
+
+// ImageCache runs a pull through registry cache, Kubernetes and Nomad
+// clusters attached to the same network as the cache pull images through
+// it so that images are only downloaded once
+type ImageCache struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	Networks []NetworkAttachment `hcl:"network,block" json:"networks,omitempty"` // networks to attach the cache to, clusters must share a network with the cache
+
+	// RemoteURL is the registry which is cached, defaults to Docker Hub
+	RemoteURL string `hcl:"remote_url,optional" json:"remote_url,omitempty" mapstructure:"remote_url"`
+}
+
+// NewImageCache creates a new ImageCache config resource
+func NewImageCache(name string) *ImageCache {
+	return &ImageCache{
+		ResourceInfo: ResourceInfo{Name: name, Type: TypeImageCache, Status: PendingCreation},
+		RemoteURL:    "https://registry-1.docker.io",
+	}
+}
+
+// FindImageCache returns the image cache attached to one of the given
+// networks, nil is returned when no image cache shares a network
+func (c *Config) FindImageCache(networks []NetworkAttachment) *ImageCache {
+	for _, r := range c.Resources {
+		ic, ok := r.(*ImageCache)
+		if !ok {
+			continue
+		}
+
+		for _, in := range ic.Networks {
+			for _, n := range networks {
+				if in.Name == n.Name {
+					return ic
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageCacheCreatesCorrectly(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, imageCacheValid)
+	defer cleanup()
+
+	ic, err := c.FindResource("image_cache.docker")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "docker", ic.Info().Name)
+	assert.Equal(t, TypeImageCache, ic.Info().Type)
+	assert.Equal(t, "https://registry-1.docker.io", ic.(*ImageCache).RemoteURL)
+	assert.Contains(t, ic.Info().DependsOn, "network.cloud")
+}
+
+func TestClusterSharingNetworkDependsOnImageCache(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, imageCacheValid)
+	defer cleanup()
+
+	k8s, err := c.FindResource("k8s_cluster.k3s")
+	assert.NoError(t, err)
+	assert.Contains(t, k8s.Info().DependsOn, "image_cache.docker")
+
+	other, err := c.FindResource("k8s_cluster.other")
+	assert.NoError(t, err)
+	assert.NotContains(t, other.Info().DependsOn, "image_cache.docker")
+}
+
+const imageCacheValid = `
+network "cloud" {
+	subnet = "10.5.0.0/16"
+}
+
+network "other" {
+	subnet = "10.6.0.0/16"
+}
+
+image_cache "docker" {
+	network {
+		name = "network.cloud"
+	}
+}
+
+k8s_cluster "k3s" {
+	driver = "k3s"
+
+	network {
+		name = "network.cloud"
+	}
+}
+
+k8s_cluster "other" {
+	driver = "k3s"
+
+	network {
+		name = "network.other"
+	}
+}
+`
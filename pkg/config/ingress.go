@@ -1,14 +1,17 @@
 package config
 
+import "fmt"
+
 // TypeIngress is the resource string for the type
 const TypeIngress ResourceType = "ingress"
 
 // Ingress defines an ingress service mapping ports between local host or docker network and the target
 // Note: This type is Deprecated and will be removed in a later version
-//       Please use one of the new specific types:
-//       * K8sIngress
-//       * NomadIngress
-//       * ContainerIngress
+//
+//	Please use one of the new specific types:
+//	* K8sIngress
+//	* NomadIngress
+//	* ContainerIngress
 type Ingress struct {
 	ResourceInfo
 
@@ -20,9 +23,30 @@ type Ingress struct {
 	Service   string `hcl:"service,optional" json:"service,omitempty"`
 	Namespace string `hcl:"namespace,optional" json:"namespace,omitempty"`
 	Ports     []Port `hcl:"port,block" json:"ports,omitempty"`
+
+	// PortRanges are added to the ports when the config is parsed
+	PortRanges []PortRange `hcl:"port_range,block" json:"port_ranges,omitempty" mapstructure:"port_ranges"`
+
+	// Protocol is the type of traffic proxied by the ingress, http or tcp,
+	// tcp passes raw connections through to the target e.g. databases
+	Protocol string `hcl:"protocol,optional" json:"protocol,omitempty"`
 }
 
 // NewIngress creates a new ingress with the correct defaults
 func NewIngress(name string) *Ingress {
 	return &Ingress{ResourceInfo: ResourceInfo{Name: name, Type: TypeIngress, Status: PendingCreation}}
 }
+
+// Validate the Ingress and return errors
+func (i *Ingress) Validate() []error {
+	return validateIngressProtocol(i.Protocol)
+}
+
+// validateIngressProtocol checks the protocol of an ingress is supported
+func validateIngressProtocol(p string) []error {
+	if p != "" && p != "http" && p != "tcp" {
+		return []error{fmt.Errorf("protocol must be http or tcp, got %s", p)}
+	}
+
+	return nil
+}
@@ -18,6 +18,21 @@ func TestIngressCreatesCorrectly(t *testing.T) {
 	assert.Equal(t, PendingCreation, cl.Info().Status)
 }
 
+func TestIngressWithPortRangeAddsPorts(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, ingressPortRange)
+	defer cleanup()
+
+	i, err := c.FindResource("ingress.grpc")
+	assert.NoError(t, err)
+
+	ports := i.(*Ingress).Ports
+	assert.Len(t, ports, 3)
+	assert.Equal(t, "9000", ports[0].Local)
+	assert.Equal(t, "9000", ports[0].Remote)
+	assert.Equal(t, "", ports[0].Host)
+	assert.Equal(t, "9002", ports[2].Remote)
+}
+
 const ingressDefault = `
 network "test" {
 	subnet = "10.0.0.0/24"
@@ -34,3 +49,19 @@ ingress "testing" {
 	target = "cluster.testing"
 }
 `
+
+const ingressPortRange = `
+container "grpc" {
+	image {
+		name = "grpc"
+	}
+}
+
+ingress "grpc" {
+	target = "container.grpc"
+
+	port_range {
+		range = "9000-9002"
+	}
+}
+`
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFolderReadsJSONFiles(t *testing.T) {
+	dir := createTempDirectory(t)
+	defer removeTestFiles(t, dir)
+
+	createNamedFile(t, dir, "*.hcl.json", jsonNetwork)
+	createNamedFile(t, dir, "*.hcl.json", jsonContainer)
+
+	c := New()
+	err := ParseFolder(dir, c, map[string]string{"version": "1.8.0"})
+	assert.NoError(t, err)
+
+	_, err = c.FindResource("network.local")
+	assert.NoError(t, err)
+
+	for _, n := range []string{"container.consul-0", "container.consul-1"} {
+		co, err := c.FindResource(n)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "consul:1.8.0", co.(*Container).Image.Name)
+		assert.Equal(t, "network.local", co.(*Container).Networks[0].Name)
+		assert.Contains(t, co.Info().DependsOn, "network.local")
+	}
+}
+
+func TestParseHCLFileReadsJSONFile(t *testing.T) {
+	dir := createTempDirectory(t)
+	defer removeTestFiles(t, dir)
+
+	f := createNamedFile(t, dir, "*.json", jsonNetwork)
+
+	c := New()
+	err := ParseHCLFile(f, c, nil)
+	assert.NoError(t, err)
+
+	n, err := c.FindResource("network.local")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.5.0.0/16", n.(*Network).Subnet)
+}
+
+func TestParseHCLFileWithUnknownJSONBlockReturnsError(t *testing.T) {
+	dir := createTempDirectory(t)
+	defer removeTestFiles(t, dir)
+
+	f := createNamedFile(t, dir, "*.json", `{"unknown": {"local": {}}}`)
+
+	c := New()
+	err := ParseHCLFile(f, c, nil)
+	assert.Error(t, err)
+}
+
+const jsonNetwork = `
+{
+  "network": {
+    "local": {
+      "subnet": "10.5.0.0/16"
+    }
+  }
+}
+`
+
+const jsonContainer = `
+{
+  "variable": {
+    "version": {
+      "type": "string",
+      "default": "1.7.2"
+    }
+  },
+  "container": {
+    "consul": {
+      "count": 2,
+      "image": {
+        "name": "consul:${var.version}"
+      },
+      "network": {
+        "name": "${resources.network.local.id}"
+      }
+    }
+  }
+}
+`
@@ -1,5 +1,7 @@
 package config
 
+import "fmt"
+
 // TypeK8sCluster is the resource string for a Cluster resource
 const TypeK8sCluster ResourceType = "k8s_cluster"
 
@@ -14,11 +16,52 @@ type K8sCluster struct {
 
 	Driver  string  `hcl:"driver" json:"driver,omitempty"`
 	Version string  `hcl:"version,optional" json:"version,omitempty"`
-	Nodes   int     `hcl:"nodes,optional" json:"nodes,omitempty"`
 	Images  []Image `hcl:"image,block" json:"images,omitempty"`
+
+	// Nodes is the total number of nodes in the cluster, the first node is
+	// the server and the remaining nodes are agents
+	Nodes int `hcl:"nodes,optional" json:"nodes,omitempty"`
+
+	// Resources are the constraints applied to each node
+	Resources *Resources `hcl:"resources,block" json:"resources,omitempty"`
+
+	// HealthCheck defines the checks which must pass before the cluster is ready
+	HealthCheck *HealthCheck `hcl:"health_check,block" json:"health_check,omitempty" mapstructure:"health_check"`
+
+	// DockerHost is the address of the Docker engine the cluster is created on
+	// e.g. tcp://10.5.0.2:2376 or ssh://user@host, defaults to the local engine
+	DockerHost string `hcl:"docker_host,optional" json:"docker_host,omitempty" mapstructure:"docker_host"`
 }
 
 // NewK8sCluster creates new Cluster config with the correct defaults
 func NewK8sCluster(name string) *K8sCluster {
 	return &K8sCluster{ResourceInfo: ResourceInfo{Name: name, Type: TypeK8sCluster, Status: PendingCreation}}
 }
+
+// Agents returns the number of agent nodes in the cluster
+func (k *K8sCluster) Agents() int {
+	if k.Nodes <= 1 {
+		return 0
+	}
+
+	return k.Nodes - 1
+}
+
+// Validate the K8sCluster and return errors
+func (k *K8sCluster) Validate() []error {
+	errs := []error{}
+
+	if k.Driver != "k3s" && k.Driver != "kind" {
+		errs = append(errs, fmt.Errorf("driver must be k3s or kind, got %s", k.Driver))
+	}
+
+	if k.Nodes < 0 {
+		errs = append(errs, fmt.Errorf("nodes must be greater than 0, got %d", k.Nodes))
+	}
+
+	if err := validateDockerHost(k.DockerHost); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/shipyard-run/shipyard/pkg/utils"
+)
+
+// TypeK8sClusterExternal is the resource string for a K8sClusterExternal resource
+const TypeK8sClusterExternal ResourceType = "k8s_cluster_external"
+
+// K8sClusterExternal is an existing Kubernetes cluster which was not created
+// by Shipyard, it can be the target for helm, k8s_config and ingress
+// resources. The cluster is never modified when the resource is destroyed.
+type K8sClusterExternal struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	// KubeConfig is the path to the Kubernetes config file, defaults to the
+	// first file in $KUBECONFIG or $HOME/.kube/config
+	KubeConfig string `hcl:"kubeconfig,optional" json:"kubeconfig,omitempty"`
+
+	// Context in the config file to use, defaults to the current context
+	Context string `hcl:"context,optional" json:"context,omitempty"`
+}
+
+// NewK8sClusterExternal creates a new K8sClusterExternal config resource
+func NewK8sClusterExternal(name string) *K8sClusterExternal {
+	return &K8sClusterExternal{ResourceInfo: ResourceInfo{Name: name, Type: TypeK8sClusterExternal, Status: PendingCreation}}
+}
+
+func (k *K8sClusterExternal) setDefaults() {
+	if strings.HasPrefix(k.KubeConfig, "~/") {
+		k.KubeConfig = filepath.Join(utils.HomeFolder(), k.KubeConfig[2:])
+	}
+
+	if k.KubeConfig != "" {
+		return
+	}
+
+	if kc := os.Getenv("KUBECONFIG"); kc != "" {
+		k.KubeConfig = strings.Split(kc, string(os.PathListSeparator))[0]
+		return
+	}
+
+	k.KubeConfig = filepath.Join(utils.HomeFolder(), ".kube", "config")
+}
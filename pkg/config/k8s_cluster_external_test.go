@@ -0,0 +1,60 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestK8sClusterExternalCreatesCorrectly(t *testing.T) {
+	c, base, cleanup := setupTestConfig(t, clusterExternal)
+	defer cleanup()
+
+	cl, err := c.FindResource("k8s_cluster_external.staging")
+	require.NoError(t, err)
+
+	assert.Equal(t, filepath.Join(base, "kubeconfig.yaml"), cl.(*K8sClusterExternal).KubeConfig)
+	assert.Equal(t, "staging", cl.(*K8sClusterExternal).Context)
+
+	h, err := c.FindResource("helm.consul")
+	require.NoError(t, err)
+	assert.Contains(t, h.Info().DependsOn, "k8s_cluster_external.staging")
+}
+
+func TestK8sClusterExternalDefaultsKubeConfig(t *testing.T) {
+	kc := os.Getenv("KUBECONFIG")
+	defer os.Setenv("KUBECONFIG", kc)
+
+	os.Setenv("KUBECONFIG", "")
+
+	k := NewK8sClusterExternal("staging")
+	k.setDefaults()
+	assert.Equal(t, filepath.Join(utils.HomeFolder(), ".kube", "config"), k.KubeConfig)
+
+	os.Setenv("KUBECONFIG", "/tmp/one:/tmp/two")
+
+	k = NewK8sClusterExternal("staging")
+	k.setDefaults()
+	assert.Equal(t, "/tmp/one", k.KubeConfig)
+
+	k = NewK8sClusterExternal("staging")
+	k.KubeConfig = "~/configs/staging"
+	k.setDefaults()
+	assert.Equal(t, filepath.Join(utils.HomeFolder(), "configs", "staging"), k.KubeConfig)
+}
+
+const clusterExternal = `
+k8s_cluster_external "staging" {
+	kubeconfig = "./kubeconfig.yaml"
+	context = "staging"
+}
+
+helm "consul" {
+	cluster = "k8s_cluster_external.staging"
+	chart = "github.com/hashicorp/consul-helm?ref=v0.22.0"
+}
+`
@@ -18,6 +18,53 @@ func TestK8sClusterCreatesCorrectly(t *testing.T) {
 	assert.Equal(t, PendingCreation, cl.Info().Status)
 }
 
+func TestK8sClusterWithNodesCreatesCorrectly(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, clusterNodes)
+	defer cleanup()
+
+	cl, err := c.FindResource("k8s_cluster.testing")
+	assert.NoError(t, err)
+
+	k := cl.(*K8sCluster)
+	assert.Equal(t, 3, k.Nodes)
+	assert.Equal(t, 2, k.Agents())
+	assert.Equal(t, 2048, k.Resources.CPU)
+	assert.Equal(t, 1024, k.Resources.Memory)
+}
+
+func TestK8sClusterAgents(t *testing.T) {
+	k := NewK8sCluster("testing")
+	k.Driver = "k3s"
+	assert.Equal(t, 0, k.Agents())
+	assert.Len(t, k.Validate(), 0)
+
+	k.Nodes = 1
+	assert.Equal(t, 0, k.Agents())
+
+	k.Nodes = -1
+	assert.Len(t, k.Validate(), 1)
+
+	k.Nodes = 1
+	k.Driver = "kind"
+	assert.Len(t, k.Validate(), 0)
+
+	k.Driver = "minikube"
+	assert.Len(t, k.Validate(), 1)
+}
+
+func TestK8sClusterWithHealthCheckCreatesCorrectly(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, clusterHealthCheck)
+	defer cleanup()
+
+	cl, err := c.FindResource("k8s_cluster.testing")
+	assert.NoError(t, err)
+
+	k := cl.(*K8sCluster)
+	assert.Equal(t, "60s", k.HealthCheck.Timeout)
+	assert.Equal(t, "localhost:6443", k.HealthCheck.TCP)
+	assert.Equal(t, []string{"kubectl", "get", "nodes"}, k.HealthCheck.Exec)
+}
+
 const clusterDefault = `
 k8s_cluster "testing" {
 	network {
@@ -26,3 +73,27 @@ k8s_cluster "testing" {
 	driver = "k3s"
 }
 `
+
+const clusterNodes = `
+k8s_cluster "testing" {
+	driver = "k3s"
+	nodes = 3
+
+	resources {
+		cpu = 2048
+		memory = 1024
+	}
+}
+`
+
+const clusterHealthCheck = `
+k8s_cluster "testing" {
+	driver = "k3s"
+
+	health_check {
+		timeout = "60s"
+		tcp     = "localhost:6443"
+		exec    = ["kubectl", "get", "nodes"]
+	}
+}
+`
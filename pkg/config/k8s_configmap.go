@@ -0,0 +1,30 @@
+package config
+
+// TypeK8sConfigMap is the resource string for a K8sConfigMap resource
+const TypeK8sConfigMap ResourceType = "k8s_configmap"
+
+// K8sConfigMap creates a ConfigMap in a Kubernetes cluster from local files
+// and inline data, the name of the resource is the name of the ConfigMap
+type K8sConfigMap struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	// Cluster is the cluster to create the ConfigMap in e.g. k8s_cluster.k3s
+	Cluster   string `hcl:"cluster" json:"cluster"`
+	Namespace string `hcl:"namespace,optional" json:"namespace,omitempty"`
+
+	// Files are added to the ConfigMap using the name of the file as the key
+	Files []string          `hcl:"files,optional" json:"files,omitempty"`
+	Data  map[string]string `hcl:"data,optional" json:"data,omitempty"`
+}
+
+// NewK8sConfigMap creates a new K8sConfigMap config resource
+func NewK8sConfigMap(name string) *K8sConfigMap {
+	return &K8sConfigMap{ResourceInfo: ResourceInfo{Name: name, Type: TypeK8sConfigMap, Status: PendingCreation}}
+}
+
+// Validate the K8sConfigMap and return errors
+func (m *K8sConfigMap) Validate() []error {
+	return validateK8sData(m.Files, m.Data)
+}
@@ -1,5 +1,7 @@
 package config
 
+import "fmt"
+
 // TypeK8sIngress is the resource string for the type
 const TypeK8sIngress ResourceType = "k8s_ingress"
 
@@ -36,9 +38,29 @@ type K8sIngress struct {
 	Namespace string `hcl:"namespace,optional" json:"namespace,omitempty"`
 
 	Ports []Port `hcl:"port,block" json:"ports,omitempty"`
+
+	// Protocol is the type of traffic proxied by the ingress, http or tcp,
+	// tcp passes raw connections through to the target e.g. databases
+	Protocol string `hcl:"protocol,optional" json:"protocol,omitempty"`
 }
 
 // NewK8sIngress creates a new ingress with the correct defaults
 func NewK8sIngress(name string) *K8sIngress {
 	return &K8sIngress{ResourceInfo: ResourceInfo{Name: name, Type: TypeK8sIngress, Status: PendingCreation}}
 }
+
+// Validate the K8sIngress and return errors
+func (i *K8sIngress) Validate() []error {
+	set := 0
+	for _, v := range []string{i.Service, i.Deployment, i.Pod} {
+		if v != "" {
+			set++
+		}
+	}
+
+	if set != 1 {
+		return []error{fmt.Errorf("exactly one of service, deployment or pod must be specified")}
+	}
+
+	return validateIngressProtocol(i.Protocol)
+}
@@ -0,0 +1,36 @@
+package config
+
+// TypeK8sNamespace is the resource string for a K8sNamespace resource
+const TypeK8sNamespace ResourceType = "k8s_namespace"
+
+// K8sNamespace creates a namespace in a Kubernetes cluster, the name of
+// the resource is the name of the namespace. Helm charts installed to the
+// namespace on the same cluster are installed after it is created.
+type K8sNamespace struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	// Cluster is the cluster to create the namespace in e.g. k8s_cluster.k3s
+	Cluster string `hcl:"cluster" json:"cluster"`
+
+	Labels      map[string]string `hcl:"labels,optional" json:"labels,omitempty"`
+	Annotations map[string]string `hcl:"annotations,optional" json:"annotations,omitempty"`
+}
+
+// NewK8sNamespace creates a new K8sNamespace config resource
+func NewK8sNamespace(name string) *K8sNamespace {
+	return &K8sNamespace{ResourceInfo: ResourceInfo{Name: name, Type: TypeK8sNamespace, Status: PendingCreation}}
+}
+
+// FindK8sNamespace returns the k8s_namespace which creates the namespace in
+// the cluster, nil is returned when the namespace is not defined
+func (c *Config) FindK8sNamespace(cluster, namespace string) *K8sNamespace {
+	for _, r := range c.Resources {
+		if ns, ok := r.(*K8sNamespace); ok && ns.Cluster == cluster && ns.Name == namespace {
+			return ns
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestK8sNamespaceCreatesCorrectly(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, k8sNamespaceValid)
+	defer cleanup()
+
+	ns, err := c.FindResource("k8s_namespace.vault")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "k8s_cluster.k3s", ns.(*K8sNamespace).Cluster)
+	assert.Equal(t, "platform", ns.(*K8sNamespace).Labels["team"])
+	assert.Equal(t, "true", ns.(*K8sNamespace).Annotations["linkerd.io/inject"])
+	assert.Contains(t, ns.Info().DependsOn, "k8s_cluster.k3s")
+}
+
+func TestHelmDependsOnNamespace(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, k8sNamespaceValid)
+	defer cleanup()
+
+	h, err := c.FindResource("helm.vault")
+	assert.NoError(t, err)
+	assert.Contains(t, h.Info().DependsOn, "k8s_namespace.vault")
+
+	h, err = c.FindResource("helm.consul")
+	assert.NoError(t, err)
+	assert.NotContains(t, h.Info().DependsOn, "k8s_namespace.vault")
+}
+
+const k8sNamespaceValid = `
+k8s_cluster "k3s" {
+	driver = "k3s"
+}
+
+k8s_namespace "vault" {
+	cluster = "k8s_cluster.k3s"
+
+	labels = {
+		team = "platform"
+	}
+
+	annotations = {
+		"linkerd.io/inject" = "true"
+	}
+}
+
+helm "vault" {
+	cluster   = "k8s_cluster.k3s"
+	chart     = "./vault"
+	namespace = "vault"
+}
+
+helm "consul" {
+	cluster = "k8s_cluster.k3s"
+	chart   = "./consul"
+}
+`
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// TypeK8sSecret is the resource string for a K8sSecret resource
+const TypeK8sSecret ResourceType = "k8s_secret"
+
+// K8sSecret creates a Secret in a Kubernetes cluster from local files and
+// inline data, the name of the resource is the name of the Secret
+type K8sSecret struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	// Cluster is the cluster to create the Secret in e.g. k8s_cluster.k3s
+	Cluster   string `hcl:"cluster" json:"cluster"`
+	Namespace string `hcl:"namespace,optional" json:"namespace,omitempty"`
+
+	// SecretType is the type of the Secret e.g. kubernetes.io/tls
+	SecretType string `hcl:"secret_type,optional" json:"secret_type,omitempty" mapstructure:"secret_type"`
+
+	// Files are added to the Secret using the name of the file as the key
+	Files []string          `hcl:"files,optional" json:"files,omitempty"`
+	Data  map[string]string `hcl:"data,optional" json:"data,omitempty"`
+}
+
+// NewK8sSecret creates a new K8sSecret config resource
+func NewK8sSecret(name string) *K8sSecret {
+	return &K8sSecret{ResourceInfo: ResourceInfo{Name: name, Type: TypeK8sSecret, Status: PendingCreation}}
+}
+
+// Validate the K8sSecret and return errors
+func (s *K8sSecret) Validate() []error {
+	return validateK8sData(s.Files, s.Data)
+}
+
+// validateK8sData checks the keys created from files do not clash with
+// each other or with the inline data
+func validateK8sData(files []string, data map[string]string) []error {
+	if len(files) == 0 && len(data) == 0 {
+		return []error{fmt.Errorf("files or data must be specified")}
+	}
+
+	errs := []error{}
+	keys := map[string]bool{}
+	for k := range data {
+		keys[k] = true
+	}
+
+	for _, f := range files {
+		k := filepath.Base(f)
+		if keys[k] {
+			errs = append(errs, fmt.Errorf("key %s is defined more than once", k))
+		}
+
+		keys[k] = true
+	}
+
+	return errs
+}
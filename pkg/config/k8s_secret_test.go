@@ -0,0 +1,90 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestK8sSecretCreatesCorrectly(t *testing.T) {
+	c, dir, cleanup := setupTestConfig(t, k8sSecretValid)
+	defer cleanup()
+
+	r, err := c.FindResource("k8s_secret.tls")
+	assert.NoError(t, err)
+
+	sec := r.(*K8sSecret)
+	assert.Equal(t, "vault", sec.Namespace)
+	assert.Equal(t, "kubernetes.io/tls", sec.SecretType)
+	assert.Equal(t, filepath.Join(dir, "certs/tls.crt"), sec.Files[0])
+	assert.Equal(t, "key", sec.Data["tls.key"])
+	assert.Contains(t, sec.DependsOn, "k8s_cluster.k3s")
+	assert.Contains(t, sec.DependsOn, "k8s_namespace.vault")
+}
+
+func TestK8sConfigMapSetsDefaults(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, k8sSecretValid)
+	defer cleanup()
+
+	r, err := c.FindResource("k8s_configmap.app")
+	assert.NoError(t, err)
+
+	cm := r.(*K8sConfigMap)
+	assert.Equal(t, "default", cm.Namespace)
+	assert.NotContains(t, cm.DependsOn, "k8s_namespace.vault")
+
+	r, err = c.FindResource("k8s_secret.db")
+	assert.NoError(t, err)
+	assert.Equal(t, "Opaque", r.(*K8sSecret).SecretType)
+}
+
+func TestK8sSecretValidate(t *testing.T) {
+	s := NewK8sSecret("empty")
+	assert.Len(t, s.Validate(), 1)
+
+	s.Files = []string{"/certs/tls.crt"}
+	s.Data = map[string]string{"tls.crt": "cert"}
+	assert.Len(t, s.Validate(), 1)
+
+	s.Data = map[string]string{"tls.key": "key"}
+	assert.Len(t, s.Validate(), 0)
+}
+
+const k8sSecretValid = `
+k8s_cluster "k3s" {
+	driver = "k3s"
+}
+
+k8s_namespace "vault" {
+	cluster = "k8s_cluster.k3s"
+}
+
+k8s_secret "tls" {
+	cluster     = "k8s_cluster.k3s"
+	namespace   = "vault"
+	secret_type = "kubernetes.io/tls"
+
+	files = ["./certs/tls.crt"]
+
+	data = {
+		"tls.key" = "key"
+	}
+}
+
+k8s_secret "db" {
+	cluster = "k8s_cluster.k3s"
+
+	data = {
+		password = "password"
+	}
+}
+
+k8s_configmap "app" {
+	cluster = "k8s_cluster.k3s"
+
+	data = {
+		LOG_LEVEL = "debug"
+	}
+}
+`
@@ -0,0 +1,32 @@
+package config
+
+// TypeK8sWait is the resource string for a K8sWait resource
+const TypeK8sWait ResourceType = "k8s_wait"
+
+// K8sWait blocks until the given conditions are met in a Kubernetes cluster,
+// resources which depend on it are not created until all pods are ready,
+// custom resource definitions are established and jobs have completed
+type K8sWait struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	// Cluster is the cluster to check e.g. k8s_cluster.k3s
+	Cluster string `hcl:"cluster" json:"cluster"`
+
+	// Pods are label selectors for pods which must be running and ready
+	Pods []string `hcl:"pods,optional" json:"pods,omitempty"`
+	// CRDs are the names of custom resource definitions which must be
+	// established e.g. certificates.cert-manager.io
+	CRDs []string `hcl:"crds,optional" json:"crds,omitempty" mapstructure:"crds"`
+	// Jobs are label selectors for jobs which must have completed
+	Jobs []string `hcl:"jobs,optional" json:"jobs,omitempty"`
+
+	// Timeout is the maximum time to wait for the conditions, defaults to 300s
+	Timeout string `hcl:"timeout,optional" json:"timeout,omitempty"`
+}
+
+// NewK8sWait creates a new K8sWait config resource
+func NewK8sWait(name string) *K8sWait {
+	return &K8sWait{ResourceInfo: ResourceInfo{Name: name, Type: TypeK8sWait, Status: PendingCreation}, Timeout: "300s"}
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestK8sWaitCreatesCorrectly(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, k8sWaitValid)
+	defer cleanup()
+
+	r, err := c.FindResource("k8s_wait.cert-manager")
+	assert.NoError(t, err)
+
+	w := r.(*K8sWait)
+	assert.Equal(t, "k8s_cluster.k3s", w.Cluster)
+	assert.Equal(t, []string{"app=cert-manager"}, w.Pods)
+	assert.Equal(t, []string{"certificates.cert-manager.io"}, w.CRDs)
+	assert.Equal(t, "60s", w.Timeout)
+	assert.Contains(t, w.DependsOn, "k8s_cluster.k3s")
+}
+
+func TestK8sWaitSetsDefaultTimeout(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, k8sWaitDefault)
+	defer cleanup()
+
+	r, err := c.FindResource("k8s_wait.jobs")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "300s", r.(*K8sWait).Timeout)
+}
+
+const k8sWaitValid = `
+k8s_cluster "k3s" {
+	driver = "k3s"
+}
+
+k8s_wait "cert-manager" {
+	cluster = "k8s_cluster.k3s"
+	pods    = ["app=cert-manager"]
+	crds    = ["certificates.cert-manager.io"]
+	timeout = "60s"
+}
+`
+
+const k8sWaitDefault = `
+k8s_cluster "k3s" {
+	driver = "k3s"
+}
+
+k8s_wait "jobs" {
+	cluster = "k8s_cluster.k3s"
+	jobs    = ["app=migrate"]
+}
+`
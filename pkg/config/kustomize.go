@@ -0,0 +1,26 @@
+package config
+
+// TypeKustomize is the resource string for a Kustomize resource
+const TypeKustomize ResourceType = "kustomize"
+
+// Kustomize builds a kustomization and applies the generated config to a
+// Kubernetes cluster
+type Kustomize struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	// Cluster is the name of the cluster to apply configuration to
+	Cluster string `hcl:"cluster" json:"cluster"`
+
+	// Path is the folder containing the kustomization.yaml e.g. ./overlays/dev
+	Path string `hcl:"path" json:"path"`
+
+	// WaitUntilReady when set to true waits until all resources have been created and are in a "Running" state
+	WaitUntilReady bool `hcl:"wait_until_ready,optional" json:"wait_until_ready" mapstructure:"wait_until_ready"`
+}
+
+// NewKustomize creates a new Kustomize config resource
+func NewKustomize(name string) *Kustomize {
+	return &Kustomize{ResourceInfo: ResourceInfo{Name: name, Type: TypeKustomize, Status: PendingCreation}}
+}
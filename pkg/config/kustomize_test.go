@@ -0,0 +1,33 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKustomizeCreatesCorrectly(t *testing.T) {
+	c, dir, cleanup := setupTestConfig(t, kustomizeValid)
+	defer cleanup()
+
+	k, err := c.FindResource("kustomize.app")
+	assert.NoError(t, err)
+
+	assert.Equal(t, filepath.Join(dir, "overlays", "dev"), k.(*Kustomize).Path)
+	assert.True(t, k.(*Kustomize).WaitUntilReady)
+	assert.Contains(t, k.Info().DependsOn, "k8s_cluster.k3s")
+}
+
+const kustomizeValid = `
+k8s_cluster "k3s" {
+	driver = "k3s"
+}
+
+kustomize "app" {
+	cluster = "k8s_cluster.k3s"
+	path    = "./overlays/dev"
+
+	wait_until_ready = true
+}
+`
@@ -0,0 +1,286 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl2/hcl"
+)
+
+// Lint checks the config for problems which are not errors but are likely
+// to be mistakes, such as networks which are not used, ingresses which
+// target resources that do not exist, circular dependencies and variables
+// which are never referenced. Files are the config files the config was
+// parsed from, they are used to find the location of each problem.
+// ParseReferences must be called before Lint so that the dependencies for
+// each resource have been set.
+func Lint(c *Config, files []string) (ParseError, error) {
+	blocks, err := readFolderBlocks(files)
+	if err != nil {
+		return ParseError{}, err
+	}
+
+	l := &linter{config: c, blocks: blocks, ranges: map[string]hcl.Range{}}
+	for _, fb := range blocks {
+		if len(fb.block.Labels) > 0 {
+			l.ranges[fmt.Sprintf("%s.%s", fb.block.Type, fb.block.Labels[len(fb.block.Labels)-1])] = fb.block.DefRange
+		}
+	}
+
+	l.lintNetworks()
+	l.lintTargets()
+	l.lintCycles()
+	l.lintVariables()
+
+	return newParseError(l.diags), nil
+}
+
+type linter struct {
+	config *Config
+	blocks []fileBlock
+	// ranges contains the location of each block keyed by type.name
+	ranges map[string]hcl.Range
+	diags  hcl.Diagnostics
+}
+
+// warn adds a warning for the resource id, the location of the block which
+// defined the resource is added when it is known
+func (l *linter) warn(id, summary, detail string) {
+	d := &hcl.Diagnostic{Severity: hcl.DiagWarning, Summary: summary, Detail: detail}
+
+	// resources created using count or for_each have an index in the name
+	if i := strings.Index(id, "["); i > 0 {
+		id = id[:i]
+	}
+
+	if r, ok := l.ranges[id]; ok {
+		d.Subject = &r
+	}
+
+	l.diags = append(l.diags, d)
+}
+
+// lintNetworks warns about resources attached to networks which are not
+// defined and networks which no resource is attached to
+func (l *linter) lintNetworks() {
+	used := map[string]bool{}
+
+	for _, r := range l.config.Resources {
+		for _, n := range resourceNetworks(r) {
+			nr, err := l.config.FindDependency(r, n.Name)
+			if err != nil {
+				l.warn(
+					ResourceID(r),
+					"Network does not exist",
+					fmt.Sprintf("%s is attached to the network %s which is not defined.", ResourceID(r), n.Name),
+				)
+				continue
+			}
+
+			used[ResourceID(nr)] = true
+		}
+
+		for _, d := range r.Info().DependsOn {
+			if dr, err := l.config.FindDependency(r, d); err == nil {
+				used[ResourceID(dr)] = true
+			}
+		}
+	}
+
+	for _, r := range l.config.Resources {
+		if r.Info().Type != TypeNetwork || used[ResourceID(r)] {
+			continue
+		}
+
+		l.warn(
+			ResourceID(r),
+			"Unused network",
+			fmt.Sprintf("No resources are attached to the network %s.", ResourceID(r)),
+		)
+	}
+}
+
+// lintTargets warns about ingresses and sidecars which target resources
+// which are not defined
+func (l *linter) lintTargets() {
+	for _, r := range l.config.Resources {
+		target := resourceTarget(r)
+		if target == "" {
+			continue
+		}
+
+		if _, err := l.config.FindDependency(r, target); err != nil {
+			l.warn(
+				ResourceID(r),
+				"Target does not exist",
+				fmt.Sprintf("%s targets %s which is not defined.", ResourceID(r), target),
+			)
+		}
+	}
+}
+
+// lintCycles warns about resources which depend on each other, resources
+// in a cycle can never be created
+func (l *linter) lintCycles() {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := map[string]int{}
+	path := []string{}
+
+	var visit func(id string)
+	visit = func(id string) {
+		state[id] = visiting
+		path = append(path, id)
+
+		deps := []string{}
+		if r, err := l.config.FindResource(id); err == nil {
+			// dependencies are resolved relative to the module of the resource
+			for _, d := range r.Info().DependsOn {
+				if dr, err := l.config.FindDependency(r, d); err == nil {
+					deps = append(deps, ResourceID(dr))
+				}
+			}
+		}
+
+		for _, d := range deps {
+			switch state[d] {
+			case unvisited:
+				visit(d)
+			case visiting:
+				cycle := []string{}
+				for i := len(path) - 1; i >= 0; i-- {
+					cycle = append([]string{path[i]}, cycle...)
+					if path[i] == d {
+						break
+					}
+				}
+
+				l.warn(
+					d,
+					"Circular dependency",
+					fmt.Sprintf("The resources %s depend on each other and can not be created.", strings.Join(append(cycle, d), " -> ")),
+				)
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[id] = visited
+	}
+
+	for _, r := range l.config.Resources {
+		if state[ResourceID(r)] == unvisited {
+			visit(ResourceID(r))
+		}
+	}
+}
+
+// lintVariables warns about variables which are not referenced by any
+// block
+func (l *linter) lintVariables() {
+	referenced := map[string]bool{}
+	defined := map[string]bool{}
+
+	for _, fb := range l.blocks {
+		if fb.block.Type == string(TypeVariable) && len(fb.block.Labels) > 0 {
+			defined[fb.block.Labels[0]] = true
+			continue
+		}
+
+		for _, t := range bodyTraversals(fb.block.Body) {
+			if t.RootName() != "var" || len(t) < 2 {
+				continue
+			}
+
+			if a, ok := t[1].(hcl.TraverseAttr); ok {
+				referenced[a.Name] = true
+			}
+		}
+	}
+
+	names := []string{}
+	for n := range defined {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	for _, n := range names {
+		if referenced[n] {
+			continue
+		}
+
+		l.warn(
+			fmt.Sprintf("%s.%s", TypeVariable, n),
+			"Unused variable",
+			fmt.Sprintf("The variable %s is defined but is not referenced.", n),
+		)
+	}
+}
+
+func resourceNetworks(r Resource) []NetworkAttachment {
+	switch v := r.(type) {
+	case *AWS:
+		return v.Networks
+	case *Container:
+		return v.Networks
+	case *ContainerIngress:
+		return v.Networks
+	case *ContainerRegistry:
+		return v.Networks
+	case *Vault:
+		return v.Networks
+	case *DNS:
+		return v.Networks
+	case *Docs:
+		return v.Networks
+	case *ExecRemote:
+		return v.Networks
+	case *ImageCache:
+		return v.Networks
+	case *LoadBalancer:
+		return v.Networks
+	case *MinIO:
+		return v.Networks
+	case *MockAPI:
+		return v.Networks
+	case *TrafficCapture:
+		return v.Networks
+	case *Ingress:
+		return v.Networks
+	case *K8sCluster:
+		return v.Networks
+	case *K8sIngress:
+		return v.Networks
+	case *NomadCluster:
+		return v.Networks
+	case *NomadIngress:
+		return v.Networks
+	}
+
+	return nil
+}
+
+func resourceTarget(r Resource) string {
+	switch v := r.(type) {
+	case *Ingress:
+		return v.Target
+	case *ContainerIngress:
+		return v.Target
+	case *Copy:
+		return v.Target
+	case *K8sIngress:
+		return v.Cluster
+	case *NomadIngress:
+		return v.Cluster
+	case *Sidecar:
+		return v.Target
+	case *SQLExec:
+		return v.Target
+	}
+
+	return ""
+}
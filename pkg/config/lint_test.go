@@ -0,0 +1,138 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lintConfig(t *testing.T, contents string) ParseError {
+	c, dir, cleanup := setupTestConfig(t, contents)
+	defer cleanup()
+
+	files, err := ConfigFiles(dir, 0)
+	require.NoError(t, err)
+
+	pe, err := Lint(c, files)
+	require.NoError(t, err)
+
+	return pe
+}
+
+func lintSummaries(pe ParseError) []string {
+	s := []string{}
+	for _, d := range pe.Diagnostics {
+		s = append(s, d.Summary)
+	}
+
+	return s
+}
+
+func TestLintWithValidConfigReturnsNoWarnings(t *testing.T) {
+	pe := lintConfig(t, lintValid)
+
+	assert.Len(t, pe.Diagnostics, 0)
+}
+
+func TestLintWarnsForUnusedNetwork(t *testing.T) {
+	pe := lintConfig(t, lintValid+`
+network "unused" {
+  subnet = "10.6.0.0/16"
+}
+`)
+
+	require.Len(t, pe.Diagnostics, 1)
+	assert.Equal(t, "Unused network", pe.Diagnostics[0].Summary)
+	assert.Contains(t, pe.Diagnostics[0].Detail, "network.unused")
+	require.NotNil(t, pe.Diagnostics[0].Subject)
+	assert.Equal(t, 20, pe.Diagnostics[0].Subject.Start.Line)
+}
+
+func TestLintWarnsForUndefinedNetworkAndTarget(t *testing.T) {
+	pe := lintConfig(t, lintValid+`
+container "vault" {
+  image {
+    name = "vault"
+  }
+
+  network {
+    name = "network.missing"
+  }
+}
+
+ingress "vault" {
+  target = "container.missing"
+
+  network {
+    name = "network.local"
+  }
+}
+`)
+
+	assert.ElementsMatch(t, []string{"Network does not exist", "Target does not exist"}, lintSummaries(pe))
+}
+
+func TestLintWarnsForCircularDependencies(t *testing.T) {
+	pe := lintConfig(t, lintValid+`
+container "a" {
+  image {
+    name = "a"
+  }
+
+  depends_on = ["container.b"]
+
+  network {
+    name = "network.local"
+  }
+}
+
+container "b" {
+  image {
+    name = "b"
+  }
+
+  depends_on = ["container.a"]
+
+  network {
+    name = "network.local"
+  }
+}
+`)
+
+	require.Len(t, pe.Diagnostics, 1)
+	assert.Equal(t, "Circular dependency", pe.Diagnostics[0].Summary)
+	assert.Contains(t, pe.Diagnostics[0].Detail, "container.a -> container.b -> container.a")
+}
+
+func TestLintWarnsForUnusedVariable(t *testing.T) {
+	pe := lintConfig(t, lintValid+`
+variable "unused" {
+  default = "abc"
+}
+`)
+
+	require.Len(t, pe.Diagnostics, 1)
+	assert.Equal(t, "Unused variable", pe.Diagnostics[0].Summary)
+	assert.Contains(t, pe.Diagnostics[0].Detail, "unused")
+}
+
+var lintValid = `
+variable "image" {
+  default = "consul:1.8.0"
+}
+
+network "local" {
+  subnet = "10.5.0.0/16"
+}
+
+container "consul" {
+  image {
+    name = var.image
+  }
+
+  network {
+    name = "network.local"
+  }
+}
+`
@@ -0,0 +1,65 @@
+package config
+
+import "fmt"
+
+// TypeLoadBalancer is the resource string for a LoadBalancer resource
+const TypeLoadBalancer ResourceType = "load_balancer"
+
+// LoadBalancer runs a HAProxy container which balances traffic across the
+// target containers or clusters, targets which fail the health check are
+// removed from the load balancer until they are healthy
+type LoadBalancer struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	Networks []NetworkAttachment `hcl:"network,block" json:"networks,omitempty"` // networks to attach the load balancer to
+
+	// Targets are the resources traffic is balanced across e.g.
+	// container.web_1, the remote port of each port is used for all targets
+	Targets []string `hcl:"targets" json:"targets"`
+
+	// Ports the load balancer listens on, Local is the port of the load
+	// balancer, Remote is the port of the targets and Host is the port
+	// on the local machine
+	Ports []Port `hcl:"port,block" json:"ports,omitempty"`
+
+	// Protocol is the type of traffic balanced, http or tcp, defaults to http
+	Protocol string `hcl:"protocol,optional" json:"protocol,omitempty"`
+
+	// Balance is the algorithm used to select a target, roundrobin,
+	// leastconn or source, defaults to roundrobin
+	Balance string `hcl:"balance,optional" json:"balance,omitempty"`
+
+	// CheckPath is the HTTP path used to check the health of the targets,
+	// when not set the targets are checked by opening a TCP connection
+	CheckPath string `hcl:"check_path,optional" json:"check_path,omitempty" mapstructure:"check_path"`
+
+	// CheckInterval is the time between health checks, defaults to 2s
+	CheckInterval string `hcl:"check_interval,optional" json:"check_interval,omitempty" mapstructure:"check_interval"`
+}
+
+// NewLoadBalancer creates a new LoadBalancer config resource
+func NewLoadBalancer(name string) *LoadBalancer {
+	return &LoadBalancer{
+		ResourceInfo:  ResourceInfo{Name: name, Type: TypeLoadBalancer, Status: PendingCreation},
+		Protocol:      "http",
+		Balance:       "roundrobin",
+		CheckInterval: "2s",
+	}
+}
+
+// Validate the LoadBalancer and return errors
+func (l *LoadBalancer) Validate() []error {
+	errs := validateIngressProtocol(l.Protocol)
+
+	if l.Balance != "roundrobin" && l.Balance != "leastconn" && l.Balance != "source" {
+		errs = append(errs, fmt.Errorf("balance must be roundrobin, leastconn or source, got %s", l.Balance))
+	}
+
+	if l.CheckPath != "" && l.Protocol == "tcp" {
+		errs = append(errs, fmt.Errorf("check_path can only be set when the protocol is http"))
+	}
+
+	return errs
+}
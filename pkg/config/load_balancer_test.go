@@ -0,0 +1,72 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadBalancerCreatesCorrectly(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, loadBalancerValid)
+	defer cleanup()
+
+	r, err := c.FindResource("load_balancer.web")
+	require.NoError(t, err)
+
+	lb := r.(*LoadBalancer)
+	assert.Equal(t, "http", lb.Protocol)
+	assert.Equal(t, "leastconn", lb.Balance)
+	assert.Equal(t, "2s", lb.CheckInterval)
+	assert.Equal(t, "/health", lb.CheckPath)
+	assert.Contains(t, lb.DependsOn, "container.web1")
+	assert.Contains(t, lb.DependsOn, "container.web2")
+	assert.Contains(t, lb.DependsOn, "network.local")
+}
+
+func TestLoadBalancerValidate(t *testing.T) {
+	lb := NewLoadBalancer("web")
+	assert.Len(t, lb.Validate(), 0)
+
+	lb.Balance = "random"
+	assert.Len(t, lb.Validate(), 1)
+
+	lb.Balance = "source"
+	lb.Protocol = "tcp"
+	lb.CheckPath = "/health"
+	assert.Len(t, lb.Validate(), 1)
+}
+
+const loadBalancerValid = `
+network "local" {
+	subnet = "10.6.0.0/16"
+}
+
+container "web1" {
+	image {
+		name = "nginx:latest"
+	}
+}
+
+container "web2" {
+	image {
+		name = "nginx:latest"
+	}
+}
+
+load_balancer "web" {
+	targets    = ["container.web1", "container.web2"]
+	balance    = "leastconn"
+	check_path = "/health"
+
+	network {
+		name = "network.local"
+	}
+
+	port {
+		local  = 80
+		remote = 80
+		host   = 18080
+	}
+}
+`
@@ -0,0 +1,62 @@
+package config
+
+import "fmt"
+
+// TypeLocalIngress is the resource string for a LocalIngress resource
+const TypeLocalIngress ResourceType = "local_ingress"
+
+// LocalIngress exposes a service running on the local machine to the
+// environment, containers reach the service using the address of the
+// ingress and pods using a Service created in the cluster
+type LocalIngress struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	Networks []NetworkAttachment `hcl:"network,block" json:"networks,omitempty"`
+
+	// Ports map the local port in the environment to the remote port of the
+	// service on the local machine
+	Ports []Port `hcl:"port,block" json:"ports,omitempty"`
+
+	// Cluster is an optional Kubernetes cluster to create a Service in, the
+	// Service has the name of the resource
+	Cluster   string `hcl:"cluster,optional" json:"cluster,omitempty"`
+	Namespace string `hcl:"namespace,optional" json:"namespace,omitempty"`
+
+	// output parameters
+
+	// Address is the DNS name containers use to reach the service
+	Address string `hcl:"address,optional" json:"address,omitempty"`
+}
+
+// NewLocalIngress creates a new LocalIngress config resource
+func NewLocalIngress(name string) *LocalIngress {
+	return &LocalIngress{ResourceInfo: ResourceInfo{Name: name, Type: TypeLocalIngress, Status: PendingCreation}}
+}
+
+// Validate the LocalIngress and return errors
+func (i *LocalIngress) Validate() []error {
+	errs := []error{}
+
+	if len(i.Ports) == 0 {
+		errs = append(errs, fmt.Errorf("at least one port must be specified"))
+	}
+
+	if i.Cluster != "" && i.IPAddress() == "" {
+		errs = append(errs, fmt.Errorf("a network with an ip_address must be specified when cluster is set, pods reach the ingress using its ip address"))
+	}
+
+	return errs
+}
+
+// IPAddress returns the first static ip address of the ingress
+func (i *LocalIngress) IPAddress() string {
+	for _, n := range i.Networks {
+		if n.IPAddress != "" {
+			return n.IPAddress
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalIngressCreatesCorrectly(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, localIngressValid)
+	defer cleanup()
+
+	r, err := c.FindResource("local_ingress.app")
+	assert.NoError(t, err)
+
+	i := r.(*LocalIngress)
+	assert.Equal(t, "app.local_ingress.shipyard.run", i.Address)
+	assert.Equal(t, "default", i.Namespace)
+	assert.Equal(t, "10.5.0.100", i.IPAddress())
+	assert.Contains(t, i.DependsOn, "network.local")
+	assert.Contains(t, i.DependsOn, "k8s_cluster.k3s")
+}
+
+func TestLocalIngressValidate(t *testing.T) {
+	i := NewLocalIngress("app")
+	assert.Len(t, i.Validate(), 1)
+
+	i.Ports = []Port{Port{Local: "8080", Remote: "3000"}}
+	assert.Len(t, i.Validate(), 0)
+
+	i.Cluster = "k8s_cluster.k3s"
+	assert.Len(t, i.Validate(), 1)
+}
+
+const localIngressValid = `
+network "local" {
+	subnet = "10.5.0.0/16"
+}
+
+k8s_cluster "k3s" {
+	driver = "k3s"
+}
+
+local_ingress "app" {
+	cluster = "k8s_cluster.k3s"
+
+	network {
+		name       = "network.local"
+		ip_address = "10.5.0.100"
+	}
+
+	port {
+		local  = "8080"
+		remote = "3000"
+	}
+}
+`
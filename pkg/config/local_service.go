@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/shipyard-run/shipyard/pkg/utils"
+)
+
+// TypeLocalService is the resource string for a LocalService resource
+const TypeLocalService ResourceType = "local_service"
+
+// LocalService runs a process on the local machine, the process is started
+// in the background and stopped when the resource is destroyed
+type LocalService struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	Command   string   `hcl:"cmd" json:"cmd"`                      // Binary to execute
+	Arguments []string `hcl:"args,optional" json:"args,omitempty"` // Arguments for the binary
+
+	// WorkingDirectory is the directory the process is started in
+	WorkingDirectory string `hcl:"working_directory,optional" json:"working_directory,omitempty" mapstructure:"working_directory"`
+
+	Environment []KV `hcl:"env,block" json:"env,omitempty"` // Environment variables to set
+
+	HealthCheck *HealthCheck `hcl:"health_check,block" json:"health_check,omitempty" mapstructure:"health_check"`
+
+	// output parameters
+
+	// PID is the process id of the running service
+	PID int `json:"pid,omitempty"`
+
+	// StartTime is the time the process was started, it is used to check
+	// the process id has not been reused by another process
+	StartTime string `json:"start_time,omitempty" mapstructure:"start_time"`
+
+	// LogFile is the path of the file the output of the process is written to
+	LogFile string `json:"log_file,omitempty" mapstructure:"log_file"`
+}
+
+// NewLocalService creates a new LocalService config resource
+func NewLocalService(name string) *LocalService {
+	return &LocalService{ResourceInfo: ResourceInfo{Name: name, Type: TypeLocalService, Status: PendingCreation}}
+}
+
+// setDefaults sets the log file and the process id of a service which has
+// already been started, the process id is not part of the config so it is
+// read from the state
+func (l *LocalService) setDefaults(module string) {
+	l.LogFile = filepath.Join(utils.ShipyardHome(), "logs", fmt.Sprintf("local_service_%s.log", l.Name))
+
+	if s, ok := stateResource(module, l.Type, l.Name).(*LocalService); ok && s.PID > 0 {
+		l.PID = s.PID
+		l.StartTime = s.StartTime
+	}
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalServiceCreatesCorrectly(t *testing.T) {
+	defer setupTemplateHome(t)()
+
+	c, base, cleanup := setupTestConfig(t, localServiceValid)
+	defer cleanup()
+
+	r, err := c.FindResource("local_service.api")
+	require.NoError(t, err)
+
+	ls := r.(*LocalService)
+	assert.Equal(t, "go", ls.Command)
+	assert.Equal(t, []string{"run", "main.go"}, ls.Arguments)
+	assert.Equal(t, filepath.Join(base, "api"), ls.WorkingDirectory)
+	assert.Equal(t, "DB_ADDR", ls.Environment[0].Key)
+	assert.Equal(t, "http://localhost:8080/health", ls.HealthCheck.HTTP)
+	assert.Equal(t, filepath.Join(utils.ShipyardHome(), "logs", "local_service_api.log"), ls.LogFile)
+	assert.Equal(t, 0, ls.PID)
+	assert.Contains(t, ls.DependsOn, "container.db")
+}
+
+func TestLocalServiceReadsPIDFromState(t *testing.T) {
+	defer setupTemplateHome(t)()
+
+	c, _, cleanup := setupTestConfig(t, localServiceValid)
+	defer cleanup()
+
+	r, _ := c.FindResource("local_service.api")
+	r.(*LocalService).PID = 1234
+	r.(*LocalService).StartTime = "Sat Oct 17 10:00:00 2026"
+	r.Info().Status = Applied
+	require.NoError(t, c.ToJSON(utils.StatePath()))
+
+	c2, _, cleanup2 := setupTestConfig(t, localServiceValid)
+	defer cleanup2()
+
+	r2, err := c2.FindResource("local_service.api")
+	require.NoError(t, err)
+	assert.Equal(t, 1234, r2.(*LocalService).PID)
+	assert.Equal(t, "Sat Oct 17 10:00:00 2026", r2.(*LocalService).StartTime)
+
+	s := New()
+	err = s.FromJSON(utils.StatePath())
+	require.NoError(t, err)
+
+	r3, err := s.FindResource("local_service.api")
+	require.NoError(t, err)
+	assert.Equal(t, 1234, r3.(*LocalService).PID)
+}
+
+func TestLocalServiceWithPIDReturnsError(t *testing.T) {
+	defer setupTemplateHome(t)()
+
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+	createNamedFile(t, dir, "*.hcl", localServiceWithPID)
+
+	c := New()
+	err := ParseFolder(dir, c, nil)
+	assert.Error(t, err)
+}
+
+const localServiceValid = `
+container "db" {
+	image {
+		name = "postgres:12"
+	}
+}
+
+local_service "api" {
+	depends_on = ["container.db"]
+
+	cmd = "go"
+	args = ["run", "main.go"]
+	working_directory = "./api"
+
+	env {
+		key = "DB_ADDR"
+		value = "localhost:5432"
+	}
+
+	health_check {
+		timeout = "30s"
+		http = "http://localhost:8080/health"
+	}
+}
+`
+
+const localServiceWithPID = `
+local_service "api" {
+	cmd = "go"
+	pid = 1
+}
+`
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// TypeLocals is the block type for locals blocks, locals do not have a name
+// and are not resources
+const TypeLocals ResourceType = "locals"
+
+// local is an expression defined in a locals block
+type local struct {
+	name string
+	file string
+	expr hcl.Expression
+}
+
+// setLocals evaluates the values defined in any locals blocks and adds them to
+// the context as local.name. Locals can reference variables and other locals,
+// locals are evaluated once all the locals they reference have been evaluated.
+func (p *parser) setLocals(blocks []fileBlock) error {
+	pending := []local{}
+	defined := map[string]string{}
+
+	for _, fb := range blocks {
+		if fb.block.Type != string(TypeLocals) {
+			continue
+		}
+
+		attrs, diag := fb.block.Body.JustAttributes()
+		if diag.HasErrors() {
+			return newParseError(diag)
+		}
+
+		for n, a := range attrs {
+			if f, ok := defined[n]; ok {
+				return fmt.Errorf("Local %s is defined more than once, defined in file %s and %s", n, f, fb.file)
+			}
+
+			defined[n] = fb.file
+			pending = append(pending, local{name: n, file: fb.file, expr: a.Expr})
+		}
+	}
+
+	values := map[string]cty.Value{}
+	p.ctx.Variables["local"] = cty.ObjectVal(values)
+
+	for len(pending) > 0 {
+		remaining := []local{}
+
+		for _, l := range pending {
+			if !localResolved(l, values) {
+				remaining = append(remaining, l)
+				continue
+			}
+
+			v, diag := l.expr.Value(p.ctx)
+			if diag.HasErrors() {
+				return fmt.Errorf("Unable to evaluate local %s defined in file %s: %s", l.name, l.file, diag.Error())
+			}
+
+			values[l.name] = v
+			p.ctx.Variables["local"] = cty.ObjectVal(values)
+		}
+
+		// no locals could be evaluated, the locals reference locals
+		// which do not exist or have circular references
+		if len(remaining) == len(pending) {
+			names := []string{}
+			for _, l := range remaining {
+				names = append(names, l.name)
+			}
+			sort.Strings(names)
+
+			return fmt.Errorf("Unable to evaluate locals %s, locals reference locals which do not exist or have circular references", strings.Join(names, ", "))
+		}
+
+		pending = remaining
+	}
+
+	return nil
+}
+
+// localResolved returns true when all the locals referenced by the
+// local have been evaluated
+func localResolved(l local, values map[string]cty.Value) bool {
+	for _, t := range l.expr.Variables() {
+		if t.RootName() != "local" || len(t) < 2 {
+			continue
+		}
+
+		a, ok := t[1].(hcl.TraverseAttr)
+		if !ok {
+			continue
+		}
+
+		if _, ok := values[a.Name]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
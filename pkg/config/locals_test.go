@@ -0,0 +1,91 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalsAreUsedInExpressions(t *testing.T) {
+	c, err := setupVariableConfig(t, nil, localsDefault)
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, "consul:1.7.2", co.(*Container).Image.Name)
+	assert.Equal(t, "consul-dc1", co.(*Container).Environment[0].Value)
+}
+
+func TestLocalsUseVariableOverrides(t *testing.T) {
+	c, err := setupVariableConfig(t, map[string]string{"version": "1.8.0"}, localsDefault)
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, "consul:1.8.0", co.(*Container).Image.Name)
+}
+
+func TestLocalsDefinedInAnotherFileAreUsed(t *testing.T) {
+	c, err := setupVariableConfig(t, nil, localsDefinitions, localsReference)
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, "consul:1.7.2", co.(*Container).Image.Name)
+}
+
+func TestLocalsDefinedTwiceReturnsError(t *testing.T) {
+	_, err := setupVariableConfig(t, nil, localsDefinitions, localsDefinitions)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Local image is defined more than once")
+}
+
+func TestLocalsWithCircularReferenceReturnsError(t *testing.T) {
+	_, err := setupVariableConfig(t, nil, localsCircular)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "a, b")
+}
+
+const localsDefault = `
+variable "version" {
+	default = "1.7.2"
+}
+
+locals {
+	dc = "dc1"
+	name = "consul-${local.dc}"
+	image = "consul:${var.version}"
+}
+
+container "consul" {
+	image {
+		name = local.image
+	}
+
+	env {
+		key = "NAME"
+		value = local.name
+	}
+}
+`
+
+const localsDefinitions = `
+locals {
+	image = "consul:1.7.2"
+}
+`
+
+const localsReference = `
+container "consul" {
+	image {
+		name = local.image
+	}
+}
+`
+
+const localsCircular = `
+locals {
+	a = local.b
+	b = local.a
+}
+`
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/shipyard-run/shipyard/pkg/utils"
+)
+
+// TypeMinIO is the resource string for a MinIO resource
+const TypeMinIO ResourceType = "minio"
+
+// MinIO runs a MinIO server to provide S3 compatible object storage, buckets
+// and users are created once the server has started. The endpoint and
+// credentials can be referenced by other resources e.g. minio.storage.env
+type MinIO struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	Networks []NetworkAttachment `hcl:"network,block" json:"networks,omitempty"` // networks to attach the server to
+
+	// Version of MinIO to run
+	Version string `hcl:"version,optional" json:"version,omitempty"`
+
+	// Port is the port on the local machine the server is exposed on
+	Port int `hcl:"port,optional" json:"port"`
+
+	// AccessKey and SecretKey are the credentials for the root user, defaults
+	// to minio and minio123
+	AccessKey string `hcl:"access_key,optional" json:"access_key" mapstructure:"access_key"`
+	SecretKey string `hcl:"secret_key,optional" json:"secret_key" mapstructure:"secret_key" sensitive:"true"`
+
+	// Buckets to create
+	Buckets []string `hcl:"buckets,optional" json:"buckets,omitempty"`
+
+	// Users are additional access keys to create
+	Users []MinIOUser `hcl:"user,block" json:"users,omitempty"`
+
+	// Address is the endpoint on the local machine e.g. http://localhost:9000,
+	// InternalAddress is the endpoint used by resources attached to the same
+	// network
+	Address         string `json:"address"`
+	InternalAddress string `json:"internal_address" mapstructure:"internal_address"`
+
+	// Env contains the environment variables used by the S3 SDKs and CLI to
+	// connect to the server as the root user
+	Env map[string]string `json:"env"`
+}
+
+// MinIOUser is an access key created on the MinIO server
+type MinIOUser struct {
+	AccessKey string `hcl:"access_key" json:"access_key" mapstructure:"access_key"`
+	SecretKey string `hcl:"secret_key" json:"secret_key" mapstructure:"secret_key" sensitive:"true"`
+
+	// Policy is the canned policy attached to the user, defaults to readwrite
+	Policy string `hcl:"policy,optional" json:"policy,omitempty"`
+}
+
+// NewMinIO creates a new MinIO config resource
+func NewMinIO(name string) *MinIO {
+	return &MinIO{
+		ResourceInfo: ResourceInfo{Name: name, Type: TypeMinIO, Status: PendingCreation},
+		Version:      "RELEASE.2021-01-16T02-19-44Z",
+		Port:         9000,
+		AccessKey:    "minio",
+		SecretKey:    "minio123",
+	}
+}
+
+// setAddress sets the addresses and environment variables for the server
+func (m *MinIO) setAddress() {
+	m.Address = fmt.Sprintf("http://localhost:%d", m.Port)
+	m.InternalAddress = fmt.Sprintf("http://%s:9000", utils.FQDN(m.Name, string(m.Type)))
+
+	m.Env = map[string]string{
+		"AWS_ACCESS_KEY_ID":     m.AccessKey,
+		"AWS_SECRET_ACCESS_KEY": m.SecretKey,
+		"AWS_ENDPOINT_URL":      m.InternalAddress,
+	}
+}
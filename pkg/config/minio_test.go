@@ -0,0 +1,75 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMinIOCreatesCorrectly(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, minioValid)
+	defer cleanup()
+
+	r, err := c.FindResource("minio.storage")
+	require.NoError(t, err)
+
+	m := r.(*MinIO)
+	assert.Equal(t, []string{"images", "backups"}, m.Buckets)
+	assert.Equal(t, "http://localhost:9001", m.Address)
+	assert.Equal(t, "http://storage.minio.shipyard.run:9000", m.InternalAddress)
+	assert.Equal(t, "readwrite", m.Users[0].Policy)
+	assert.Equal(t, "readonly", m.Users[1].Policy)
+	assert.Equal(t, "admin", m.Env["AWS_ACCESS_KEY_ID"])
+	assert.Contains(t, m.DependsOn, "network.local")
+}
+
+func TestMinIOEnvCanBeReferenced(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, minioValid)
+	defer cleanup()
+
+	r, err := c.FindResource("container.app")
+	require.NoError(t, err)
+
+	assert.Contains(t, r.(*Container).Environment, KV{Key: "S3_ENDPOINT", Value: "http://storage.minio.shipyard.run:9000"})
+	assert.Contains(t, r.Info().DependsOn, "minio.storage")
+}
+
+const minioValid = `
+network "local" {
+	subnet = "10.6.0.0/16"
+}
+
+minio "storage" {
+	port       = 9001
+	access_key = "admin"
+	secret_key = "Adm1nS3cret"
+	buckets    = ["images", "backups"]
+
+	user {
+		access_key = "app"
+		secret_key = "AppS3cret"
+	}
+
+	user {
+		access_key = "reader"
+		secret_key = "ReaderS3cret"
+		policy     = "readonly"
+	}
+
+	network {
+		name = "network.local"
+	}
+}
+
+container "app" {
+	image {
+		name = "app:latest"
+	}
+
+	env {
+		key   = "S3_ENDPOINT"
+		value = minio.storage.internal_address
+	}
+}
+`
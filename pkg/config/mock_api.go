@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shipyard-run/shipyard/pkg/utils"
+)
+
+// TypeMockAPI is the resource string for a MockAPI resource
+const TypeMockAPI ResourceType = "mock_api"
+
+// MockAPI serves a mock HTTP API on a network, responses are either
+// generated from the examples in an OpenAPI spec or defined with route
+// blocks
+type MockAPI struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	Networks []NetworkAttachment `hcl:"network,block" json:"networks,omitempty"` // networks to attach the API to
+
+	// Spec is an OpenAPI spec in YAML or JSON format
+	Spec string `hcl:"spec,optional" json:"spec,omitempty"`
+
+	// Routes are the requests the API responds to
+	Routes []MockRoute `hcl:"route,block" json:"routes,omitempty"`
+
+	// Port is the port on the local machine the API is exposed on, the API is
+	// only available on the network when not set
+	Port int `hcl:"port,optional" json:"port,omitempty"`
+
+	// Address is the address of the API on the local machine, InternalAddress
+	// is the address used by resources attached to the same network
+	Address         string `json:"address,omitempty"`
+	InternalAddress string `json:"internal_address" mapstructure:"internal_address"`
+}
+
+// MockRoute is a request and the response returned by a MockAPI
+type MockRoute struct {
+	// Method is the HTTP method of the request, defaults to GET
+	Method string `hcl:"method,optional" json:"method"`
+	// Path of the request e.g. /users/1
+	Path string `hcl:"path" json:"path"`
+
+	// Status is the status code of the response, defaults to 200
+	Status  int               `hcl:"status,optional" json:"status"`
+	Body    string            `hcl:"body,optional" json:"body,omitempty"`
+	Headers map[string]string `hcl:"headers,optional" json:"headers,omitempty"`
+}
+
+// NewMockAPI creates a new MockAPI config resource
+func NewMockAPI(name string) *MockAPI {
+	return &MockAPI{ResourceInfo: ResourceInfo{Name: name, Type: TypeMockAPI, Status: PendingCreation}}
+}
+
+// ListenPort returns the port the API listens on in the container, the
+// port differs for OpenAPI specs and routes
+func (m *MockAPI) ListenPort() int {
+	if m.Spec != "" {
+		return 4010
+	}
+
+	return 8080
+}
+
+// setDefaults sets the defaults for the routes and the addresses of the API
+func (m *MockAPI) setDefaults() {
+	for i := range m.Routes {
+		if m.Routes[i].Method == "" {
+			m.Routes[i].Method = "GET"
+		}
+
+		m.Routes[i].Method = strings.ToUpper(m.Routes[i].Method)
+
+		if m.Routes[i].Status == 0 {
+			m.Routes[i].Status = 200
+		}
+	}
+
+	if m.Port > 0 {
+		m.Address = fmt.Sprintf("http://localhost:%d", m.Port)
+	}
+
+	m.InternalAddress = fmt.Sprintf("http://%s:%d", utils.FQDN(m.Name, string(m.Type)), m.ListenPort())
+}
+
+// Validate the MockAPI and return errors
+func (m *MockAPI) Validate() []error {
+	if m.Spec == "" && len(m.Routes) == 0 {
+		return []error{fmt.Errorf("either spec or at least one route must be set")}
+	}
+
+	if m.Spec != "" && len(m.Routes) > 0 {
+		return []error{fmt.Errorf("spec and route can not be used together")}
+	}
+
+	errs := []error{}
+	for _, r := range m.Routes {
+		if !strings.HasPrefix(r.Path, "/") {
+			errs = append(errs, fmt.Errorf("invalid path %s for route, paths must start with /", r.Path))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMockAPICreatesCorrectly(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, mockAPIValid)
+	defer cleanup()
+
+	r, err := c.FindResource("mock_api.payments")
+	require.NoError(t, err)
+
+	m := r.(*MockAPI)
+	require.Len(t, m.Routes, 2)
+	assert.Equal(t, "GET", m.Routes[0].Method)
+	assert.Equal(t, 200, m.Routes[0].Status)
+	assert.Equal(t, "POST", m.Routes[1].Method)
+	assert.Equal(t, 201, m.Routes[1].Status)
+	assert.Equal(t, "http://localhost:9090", m.Address)
+	assert.Equal(t, "http://payments.mock_api.shipyard.run:8080", m.InternalAddress)
+	assert.Contains(t, m.DependsOn, "network.local")
+}
+
+func TestMockAPIWithSpecSetsAddress(t *testing.T) {
+	c, dir, cleanup := setupTestConfig(t, mockAPISpec)
+	defer cleanup()
+
+	r, err := c.FindResource("mock_api.users")
+	require.NoError(t, err)
+
+	m := r.(*MockAPI)
+	assert.Equal(t, filepath.Join(dir, "openapi.yaml"), m.Spec)
+	assert.Empty(t, m.Address)
+	assert.Equal(t, "http://users.mock_api.shipyard.run:4010", m.InternalAddress)
+}
+
+func TestMockAPIValidate(t *testing.T) {
+	m := NewMockAPI("api")
+	assert.Len(t, m.Validate(), 1)
+
+	m.Routes = []MockRoute{{Path: "users"}}
+	assert.Len(t, m.Validate(), 1)
+
+	m.Spec = "./openapi.yaml"
+	assert.Len(t, m.Validate(), 1)
+
+	m.Routes = nil
+	assert.Len(t, m.Validate(), 0)
+}
+
+const mockAPIValid = `
+network "local" {
+	subnet = "10.6.0.0/16"
+}
+
+mock_api "payments" {
+	port = 9090
+
+	network {
+		name = "network.local"
+	}
+
+	route {
+		path = "/payments"
+		body = "[]"
+
+		headers = {
+			"Content-Type" = "application/json"
+		}
+	}
+
+	route {
+		method = "post"
+		path   = "/payments"
+		status = 201
+	}
+}
+`
+
+const mockAPISpec = `
+mock_api "users" {
+	spec = "./openapi.yaml"
+}
+`
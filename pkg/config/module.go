@@ -11,6 +11,9 @@ type Module struct {
 	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
 
 	Source string `hcl:"source" json:"source"`
+
+	// Variables sets the values of variables defined in the module
+	Variables map[string]string `hcl:"variables,optional" json:"variables,omitempty"`
 }
 
 // NewModule creates a new Module config resource
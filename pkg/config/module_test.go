@@ -0,0 +1,328 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupModuleConfig(t *testing.T, parent string) (*Config, error) {
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+
+	// create the module and a nested module in sub folders
+	md := filepath.Join(dir, "consul")
+	os.MkdirAll(md, os.ModePerm)
+	createNamedFile(t, md, "*.hcl", moduleConsul)
+
+	nd := filepath.Join(md, "nested")
+	os.MkdirAll(nd, os.ModePerm)
+	createNamedFile(t, nd, "*.hcl", moduleNested)
+
+	createNamedFile(t, dir, "*.hcl", parent)
+
+	c := New()
+	err := ParseFolder(dir, c, nil)
+
+	return c, err
+}
+
+func TestModuleResourcesAreAddedWithNamespace(t *testing.T) {
+	c, err := setupModuleConfig(t, moduleParent)
+	assert.NoError(t, err)
+
+	n, err := c.FindResource("network.cloud")
+	assert.NoError(t, err)
+	assert.Equal(t, "", n.Info().Module)
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, "consul", co.Info().Module)
+
+	ne, err := c.FindResource("container.nested")
+	assert.NoError(t, err)
+	assert.Equal(t, "consul.nested", ne.Info().Module)
+}
+
+func TestModuleVariablesArePassedToModule(t *testing.T) {
+	c, err := setupModuleConfig(t, moduleParent)
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, "consul:1.8.0", co.(*Container).Image.Name)
+}
+
+func TestModuleVariablesUseDefaultWhenNotSet(t *testing.T) {
+	c, err := setupModuleConfig(t, moduleParentNoVariables)
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, "consul:1.7.2", co.(*Container).Image.Name)
+}
+
+func TestModuleDependsOnIsAddedToResources(t *testing.T) {
+	c, err := setupModuleConfig(t, moduleParent)
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Contains(t, co.Info().DependsOn, "network.cloud")
+
+	ne, err := c.FindResource("container.nested")
+	assert.NoError(t, err)
+	assert.Contains(t, ne.Info().DependsOn, "network.cloud")
+}
+
+func TestModuleWithUndefinedVariableReturnsError(t *testing.T) {
+	_, err := setupModuleConfig(t, fmt.Sprintf(moduleParentTemplate, `nope = "abc"`))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "module consul")
+}
+
+func TestModuleIsSavedInState(t *testing.T) {
+	c, cleanup := setupConfigTests(t)
+	defer cleanup()
+
+	co, _ := c.FindResource("container.config")
+	co.Info().Module = "consul"
+
+	err := c.ToJSON(utils.StatePath())
+	assert.NoError(t, err)
+
+	c2 := New()
+	err = c2.FromJSON(utils.StatePath())
+	assert.NoError(t, err)
+
+	co2, err := c2.FindResource("container.config")
+	assert.NoError(t, err)
+	assert.Equal(t, "consul", co2.Info().Module)
+}
+
+func TestModuleResourcesAreAddedAfterFolderResources(t *testing.T) {
+	c, err := setupModuleConfig(t, moduleParentBeforeNetwork)
+	assert.NoError(t, err)
+
+	names := []string{}
+	for _, r := range c.Resources {
+		names = append(names, fmt.Sprintf("%s.%s", r.Info().Type, r.Info().Name))
+	}
+
+	assert.Equal(t, []string{"network.cloud", "container.consul", "container.nested"}, names)
+}
+
+func TestModuleCanBeUsedMoreThanOnce(t *testing.T) {
+	c, err := setupModuleConfig(t, moduleParentTwice)
+	assert.NoError(t, err)
+
+	one, err := c.FindResource("module.one.container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, "consul:1.8.0", one.(*Container).Image.Name)
+
+	two, err := c.FindResource("module.two.container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, "consul:1.9.0", two.(*Container).Image.Name)
+
+	_, err = c.FindResource("module.two.nested.container.nested")
+	assert.NoError(t, err)
+
+	// the name without the module is ambiguous
+	_, err = c.FindResource("container.consul")
+	assert.Error(t, err)
+
+	_, err = c.DoYaLikeDAGs()
+	assert.NoError(t, err)
+}
+
+func TestModuleDependenciesResolveInsideModule(t *testing.T) {
+	c := New()
+
+	for _, m := range []string{"one", "two"} {
+		n := NewNetwork("local")
+		n.Module = m
+		assert.NoError(t, c.AddResource(n))
+
+		co := NewContainer("app")
+		co.Module = m
+		co.DependsOn = []string{"network.local"}
+		assert.NoError(t, c.AddResource(co))
+	}
+
+	co, _ := c.FindResource("module.two.container.app")
+	d, err := c.FindDependency(co, "network.local")
+	assert.NoError(t, err)
+	assert.Equal(t, "module.two.network.local", ResourceID(d))
+
+	d, err = co.FindDependentResource("network.local")
+	assert.NoError(t, err)
+	assert.Equal(t, "two", d.Info().Module)
+}
+
+func TestModuleResourcesWithSameNameAreLoadedFromState(t *testing.T) {
+	c, err := setupModuleConfig(t, moduleParentTwice)
+	assert.NoError(t, err)
+
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+
+	state := filepath.Join(dir, "state.json")
+	d, err := json.Marshal(c)
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(state, d, os.ModePerm))
+
+	sc := New()
+	err = sc.FromJSON(state)
+	assert.NoError(t, err)
+	assert.Len(t, sc.Resources, len(c.Resources))
+
+	_, err = sc.FindResource("module.one.container.consul")
+	assert.NoError(t, err)
+
+	_, err = sc.FindResource("module.two.container.consul")
+	assert.NoError(t, err)
+}
+
+func TestModuleResourceCanBeReferencedInDependsOn(t *testing.T) {
+	c, err := setupModuleConfig(t, moduleParentTwice+moduleDependsOnModule)
+	assert.NoError(t, err)
+
+	err = ParseReferences(c)
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("container.app")
+	assert.NoError(t, err)
+	assert.Contains(t, co.Info().DependsOn, "module.one.container.consul")
+
+	for _, e := range c.Validate() {
+		assert.NotContains(t, e.Error(), "container.app")
+	}
+}
+
+func TestModuleResourceCanHaveSameNameAsParentResource(t *testing.T) {
+	c, err := setupModuleConfig(t, moduleParent+moduleParentSameName)
+	assert.NoError(t, err)
+
+	// the id without a module is the resource in the parent
+	p, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, "consul:parent", p.(*Container).Image.Name)
+
+	m, err := c.FindResource("module.consul.container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, "consul:1.8.0", m.(*Container).Image.Name)
+
+	err = ParseReferences(c)
+	assert.NoError(t, err)
+}
+
+func TestValidResourceID(t *testing.T) {
+	assert.True(t, validResourceID("container.web"))
+	assert.True(t, validResourceID("module.one.container.web"))
+	assert.True(t, validResourceID("module.one.nested.container.web"))
+
+	assert.False(t, validResourceID("web"))
+	assert.False(t, validResourceID("container."))
+	assert.False(t, validResourceID("container.web.extra"))
+	assert.False(t, validResourceID("module.one.container"))
+	assert.False(t, validResourceID("module..container.web"))
+}
+
+const moduleParentSameName = `
+container "consul" {
+	image {
+		name = "consul:parent"
+	}
+}
+`
+
+const moduleDependsOnModule = `
+container "app" {
+	image {
+		name = "alpine"
+	}
+
+	depends_on = ["module.one.container.consul"]
+}
+`
+
+const moduleParentTwice = `
+module "one" {
+	source = "./consul"
+
+	variables = {
+		version = "1.8.0"
+	}
+}
+
+module "two" {
+	source = "./consul"
+
+	variables = {
+		version = "1.9.0"
+	}
+}
+`
+
+const moduleParentBeforeNetwork = `
+module "consul" {
+	source = "./consul"
+}
+
+network "cloud" {
+	subnet = "10.0.0.0/16"
+}
+`
+
+var moduleParentTemplate = `
+network "cloud" {
+	subnet = "10.0.0.0/16"
+}
+
+module "consul" {
+	source = "./consul"
+	depends_on = ["network.cloud"]
+
+	variables = {
+		%s
+	}
+}
+`
+
+var moduleParent = fmt.Sprintf(moduleParentTemplate, `version = "1.8.0"`)
+
+const moduleParentNoVariables = `
+module "consul" {
+	source = "./consul"
+}
+`
+
+const moduleConsul = `
+variable "version" {
+	default = "1.7.2"
+}
+
+container "consul" {
+	image {
+		name = "consul:${var.version}"
+	}
+}
+
+module "nested" {
+	source = "./nested"
+}
+`
+
+const moduleNested = `
+container "nested" {
+	image {
+		name = "alpine"
+	}
+}
+`
@@ -1,5 +1,10 @@
 package config
 
+import (
+	"fmt"
+	"net"
+)
+
 // TypeNetwork is the string resource type for Network resources
 const TypeNetwork ResourceType = "network"
 
@@ -8,9 +13,26 @@ type Network struct {
 	ResourceInfo
 
 	Subnet string `hcl:"subnet" json:"subnet"`
+
+	// DockerHost is the address of the Docker engine the network is created on
+	// e.g. tcp://10.5.0.2:2376 or ssh://user@host, defaults to the local engine
+	DockerHost string `hcl:"docker_host,optional" json:"docker_host,omitempty" mapstructure:"docker_host"`
 }
 
 // NewNetwork creates a new Network resource with the correct defaults
 func NewNetwork(name string) *Network {
 	return &Network{ResourceInfo: ResourceInfo{Name: name, Type: TypeNetwork, Status: PendingCreation}}
 }
+
+// Validate the Network and return errors
+func (n *Network) Validate() []error {
+	if _, _, err := net.ParseCIDR(n.Subnet); err != nil {
+		return []error{fmt.Errorf("invalid subnet %s, subnet must be a CIDR block e.g. 10.5.0.0/16", n.Subnet)}
+	}
+
+	if err := validateDockerHost(n.DockerHost); err != nil {
+		return []error{err}
+	}
+
+	return nil
+}
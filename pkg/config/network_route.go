@@ -0,0 +1,51 @@
+package config
+
+import "fmt"
+
+// TypeNetworkRoute is the resource string for a NetworkRoute resource
+const TypeNetworkRoute ResourceType = "network_route"
+
+// NetworkRoute allows resources attached to one network to connect to
+// resources attached to another network, by default Docker isolates networks
+// from each other
+type NetworkRoute struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	// Networks are the two networks to route between e.g.
+	// ["network.onprem", "network.cloud"]
+	Networks []string `hcl:"networks" json:"networks"`
+
+	// Ports limits the traffic to the given TCP ports, all traffic is allowed
+	// when not set
+	Ports []int `hcl:"ports,optional" json:"ports,omitempty"`
+
+	// OneWay only allows connections to be opened from the first network to
+	// the second, replies are always allowed
+	OneWay bool `hcl:"one_way,optional" json:"one_way,omitempty" mapstructure:"one_way"`
+}
+
+// NewNetworkRoute creates a new NetworkRoute config resource
+func NewNetworkRoute(name string) *NetworkRoute {
+	return &NetworkRoute{ResourceInfo: ResourceInfo{Name: name, Type: TypeNetworkRoute, Status: PendingCreation}}
+}
+
+// Validate the NetworkRoute and return errors
+func (n *NetworkRoute) Validate() []error {
+	errs := []error{}
+
+	if len(n.Networks) != 2 {
+		errs = append(errs, fmt.Errorf("networks must contain two networks, got %d", len(n.Networks)))
+	} else if n.Networks[0] == n.Networks[1] {
+		errs = append(errs, fmt.Errorf("networks must be different, got %s twice", n.Networks[0]))
+	}
+
+	for _, p := range n.Ports {
+		if p < 1 || p > 65535 {
+			errs = append(errs, fmt.Errorf("invalid port %d, ports must be between 1 and 65535", p))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkRouteCreatesCorrectly(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, networkRouteValid)
+	defer cleanup()
+
+	r, err := c.FindResource("network_route.vpn")
+	require.NoError(t, err)
+
+	nr := r.(*NetworkRoute)
+	assert.Equal(t, []int{5432}, nr.Ports)
+	assert.True(t, nr.OneWay)
+	assert.Contains(t, nr.DependsOn, "network.onprem")
+	assert.Contains(t, nr.DependsOn, "network.cloud")
+}
+
+func TestNetworkRouteValidate(t *testing.T) {
+	nr := NewNetworkRoute("vpn")
+	nr.Networks = []string{"network.onprem", "network.cloud"}
+	assert.Len(t, nr.Validate(), 0)
+
+	nr.Networks = []string{"network.onprem", "network.onprem"}
+	assert.Len(t, nr.Validate(), 1)
+
+	nr.Networks = []string{"network.onprem"}
+	nr.Ports = []int{0}
+	assert.Len(t, nr.Validate(), 2)
+}
+
+const networkRouteValid = `
+network "onprem" {
+	subnet = "10.5.0.0/16"
+}
+
+network "cloud" {
+	subnet = "10.6.0.0/16"
+}
+
+network_route "vpn" {
+	networks = ["network.onprem", "network.cloud"]
+	ports    = [5432]
+	one_way  = true
+}
+`
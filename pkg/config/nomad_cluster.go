@@ -18,6 +18,22 @@ type NomadCluster struct {
 	Environment []KV     `hcl:"env,block" json:"environment,omitempty"`
 	Images      []Image  `hcl:"image,block" json:"images,omitempty"`
 	Volumes     []Volume `hcl:"volume,block" json:"volumes,omitempty"` // volumes to attach to the cluster
+
+	// HealthCheck defines the checks which must pass before the cluster is ready
+	HealthCheck *HealthCheck `hcl:"health_check,block" json:"health_check,omitempty" mapstructure:"health_check"`
+
+	// DockerHost is the address of the Docker engine the cluster is created on
+	// e.g. tcp://10.5.0.2:2376 or ssh://user@host, defaults to the local engine
+	DockerHost string `hcl:"docker_host,optional" json:"docker_host,omitempty" mapstructure:"docker_host"`
+}
+
+// Validate the NomadCluster and return errors
+func (n *NomadCluster) Validate() []error {
+	if err := validateDockerHost(n.DockerHost); err != nil {
+		return []error{err}
+	}
+
+	return nil
 }
 
 // NewCluster creates new Cluster config with the correct defaults
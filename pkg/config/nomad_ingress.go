@@ -18,9 +18,18 @@ type NomadIngress struct {
 	Task  string `hcl:"task" json:"task"`
 
 	Ports []Port `hcl:"port,block" json:"ports,omitempty"`
+
+	// Protocol is the type of traffic proxied by the ingress, http or tcp,
+	// tcp passes raw connections through to the target e.g. databases
+	Protocol string `hcl:"protocol,optional" json:"protocol,omitempty"`
 }
 
 // NewNomadIngress creates a new ingress with the correct defaults
 func NewNomadIngress(name string) *NomadIngress {
 	return &NomadIngress{ResourceInfo: ResourceInfo{Name: name, Type: TypeNomadIngress, Status: PendingCreation}}
 }
+
+// Validate the NomadIngress and return errors
+func (i *NomadIngress) Validate() []error {
+	return validateIngressProtocol(i.Protocol)
+}
@@ -0,0 +1,41 @@
+package config
+
+import "reflect"
+
+// TypeNullResource is the resource string for a NullResource resource
+const TypeNullResource ResourceType = "null_resource"
+
+// NullResource does nothing when it is created, it is used to group
+// dependencies so that several resources are complete before others start.
+// When the triggers change the resource is created again.
+type NullResource struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	// Triggers are arbitrary values, the resource is replaced when any of
+	// the values differ from the values in the state
+	Triggers map[string]string `hcl:"triggers,optional" json:"triggers,omitempty"`
+}
+
+// NewNullResource creates a new NullResource config resource
+func NewNullResource(name string) *NullResource {
+	return &NullResource{ResourceInfo: ResourceInfo{Name: name, Type: TypeNullResource, Status: PendingCreation}}
+}
+
+// checkTriggers marks the resource to be created again when the triggers
+// are different to the triggers of the applied resource in the state
+func (n *NullResource) checkTriggers(module string) {
+	s, ok := stateResource(module, n.Type, n.Name).(*NullResource)
+	if !ok || s.Status != Applied {
+		return
+	}
+
+	if len(s.Triggers) == 0 && len(n.Triggers) == 0 {
+		return
+	}
+
+	if !reflect.DeepEqual(s.Triggers, n.Triggers) {
+		n.Status = PendingModification
+	}
+}
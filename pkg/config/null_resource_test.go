@@ -0,0 +1,91 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNullResourceCreatesCorrectly(t *testing.T) {
+	defer setupTemplateHome(t)()
+
+	c, _, cleanup := setupTestConfig(t, nullResourceValid)
+	defer cleanup()
+
+	r, err := c.FindResource("null_resource.ready")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"version": "1"}, r.(*NullResource).Triggers)
+	assert.Equal(t, PendingCreation, r.Info().Status)
+	assert.Contains(t, r.Info().DependsOn, "container.db")
+
+	co, err := c.FindResource("container.app")
+	require.NoError(t, err)
+	assert.Contains(t, co.Info().DependsOn, "null_resource.ready")
+}
+
+func TestNullResourceIsReplacedWhenTriggersChange(t *testing.T) {
+	defer setupTemplateHome(t)()
+
+	c, _, cleanup := setupTestConfig(t, nullResourceValid)
+	defer cleanup()
+
+	r, _ := c.FindResource("null_resource.ready")
+	r.Info().Status = Applied
+	require.NoError(t, c.ToJSON(utils.StatePath()))
+
+	c2, _, cleanup2 := setupTestConfig(t, nullResourceValid)
+	defer cleanup2()
+
+	r2, _ := c2.FindResource("null_resource.ready")
+	assert.Equal(t, PendingCreation, r2.Info().Status)
+
+	c3, _, cleanup3 := setupTestConfig(t, strings.Replace(nullResourceValid, `version = "1"`, `version = "2"`, 1))
+	defer cleanup3()
+
+	r3, _ := c3.FindResource("null_resource.ready")
+	assert.Equal(t, PendingModification, r3.Info().Status)
+}
+
+func TestConfigMergeKeepsPendingModificationForAppliedResource(t *testing.T) {
+	c := New()
+	n := NewNullResource("ready")
+	n.Status = Applied
+	c.AddResource(n)
+
+	n2 := NewNullResource("ready")
+	n2.Status = PendingModification
+	c2 := New()
+	c2.AddResource(n2)
+
+	c.Merge(c2)
+
+	assert.Equal(t, PendingModification, c.Resources[0].Info().Status)
+}
+
+const nullResourceValid = `
+container "db" {
+	image {
+		name = "postgres:12"
+	}
+}
+
+null_resource "ready" {
+	depends_on = ["container.db"]
+
+	triggers = {
+		version = "1"
+	}
+}
+
+container "app" {
+	image {
+		name = "app:latest"
+	}
+
+	depends_on = ["null_resource.ready"]
+}
+`
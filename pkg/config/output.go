@@ -0,0 +1,121 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/hcl2/gohcl"
+	"github.com/hashicorp/hcl2/hcl"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// TypeOutput is the block type for an Output
+const TypeOutput ResourceType = "output"
+
+// Output is a value which is returned to the user after the
+// resources in a blueprint have been applied
+//
+//	output "consul_addr" {
+//	  description = "Address of the Consul server"
+//	  value       = "http://consul.container.shipyard.run:8500"
+//	}
+type Output struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Sensitive outputs are redacted when they are shown
+	Sensitive bool `json:"sensitive,omitempty"`
+	// Value is the evaluated value of the output, it is only set
+	// once Evaluate has been called
+	Value interface{} `json:"value"`
+
+	expr hcl.Expression
+	ctx  *hcl.EvalContext
+}
+
+// outputBody is the HCL schema for the output block
+type outputBody struct {
+	Description string         `hcl:"description,optional"`
+	Sensitive   bool           `hcl:"sensitive,optional"`
+	Value       hcl.Expression `hcl:"value"`
+}
+
+// NewOutput creates a new Output
+func NewOutput(name string) *Output {
+	return &Output{Name: name}
+}
+
+// Evaluate sets the Value of the output by evaluating the expression defined
+// in the config, outputs loaded from the state have no expression and keep
+// their existing value
+func (o *Output) Evaluate() error {
+	if o.expr == nil {
+		return nil
+	}
+
+	v, diag := o.expr.Value(o.ctx)
+	if diag.HasErrors() {
+		return fmt.Errorf("Unable to evaluate output %s: %s", o.Name, diag.Error())
+	}
+
+	if o.Sensitive {
+		markSensitiveValue(v)
+	}
+
+	// convert the value to a native type so it can be serialized
+	d, err := ctyjson.Marshal(v, v.Type())
+	if err != nil {
+		return fmt.Errorf("Unable to evaluate output %s: %s", o.Name, err)
+	}
+
+	var val interface{}
+	err = json.Unmarshal(d, &val)
+	if err != nil {
+		return fmt.Errorf("Unable to evaluate output %s: %s", o.Name, err)
+	}
+
+	o.Value = val
+
+	return nil
+}
+
+// FindOutput returns the output with the given name
+func (c *Config) FindOutput(name string) (*Output, error) {
+	for _, o := range c.Outputs {
+		if o.Name == name {
+			return o, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Output not found: %s", name)
+}
+
+// AddOutput adds an output to the config, an error is returned
+// if an output with the same name already exists
+func (c *Config) AddOutput(o *Output) error {
+	if _, err := c.FindOutput(o.Name); err == nil {
+		return fmt.Errorf("Output %s is defined more than once", o.Name)
+	}
+
+	c.Outputs = append(c.Outputs, o)
+
+	return nil
+}
+
+// decodeOutput decodes an output block, the value is not evaluated until
+// the resources have been applied
+func (p *parser) decodeOutput(b *hcl.Block) (*Output, error) {
+	ob := &outputBody{}
+
+	diag := gohcl.DecodeBody(b.Body, p.ctx, ob)
+	if diag.HasErrors() {
+		return nil, fmt.Errorf("Unable to decode output %s: %s", b.Labels[0], diag.Error())
+	}
+
+	o := NewOutput(b.Labels[0])
+	o.Description = ob.Description
+	o.Sensitive = ob.Sensitive
+	o.expr = ob.Value
+	o.ctx = p.ctx
+
+	return o, nil
+}
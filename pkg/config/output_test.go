@@ -0,0 +1,93 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutputIsParsedAndEvaluated(t *testing.T) {
+	c, err := setupVariableConfig(t, nil, outputDefault)
+	assert.NoError(t, err)
+
+	o, err := c.FindOutput("consul_addr")
+	assert.NoError(t, err)
+	assert.Equal(t, "Address of the Consul server", o.Description)
+	assert.Nil(t, o.Value)
+
+	err = o.Evaluate()
+	assert.NoError(t, err)
+	assert.Equal(t, "http://consul.container.shipyard.run:8500", o.Value)
+}
+
+func TestOutputWithComplexValueIsEvaluated(t *testing.T) {
+	c, err := setupVariableConfig(t, nil, outputDefault)
+	assert.NoError(t, err)
+
+	o, err := c.FindOutput("ports")
+	assert.NoError(t, err)
+
+	err = o.Evaluate()
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{float64(8500), float64(8501)}, o.Value)
+}
+
+func TestOutputDefinedTwiceReturnsError(t *testing.T) {
+	_, err := setupVariableConfig(t, nil, outputDefault, outputDefault)
+	assert.Error(t, err)
+}
+
+func TestOutputIsSavedInState(t *testing.T) {
+	c, cleanup := setupConfigTests(t)
+	defer cleanup()
+
+	o := NewOutput("addr")
+	o.Value = "http://localhost"
+	c.AddOutput(o)
+
+	err := c.ToJSON(utils.StatePath())
+	assert.NoError(t, err)
+
+	c2 := New()
+	err = c2.FromJSON(utils.StatePath())
+	assert.NoError(t, err)
+
+	o2, err := c2.FindOutput("addr")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost", o2.Value)
+
+	// outputs loaded from state keep their value
+	err = o2.Evaluate()
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost", o2.Value)
+}
+
+func TestConfigMergeReplacesOutputs(t *testing.T) {
+	c := New()
+	c.AddOutput(NewOutput("old"))
+
+	c2 := New()
+	c2.AddResource(NewContainer("consul"))
+	c2.AddOutput(NewOutput("new"))
+
+	c.Merge(c2)
+
+	assert.Len(t, c.Outputs, 1)
+	assert.Equal(t, "new", c.Outputs[0].Name)
+}
+
+const outputDefault = `
+variable "port" {
+	default = 8500
+}
+
+output "consul_addr" {
+	description = "Address of the Consul server"
+	value = "http://consul.container.shipyard.run:${var.port}"
+}
+
+output "ports" {
+	value = [var.port, 8501]
+}
+`
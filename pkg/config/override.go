@@ -0,0 +1,118 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+)
+
+// isOverrideFile returns true when the file is an override file,
+// override files are named override.hcl or end in _override.hcl
+func isOverrideFile(file string) bool {
+	name := filepath.Base(file)
+	return name == "override.hcl" || strings.HasSuffix(name, "_override.hcl")
+}
+
+// readFolderBlocks returns the blocks for the given files, override files are
+// read after all other files and merged into the blocks they override
+func readFolderBlocks(files []string) ([]fileBlock, error) {
+	blocks := []fileBlock{}
+	overrides := []fileBlock{}
+
+	for _, f := range files {
+		fb, err := readBlocks(f)
+		if err != nil {
+			return nil, err
+		}
+
+		if isOverrideFile(f) {
+			overrides = append(overrides, fb...)
+			continue
+		}
+
+		blocks = append(blocks, fb...)
+	}
+
+	for _, o := range overrides {
+		i := findBlock(blocks, o.block)
+		if i < 0 {
+			return nil, fmt.Errorf(
+				"Unable to override %s %s defined in file %s, the %s is not defined in the blueprint",
+				o.block.Type,
+				strings.Join(o.block.Labels, "."),
+				o.file,
+				o.block.Type,
+			)
+		}
+
+		b, err := mergeBlock(blocks[i].block, o.block)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to override %s %s defined in file %s: %s", o.block.Type, strings.Join(o.block.Labels, "."), o.file, err)
+		}
+
+		// the block is still decoded relative to the original file
+		blocks[i].block = b
+	}
+
+	return blocks, nil
+}
+
+func findBlock(blocks []fileBlock, b *hcl.Block) int {
+	for i, fb := range blocks {
+		if fb.block.Type == b.Type && strings.Join(fb.block.Labels, ".") == strings.Join(b.Labels, ".") {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// mergeBlock returns a new block with the attributes and nested blocks from
+// the override replacing those in the original block. Nested blocks replace
+// all of the nested blocks of the same type in the original, for example
+// setting a port block in the override replaces all ports for a container.
+// The original block is not modified.
+func mergeBlock(original, override *hcl.Block) (*hcl.Block, error) {
+	ob, ok := original.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, fmt.Errorf("only blocks defined using the native syntax can be overridden")
+	}
+
+	vb, ok := override.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, fmt.Errorf("override files must use the native syntax")
+	}
+
+	body := *ob
+	body.Attributes = hclsyntax.Attributes{}
+
+	for k, v := range ob.Attributes {
+		body.Attributes[k] = v
+	}
+
+	for k, v := range vb.Attributes {
+		body.Attributes[k] = v
+	}
+
+	overridden := map[string]bool{}
+	for _, b := range vb.Blocks {
+		overridden[b.Type] = true
+	}
+
+	body.Blocks = hclsyntax.Blocks{}
+	for _, b := range ob.Blocks {
+		if !overridden[b.Type] {
+			body.Blocks = append(body.Blocks, b)
+		}
+	}
+
+	body.Blocks = append(body.Blocks, vb.Blocks...)
+
+	nb := *original
+	nb.Body = &body
+
+	return &nb, nil
+}
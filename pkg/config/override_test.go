@@ -0,0 +1,124 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupOverrideConfig(t *testing.T, contents, override string) (*Config, error) {
+	dir := createTempDirectory(t)
+	defer removeTestFiles(t, dir)
+
+	createTestFile(t, dir, contents)
+	createNamedFile(t, dir, "*_override.hcl", override)
+
+	c := New()
+	err := ParseFolder(dir, c, nil)
+
+	return c, err
+}
+
+func TestOverrideReplacesAttributes(t *testing.T) {
+	c, err := setupOverrideConfig(t, overrideContainer, `
+container "consul" {
+	image {
+		name = "consul:1.8.0"
+	}
+
+	privileged = true
+}
+`)
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+
+	cc := co.(*Container)
+	assert.Equal(t, "consul:1.8.0", cc.Image.Name)
+	assert.True(t, cc.Privileged)
+
+	// attributes and blocks which are not overridden are kept
+	assert.Equal(t, []string{"consul", "agent"}, cc.Command)
+	assert.Len(t, cc.Ports, 2)
+}
+
+func TestOverrideReplacesAllNestedBlocksOfType(t *testing.T) {
+	c, err := setupOverrideConfig(t, overrideContainer, `
+container "consul" {
+	port {
+		local = "8600"
+		remote = "8600"
+	}
+}
+`)
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+
+	cc := co.(*Container)
+	assert.Len(t, cc.Ports, 1)
+	assert.Equal(t, "8600", cc.Ports[0].Local)
+	assert.Equal(t, "consul:1.7.2", cc.Image.Name)
+}
+
+func TestOverrideReplacesVariableDefault(t *testing.T) {
+	c, err := setupOverrideConfig(t, overrideContainer, `
+variable "version" {
+	default = "1.8.1"
+}
+
+container "consul" {
+	image {
+		name = "consul:${var.version}"
+	}
+}
+`)
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, "consul:1.8.1", co.(*Container).Image.Name)
+}
+
+func TestOverrideForUndefinedResourceReturnsError(t *testing.T) {
+	_, err := setupOverrideConfig(t, overrideContainer, `
+container "vault" {
+	privileged = true
+}
+`)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Unable to override container vault")
+}
+
+func TestIsOverrideFile(t *testing.T) {
+	assert.True(t, isOverrideFile("/tmp/override.hcl"))
+	assert.True(t, isOverrideFile("/tmp/consul_override.hcl"))
+	assert.False(t, isOverrideFile("/tmp/consul.hcl"))
+	assert.False(t, isOverrideFile("/tmp/override/consul.hcl"))
+}
+
+const overrideContainer = `
+variable "version" {
+	default = "1.7.2"
+}
+
+container "consul" {
+	image {
+		name = "consul:${var.version}"
+	}
+
+	command = ["consul", "agent"]
+
+	port {
+		local = "8500"
+		remote = "8500"
+	}
+
+	port {
+		local = "8501"
+		remote = "8501"
+	}
+}
+`
@@ -3,9 +3,11 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
 )
 
 func setup() func() {
@@ -23,7 +25,7 @@ func TestRunParsesBlueprintInMarkdownFormat(t *testing.T) {
 	}
 
 	c := New()
-	err = ParseFolder(absoluteFolderPath, c)
+	err = ParseFolder(absoluteFolderPath, c, nil)
 	assert.NoError(t, err)
 
 	assert.NotNil(t, c.Blueprint)
@@ -44,12 +46,213 @@ func TestRunParsesBlueprintInHCLFormat(t *testing.T) {
 	}
 
 	c := New()
-	err = ParseFolder(absoluteFolderPath, c)
+	err = ParseFolder(absoluteFolderPath, c, nil)
 	assert.NoError(t, err)
 
 	assert.NotNil(t, c.Blueprint)
 }
 
+func TestParseFolderWithDuplicateBlocksInDifferentFilesReturnsError(t *testing.T) {
+	dir, cleanup := createTestFiles(t, duplicateContainer, duplicateContainer)
+	defer cleanup()
+
+	c := New()
+	err := ParseFolder(dir, c, nil)
+	assert.Error(t, err)
+
+	de := DuplicateBlockError{}
+	assert.True(t, xerrors.As(err, &de))
+	assert.Equal(t, "container", de.Type)
+	assert.Equal(t, "web", de.Name)
+	assert.NotEqual(t, de.First.Filename, de.Duplicate.Filename)
+	assert.Equal(t, 2, de.First.Start.Line)
+
+	assert.Contains(t, err.Error(), de.First.Filename+":2")
+	assert.Contains(t, err.Error(), de.Duplicate.Filename+":2")
+}
+
+func TestParseHCLFileWithDuplicateBlocksReturnsError(t *testing.T) {
+	dir := createTempDirectory(t)
+	defer removeTestFiles(t, dir)
+
+	f := createTestFile(t, dir, duplicateContainer+duplicateContainer)
+
+	c := New()
+	err := ParseHCLFile(f, c, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), f+":2")
+	assert.Contains(t, err.Error(), f+":9")
+}
+
+func TestParseFolderWithDuplicateCountInstanceReturnsError(t *testing.T) {
+	_, err := setupVariableConfig(t, nil, duplicateCount)
+	assert.Error(t, err)
+	assert.True(t, xerrors.As(err, &ResourceExistsError{}))
+}
+
+func TestConfigFilesAreSortedByName(t *testing.T) {
+	dir := createTempDirectory(t)
+	defer removeTestFiles(t, dir)
+
+	c := createNamedFile(t, dir, "c*.hcl", "")
+	a := createNamedFile(t, dir, "a*.yard.yaml", "")
+	createNamedFile(t, dir, "e*.yaml", "")
+	b := createNamedFile(t, dir, "b*.hcl.json", "")
+	createNamedFile(t, dir, "d*.txt", "")
+
+	files, err := ConfigFiles(dir, 0)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{a, b, c}, files)
+}
+
+func TestParseFolderWithMissingDependencyReturnsError(t *testing.T) {
+	dir, cleanup := createTestFiles(t, missingDependency)
+	defer cleanup()
+
+	c := New()
+	err := ParseFolder(dir, c, nil)
+	assert.Error(t, err)
+
+	de := DependencyError{}
+	assert.True(t, xerrors.As(err, &de))
+	assert.Equal(t, "container.web", de.Resource)
+	assert.Equal(t, "network.nope", de.Dependency)
+	assert.Equal(t, 8, de.Range.Start.Line)
+	assert.Contains(t, err.Error(), de.Range.Filename+":8")
+}
+
+func TestParseFolderWithInvalidDependencyReturnsError(t *testing.T) {
+	_, err := setupVariableConfig(t, nil, invalidDependency)
+	assert.Error(t, err)
+
+	de := DependencyError{}
+	assert.True(t, xerrors.As(err, &de))
+	assert.Equal(t, "nope", de.Dependency)
+	assert.Contains(t, err.Error(), "is not a valid reference")
+}
+
+func TestParseFolderWithDependencyDefinedLaterSucceeds(t *testing.T) {
+	_, err := setupVariableConfig(t, nil, dependencyDefinedLater)
+	assert.NoError(t, err)
+}
+
+func TestParseHCLStringAddsResources(t *testing.T) {
+	c := New()
+	err := ParseHCLString(dependencyDefinedLater, "/tmp/config.hcl", c, nil)
+	assert.NoError(t, err)
+
+	_, err = c.FindResource("container.web")
+	assert.NoError(t, err)
+
+	n, err := c.FindResource("network.cloud")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.0/16", n.(*Network).Subnet)
+}
+
+func TestParseHCLReaderUsesFilenameForSyntax(t *testing.T) {
+	c := New()
+	err := ParseHCL(strings.NewReader(yamlNetwork), "/tmp/config.yaml", c, nil)
+	assert.NoError(t, err)
+
+	_, err = c.FindResource("network.cloud")
+	assert.NoError(t, err)
+}
+
+func TestParseHCLStringWithInvalidConfigReturnsFilename(t *testing.T) {
+	c := New()
+	err := ParseHCLString(`container "web" {`, "/tmp/config.hcl", c, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "/tmp/config.hcl")
+}
+
+func TestParseHCLStringSetsVariables(t *testing.T) {
+	c := New()
+	err := ParseHCLString(hclWithVariable, "/tmp/config.hcl", c, map[string]string{"subnet": "10.1.0.0/16"})
+	assert.NoError(t, err)
+
+	n, err := c.FindResource("network.cloud")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.1.0.0/16", n.(*Network).Subnet)
+}
+
+const hclWithVariable = `
+variable "subnet" {
+  default = "10.0.0.0/16"
+}
+
+network "cloud" {
+  subnet = var.subnet
+}
+`
+
+const yamlNetwork = `
+network:
+  cloud:
+    subnet: 10.0.0.0/16
+`
+
+const missingDependency = `
+container "web" {
+	image {
+		name = "nginx"
+	}
+
+	depends_on = [
+		"network.nope",
+	]
+}
+`
+
+const invalidDependency = `
+container "web" {
+	image {
+		name = "nginx"
+	}
+
+	depends_on = ["nope"]
+}
+`
+
+const dependencyDefinedLater = `
+container "web" {
+	image {
+		name = "nginx"
+	}
+
+	depends_on = ["network.cloud"]
+}
+
+network "cloud" {
+	subnet = "10.0.0.0/16"
+}
+`
+
+const duplicateContainer = `
+container "web" {
+	image {
+		name = "nginx"
+	}
+}
+
+`
+
+const duplicateCount = `
+container "web-0" {
+	image {
+		name = "nginx"
+	}
+}
+
+container "web" {
+	count = 1
+
+	image {
+		name = "nginx"
+	}
+}
+`
+
 /*
 func TestSingleKubernetesCluster(t *testing.T) {
 	absoluteFolderPath, err := filepath.Abs("./examples/single-cluster-k8s")
@@ -61,7 +264,7 @@ func TestSingleKubernetesCluster(t *testing.T) {
 	defer tearDown()
 
 	c := New()
-	err = ParseFolder("./examples/single-cluster-k8s", c)
+	err = ParseFolder("./examples/single-cluster-k8s", c, nil)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, c)
@@ -127,7 +330,7 @@ func TestMultiCluster(t *testing.T) {
 	defer tearDown()
 
 	c := New()
-	err = ParseFolder(absoluteFolderPath, c)
+	err = ParseFolder(absoluteFolderPath, c, nil)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, c)
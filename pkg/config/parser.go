@@ -1,18 +1,18 @@
 package config
 
-// TODO how do we deal with multiple stanza with the same name
-
 import (
+	"bytes"
 	"context"
-	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
-	"path"
 	"path/filepath"
 	"strings"
 
 	"github.com/gernest/front"
 	"github.com/hashicorp/go-getter"
+	"github.com/hashicorp/hcl2/ext/dynblock"
 	"github.com/hashicorp/hcl2/gohcl"
 	"github.com/hashicorp/hcl2/hcl"
 	"github.com/hashicorp/hcl2/hcl/hclsyntax"
@@ -20,10 +20,35 @@ import (
 	"github.com/shipyard-run/shipyard/pkg/utils"
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
 	"golang.org/x/xerrors"
+	"sigs.k8s.io/yaml"
 )
 
-var ctx *hcl.EvalContext
+// parser decodes config files into a Config, a parser is created for each
+// folder or file which is parsed, modules and blueprint sources are parsed
+// with their own parser as they have their own variables and data directory
+type parser struct {
+	// ctx is the context used to evaluate expressions in the config
+	ctx *hcl.EvalContext
+
+	// folder is the folder being parsed by ParseFolder, it is used to
+	// determine the data directory for the blueprint
+	folder string
+
+	// module is the module being parsed including the names of any parent
+	// modules e.g. consul.nested, resources are namespaced with it when they
+	// are added to the config
+	module string
+
+	options ParseOptions
+}
+
+// child returns a parser for a module or source defined in the config
+// being parsed, the options are the same as the parent
+func (p *parser) child(module string) *parser {
+	return &parser{module: module, options: p.options}
+}
 
 type ResourceTypeNotExistError struct {
 	Type string
@@ -34,45 +59,298 @@ func (r ResourceTypeNotExistError) Error() string {
 	return fmt.Sprintf("Resource type %s defined in file %s, does not exist. Please check the documentation for supported resources. We love PRs if you would like to create a resource of this type :)", r.Type, r.File)
 }
 
-// ParseFolder for config entries
-func ParseFolder(folder string, c *Config) error {
-	ctx = buildContext()
+// DuplicateBlockError is returned when more than one block is
+// defined with the same type and name
+type DuplicateBlockError struct {
+	Type      string
+	Name      string
+	First     hcl.Range
+	Duplicate hcl.Range
+}
+
+func (d DuplicateBlockError) Error() string {
+	return fmt.Sprintf("%s %s is defined more than once, first defined at %s and again at %s", d.Type, d.Name, d.First, d.Duplicate)
+}
+
+// DependencyError is returned when the depends_on attribute of a block
+// references a resource which does not exist
+type DependencyError struct {
+	Resource   string
+	Dependency string
+	Reason     string
+	Range      hcl.Range
+}
+
+func (d DependencyError) Error() string {
+	return fmt.Sprintf("%s depends on %s which %s, defined at %s", d.Resource, d.Dependency, d.Reason, d.Range)
+}
+
+// MultipleBlueprintsError is returned when a folder contains more than
+// one file which defines the blueprint
+type MultipleBlueprintsError struct {
+	Files []string
+}
+
+func (m MultipleBlueprintsError) Error() string {
+	return fmt.Sprintf("A folder can only contain one blueprint, the blueprint is defined in the files %s", strings.Join(m.Files, ", "))
+}
+
+// ConfigFiles returns the config files in a folder in the order they are
+// parsed, files are sorted lexically by name regardless of format. Files
+// in sub folders up to maxDepth levels deep are returned after the
+// files in the folder. Override files are always applied after all other
+// files. Files which match the patterns in the folder's .shipyardignore
+// file are not returned.
+func ConfigFiles(folder string, maxDepth int) ([]string, error) {
+	abs, _ := filepath.Abs(folder)
+
+	rules, err := readIgnoreFile(abs)
+	if err != nil {
+		return nil, xerrors.Errorf("Unable to read %s: %w", ignoreFile, err)
+	}
+
+	folders, err := walkFolder(abs, rules, maxDepth)
+	if err != nil {
+		return nil, xerrors.Errorf("Unable to read folder %s: %w", abs, err)
+	}
+
+	files := []string{}
+	for _, f := range folders {
+		// config can be written using HCL, the JSON syntax or YAML, YAML
+		// files must use the .yard suffix as blueprints often contain other
+		// YAML files such as Kubernetes manifests and Helm values
+		ff, err := globFiles(abs, f, rules, "*.hcl", "*.hcl.json", "*.yard.yaml", "*.yard.yml")
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, ff...)
+	}
+
+	return files, nil
+}
+
+// ParseOptions control how config files are parsed
+type ParseOptions struct {
+	// MaxFolderDepth is the number of levels of sub folders which are
+	// searched for config files, the default of 0 only parses the files
+	// in the folder. Sub folders which contain modules should be excluded
+	// using a .shipyardignore file when setting a depth greater than 0.
+	MaxFolderDepth int
+
+	// Permissive reports unknown attributes and blocks as warnings rather
+	// than errors, the warnings are returned by ParseWarnings
+	Permissive bool
+
+	// ForceUpdate fetches blueprint sources again even when they already
+	// exist in the cache
+	ForceUpdate bool
+}
+
+// ParseFolderWithOptions parses a folder in the same way as ParseFolder,
+// opts control how the files in the folder are parsed
+func ParseFolderWithOptions(folder string, c *Config, variables map[string]string, opts ParseOptions) error {
+	p := &parser{options: opts}
+
+	return p.parseFolder(folder, c, variables)
+}
+
+// ParseFolder for config entries, variables contains values for any
+// variable blocks defined in the folder and overrides their defaults,
+// any *.vars files in the folder also set values for variables.
+// Files are parsed in the order returned by ConfigFiles, resources in
+// the folder are always decoded before the resources in any modules.
+func ParseFolder(folder string, c *Config, variables map[string]string) error {
+	return ParseFolderWithOptions(folder, c, variables, ParseOptions{})
+}
 
+func (p *parser) parseFolder(folder string, c *Config, variables map[string]string) error {
 	abs, _ := filepath.Abs(folder)
+	p.folder = abs
+
+	rules, err := readIgnoreFile(abs)
+	if err != nil {
+		return xerrors.Errorf("Unable to read %s: %w", ignoreFile, err)
+	}
 
 	// pick up the blueprint file
-	yardFilesHCL, err := filepath.Glob(path.Join(abs, "*.yard"))
+	yardFilesHCL, err := globFiles(abs, abs, rules, "*.yard")
 	if err != nil {
 		return err
 	}
 
-	yardFilesMD, err := filepath.Glob(path.Join(abs, "*.md"))
+	yardFilesMD, err := globFiles(abs, abs, rules, "*.md")
 	if err != nil {
 		return err
 	}
 
-	yardFiles := []string{}
-	yardFiles = append(yardFiles, yardFilesHCL...)
-	yardFiles = append(yardFiles, yardFilesMD...)
+	yardFile, err := blueprintFile(yardFilesHCL, yardFilesMD)
+	if err != nil {
+		return err
+	}
 
-	if len(yardFiles) > 0 {
-		err := ParseYardFile(yardFiles[0], c)
+	// check the version before reading any other files so that blueprints
+	// which use newer features fail with a clear error
+	if yardFile != "" {
+		err := checkBlueprintVersion(yardFile)
 		if err != nil {
-			return err
+			return xerrors.Errorf("Unable to parse blueprint %s: %w", yardFile, err)
 		}
 	}
 
-	// load files from the current folder
-	files, err := filepath.Glob(path.Join(abs, "*.hcl"))
+	files, err := ConfigFiles(abs, p.options.MaxFolderDepth)
+	if err != nil {
+		return err
+	}
+
+	// blocks from all files are decoded together so that resources can
+	// reference resources defined in other files
+	blocks, err := readFolderBlocks(files)
+	if err != nil {
+		return err
+	}
+
+	// variables files in the folder set the value of variables, values
+	// passed to ParseFolder take precedence over the files
+	varFiles, err := globFiles(abs, abs, rules, "*.vars")
+	if err != nil {
+		return err
+	}
+
+	overrides, err := parseVariablesFiles(varFiles)
+	if err != nil {
+		return err
+	}
+
+	for k, v := range variables {
+		overrides[k] = v
+	}
+
+	// the blocks are decoded in the same way as a single file with ParseHCL
+	err = p.decodeBlocks(blocks, c, overrides)
+	if err != nil {
+		return err
+	}
+
+	if yardFile == "" {
+		return nil
+	}
+
+	// the blueprint can reference variables so it is decoded using the
+	// context for the folder
+	err = p.parseYardFile(yardFile, c)
 	if err != nil {
 		return err
 	}
 
-	for _, f := range files {
-		err := ParseHCLFile(f, c)
+	return p.parseSources(c.Blueprint, c)
+}
+
+// blueprintFile returns the file which defines the blueprint for a folder,
+// .yard files take precedence over markdown files and only markdown files
+// with frontmatter define a blueprint. An error is returned when more than
+// one file could define the blueprint.
+func blueprintFile(yardFiles, mdFiles []string) (string, error) {
+	if len(yardFiles) > 1 {
+		return "", MultipleBlueprintsError{yardFiles}
+	}
+
+	if len(yardFiles) == 1 {
+		return yardFiles[0], nil
+	}
+
+	files := []string{}
+	for _, f := range mdFiles {
+		d, err := ioutil.ReadFile(f)
 		if err != nil {
+			return "", err
+		}
+
+		if bytes.HasPrefix(bytes.TrimSpace(d), []byte("---")) {
+			files = append(files, f)
+		}
+	}
+
+	if len(files) > 1 {
+		return "", MultipleBlueprintsError{files}
+	}
+
+	if len(files) == 1 {
+		return files[0], nil
+	}
+
+	return "", nil
+}
+
+// checkBlueprintVersion returns an error when the shipyard_version constraint
+// in the blueprint file is not satisfied by this version of Shipyard. Only the
+// constraint is read as the rest of the file may use features which are not
+// supported by this version.
+func checkBlueprintVersion(file string) error {
+	bp := &Blueprint{}
+
+	if filepath.Ext(file) != ".yard" {
+		c := &Config{}
+		err := parseYardMarkdown(file, c)
+		if err != nil || c.Blueprint == nil {
 			return err
 		}
+
+		return c.Blueprint.CheckVersion(Version)
+	}
+
+	f, err := parseHCL(file)
+	if err != nil {
+		return err
+	}
+
+	content, _, diag := f.Body.PartialContent(&hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{{Name: "shipyard_version"}},
+	})
+	if diag.HasErrors() {
+		return newParseError(diag)
+	}
+
+	if a, ok := content.Attributes["shipyard_version"]; ok {
+		diag := gohcl.DecodeExpression(a.Expr, nil, &bp.ShipyardVersion)
+		if diag.HasErrors() {
+			return newParseError(diag)
+		}
+	}
+
+	return bp.CheckVersion(Version)
+}
+
+// parseSources fetches the remote sources defined in the blueprint and
+// parses them into the config, sources are cached in the shipyard home
+// folder and are only fetched when they do not exist in the cache or when
+// the ForceUpdate option is set
+func (p *parser) parseSources(bp *Blueprint, c *Config) error {
+	for _, s := range bp.Sources {
+		addr := s.Address()
+		dst := utils.GetBlueprintLocalFolder(addr)
+
+		if p.options.ForceUpdate {
+			err := os.RemoveAll(dst)
+			if err != nil {
+				return xerrors.Errorf("Unable to remove cached source %s: %w", s.Name, err)
+			}
+		}
+
+		if _, err := os.Stat(dst); err != nil {
+			err := getFiles(addr, dst)
+			if err != nil {
+				return xerrors.Errorf("Unable to fetch source %s: %w", s.Name, err)
+			}
+		}
+
+		// sources have their own variables and blueprint, the blueprint
+		// must be restored after parsing
+		err := p.child(p.module).parseFolder(dst, c, nil)
+		c.Blueprint = bp
+		if err != nil {
+			return xerrors.Errorf("Unable to parse source %s: %w", s.Name, err)
+		}
 	}
 
 	return nil
@@ -80,33 +358,35 @@ func ParseFolder(folder string, c *Config) error {
 
 // ParseYardFile parses a blueprint configuration file
 func ParseYardFile(file string, c *Config) error {
+	p := &parser{}
+
+	return p.parseYardFile(file, c)
+}
+
+func (p *parser) parseYardFile(file string, c *Config) error {
 	if filepath.Ext(file) == ".yard" {
-		return parseYardHCL(file, c)
+		return p.parseYardHCL(file, c)
 	}
 
 	return parseYardMarkdown(file, c)
 }
 
-func parseYardHCL(file string, c *Config) error {
-	ctx = buildContext()
-
-	parser := hclparse.NewParser()
-
-	f, diag := parser.ParseHCLFile(file)
-	if diag.HasErrors() {
-		return errors.New(diag.Error())
+func (p *parser) parseYardHCL(file string, c *Config) error {
+	if p.ctx == nil {
+		p.ctx = buildContext(p.folder)
 	}
 
-	body, ok := f.Body.(*hclsyntax.Body)
-	if !ok {
-		return errors.New("Error getting body")
+	f, err := parseHCL(file)
+	if err != nil {
+		return err
 	}
 
 	bp := &Blueprint{}
 
-	diag = gohcl.DecodeBody(body, ctx, bp)
+	p.setFileFunctions(file)
+	diag := gohcl.DecodeBody(f.Body, p.ctx, bp)
 	if diag.HasErrors() {
-		return errors.New(diag.Error())
+		return newParseError(diag)
 	}
 
 	c.Blueprint = bp
@@ -145,6 +425,10 @@ func parseYardMarkdown(file string, c *Config) error {
 		bp.Slug = a
 	}
 
+	if a, ok := fr["shipyard_version"].(string); ok {
+		bp.ShipyardVersion = a
+	}
+
 	if a, ok := fr["browser_windows"].(string); ok {
 		bp.BrowserWindows = strings.Split(a, ",")
 	}
@@ -157,276 +441,1294 @@ func parseYardMarkdown(file string, c *Config) error {
 				bp.Environment = append(bp.Environment, KV{Key: parts[0], Value: parts[1]})
 			}
 		}
-	}
+	}
+
+	bp.Intro = body
+
+	c.Blueprint = bp
+	return nil
+}
+
+// ParseHCLFile parses a config file and adds it to the config, variables
+// contains values for any variable blocks defined in the file
+func ParseHCLFile(file string, c *Config, variables map[string]string) error {
+	return ParseHCLFileWithOptions(file, c, variables, ParseOptions{})
+}
+
+// ParseHCLFileWithOptions parses a config file in the same way as
+// ParseHCLFile, opts control how the file is parsed
+func ParseHCLFileWithOptions(file string, c *Config, variables map[string]string, opts ParseOptions) error {
+	d, err := readConfigFile(file)
+	if err != nil {
+		return err
+	}
+
+	p := &parser{options: opts}
+
+	return p.parseHCL(d, file, c, variables)
+}
+
+// ParseHCL parses config from a reader and adds it to the config without
+// reading from the filesystem. The filename determines the syntax of the
+// config and is used in error messages, relative paths in the config are
+// resolved from the folder containing filename. Variables contains values
+// for any variable blocks defined in the config.
+func ParseHCL(r io.Reader, filename string, c *Config, variables map[string]string) error {
+	d, err := ioutil.ReadAll(r)
+	if err != nil {
+		return xerrors.Errorf("Unable to read config %s: %w", filename, err)
+	}
+
+	p := &parser{}
+
+	return p.parseHCL(d, filename, c, variables)
+}
+
+// parseHCL parses the source of a single file and adds it to the config
+func (p *parser) parseHCL(d []byte, filename string, c *Config, variables map[string]string) error {
+	f, err := parseHCLSource(d, filename)
+	if err != nil {
+		return err
+	}
+
+	blocks, err := fileBlocks(f, filename)
+	if err != nil {
+		return err
+	}
+
+	return p.decodeBlocks(blocks, c, variables)
+}
+
+// ParseHCLString parses config from a string and adds it to the config,
+// see ParseHCL
+func ParseHCLString(src, filename string, c *Config, variables map[string]string) error {
+	return ParseHCL(strings.NewReader(src), filename, c, variables)
+}
+
+// decodeBlocks resolves the variables and decodes the blocks into the
+// config, it is used for both single files and folders
+func (p *parser) decodeBlocks(blocks []fileBlock, c *Config, variables map[string]string) error {
+	// variables must be resolved before any other blocks can be decoded
+	err := p.setupContext(blocks, variables)
+	if err != nil {
+		return err
+	}
+
+	return p.parseBlocks(blocks, c)
+}
+
+// parseHCL returns the syntax tree for the given file, encrypted files are
+// decrypted before they are parsed
+func parseHCL(file string) (*hcl.File, error) {
+	d, err := readConfigFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseHCLSource(d, file)
+}
+
+// parseHCLSource returns the syntax tree for the source of a file, the
+// syntax is determined by the extension of the filename
+func parseHCLSource(d []byte, file string) (*hcl.File, error) {
+	p := hclparse.NewParser()
+
+	parse := p.ParseHCL
+	switch filepath.Ext(file) {
+	case ".json":
+		parse = p.ParseJSON
+	case ".yaml", ".yml":
+		// YAML files use the same structure as the JSON syntax
+		j, err := yaml.YAMLToJSON(d)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to parse YAML file %s: %s", file, err)
+		}
+
+		d = j
+
+		parse = p.ParseJSON
+	}
+
+	f, diag := parse(d, file)
+	if diag.HasErrors() {
+		return nil, newParseError(diag, f)
+	}
+
+	return f, nil
+}
+
+// readBlocks returns the top level blocks defined in the file
+func readBlocks(file string) ([]fileBlock, error) {
+	f, err := parseHCL(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return fileBlocks(f, file)
+}
+
+// fileBlocks returns the top level blocks defined in a parsed file
+func fileBlocks(f *hcl.File, file string) ([]fileBlock, error) {
+	blocks := []fileBlock{}
+
+	// native syntax files are read directly so that unknown
+	// resource types can be reported
+	if body, ok := f.Body.(*hclsyntax.Body); ok {
+		for _, b := range body.Blocks {
+			blocks = append(blocks, fileBlock{block: b.AsHCLBlock(), file: file})
+		}
+
+		return blocks, nil
+	}
+
+	// other syntaxes such as JSON can only be read using a schema
+	content, diag := f.Body.Content(fileSchema())
+	if diag.HasErrors() {
+		return nil, newParseError(diag)
+	}
+
+	for _, b := range content.Blocks {
+		blocks = append(blocks, fileBlock{block: b, file: file})
+	}
+
+	return blocks, nil
+}
+
+// fileSchema returns the schema for the top level blocks in a config file
+func fileSchema() *hcl.BodySchema {
+	types := append([]ResourceType{}, referenceTypes...)
+	types = append(types, TypeVariable, TypeOutput, TypeModule, TypeCompose, TypeService)
+
+	s := &hcl.BodySchema{}
+	for _, t := range types {
+		s.Blocks = append(s.Blocks, hcl.BlockHeaderSchema{Type: string(t), LabelNames: []string{"name"}})
+	}
+
+	s.Blocks = append(s.Blocks, hcl.BlockHeaderSchema{Type: string(TypeLocals)})
+	s.Blocks = append(s.Blocks, hcl.BlockHeaderSchema{Type: string(TypeData), LabelNames: []string{"type", "name"}})
+
+	return s
+}
+
+// parseBlock decodes a single block and adds it to the config
+func (p *parser) parseBlock(b *hcl.Block, file string, c *Config) error {
+	switch b.Type {
+	case string(TypeK8sCluster):
+		cl := NewK8sCluster(b.Labels[0])
+
+		err := p.decodeBody(b, cl)
+		if err != nil {
+			return err
+		}
+
+		err = p.addResource(c, cl)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeK8sClusterExternal):
+		cl := NewK8sClusterExternal(b.Labels[0])
+
+		err := p.decodeBody(b, cl)
+		if err != nil {
+			return err
+		}
+
+		cl.setDefaults()
+		cl.KubeConfig = ensureAbsolute(cl.KubeConfig, file)
+
+		err = p.addResource(c, cl)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeK8sConfig):
+		h := NewK8sConfig(b.Labels[0])
+
+		err := p.decodeBody(b, h)
+		if err != nil {
+			return err
+		}
+
+		// make all the paths absolute
+		for i, p := range h.Paths {
+			h.Paths[i] = ensureAbsolute(p, file)
+		}
+
+		err = p.addResource(c, h)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeKustomize):
+		k := NewKustomize(b.Labels[0])
+
+		err := p.decodeBody(b, k)
+		if err != nil {
+			return err
+		}
+
+		k.Path = ensureAbsolute(k.Path, file)
+
+		err = p.addResource(c, k)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeK8sNamespace):
+		ns := NewK8sNamespace(b.Labels[0])
+
+		err := p.decodeBody(b, ns)
+		if err != nil {
+			return err
+		}
+
+		err = p.addResource(c, ns)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeLocalIngress):
+		i := NewLocalIngress(b.Labels[0])
+
+		err := p.decodeBody(b, i)
+		if err != nil {
+			return err
+		}
+
+		i.Address = utils.FQDN(i.Name, string(i.Type))
+
+		if i.Cluster != "" && i.Namespace == "" {
+			i.Namespace = "default"
+		}
+
+		err = p.addResource(c, i)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeK8sSecret):
+		sec := NewK8sSecret(b.Labels[0])
+
+		err := p.decodeBody(b, sec)
+		if err != nil {
+			return err
+		}
+
+		if sec.Namespace == "" {
+			sec.Namespace = "default"
+		}
+
+		if sec.SecretType == "" {
+			sec.SecretType = "Opaque"
+		}
+
+		for i, f := range sec.Files {
+			sec.Files[i] = ensureAbsolute(f, file)
+		}
+
+		err = p.addResource(c, sec)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeK8sConfigMap):
+		cm := NewK8sConfigMap(b.Labels[0])
+
+		err := p.decodeBody(b, cm)
+		if err != nil {
+			return err
+		}
+
+		if cm.Namespace == "" {
+			cm.Namespace = "default"
+		}
+
+		for i, f := range cm.Files {
+			cm.Files[i] = ensureAbsolute(f, file)
+		}
+
+		err = p.addResource(c, cm)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeK8sWait):
+		w := NewK8sWait(b.Labels[0])
+
+		err := p.decodeBody(b, w)
+		if err != nil {
+			return err
+		}
+
+		err = p.addResource(c, w)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeHTTPCheck):
+		hc := NewHTTPCheck(b.Labels[0])
+
+		err := p.decodeBody(b, hc)
+		if err != nil {
+			return err
+		}
+
+		if (hc.HTTP == "") == (hc.TCP == "") {
+			return fmt.Errorf("Unable to decode http_check %s, one of http or tcp must be set", hc.Name)
+		}
+
+		err = p.addResource(c, hc)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeSQLExec):
+		se := NewSQLExec(b.Labels[0])
+
+		err := p.decodeBody(b, se)
+		if err != nil {
+			return err
+		}
+
+		if se.Driver != "postgres" && se.Driver != "mysql" {
+			return fmt.Errorf("Unable to decode sql_exec %s, driver must be postgres or mysql", se.Name)
+		}
+
+		if len(se.Files) == 0 && se.SQL == "" {
+			return fmt.Errorf("Unable to decode sql_exec %s, one of files or sql must be set", se.Name)
+		}
+
+		for i, f := range se.Files {
+			se.Files[i] = ensureAbsolute(f, file)
+		}
+
+		err = p.addResource(c, se)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeConsulConfig):
+		cc := NewConsulConfig(b.Labels[0])
+
+		err := p.decodeBody(b, cc)
+		if err != nil {
+			return err
+		}
+
+		for i, e := range cc.Entries {
+			cc.Entries[i] = ensureAbsolute(e, file)
+		}
+
+		err = p.addResource(c, cc)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeHelmRepository):
+		hr := NewHelmRepository(b.Labels[0])
+
+		err := p.decodeBody(b, hr)
+		if err != nil {
+			return err
+		}
+
+		err = p.addResource(c, hr)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeHelm):
+		h := NewHelm(b.Labels[0])
+
+		err := p.decodeBody(b, h)
+		if err != nil {
+			return err
+		}
+
+		if h.Version != "" && h.Repository == "" {
+			return fmt.Errorf("Unable to parse helm %s, version can only be set for charts installed from a repository", h.Name)
+		}
+
+		// only set absolute if is local folder
+		if h.Repository == "" && h.Chart != "" && utils.IsLocalFolder(ensureAbsolute(h.Chart, file)) {
+			h.Chart = ensureAbsolute(h.Chart, file)
+		}
+
+		if h.Values != "" && utils.IsLocalFolder(ensureAbsolute(h.Values, file)) {
+			h.Values = ensureAbsolute(h.Values, file)
+		}
+
+		err = p.addResource(c, h)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeK8sIngress):
+		i := NewK8sIngress(b.Labels[0])
+
+		err := p.decodeBody(b, i)
+		if err != nil {
+			return err
+		}
+
+		err = p.addResource(c, i)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeNomadCluster):
+		cl := NewNomadCluster(b.Labels[0])
+
+		err := p.decodeBody(b, cl)
+		if err != nil {
+			return err
+		}
+
+		// Process volumes
+		// make sure mount paths are absolute
+		ensureAbsoluteVolumes(cl.Volumes, file)
+
+		err = p.addResource(c, cl)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeNomadJob):
+		h := NewNomadJob(b.Labels[0])
+
+		err := p.decodeBody(b, h)
+		if err != nil {
+			return err
+		}
+
+		// make all the paths absolute
+		for i, p := range h.Paths {
+			h.Paths[i] = ensureAbsolute(p, file)
+		}
+
+		err = p.addResource(c, h)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeNomadIngress):
+		i := NewNomadIngress(b.Labels[0])
+
+		err := p.decodeBody(b, i)
+		if err != nil {
+			return err
+		}
+
+		err = p.addResource(c, i)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeNetwork):
+		n := NewNetwork(b.Labels[0])
+
+		err := p.decodeBody(b, n)
+		if err != nil {
+			return err
+		}
+
+		err = p.addResource(c, n)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeIngress):
+		i := NewIngress(b.Labels[0])
+
+		err := p.decodeBody(b, i)
+		if err != nil {
+			return err
+		}
+
+		ports, err := expandPortRanges(i.PortRanges)
+		if err != nil {
+			return fmt.Errorf("Unable to decode ingress %s: %s", i.Name, err)
+		}
+		i.Ports = append(i.Ports, ports...)
+
+		err = p.addResource(c, i)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeContainer):
+		co := NewContainer(b.Labels[0])
+
+		// sidecar blocks are decoded as separate resources
+		sidecars, body, diag := b.Body.PartialContent(containerSidecarSchema)
+		if diag.HasErrors() {
+			return newParseError(diag)
+		}
+
+		err := p.decodeBody(&hcl.Block{Type: b.Type, Labels: b.Labels, Body: body, DefRange: b.DefRange}, co)
+		if err != nil {
+			return err
+		}
+
+		// process volumes
+		// make sure mount paths are absolute
+		ensureAbsoluteVolumes(co.Volumes, file)
+
+		ports, err := expandPortRanges(co.PortRanges)
+		if err != nil {
+			return fmt.Errorf("Unable to decode container %s: %s", co.Name, err)
+		}
+		co.Ports = append(co.Ports, ports...)
+
+		err = p.addResource(c, co)
+		if err != nil {
+			return err
+		}
+
+		for _, sb := range sidecars.Blocks {
+			err := p.parseContainerSidecar(sb, co, file, c)
+			if err != nil {
+				return err
+			}
+		}
+
+	case string(TypeDockerVolume):
+		v := NewDockerVolume(b.Labels[0])
+
+		err := p.decodeBody(b, v)
+		if err != nil {
+			return err
+		}
+
+		err = p.addResource(c, v)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeContainerBuild):
+		cb := NewContainerBuild(b.Labels[0])
+
+		err := p.decodeBody(b, cb)
+		if err != nil {
+			return err
+		}
+
+		cb.Context = ensureAbsolute(cb.Context, file)
+
+		err = p.addResource(c, cb)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeCertificateCA):
+		ca := NewCertificateCA(b.Labels[0])
+
+		err := p.decodeBody(b, ca)
+		if err != nil {
+			return err
+		}
+
+		ca.Output = p.certificateOutput(ca.Output, file)
+		ca.Cert = filepath.Join(ca.Output, ca.Name+".cert")
+		ca.Key = filepath.Join(ca.Output, ca.Name+".key")
+
+		err = p.addResource(c, ca)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeCertificateLeaf):
+		cl := NewCertificateLeaf(b.Labels[0])
+
+		err := p.decodeBody(b, cl)
+		if err != nil {
+			return err
+		}
+
+		cl.CACert = ensureAbsolute(cl.CACert, file)
+		cl.CAKey = ensureAbsolute(cl.CAKey, file)
+		cl.Output = p.certificateOutput(cl.Output, file)
+		cl.Cert = filepath.Join(cl.Output, cl.Name+".cert")
+		cl.Key = filepath.Join(cl.Output, cl.Name+".key")
+
+		err = p.addResource(c, cl)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeTemplate):
+		t := NewTemplate(b.Labels[0])
+
+		err := p.decodeBody(b, t)
+		if err != nil {
+			return err
+		}
+
+		if !t.IsInline() {
+			t.Source = ensureAbsolute(t.Source, file)
+		}
+
+		t.Destination = ensureAbsolute(t.Destination, file)
+
+		err = p.addResource(c, t)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeCopy):
+		cp := NewCopy(b.Labels[0])
+
+		err := p.decodeBody(b, cp)
+		if err != nil {
+			return err
+		}
+
+		cp.Source = ensureAbsolute(cp.Source, file)
+
+		err = p.addResource(c, cp)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeRandomPassword):
+		rp := NewRandomPassword(b.Labels[0])
+
+		err := p.decodeBody(b, rp)
+		if err != nil {
+			return err
+		}
+
+		err = rp.generate(p.module)
+		if err != nil {
+			return err
+		}
+
+		// the value is generated after decoding so it is not marked by
+		// decodeBody, values which interpolate it are also redacted
+		MarkSensitive(rp.Value)
+
+		err = p.addResource(c, rp)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeRandomID):
+		r := NewRandomID(b.Labels[0])
+
+		err := p.decodeBody(b, r)
+		if err != nil {
+			return err
+		}
+
+		err = r.generate(p.module)
+		if err != nil {
+			return err
+		}
+
+		err = p.addResource(c, r)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeNullResource):
+		n := NewNullResource(b.Labels[0])
+
+		err := p.decodeBody(b, n)
+		if err != nil {
+			return err
+		}
+
+		n.checkTriggers(p.module)
+
+		err = p.addResource(c, n)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeContainerRegistry):
+		cr := NewContainerRegistry(b.Labels[0])
+
+		err := p.decodeBody(b, cr)
+		if err != nil {
+			return err
+		}
+
+		cr.setAddress()
+
+		err = p.addResource(c, cr)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeVault):
+		v := NewVault(b.Labels[0])
+
+		err := p.decodeBody(b, v)
+		if err != nil {
+			return err
+		}
+
+		for i, p := range v.Policies {
+			v.Policies[i] = ensureAbsolute(p, file)
+		}
+
+		for i, s := range v.Secrets {
+			v.Secrets[i] = ensureAbsolute(s, file)
+		}
+
+		v.setAddress()
+
+		err = p.addResource(c, v)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeLoadBalancer):
+		lb := NewLoadBalancer(b.Labels[0])
+
+		err := p.decodeBody(b, lb)
+		if err != nil {
+			return err
+		}
+
+		err = p.addResource(c, lb)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeDNS):
+		d := NewDNS(b.Labels[0])
+
+		err := p.decodeBody(b, d)
+		if err != nil {
+			return err
+		}
+
+		d.setAddress()
+
+		err = p.addResource(c, d)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeAWS):
+		a := NewAWS(b.Labels[0])
+
+		err := p.decodeBody(b, a)
+		if err != nil {
+			return err
+		}
+
+		if a.Bootstrap != "" {
+			a.Bootstrap = ensureAbsolute(a.Bootstrap, file)
+		}
+
+		a.setAddress()
+
+		err = p.addResource(c, a)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeMinIO):
+		m := NewMinIO(b.Labels[0])
+
+		err := p.decodeBody(b, m)
+		if err != nil {
+			return err
+		}
+
+		for i, u := range m.Users {
+			if u.Policy == "" {
+				m.Users[i].Policy = "readwrite"
+			}
+		}
+
+		m.setAddress()
+
+		err = p.addResource(c, m)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeNetworkRoute):
+		nr := NewNetworkRoute(b.Labels[0])
+
+		err := p.decodeBody(b, nr)
+		if err != nil {
+			return err
+		}
+
+		err = p.addResource(c, nr)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeGitOps):
+		g := NewGitOps(b.Labels[0])
+
+		err := p.decodeBody(b, g)
+		if err != nil {
+			return err
+		}
+
+		g.setDefaults()
+
+		err = p.addResource(c, g)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeTrafficCapture):
+		tc := NewTrafficCapture(b.Labels[0])
+
+		err := p.decodeBody(b, tc)
+		if err != nil {
+			return err
+		}
+
+		if tc.Output == "" {
+			folder := p.folder
+			if folder == "" {
+				folder = filepath.Dir(file)
+			}
+
+			tc.Output = filepath.Join(blueprintDataDir(folder), "captures")
+		} else {
+			tc.Output = ensureAbsolute(tc.Output, file)
+		}
+
+		tc.setAddress()
+
+		err = p.addResource(c, tc)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeChaos):
+		ch := NewChaos(b.Labels[0])
+
+		err := p.decodeBody(b, ch)
+		if err != nil {
+			return err
+		}
+
+		err = p.addResource(c, ch)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeMockAPI):
+		m := NewMockAPI(b.Labels[0])
+
+		err := p.decodeBody(b, m)
+		if err != nil {
+			return err
+		}
+
+		if m.Spec != "" {
+			m.Spec = ensureAbsolute(m.Spec, file)
+		}
+
+		m.setDefaults()
+
+		err = p.addResource(c, m)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeLocalService):
+		ls := NewLocalService(b.Labels[0])
+
+		err := p.decodeBody(b, ls)
+		if err != nil {
+			return err
+		}
+
+		if ls.WorkingDirectory != "" {
+			ls.WorkingDirectory = ensureAbsolute(ls.WorkingDirectory, file)
+		}
+
+		ls.setDefaults(p.module)
+
+		err = p.addResource(c, ls)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeImageCache):
+		ic := NewImageCache(b.Labels[0])
+
+		err := p.decodeBody(b, ic)
+		if err != nil {
+			return err
+		}
+
+		err = p.addResource(c, ic)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeContainerIngress):
+		i := NewContainerIngress(b.Labels[0])
+
+		err := p.decodeBody(b, i)
+		if err != nil {
+			return err
+		}
+
+		err = p.addResource(c, i)
+		if err != nil {
+			return err
+		}
+
+	case string(TypeSidecar):
+		s := NewSidecar(b.Labels[0])
+
+		err := p.decodeBody(b, s)
+		if err != nil {
+			return err
+		}
+
+		if s.Target == "" {
+			return fmt.Errorf("Unable to decode sidecar %s defined in file %s, the target attribute is required", s.Name, file)
+		}
 
-	bp.Intro = body
+		ensureAbsoluteVolumes(s.Volumes, file)
 
-	c.Blueprint = bp
-	return nil
-}
+		err = p.addResource(c, s)
+		if err != nil {
+			return err
+		}
 
-// ParseHCLFile parses a config file and adds it to the config
-func ParseHCLFile(file string, c *Config) error {
-	ctx = buildContext()
-	parser := hclparse.NewParser()
+	case string(TypeDocs):
+		do := NewDocs(b.Labels[0])
 
-	f, diag := parser.ParseHCLFile(file)
-	if diag.HasErrors() {
-		return errors.New(diag.Error())
-	}
+		err := p.decodeBody(b, do)
+		if err != nil {
+			return err
+		}
 
-	body, ok := f.Body.(*hclsyntax.Body)
-	if !ok {
-		return errors.New("Error getting body")
-	}
+		do.Path = ensureAbsolute(do.Path, file)
 
-	for _, b := range body.Blocks {
-		switch b.Type {
-		case string(TypeK8sCluster):
-			cl := NewK8sCluster(b.Labels[0])
+		err = p.addResource(c, do)
+		if err != nil {
+			return err
+		}
 
-			err := decodeBody(b, cl)
-			if err != nil {
-				return err
-			}
+	case string(TypeExecLocal):
+		h := NewExecLocal(b.Labels[0])
 
-			c.AddResource(cl)
+		err := p.decodeBody(b, h)
+		if err != nil {
+			return err
+		}
 
-		case string(TypeK8sConfig):
-			h := NewK8sConfig(b.Labels[0])
+		// inline commands are run as scripts
+		if isInlineScript(h.Command) && h.Script == "" {
+			h.Script = h.Command
+			h.Command = ""
+		}
 
-			err := decodeBody(b, h)
+		if isInlineScript(h.Script) {
+			s, err := writeInlineScript(h.Name, h.Script)
 			if err != nil {
-				return err
-			}
-
-			// make all the paths absolute
-			for i, p := range h.Paths {
-				h.Paths[i] = ensureAbsolute(p, file)
+				return fmt.Errorf("Unable to write inline script for exec_local %s: %s", h.Name, err)
 			}
 
-			c.AddResource(h)
-
-		case string(TypeHelm):
-			h := NewHelm(b.Labels[0])
+			h.Script = s
+		} else {
+			h.Script = ensureAbsolute(h.Script, file)
+		}
 
-			err := decodeBody(b, h)
-			if err != nil {
-				return err
-			}
+		err = p.addResource(c, h)
+		if err != nil {
+			return err
+		}
 
-			// only set absolute if is local folder
-			if h.Chart != "" && utils.IsLocalFolder(ensureAbsolute(h.Chart, file)) {
-				h.Chart = ensureAbsolute(h.Chart, file)
-			}
+	case string(TypeExecRemote):
+		h := NewExecRemote(b.Labels[0])
 
-			if h.Values != "" && utils.IsLocalFolder(ensureAbsolute(h.Values, file)) {
-				h.Values = ensureAbsolute(h.Values, file)
-			}
+		err := p.decodeBody(b, h)
+		if err != nil {
+			return err
+		}
 
-			c.AddResource(h)
+		if h.Script != "" && h.Command != "" {
+			return fmt.Errorf("Unable to decode exec_remote %s, only one of script or cmd can be set", h.Name)
+		}
 
-		case string(TypeK8sIngress):
-			i := NewK8sIngress(b.Labels[0])
+		// script is run using the shell in the container, a path to a
+		// script file would be executed as a command
+		if h.Script != "" && !isInlineScript(h.Script) {
+			return fmt.Errorf("Unable to decode exec_remote %s, script must contain the script defined using a heredoc, use cmd to run a script file in the container", h.Name)
+		}
 
-			err := decodeBody(b, i)
-			if err != nil {
-				return err
-			}
+		// process volumes
+		// make sure mount paths are absolute
+		ensureAbsoluteVolumes(h.Volumes, file)
 
-			c.AddResource(i)
+		err = p.addResource(c, h)
+		if err != nil {
+			return err
+		}
 
-		case string(TypeNomadCluster):
-			cl := NewNomadCluster(b.Labels[0])
+	case string(TypeVariable), string(TypeLocals), string(TypeData):
+		// variables, locals and data sources have already been added to the context
 
-			err := decodeBody(b, cl)
-			if err != nil {
-				return err
-			}
+	case string(TypeOutput):
+		o, err := p.decodeOutput(b)
+		if err != nil {
+			return err
+		}
 
-			// Process volumes
-			// make sure mount paths are absolute
-			for i, v := range cl.Volumes {
-				cl.Volumes[i].Source = ensureAbsolute(v.Source, file)
-			}
+		err = c.AddOutput(o)
+		if err != nil {
+			return err
+		}
 
-			c.AddResource(cl)
+	case string(TypeModule):
+		m := NewModule(b.Labels[0])
 
-		case string(TypeNomadJob):
-			h := NewNomadJob(b.Labels[0])
+		err := p.decodeBody(b, m)
+		if err != nil {
+			return err
+		}
 
-			err := decodeBody(b, h)
+		// import the source files for this module
+		if !utils.IsLocalFolder(ensureAbsolute(m.Source, file)) {
+			// get the details
+			dst := utils.GetBlueprintLocalFolder(m.Source)
+			err := getFiles(m.Source, dst)
 			if err != nil {
 				return err
 			}
 
-			// make all the paths absolute
-			for i, p := range h.Paths {
-				h.Paths[i] = ensureAbsolute(p, file)
-			}
-
-			c.AddResource(h)
-
-		case string(TypeNomadIngress):
-			i := NewNomadIngress(b.Labels[0])
+			// set the source to the local folder
+			m.Source = dst
+		}
 
-			err := decodeBody(b, i)
-			if err != nil {
-				return err
-			}
+		// set the absolute path
+		m.Source = ensureAbsolute(m.Source, file)
 
-			c.AddResource(i)
+		// recursively parse references for the module, modules have their
+		// own variables so they are parsed with their own parser
+		module := m.Name
+		if p.module != "" {
+			module = fmt.Sprintf("%s.%s", p.module, m.Name)
+		}
 
-		case string(TypeNetwork):
-			n := NewNetwork(b.Labels[0])
+		n := len(c.Resources)
 
-			err := decodeBody(b, n)
-			if err != nil {
-				return err
-			}
+		err = p.child(module).parseFolder(m.Source, c, m.Variables)
+		if err != nil {
+			return xerrors.Errorf("Unable to parse module %s: %w", m.Name, err)
+		}
 
-			c.AddResource(n)
+		// add any dependencies for the module to its resources
+		for _, r := range c.Resources[n:] {
+			r.Info().DependsOn = append(r.Info().DependsOn, m.Depends...)
+		}
 
-		case string(TypeIngress):
-			i := NewIngress(b.Labels[0])
+	case string(TypeCompose):
+		cm := NewCompose(b.Labels[0])
 
-			err := decodeBody(b, i)
-			if err != nil {
-				return err
-			}
+		err := p.decodeBody(b, cm)
+		if err != nil {
+			return err
+		}
 
-			c.AddResource(i)
+		cm.File = ensureAbsolute(cm.File, file)
 
-		case string(TypeContainer):
-			co := NewContainer(b.Labels[0])
+		// the services and networks in the compose file are
+		// added as resources, the compose block is not a resource
+		resources, err := composeResources(cm)
+		if err != nil {
+			return err
+		}
 
-			err := decodeBody(b, co)
+		for _, r := range resources {
+			err := p.addResource(c, r)
 			if err != nil {
 				return err
 			}
+		}
 
-			// process volumes
-			// make sure mount paths are absolute
-			for i, v := range co.Volumes {
-				co.Volumes[i].Source = ensureAbsolute(v.Source, file)
-			}
+	case string(TypeService):
+		sv := NewService(b.Labels[0])
 
-			c.AddResource(co)
+		err := p.decodeBody(b, sv)
+		if err != nil {
+			return err
+		}
 
-		case string(TypeContainerIngress):
-			i := NewContainerIngress(b.Labels[0])
+		// the service block is not a resource, the container and http_check
+		// it expands to are added instead
+		resources, err := serviceResources(sv)
+		if err != nil {
+			return err
+		}
 
-			err := decodeBody(b, i)
+		for _, r := range resources {
+			err := p.addResource(c, r)
 			if err != nil {
 				return err
 			}
+		}
+
+	default:
+		return ResourceTypeNotExistError{string(b.Type), file}
+	}
 
-			c.AddResource(i)
+	return nil
+}
 
-		case string(TypeSidecar):
-			s := NewSidecar(b.Labels[0])
+// containerSidecarSchema is the schema for sidecar blocks nested
+// in a container block
+var containerSidecarSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: string(TypeSidecar), LabelNames: []string{"name"}},
+	},
+}
 
-			err := decodeBody(b, s)
-			if err != nil {
-				return err
-			}
+// parseContainerSidecar decodes a sidecar block nested in a container, the
+// sidecar targets the container so that it shares the network namespace of
+// the container and depends on it
+func (p *parser) parseContainerSidecar(b *hcl.Block, co *Container, file string, c *Config) error {
+	s := NewSidecar(b.Labels[0])
 
-			for i, v := range s.Volumes {
-				s.Volumes[i].Source = ensureAbsolute(v.Source, file)
-			}
+	err := p.decodeBody(b, s)
+	if err != nil {
+		return err
+	}
 
-			c.AddResource(s)
+	if s.Target != "" {
+		return fmt.Errorf("Unable to decode sidecar %s defined in container %s, sidecars defined in a container can not set the target attribute", s.Name, co.Name)
+	}
 
-		case string(TypeDocs):
-			do := NewDocs(b.Labels[0])
+	s.Target = fmt.Sprintf("%s.%s", TypeContainer, co.Name)
 
-			err := decodeBody(b, do)
-			if err != nil {
-				return err
-			}
+	ensureAbsoluteVolumes(s.Volumes, file)
 
-			do.Path = ensureAbsolute(do.Path, file)
+	return p.addResource(c, s)
+}
 
-			c.AddResource(do)
+// addResource adds a decoded resource to the config, resources defined in a
+// module are namespaced with the module before they are added so they do not
+// conflict with resources of the same name outside of the module
+func (p *parser) addResource(c *Config, r Resource) error {
+	r.Info().Module = p.module
 
-		case string(TypeExecLocal):
-			h := NewExecLocal(b.Labels[0])
+	return c.AddResource(r)
+}
 
-			err := decodeBody(b, h)
-			if err != nil {
-				return err
+// ParseReferences links the object references in config elements
+func ParseReferences(c *Config) error {
+	for _, r := range c.Resources {
+		switch r.Info().Type {
+		case TypeContainer:
+			c := r.(*Container)
+			for _, n := range c.Networks {
+				c.DependsOn = append(c.DependsOn, n.Name)
 			}
+			c.DependsOn = append(c.DependsOn, c.Depends...)
+			c.DependsOn = append(c.DependsOn, volumeReferences(c.Volumes)...)
 
-			h.Script = ensureAbsolute(h.Script, file)
+		case TypeDockerVolume:
+			c := r.(*DockerVolume)
+			c.DependsOn = append(c.DependsOn, c.Depends...)
+
+		case TypeContainerBuild:
+			c := r.(*ContainerBuild)
+			c.DependsOn = append(c.DependsOn, c.Depends...)
 
-			c.AddResource(h)
+		case TypeCertificateCA:
+			c := r.(*CertificateCA)
+			c.DependsOn = append(c.DependsOn, c.Depends...)
 
-		case string(TypeExecRemote):
-			h := NewExecRemote(b.Labels[0])
+		case TypeCertificateLeaf:
+			c := r.(*CertificateLeaf)
+			c.DependsOn = append(c.DependsOn, c.Depends...)
 
-			err := decodeBody(b, h)
-			if err != nil {
-				return err
-			}
+		case TypeTemplate:
+			c := r.(*Template)
+			c.DependsOn = append(c.DependsOn, c.Depends...)
 
-			/*
-				if h.Script != "" {
-					h.Script = ensureAbsolute(h.Script, file)
-				}
-			*/
+		case TypeContainerRegistry:
+			c := r.(*ContainerRegistry)
+			for _, n := range c.Networks {
+				c.DependsOn = append(c.DependsOn, n.Name)
+			}
+			c.DependsOn = append(c.DependsOn, c.Depends...)
 
-			// process volumes
-			// make sure mount paths are absolute
-			for i, v := range h.Volumes {
-				h.Volumes[i].Source = ensureAbsolute(v.Source, file)
+		case TypeLoadBalancer:
+			c := r.(*LoadBalancer)
+			for _, n := range c.Networks {
+				c.DependsOn = append(c.DependsOn, n.Name)
 			}
+			c.DependsOn = append(c.DependsOn, c.Targets...)
+			c.DependsOn = append(c.DependsOn, c.Depends...)
 
-			c.AddResource(h)
+		case TypeDNS:
+			c := r.(*DNS)
+			for _, n := range c.Networks {
+				c.DependsOn = append(c.DependsOn, n.Name)
+			}
+			c.DependsOn = append(c.DependsOn, c.Depends...)
 
-		case string(TypeModule):
-			m := NewModule(b.Labels[0])
+		case TypeAWS:
+			c := r.(*AWS)
+			for _, n := range c.Networks {
+				c.DependsOn = append(c.DependsOn, n.Name)
+			}
+			c.DependsOn = append(c.DependsOn, c.Depends...)
 
-			err := decodeBody(b, m)
-			if err != nil {
-				return err
+		case TypeMinIO:
+			c := r.(*MinIO)
+			for _, n := range c.Networks {
+				c.DependsOn = append(c.DependsOn, n.Name)
 			}
+			c.DependsOn = append(c.DependsOn, c.Depends...)
 
-			// import the source files for this module
-			if !utils.IsLocalFolder(ensureAbsolute(m.Source, file)) {
-				// get the details
-				dst := utils.GetBlueprintLocalFolder(m.Source)
-				err := getFiles(m.Source, dst)
-				if err != nil {
-					return err
-				}
+		case TypeNetworkRoute:
+			c := r.(*NetworkRoute)
+			c.DependsOn = append(c.DependsOn, c.Networks...)
+			c.DependsOn = append(c.DependsOn, c.Depends...)
 
-				// set the source to the local folder
-				m.Source = dst
+		case TypeTrafficCapture:
+			c := r.(*TrafficCapture)
+			for _, n := range c.Networks {
+				c.DependsOn = append(c.DependsOn, n.Name)
 			}
+			c.DependsOn = append(c.DependsOn, c.Depends...)
 
-			// set the absolute path
-			m.Source = ensureAbsolute(m.Source, file)
+		case TypeChaos:
+			ch := r.(*Chaos)
+			ch.DependsOn = append(ch.DependsOn, ch.Targets...)
+			ch.DependsOn = append(ch.DependsOn, c.containersOnNetworks(ch.Targets)...)
+			ch.DependsOn = append(ch.DependsOn, ch.Depends...)
 
-			// recursively parse references for the module
-			err = ParseFolder(m.Source, c)
-			if err != nil {
-				return err
+		case TypeMockAPI:
+			c := r.(*MockAPI)
+			for _, n := range c.Networks {
+				c.DependsOn = append(c.DependsOn, n.Name)
 			}
+			c.DependsOn = append(c.DependsOn, c.Depends...)
 
-		default:
-			return ResourceTypeNotExistError{string(b.Type), file}
-		}
-	}
+		case TypeLocalService:
+			c := r.(*LocalService)
+			c.DependsOn = append(c.DependsOn, c.Depends...)
 
-	return nil
-}
+		case TypeVault:
+			c := r.(*Vault)
+			for _, n := range c.Networks {
+				c.DependsOn = append(c.DependsOn, n.Name)
+			}
+			c.DependsOn = append(c.DependsOn, c.Depends...)
 
-// ParseReferences links the object references in config elements
-func ParseReferences(c *Config) error {
-	for _, r := range c.Resources {
-		switch r.Info().Type {
-		case TypeContainer:
-			c := r.(*Container)
+		case TypeImageCache:
+			c := r.(*ImageCache)
 			for _, n := range c.Networks {
 				c.DependsOn = append(c.DependsOn, n.Name)
 			}
@@ -444,6 +1746,33 @@ func ParseReferences(c *Config) error {
 			c := r.(*Sidecar)
 			c.DependsOn = append(c.DependsOn, c.Target)
 			c.DependsOn = append(c.DependsOn, c.Depends...)
+			c.DependsOn = append(c.DependsOn, volumeReferences(c.Volumes)...)
+
+		case TypeCopy:
+			c := r.(*Copy)
+			c.DependsOn = append(c.DependsOn, c.Target)
+			c.DependsOn = append(c.DependsOn, c.Depends...)
+
+		case TypeConsulConfig:
+			c := r.(*ConsulConfig)
+			c.DependsOn = append(c.DependsOn, c.Depends...)
+
+		case TypeSQLExec:
+			c := r.(*SQLExec)
+			c.DependsOn = append(c.DependsOn, c.Target)
+			c.DependsOn = append(c.DependsOn, c.Depends...)
+
+		case TypeNullResource:
+			c := r.(*NullResource)
+			c.DependsOn = append(c.DependsOn, c.Depends...)
+
+		case TypeRandomPassword:
+			c := r.(*RandomPassword)
+			c.DependsOn = append(c.DependsOn, c.Depends...)
+
+		case TypeRandomID:
+			c := r.(*RandomID)
+			c.DependsOn = append(c.DependsOn, c.Depends...)
 
 		case TypeDocs:
 			c := r.(*Docs)
@@ -459,6 +1788,7 @@ func ParseReferences(c *Config) error {
 			}
 
 			c.DependsOn = append(c.DependsOn, c.Depends...)
+			c.DependsOn = append(c.DependsOn, volumeReferences(c.Volumes)...)
 
 			// target is optional
 			if c.Target != "" {
@@ -474,14 +1804,93 @@ func ParseReferences(c *Config) error {
 			c.DependsOn = append(c.DependsOn, c.Depends...)
 
 		case TypeK8sCluster:
-			c := r.(*K8sCluster)
-			for _, n := range c.Networks {
-				c.DependsOn = append(c.DependsOn, n.Name)
+			cl := r.(*K8sCluster)
+			for _, n := range cl.Networks {
+				cl.DependsOn = append(cl.DependsOn, n.Name)
 			}
-			c.DependsOn = append(c.DependsOn, c.Depends...)
+			cl.DependsOn = append(cl.DependsOn, cl.Depends...)
+
+			// images are pulled through the cache so it must be created first
+			if ic := c.FindImageCache(cl.Networks); ic != nil {
+				cl.DependsOn = append(cl.DependsOn, ResourceID(ic))
+			}
+
+		case TypeK8sClusterExternal:
+			cl := r.(*K8sClusterExternal)
+			cl.DependsOn = append(cl.DependsOn, cl.Depends...)
 
 		case TypeHelm:
-			c := r.(*Helm)
+			h := r.(*Helm)
+			h.DependsOn = append(h.DependsOn, h.Cluster)
+			h.DependsOn = append(h.DependsOn, h.Depends...)
+
+			// the namespace must exist before the chart is installed
+			if ns := c.FindK8sNamespace(h.Cluster, h.Namespace); ns != nil {
+				h.DependsOn = append(h.DependsOn, ResourceID(ns))
+			}
+
+			if strings.HasPrefix(h.Repository, string(TypeHelmRepository)+".") {
+				h.DependsOn = append(h.DependsOn, h.Repository)
+			}
+
+		case TypeHelmRepository:
+			c := r.(*HelmRepository)
+			c.DependsOn = append(c.DependsOn, c.Depends...)
+
+		case TypeKustomize:
+			k := r.(*Kustomize)
+			k.DependsOn = append(k.DependsOn, k.Cluster)
+			k.DependsOn = append(k.DependsOn, k.Depends...)
+
+		case TypeK8sNamespace:
+			c := r.(*K8sNamespace)
+			c.DependsOn = append(c.DependsOn, c.Cluster)
+			c.DependsOn = append(c.DependsOn, c.Depends...)
+
+		case TypeLocalIngress:
+			i := r.(*LocalIngress)
+			for _, n := range i.Networks {
+				i.DependsOn = append(i.DependsOn, n.Name)
+			}
+			i.DependsOn = append(i.DependsOn, i.Depends...)
+
+			if i.Cluster != "" {
+				i.DependsOn = append(i.DependsOn, i.Cluster)
+
+				if ns := c.FindK8sNamespace(i.Cluster, i.Namespace); ns != nil {
+					i.DependsOn = append(i.DependsOn, ResourceID(ns))
+				}
+			}
+
+		case TypeK8sSecret:
+			sec := r.(*K8sSecret)
+			sec.DependsOn = append(sec.DependsOn, sec.Cluster)
+			sec.DependsOn = append(sec.DependsOn, sec.Depends...)
+
+			if ns := c.FindK8sNamespace(sec.Cluster, sec.Namespace); ns != nil {
+				sec.DependsOn = append(sec.DependsOn, ResourceID(ns))
+			}
+
+		case TypeK8sConfigMap:
+			cm := r.(*K8sConfigMap)
+			cm.DependsOn = append(cm.DependsOn, cm.Cluster)
+			cm.DependsOn = append(cm.DependsOn, cm.Depends...)
+
+			if ns := c.FindK8sNamespace(cm.Cluster, cm.Namespace); ns != nil {
+				cm.DependsOn = append(cm.DependsOn, ResourceID(ns))
+			}
+
+		case TypeHTTPCheck:
+			c := r.(*HTTPCheck)
+			c.DependsOn = append(c.DependsOn, c.Depends...)
+
+		case TypeGitOps:
+			c := r.(*GitOps)
+			c.DependsOn = append(c.DependsOn, c.Cluster)
+			c.DependsOn = append(c.DependsOn, c.Depends...)
+
+		case TypeK8sWait:
+			c := r.(*K8sWait)
 			c.DependsOn = append(c.DependsOn, c.Cluster)
 			c.DependsOn = append(c.DependsOn, c.Depends...)
 
@@ -499,11 +1908,17 @@ func ParseReferences(c *Config) error {
 			c.DependsOn = append(c.DependsOn, c.Depends...)
 
 		case TypeNomadCluster:
-			c := r.(*NomadCluster)
-			for _, n := range c.Networks {
-				c.DependsOn = append(c.DependsOn, n.Name)
+			cl := r.(*NomadCluster)
+			for _, n := range cl.Networks {
+				cl.DependsOn = append(cl.DependsOn, n.Name)
+			}
+			cl.DependsOn = append(cl.DependsOn, cl.Depends...)
+			cl.DependsOn = append(cl.DependsOn, volumeReferences(cl.Volumes)...)
+
+			// images are pulled through the cache so it must be created first
+			if ic := c.FindImageCache(cl.Networks); ic != nil {
+				cl.DependsOn = append(cl.DependsOn, ResourceID(ic))
 			}
-			c.DependsOn = append(c.DependsOn, c.Depends...)
 
 		case TypeNomadIngress:
 			c := r.(*NomadIngress)
@@ -523,7 +1938,7 @@ func ParseReferences(c *Config) error {
 	return nil
 }
 
-func buildContext() *hcl.EvalContext {
+func buildContext(folder string) *hcl.EvalContext {
 	var EnvFunc = function.New(&function.Spec{
 		Params: []function.Parameter{
 			{
@@ -567,6 +1982,37 @@ func buildContext() *hcl.EvalContext {
 		},
 	})
 
+	var DockerIPFunc = function.New(&function.Spec{
+		Type: function.StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			return cty.StringVal(utils.GetDockerIP()), nil
+		},
+	})
+
+	var DockerHostFunc = function.New(&function.Spec{
+		Type: function.StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			return cty.StringVal(utils.GetDockerHost()), nil
+		},
+	})
+
+	// SensitiveFunc marks a value as sensitive so that it is redacted from
+	// logs and state, the value is returned unchanged
+	var SensitiveFunc = function.New(&function.Spec{
+		Params: []function.Parameter{
+			{
+				Name:             "value",
+				Type:             cty.String,
+				AllowDynamicType: true,
+			},
+		},
+		Type: function.StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			MarkSensitive(args[0].AsString())
+			return args[0], nil
+		},
+	})
+
 	ctx := &hcl.EvalContext{
 		Functions: map[string]function.Function{},
 	}
@@ -574,16 +2020,52 @@ func buildContext() *hcl.EvalContext {
 	ctx.Functions["k8s_config"] = KubeConfigFunc
 	ctx.Functions["home"] = HomeFunc
 	ctx.Functions["shipyard"] = ShipyardFunc
+	ctx.Functions["sensitive"] = SensitiveFunc
+	ctx.Functions["docker_ip"] = DockerIPFunc
+	ctx.Functions["docker_host"] = DockerHostFunc
+	ctx.Functions["file"] = newFileFunc("")
+	ctx.Functions["templatefile"] = newTemplateFileFunc(ctx, "")
+	ctx.Functions["template_file"] = ctx.Functions["templatefile"]
+	ctx.Functions["data_dir"] = newDataDirFunc(folder)
+	ctx.Functions["data"] = newDataFunc(folder)
+
+	// string functions
+	ctx.Functions["format"] = stdlib.FormatFunc
+	ctx.Functions["upper"] = stdlib.UpperFunc
+	ctx.Functions["lower"] = stdlib.LowerFunc
+	ctx.Functions["join"] = JoinFunc
+	ctx.Functions["split"] = SplitFunc
+	ctx.Functions["trimspace"] = TrimSpaceFunc
+	ctx.Functions["replace"] = ReplaceFunc
+
+	// collection and numeric functions
+	ctx.Functions["length"] = LengthFunc
+	ctx.Functions["concat"] = stdlib.ConcatFunc
+	ctx.Functions["merge"] = MergeFunc
+	ctx.Functions["range"] = stdlib.RangeFunc
+	ctx.Functions["min"] = stdlib.MinFunc
+	ctx.Functions["max"] = stdlib.MaxFunc
+	ctx.Functions["abs"] = stdlib.AbsoluteFunc
+
+	// functions registered by the user or embedding application
+	addCustomFunctions(ctx.Functions)
 
 	return ctx
 }
 
-func decodeBody(b *hclsyntax.Block, p interface{}) error {
-	diag := gohcl.DecodeBody(b.Body, ctx, p)
+func (p *parser) decodeBody(b *hcl.Block, v interface{}) error {
+	// dynamic blocks are expanded into the nested blocks they define
+	// before decoding
+	diag := gohcl.DecodeBody(dynblock.Expand(b.Body, p.ctx), p.ctx, v)
+
+	// unknown attributes and blocks are warnings when using permissive mode
+	diag = p.checkUnknown(b, diag)
 	if diag.HasErrors() {
-		return errors.New(diag.Error())
+		return newParseError(diag)
 	}
 
+	markSensitiveAttributes(v)
+
 	return nil
 }
 
@@ -613,6 +2095,7 @@ func getFiles(source, dest string) error {
 		Dst:     dest,
 		Pwd:     pwd,
 		Mode:    getter.ClientModeAny,
+		Getters: utils.Getters(),
 		Options: []getter.ClientOption{},
 	}
 
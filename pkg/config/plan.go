@@ -0,0 +1,188 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// ChangeAction is the action which will be taken for a resource on the next run
+type ChangeAction string
+
+// ChangeCreate means the resource does not exist and will be created
+const ChangeCreate ChangeAction = "create"
+
+// ChangeReplace means the resource exists but has been tainted or failed,
+// or depends on a resource which will be replaced, and will be destroyed and
+// created again
+const ChangeReplace ChangeAction = "replace"
+
+// ChangeNotApplied means the attributes of an existing resource have changed,
+// run does not update existing resources so the change is only applied when
+// the resource is replaced e.g. with run --replace
+const ChangeNotApplied ChangeAction = "not_applied"
+
+// ChangeKept means the resource exists but has been removed from the config,
+// run does not remove it from the stack, it is removed by destroy
+const ChangeKept ChangeAction = "kept"
+
+// AttributeChange is a single attribute which differs between the state and the config
+type AttributeChange struct {
+	Name string
+	Old  interface{}
+	New  interface{}
+}
+
+// Change is a pending change for a resource
+type Change struct {
+	Action     ChangeAction
+	Resource   Resource
+	Attributes []AttributeChange
+}
+
+// planIgnoredAttributes are not part of the config for a resource
+var planIgnoredAttributes = map[string]bool{
+	"name":   true,
+	"type":   true,
+	"status": true,
+	"module": true,
+}
+
+// Plan compares the parsed config c with the recorded state and returns the
+// changes run will make, resources which are unchanged are not returned
+func Plan(state, c *Config) ([]Change, error) {
+	changes := []Change{}
+
+	for _, r := range c.Resources {
+		sr, err := state.FindResource(ResourceID(r))
+		if err != nil {
+			changes = append(changes, Change{Action: ChangeCreate, Resource: r})
+			continue
+		}
+
+		switch sr.Info().Status {
+		case PendingCreation:
+			changes = append(changes, Change{Action: ChangeCreate, Resource: r})
+			continue
+		case PendingModification, Failed:
+			changes = append(changes, Change{Action: ChangeReplace, Resource: r})
+			continue
+		}
+
+		attrs, err := diffAttributes(sr, r)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(attrs) > 0 {
+			changes = append(changes, Change{Action: ChangeNotApplied, Resource: r, Attributes: attrs})
+		}
+	}
+
+	changes = planDependents(c, changes)
+
+	for _, sr := range state.Resources {
+		_, err := c.FindResource(ResourceID(sr))
+		if err != nil {
+			changes = append(changes, Change{Action: ChangeKept, Resource: sr})
+		}
+	}
+
+	return changes, nil
+}
+
+// planDependents replaces the resources which depend on a resource which is
+// replaced, in the same way as run
+func planDependents(c *Config, changes []Change) []Change {
+	replaced := map[string]bool{}
+	index := map[string]int{}
+	for i, ch := range changes {
+		id := ResourceID(ch.Resource)
+		index[id] = i
+		replaced[id] = ch.Action == ChangeReplace
+	}
+
+	for added := true; added; {
+		added = false
+
+		for _, r := range c.Resources {
+			id := ResourceID(r)
+			if replaced[id] {
+				continue
+			}
+
+			for _, d := range r.Info().DependsOn {
+				dr, err := c.FindDependency(r, d)
+				if err != nil || !replaced[ResourceID(dr)] {
+					continue
+				}
+
+				// resources which are created do not have anything to replace
+				if i, ok := index[id]; ok {
+					if changes[i].Action == ChangeCreate {
+						break
+					}
+
+					changes[i].Action = ChangeReplace
+				} else {
+					index[id] = len(changes)
+					changes = append(changes, Change{Action: ChangeReplace, Resource: r})
+				}
+
+				replaced[id] = true
+				added = true
+				break
+			}
+		}
+	}
+
+	return changes
+}
+
+// diffAttributes returns the attributes set in the config which differ from
+// the state, attributes which are only in the state are output parameters
+// set when the resource was created and are ignored
+func diffAttributes(old, new Resource) ([]AttributeChange, error) {
+	om, err := attributeMap(old)
+	if err != nil {
+		return nil, err
+	}
+
+	nm, err := attributeMap(new)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []AttributeChange{}
+	for k, nv := range nm {
+		if planIgnoredAttributes[k] {
+			continue
+		}
+
+		ov := om[k]
+		if !reflect.DeepEqual(ov, nv) {
+			changes = append(changes, AttributeChange{Name: k, Old: ov, New: nv})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+
+	return changes, nil
+}
+
+// attributeMap returns the attributes of a resource as they are written to
+// the state, sensitive values are redacted in the same way
+func attributeMap(r Resource) (map[string]interface{}, error) {
+	d, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	m := map[string]interface{}{}
+	err = json.Unmarshal([]byte(Redact(string(d))), &m)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
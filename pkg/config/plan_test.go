@@ -0,0 +1,131 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupPlanTests() (*Config, *Config) {
+	state := New()
+
+	co := NewContainer("consul")
+	co.Image = Image{Name: "consul:1.8.0"}
+	co.Status = Applied
+	state.AddResource(co)
+
+	n := NewNetwork("cloud")
+	n.Subnet = "10.5.0.0/16"
+	n.Status = Applied
+	state.AddResource(n)
+
+	c := New()
+
+	co2 := NewContainer("consul")
+	co2.Image = Image{Name: "consul:1.8.0"}
+	c.AddResource(co2)
+
+	n2 := NewNetwork("cloud")
+	n2.Subnet = "10.5.0.0/16"
+	c.AddResource(n2)
+
+	return state, c
+}
+
+func TestPlanReturnsNoChangesWhenConfigMatchesState(t *testing.T) {
+	state, c := setupPlanTests()
+
+	changes, err := Plan(state, c)
+	require.NoError(t, err)
+
+	assert.Len(t, changes, 0)
+}
+
+func TestPlanReturnsCreateForNewResources(t *testing.T) {
+	state, c := setupPlanTests()
+	c.AddResource(NewContainer("vault"))
+
+	changes, err := Plan(state, c)
+	require.NoError(t, err)
+
+	require.Len(t, changes, 1)
+	assert.Equal(t, ChangeCreate, changes[0].Action)
+	assert.Equal(t, "vault", changes[0].Resource.Info().Name)
+}
+
+func TestPlanReturnsNotAppliedWithAttributesForChangedResources(t *testing.T) {
+	state, c := setupPlanTests()
+	c.Resources[0].(*Container).Image.Name = "consul:1.9.0"
+
+	changes, err := Plan(state, c)
+	require.NoError(t, err)
+
+	require.Len(t, changes, 1)
+	assert.Equal(t, ChangeNotApplied, changes[0].Action)
+	require.Len(t, changes[0].Attributes, 1)
+	assert.Equal(t, "image", changes[0].Attributes[0].Name)
+	assert.Equal(t, map[string]interface{}{"name": "consul:1.8.0"}, changes[0].Attributes[0].Old)
+	assert.Equal(t, map[string]interface{}{"name": "consul:1.9.0"}, changes[0].Attributes[0].New)
+}
+
+func TestPlanReturnsReplaceForTaintedResources(t *testing.T) {
+	state, c := setupPlanTests()
+	state.Resources[1].Info().Status = PendingModification
+
+	changes, err := Plan(state, c)
+	require.NoError(t, err)
+
+	require.Len(t, changes, 1)
+	assert.Equal(t, ChangeReplace, changes[0].Action)
+	assert.Equal(t, "cloud", changes[0].Resource.Info().Name)
+}
+
+func TestPlanReturnsKeptForRemovedResources(t *testing.T) {
+	state, c := setupPlanTests()
+	c.RemoveResource(c.Resources[1])
+
+	changes, err := Plan(state, c)
+	require.NoError(t, err)
+
+	require.Len(t, changes, 1)
+	assert.Equal(t, ChangeKept, changes[0].Action)
+	assert.Equal(t, TypeNetwork, changes[0].Resource.Info().Type)
+}
+
+func TestPlanReturnsReplaceForDependentsOfReplacedResources(t *testing.T) {
+	state, c := setupPlanTests()
+	state.Resources[1].Info().Status = PendingModification
+	state.Resources[0].Info().DependsOn = []string{"network.cloud"}
+	c.Resources[0].Info().DependsOn = []string{"network.cloud"}
+	c.Resources[0].(*Container).Image.Name = "consul:1.9.0"
+
+	changes, err := Plan(state, c)
+	require.NoError(t, err)
+
+	require.Len(t, changes, 2)
+	assert.Equal(t, ChangeReplace, changes[0].Action)
+	assert.Equal(t, "consul", changes[0].Resource.Info().Name)
+	assert.Len(t, changes[0].Attributes, 1)
+	assert.Equal(t, ChangeReplace, changes[1].Action)
+	assert.Equal(t, "cloud", changes[1].Resource.Info().Name)
+}
+
+func TestPlanIgnoresOutputParametersOnlySetInState(t *testing.T) {
+	state, c := setupPlanTests()
+
+	ls := NewLocalService("app")
+	ls.Command = "./app"
+	ls.PID = 1234
+	ls.Status = Applied
+	state.AddResource(ls)
+
+	ls2 := NewLocalService("app")
+	ls2.Command = "./app"
+	c.AddResource(ls2)
+
+	changes, err := Plan(state, c)
+	require.NoError(t, err)
+
+	assert.Len(t, changes, 0)
+}
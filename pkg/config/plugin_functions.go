@@ -0,0 +1,141 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+var customFunctions = map[string]function.Function{}
+var customFunctionsLock = sync.Mutex{}
+
+// RegisterFunction adds a function which can be used in expressions,
+// functions are added to the context after the built in functions and can
+// not replace them
+func RegisterFunction(name string, f function.Function) {
+	customFunctionsLock.Lock()
+	defer customFunctionsLock.Unlock()
+
+	customFunctions[name] = f
+}
+
+// FunctionsDir returns the folder containing function plugins,
+// usually $HOME/.shipyard/functions
+func FunctionsDir() string {
+	return filepath.Join(utils.ShipyardHome(), "functions")
+}
+
+var functionNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// addCustomFunctions adds the registered functions and any function
+// plugins to the context, functions with the same name as a built in
+// function are ignored
+func addCustomFunctions(funcs map[string]function.Function) {
+	for n, f := range pluginFunctions() {
+		if _, ok := funcs[n]; !ok {
+			funcs[n] = f
+		}
+	}
+
+	customFunctionsLock.Lock()
+	defer customFunctionsLock.Unlock()
+
+	for n, f := range customFunctions {
+		if _, ok := funcs[n]; !ok {
+			funcs[n] = f
+		}
+	}
+}
+
+// pluginFunctions returns a function for each executable in the functions
+// folder, the name of the function is the file name without the extension
+// e.g. vault_secret.sh is called using vault_secret("path")
+func pluginFunctions() map[string]function.Function {
+	funcs := map[string]function.Function{}
+
+	files, err := ioutil.ReadDir(FunctionsDir())
+	if err != nil {
+		return funcs
+	}
+
+	for _, f := range files {
+		if f.IsDir() || f.Mode()&0111 == 0 {
+			continue
+		}
+
+		name := strings.TrimSuffix(f.Name(), filepath.Ext(f.Name()))
+		if !functionNameRegexp.MatchString(name) {
+			continue
+		}
+
+		funcs[name] = newPluginFunc(name, filepath.Join(FunctionsDir(), f.Name()))
+	}
+
+	return funcs
+}
+
+// pluginRequest is written to the stdin of a function plugin
+type pluginRequest struct {
+	Args []ctyjson.SimpleJSONValue `json:"args"`
+}
+
+// newPluginFunc creates a function which executes a plugin, the arguments
+// are written to stdin as JSON {"args": [...]} and the plugin writes the
+// result to stdout as a JSON value. A non zero exit code is an error, the
+// message is read from stderr.
+func newPluginFunc(name, path string) function.Function {
+	return function.New(&function.Spec{
+		VarParam: &function.Parameter{
+			Name:             "args",
+			Type:             cty.DynamicPseudoType,
+			AllowDynamicType: true,
+			AllowNull:        true,
+		},
+		Type: function.StaticReturnType(cty.DynamicPseudoType),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			req := pluginRequest{Args: []ctyjson.SimpleJSONValue{}}
+			for _, a := range args {
+				req.Args = append(req.Args, ctyjson.SimpleJSONValue{Value: a})
+			}
+
+			in, err := json.Marshal(req)
+			if err != nil {
+				return cty.NilVal, fmt.Errorf("Unable to encode arguments for function %s: %s", name, err)
+			}
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			cmd := exec.Command(path)
+			cmd.Stdin = bytes.NewReader(in)
+			cmd.Stdout = stdout
+			cmd.Stderr = stderr
+			cmd.Env = os.Environ()
+
+			err = cmd.Run()
+			if err != nil {
+				return cty.NilVal, fmt.Errorf("Function %s failed: %s %s", name, err, strings.TrimSpace(stderr.String()))
+			}
+
+			v := ctyjson.SimpleJSONValue{}
+			err = v.UnmarshalJSON(bytes.TrimSpace(stdout.Bytes()))
+			if err != nil {
+				return cty.NilVal, fmt.Errorf("Function %s returned invalid JSON: %s", name, err)
+			}
+
+			return v.Value, nil
+		},
+	})
+}
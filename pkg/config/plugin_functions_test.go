@@ -0,0 +1,91 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+func setupFunctionPlugins(t *testing.T, plugins map[string]string) func() {
+	home := createTempDirectory(t)
+
+	oldHome := os.Getenv("HOME")
+	os.Setenv("HOME", home)
+
+	os.MkdirAll(FunctionsDir(), os.ModePerm)
+	for name, script := range plugins {
+		err := ioutil.WriteFile(filepath.Join(FunctionsDir(), name), []byte(script), 0755)
+		require.NoError(t, err)
+	}
+
+	return func() {
+		os.Setenv("HOME", oldHome)
+		removeTestFiles(t, home)
+	}
+}
+
+func TestRegisterFunctionAddsFunctionToContext(t *testing.T) {
+	RegisterFunction("greeting", function.New(&function.Spec{
+		Params: []function.Parameter{{Name: "name", Type: cty.String}},
+		Type:   function.StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			return cty.StringVal("hello " + args[0].AsString()), nil
+		},
+	}))
+	defer delete(customFunctions, "greeting")
+
+	v, err := evalTestExpression(t, `greeting("nic")`)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello nic", v.AsString())
+}
+
+func TestRegisterFunctionCanNotReplaceBuiltInFunctions(t *testing.T) {
+	RegisterFunction("upper", function.New(&function.Spec{
+		Params: []function.Parameter{{Name: "s", Type: cty.String}},
+		Type:   function.StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			return cty.StringVal("replaced"), nil
+		},
+	}))
+	defer delete(customFunctions, "upper")
+
+	v, err := evalTestExpression(t, `upper("consul")`)
+	assert.NoError(t, err)
+	assert.Equal(t, "CONSUL", v.AsString())
+}
+
+func TestPluginFunctionReturnsValue(t *testing.T) {
+	cleanup := setupFunctionPlugins(t, map[string]string{
+		// the plugin echoes the request so the arguments can be checked
+		"echo_args.sh": "#!/bin/sh\ncat",
+		"not_executable": "",
+	})
+	defer cleanup()
+
+	v, err := evalTestExpression(t, `echo_args("secret/data/consul", 2)`)
+	assert.NoError(t, err)
+
+	args := v.GetAttr("args")
+	assert.Equal(t, "secret/data/consul", args.Index(cty.NumberIntVal(0)).AsString())
+	assert.True(t, args.Index(cty.NumberIntVal(1)).Equals(cty.NumberIntVal(2)).True())
+
+	_, err = evalTestExpression(t, `not_executable()`)
+	assert.Error(t, err)
+}
+
+func TestPluginFunctionWithErrorReturnsMessage(t *testing.T) {
+	cleanup := setupFunctionPlugins(t, map[string]string{
+		"vault_secret": "#!/bin/sh\necho 'permission denied' >&2\nexit 1",
+	})
+	defer cleanup()
+
+	_, err := evalTestExpression(t, `vault_secret("secret/data/consul")`)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "permission denied")
+}
@@ -1,5 +1,11 @@
 package config
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 // Port is a port mapping
 type Port struct {
 	Local         string `hcl:"local" json:"local"`                                                             // Local port in the container
@@ -8,3 +14,66 @@ type Port struct {
 	Protocol      string `hcl:"protocol,optional" json:"protocol,omitempty"`                                    // Protocol tcp, udp
 	OpenInBrowser string `hcl:"open_in_browser,optional" json:"open_in_browser" mapstructure:"open_in_browser"` // When a host port is defined open this port with the given path in a browser
 }
+
+// maxPortRange is the largest number of ports a port range can contain
+const maxPortRange = 1000
+
+// PortRange is a range of ports mapped to the same ports on the target, e.g.
+// 8000-8010
+type PortRange struct {
+	Range      string `hcl:"range" json:"range"`                                                           // Range of ports start-end
+	EnableHost bool   `hcl:"enable_host,optional" json:"enable_host,omitempty" mapstructure:"enable_host"` // Expose the ports on the host
+	Protocol   string `hcl:"protocol,optional" json:"protocol,omitempty"`                                  // Protocol tcp, udp
+}
+
+// expandPortRanges converts the port ranges into a port mapping for each
+// port in the range
+func expandPortRanges(ranges []PortRange) ([]Port, error) {
+	ports := []Port{}
+
+	for _, r := range ranges {
+		start, end, err := parsePortRange(r.Range)
+		if err != nil {
+			return nil, err
+		}
+
+		for p := start; p <= end; p++ {
+			port := Port{Local: fmt.Sprintf("%d", p), Remote: fmt.Sprintf("%d", p), Protocol: r.Protocol}
+			if r.EnableHost {
+				port.Host = port.Local
+			}
+
+			ports = append(ports, port)
+		}
+	}
+
+	return ports, nil
+}
+
+// parsePortRange returns the first and last port of a range
+func parsePortRange(r string) (int, int, error) {
+	parts := strings.Split(r, "-")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid port range %s, ranges must be in the format start-end e.g. 8000-8010", r)
+	}
+
+	start, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid port range %s, start is not a number", r)
+	}
+
+	end, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid port range %s, end is not a number", r)
+	}
+
+	if start < 1 || end > 65535 || start > end {
+		return 0, 0, fmt.Errorf("invalid port range %s, ports must be between 1 and 65535 and start must not be greater than end", r)
+	}
+
+	if end-start >= maxPortRange {
+		return 0, 0, fmt.Errorf("invalid port range %s, ranges can contain at most %d ports", r, maxPortRange)
+	}
+
+	return start, end, nil
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/shipyard-run/shipyard/pkg/utils"
+)
+
+// TypeRandomPassword is the resource string for a RandomPassword resource
+const TypeRandomPassword ResourceType = "random_password"
+
+// TypeRandomID is the resource string for a RandomID resource
+const TypeRandomID ResourceType = "random_id"
+
+const (
+	passwordChars        = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	passwordSpecialChars = "!#%&*()-_=+[]{}<>:?"
+)
+
+// RandomPassword generates a random password which can be referenced by
+// other resources e.g. random_password.db.value, the password is stored in
+// the state and is only generated again when the length or special
+// attributes change
+type RandomPassword struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	Length  int  `hcl:"length,optional" json:"length"`
+	Special bool `hcl:"special,optional" json:"special"`
+
+	// Value is the generated password
+	Value string `json:"value" sensitive:"true"`
+}
+
+// NewRandomPassword creates a new RandomPassword config resource
+func NewRandomPassword(name string) *RandomPassword {
+	return &RandomPassword{
+		ResourceInfo: ResourceInfo{Name: name, Type: TypeRandomPassword, Status: PendingCreation},
+		Length:       32,
+	}
+}
+
+// RandomID generates random bytes which can be referenced by other
+// resources as hex or base64 e.g. random_id.suffix.hex, the value is stored
+// in the state and is only generated again when byte_length changes
+type RandomID struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	ByteLength int `hcl:"byte_length,optional" json:"byte_length" mapstructure:"byte_length"`
+
+	// Hex and Base64 are the generated bytes encoded as hex and url safe
+	// base64 without padding
+	Hex    string `json:"hex"`
+	Base64 string `json:"base64"`
+}
+
+// NewRandomID creates a new RandomID config resource
+func NewRandomID(name string) *RandomID {
+	return &RandomID{
+		ResourceInfo: ResourceInfo{Name: name, Type: TypeRandomID, Status: PendingCreation},
+		ByteLength:   8,
+	}
+}
+
+// generate sets the password, the password from the state of the resource
+// in module is used when the attributes have not changed
+func (p *RandomPassword) generate(module string) error {
+	if p.Length < 1 {
+		return fmt.Errorf("Invalid length %d for random_password %s, length must be greater than 0", p.Length, p.Name)
+	}
+
+	if s, ok := stateResource(module, p.Type, p.Name).(*RandomPassword); ok && s.Length == p.Length && s.Special == p.Special && s.Value != "" {
+		p.Value = s.Value
+		return nil
+	}
+
+	chars := passwordChars
+	if p.Special {
+		chars += passwordSpecialChars
+	}
+
+	v := make([]byte, p.Length)
+	for i := range v {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(chars))))
+		if err != nil {
+			return fmt.Errorf("Unable to generate password for random_password %s: %s", p.Name, err)
+		}
+
+		v[i] = chars[n.Int64()]
+	}
+
+	p.Value = string(v)
+
+	return nil
+}
+
+// generate sets the random bytes, the value from the state is used when
+// the byte length has not changed
+func (r *RandomID) generate(module string) error {
+	if r.ByteLength < 1 {
+		return fmt.Errorf("Invalid byte_length %d for random_id %s, byte_length must be greater than 0", r.ByteLength, r.Name)
+	}
+
+	if s, ok := stateResource(module, r.Type, r.Name).(*RandomID); ok && s.ByteLength == r.ByteLength && s.Hex != "" {
+		r.Hex = s.Hex
+		r.Base64 = s.Base64
+		return nil
+	}
+
+	b := make([]byte, r.ByteLength)
+	_, err := rand.Read(b)
+	if err != nil {
+		return fmt.Errorf("Unable to generate bytes for random_id %s: %s", r.Name, err)
+	}
+
+	r.Hex = hex.EncodeToString(b)
+	r.Base64 = base64.RawURLEncoding.EncodeToString(b)
+
+	return nil
+}
+
+// stateResource returns the resource defined in module from the state file,
+// nil is returned when the state or the resource does not exist
+func stateResource(module string, t ResourceType, name string) Resource {
+	sc := New()
+	err := sc.FromJSON(utils.StatePath())
+	if err != nil {
+		return nil
+	}
+
+	id := fmt.Sprintf("%s.%s", t, name)
+	if module != "" {
+		id = fmt.Sprintf("module.%s.%s", module, id)
+	}
+
+	r, err := sc.FindResource(id)
+	if err != nil {
+		return nil
+	}
+
+	return r
+}
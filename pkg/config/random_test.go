@@ -0,0 +1,144 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRandomPasswordCreatesCorrectly(t *testing.T) {
+	defer setupTemplateHome(t)()
+
+	c, _, cleanup := setupTestConfig(t, randomValid)
+	defer cleanup()
+
+	p, err := c.FindResource("random_password.db")
+	assert.NoError(t, err)
+
+	assert.Len(t, p.(*RandomPassword).Value, 32)
+	assert.False(t, strings.ContainsAny(p.(*RandomPassword).Value, passwordSpecialChars))
+
+	r, err := c.FindResource("random_id.suffix")
+	assert.NoError(t, err)
+
+	assert.Len(t, r.(*RandomID).Hex, 8)
+	assert.NotEmpty(t, r.(*RandomID).Base64)
+
+	co, err := c.FindResource("container.db")
+	assert.NoError(t, err)
+
+	assert.Equal(t, p.(*RandomPassword).Value, co.(*Container).Environment[0].Value)
+	assert.Contains(t, co.Info().DependsOn, "random_password.db")
+}
+
+func TestRandomValuesAreReadFromState(t *testing.T) {
+	defer setupTemplateHome(t)()
+
+	c, _, cleanup := setupTestConfig(t, randomValid)
+	defer cleanup()
+
+	require.NoError(t, c.ToJSON(utils.StatePath()))
+
+	c2, _, cleanup2 := setupTestConfig(t, randomValid)
+	defer cleanup2()
+
+	p, _ := c.FindResource("random_password.db")
+	p2, _ := c2.FindResource("random_password.db")
+	assert.Equal(t, p.(*RandomPassword).Value, p2.(*RandomPassword).Value)
+
+	r, _ := c.FindResource("random_id.suffix")
+	r2, _ := c2.FindResource("random_id.suffix")
+	assert.Equal(t, r.(*RandomID).Hex, r2.(*RandomID).Hex)
+}
+
+func TestRandomPasswordIsRedactedWhenInterpolated(t *testing.T) {
+	defer setupTemplateHome(t)()
+	defer ResetSensitive()
+
+	c, _, cleanup := setupTestConfig(t, strings.Replace(randomValid, "value = random_password.db.value", `value = "postgres://root:${random_password.db.value}@db"`, 1))
+	defer cleanup()
+
+	p, _ := c.FindResource("random_password.db")
+	require.True(t, IsSensitive(p.(*RandomPassword).Value))
+
+	co, _ := c.FindResource("container.db")
+	env := co.(*Container).Environment[0].Value
+	assert.Contains(t, env, p.(*RandomPassword).Value)
+	assert.Equal(t, "postgres://root:(sensitive value)@db", Redact(env))
+}
+
+func TestSensitiveRandomPasswordIsReadFromState(t *testing.T) {
+	defer setupTemplateHome(t)()
+	defer ResetSensitive()
+
+	c, _, cleanup := setupTestConfig(t, strings.Replace(randomValid, "value = random_password.db.value", "value = random_password.db.value\n\t\tsensitive = true", 1))
+	defer cleanup()
+
+	p, _ := c.FindResource("random_password.db")
+	require.True(t, IsSensitive(p.(*RandomPassword).Value))
+
+	require.NoError(t, c.ToJSON(utils.StatePath()))
+
+	sc := New()
+	require.NoError(t, sc.FromJSON(utils.StatePath()))
+
+	sp, _ := sc.FindResource("random_password.db")
+	assert.Equal(t, p.(*RandomPassword).Value, sp.(*RandomPassword).Value)
+
+	c2, _, cleanup2 := setupTestConfig(t, randomValid)
+	defer cleanup2()
+
+	p2, _ := c2.FindResource("random_password.db")
+	assert.Equal(t, p.(*RandomPassword).Value, p2.(*RandomPassword).Value)
+}
+
+func TestRandomPasswordGeneratedWhenAttributesChange(t *testing.T) {
+	defer setupTemplateHome(t)()
+
+	c, _, cleanup := setupTestConfig(t, randomValid)
+	defer cleanup()
+
+	require.NoError(t, c.ToJSON(utils.StatePath()))
+
+	c2, _, cleanup2 := setupTestConfig(t, strings.Replace(randomValid, "length  = 32", "length  = 16", 1))
+	defer cleanup2()
+
+	p2, _ := c2.FindResource("random_password.db")
+	assert.Len(t, p2.(*RandomPassword).Value, 16)
+}
+
+func TestRandomPasswordReturnsErrorForInvalidLength(t *testing.T) {
+	defer setupTemplateHome(t)()
+
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+	createNamedFile(t, dir, "*.hcl", `random_password "db" { length = 0 }`)
+
+	err := ParseFolder(dir, &Config{}, nil)
+	assert.Error(t, err)
+}
+
+const randomValid = `
+random_password "db" {
+	length  = 32
+	special = false
+}
+
+random_id "suffix" {
+	byte_length = 4
+}
+
+container "db" {
+	image {
+		name = "postgres:12"
+	}
+
+	env {
+		key   = "POSTGRES_PASSWORD"
+		value = random_password.db.value
+	}
+}
+`
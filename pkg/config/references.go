@@ -0,0 +1,500 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+	"golang.org/x/xerrors"
+)
+
+// resourcesVariable is the root variable which contains the attributes for
+// all decoded resources e.g. resources.network.local.name
+const resourcesVariable = "resources"
+
+// referenceTypes are the resource types which can also be referenced without
+// the resources prefix e.g. container.consul.image.name
+var referenceTypes = []ResourceType{
+	TypeAWS,
+	TypeCertificateCA,
+	TypeChaos,
+	TypeCertificateLeaf,
+	TypeConsulConfig,
+	TypeContainer,
+	TypeContainerBuild,
+	TypeContainerIngress,
+	TypeContainerRegistry,
+	TypeCopy,
+	TypeDNS,
+	TypeDockerVolume,
+	TypeDocs,
+	TypeExecLocal,
+	TypeExecRemote,
+	TypeGitOps,
+	TypeHelm,
+	TypeHelmRepository,
+	TypeHTTPCheck,
+	TypeImageCache,
+	TypeIngress,
+	TypeK8sCluster,
+	TypeK8sClusterExternal,
+	TypeK8sConfig,
+	TypeK8sConfigMap,
+	TypeK8sIngress,
+	TypeK8sNamespace,
+	TypeK8sSecret,
+	TypeK8sWait,
+	TypeKustomize,
+	TypeLoadBalancer,
+	TypeLocalIngress,
+	TypeLocalService,
+	TypeMinIO,
+	TypeMockAPI,
+	TypeNetwork,
+	TypeNetworkRoute,
+	TypeNomadCluster,
+	TypeNomadIngress,
+	TypeNomadJob,
+	TypeNullResource,
+	TypeRandomID,
+	TypeRandomPassword,
+	TypeSidecar,
+	TypeSQLExec,
+	TypeTemplate,
+	TypeTrafficCapture,
+	TypeVault,
+}
+
+// fileBlock is a block and the file it was defined in
+type fileBlock struct {
+	block *hcl.Block
+	file  string
+}
+
+// parseBlocks decodes the given blocks and adds them to the config. Blocks
+// which reference the attributes of other resources are decoded once the
+// resources they reference have been decoded, these blocks also depend on
+// the referenced resources.
+func (p *parser) parseBlocks(blocks []fileBlock, c *Config) error {
+	err := checkDuplicates(blocks)
+	if err != nil {
+		return err
+	}
+
+	pending := []fileBlock{}
+	modules := []fileBlock{}
+	deps := []dependency{}
+
+	for _, fb := range blocks {
+		if len(blockReferences(fb.block)) > 0 {
+			pending = append(pending, fb)
+			continue
+		}
+
+		// modules are decoded after the other blocks so that the order
+		// of resources is always the folder before any modules
+		if fb.block.Type == string(TypeModule) {
+			modules = append(modules, fb)
+			continue
+		}
+
+		d, err := p.parseFileBlock(fb, c, nil)
+		if err != nil {
+			return err
+		}
+
+		deps = append(deps, d...)
+	}
+
+	for _, fb := range modules {
+		d, err := p.parseFileBlock(fb, c, nil)
+		if err != nil {
+			return err
+		}
+
+		deps = append(deps, d...)
+	}
+
+	for len(pending) > 0 {
+		err := p.setResourceVariables(c)
+		if err != nil {
+			return err
+		}
+
+		remaining := []fileBlock{}
+
+		for _, fb := range pending {
+			refs := blockReferences(fb.block)
+			if len(missingReferences(c, refs)) > 0 {
+				remaining = append(remaining, fb)
+				continue
+			}
+
+			d, err := p.parseFileBlock(fb, c, refs)
+			if err != nil {
+				return err
+			}
+
+			deps = append(deps, d...)
+		}
+
+		// no blocks could be decoded, the references do not exist or are circular
+		if len(remaining) == len(pending) {
+			fb := remaining[0]
+			missing := missingReferences(c, blockReferences(fb.block))
+
+			return fmt.Errorf(
+				"Unable to resolve references in %s %s defined in file %s, resources %s do not exist or have circular references",
+				fb.block.Type,
+				strings.Join(fb.block.Labels, "."),
+				fb.file,
+				strings.Join(missing, ", "),
+			)
+		}
+
+		pending = remaining
+	}
+
+	err = checkDependencies(c, deps)
+	if err != nil {
+		return err
+	}
+
+	return p.setResourceVariables(c)
+}
+
+// checkDuplicates returns an error when more than one block has the
+// same type and name, variables and data sources are checked when the
+// context is created
+func checkDuplicates(blocks []fileBlock) error {
+	defined := map[string]*hcl.Block{}
+
+	for _, fb := range blocks {
+		b := fb.block
+		if b.Type == string(TypeVariable) || b.Type == string(TypeData) || len(b.Labels) == 0 {
+			continue
+		}
+
+		key := fmt.Sprintf("%s.%s", b.Type, b.Labels[0])
+		if first, ok := defined[key]; ok {
+			return DuplicateBlockError{Type: b.Type, Name: b.Labels[0], First: first.DefRange, Duplicate: b.DefRange}
+		}
+
+		defined[key] = b
+	}
+
+	return nil
+}
+
+// parseFileBlock expands and decodes a block, any resources created by the block
+// depend on the resources in dependsOn. The resources referenced by the
+// depends_on attribute of the block are returned so that they can be checked
+// once all blocks have been decoded.
+func (p *parser) parseFileBlock(fb fileBlock, c *Config, dependsOn []string) ([]dependency, error) {
+	p.setFileFunctions(fb.file)
+
+	// blocks with count or for_each are expanded into multiple instances
+	instances, err := p.expandBlock(fb.block)
+	if err != nil {
+		return nil, err
+	}
+
+	deps := []dependency{}
+
+	for _, i := range instances {
+		n := len(c.Resources)
+
+		p.setInstanceVariables(i.variables)
+		var onCreate, onDestroy []Hook
+		retries, interval, err := p.blockRetry(fb.block)
+		if err == nil {
+			onCreate, onDestroy, err = p.blockHooks(fb.block, fb.file)
+		}
+		if err == nil {
+			err = p.parseBlock(i.block, fb.file, c)
+		}
+		if err == nil {
+			deps = append(deps, p.blockDependencies(i.block)...)
+		}
+		p.setInstanceVariables(nil)
+
+		if xerrors.As(err, &ResourceExistsError{}) {
+			return nil, xerrors.Errorf("Unable to add %s %s defined at %s: %w", i.block.Type, i.block.Labels[0], i.block.DefRange, err)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range c.Resources[n:] {
+			r.Info().DependsOn = append(r.Info().DependsOn, dependsOn...)
+			r.Info().Retries = retries
+			r.Info().RetryInterval = interval
+			r.Info().OnCreate = onCreate
+			r.Info().OnDestroy = onDestroy
+		}
+	}
+
+	return deps, nil
+}
+
+// dependency is a resource referenced in the depends_on attribute of a block
+type dependency struct {
+	// block is the type and name of the block e.g. container.consul
+	block string
+	// module is the module which defines the block, names are resolved
+	// inside the module first
+	module string
+	name   string
+	rng    hcl.Range
+}
+
+// blockDependencies returns the resources referenced by the depends_on
+// attribute of a block, values which can not be evaluated are ignored as
+// they are reported when the block is decoded
+func (p *parser) blockDependencies(b *hcl.Block) []dependency {
+	content, _, _ := b.Body.PartialContent(&hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{{Name: "depends_on"}},
+	})
+
+	if content == nil || content.Attributes["depends_on"] == nil {
+		return nil
+	}
+
+	exprs, diag := hcl.ExprList(content.Attributes["depends_on"].Expr)
+	if diag.HasErrors() {
+		return nil
+	}
+
+	deps := []dependency{}
+	for _, e := range exprs {
+		v, diag := e.Value(p.ctx)
+		if diag.HasErrors() || v.Type() != cty.String || v.IsNull() || !v.IsKnown() {
+			continue
+		}
+
+		deps = append(deps, dependency{
+			block:  fmt.Sprintf("%s.%s", b.Type, strings.Join(b.Labels, ".")),
+			module: p.module,
+			name:   v.AsString(),
+			rng:    e.Range(),
+		})
+	}
+
+	return deps
+}
+
+// checkDependencies returns an error when a depends_on attribute references
+// a resource which does not exist
+func checkDependencies(c *Config, deps []dependency) error {
+	for _, d := range deps {
+		if !validResourceID(d.name) {
+			return DependencyError{Resource: d.block, Dependency: d.name, Range: d.rng, Reason: "is not a valid reference, references must be in the form type.name or module.name.type.name"}
+		}
+
+		if _, err := c.findInModule(d.module, d.name); err != nil {
+			return DependencyError{Resource: d.block, Dependency: d.name, Range: d.rng, Reason: "does not exist"}
+		}
+	}
+
+	return nil
+}
+
+// blockReferences returns the resources referenced by expressions in the
+// block in the form type.name
+func blockReferences(b *hcl.Block) []string {
+	refs := []string{}
+	seen := map[string]bool{}
+
+	for _, t := range bodyTraversals(b.Body) {
+		r := traversalReference(t)
+		if r == "" || seen[r] {
+			continue
+		}
+
+		seen[r] = true
+		refs = append(refs, r)
+	}
+
+	return refs
+}
+
+// bodyTraversals returns the variables referenced by expressions in the body,
+// bodies which are not native syntax such as JSON are read as attributes as
+// the schema is not known
+func bodyTraversals(b hcl.Body) []hcl.Traversal {
+	t := []hcl.Traversal{}
+
+	sb, ok := b.(*hclsyntax.Body)
+	if !ok {
+		attrs, _ := b.JustAttributes()
+		for _, a := range attrs {
+			t = append(t, a.Expr.Variables()...)
+		}
+
+		return t
+	}
+
+	for _, a := range sb.Attributes {
+		t = append(t, a.Expr.Variables()...)
+	}
+
+	for _, nb := range sb.Blocks {
+		if nb.Type != "dynamic" || len(nb.Labels) == 0 {
+			t = append(t, bodyTraversals(nb.Body)...)
+			continue
+		}
+
+		// the iterator for a dynamic block is not a reference
+		iterator := nb.Labels[0]
+		if a, ok := nb.Body.Attributes["iterator"]; ok {
+			iterator = hcl.ExprAsKeyword(a.Expr)
+		}
+
+		for _, dt := range bodyTraversals(nb.Body) {
+			if dt.RootName() != iterator {
+				t = append(t, dt)
+			}
+		}
+	}
+
+	return t
+}
+
+// traversalReference returns the resource referenced by a traversal
+// e.g. resources.network.local.name returns network.local, traversals which
+// do not reference a resource return an empty string
+func traversalReference(t hcl.Traversal) string {
+	steps := []string{t.RootName()}
+
+	for _, s := range t[1:] {
+		switch st := s.(type) {
+		case hcl.TraverseAttr:
+			steps = append(steps, st.Name)
+		case hcl.TraverseIndex:
+			if st.Key.Type() != cty.String || !st.Key.IsKnown() {
+				return ""
+			}
+
+			steps = append(steps, st.Key.AsString())
+		default:
+			return ""
+		}
+
+		if len(steps) == 3 {
+			break
+		}
+	}
+
+	if steps[0] == resourcesVariable {
+		steps = steps[1:]
+	} else if !isReferenceType(steps[0]) {
+		return ""
+	}
+
+	if len(steps) < 2 {
+		return ""
+	}
+
+	return fmt.Sprintf("%s.%s", steps[0], steps[1])
+}
+
+func isReferenceType(t string) bool {
+	for _, rt := range referenceTypes {
+		if string(rt) == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+func missingReferences(c *Config, refs []string) []string {
+	missing := []string{}
+
+	for _, r := range refs {
+		if _, err := c.FindResource(r); err != nil {
+			missing = append(missing, r)
+		}
+	}
+
+	return missing
+}
+
+// setResourceVariables adds the attributes of the decoded resources to the
+// context so that they can be referenced by other resources
+func (p *parser) setResourceVariables(c *Config) error {
+	types := map[string]map[string]cty.Value{}
+
+	for _, r := range c.Resources {
+		i := r.Info()
+		if !isReferenceType(string(i.Type)) {
+			continue
+		}
+
+		v, err := resourceValue(r)
+		if err != nil {
+			return fmt.Errorf("Unable to convert resource %s.%s: %s", i.Type, i.Name, err)
+		}
+
+		if types[string(i.Type)] == nil {
+			types[string(i.Type)] = map[string]cty.Value{}
+		}
+
+		// resources of modules which have been parsed do not replace
+		// resources with the same name in the module being parsed
+		if _, ok := types[string(i.Type)][i.Name]; ok && i.Module != "" {
+			continue
+		}
+
+		types[string(i.Type)][i.Name] = v
+	}
+
+	resources := map[string]cty.Value{}
+	for t, v := range types {
+		resources[t] = cty.ObjectVal(v)
+		p.ctx.Variables[t] = resources[t]
+	}
+
+	p.ctx.Variables[resourcesVariable] = cty.ObjectVal(resources)
+
+	return nil
+}
+
+// resourceValue converts a resource to a cty value using its json
+// representation, the value also contains the id of the resource
+// e.g. network.local
+func resourceValue(r Resource) (cty.Value, error) {
+	v, err := jsonValue(r)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	attrs := v.AsValueMap()
+	if attrs == nil {
+		attrs = map[string]cty.Value{}
+	}
+
+	attrs["id"] = cty.StringVal(fmt.Sprintf("%s.%s", r.Info().Type, r.Info().Name))
+
+	return cty.ObjectVal(attrs), nil
+}
+
+// jsonValue converts a value to a cty value using its json representation
+func jsonValue(i interface{}) (cty.Value, error) {
+	d, err := json.Marshal(i)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	t, err := ctyjson.ImpliedType(d)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	return ctyjson.Unmarshal(d, t)
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+	"github.com/stretchr/testify/assert"
+)
+
+func parseTestBlock(t *testing.T, src string) *hcl.Block {
+	f, diag := hclsyntax.ParseConfig([]byte(src), "test.hcl", hcl.Pos{Line: 1, Column: 1})
+	assert.False(t, diag.HasErrors())
+
+	return f.Body.(*hclsyntax.Body).Blocks[0].AsHCLBlock()
+}
+
+func TestReferencesResolveResourceAttributes(t *testing.T) {
+	// the referencing resource is defined before the referenced resources
+	c, err := setupVariableConfig(t, nil, referenceContainer, referenceNetwork)
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("container.web")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "network.local", co.(*Container).Networks[0].Name)
+	assert.Equal(t, []string{"local-web"}, co.(*Container).Networks[0].Aliases)
+	assert.Equal(t, "nginx:1.18", co.(*Container).Image.Name)
+}
+
+func TestReferencesAddDependencies(t *testing.T) {
+	c, err := setupVariableConfig(t, nil, referenceContainer, referenceNetwork)
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("container.web")
+	assert.NoError(t, err)
+
+	assert.Contains(t, co.Info().DependsOn, "network.local")
+	assert.Contains(t, co.Info().DependsOn, "container.base")
+}
+
+func TestReferencesResolveInOutputs(t *testing.T) {
+	c, err := setupVariableConfig(t, nil, referenceNetwork, referenceOutput)
+	assert.NoError(t, err)
+
+	o, err := c.FindOutput("subnet")
+	assert.NoError(t, err)
+
+	err = o.Evaluate()
+	assert.NoError(t, err)
+	assert.Equal(t, "10.5.0.0/16", o.Value)
+}
+
+func TestReferencesToMissingResourceReturnsError(t *testing.T) {
+	_, err := setupVariableConfig(t, nil, referenceContainer)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "network.local")
+}
+
+func TestReferencesWithCycleReturnsError(t *testing.T) {
+	_, err := setupVariableConfig(t, nil, referenceCycle)
+	assert.Error(t, err)
+}
+
+func TestTraversalReference(t *testing.T) {
+	tests := map[string]string{
+		"resources.network.local.name":    "network.local",
+		"container.web.image.name":        "container.web",
+		`resources.container["web-0"].id`: "container.web-0",
+		"var.version":                     "",
+		"count.index":                     "",
+		"resources.network":               "",
+	}
+
+	for in, out := range tests {
+		b := parseTestBlock(t, "container \"test\" {\n  value = "+in+"\n}")
+		refs := blockReferences(b)
+
+		if out == "" {
+			assert.Len(t, refs, 0, in)
+		} else {
+			assert.Equal(t, []string{out}, refs, in)
+		}
+	}
+}
+
+const referenceContainer = `
+container "web" {
+	image {
+		name = container.base.image.name
+	}
+
+	network {
+		name = resources.network.local.id
+		aliases = ["${resources.network.local.name}-web"]
+	}
+}
+`
+
+const referenceNetwork = `
+network "local" {
+	subnet = "10.5.0.0/16"
+}
+
+container "base" {
+	image {
+		name = "nginx:1.18"
+	}
+}
+`
+
+const referenceOutput = `
+output "subnet" {
+	value = resources.network.local.subnet
+}
+`
+
+const referenceCycle = `
+container "a" {
+	image {
+		name = container.b.image.name
+	}
+}
+
+container "b" {
+	image {
+		name = container.a.image.name
+	}
+}
+`
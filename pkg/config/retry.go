@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/zclconf/go-cty/cty/gocty"
+)
+
+// blockRetry evaluates the retries and retry_interval meta arguments for a
+// block, retries is nil when it is not set
+func (p *parser) blockRetry(b *hcl.Block) (*int, string, error) {
+	if len(b.Labels) == 0 {
+		return nil, "", nil
+	}
+
+	content, _, diag := b.Body.PartialContent(metaSchema)
+	if diag.HasErrors() {
+		return nil, "", fmt.Errorf("Unable to decode %s: %s", b.Type, diag.Error())
+	}
+
+	var retries *int
+	if attr, ok := content.Attributes["retries"]; ok {
+		v, diag := attr.Expr.Value(p.ctx)
+		if diag.HasErrors() {
+			return nil, "", fmt.Errorf("Invalid retries for %s %s: %s", b.Type, strings.Join(b.Labels, "."), diag.Error())
+		}
+
+		var n int
+		err := gocty.FromCtyValue(v, &n)
+		if err != nil || n < 0 {
+			return nil, "", fmt.Errorf("Invalid retries for %s %s, retries must be a whole number greater than or equal to 0", b.Type, strings.Join(b.Labels, "."))
+		}
+
+		retries = &n
+	}
+
+	interval := ""
+	if attr, ok := content.Attributes["retry_interval"]; ok {
+		v, diag := attr.Expr.Value(p.ctx)
+		if diag.HasErrors() {
+			return nil, "", fmt.Errorf("Invalid retry_interval for %s %s: %s", b.Type, strings.Join(b.Labels, "."), diag.Error())
+		}
+
+		err := gocty.FromCtyValue(v, &interval)
+		if err == nil {
+			_, err = time.ParseDuration(interval)
+		}
+
+		if err != nil {
+			return nil, "", fmt.Errorf("Invalid retry_interval for %s %s, retry_interval must be a duration e.g. 10s", b.Type, strings.Join(b.Labels, "."))
+		}
+	}
+
+	return retries, interval, nil
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetrySetsRetryPolicyOnResource(t *testing.T) {
+	c, err := setupVariableConfig(t, nil, retryContainer)
+	require.NoError(t, err)
+
+	co, err := c.FindResource("container.web")
+	require.NoError(t, err)
+
+	require.NotNil(t, co.Info().Retries)
+	assert.Equal(t, 3, *co.Info().Retries)
+	assert.Equal(t, "10s", co.Info().RetryInterval)
+}
+
+func TestRetryNotSetLeavesRetriesNil(t *testing.T) {
+	c, err := setupVariableConfig(t, nil, countContainer)
+	require.NoError(t, err)
+
+	assert.Nil(t, c.Resources[0].Info().Retries)
+}
+
+func TestRetryInvalidIntervalReturnsError(t *testing.T) {
+	_, err := setupVariableConfig(t, nil, `
+container "web" {
+  retries        = 1
+  retry_interval = "soon"
+
+  image {
+    name = "nginx"
+  }
+}
+`)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "retry_interval must be a duration")
+}
+
+func TestRetryPolicyIsLoadedFromState(t *testing.T) {
+	c, err := setupVariableConfig(t, nil, retryContainer)
+	require.NoError(t, err)
+
+	d, err := json.Marshal(c)
+	require.NoError(t, err)
+
+	sc := New()
+	err = json.Unmarshal(d, sc)
+	require.NoError(t, err)
+
+	co, err := sc.FindResource("container.web")
+	require.NoError(t, err)
+
+	require.NotNil(t, co.Info().Retries)
+	assert.Equal(t, 3, *co.Info().Retries)
+	assert.Equal(t, "10s", co.Info().RetryInterval)
+}
+
+const retryContainer = `
+container "web" {
+  retries        = 3
+  retry_interval = "10s"
+
+  image {
+    name = "nginx"
+  }
+}
+`
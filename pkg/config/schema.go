@@ -0,0 +1,239 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// schemaURI is the version of JSON Schema used by Schema
+const schemaURI = "http://json-schema.org/draft-07/schema#"
+
+// schemaTypes are the structs used to decode each resource type
+var schemaTypes = map[ResourceType]interface{}{
+	TypeCertificateCA:      CertificateCA{},
+	TypeCertificateLeaf:    CertificateLeaf{},
+	TypeContainer:          Container{},
+	TypeContainerBuild:     ContainerBuild{},
+	TypeContainerIngress:   ContainerIngress{},
+	TypeContainerRegistry:  ContainerRegistry{},
+	TypeCompose:            Compose{},
+	TypeService:            Service{},
+	TypeCopy:               Copy{},
+	TypeDocs:               Docs{},
+	TypeDockerVolume:       DockerVolume{},
+	TypeExecLocal:          ExecLocal{},
+	TypeExecRemote:         ExecRemote{},
+	TypeLocalService:       LocalService{},
+	TypeLocalIngress:       LocalIngress{},
+	TypeHelm:               Helm{},
+	TypeHelmRepository:     HelmRepository{},
+	TypeImageCache:         ImageCache{},
+	TypeIngress:            Ingress{},
+	TypeK8sCluster:         K8sCluster{},
+	TypeK8sClusterExternal: K8sClusterExternal{},
+	TypeK8sConfig:          K8sConfig{},
+	TypeK8sIngress:         K8sIngress{},
+	TypeK8sNamespace:       K8sNamespace{},
+	TypeK8sSecret:          K8sSecret{},
+	TypeK8sConfigMap:       K8sConfigMap{},
+	TypeK8sWait:            K8sWait{},
+	TypeGitOps:             GitOps{},
+	TypeHTTPCheck:          HTTPCheck{},
+	TypeSQLExec:            SQLExec{},
+	TypeConsulConfig:       ConsulConfig{},
+	TypeVault:              Vault{},
+	TypeAWS:                AWS{},
+	TypeMockAPI:            MockAPI{},
+	TypeChaos:              Chaos{},
+	TypeTrafficCapture:     TrafficCapture{},
+	TypeNetworkRoute:       NetworkRoute{},
+	TypeMinIO:              MinIO{},
+	TypeDNS:                DNS{},
+	TypeLoadBalancer:       LoadBalancer{},
+	TypeKustomize:          Kustomize{},
+	TypeModule:             Module{},
+	TypeNetwork:            Network{},
+	TypeNomadCluster:       NomadCluster{},
+	TypeNomadIngress:       NomadIngress{},
+	TypeNomadJob:           NomadJob{},
+	TypeOutput:             outputBody{},
+	TypeNullResource:       NullResource{},
+	TypeRandomID:           RandomID{},
+	TypeRandomPassword:     RandomPassword{},
+	TypeSidecar:            Sidecar{},
+	TypeTemplate:           Template{},
+}
+
+// Schema returns a JSON Schema describing the resources which can be defined
+// in a blueprint using the JSON or YAML syntax, the schema can be used by
+// editors to provide completion and validation
+func Schema() ([]byte, error) {
+	props := map[string]interface{}{}
+
+	for t, s := range schemaTypes {
+		rs := structSchema(reflect.TypeOf(s))
+
+		// outputs, modules, compose files and services can not be expanded using the meta arguments
+		if t != TypeOutput && t != TypeModule && t != TypeCompose && t != TypeService {
+			for n, m := range metaArgumentSchema() {
+				rs["properties"].(map[string]interface{})[n] = m
+			}
+		}
+
+		props[string(t)] = namedBlocks(rs)
+	}
+
+	props[string(TypeVariable)] = namedBlocks(map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"description": map[string]interface{}{"type": "string"},
+			"type":        map[string]interface{}{"type": "string"},
+			"default":     map[string]interface{}{},
+			"sensitive":   map[string]interface{}{"type": []string{"boolean", "string"}},
+		},
+		"additionalProperties": false,
+	})
+
+	props[string(TypeLocals)] = map[string]interface{}{"type": "object"}
+
+	props[string(TypeData)] = map[string]interface{}{
+		"type": "object",
+		"additionalProperties": namedBlocks(map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": map[string]interface{}{"type": "string"},
+		}),
+	}
+
+	s := map[string]interface{}{
+		"$schema":              schemaURI,
+		"title":                "Shipyard blueprint",
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+
+	return json.MarshalIndent(s, "", "  ")
+}
+
+// namedBlocks returns the schema for blocks which have a name label, in the
+// JSON syntax the label is the key of an object containing the block
+func namedBlocks(s map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"type":                 "object",
+		"additionalProperties": s,
+	}
+}
+
+// metaArgumentSchema returns the schema for the meta arguments which can be
+// set on any resource
+func metaArgumentSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"count":          map[string]interface{}{"type": []string{"integer", "string"}},
+		"for_each":       map[string]interface{}{"type": []string{"object", "array", "string"}},
+		"enabled":        map[string]interface{}{"type": []string{"boolean", "string"}},
+		"disabled":       map[string]interface{}{"type": []string{"boolean", "string"}},
+		"retries":        map[string]interface{}{"type": []string{"integer", "string"}},
+		"retry_interval": map[string]interface{}{"type": "string"},
+		"on_create":      map[string]interface{}{"type": []string{"object", "array"}},
+		"on_destroy":     map[string]interface{}{"type": []string{"object", "array"}},
+	}
+}
+
+// structSchema returns the schema for a struct using the hcl tags of its
+// fields, fields without a hcl tag are not part of the config
+func structSchema(t reflect.Type) map[string]interface{} {
+	props := map[string]interface{}{}
+	required := []string{}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		tag, ok := f.Tag.Lookup("hcl")
+		if !ok {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		name := parts[0]
+		kind := "attr"
+		if len(parts) > 1 {
+			kind = parts[1]
+		}
+
+		switch kind {
+		case "attr":
+			props[name] = valueSchema(f.Type)
+			required = append(required, name)
+		case "optional":
+			props[name] = valueSchema(f.Type)
+		case "block":
+			props[name] = blockSchema(f.Type)
+
+			// single blocks which are not pointers must be set
+			if f.Type.Kind() == reflect.Struct {
+				required = append(required, name)
+			}
+		}
+	}
+
+	s := map[string]interface{}{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+
+	if len(required) > 0 {
+		sort.Strings(required)
+		s["required"] = required
+	}
+
+	return s
+}
+
+// blockSchema returns the schema for a nested block, in the JSON syntax
+// blocks which can be repeated are defined as an array of objects or a
+// single object
+func blockSchema(t reflect.Type) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return blockSchema(t.Elem())
+	case reflect.Slice:
+		item := blockSchema(t.Elem())
+		return map[string]interface{}{
+			"anyOf": []interface{}{
+				item,
+				map[string]interface{}{"type": "array", "items": item},
+			},
+		}
+	}
+
+	return structSchema(t)
+}
+
+// valueSchema returns the schema for an attribute, as the JSON syntax allows
+// any value to be set using a string template e.g. "${var.cpu}" attributes
+// which are not strings also accept a string
+func valueSchema(t reflect.Type) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return valueSchema(t.Elem())
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": []string{"boolean", "string"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": []string{"integer", "string"}}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": []string{"number", "string"}}
+	case reflect.Slice:
+		return map[string]interface{}{"type": []string{"array", "string"}, "items": valueSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": []string{"object", "string"}, "additionalProperties": valueSchema(t.Elem())}
+	}
+
+	// expressions and interfaces accept any value
+	return map[string]interface{}{}
+}
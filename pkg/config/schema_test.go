@@ -0,0 +1,69 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupSchema(t *testing.T) map[string]interface{} {
+	d, err := Schema()
+	assert.NoError(t, err)
+
+	s := map[string]interface{}{}
+	err = json.Unmarshal(d, &s)
+	assert.NoError(t, err)
+
+	return s
+}
+
+// resourceSchema returns the schema for a single resource of the given type
+func resourceSchema(t *testing.T, s map[string]interface{}, rt ResourceType) map[string]interface{} {
+	props := s["properties"].(map[string]interface{})
+	assert.Contains(t, props, string(rt))
+
+	return props[string(rt)].(map[string]interface{})["additionalProperties"].(map[string]interface{})
+}
+
+func TestSchemaContainsAllResourceTypes(t *testing.T) {
+	s := setupSchema(t)
+
+	assert.Equal(t, schemaURI, s["$schema"])
+
+	props := s["properties"].(map[string]interface{})
+	for _, rt := range append(referenceTypes, TypeModule, TypeOutput, TypeVariable, TypeLocals, TypeData) {
+		assert.Contains(t, props, string(rt))
+	}
+}
+
+func TestSchemaContainsAttributesAndBlocks(t *testing.T) {
+	s := setupSchema(t)
+
+	c := resourceSchema(t, s, TypeContainer)
+	props := c["properties"].(map[string]interface{})
+
+	assert.Equal(t, []interface{}{"array", "string"}, props["entrypoint"].(map[string]interface{})["type"])
+	assert.Equal(t, []interface{}{"boolean", "string"}, props["privileged"].(map[string]interface{})["type"])
+	assert.Contains(t, props, "count")
+
+	// image is a required block
+	image := props["image"].(map[string]interface{})
+	assert.Equal(t, "object", image["type"])
+	assert.Contains(t, image["required"], "name")
+	assert.Contains(t, c["required"], "image")
+
+	// ports can be repeated
+	assert.Contains(t, props["port"], "anyOf")
+}
+
+func TestSchemaOutputsDoNotContainMetaArguments(t *testing.T) {
+	s := setupSchema(t)
+
+	o := resourceSchema(t, s, TypeOutput)
+	props := o["properties"].(map[string]interface{})
+
+	assert.Contains(t, props, "value")
+	assert.NotContains(t, props, "count")
+	assert.Equal(t, []interface{}{"value"}, o["required"])
+}
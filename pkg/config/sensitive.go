@@ -0,0 +1,196 @@
+package config
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// RedactedValue replaces sensitive values in logs and output
+const RedactedValue = "(sensitive value)"
+
+var sensitiveMutex sync.RWMutex
+var sensitiveValues = map[string]bool{}
+
+// MarkSensitive records a value as sensitive, any output which contains the
+// value will be redacted. Since values are matched by content, strings which
+// are built by interpolating a sensitive value are also redacted.
+func MarkSensitive(v string) {
+	if v == "" {
+		return
+	}
+
+	sensitiveMutex.Lock()
+	defer sensitiveMutex.Unlock()
+
+	sensitiveValues[v] = true
+
+	// values are also matched in their JSON escaped form so they can be
+	// removed from serialized output
+	if d, err := json.Marshal(v); err == nil {
+		e := strings.Trim(string(d), `"`)
+		sensitiveValues[e] = true
+	}
+}
+
+// markSensitiveValue marks the strings contained in a value as sensitive,
+// numbers and bools are not marked as they are matched by content and would
+// redact unrelated values
+func markSensitiveValue(v cty.Value) {
+	if v.IsNull() || !v.IsKnown() {
+		return
+	}
+
+	if v.Type() == cty.String {
+		MarkSensitive(v.AsString())
+		return
+	}
+
+	if v.CanIterateElements() {
+		for it := v.ElementIterator(); it.Next(); {
+			_, ev := it.Element()
+			markSensitiveValue(ev)
+		}
+	}
+}
+
+var kvType = reflect.TypeOf(KV{})
+
+// markSensitiveAttributes marks the values of fields tagged with
+// sensitive:"true" and of env or config blocks which set sensitive = true
+// in a resource, nested blocks are also searched
+func markSensitiveAttributes(i interface{}) {
+	markSensitiveFields(reflect.Indirect(reflect.ValueOf(i)))
+}
+
+func markSensitiveFields(v reflect.Value) {
+	if v.Kind() != reflect.Struct {
+		return
+	}
+
+	if v.Type() == kvType {
+		if v.FieldByName("Sensitive").Bool() {
+			MarkSensitive(v.FieldByName("Value").String())
+		}
+
+		return
+	}
+
+	for n := 0; n < v.NumField(); n++ {
+		f := v.Field(n)
+
+		switch f.Kind() {
+		case reflect.String:
+			if v.Type().Field(n).Tag.Get("sensitive") == "true" {
+				MarkSensitive(f.String())
+			}
+		case reflect.Struct:
+			markSensitiveFields(f)
+		case reflect.Slice:
+			for j := 0; j < f.Len(); j++ {
+				markSensitiveFields(f.Index(j))
+			}
+		}
+	}
+}
+
+// markStateSensitive marks the sensitive attributes of the resources and
+// sensitive outputs in a config loaded from the state
+func markStateSensitive(c *Config) {
+	for _, r := range c.Resources {
+		markSensitiveAttributes(r)
+	}
+
+	for _, o := range c.Outputs {
+		if !o.Sensitive {
+			continue
+		}
+
+		markSensitiveInterface(o.Value)
+	}
+}
+
+// markSensitiveInterface marks the strings contained in a value decoded
+// from JSON as sensitive
+func markSensitiveInterface(v interface{}) {
+	switch t := v.(type) {
+	case string:
+		MarkSensitive(t)
+	case []interface{}:
+		for _, i := range t {
+			markSensitiveInterface(i)
+		}
+	case map[string]interface{}:
+		for _, i := range t {
+			markSensitiveInterface(i)
+		}
+	}
+}
+
+// IsSensitive returns true when the value has been marked as sensitive
+func IsSensitive(v string) bool {
+	sensitiveMutex.RLock()
+	defer sensitiveMutex.RUnlock()
+
+	return sensitiveValues[v]
+}
+
+// ResetSensitive removes all values which have been marked as sensitive
+func ResetSensitive() {
+	sensitiveMutex.Lock()
+	defer sensitiveMutex.Unlock()
+
+	sensitiveValues = map[string]bool{}
+}
+
+// Redact replaces any sensitive values contained in s with RedactedValue
+func Redact(s string) string {
+	sensitiveMutex.RLock()
+	defer sensitiveMutex.RUnlock()
+
+	if len(sensitiveValues) == 0 {
+		return s
+	}
+
+	// replace the longest values first so a value which contains
+	// another sensitive value is not partially redacted
+	values := make([]string, 0, len(sensitiveValues))
+	for v := range sensitiveValues {
+		values = append(values, v)
+	}
+
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+
+	for _, v := range values {
+		s = strings.Replace(s, v, RedactedValue, -1)
+	}
+
+	return s
+}
+
+// RedactWriter is an io.Writer which removes sensitive values before
+// writing to the underlying writer
+type RedactWriter struct {
+	w io.Writer
+}
+
+// NewRedactWriter creates a RedactWriter which writes to w
+func NewRedactWriter(w io.Writer) *RedactWriter {
+	return &RedactWriter{w}
+}
+
+// Write redacts p and writes it to the underlying writer, the returned count
+// is the length of p so that callers do not treat redaction as a short write
+func (r *RedactWriter) Write(p []byte) (int, error) {
+	_, err := r.w.Write([]byte(Redact(string(p))))
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
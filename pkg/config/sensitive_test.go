@@ -0,0 +1,277 @@
+package config
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactReplacesSensitiveValues(t *testing.T) {
+	defer ResetSensitive()
+	MarkSensitive("s3cr3t")
+
+	assert.Equal(t, "token=(sensitive value)", Redact("token=s3cr3t"))
+	assert.Equal(t, "nothing here", Redact("nothing here"))
+}
+
+func TestRedactReplacesLongestValueFirst(t *testing.T) {
+	defer ResetSensitive()
+	MarkSensitive("abc")
+	MarkSensitive("abcdef")
+
+	assert.Equal(t, "(sensitive value)", Redact("abcdef"))
+}
+
+func TestMarkSensitiveIgnoresEmptyValues(t *testing.T) {
+	defer ResetSensitive()
+	MarkSensitive("")
+
+	assert.Equal(t, "abc", Redact("abc"))
+}
+
+func TestRedactWriterRedactsOutput(t *testing.T) {
+	defer ResetSensitive()
+	MarkSensitive("s3cr3t")
+
+	b := bytes.NewBufferString("")
+	w := NewRedactWriter(b)
+
+	n, err := w.Write([]byte("password s3cr3t"))
+	assert.NoError(t, err)
+	assert.Equal(t, 15, n)
+	assert.Equal(t, "password (sensitive value)", b.String())
+}
+
+func TestSensitiveFunctionMarksValues(t *testing.T) {
+	defer ResetSensitive()
+	os.Setenv("SHIPYARD_TEST_TOKEN", "s3cr3t")
+	defer os.Unsetenv("SHIPYARD_TEST_TOKEN")
+
+	c, _, cleanup := setupTestConfig(t, containerSensitive)
+	defer cleanup()
+
+	co, err := c.FindResource("container.testing")
+	assert.NoError(t, err)
+
+	// the value is unchanged in the config but is marked as sensitive
+	// including when it has been interpolated into another string
+	env := co.(*Container).Environment
+	assert.Equal(t, "s3cr3t", env[0].Value)
+	assert.True(t, IsSensitive("s3cr3t"))
+	assert.Equal(t, "Bearer (sensitive value)", Redact(env[1].Value))
+}
+
+func TestStateContainsSensitiveValues(t *testing.T) {
+	defer ResetSensitive()
+	c, cleanup := setupConfigTests(t)
+	defer cleanup()
+
+	MarkSensitive(`s3"cr3t`)
+	co, _ := c.FindResource("container.config")
+	co.(*Container).Environment = []KV{KV{Key: "TOKEN", Value: `s3"cr3t`, Sensitive: true}}
+
+	err := c.ToJSON(utils.StatePath())
+	assert.NoError(t, err)
+
+	// the state must not lose values, sensitive values are only redacted
+	// when they are displayed
+	d, err := ioutil.ReadFile(utils.StatePath())
+	assert.NoError(t, err)
+	assert.Contains(t, string(d), `s3\"cr3t`)
+	assert.NotContains(t, string(d), RedactedValue)
+}
+
+func TestStateMarksSensitiveValuesWhenLoaded(t *testing.T) {
+	defer ResetSensitive()
+	c, cleanup := setupConfigTests(t)
+	defer cleanup()
+
+	co, _ := c.FindResource("container.config")
+	co.(*Container).Environment = []KV{
+		KV{Key: "TOKEN", Value: "s3cr3t", Sensitive: true},
+		KV{Key: "LOG", Value: "info"},
+	}
+	c.Outputs = []*Output{&Output{Name: "key", Sensitive: true, Value: []interface{}{"license-key"}}}
+
+	err := c.ToJSON(utils.StatePath())
+	assert.NoError(t, err)
+
+	sc := New()
+	err = sc.FromJSON(utils.StatePath())
+	assert.NoError(t, err)
+
+	sco, _ := sc.FindResource("container.config")
+	assert.Equal(t, "s3cr3t", sco.(*Container).Environment[0].Value)
+
+	assert.True(t, IsSensitive("s3cr3t"))
+	assert.True(t, IsSensitive("license-key"))
+	assert.False(t, IsSensitive("info"))
+}
+
+func TestStateMarksSensitiveAttributesWhenLoaded(t *testing.T) {
+	defer ResetSensitive()
+	c, cleanup := setupConfigTests(t)
+	defer cleanup()
+
+	se := NewSQLExec("db")
+	se.Password = "sql-password"
+	c.AddResource(se)
+
+	cc := NewConsulConfig("entries")
+	cc.Token = "consul-token"
+	c.AddResource(cc)
+
+	hr := NewHelmRepository("charts")
+	hr.Password = "helm-password"
+	c.AddResource(hr)
+
+	v := NewVault("dev")
+	v.RootToken = "vault-token"
+	c.AddResource(v)
+
+	m := NewMinIO("s3")
+	m.SecretKey = "minio-secret"
+	m.Users = []MinIOUser{MinIOUser{AccessKey: "app", SecretKey: "user-secret"}}
+	c.AddResource(m)
+
+	p := NewRandomPassword("db")
+	p.Value = "random-password"
+	c.AddResource(p)
+
+	err := c.ToJSON(utils.StatePath())
+	assert.NoError(t, err)
+
+	ResetSensitive()
+
+	sc := New()
+	err = sc.FromJSON(utils.StatePath())
+	assert.NoError(t, err)
+
+	for _, v := range []string{"sql-password", "consul-token", "helm-password", "vault-token", "minio-secret", "user-secret", "random-password"} {
+		assert.True(t, IsSensitive(v), v)
+	}
+
+	assert.False(t, IsSensitive("app"))
+}
+
+func TestSensitiveVariableMarksValues(t *testing.T) {
+	defer ResetSensitive()
+
+	c, err := setupVariableConfig(t, map[string]string{"token": "s3cr3t"}, variableSensitive)
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("container.testing")
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cr3t", co.(*Container).Environment[0].Value)
+
+	assert.True(t, IsSensitive("s3cr3t"))
+	assert.True(t, IsSensitive("a"))
+	assert.True(t, IsSensitive("b"))
+
+	// variables which are not sensitive are not marked
+	assert.False(t, IsSensitive("consul"))
+}
+
+func TestSensitiveEnvBlockMarksValue(t *testing.T) {
+	defer ResetSensitive()
+
+	c, err := setupVariableConfig(t, nil, envSensitive)
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("container.testing")
+	assert.NoError(t, err)
+	assert.True(t, co.(*Container).Environment[0].Sensitive)
+
+	assert.True(t, IsSensitive("license-key"))
+	assert.False(t, IsSensitive("info"))
+}
+
+func TestSensitiveOutputMarksValue(t *testing.T) {
+	defer ResetSensitive()
+
+	c, err := setupVariableConfig(t, nil, outputSensitive)
+	assert.NoError(t, err)
+
+	o, err := c.FindOutput("token")
+	assert.NoError(t, err)
+	assert.True(t, o.Sensitive)
+
+	err = o.Evaluate()
+	assert.NoError(t, err)
+	assert.True(t, IsSensitive("root-token"))
+}
+
+const variableSensitive = `
+variable "token" {
+	sensitive = true
+}
+
+variable "keys" {
+	type      = list(string)
+	default   = ["a", "b"]
+	sensitive = true
+}
+
+variable "image" {
+	default = "consul"
+}
+
+container "testing" {
+	image {
+		name = var.image
+	}
+
+	env {
+		key = "TOKEN"
+		value = var.token
+	}
+}
+`
+
+const envSensitive = `
+container "testing" {
+	image {
+		name = "consul"
+	}
+
+	env {
+		key       = "LICENSE"
+		value     = "license-key"
+		sensitive = true
+	}
+
+	env {
+		key   = "LOG_LEVEL"
+		value = "info"
+	}
+}
+`
+
+const outputSensitive = `
+output "token" {
+	value     = "root-token"
+	sensitive = true
+}
+`
+
+const containerSensitive = `
+container "testing" {
+	image {
+		name = "consul"
+	}
+
+	env {
+		key = "TOKEN"
+		value = sensitive(env("SHIPYARD_TEST_TOKEN"))
+	}
+
+	env {
+		key = "HEADER"
+		value = "Bearer ${sensitive(env("SHIPYARD_TEST_TOKEN"))}"
+	}
+}
+`
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TypeService is the resource string for a Service resource
+const TypeService ResourceType = "service"
+
+// Service adds a container for a common backing service from the service
+// catalog e.g. postgres, the container is exposed on the local machine and
+// a http_check with the same name waits until the service accepts connections
+type Service struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	// Catalog is the name of the service in the catalog, defaults to the name
+	// of the block
+	Catalog string `hcl:"catalog,optional" json:"catalog,omitempty"`
+
+	// Version is the tag of the image, defaults to the version in the catalog
+	Version string `hcl:"version,optional" json:"version,omitempty"`
+
+	// Port is the port on the local machine the service is exposed on,
+	// defaults to the port of the service
+	Port string `hcl:"port,optional" json:"port,omitempty"`
+
+	// Network the container is attached to e.g. network.local
+	Network string `hcl:"network,optional" json:"network,omitempty"`
+
+	// Env is merged with the default environment of the service
+	Env map[string]string `hcl:"env,optional" json:"env,omitempty"`
+}
+
+// NewService creates a new Service config resource
+func NewService(name string) *Service {
+	return &Service{ResourceInfo: ResourceInfo{Name: name, Type: TypeService, Status: PendingCreation}}
+}
+
+// serviceCatalogEntry defines the container for a service in the catalog
+type serviceCatalogEntry struct {
+	image   string
+	version string
+	port    string
+	env     map[string]string
+}
+
+// serviceCatalog are the services which can be created with a service block
+var serviceCatalog = map[string]serviceCatalogEntry{
+	"postgres": {
+		image:   "postgres",
+		version: "14",
+		port:    "5432",
+		env: map[string]string{
+			"POSTGRES_USER":     "postgres",
+			"POSTGRES_PASSWORD": "password",
+		},
+	},
+	"redis": {
+		image:   "redis",
+		version: "6",
+		port:    "6379",
+	},
+	"kafka": {
+		image:   "bitnami/kafka",
+		version: "3.4",
+		port:    "9092",
+		env: map[string]string{
+			"KAFKA_ENABLE_KRAFT":                       "yes",
+			"KAFKA_CFG_NODE_ID":                        "1",
+			"KAFKA_CFG_PROCESS_ROLES":                  "broker,controller",
+			"KAFKA_CFG_CONTROLLER_LISTENER_NAMES":      "CONTROLLER",
+			"KAFKA_CFG_CONTROLLER_QUORUM_VOTERS":       "1@127.0.0.1:9093",
+			"KAFKA_CFG_LISTENERS":                      "PLAINTEXT://:9092,CONTROLLER://:9093",
+			"KAFKA_CFG_ADVERTISED_LISTENERS":           "PLAINTEXT://localhost:9092",
+			"KAFKA_CFG_LISTENER_SECURITY_PROTOCOL_MAP": "CONTROLLER:PLAINTEXT,PLAINTEXT:PLAINTEXT",
+			"ALLOW_PLAINTEXT_LISTENER":                 "yes",
+		},
+	},
+}
+
+// serviceResources returns the container and http_check resources for the
+// service
+func serviceResources(s *Service) ([]Resource, error) {
+	name := s.Catalog
+	if name == "" {
+		name = s.Name
+	}
+
+	e, ok := serviceCatalog[name]
+	if !ok {
+		return nil, fmt.Errorf("Unable to create service %s, %s is not in the service catalog, available services are %s", s.Name, name, strings.Join(serviceCatalogNames(), ", "))
+	}
+
+	version := s.Version
+	if version == "" {
+		version = e.version
+	}
+
+	port := s.Port
+	if port == "" {
+		port = e.port
+	}
+
+	co := NewContainer(s.Name)
+	co.Image.Name = fmt.Sprintf("%s:%s", e.image, version)
+	co.Depends = append(co.Depends, s.Depends...)
+	co.Ports = []Port{{Local: e.port, Remote: e.port, Host: port}}
+
+	env := map[string]string{}
+	for k, v := range e.env {
+		env[k] = v
+	}
+
+	for k, v := range s.Env {
+		env[k] = v
+	}
+
+	for _, k := range sortedKeys(env) {
+		co.Environment = append(co.Environment, KV{Key: k, Value: env[k]})
+	}
+
+	if s.Network != "" {
+		co.Networks = append(co.Networks, NetworkAttachment{Name: s.Network})
+	}
+
+	hc := NewHTTPCheck(s.Name)
+	hc.TCP = fmt.Sprintf("localhost:%s", port)
+	hc.Timeout = "60s"
+	hc.Depends = []string{fmt.Sprintf("%s.%s", TypeContainer, s.Name)}
+
+	return []Resource{co, hc}, nil
+}
+
+func serviceCatalogNames() []string {
+	names := []string{}
+	for n := range serviceCatalog {
+		names = append(names, n)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
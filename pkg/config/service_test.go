@@ -0,0 +1,75 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceCreatesContainerAndCheck(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, serviceValid)
+	defer cleanup()
+
+	r, err := c.FindResource("container.postgres")
+	require.NoError(t, err)
+
+	co := r.(*Container)
+	assert.Equal(t, "postgres:13", co.Image.Name)
+	assert.Equal(t, []Port{{Local: "5432", Remote: "5432", Host: "15432"}}, co.Ports)
+	assert.Equal(t, []NetworkAttachment{{Name: "network.local"}}, co.Networks)
+	assert.Contains(t, co.Environment, KV{Key: "POSTGRES_PASSWORD", Value: "secret"})
+	assert.Contains(t, co.Environment, KV{Key: "POSTGRES_USER", Value: "postgres"})
+	assert.Contains(t, co.DependsOn, "network.local")
+
+	r, err = c.FindResource("http_check.postgres")
+	require.NoError(t, err)
+	assert.Equal(t, "localhost:15432", r.(*HTTPCheck).TCP)
+	assert.Contains(t, r.Info().DependsOn, "container.postgres")
+}
+
+func TestServiceUsesCatalogDefaults(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, serviceValid)
+	defer cleanup()
+
+	r, err := c.FindResource("container.cache")
+	require.NoError(t, err)
+
+	co := r.(*Container)
+	assert.Equal(t, "redis:6", co.Image.Name)
+	assert.Equal(t, []Port{{Local: "6379", Remote: "6379", Host: "6379"}}, co.Ports)
+}
+
+func TestServiceNotInCatalogReturnsError(t *testing.T) {
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+
+	createNamedFile(t, dir, "*.hcl", `
+service "mongo" {
+	version = "4"
+}
+`)
+
+	err := ParseFolder(dir, &Config{}, nil)
+	assert.Error(t, err)
+}
+
+const serviceValid = `
+network "local" {
+	subnet = "10.6.0.0/16"
+}
+
+service "postgres" {
+	version = "13"
+	port    = "15432"
+	network = "network.local"
+
+	env = {
+		POSTGRES_PASSWORD = "secret"
+	}
+}
+
+service "cache" {
+	catalog = "redis"
+}
+`
@@ -10,7 +10,9 @@ type Sidecar struct {
 
 	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
 
-	Target string `hcl:"target" json:"target"`
+	// Target is the container to attach the sidecar to, sidecars defined in a
+	// container block always target the container
+	Target string `hcl:"target,optional" json:"target"`
 
 	Image       Image    `hcl:"image,block" json:"image"`                        // image to use for the container
 	Entrypoint  []string `hcl:"entrypoint,optional" json:"entrypoint,omitempty"` // entrypoint to use when starting the container
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// sopsCommand is the sops binary used to decrypt files
+var sopsCommand = "sops"
+
+// IsEncryptedFile returns true when the file is encrypted with SOPS,
+// encrypted files are named *.sops.hcl or *.enc.hcl
+func IsEncryptedFile(file string) bool {
+	name := filepath.Base(file)
+	return strings.HasSuffix(name, ".sops.hcl") || strings.HasSuffix(name, ".enc.hcl")
+}
+
+// readConfigFile reads a config file, encrypted files are decrypted
+func readConfigFile(file string) ([]byte, error) {
+	if IsEncryptedFile(file) {
+		return decryptFile(file)
+	}
+
+	return ioutil.ReadFile(file)
+}
+
+// decryptFile decrypts a SOPS encrypted file, the plain text is only held
+// in memory and is marked as sensitive so that it is redacted from any
+// output. Files must be encrypted using the binary format, the keys are
+// configured using the standard SOPS configuration for age, PGP or KMS.
+//
+//	sops --encrypt --input-type binary --output-type json secrets.hcl > secrets.sops.hcl
+func decryptFile(file string) ([]byte, error) {
+	if _, err := exec.LookPath(sopsCommand); err != nil {
+		return nil, xerrors.Errorf("Unable to decrypt %s, sops must be installed to parse encrypted files: %w", file, err)
+	}
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	cmd := exec.Command(sopsCommand, "--decrypt", "--input-type", "binary", "--output-type", "binary", file)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return nil, xerrors.Errorf("Unable to decrypt %s: %s: %w", file, strings.TrimSpace(stderr.String()), err)
+	}
+
+	MarkSensitive(stdout.String())
+
+	return stdout.Bytes(), nil
+}
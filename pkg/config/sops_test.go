@@ -0,0 +1,113 @@
+package config
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSops is a sops replacement which "decrypts" files by removing the
+// ENC: prefix from each line, the last argument is the file
+const fakeSops = `#!/bin/sh
+for f; do :; done
+if grep -q FAIL "$f"; then
+  echo "Failed to get the data key" >&2
+  exit 1
+fi
+sed 's/^ENC://' "$f"
+`
+
+func setupSops(t *testing.T) (string, func()) {
+	dir := createTempDirectory(t)
+
+	sops := filepath.Join(dir, "sops")
+	err := ioutil.WriteFile(sops, []byte(fakeSops), 0755)
+	require.NoError(t, err)
+
+	old := sopsCommand
+	sopsCommand = sops
+
+	return dir, func() {
+		sopsCommand = old
+		removeTestFiles(t, dir)
+	}
+}
+
+func TestIsEncryptedFile(t *testing.T) {
+	assert.True(t, IsEncryptedFile("/stack/secrets.sops.hcl"))
+	assert.True(t, IsEncryptedFile("secrets.enc.hcl"))
+	assert.False(t, IsEncryptedFile("/stack/sops.hcl"))
+	assert.False(t, IsEncryptedFile("/stack/secrets.hcl"))
+}
+
+func TestParseFolderDecryptsEncryptedFiles(t *testing.T) {
+	_, cleanup := setupSops(t)
+	defer cleanup()
+
+	dir, cleanupFiles := createTestFiles(t)
+	defer cleanupFiles()
+
+	createNamedFile(t, dir, "*.sops.hcl", encryptedNetwork)
+
+	c := New()
+	err := ParseFolder(dir, c, nil)
+	assert.NoError(t, err)
+
+	n, err := c.FindResource("network.secret")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.9.0.0/16", n.(*Network).Subnet)
+}
+
+func TestDecryptedFileIsRedacted(t *testing.T) {
+	_, cleanup := setupSops(t)
+	defer cleanup()
+
+	dir := createTempDirectory(t)
+	defer removeTestFiles(t, dir)
+
+	f := createNamedFile(t, dir, "*.sops.hcl", encryptedNetwork)
+
+	d, err := readConfigFile(f)
+	require.NoError(t, err)
+
+	assert.Equal(t, "decrypted: "+RedactedValue, Redact("decrypted: "+string(d)))
+}
+
+func TestParseHCLFileWithEncryptedFileReturnsDecryptError(t *testing.T) {
+	_, cleanup := setupSops(t)
+	defer cleanup()
+
+	dir := createTempDirectory(t)
+	defer removeTestFiles(t, dir)
+
+	f := createNamedFile(t, dir, "*.enc.hcl", "FAIL")
+
+	c := New()
+	err := ParseHCLFile(f, c, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Failed to get the data key")
+}
+
+func TestParseEncryptedFileWithoutSopsReturnsError(t *testing.T) {
+	old := sopsCommand
+	sopsCommand = "sops-does-not-exist"
+	defer func() { sopsCommand = old }()
+
+	dir := createTempDirectory(t)
+	defer removeTestFiles(t, dir)
+
+	f := createNamedFile(t, dir, "*.sops.hcl", encryptedNetwork)
+
+	c := New()
+	err := ParseHCLFile(f, c, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "sops must be installed")
+}
+
+const encryptedNetwork = `ENC:network "secret" {
+ENC:  subnet = "10.9.0.0/16"
+ENC:}
+`
@@ -0,0 +1,41 @@
+package config
+
+// TypeSQLExec is the resource string for a SQLExec resource
+const TypeSQLExec ResourceType = "sql_exec"
+
+// SQLExec runs SQL files or inline statements against a database running in
+// a container, the database client runs in a temporary container attached to
+// the same networks as the target
+type SQLExec struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	// Target is the container running the database e.g. container.postgres
+	Target string `hcl:"target" json:"target"`
+
+	// Driver is the type of database, postgres or mysql
+	Driver string `hcl:"driver" json:"driver"`
+
+	// Port the database listens on, defaults to the default port for the driver
+	Port string `hcl:"port,optional" json:"port,omitempty"`
+
+	Database string `hcl:"database,optional" json:"database,omitempty"`
+	Username string `hcl:"username,optional" json:"username,omitempty"`
+	Password string `hcl:"password,optional" json:"password,omitempty" sensitive:"true"`
+
+	// Files are SQL files which are run in order
+	Files []string `hcl:"files,optional" json:"files,omitempty"`
+
+	// SQL is an inline statement which is run after the files
+	SQL string `hcl:"sql,optional" json:"sql,omitempty"`
+
+	// Timeout is the maximum time to wait for the database to accept
+	// connections, defaults to 60s
+	Timeout string `hcl:"timeout,optional" json:"timeout,omitempty"`
+}
+
+// NewSQLExec creates a new SQLExec config resource
+func NewSQLExec(name string) *SQLExec {
+	return &SQLExec{ResourceInfo: ResourceInfo{Name: name, Type: TypeSQLExec, Status: PendingCreation}, Timeout: "60s"}
+}
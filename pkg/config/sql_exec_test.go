@@ -0,0 +1,70 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLExecCreatesCorrectly(t *testing.T) {
+	c, dir, cleanup := setupTestConfig(t, sqlExecValid)
+	defer cleanup()
+
+	r, err := c.FindResource("sql_exec.seed")
+	require.NoError(t, err)
+
+	se := r.(*SQLExec)
+	assert.Equal(t, "postgres", se.Driver)
+	assert.Equal(t, []string{filepath.Join(dir, "sql/schema.sql")}, se.Files)
+	assert.Equal(t, "60s", se.Timeout)
+	assert.Contains(t, se.DependsOn, "container.postgres")
+}
+
+func TestSQLExecWithInvalidDriverReturnsError(t *testing.T) {
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+
+	createNamedFile(t, dir, "*.hcl", `
+sql_exec "seed" {
+	target = "container.mongo"
+	driver = "mongo"
+	sql    = "db.users.find()"
+}
+`)
+
+	err := ParseFolder(dir, &Config{}, nil)
+	assert.Error(t, err)
+}
+
+func TestSQLExecWithoutSQLReturnsError(t *testing.T) {
+	dir, cleanup := createTestFiles(t)
+	defer cleanup()
+
+	createNamedFile(t, dir, "*.hcl", `
+sql_exec "seed" {
+	target = "container.postgres"
+	driver = "postgres"
+}
+`)
+
+	err := ParseFolder(dir, &Config{}, nil)
+	assert.Error(t, err)
+}
+
+const sqlExecValid = `
+container "postgres" {
+	image {
+		name = "postgres:14"
+	}
+}
+
+sql_exec "seed" {
+	target   = "container.postgres"
+	driver   = "postgres"
+	username = "postgres"
+	password = "password"
+	files    = ["./sql/schema.sql"]
+}
+`
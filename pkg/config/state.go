@@ -37,8 +37,15 @@ func (c *Config) ToJSON(path string) error {
 	}
 	defer f.Close()
 
-	ne := json.NewEncoder(f)
-	return ne.Encode(c)
+	d, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	// the state contains the real values so that it can be used to
+	// recreate resources, sensitive values are redacted when displayed
+	_, err = f.WriteString(string(d) + "\n")
+	return err
 }
 
 // FromJSON attempts to rehydrate the config from a JSON formatted statefile
@@ -51,7 +58,16 @@ func (c *Config) FromJSON(path string) error {
 	defer f.Close()
 
 	jd := json.NewDecoder(f)
-	return jd.Decode(c)
+	err = jd.Decode(c)
+	if err != nil {
+		return err
+	}
+
+	// values which were sensitive when the state was written are marked
+	// again so they are redacted when the state is displayed
+	markStateSensitive(c)
+
+	return nil
 }
 
 // UnmarshalJSON is a cusom Unmarshaler to deal with
@@ -73,6 +89,13 @@ func (c *Config) UnmarshalJSON(b []byte) error {
 		}
 	}
 
+	if objMap["outputs"] != nil {
+		err = json.Unmarshal(*objMap["outputs"], &c.Outputs)
+		if err != nil {
+			return err
+		}
+	}
+
 	var rawMessagesForResources []*json.RawMessage
 	err = json.Unmarshal(*objMap["resources"], &rawMessagesForResources)
 	if err != nil {
@@ -86,6 +109,8 @@ func (c *Config) UnmarshalJSON(b []byte) error {
 			return err
 		}
 
+		added := len(c.Resources)
+
 		t := ResourceType(mm["type"].(string))
 		switch t {
 		case TypeContainer:
@@ -103,7 +128,568 @@ func (c *Config) UnmarshalJSON(b []byte) error {
 					t.DependsOn = append(t.DependsOn, i.(string))
 				}
 			}
-			c.AddResource(&t)
+			c.addStateResource(&t, mm)
+
+		case TypeContainerBuild:
+			t := ContainerBuild{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
+
+		case TypeCertificateCA:
+			t := CertificateCA{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
+
+		case TypeCertificateLeaf:
+			t := CertificateLeaf{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
+
+		case TypeTemplate:
+			t := Template{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
+
+		case TypeCopy:
+			t := Copy{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
+
+		case TypeNullResource:
+			t := NullResource{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
+
+		case TypeRandomPassword:
+			t := RandomPassword{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
+
+		case TypeRandomID:
+			t := RandomID{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
+
+		case TypeLoadBalancer:
+			t := LoadBalancer{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
+
+		case TypeDNS:
+			t := DNS{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
+
+		case TypeNetworkRoute:
+			t := NetworkRoute{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
+
+		case TypeChaos:
+			t := Chaos{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
+
+		case TypeMockAPI:
+			t := MockAPI{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
+
+		case TypeLocalService:
+			t := LocalService{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
+
+		case TypeK8sClusterExternal:
+			t := K8sClusterExternal{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
+
+		case TypeAWS:
+			t := AWS{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
+
+		case TypeTrafficCapture:
+			t := TrafficCapture{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
+
+		case TypeMinIO:
+			t := MinIO{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
+
+		case TypeVault:
+			t := Vault{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
+
+		case TypeContainerRegistry:
+			t := ContainerRegistry{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
+
+		case TypeConsulConfig:
+			t := ConsulConfig{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
+
+		case TypeSQLExec:
+			t := SQLExec{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
+
+		case TypeHTTPCheck:
+			t := HTTPCheck{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
+
+		case TypeK8sWait:
+			t := K8sWait{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
+
+		case TypeGitOps:
+			t := GitOps{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
+
+		case TypeK8sNamespace:
+			t := K8sNamespace{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
+
+		case TypeLocalIngress:
+			t := LocalIngress{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
+
+		case TypeDockerVolume:
+			t := DockerVolume{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
+
+		case TypeK8sSecret:
+			t := K8sSecret{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
+
+		case TypeK8sConfigMap:
+			t := K8sConfigMap{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
+
+		case TypeKustomize:
+			t := Kustomize{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
+
+		case TypeHelmRepository:
+			t := HelmRepository{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
+
+		case TypeImageCache:
+			t := ImageCache{}
+			err := mapstructure.Decode(mm, &t)
+			if err != nil {
+				return err
+			}
+			t.Name = mm["name"].(string)
+			t.Type = ResourceType(mm["type"].(string))
+			t.Status = Status(mm["status"].(string))
+
+			if d, ok := mm["depends_on"].([]interface{}); ok {
+				for _, i := range d {
+					t.DependsOn = append(t.DependsOn, i.(string))
+				}
+			}
+			c.addStateResource(&t, mm)
 
 		case TypeContainerIngress:
 			t := ContainerIngress{}
@@ -120,7 +706,7 @@ func (c *Config) UnmarshalJSON(b []byte) error {
 					t.DependsOn = append(t.DependsOn, i.(string))
 				}
 			}
-			c.AddResource(&t)
+			c.addStateResource(&t, mm)
 
 		case TypeSidecar:
 			t := Sidecar{}
@@ -137,7 +723,7 @@ func (c *Config) UnmarshalJSON(b []byte) error {
 					t.DependsOn = append(t.DependsOn, i.(string))
 				}
 			}
-			c.AddResource(&t)
+			c.addStateResource(&t, mm)
 
 		case TypeDocs:
 			t := Docs{}
@@ -154,7 +740,7 @@ func (c *Config) UnmarshalJSON(b []byte) error {
 					t.DependsOn = append(t.DependsOn, i.(string))
 				}
 			}
-			c.AddResource(&t)
+			c.addStateResource(&t, mm)
 
 		case TypeExecRemote:
 			t := ExecRemote{}
@@ -171,7 +757,7 @@ func (c *Config) UnmarshalJSON(b []byte) error {
 					t.DependsOn = append(t.DependsOn, i.(string))
 				}
 			}
-			c.AddResource(&t)
+			c.addStateResource(&t, mm)
 
 		case TypeExecLocal:
 			t := ExecLocal{}
@@ -188,7 +774,7 @@ func (c *Config) UnmarshalJSON(b []byte) error {
 					t.DependsOn = append(t.DependsOn, i.(string))
 				}
 			}
-			c.AddResource(&t)
+			c.addStateResource(&t, mm)
 
 		case TypeHelm:
 			t := Helm{}
@@ -205,7 +791,7 @@ func (c *Config) UnmarshalJSON(b []byte) error {
 					t.DependsOn = append(t.DependsOn, i.(string))
 				}
 			}
-			c.AddResource(&t)
+			c.addStateResource(&t, mm)
 
 		case TypeIngress:
 			t := Ingress{}
@@ -222,7 +808,7 @@ func (c *Config) UnmarshalJSON(b []byte) error {
 					t.DependsOn = append(t.DependsOn, i.(string))
 				}
 			}
-			c.AddResource(&t)
+			c.addStateResource(&t, mm)
 
 		case TypeK8sCluster:
 			t := K8sCluster{}
@@ -239,7 +825,7 @@ func (c *Config) UnmarshalJSON(b []byte) error {
 					t.DependsOn = append(t.DependsOn, i.(string))
 				}
 			}
-			c.AddResource(&t)
+			c.addStateResource(&t, mm)
 
 		case TypeK8sConfig:
 			t := K8sConfig{}
@@ -256,7 +842,7 @@ func (c *Config) UnmarshalJSON(b []byte) error {
 					t.DependsOn = append(t.DependsOn, i.(string))
 				}
 			}
-			c.AddResource(&t)
+			c.addStateResource(&t, mm)
 
 		case TypeK8sIngress:
 			t := K8sIngress{}
@@ -273,7 +859,7 @@ func (c *Config) UnmarshalJSON(b []byte) error {
 					t.DependsOn = append(t.DependsOn, i.(string))
 				}
 			}
-			c.AddResource(&t)
+			c.addStateResource(&t, mm)
 
 		case TypeNetwork:
 			t := Network{}
@@ -290,7 +876,7 @@ func (c *Config) UnmarshalJSON(b []byte) error {
 					t.DependsOn = append(t.DependsOn, i.(string))
 				}
 			}
-			c.AddResource(&t)
+			c.addStateResource(&t, mm)
 
 		case TypeNomadCluster:
 			t := NomadCluster{}
@@ -307,7 +893,7 @@ func (c *Config) UnmarshalJSON(b []byte) error {
 					t.DependsOn = append(t.DependsOn, i.(string))
 				}
 			}
-			c.AddResource(&t)
+			c.addStateResource(&t, mm)
 
 		case TypeNomadJob:
 			t := NomadJob{}
@@ -324,7 +910,7 @@ func (c *Config) UnmarshalJSON(b []byte) error {
 					t.DependsOn = append(t.DependsOn, i.(string))
 				}
 			}
-			c.AddResource(&t)
+			c.addStateResource(&t, mm)
 
 		case TypeNomadIngress:
 			t := NomadIngress{}
@@ -341,20 +927,55 @@ func (c *Config) UnmarshalJSON(b []byte) error {
 					t.DependsOn = append(t.DependsOn, i.(string))
 				}
 			}
-			c.AddResource(&t)
+			c.addStateResource(&t, mm)
+
+		}
+
+		// set the retry policy for resources which override the defaults
+		if len(c.Resources) > added {
+			r := c.Resources[added]
+
+			if n, ok := mm["retries"].(float64); ok {
+				retries := int(n)
+				r.Info().Retries = &retries
+			}
+
+			if i, ok := mm["retry_interval"].(string); ok {
+				r.Info().RetryInterval = i
+			}
+
+			// hooks are decoded using the json tags
+			if h, ok := mm["on_create"]; ok {
+				d, _ := json.Marshal(h)
+				json.Unmarshal(d, &r.Info().OnCreate)
+			}
 
+			if h, ok := mm["on_destroy"]; ok {
+				d, _ := json.Marshal(h)
+				json.Unmarshal(d, &r.Info().OnDestroy)
+			}
 		}
 	}
 
 	return nil
 }
 
+// addStateResource adds a resource decoded from the state, the module is
+// part of the id of the resource so it is set before the resource is added
+func (c *Config) addStateResource(r Resource, mm map[string]interface{}) {
+	if m, ok := mm["module"].(string); ok {
+		r.Info().Module = m
+	}
+
+	c.AddResource(r)
+}
+
 // Merge config merges two config items
 func (c *Config) Merge(c2 *Config) {
 	for _, cc2 := range c2.Resources {
 		found := false
 		for i, cc := range c.Resources {
-			if cc2.Info().Name == cc.Info().Name && cc2.Info().Type == cc.Info().Type {
+			if ResourceID(cc2) == ResourceID(cc) {
 				// Exists in the collection already
 				// Replace the resource with the new one and set pending state only if it is not marked for modification.
 				// If marked for modification then the user has specifically tained the resource
@@ -362,6 +983,12 @@ func (c *Config) Merge(c2 *Config) {
 				// do not update the status for resources we need to re-create or have not yet been created
 				if status == Applied {
 					status = PendingUpdate
+
+					// resources can request to be created again e.g. when the
+					// triggers for a null_resource change
+					if cc2.Info().Status == PendingModification {
+						status = PendingModification
+					}
 				}
 
 				c.Resources[i] = cc2
@@ -384,4 +1011,9 @@ func (c *Config) Merge(c2 *Config) {
 	if c2.Blueprint != nil {
 		c.Blueprint = c2.Blueprint
 	}
+
+	// outputs are replaced when new config has been loaded
+	if len(c2.Resources) > 0 || len(c2.Outputs) > 0 {
+		c.Outputs = c2.Outputs
+	}
 }
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/hcl2/hcl"
+)
+
+// parseWarnings are the warnings for unknown attributes and blocks found
+// since the last call to ResetParseWarnings
+var parseWarnings = hcl.Diagnostics{}
+var parseWarningsLock = sync.Mutex{}
+
+// unknownSummaries are the summaries of the diagnostics returned by the HCL
+// and JSON parsers for attributes or blocks which are not in the schema
+var unknownSummaries = []string{
+	"Unsupported argument",
+	"Unsupported block type",
+	"Extraneous JSON object property",
+}
+
+// ParseWarnings returns the warnings for any unknown attributes or blocks
+// found when parsing, the warnings are returned as a ParseError so that they
+// can be written with the source using WriteDiagnostics
+func ParseWarnings() ParseError {
+	parseWarningsLock.Lock()
+	warnings := append(hcl.Diagnostics{}, parseWarnings...)
+	parseWarningsLock.Unlock()
+
+	return newParseError(warnings)
+}
+
+// ResetParseWarnings removes any warnings from a previous parse
+func ResetParseWarnings() {
+	parseWarningsLock.Lock()
+	defer parseWarningsLock.Unlock()
+
+	parseWarnings = hcl.Diagnostics{}
+}
+
+// checkUnknown adds the block type and name to the diagnostics for any
+// unknown attributes or blocks, when the Permissive option is set these
+// diagnostics are removed and added to the parse warnings and the rest of
+// the resource is decoded
+func (p *parser) checkUnknown(b *hcl.Block, diag hcl.Diagnostics) hcl.Diagnostics {
+	out := hcl.Diagnostics{}
+
+	for _, d := range diag {
+		if !isUnknownDiagnostic(d) {
+			out = append(out, d)
+			continue
+		}
+
+		d.Detail = fmt.Sprintf("%s The %s is defined in %s %q.", d.Detail, unknownKind(d), b.Type, strings.Join(b.Labels, "."))
+
+		if !p.options.Permissive {
+			out = append(out, d)
+			continue
+		}
+
+		d.Severity = hcl.DiagWarning
+		addParseWarning(d)
+	}
+
+	return out
+}
+
+func addParseWarning(d *hcl.Diagnostic) {
+	parseWarningsLock.Lock()
+	defer parseWarningsLock.Unlock()
+
+	parseWarnings = append(parseWarnings, d)
+}
+
+func isUnknownDiagnostic(d *hcl.Diagnostic) bool {
+	for _, s := range unknownSummaries {
+		if d.Summary == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func unknownKind(d *hcl.Diagnostic) string {
+	if d.Summary == "Unsupported block type" {
+		return "block"
+	}
+
+	return "argument"
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
+)
+
+func resetParseWarnings(t *testing.T) {
+	ResetParseWarnings()
+	t.Cleanup(ResetParseWarnings)
+}
+
+func setupPermissiveConfig(t *testing.T, contents ...string) (*Config, error) {
+	dir, cleanup := createTestFiles(t, contents...)
+	defer cleanup()
+
+	c := New()
+	err := ParseFolderWithOptions(dir, c, nil, ParseOptions{Permissive: true})
+
+	return c, err
+}
+
+func TestStrictModeUnknownAttributeReturnsErrorWithSuggestion(t *testing.T) {
+	resetParseWarnings(t)
+
+	_, err := setupVariableConfig(t, nil, unknownAttributes)
+	assert.Error(t, err)
+
+	pe := ParseError{}
+	assert.True(t, xerrors.As(err, &pe))
+	assert.Len(t, pe.Diagnostics, 2)
+
+	assert.Contains(t, pe.Diagnostics[0].Detail, `Did you mean "entrypoint"?`)
+	assert.Contains(t, pe.Diagnostics[0].Detail, `The argument is defined in container "consul".`)
+	assert.Contains(t, pe.Diagnostics[1].Detail, `The block is defined in container "consul".`)
+}
+
+func TestPermissiveModeUnknownAttributeReturnsWarnings(t *testing.T) {
+	resetParseWarnings(t)
+
+	c, err := setupPermissiveConfig(t, unknownAttributes)
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, "consul:1.7.2", co.(*Container).Image.Name)
+
+	w := ParseWarnings()
+	assert.Len(t, w.Diagnostics, 2)
+	assert.Equal(t, hcl.DiagWarning, w.Diagnostics[0].Severity)
+	assert.Contains(t, w.Diagnostics[0].Detail, `Did you mean "entrypoint"?`)
+}
+
+func TestPermissiveModeReturnsOtherErrors(t *testing.T) {
+	resetParseWarnings(t)
+
+	_, err := setupPermissiveConfig(t, unknownAttributesInvalid)
+	assert.Error(t, err)
+}
+
+func TestPermissiveModeCollectsWarningsConcurrently(t *testing.T) {
+	resetParseWarnings(t)
+
+	p := &parser{options: ParseOptions{Permissive: true}}
+	b := &hcl.Block{Type: "container", Labels: []string{"consul"}}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.checkUnknown(b, hcl.Diagnostics{&hcl.Diagnostic{Summary: "Unsupported argument"}})
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Len(t, ParseWarnings().Diagnostics, 50)
+}
+
+const unknownAttributes = `
+container "consul" {
+	image {
+		name = "consul:1.7.2"
+	}
+
+	entrypont = ["consul"]
+
+	voluem {
+		source = "."
+		destination = "/files"
+	}
+}
+`
+
+const unknownAttributesInvalid = `
+container "consul" {
+	image {
+		name = "consul:1.7.2"
+	}
+
+	entrypont = ["consul"]
+	command = "not a list"
+}
+`
@@ -0,0 +1,34 @@
+package config
+
+// TypeTemplate is the resource string for a Template resource
+const TypeTemplate ResourceType = "template"
+
+// Template renders a file to disk when the resource is created, templates
+// are rendered after the resources they depend on so they can contain files
+// generated by those resources
+type Template struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	// Source is the path to the template or the template defined using a
+	// heredoc, variables are referenced using ${name}
+	Source string `hcl:"source" json:"source"`
+
+	// Destination is the file the rendered template is written to
+	Destination string `hcl:"destination" json:"destination"`
+
+	// Vars are the variables which can be referenced by the template
+	Vars map[string]string `hcl:"vars,optional" json:"vars,omitempty"`
+}
+
+// NewTemplate creates a new Template config resource
+func NewTemplate(name string) *Template {
+	return &Template{ResourceInfo: ResourceInfo{Name: name, Type: TypeTemplate, Status: PendingCreation}}
+}
+
+// IsInline returns true when the source contains the template rather than
+// the path to a template file
+func (t *Template) IsInline() bool {
+	return isInlineScript(t.Source)
+}
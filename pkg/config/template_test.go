@@ -0,0 +1,51 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateCreatesCorrectly(t *testing.T) {
+	c, dir, cleanup := setupTestConfig(t, templateValid)
+	defer cleanup()
+
+	f, err := c.FindResource("template.file")
+	assert.NoError(t, err)
+
+	assert.Equal(t, filepath.Join(dir, "consul.tmpl"), f.(*Template).Source)
+	assert.Equal(t, filepath.Join(dir, "out", "consul.hcl"), f.(*Template).Destination)
+	assert.Equal(t, "dc1", f.(*Template).Vars["dc"])
+	assert.Contains(t, f.Info().DependsOn, "network.onprem")
+
+	i, err := c.FindResource("template.inline")
+	assert.NoError(t, err)
+
+	assert.True(t, i.(*Template).IsInline())
+	assert.Equal(t, "datacenter = \"${dc}\"\n", i.(*Template).Source)
+}
+
+const templateValid = `
+network "onprem" {
+	subnet = "10.6.0.0/16"
+}
+
+template "file" {
+	source      = "./consul.tmpl"
+	destination = "./out/consul.hcl"
+
+	vars = {
+		dc     = "dc1"
+		subnet = network.onprem.subnet
+	}
+}
+
+template "inline" {
+	source = <<EOF
+datacenter = "$${dc}"
+EOF
+
+	destination = "./out/inline.hcl"
+}
+`
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/shipyard-run/shipyard/pkg/utils"
+)
+
+// TypeTrafficCapture is the resource string for a TrafficCapture resource
+const TypeTrafficCapture ResourceType = "traffic_capture"
+
+// TrafficCapture runs mitmproxy as a HTTP proxy which records the requests
+// sent by resources on the network. Resources send traffic through the proxy
+// by setting the variables in env e.g. traffic_capture.debug.env.HTTP_PROXY
+type TrafficCapture struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	Networks []NetworkAttachment `hcl:"network,block" json:"networks,omitempty"` // networks to attach the proxy to
+
+	// Port is the port on the local machine the proxy is exposed on
+	Port int `hcl:"port,optional" json:"port"`
+
+	// WebPort is the port on the local machine for the web interface used to
+	// inspect the captured traffic
+	WebPort int `hcl:"web_port,optional" json:"web_port" mapstructure:"web_port"`
+
+	// Upstream is a proxy which traffic is forwarded to rather than sending
+	// it directly to the destination e.g. a corporate proxy
+	Upstream string `hcl:"upstream,optional" json:"upstream,omitempty"`
+
+	// Output is the folder the captures are written to, defaults to the
+	// captures folder in the data directory for the blueprint
+	Output string `hcl:"output,optional" json:"output,omitempty"`
+
+	// Address is the web interface on the local machine, ProxyAddress is the
+	// address of the proxy used by resources attached to the same network
+	Address      string `json:"address"`
+	ProxyAddress string `json:"proxy_address" mapstructure:"proxy_address"`
+
+	// CACert is the path of the certificate authority used to intercept
+	// HTTPS traffic, clients must trust the certificate
+	CACert string `json:"ca_cert" mapstructure:"ca_cert"`
+
+	// Env contains the proxy environment variables for resources attached to
+	// the same network
+	Env map[string]string `json:"env"`
+}
+
+// NewTrafficCapture creates a new TrafficCapture config resource
+func NewTrafficCapture(name string) *TrafficCapture {
+	return &TrafficCapture{
+		ResourceInfo: ResourceInfo{Name: name, Type: TypeTrafficCapture, Status: PendingCreation},
+		Port:         8080,
+		WebPort:      8081,
+	}
+}
+
+// setAddress sets the addresses, certificate path and environment variables
+// for the proxy
+func (t *TrafficCapture) setAddress() {
+	t.Address = fmt.Sprintf("http://localhost:%d", t.WebPort)
+	t.ProxyAddress = fmt.Sprintf("http://%s:8080", utils.FQDN(t.Name, string(t.Type)))
+	t.CACert = filepath.Join(t.Output, "certs", "mitmproxy-ca-cert.pem")
+
+	t.Env = map[string]string{
+		"HTTP_PROXY":  t.ProxyAddress,
+		"HTTPS_PROXY": t.ProxyAddress,
+		"http_proxy":  t.ProxyAddress,
+		"https_proxy": t.ProxyAddress,
+	}
+}
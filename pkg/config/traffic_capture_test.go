@@ -0,0 +1,64 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrafficCaptureCreatesCorrectly(t *testing.T) {
+	defer setupTemplateHome(t)()
+
+	c, dir, cleanup := setupTestConfig(t, trafficCaptureValid)
+	defer cleanup()
+
+	r, err := c.FindResource("traffic_capture.debug")
+	require.NoError(t, err)
+
+	tc := r.(*TrafficCapture)
+	assert.Equal(t, filepath.Join(blueprintDataDir(dir), "captures"), tc.Output)
+	assert.Equal(t, "http://localhost:9081", tc.Address)
+	assert.Equal(t, "http://debug.traffic_capture.shipyard.run:8080", tc.ProxyAddress)
+	assert.Equal(t, filepath.Join(tc.Output, "certs", "mitmproxy-ca-cert.pem"), tc.CACert)
+	assert.Contains(t, tc.DependsOn, "network.local")
+}
+
+func TestTrafficCaptureEnvCanBeReferenced(t *testing.T) {
+	defer setupTemplateHome(t)()
+
+	c, _, cleanup := setupTestConfig(t, trafficCaptureValid)
+	defer cleanup()
+
+	r, err := c.FindResource("container.app")
+	require.NoError(t, err)
+
+	assert.Contains(t, r.(*Container).Environment, KV{Key: "HTTPS_PROXY", Value: "http://debug.traffic_capture.shipyard.run:8080"})
+	assert.Contains(t, r.Info().DependsOn, "traffic_capture.debug")
+}
+
+const trafficCaptureValid = `
+network "local" {
+	subnet = "10.6.0.0/16"
+}
+
+traffic_capture "debug" {
+	web_port = 9081
+
+	network {
+		name = "network.local"
+	}
+}
+
+container "app" {
+	image {
+		name = "app:latest"
+	}
+
+	env {
+		key   = "HTTPS_PROXY"
+		value = traffic_capture.debug.env.HTTPS_PROXY
+	}
+}
+`
@@ -0,0 +1,181 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// validator is implemented by resources which can check their own config
+type validator interface {
+	Validate() []error
+}
+
+// Validate checks the config for semantic errors which can not be detected
+// when parsing, such as invalid subnets, references to resources which do
+// not exist and host ports which are used by more than one resource.
+// ParseReferences must be called before Validate so that the dependencies
+// for each resource have been set.
+func (c *Config) Validate() []error {
+	errs := []error{}
+
+	if c.Blueprint != nil {
+		errs = append(errs, c.Blueprint.Validate()...)
+	}
+
+	for _, r := range c.Resources {
+		id := ResourceID(r)
+
+		if v, ok := r.(validator); ok {
+			for _, err := range v.Validate() {
+				errs = append(errs, fmt.Errorf("%s: %s", id, err))
+			}
+		}
+
+		// depends_on, cluster, network and target are all
+		// added to the dependencies
+		checked := map[string]bool{}
+		for _, d := range r.Info().DependsOn {
+			if checked[d] {
+				continue
+			}
+			checked[d] = true
+
+			if !validResourceID(d) {
+				errs = append(errs, fmt.Errorf("%s: invalid reference %s, references must be in the form type.name or module.name.type.name", id, d))
+				continue
+			}
+
+			if _, err := c.FindDependency(r, d); err != nil {
+				errs = append(errs, fmt.Errorf("%s: references %s which does not exist", id, d))
+			}
+		}
+	}
+
+	errs = append(errs, c.validateHostPorts()...)
+	errs = append(errs, c.validateDockerHosts()...)
+
+	return errs
+}
+
+// validateHostPorts returns an error for each host port used by more than one resource
+func (c *Config) validateHostPorts() []error {
+	errs := []error{}
+	used := map[string]string{}
+
+	for _, r := range c.Resources {
+		for _, p := range resourcePorts(r) {
+			// ports without a host port are bound to a random port
+			host := p.Host
+			if host == "" {
+				continue
+			}
+
+			protocol := p.Protocol
+			if protocol == "" {
+				protocol = "tcp"
+			}
+
+			key := fmt.Sprintf("%s/%s", host, protocol)
+			if first, ok := used[key]; ok {
+				errs = append(errs, fmt.Errorf("%s: host port %s is already used by %s", ResourceID(r), key, first))
+				continue
+			}
+
+			used[key] = ResourceID(r)
+		}
+	}
+
+	return errs
+}
+
+// validateDockerHosts returns an error for each resource attached to a
+// network on a different Docker engine, networks can not span engines
+func (c *Config) validateDockerHosts() []error {
+	errs := []error{}
+
+	for _, r := range c.Resources {
+		host, networks := resourceDockerHost(r)
+
+		for _, na := range networks {
+			n, ok := c.lookupNetwork(r, na.Name)
+			if !ok || n.DockerHost == host {
+				continue
+			}
+
+			errs = append(errs, fmt.Errorf("%s: network %s is created on the %s Docker engine, resources attached to a network must use the same docker_host", ResourceID(r), na.Name, dockerHostName(n.DockerHost)))
+		}
+	}
+
+	return errs
+}
+
+func (c *Config) lookupNetwork(r Resource, name string) (*Network, bool) {
+	r, err := c.FindDependency(r, name)
+	if err != nil {
+		return nil, false
+	}
+
+	n, ok := r.(*Network)
+	return n, ok
+}
+
+// validateDockerHost checks the scheme of a docker_host address
+func validateDockerHost(host string) error {
+	if host == "" {
+		return nil
+	}
+
+	u, err := url.Parse(host)
+	if err != nil {
+		return fmt.Errorf("invalid docker_host %s: %s", host, err)
+	}
+
+	switch u.Scheme {
+	case "unix", "npipe", "tcp", "ssh":
+		return nil
+	}
+
+	return fmt.Errorf("invalid docker_host %s, the address must start with unix://, npipe://, tcp:// or ssh://", host)
+}
+
+func dockerHostName(host string) string {
+	if host == "" {
+		return "local"
+	}
+
+	return host
+}
+
+// resourceDockerHost returns the Docker engine and the networks for
+// resources which can be created on a remote engine
+func resourceDockerHost(r Resource) (string, []NetworkAttachment) {
+	switch v := r.(type) {
+	case *Container:
+		return v.DockerHost, v.Networks
+	case *K8sCluster:
+		return v.DockerHost, v.Networks
+	case *NomadCluster:
+		return v.DockerHost, v.Networks
+	}
+
+	return "", nil
+}
+
+func resourcePorts(r Resource) []Port {
+	switch v := r.(type) {
+	case *Container:
+		return v.Ports
+	case *Ingress:
+		return v.Ports
+	case *ContainerIngress:
+		return v.Ports
+	case *K8sIngress:
+		return v.Ports
+	case *NomadIngress:
+		return v.Ports
+	case *LoadBalancer:
+		return v.Ports
+	}
+
+	return nil
+}
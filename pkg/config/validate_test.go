@@ -0,0 +1,172 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupValidateConfig(t *testing.T, contents ...string) *Config {
+	c, err := setupVariableConfig(t, nil, contents...)
+	assert.NoError(t, err)
+
+	err = ParseReferences(c)
+	assert.NoError(t, err)
+
+	return c
+}
+
+func TestValidateWithValidConfigReturnsNoErrors(t *testing.T) {
+	c := setupValidateConfig(t, validateNetwork, validateContainer)
+
+	errs := c.Validate()
+	assert.Len(t, errs, 0)
+}
+
+func TestValidateWithInvalidSubnetReturnsError(t *testing.T) {
+	c := setupValidateConfig(t, `
+network "local" {
+	subnet = "10.5.0.0"
+}
+`)
+
+	errs := c.Validate()
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "network.local: invalid subnet")
+}
+
+func TestValidateWithMissingReferenceReturnsError(t *testing.T) {
+	c := setupValidateConfig(t, validateContainer)
+
+	errs := c.Validate()
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "container.consul: references network.local which does not exist")
+}
+
+func TestValidateWithMissingClusterReturnsError(t *testing.T) {
+	c := setupValidateConfig(t, `
+helm "consul" {
+	cluster = "k8s_cluster.k3s"
+	chart = "./helm/consul"
+}
+`)
+
+	errs := c.Validate()
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "helm.consul: references k8s_cluster.k3s which does not exist")
+}
+
+func TestValidateWithMissingImageReturnsError(t *testing.T) {
+	c := setupValidateConfig(t, `
+container "consul" {
+	image {
+		name = ""
+	}
+}
+`)
+
+	errs := c.Validate()
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "container.consul: image name must be specified")
+}
+
+func TestValidateWithDuplicateHostPortReturnsError(t *testing.T) {
+	c := setupValidateConfig(t, validateNetwork, validateContainer, `
+ingress "consul" {
+	target = "container.consul"
+
+	port {
+		local = "8500"
+		remote = "8500"
+		host = "18500"
+	}
+}
+`)
+
+	errs := c.Validate()
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "host port 18500/tcp is already used by")
+}
+
+func TestValidateK8sIngressRequiresSingleTarget(t *testing.T) {
+	i := NewK8sIngress("test")
+	assert.Len(t, i.Validate(), 1)
+
+	i.Service = "consul"
+	assert.Len(t, i.Validate(), 0)
+
+	i.Pod = "consul-0"
+	assert.Len(t, i.Validate(), 1)
+}
+
+func TestValidateIngressProtocol(t *testing.T) {
+	i := NewContainerIngress("test")
+	assert.Len(t, i.Validate(), 0)
+
+	i.Protocol = "tcp"
+	assert.Len(t, i.Validate(), 0)
+
+	i.Protocol = "udp"
+	assert.Len(t, i.Validate(), 1)
+
+	k := NewK8sIngress("test")
+	k.Service = "postgres"
+	k.Protocol = "grpc"
+	assert.Len(t, k.Validate(), 1)
+}
+
+func TestValidateDockerHostScheme(t *testing.T) {
+	c := setupValidateConfig(t, `
+network "local" {
+	subnet = "10.5.0.0/16"
+	docker_host = "http://10.5.0.2:2375"
+}
+`)
+
+	errs := c.Validate()
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "network.local: invalid docker_host http://10.5.0.2:2375")
+}
+
+func TestValidateDockerHostMustMatchNetwork(t *testing.T) {
+	c := setupValidateConfig(t, `
+network "local" {
+	subnet = "10.5.0.0/16"
+	docker_host = "ssh://nic@build.example.com"
+}
+`, validateContainer)
+
+	errs := c.Validate()
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "container.consul: network network.local is created on the ssh://nic@build.example.com Docker engine")
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+
+	co.(*Container).DockerHost = "ssh://nic@build.example.com"
+	assert.Len(t, c.Validate(), 0)
+}
+
+const validateNetwork = `
+network "local" {
+	subnet = "10.5.0.0/16"
+}
+`
+
+const validateContainer = `
+container "consul" {
+	image {
+		name = "consul:1.7.2"
+	}
+
+	network {
+		name = "network.local"
+	}
+
+	port {
+		local = "8500"
+		remote = "8500"
+		host = "18500"
+	}
+}
+`
@@ -0,0 +1,263 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/hashicorp/hcl2/ext/typeexpr"
+	"github.com/hashicorp/hcl2/gohcl"
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"golang.org/x/xerrors"
+)
+
+// TypeVariable is the block type for a Variable
+const TypeVariable ResourceType = "variable"
+
+// EnvVariablePrefix is the prefix for environment variables which set the
+// value of a variable e.g. SY_VAR_consul_version sets var.consul_version
+const EnvVariablePrefix = "SY_VAR_"
+
+// Variable is an input to a blueprint, variables are referenced in
+// expressions using var.name
+//
+//	variable "consul_version" {
+//	  type    = string
+//	  default = "1.7.2"
+//	}
+type Variable struct {
+	Name        string
+	Description string
+	// Type is the type constraint for the variable, when not set
+	// the variable accepts any type
+	Type cty.Type
+	// Default is the value used when the variable is not set from the CLI
+	Default cty.Value
+	// Sensitive variables are redacted from logs and output
+	Sensitive bool
+}
+
+// NewVariable creates a new Variable which accepts any type
+func NewVariable(name string) *Variable {
+	return &Variable{Name: name, Type: cty.DynamicPseudoType, Default: cty.NilVal}
+}
+
+// Value returns the value of the variable converted to the variable type,
+// if override is not nil it is used in place of the default value
+func (v *Variable) Value(override *string) (cty.Value, error) {
+	if override != nil {
+		return v.parseOverride(*override)
+	}
+
+	if v.Default == cty.NilVal {
+		return cty.NilVal, fmt.Errorf("Variable %s does not have a default value, set the value using --var %s=value", v.Name, v.Name)
+	}
+
+	val, err := convert.Convert(v.Default, v.Type)
+	if err != nil {
+		return cty.NilVal, xerrors.Errorf("Invalid default value for variable %s: %w", v.Name, err)
+	}
+
+	return val, nil
+}
+
+// parseOverride converts a value set from the CLI to the variable type,
+// primitive types are converted from the raw string other types are
+// parsed as HCL expressions e.g. ["a", "b"]
+func (v *Variable) parseOverride(s string) (cty.Value, error) {
+	var val cty.Value
+
+	if v.Type.IsPrimitiveType() || v.Type == cty.DynamicPseudoType {
+		val = cty.StringVal(s)
+	} else {
+		expr, diag := hclsyntax.ParseExpression([]byte(s), "--var "+v.Name, hcl.Pos{Line: 1, Column: 1})
+		if diag.HasErrors() {
+			return cty.NilVal, fmt.Errorf("Unable to parse value for variable %s: %s", v.Name, diag.Error())
+		}
+
+		val, diag = expr.Value(nil)
+		if diag.HasErrors() {
+			return cty.NilVal, fmt.Errorf("Unable to parse value for variable %s: %s", v.Name, diag.Error())
+		}
+	}
+
+	val, err := convert.Convert(val, v.Type)
+	if err != nil {
+		return cty.NilVal, xerrors.Errorf("Invalid value for variable %s: %w", v.Name, err)
+	}
+
+	return val, nil
+}
+
+// ParseVariablesFile reads a variables file containing name = value
+// assignments, the values are returned in the same form as values set
+// using --var, strings are returned as the raw string other types
+// are returned as the HCL expression e.g. ["a", "b"]
+//
+//	consul_version = "1.8.0"
+//	replicas       = 3
+func ParseVariablesFile(file string) (map[string]string, error) {
+	d, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, xerrors.Errorf("Unable to read variables file %s: %w", file, err)
+	}
+
+	f, diag := hclsyntax.ParseConfig(d, file, hcl.Pos{Line: 1, Column: 1})
+	if diag.HasErrors() {
+		return nil, newParseError(diag)
+	}
+
+	attrs, diag := f.Body.JustAttributes()
+	if diag.HasErrors() {
+		return nil, newParseError(diag)
+	}
+
+	out := map[string]string{}
+	for n, a := range attrs {
+		// values in a variables file must be constant
+		val, diag := a.Expr.Value(nil)
+		if diag.HasErrors() {
+			return nil, fmt.Errorf("Unable to parse value for variable %s in file %s: %s", n, file, diag.Error())
+		}
+
+		if val.Type() == cty.String && !val.IsNull() {
+			out[n] = val.AsString()
+			continue
+		}
+
+		out[n] = string(a.Expr.Range().SliceBytes(d))
+	}
+
+	return out, nil
+}
+
+// parseVariablesFiles reads the given variables files, values in later
+// files replace values in earlier files
+func parseVariablesFiles(files []string) (map[string]string, error) {
+	out := map[string]string{}
+
+	for _, f := range files {
+		vars, err := ParseVariablesFile(f)
+		if err != nil {
+			return nil, err
+		}
+
+		for k, v := range vars {
+			out[k] = v
+		}
+	}
+
+	return out, nil
+}
+
+// decodeVariable decodes a variable block, variables can not be decoded with
+// gohcl as the type attribute is a type expression not a value
+func (p *parser) decodeVariable(b *hcl.Block) (*Variable, error) {
+	v := NewVariable(b.Labels[0])
+
+	attrs, diag := b.Body.JustAttributes()
+	if diag.HasErrors() {
+		return nil, fmt.Errorf("Unable to decode variable %s: %s", v.Name, diag.Error())
+	}
+
+	for n, a := range attrs {
+		switch n {
+		case "description":
+			diag := gohcl.DecodeExpression(a.Expr, p.ctx, &v.Description)
+			if diag.HasErrors() {
+				return nil, fmt.Errorf("Invalid description for variable %s: %s", v.Name, diag.Error())
+			}
+		case "type":
+			t, diag := typeexpr.TypeConstraint(a.Expr)
+			if diag.HasErrors() {
+				return nil, fmt.Errorf("Invalid type for variable %s: %s", v.Name, diag.Error())
+			}
+
+			v.Type = t
+		case "sensitive":
+			diag := gohcl.DecodeExpression(a.Expr, p.ctx, &v.Sensitive)
+			if diag.HasErrors() {
+				return nil, fmt.Errorf("Invalid sensitive for variable %s: %s", v.Name, diag.Error())
+			}
+		case "default":
+			val, diag := a.Expr.Value(p.ctx)
+			if diag.HasErrors() {
+				return nil, fmt.Errorf("Invalid default for variable %s: %s", v.Name, diag.Error())
+			}
+
+			v.Default = val
+		default:
+			return nil, fmt.Errorf("Unsupported argument %s for variable %s", n, v.Name)
+		}
+	}
+
+	return v, nil
+}
+
+// setupContext builds the evaluation context used to decode the given blocks,
+// variables defined in any of the blocks are resolved using the overrides,
+// SY_VAR_ environment variables or their default values and added to the
+// context as var.name, locals are then evaluated and added to the context as
+// local.name, finally data sources are read and added as data.type.name
+func (p *parser) setupContext(blocks []fileBlock, overrides map[string]string) error {
+	p.ctx = buildContext(p.folder)
+
+	vars := map[string]*Variable{}
+
+	for _, fb := range blocks {
+		b := fb.block
+		if b.Type != string(TypeVariable) {
+			continue
+		}
+
+		v, err := p.decodeVariable(b)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := vars[v.Name]; ok {
+			return fmt.Errorf("Variable %s is defined more than once, duplicate found in file %s", v.Name, fb.file)
+		}
+
+		vars[v.Name] = v
+	}
+
+	for k := range overrides {
+		if _, ok := vars[k]; !ok {
+			return fmt.Errorf("Variable %s is set using --var but is not defined in the blueprint", k)
+		}
+	}
+
+	values := map[string]cty.Value{}
+	for n, v := range vars {
+		var override *string
+		if o, ok := overrides[n]; ok {
+			override = &o
+		} else if e, ok := os.LookupEnv(EnvVariablePrefix + n); ok {
+			override = &e
+		}
+
+		val, err := v.Value(override)
+		if err != nil {
+			return err
+		}
+
+		if v.Sensitive {
+			markSensitiveValue(val)
+		}
+
+		values[n] = val
+	}
+
+	p.ctx.Variables = map[string]cty.Value{"var": cty.ObjectVal(values)}
+
+	err := p.setLocals(blocks)
+	if err != nil {
+		return err
+	}
+
+	return p.setDataSources(blocks)
+}
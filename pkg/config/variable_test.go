@@ -0,0 +1,278 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupVariableConfig(t *testing.T, variables map[string]string, contents ...string) (*Config, error) {
+	dir, cleanup := createTestFiles(t, contents...)
+	defer cleanup()
+
+	c := New()
+	err := ParseFolder(dir, c, variables)
+
+	return c, err
+}
+
+func TestVariableDefaultIsUsedInExpressions(t *testing.T) {
+	c, err := setupVariableConfig(t, nil, variableDefault)
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, "consul:1.7.2", co.(*Container).Image.Name)
+	assert.Equal(t, "3", co.(*Container).Environment[0].Value)
+}
+
+func TestVariableOverrideReplacesDefault(t *testing.T) {
+	c, err := setupVariableConfig(t, map[string]string{"version": "1.8.0", "replicas": "5"}, variableDefault)
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, "consul:1.8.0", co.(*Container).Image.Name)
+	assert.Equal(t, "5", co.(*Container).Environment[0].Value)
+}
+
+func TestParseFolderCanBeCalledConcurrently(t *testing.T) {
+	dir, cleanup := createTestFiles(t, variableDefault)
+	defer cleanup()
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(version string) {
+			defer wg.Done()
+
+			c := New()
+			err := ParseFolder(dir, c, map[string]string{"version": version})
+			assert.NoError(t, err)
+
+			co, err := c.FindResource("container.consul")
+			assert.NoError(t, err)
+			assert.Equal(t, "consul:"+version, co.(*Container).Image.Name)
+		}(fmt.Sprintf("1.%d.0", i))
+	}
+
+	wg.Wait()
+}
+
+func TestVariableDefinedInAnotherFileIsUsed(t *testing.T) {
+	c, err := setupVariableConfig(t, nil, variableDefinitions, variableReference)
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, "consul:1.7.2", co.(*Container).Image.Name)
+}
+
+func TestVariableOverrideWithInvalidTypeReturnsError(t *testing.T) {
+	_, err := setupVariableConfig(t, map[string]string{"replicas": "abc"}, variableDefault)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "replicas")
+}
+
+func TestVariableOverrideForUndefinedVariableReturnsError(t *testing.T) {
+	_, err := setupVariableConfig(t, map[string]string{"nope": "abc"}, variableDefault)
+	assert.Error(t, err)
+}
+
+func TestVariableWithoutDefaultOrOverrideReturnsError(t *testing.T) {
+	_, err := setupVariableConfig(t, nil, variableNoDefault)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "--var name=value")
+}
+
+func TestVariableDefinedTwiceReturnsError(t *testing.T) {
+	_, err := setupVariableConfig(t, nil, variableDefinitions, variableDefinitions)
+	assert.Error(t, err)
+}
+
+func TestVariableWithComplexTypeParsesOverride(t *testing.T) {
+	c, err := setupVariableConfig(t, map[string]string{"cmd": `["consul", "agent"]`}, variableList)
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"consul", "agent"}, co.(*Container).Command)
+}
+
+func TestVariablesFileInFolderSetsValues(t *testing.T) {
+	dir, cleanup := createTestFiles(t, variablesFileConfig)
+	defer cleanup()
+	createNamedFile(t, dir, "*.vars", variablesFile)
+
+	c := New()
+	err := ParseFolder(dir, c, nil)
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, "consul:1.8.0", co.(*Container).Image.Name)
+	assert.Equal(t, []string{"consul", "agent"}, co.(*Container).Command)
+	assert.Equal(t, "4", co.(*Container).Environment[0].Value)
+}
+
+func TestVariablesPassedToParseFolderReplaceVariablesFile(t *testing.T) {
+	dir, cleanup := createTestFiles(t, variablesFileConfig)
+	defer cleanup()
+	createNamedFile(t, dir, "*.vars", variablesFile)
+
+	c := New()
+	err := ParseFolder(dir, c, map[string]string{"version": "1.9.0"})
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, "consul:1.9.0", co.(*Container).Image.Name)
+}
+
+func TestParseVariablesFileReturnsValues(t *testing.T) {
+	dir := createTempDirectory(t)
+	defer removeTestFiles(t, dir)
+	f := createNamedFile(t, dir, "*.vars", variablesFile)
+
+	vars, err := ParseVariablesFile(f)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"version": "1.8.0", "replicas": "4", "cmd": `["consul", "agent"]`}, vars)
+}
+
+func TestParseVariablesFileWithExpressionReturnsError(t *testing.T) {
+	dir := createTempDirectory(t)
+	defer removeTestFiles(t, dir)
+	f := createNamedFile(t, dir, "*.vars", `version = var.other`)
+
+	_, err := ParseVariablesFile(f)
+	assert.Error(t, err)
+}
+
+func TestVariableEnvironmentVariableReplacesDefault(t *testing.T) {
+	os.Setenv("SY_VAR_version", "1.8.0")
+	defer os.Unsetenv("SY_VAR_version")
+
+	c, err := setupVariableConfig(t, nil, variableDefault)
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, "consul:1.8.0", co.(*Container).Image.Name)
+}
+
+func TestVariableOverrideReplacesEnvironmentVariable(t *testing.T) {
+	os.Setenv("SY_VAR_version", "1.8.0")
+	defer os.Unsetenv("SY_VAR_version")
+
+	c, err := setupVariableConfig(t, map[string]string{"version": "1.9.0"}, variableDefault)
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, "consul:1.9.0", co.(*Container).Image.Name)
+}
+
+func TestVariableEnvironmentVariableForUndefinedVariableIsIgnored(t *testing.T) {
+	os.Setenv("SY_VAR_nope", "abc")
+	defer os.Unsetenv("SY_VAR_nope")
+
+	_, err := setupVariableConfig(t, nil, variableDefault)
+	assert.NoError(t, err)
+}
+
+const variablesFile = `
+version  = "1.8.0"
+replicas = 4
+cmd      = ["consul", "agent"]
+`
+
+const variablesFileConfig = `
+variable "version" {
+	type = string
+	default = "1.7.2"
+}
+
+variable "replicas" {
+	type = number
+	default = 3
+}
+
+variable "cmd" {
+	type = list(string)
+	default = []
+}
+
+container "consul" {
+	image {
+		name = "consul:${var.version}"
+	}
+
+	command = var.cmd
+
+	env {
+		key = "REPLICAS"
+		value = var.replicas
+	}
+}
+`
+
+const variableDefault = `
+variable "version" {
+	description = "Version of Consul"
+	type = string
+	default = "1.7.2"
+}
+
+variable "replicas" {
+	type = number
+	default = 3
+}
+
+container "consul" {
+	image {
+		name = "consul:${var.version}"
+	}
+
+	env {
+		key = "REPLICAS"
+		value = var.replicas
+	}
+}
+`
+
+const variableDefinitions = `
+variable "version" {
+	default = "1.7.2"
+}
+`
+
+const variableReference = `
+container "consul" {
+	image {
+		name = "consul:${var.version}"
+	}
+}
+`
+
+const variableNoDefault = `
+variable "name" {}
+`
+
+const variableList = `
+variable "cmd" {
+	type = list(string)
+	default = []
+}
+
+container "consul" {
+	image {
+		name = "consul"
+	}
+
+	command = var.cmd
+}
+`
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/shipyard-run/shipyard/pkg/utils"
+)
+
+// TypeVault is the resource string for a Vault resource
+const TypeVault ResourceType = "vault"
+
+// Vault runs a Vault server in dev mode, policies and secrets are written to
+// the server once it has started. The address and root token can be
+// referenced by other resources e.g. vault.dev.address
+type Vault struct {
+	ResourceInfo
+
+	Depends []string `hcl:"depends_on,optional" json:"depends,omitempty"`
+
+	Networks []NetworkAttachment `hcl:"network,block" json:"networks,omitempty"` // networks to attach the server to
+
+	// Version of Vault to run, defaults to 1.6.1
+	Version string `hcl:"version,optional" json:"version,omitempty"`
+
+	// Port is the port on the local machine the server is exposed on
+	Port int `hcl:"port,optional" json:"port"`
+
+	// RootToken is the token for the root user, defaults to root
+	RootToken string `hcl:"root_token,optional" json:"root_token" mapstructure:"root_token" sensitive:"true"`
+
+	// Policies are HCL or JSON policy files, the name of the policy is the
+	// name of the file without the extension
+	Policies []string `hcl:"policies,optional" json:"policies,omitempty"`
+
+	// Secrets are JSON files containing an object where the keys are paths
+	// and the values are the data written to the path
+	Secrets []string `hcl:"secrets,optional" json:"secrets,omitempty"`
+
+	// Address is the address of the server on the local machine
+	// e.g. http://localhost:8200, InternalAddress is the address used by
+	// resources attached to the same network
+	Address         string `json:"address"`
+	InternalAddress string `json:"internal_address" mapstructure:"internal_address"`
+}
+
+// NewVault creates a new Vault config resource
+func NewVault(name string) *Vault {
+	return &Vault{
+		ResourceInfo: ResourceInfo{Name: name, Type: TypeVault, Status: PendingCreation},
+		Version:      "1.6.1",
+		Port:         8200,
+		RootToken:    "root",
+	}
+}
+
+// setAddress sets the addresses of the server
+func (v *Vault) setAddress() {
+	v.Address = fmt.Sprintf("http://localhost:%d", v.Port)
+	v.InternalAddress = fmt.Sprintf("http://%s:8200", utils.FQDN(v.Name, string(v.Type)))
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVaultCreatesCorrectly(t *testing.T) {
+	c, dir, cleanup := setupTestConfig(t, vaultValid)
+	defer cleanup()
+
+	r, err := c.FindResource("vault.dev")
+	require.NoError(t, err)
+
+	v := r.(*Vault)
+	assert.Equal(t, "1.6.1", v.Version)
+	assert.Equal(t, "root", v.RootToken)
+	assert.Equal(t, "http://localhost:18200", v.Address)
+	assert.Equal(t, "http://dev.vault.shipyard.run:8200", v.InternalAddress)
+	assert.Equal(t, []string{filepath.Join(dir, "policies/admin.hcl")}, v.Policies)
+	assert.Contains(t, v.DependsOn, "network.local")
+}
+
+func TestVaultAddressCanBeReferenced(t *testing.T) {
+	c, _, cleanup := setupTestConfig(t, vaultValid)
+	defer cleanup()
+
+	r, err := c.FindResource("container.app")
+	require.NoError(t, err)
+
+	assert.Contains(t, r.(*Container).Environment, KV{Key: "VAULT_ADDR", Value: "http://dev.vault.shipyard.run:8200"})
+}
+
+const vaultValid = `
+network "local" {
+	subnet = "10.6.0.0/16"
+}
+
+vault "dev" {
+	port     = 18200
+	policies = ["./policies/admin.hcl"]
+
+	network {
+		name = "network.local"
+	}
+}
+
+container "app" {
+	image {
+		name = "app:latest"
+	}
+
+	env {
+		key   = "VAULT_ADDR"
+		value = vault.dev.internal_address
+	}
+}
+`
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// walkFolder returns the folder and any sub folders up to maxDepth levels
+// deep, folders are returned root first followed by the sub folders in
+// lexical order. Symlinked folders are followed, folders which have already
+// been visited are skipped to prevent cycles.
+func walkFolder(root string, rules ignoreRules, maxDepth int) ([]string, error) {
+	folders := []string{}
+	visited := map[string]bool{}
+
+	var walk func(dir string, depth int) error
+	walk = func(dir string, depth int) error {
+		real, err := filepath.EvalSymlinks(dir)
+		if err != nil {
+			return err
+		}
+
+		if visited[real] {
+			return nil
+		}
+
+		visited[real] = true
+		folders = append(folders, dir)
+
+		if depth >= maxDepth {
+			return nil
+		}
+
+		entries, err := ioutil.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+
+		names := []string{}
+		for _, e := range entries {
+			p := filepath.Join(dir, e.Name())
+
+			// ReadDir uses Lstat, resolve symlinks to check for folders
+			if e.Mode()&os.ModeSymlink != 0 {
+				fi, err := os.Stat(p)
+				if err != nil {
+					// ignore broken links
+					continue
+				}
+
+				e = fi
+			}
+
+			if !e.IsDir() {
+				continue
+			}
+
+			rel, err := filepath.Rel(root, p)
+			if err != nil {
+				return err
+			}
+
+			if rules.ignored(rel, true) {
+				continue
+			}
+
+			names = append(names, p)
+		}
+
+		sort.Strings(names)
+
+		for _, n := range names {
+			err := walk(n, depth+1)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	err := walk(root, 0)
+	return folders, err
+}
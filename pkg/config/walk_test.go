@@ -0,0 +1,98 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupNestedFolders(t *testing.T) string {
+	dir := createTempDirectory(t)
+
+	for _, d := range []string{"b", "a/nested", "a/nested/deep"} {
+		err := os.MkdirAll(filepath.Join(dir, d), os.ModePerm)
+		assert.NoError(t, err)
+	}
+
+	return dir
+}
+
+func TestWalkFolderReturnsRootOnlyWithDepthZero(t *testing.T) {
+	dir := setupNestedFolders(t)
+	defer removeTestFiles(t, dir)
+
+	folders, err := walkFolder(dir, ignoreRules{}, 0)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{dir}, folders)
+}
+
+func TestWalkFolderReturnsFoldersInOrderToMaxDepth(t *testing.T) {
+	dir := setupNestedFolders(t)
+	defer removeTestFiles(t, dir)
+
+	folders, err := walkFolder(dir, ignoreRules{}, 2)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{
+		dir,
+		filepath.Join(dir, "a"),
+		filepath.Join(dir, "a/nested"),
+		filepath.Join(dir, "b"),
+	}, folders)
+}
+
+func TestWalkFolderFollowsSymlinksWithoutCycles(t *testing.T) {
+	dir := setupNestedFolders(t)
+	defer removeTestFiles(t, dir)
+
+	// link to a folder and a link back to the root
+	err := os.Symlink(filepath.Join(dir, "a/nested/deep"), filepath.Join(dir, "c"))
+	assert.NoError(t, err)
+	err = os.Symlink(dir, filepath.Join(dir, "b/root"))
+	assert.NoError(t, err)
+
+	folders, err := walkFolder(dir, ignoreRules{}, 10)
+	assert.NoError(t, err)
+
+	// c is not returned as it resolves to a/nested/deep
+	assert.Equal(t, []string{
+		dir,
+		filepath.Join(dir, "a"),
+		filepath.Join(dir, "a/nested"),
+		filepath.Join(dir, "a/nested/deep"),
+		filepath.Join(dir, "b"),
+	}, folders)
+}
+
+func TestConfigFilesIncludesSubFolders(t *testing.T) {
+	dir := setupNestedFolders(t)
+	defer removeTestFiles(t, dir)
+
+	root := createNamedFile(t, dir, "z*.hcl", "")
+	deep := createNamedFile(t, filepath.Join(dir, "a/nested/deep"), "*.hcl", "")
+	b := createNamedFile(t, filepath.Join(dir, "b"), "*.hcl", "")
+
+	files, err := ConfigFiles(dir, 5)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{root, deep, b}, files)
+}
+
+func TestParseFolderParsesSubFolders(t *testing.T) {
+	dir := setupNestedFolders(t)
+	defer removeTestFiles(t, dir)
+
+	createNamedFile(t, dir, "*.hcl", variableDefinitions)
+	createNamedFile(t, filepath.Join(dir, "a/nested/deep"), "*.hcl", variableReference)
+
+	c := New()
+	err := ParseFolderWithOptions(dir, c, nil, ParseOptions{MaxFolderDepth: 5})
+	assert.NoError(t, err)
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, "consul:1.7.2", co.(*Container).Image.Name)
+}
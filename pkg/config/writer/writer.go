@@ -0,0 +1,304 @@
+// Package writer modifies blueprint config files and writes them back to disk,
+// changes are made using hclwrite so that comments and formatting in the
+// original files are preserved.
+//
+//	bp, err := writer.LoadFolder("./my-stack")
+//	c, err := bp.Resource("container", "consul")
+//	c.Block("image").SetAttribute("name", cty.StringVal("consul:1.8.0"))
+//	err = bp.Save()
+package writer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+	"github.com/hashicorp/hcl2/hclwrite"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/zclconf/go-cty/cty"
+	"golang.org/x/xerrors"
+)
+
+// ResourceNotFoundError is returned when a resource is not defined in the
+// files which have been loaded
+type ResourceNotFoundError struct {
+	Type string
+	Name string
+}
+
+func (r ResourceNotFoundError) Error() string {
+	return fmt.Sprintf("Resource %s.%s is not defined", r.Type, r.Name)
+}
+
+// Blueprint is a set of config files loaded from a folder
+type Blueprint struct {
+	Files []*File
+}
+
+// LoadFolder loads the HCL config files in a folder, files are loaded in the
+// same order they are parsed by config.ParseFolder. Files written using the
+// JSON or YAML syntax and encrypted files can not be modified and are not
+// loaded.
+func LoadFolder(folder string) (*Blueprint, error) {
+	files, err := config.ConfigFiles(folder, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	bp := &Blueprint{Files: []*File{}}
+
+	for _, f := range files {
+		if filepath.Ext(f) != ".hcl" || config.IsEncryptedFile(f) {
+			continue
+		}
+
+		lf, err := Load(f)
+		if err != nil {
+			return nil, err
+		}
+
+		bp.Files = append(bp.Files, lf)
+	}
+
+	return bp, nil
+}
+
+// Resource returns the resource with the given type and name from any of
+// the files in the blueprint
+func (b *Blueprint) Resource(typ, name string) (*Block, error) {
+	for _, f := range b.Files {
+		r, err := f.Resource(typ, name)
+		if err == nil {
+			return r, nil
+		}
+	}
+
+	return nil, ResourceNotFoundError{typ, name}
+}
+
+// Save writes all of the files in the blueprint which have been changed
+func (b *Blueprint) Save() error {
+	for _, f := range b.Files {
+		if !f.Changed() {
+			continue
+		}
+
+		err := f.Save()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// File is a config file which can be modified
+type File struct {
+	Filename string
+
+	file     *hclwrite.File
+	original []byte
+	blocks   []*Block
+}
+
+// Load reads a config file
+func Load(filename string) (*File, error) {
+	src, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, xerrors.Errorf("Unable to read file %s: %w", filename, err)
+	}
+
+	return Parse(src, filename)
+}
+
+// Parse creates a File from the source of a config file
+func Parse(src []byte, filename string) (*File, error) {
+	// hclwrite does not validate the config, check that it is valid
+	// before making any changes
+	_, diag := hclsyntax.ParseConfig(src, filename, hcl.Pos{Line: 1, Column: 1})
+	if diag.HasErrors() {
+		return nil, xerrors.Errorf("Unable to parse file %s: %w", filename, diag)
+	}
+
+	f, diag := hclwrite.ParseConfig(src, filename, hcl.Pos{Line: 1, Column: 1})
+	if diag.HasErrors() {
+		return nil, xerrors.Errorf("Unable to parse file %s: %w", filename, diag)
+	}
+
+	return &File{
+		Filename: filename,
+		file:     f,
+		original: src,
+		blocks:   newBlocks(f.Body()),
+	}, nil
+}
+
+// Resources returns the top level blocks in the file in the order they
+// are defined
+func (f *File) Resources() []*Block {
+	return f.blocks
+}
+
+// Resource returns the top level block with the given type and name
+func (f *File) Resource(typ, name string) (*Block, error) {
+	for _, b := range f.blocks {
+		if b.Type == typ && len(b.Labels) > 0 && b.Labels[0] == name {
+			return b, nil
+		}
+	}
+
+	return nil, ResourceNotFoundError{typ, name}
+}
+
+// AddResource adds a new resource to the end of the file
+func (f *File) AddResource(typ, name string) *Block {
+	body := f.file.Body()
+	if len(f.blocks) > 0 {
+		body.AppendNewline()
+	}
+
+	b := newBlock(body.AppendNewBlock(typ, []string{name}), typ, []string{name})
+	f.blocks = append(f.blocks, b)
+
+	return b
+}
+
+// Bytes returns the source of the file including any changes
+func (f *File) Bytes() []byte {
+	return hclwrite.Format(f.file.Bytes())
+}
+
+// Changed returns true when the file has been modified since it was loaded
+func (f *File) Changed() bool {
+	return string(f.Bytes()) != string(f.original)
+}
+
+// Save writes the file to disk
+func (f *File) Save() error {
+	return ioutil.WriteFile(f.Filename, f.Bytes(), 0644)
+}
+
+// Block is a block in a config file, either a resource or a nested block
+// such as a port
+type Block struct {
+	Type   string
+	Labels []string
+
+	block  *hclwrite.Block
+	blocks []*Block
+}
+
+func newBlock(b *hclwrite.Block, typ string, labels []string) *Block {
+	return &Block{Type: typ, Labels: labels, block: b, blocks: newBlocks(b.Body())}
+}
+
+// Attribute returns the source of the expression for an attribute, false is
+// returned when the attribute is not set
+func (b *Block) Attribute(name string) (string, bool) {
+	a := b.block.Body().GetAttribute(name)
+	if a == nil {
+		return "", false
+	}
+
+	return strings.TrimSpace(string(a.Expr().BuildTokens(nil).Bytes())), true
+}
+
+// SetAttribute sets the value of an attribute, if the attribute is already
+// set the value is replaced, otherwise the attribute is added to the end
+// of the block
+func (b *Block) SetAttribute(name string, v cty.Value) {
+	b.block.Body().SetAttributeValue(name, v)
+}
+
+// Blocks returns the nested blocks of the given type in the order they are
+// defined
+func (b *Block) Blocks(typ string) []*Block {
+	blocks := []*Block{}
+	for _, nb := range b.blocks {
+		if nb.Type == typ {
+			blocks = append(blocks, nb)
+		}
+	}
+
+	return blocks
+}
+
+// Block returns the first nested block of the given type, nil is returned
+// when the block does not exist
+func (b *Block) Block(typ string) *Block {
+	if blocks := b.Blocks(typ); len(blocks) > 0 {
+		return blocks[0]
+	}
+
+	return nil
+}
+
+// AddBlock adds a new nested block to the end of the block
+func (b *Block) AddBlock(typ string, labels ...string) *Block {
+	body := b.block.Body()
+	body.AppendNewline()
+
+	nb := newBlock(body.AppendNewBlock(typ, labels), typ, labels)
+	b.blocks = append(b.blocks, nb)
+
+	return nb
+}
+
+// newBlocks returns the blocks in a body in the order they are defined,
+// hclwrite does not keep the order of blocks so they are sorted using the
+// position of their tokens in the body
+func newBlocks(body *hclwrite.Body) []*Block {
+	pos := map[*hclwrite.Token]int{}
+	for i, t := range body.BuildTokens(nil) {
+		pos[t] = i
+	}
+
+	wb := body.Blocks()
+	sort.Slice(wb, func(i, j int) bool {
+		return firstTokenPos(wb[i], pos) < firstTokenPos(wb[j], pos)
+	})
+
+	blocks := []*Block{}
+	for _, b := range wb {
+		typ, labels := blockHeader(b)
+		blocks = append(blocks, newBlock(b, typ, labels))
+	}
+
+	return blocks
+}
+
+func firstTokenPos(b *hclwrite.Block, pos map[*hclwrite.Token]int) int {
+	toks := b.BuildTokens(nil)
+	if len(toks) == 0 {
+		return -1
+	}
+
+	return pos[toks[0]]
+}
+
+// blockHeader returns the type and labels of a block from its tokens
+func blockHeader(b *hclwrite.Block) (string, []string) {
+	typ := ""
+	labels := []string{}
+
+	for _, t := range b.BuildTokens(nil) {
+		switch t.Type {
+		case hclsyntax.TokenOBrace:
+			return typ, labels
+		case hclsyntax.TokenIdent:
+			if typ == "" {
+				typ = string(t.Bytes)
+			} else {
+				labels = append(labels, string(t.Bytes))
+			}
+		case hclsyntax.TokenQuotedLit:
+			labels = append(labels, string(t.Bytes))
+		}
+	}
+
+	return typ, labels
+}
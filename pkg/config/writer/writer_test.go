@@ -0,0 +1,183 @@
+package writer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var writerConfig = `# the network for the stack
+network "onprem" {
+  subnet = "10.6.0.0/16"
+}
+
+// consul server
+container "consul" {
+  image {
+    # pinned version
+    name = "consul:1.6.1"
+  }
+
+  port {
+    local  = 8500
+    remote = 8500
+  }
+}
+
+container "vault" {
+  image {
+    name = "vault:1.4.0"
+  }
+}
+`
+
+func setupWriterTests(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "writer")
+	require.NoError(t, err)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "config.hcl"), []byte(writerConfig), 0644)
+	require.NoError(t, err)
+
+	return dir, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+func TestParseReturnsResourcesInOrder(t *testing.T) {
+	f, err := Parse([]byte(writerConfig), "config.hcl")
+	require.NoError(t, err)
+
+	r := f.Resources()
+	require.Len(t, r, 3)
+	assert.Equal(t, "network", r[0].Type)
+	assert.Equal(t, []string{"onprem"}, r[0].Labels)
+	assert.Equal(t, "container", r[1].Type)
+	assert.Equal(t, []string{"consul"}, r[1].Labels)
+	assert.Equal(t, []string{"vault"}, r[2].Labels)
+}
+
+func TestParseWithInvalidConfigReturnsError(t *testing.T) {
+	_, err := Parse([]byte(`container "consul" {`), "config.hcl")
+	assert.Error(t, err)
+}
+
+func TestResourceReturnsAttributes(t *testing.T) {
+	f, err := Parse([]byte(writerConfig), "config.hcl")
+	require.NoError(t, err)
+
+	c, err := f.Resource("container", "consul")
+	require.NoError(t, err)
+
+	v, ok := c.Block("image").Attribute("name")
+	assert.True(t, ok)
+	assert.Equal(t, `"consul:1.6.1"`, v)
+
+	_, ok = c.Attribute("command")
+	assert.False(t, ok)
+}
+
+func TestResourceNotFoundReturnsError(t *testing.T) {
+	f, err := Parse([]byte(writerConfig), "config.hcl")
+	require.NoError(t, err)
+
+	_, err = f.Resource("container", "nomad")
+	assert.IsType(t, ResourceNotFoundError{}, err)
+}
+
+func TestSetAttributePreservesComments(t *testing.T) {
+	f, err := Parse([]byte(writerConfig), "config.hcl")
+	require.NoError(t, err)
+
+	c, err := f.Resource("container", "consul")
+	require.NoError(t, err)
+
+	c.Block("image").SetAttribute("name", cty.StringVal("consul:1.8.0"))
+
+	out := string(f.Bytes())
+	assert.Contains(t, out, `name = "consul:1.8.0"`)
+	assert.NotContains(t, out, "consul:1.6.1")
+	assert.Contains(t, out, "# the network for the stack")
+	assert.Contains(t, out, "# pinned version")
+	assert.Contains(t, out, "// consul server")
+	assert.True(t, f.Changed())
+}
+
+func TestUnchangedFileIsNotChanged(t *testing.T) {
+	f, err := Parse([]byte(writerConfig), "config.hcl")
+	require.NoError(t, err)
+
+	assert.Equal(t, writerConfig, string(f.Bytes()))
+	assert.False(t, f.Changed())
+}
+
+func TestAddBlockAddsNestedBlock(t *testing.T) {
+	f, err := Parse([]byte(writerConfig), "config.hcl")
+	require.NoError(t, err)
+
+	c, err := f.Resource("container", "consul")
+	require.NoError(t, err)
+
+	p := c.AddBlock("port")
+	p.SetAttribute("local", cty.NumberIntVal(8300))
+	p.SetAttribute("remote", cty.NumberIntVal(8300))
+
+	assert.Len(t, c.Blocks("port"), 2)
+
+	// parse the output to check the new block is valid
+	f2, err := Parse(f.Bytes(), "config.hcl")
+	require.NoError(t, err)
+
+	c2, err := f2.Resource("container", "consul")
+	require.NoError(t, err)
+
+	ports := c2.Blocks("port")
+	require.Len(t, ports, 2)
+
+	v, _ := ports[1].Attribute("local")
+	assert.Equal(t, "8300", v)
+}
+
+func TestAddResourceAddsBlockToEndOfFile(t *testing.T) {
+	f, err := Parse([]byte(writerConfig), "config.hcl")
+	require.NoError(t, err)
+
+	r := f.AddResource("container", "nomad")
+	r.AddBlock("image").SetAttribute("name", cty.StringVal("nomad:0.11.0"))
+
+	f2, err := Parse(f.Bytes(), "config.hcl")
+	require.NoError(t, err)
+
+	res := f2.Resources()
+	require.Len(t, res, 4)
+	assert.Equal(t, []string{"nomad"}, res[3].Labels)
+
+	v, _ := res[3].Block("image").Attribute("name")
+	assert.Equal(t, `"nomad:0.11.0"`, v)
+}
+
+func TestLoadFolderAndSaveWritesChanges(t *testing.T) {
+	dir, cleanup := setupWriterTests(t)
+	defer cleanup()
+
+	bp, err := LoadFolder(dir)
+	require.NoError(t, err)
+	require.Len(t, bp.Files, 1)
+
+	c, err := bp.Resource("container", "vault")
+	require.NoError(t, err)
+
+	c.Block("image").SetAttribute("name", cty.StringVal("vault:1.5.0"))
+
+	err = bp.Save()
+	require.NoError(t, err)
+
+	d, err := ioutil.ReadFile(filepath.Join(dir, "config.hcl"))
+	require.NoError(t, err)
+	assert.Contains(t, string(d), `name = "vault:1.5.0"`)
+	assert.Contains(t, string(d), "# pinned version")
+}
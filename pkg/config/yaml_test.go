@@ -0,0 +1,75 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFolderReadsYAMLFiles(t *testing.T) {
+	dir := createTempDirectory(t)
+	defer removeTestFiles(t, dir)
+
+	createNamedFile(t, dir, "*.yard.yaml", yamlConfig)
+
+	c := New()
+	err := ParseFolder(dir, c, nil)
+	assert.NoError(t, err)
+
+	n, err := c.FindResource("network.local")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.5.0.0/16", n.(*Network).Subnet)
+
+	co, err := c.FindResource("container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, "consul:1.7.2", co.(*Container).Image.Name)
+	assert.Equal(t, "network.local", co.(*Container).Networks[0].Name)
+	assert.Len(t, co.(*Container).Environment, 2)
+	assert.Equal(t, "CONSUL_HTTP_ADDR", co.(*Container).Environment[1].Key)
+}
+
+func TestParseFolderIgnoresOtherYAMLFiles(t *testing.T) {
+	dir := createTempDirectory(t)
+	defer removeTestFiles(t, dir)
+
+	createNamedFile(t, dir, "*.yaml", "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: test\n")
+	createNamedFile(t, dir, "*.yml", "replicas: 3\n")
+
+	c := New()
+	err := ParseFolder(dir, c, nil)
+	assert.NoError(t, err)
+	assert.Len(t, c.Resources, 0)
+}
+
+func TestParseHCLFileWithInvalidYAMLReturnsError(t *testing.T) {
+	dir := createTempDirectory(t)
+	defer removeTestFiles(t, dir)
+
+	f := createNamedFile(t, dir, "*.yml", "network:\n  local: [")
+
+	c := New()
+	err := ParseHCLFile(f, c, nil)
+	assert.Error(t, err)
+}
+
+const yamlConfig = `
+variable:
+  version:
+    default: 1.7.2
+
+network:
+  local:
+    subnet: 10.5.0.0/16
+
+container:
+  consul:
+    image:
+      name: consul:${var.version}
+    network:
+      name: network.local
+    env:
+      - key: CONSUL_BIND
+        value: 0.0.0.0
+      - key: CONSUL_HTTP_ADDR
+        value: http://localhost:8500
+`
@@ -0,0 +1,129 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+)
+
+// Namespace is the prefix for all metrics exported by Shipyard
+const Namespace = "shipyard"
+
+// ResultSuccess is the result label for operations which completed without error
+const ResultSuccess = "success"
+
+// ResultError is the result label for operations which returned an error
+const ResultError = "error"
+
+var registry = prometheus.NewRegistry()
+
+var operationDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "operation_duration_seconds",
+		Help:      "Time taken to apply or destroy an environment",
+		Buckets:   []float64{1, 5, 10, 30, 60, 120, 300, 600, 1200},
+	},
+	[]string{"operation", "result"},
+)
+
+var providerErrors = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "provider_errors_total",
+		Help:      "Number of errors returned by providers when creating or destroying resources",
+	},
+	[]string{"type", "operation"},
+)
+
+func init() {
+	registry.MustRegister(
+		operationDuration,
+		providerErrors,
+		&stateCollector{},
+		prometheus.NewGoCollector(),
+	)
+}
+
+// Handler returns a http.Handler which serves the metrics in the Prometheus
+// exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// ObserveOperation records the duration of an apply or destroy operation
+// which started at the given time
+func ObserveOperation(operation string, start time.Time, err error) {
+	operationDuration.WithLabelValues(operation, result(err)).Observe(time.Since(start).Seconds())
+}
+
+// IncProviderError increments the error count for the given resource type
+// and provider operation
+func IncProviderError(t config.ResourceType, operation string) {
+	providerErrors.WithLabelValues(string(t), operation).Inc()
+}
+
+func result(err error) string {
+	if err != nil {
+		return ResultError
+	}
+
+	return ResultSuccess
+}
+
+var resourcesDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(Namespace, "", "resources"),
+	"Number of resources in the current state grouped by type and status",
+	[]string{"type", "status"},
+	nil,
+)
+
+var environmentsDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(Namespace, "", "active_environments"),
+	"Number of environments which currently have resources",
+	nil,
+	nil,
+)
+
+// stateCollector reads the state file each time metrics are scraped
+// so that the values reflect changes made by other Shipyard processes
+type stateCollector struct{}
+
+func (s *stateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- resourcesDesc
+	ch <- environmentsDesc
+}
+
+func (s *stateCollector) Collect(ch chan<- prometheus.Metric) {
+	c := config.New()
+	err := c.FromJSON(utils.StatePath())
+	if err != nil && err != config.StateNotFoundError {
+		ch <- prometheus.NewInvalidMetric(resourcesDesc, err)
+		return
+	}
+
+	type key struct {
+		t config.ResourceType
+		s config.Status
+	}
+
+	counts := map[key]int{}
+	for _, r := range c.Resources {
+		counts[key{r.Info().Type, r.Info().Status}]++
+	}
+
+	for k, v := range counts {
+		ch <- prometheus.MustNewConstMetric(resourcesDesc, prometheus.GaugeValue, float64(v), string(k.t), string(k.s))
+	}
+
+	active := 0
+	if len(c.Resources) > 0 {
+		active = 1
+	}
+
+	ch <- prometheus.MustNewConstMetric(environmentsDesc, prometheus.GaugeValue, float64(active))
+}
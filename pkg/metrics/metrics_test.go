@@ -0,0 +1,116 @@
+package metrics
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupState(t *testing.T, state string) func() {
+	home := os.Getenv("HOME")
+	dir, err := ioutil.TempDir("", "")
+	assert.NoError(t, err)
+	os.Setenv("HOME", dir)
+
+	if state != "" {
+		os.MkdirAll(utils.StateDir(), os.ModePerm)
+		ioutil.WriteFile(utils.StatePath(), []byte(state), os.ModePerm)
+	}
+
+	return func() {
+		os.Setenv("HOME", home)
+		os.RemoveAll(dir)
+	}
+}
+
+func TestIncProviderErrorIncrementsCounter(t *testing.T) {
+	before := testutil.ToFloat64(providerErrors.WithLabelValues("container", "create"))
+
+	IncProviderError(config.TypeContainer, "create")
+
+	assert.Equal(t, before+1, testutil.ToFloat64(providerErrors.WithLabelValues("container", "create")))
+}
+
+func TestObserveOperationRecordsResult(t *testing.T) {
+	ObserveOperation("apply", time.Now(), fmt.Errorf("boom"))
+
+	mfs, err := registry.Gather()
+	assert.NoError(t, err)
+
+	for _, mf := range mfs {
+		if mf.GetName() == "shipyard_operation_duration_seconds" {
+			for _, m := range mf.GetMetric() {
+				for _, l := range m.GetLabel() {
+					if l.GetName() == "result" && l.GetValue() == ResultError {
+						return
+					}
+				}
+			}
+		}
+	}
+
+	t.Fatal("Expected operation duration with error result")
+}
+
+func TestStateCollectorReturnsResourcesByStatus(t *testing.T) {
+	cleanup := setupState(t, testState)
+	defer cleanup()
+
+	expected := `
+# HELP shipyard_active_environments Number of environments which currently have resources
+# TYPE shipyard_active_environments gauge
+shipyard_active_environments 1
+# HELP shipyard_resources Number of resources in the current state grouped by type and status
+# TYPE shipyard_resources gauge
+shipyard_resources{status="applied",type="container"} 2
+shipyard_resources{status="failed",type="k8s_cluster"} 1
+`
+
+	err := testutil.CollectAndCompare(&stateCollector{}, strings.NewReader(expected))
+	assert.NoError(t, err)
+}
+
+func TestStateCollectorWithNoStateReturnsNoActiveEnvironments(t *testing.T) {
+	cleanup := setupState(t, "")
+	defer cleanup()
+
+	expected := `
+# HELP shipyard_active_environments Number of environments which currently have resources
+# TYPE shipyard_active_environments gauge
+shipyard_active_environments 0
+`
+
+	err := testutil.CollectAndCompare(&stateCollector{}, strings.NewReader(expected))
+	assert.NoError(t, err)
+}
+
+var testState = `
+{
+  "blueprint": null,
+  "resources": [
+	{
+      "name": "consul",
+      "status": "applied",
+      "type": "container"
+	},
+	{
+      "name": "vault",
+      "status": "applied",
+      "type": "container"
+	},
+	{
+      "name": "k3s",
+      "status": "failed",
+      "type": "k8s_cluster"
+	}
+  ]
+}
+`
@@ -0,0 +1,142 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"golang.org/x/xerrors"
+)
+
+const localstackImage = "localstack/localstack"
+const localstackPort = 4566
+
+// AWS is a provider for running LocalStack
+type AWS struct {
+	config     *config.AWS
+	client     clients.ContainerTasks
+	httpClient clients.HTTP
+	log        hclog.Logger
+}
+
+// NewAWS creates a new LocalStack provider
+func NewAWS(a *config.AWS, cl clients.ContainerTasks, hc clients.HTTP, l hclog.Logger) *AWS {
+	return &AWS{a, cl, hc, l}
+}
+
+// Create the LocalStack container and run the bootstrap script
+func (a *AWS) Create(ctx context.Context) error {
+	a.log.Info("Creating AWS", "ref", a.config.Name, "services", a.config.Services, "address", a.config.Address)
+
+	cc := config.NewContainer(a.config.Name)
+	a.config.ResourceInfo.AddChild(cc)
+
+	cc.Image = config.Image{Name: fmt.Sprintf("%s:%s", localstackImage, a.config.Version)}
+	cc.Networks = a.config.Networks
+	cc.Environment = []config.KV{
+		config.KV{Key: "DEFAULT_REGION", Value: a.config.Region},
+	}
+
+	if len(a.config.Services) > 0 {
+		cc.Environment = append(cc.Environment, config.KV{Key: "SERVICES", Value: strings.Join(a.config.Services, ",")})
+	}
+
+	cc.Ports = []config.Port{
+		config.Port{
+			Local:    fmt.Sprintf("%d", localstackPort),
+			Host:     fmt.Sprintf("%d", a.config.Port),
+			Protocol: "tcp",
+		},
+	}
+
+	err := a.client.PullImage(ctx, cc.Image, false)
+	if err != nil {
+		return err
+	}
+
+	id, err := a.client.CreateContainer(cc)
+	if err != nil {
+		return err
+	}
+
+	err = a.httpClient.HealthCheckHTTP(fmt.Sprintf("%s/health", a.config.Address), 120*time.Second)
+	if err != nil {
+		return xerrors.Errorf("LocalStack did not start: %w", err)
+	}
+
+	if a.config.Bootstrap == "" {
+		return nil
+	}
+
+	a.log.Debug("Running bootstrap script", "ref", a.config.Name, "script", a.config.Bootstrap)
+
+	err = a.client.CopyToContainer(id, a.config.Bootstrap, "/tmp/shipyard")
+	if err != nil {
+		return xerrors.Errorf("Unable to copy bootstrap script: %w", err)
+	}
+
+	err = a.client.ExecuteCommand(
+		id,
+		[]string{"sh", filepath.Join("/tmp/shipyard", filepath.Base(a.config.Bootstrap))},
+		a.env(),
+		"/tmp/shipyard",
+		a.log.StandardWriter(&hclog.StandardLoggerOptions{ForceLevel: hclog.Debug}),
+	)
+	if err != nil {
+		return xerrors.Errorf("Unable to run bootstrap script: %w", err)
+	}
+
+	return nil
+}
+
+// Destroy the LocalStack container, all data in the services is lost
+func (a *AWS) Destroy() error {
+	a.log.Info("Destroy AWS", "ref", a.config.Name)
+
+	ids, err := a.Lookup()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		err := a.client.RemoveContainer(id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Lookup the ID of the LocalStack container
+func (a *AWS) Lookup() ([]string, error) {
+	return a.client.FindContainerIDs(a.config.Name, a.config.Type)
+}
+
+// env returns the environment for the bootstrap script, the endpoint is the
+// local address as the script runs in the LocalStack container
+func (a *AWS) env() []string {
+	keys := []string{}
+	for k := range a.config.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	env := []string{}
+	for _, k := range keys {
+		v := a.config.Env[k]
+		if k == "AWS_ENDPOINT_URL" {
+			v = fmt.Sprintf("http://localhost:%d", localstackPort)
+		}
+
+		env = append(env, fmt.Sprintf("%s=%s", k, v))
+	}
+
+	return env
+}
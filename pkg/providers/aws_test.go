@@ -0,0 +1,88 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupAWS() (*config.AWS, *mocks.MockContainerTasks, *mocks.MockHTTP) {
+	a := config.NewAWS("local")
+	a.Services = []string{"s3", "sqs"}
+	a.Bootstrap = "/tmp/scripts/init.sh"
+	a.Address = "http://localhost:4566"
+	a.Env = map[string]string{
+		"AWS_ACCESS_KEY_ID":  "test",
+		"AWS_DEFAULT_REGION": "us-east-1",
+		"AWS_ENDPOINT_URL":   "http://local.aws.shipyard.run:4566",
+	}
+
+	md := &mocks.MockContainerTasks{}
+	md.On("PullImage", mock.Anything, mock.Anything).Return(nil)
+	md.On("CreateContainer", mock.Anything).Return("abc", nil)
+	md.On("CopyToContainer", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	md.On("ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	hc := &mocks.MockHTTP{}
+	hc.On("HealthCheckHTTP", mock.Anything, mock.Anything).Return(nil)
+
+	return a, md, hc
+}
+
+func TestAWSCreatesLocalStackContainer(t *testing.T) {
+	a, md, hc := setupAWS()
+	p := NewAWS(a, md, hc, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	cc := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+	assert.Equal(t, "localstack/localstack:0.12.5", cc.Image.Name)
+	assert.Contains(t, cc.Environment, config.KV{Key: "SERVICES", Value: "s3,sqs"})
+	assert.Equal(t, "4566", cc.Ports[0].Host)
+
+	hc.AssertCalled(t, "HealthCheckHTTP", "http://localhost:4566/health", mock.Anything)
+}
+
+func TestAWSRunsBootstrapScript(t *testing.T) {
+	a, md, hc := setupAWS()
+	p := NewAWS(a, md, hc, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	md.AssertCalled(t, "CopyToContainer", "abc", "/tmp/scripts/init.sh", "/tmp/shipyard")
+
+	call := getCalls(&md.Mock, "ExecuteCommand")[0]
+	assert.Equal(t, []string{"sh", "/tmp/shipyard/init.sh"}, call.Arguments[1])
+	assert.Contains(t, call.Arguments[2], "AWS_ENDPOINT_URL=http://localhost:4566")
+	assert.Contains(t, call.Arguments[2], "AWS_DEFAULT_REGION=us-east-1")
+}
+
+func TestAWSWithoutBootstrapDoesNotRunScript(t *testing.T) {
+	a, md, hc := setupAWS()
+	a.Bootstrap = ""
+	p := NewAWS(a, md, hc, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	md.AssertNotCalled(t, "ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAWSReturnsErrorWhenBootstrapFails(t *testing.T) {
+	a, md, hc := setupAWS()
+	removeOn(&md.Mock, "ExecuteCommand")
+	md.On("ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("exit code 1"))
+	p := NewAWS(a, md, hc, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+}
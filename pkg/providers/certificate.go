@@ -0,0 +1,224 @@
+package providers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"golang.org/x/xerrors"
+)
+
+// certificateValidity is the length of time generated certificates are
+// valid for
+const certificateValidity = 365 * 24 * time.Hour
+
+// CertificateCA is a provider for generating a certificate authority
+type CertificateCA struct {
+	config *config.CertificateCA
+	log    hclog.Logger
+}
+
+// NewCertificateCA creates a new certificate authority provider
+func NewCertificateCA(ca *config.CertificateCA, l hclog.Logger) *CertificateCA {
+	return &CertificateCA{ca, l}
+}
+
+// Create generates the certificate and key
+func (c *CertificateCA) Create(ctx context.Context) error {
+	c.log.Info("Creating CA Certificate", "ref", c.config.Name, "cert", c.config.Cert)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return xerrors.Errorf("Unable to generate key: %w", err)
+	}
+
+	tmpl, err := certificateTemplate(c.config.Name)
+	if err != nil {
+		return err
+	}
+
+	tmpl.IsCA = true
+	tmpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature
+
+	cert, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return xerrors.Errorf("Unable to create certificate: %w", err)
+	}
+
+	return writeCertificate(c.config.Output, c.config.Cert, c.config.Key, cert, key)
+}
+
+// Destroy removes the certificate and key
+func (c *CertificateCA) Destroy() error {
+	c.log.Info("Destroy CA Certificate", "ref", c.config.Name)
+
+	return removeCertificate(c.config.Cert, c.config.Key)
+}
+
+// Lookup returns nothing, certificates are files
+func (c *CertificateCA) Lookup() ([]string, error) {
+	return nil, nil
+}
+
+// CertificateLeaf is a provider for generating certificates signed by a
+// certificate authority
+type CertificateLeaf struct {
+	config *config.CertificateLeaf
+	log    hclog.Logger
+}
+
+// NewCertificateLeaf creates a new leaf certificate provider
+func NewCertificateLeaf(cl *config.CertificateLeaf, l hclog.Logger) *CertificateLeaf {
+	return &CertificateLeaf{cl, l}
+}
+
+// Create generates the certificate and key and signs the certificate with
+// the certificate authority
+func (c *CertificateLeaf) Create(ctx context.Context) error {
+	c.log.Info("Creating Leaf Certificate", "ref", c.config.Name, "cert", c.config.Cert)
+
+	caCert, caKey, err := readCertificate(c.config.CACert, c.config.CAKey)
+	if err != nil {
+		return xerrors.Errorf("Unable to read CA certificate: %w", err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return xerrors.Errorf("Unable to generate key: %w", err)
+	}
+
+	tmpl, err := certificateTemplate(c.config.Name)
+	if err != nil {
+		return err
+	}
+
+	tmpl.KeyUsage = x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment
+	tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	tmpl.DNSNames = c.config.DNSNames
+
+	for _, ip := range c.config.IPAddresses {
+		i := net.ParseIP(ip)
+		if i == nil {
+			return fmt.Errorf("Invalid IP address %s", ip)
+		}
+
+		tmpl.IPAddresses = append(tmpl.IPAddresses, i)
+	}
+
+	cert, err := x509.CreateCertificate(rand.Reader, tmpl, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		return xerrors.Errorf("Unable to create certificate: %w", err)
+	}
+
+	return writeCertificate(c.config.Output, c.config.Cert, c.config.Key, cert, key)
+}
+
+// Destroy removes the certificate and key
+func (c *CertificateLeaf) Destroy() error {
+	c.log.Info("Destroy Leaf Certificate", "ref", c.config.Name)
+
+	return removeCertificate(c.config.Cert, c.config.Key)
+}
+
+// Lookup returns nothing, certificates are files
+func (c *CertificateLeaf) Lookup() ([]string, error) {
+	return nil, nil
+}
+
+func certificateTemplate(name string) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, xerrors.Errorf("Unable to generate serial number: %w", err)
+	}
+
+	return &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: name, Organization: []string{"Shipyard"}},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(certificateValidity),
+		BasicConstraintsValid: true,
+	}, nil
+}
+
+// writeCertificate writes the certificate and key to PEM encoded files
+func writeCertificate(dir, certFile, keyFile string, cert []byte, key *ecdsa.PrivateKey) error {
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return xerrors.Errorf("Unable to create output folder %s: %w", dir, err)
+	}
+
+	kd, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return xerrors.Errorf("Unable to encode key: %w", err)
+	}
+
+	err = ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}), 0644)
+	if err != nil {
+		return xerrors.Errorf("Unable to write certificate %s: %w", certFile, err)
+	}
+
+	err = ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: kd}), 0600)
+	if err != nil {
+		return xerrors.Errorf("Unable to write key %s: %w", keyFile, err)
+	}
+
+	return nil
+}
+
+// readCertificate reads a PEM encoded certificate and EC key
+func readCertificate(certFile, keyFile string) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	cd, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cb, _ := pem.Decode(cd)
+	if cb == nil {
+		return nil, nil, fmt.Errorf("%s does not contain a PEM encoded certificate", certFile)
+	}
+
+	cert, err := x509.ParseCertificate(cb.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	kd, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	kb, _ := pem.Decode(kd)
+	if kb == nil {
+		return nil, nil, fmt.Errorf("%s does not contain a PEM encoded key", keyFile)
+	}
+
+	key, err := x509.ParseECPrivateKey(kb.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cert, key, nil
+}
+
+func removeCertificate(files ...string) error {
+	for _, f := range files {
+		err := os.Remove(f)
+		if err != nil && !os.IsNotExist(err) {
+			return xerrors.Errorf("Unable to remove %s: %w", f, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package providers
+
+import (
+	"context"
+	"crypto/x509"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupCertificates(t *testing.T) (*config.CertificateCA, *config.CertificateLeaf, func()) {
+	dir, err := ioutil.TempDir("", "certs")
+	assert.NoError(t, err)
+
+	ca := config.NewCertificateCA("root")
+	ca.Output = dir
+	ca.Cert = filepath.Join(dir, "root.cert")
+	ca.Key = filepath.Join(dir, "root.key")
+
+	cl := config.NewCertificateLeaf("leaf")
+	cl.CACert = ca.Cert
+	cl.CAKey = ca.Key
+	cl.DNSNames = []string{"consul.container.shipyard.run"}
+	cl.IPAddresses = []string{"127.0.0.1"}
+	cl.Output = dir
+	cl.Cert = filepath.Join(dir, "leaf.cert")
+	cl.Key = filepath.Join(dir, "leaf.key")
+
+	return ca, cl, func() { os.RemoveAll(dir) }
+}
+
+func TestCertificateLeafCreateSignsWithCA(t *testing.T) {
+	ca, cl, cleanup := setupCertificates(t)
+	defer cleanup()
+
+	err := NewCertificateCA(ca, hclog.NewNullLogger()).Create(context.Background())
+	assert.NoError(t, err)
+
+	err = NewCertificateLeaf(cl, hclog.NewNullLogger()).Create(context.Background())
+	assert.NoError(t, err)
+
+	caCert, _, err := readCertificate(ca.Cert, ca.Key)
+	assert.NoError(t, err)
+	assert.True(t, caCert.IsCA)
+
+	cert, _, err := readCertificate(cl.Cert, cl.Key)
+	assert.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+
+	_, err = cert.Verify(x509.VerifyOptions{Roots: pool, DNSName: "consul.container.shipyard.run"})
+	assert.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", cert.IPAddresses[0].String())
+}
+
+func TestCertificateLeafCreateReturnsErrorWhenNoCA(t *testing.T) {
+	_, cl, cleanup := setupCertificates(t)
+	defer cleanup()
+
+	err := NewCertificateLeaf(cl, hclog.NewNullLogger()).Create(context.Background())
+	assert.Error(t, err)
+}
+
+func TestCertificateDestroyRemovesFiles(t *testing.T) {
+	ca, _, cleanup := setupCertificates(t)
+	defer cleanup()
+
+	p := NewCertificateCA(ca, hclog.NewNullLogger())
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	err = p.Destroy()
+	assert.NoError(t, err)
+
+	assert.NoFileExists(t, ca.Cert)
+	assert.NoFileExists(t, ca.Key)
+}
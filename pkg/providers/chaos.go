@@ -0,0 +1,228 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"golang.org/x/xerrors"
+)
+
+const chaosImage = "nicolaka/netshoot:v0.1"
+
+// chaosInterfaces runs a tc command for each network interface in the
+// container except the loopback
+const chaosInterfaces = `for i in $(ls /sys/class/net | grep -v '^lo$'); do %s; done`
+
+// Chaos is a provider which injects network faults into containers, a
+// privileged helper container shares the network of each target and uses
+// tc to configure netem
+type Chaos struct {
+	config *config.Chaos
+	client clients.ContainerTasks
+	log    hclog.Logger
+}
+
+// NewChaos creates a new Chaos provider
+func NewChaos(c *config.Chaos, cc clients.ContainerTasks, l hclog.Logger) *Chaos {
+	return &Chaos{c, cc, l}
+}
+
+// Create a helper container for each target and apply the faults when the
+// resource is enabled
+func (c *Chaos) Create(ctx context.Context) error {
+	c.log.Info("Creating Chaos", "ref", c.config.Name, "targets", c.config.Targets)
+
+	targets, err := c.targets()
+	if err != nil {
+		return err
+	}
+
+	for _, t := range targets {
+		cc := config.NewContainer(c.helperName(t))
+		c.config.ResourceInfo.AddChild(cc)
+
+		cc.Image = config.Image{Name: chaosImage}
+		cc.Networks = []config.NetworkAttachment{config.NetworkAttachment{Name: fmt.Sprintf("%s.%s", t.Type, t.Name)}}
+		cc.Privileged = true
+		cc.Command = []string{"tail", "-f", "/dev/null"} // ensure container does not immediately exit
+
+		err := c.client.PullImage(ctx, cc.Image, false)
+		if err != nil {
+			return err
+		}
+
+		_, err = c.client.CreateContainer(cc)
+		if err != nil {
+			return xerrors.Errorf("Unable to create helper for %s: %w", t.Name, err)
+		}
+	}
+
+	if !c.config.Enabled {
+		return nil
+	}
+
+	return c.Enable()
+}
+
+// Destroy the helper containers, the faults are removed with the network
+// namespace of the target
+func (c *Chaos) Destroy() error {
+	c.log.Info("Destroy Chaos", "ref", c.config.Name)
+
+	ids, err := c.Lookup()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		// the target may still be running
+		c.exec(id, c.clearCommand())
+
+		err := c.client.RemoveContainer(id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Lookup the IDs of the helper containers
+func (c *Chaos) Lookup() ([]string, error) {
+	targets, err := c.targets()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []string{}
+	for _, t := range targets {
+		i, err := c.client.FindContainerIDs(c.helperName(t), c.config.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, i...)
+	}
+
+	return ids, nil
+}
+
+// Enable applies the faults to all the targets
+func (c *Chaos) Enable() error {
+	c.log.Info("Enabling Chaos", "ref", c.config.Name, "latency", c.config.Latency, "loss", c.config.Loss, "bandwidth", c.config.Bandwidth)
+
+	return c.execAll(c.netemCommand())
+}
+
+// Disable removes the faults from all the targets
+func (c *Chaos) Disable() error {
+	c.log.Info("Disabling Chaos", "ref", c.config.Name)
+
+	return c.execAll(c.clearCommand())
+}
+
+func (c *Chaos) execAll(command []string) error {
+	ids, err := c.Lookup()
+	if err != nil {
+		return err
+	}
+
+	if len(ids) == 0 {
+		return fmt.Errorf("Unable to find helper containers for %s", c.config.Name)
+	}
+
+	for _, id := range ids {
+		err := c.exec(id, command)
+		if err != nil {
+			return xerrors.Errorf("Unable to configure network faults: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Chaos) exec(id string, command []string) error {
+	return c.client.ExecuteCommand(id, command, []string{}, "/", c.log.StandardWriter(&hclog.StandardLoggerOptions{ForceLevel: hclog.Debug}))
+}
+
+// netemCommand returns the command which replaces the root queueing
+// discipline of each interface with netem
+func (c *Chaos) netemCommand() []string {
+	args := []string{"tc", "qdisc", "replace", "dev", "$i", "root", "netem"}
+
+	if c.config.Latency != "" {
+		args = append(args, "delay", c.config.Latency)
+
+		if c.config.Jitter != "" {
+			args = append(args, c.config.Jitter)
+		}
+	}
+
+	if c.config.Loss > 0 {
+		args = append(args, "loss", fmt.Sprintf("%v%%", c.config.Loss))
+	}
+
+	if c.config.Bandwidth != "" {
+		args = append(args, "rate", c.config.Bandwidth)
+	}
+
+	return []string{"sh", "-c", fmt.Sprintf(chaosInterfaces, strings.Join(args, " "))}
+}
+
+// clearCommand returns the command which restores the default queueing
+// discipline, errors are ignored as netem may not have been applied
+func (c *Chaos) clearCommand() []string {
+	return []string{"sh", "-c", fmt.Sprintf(chaosInterfaces, "tc qdisc del dev $i root 2>/dev/null || true")}
+}
+
+func (c *Chaos) helperName(t *config.Container) string {
+	return fmt.Sprintf("%s-%s", c.config.Name, t.Name)
+}
+
+// targets returns the containers faults are applied to, network targets
+// are expanded to the containers attached to the network
+func (c *Chaos) targets() ([]*config.Container, error) {
+	containers := []*config.Container{}
+	added := map[string]bool{}
+
+	add := func(co *config.Container) {
+		if !added[co.Name] {
+			added[co.Name] = true
+			containers = append(containers, co)
+		}
+	}
+
+	for _, t := range c.config.Targets {
+		r, err := c.config.FindDependentResource(t)
+		if err != nil {
+			return nil, xerrors.Errorf("Unable to find target: %w", err)
+		}
+
+		switch v := r.(type) {
+		case *config.Container:
+			add(v)
+		case *config.Network:
+			for _, cr := range c.config.Config.Resources {
+				co, ok := cr.(*config.Container)
+				if !ok {
+					continue
+				}
+
+				for _, n := range co.Networks {
+					if n.Name == t {
+						add(co)
+						break
+					}
+				}
+			}
+		default:
+			return nil, fmt.Errorf("Unable to inject faults into %s, targets must be containers or networks", t)
+		}
+	}
+
+	return containers, nil
+}
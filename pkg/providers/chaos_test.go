@@ -0,0 +1,104 @@
+package providers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupChaos() (*config.Chaos, *mocks.MockContainerTasks) {
+	n := config.NewNetwork("local")
+
+	c1 := config.NewContainer("api")
+	c1.Networks = []config.NetworkAttachment{{Name: "network.local"}}
+
+	c2 := config.NewContainer("db")
+	c2.Networks = []config.NetworkAttachment{{Name: "network.local"}}
+
+	ch := config.NewChaos("slow")
+	ch.Targets = []string{"container.db"}
+	ch.Latency = "200ms"
+	ch.Jitter = "20ms"
+	ch.Loss = 5
+	ch.Bandwidth = "1mbit"
+
+	c := config.New()
+	c.AddResource(n)
+	c.AddResource(c1)
+	c.AddResource(c2)
+	c.AddResource(ch)
+
+	md := &mocks.MockContainerTasks{}
+	md.On("PullImage", mock.Anything, mock.Anything).Return(nil)
+	md.On("CreateContainer", mock.Anything).Return("abc", nil)
+	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return([]string{"abc"}, nil)
+	md.On("RemoveContainer", mock.Anything).Return(nil)
+	md.On("ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	return ch, md
+}
+
+func TestChaosCreatesHelperInTargetNetwork(t *testing.T) {
+	ch, md := setupChaos()
+	p := NewChaos(ch, md, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	cc := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+	assert.Equal(t, "slow-db", cc.Name)
+	assert.Equal(t, "container.db", cc.Networks[0].Name)
+	assert.True(t, cc.Privileged)
+}
+
+func TestChaosAppliesNetem(t *testing.T) {
+	ch, md := setupChaos()
+	p := NewChaos(ch, md, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	cmd := getCalls(&md.Mock, "ExecuteCommand")[0].Arguments[1].([]string)
+	assert.Equal(t, "sh", cmd[0])
+	assert.Contains(t, cmd[2], "tc qdisc replace dev $i root netem delay 200ms 20ms loss 5% rate 1mbit")
+}
+
+func TestChaosDisabledDoesNotApplyNetem(t *testing.T) {
+	ch, md := setupChaos()
+	ch.Enabled = false
+	p := NewChaos(ch, md, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	md.AssertNotCalled(t, "ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestChaosNetworkTargetAppliesToAttachedContainers(t *testing.T) {
+	ch, md := setupChaos()
+	ch.Targets = []string{"network.local", "container.db"}
+	p := NewChaos(ch, md, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	md.AssertNumberOfCalls(t, "CreateContainer", 2)
+	md.AssertNumberOfCalls(t, "ExecuteCommand", 2)
+}
+
+func TestChaosDestroyRemovesHelpers(t *testing.T) {
+	ch, md := setupChaos()
+	p := NewChaos(ch, md, hclog.NewNullLogger())
+
+	err := p.Destroy()
+	require.NoError(t, err)
+
+	md.AssertCalled(t, "FindContainerIDs", "slow-db", config.TypeChaos)
+	md.AssertCalled(t, "RemoveContainer", "abc")
+}
@@ -1,10 +1,40 @@
 package providers
 
 import (
+	"context"
 	"errors"
+	"sync"
+
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
 )
 
 var (
 	ErrorClusterDriverNotImplemented = errors.New("driver not implemented")
 	ErrorClusterExists               = errors.New("cluster exists")
 )
+
+// pullImages pulls the images to import into a cluster in parallel, the
+// first error encountered is returned once all pulls have completed
+func pullImages(ctx context.Context, client clients.ContainerTasks, images []config.Image) error {
+	wg := sync.WaitGroup{}
+	errs := make(chan error, len(images))
+
+	for _, i := range images {
+		wg.Add(1)
+
+		go func(i config.Image) {
+			defer wg.Done()
+
+			err := client.PullImage(ctx, i, false)
+			if err != nil {
+				errs <- err
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	return <-errs
+}
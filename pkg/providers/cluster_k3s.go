@@ -2,6 +2,7 @@ package providers
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -24,6 +25,9 @@ const k3sBaseImage = "rancher/k3s"
 
 var startTimeout = (300 * time.Second)
 
+// k3sClusterSecret is the secret agents use to join the server
+const k3sClusterSecret = "mysupersecret" // This should be random
+
 // K8sCluster defines a provider which can create Kubernetes clusters
 type K8sCluster struct {
 	config     *config.K8sCluster
@@ -39,31 +43,76 @@ func NewK8sCluster(c *config.K8sCluster, cc clients.ContainerTasks, kc clients.K
 }
 
 // Create implements interface method to create a cluster of the specified type
-func (c *K8sCluster) Create() error {
+func (c *K8sCluster) Create(ctx context.Context) error {
+	var err error
+
 	switch c.config.Driver {
 	case "k3s":
-		return c.createK3s()
+		err = c.createK3s(ctx)
+	case "kind":
+		err = c.createKind(ctx)
 	default:
 		return ErrorClusterDriverNotImplemented
 	}
+
+	if err != nil {
+		return err
+	}
+
+	// exec checks run in the server node
+	return runHealthCheck(c.config.HealthCheck, c.httpClient, execInContainer(c.client, fmt.Sprintf("server.%s", c.config.Name), c.config.Type, c.log), c.log)
 }
 
 // Destroy implements interface method to destroy a cluster
 func (c *K8sCluster) Destroy() error {
 	switch c.config.Driver {
 	case "k3s":
-		return c.destroyK3s()
+		return c.destroyNodes()
+	case "kind":
+		return c.destroyKind()
 	default:
 		return ErrorClusterDriverNotImplemented
 	}
 }
 
-// Lookup the a clusters current state
+// Lookup the IDs of the server and agent containers for the cluster
 func (c *K8sCluster) Lookup() ([]string, error) {
-	return c.client.FindContainerIDs(fmt.Sprintf("server.%s", c.config.Name), c.config.Type)
+	ids := []string{}
+
+	for _, n := range c.nodeNames() {
+		i, err := c.client.FindContainerIDs(n, c.config.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, i...)
+	}
+
+	return ids, nil
+}
+
+// Drifted returns true when any of the node containers have been removed
+func (c *K8sCluster) Drifted() (bool, error) {
+	ids, err := c.Lookup()
+	if err != nil {
+		return false, err
+	}
+
+	return len(ids) < len(c.nodeNames()), nil
 }
 
-func (c *K8sCluster) createK3s() error {
+// nodeNames returns the names of the server and agent containers
+func (c *K8sCluster) nodeNames() []string {
+	names := []string{fmt.Sprintf("server.%s", c.config.Name)}
+
+	for i := 1; i <= c.config.Agents(); i++ {
+		names = append(names, fmt.Sprintf("agent-%d.%s", i, c.config.Name))
+	}
+
+	return names
+}
+
+func (c *K8sCluster) createK3s(ctx context.Context) error {
 	c.log.Info("Creating Cluster", "ref", c.config.Name)
 
 	// check the cluster does not already exist
@@ -80,7 +129,7 @@ func (c *K8sCluster) createK3s() error {
 	image := fmt.Sprintf("%s:%s", k3sBaseImage, c.config.Version)
 
 	// pull the container image
-	err = c.client.PullImage(config.Image{Name: image}, false)
+	err = c.client.PullImage(ctx, config.Image{Name: image}, false)
 	if err != nil {
 		return err
 	}
@@ -99,6 +148,7 @@ func (c *K8sCluster) createK3s() error {
 	cc.Image = config.Image{Name: image}
 	cc.Networks = c.config.Networks
 	cc.Privileged = true // k3s must run Privlidged
+	cc.Resources = c.config.Resources
 
 	// set the volume mount for the images
 	cc.Volumes = []config.Volume{
@@ -109,16 +159,36 @@ func (c *K8sCluster) createK3s() error {
 		},
 	}
 
+	// pull images through the image cache when the cluster shares a network with one
+	if ic := findImageCache(&c.config.ResourceInfo, c.config.Networks); ic != nil {
+		dir, _, _ := utils.CreateKubeConfigPath(c.config.Name)
+
+		reg, err := writeK3sRegistries(dir, ic)
+		if err != nil {
+			return xerrors.Errorf("Unable to write registry config for image cache: %w", err)
+		}
+
+		cc.Volumes = append(cc.Volumes, config.Volume{Source: reg, Destination: "/etc/rancher/k3s/registries.yaml"})
+	}
+
+	// agents mount the same volumes as the server
+	volumes := cc.Volumes
+
 	// set the environment variables for the K3S_KUBECONFIG_OUTPUT and K3S_CLUSTER_SECRET
 	cc.Environment = []config.KV{
 		config.KV{Key: "K3S_KUBECONFIG_OUTPUT", Value: "/output/kubeconfig.yaml"},
-		config.KV{Key: "K3S_CLUSTER_SECRET", Value: "mysupersecret"}, // This should be random
+		config.KV{Key: "K3S_CLUSTER_SECRET", Value: k3sClusterSecret},
 	}
 
 	// set the API server port to a random number 64000 - 65000
 	apiPort := rand.Intn(1000) + 64000
 	args := []string{"server", fmt.Sprintf("--https-listen-port=%d", apiPort)}
 
+	// the certificate must be valid for the remote machine
+	if c.config.DockerHost != "" {
+		args = append(args, fmt.Sprintf("--tls-san=%s", c.apiHost()))
+	}
+
 	// expose the API server port
 	cc.Ports = []config.Port{
 		config.Port{
@@ -143,6 +213,17 @@ func (c *K8sCluster) createK3s() error {
 		return err
 	}
 
+	// agents can only join once the server has started
+	nodeIDs := []string{id}
+	for i := 1; i <= c.config.Agents(); i++ {
+		aid, err := c.createAgent(fmt.Sprintf("agent-%d.%s", i, c.config.Name), image, volumes, apiPort)
+		if err != nil {
+			return xerrors.Errorf("Unable to create agent: %w", err)
+		}
+
+		nodeIDs = append(nodeIDs, aid)
+	}
+
 	// get the Kubernetes config file and drop it in $HOME/.shipyard/config/[clustername]/kubeconfig.yml
 	kc, err := c.copyKubeConfig(id)
 	if err != nil {
@@ -182,16 +263,47 @@ func (c *K8sCluster) createK3s() error {
 
 	// import the images to the servers container d instance
 	// importing images means that k3s does not need to pull from a remote docker hub
+	// images are imported into every node so that pods can be scheduled
+	// on any node
 	if c.config.Images != nil && len(c.config.Images) > 0 {
-		err := c.ImportLocalDockerImages(utils.ImageVolumeName, id, c.config.Images, false)
-		if err != nil {
-			return xerrors.Errorf("Error importing Docker images: %w", err)
+		for _, nid := range nodeIDs {
+			err := c.ImportLocalDockerImages(ctx, utils.ImageVolumeName, nid, c.config.Images, false)
+			if err != nil {
+				return xerrors.Errorf("Error importing Docker images: %w", err)
+			}
 		}
 	}
 
 	return nil
 }
 
+// createAgent creates an agent container which joins the cluster and waits
+// for it to start
+func (c *K8sCluster) createAgent(name, image string, volumes []config.Volume, apiPort int) (string, error) {
+	c.log.Debug("Creating Cluster agent", "ref", c.config.Name, "agent", name)
+
+	cc := config.NewContainer(name)
+	c.config.ResourceInfo.AddChild(cc)
+
+	cc.Image = config.Image{Name: image}
+	cc.Networks = c.config.Networks
+	cc.Privileged = true
+	cc.Resources = c.config.Resources
+	cc.Volumes = volumes
+	cc.Command = []string{"agent"}
+	cc.Environment = []config.KV{
+		config.KV{Key: "K3S_URL", Value: fmt.Sprintf("https://server.%s:%d", utils.FQDN(c.config.Name, string(c.config.Type)), apiPort)},
+		config.KV{Key: "K3S_CLUSTER_SECRET", Value: k3sClusterSecret},
+	}
+
+	id, err := c.client.CreateContainer(cc)
+	if err != nil {
+		return "", err
+	}
+
+	return id, c.waitForStart(id)
+}
+
 func (c *K8sCluster) waitForStart(id string) error {
 	start := time.Now()
 
@@ -235,6 +347,22 @@ func (c *K8sCluster) copyKubeConfig(id string) (string, error) {
 		return "", err
 	}
 
+	// the API server of a cluster on a remote Docker engine is reached
+	// using the address of the remote machine
+	if c.config.DockerHost != "" {
+		d, err := ioutil.ReadFile(destPath)
+		if err != nil {
+			return "", err
+		}
+
+		d = []byte(strings.Replace(string(d), "server: https://127.0.0.1", fmt.Sprintf("server: https://%s", c.apiHost()), -1))
+
+		err = ioutil.WriteFile(destPath, d, 0644)
+		if err != nil {
+			return "", err
+		}
+	}
+
 	return destPath, nil
 }
 
@@ -254,7 +382,7 @@ func (c *K8sCluster) createDockerKubeConfig(kubeconfig string) error {
 	// manipulate the file
 	newConfig := strings.Replace(
 		string(readBytes),
-		"server: https://127.0.0.1",
+		fmt.Sprintf("server: https://%s", c.apiHost()),
 		fmt.Sprintf("server: https://server.%s", utils.FQDN(c.config.Name, string(c.config.Type))),
 		-1,
 	)
@@ -272,16 +400,25 @@ func (c *K8sCluster) createDockerKubeConfig(kubeconfig string) error {
 	return nil
 }
 
+// importCommand returns the command which imports an image into containerd,
+// kind nodes run containerd directly so the Kubernetes namespace must be set
+func (c *K8sCluster) importCommand(file string) []string {
+	if c.config.Driver == "kind" {
+		return []string{"ctr", "--namespace=k8s.io", "images", "import", "/images/" + file}
+	}
+
+	return []string{"ctr", "image", "import", "/images/" + file}
+}
+
 // ImportLocalDockerImages fetches Docker images stored on the local client and imports them into the cluster
-func (c *K8sCluster) ImportLocalDockerImages(name string, id string, images []config.Image, force bool) error {
-	imgs := []string{}
+func (c *K8sCluster) ImportLocalDockerImages(ctx context.Context, name string, id string, images []config.Image, force bool) error {
+	err := pullImages(ctx, c.client, images)
+	if err != nil {
+		return err
+	}
 
+	imgs := []string{}
 	for _, i := range images {
-		err := c.client.PullImage(i, false)
-		if err != nil {
-			return err
-		}
-
 		imgs = append(imgs, i.Name)
 	}
 
@@ -295,7 +432,7 @@ func (c *K8sCluster) ImportLocalDockerImages(name string, id string, images []co
 	for _, i := range imagesFile {
 		// execute the command to import the image
 		// write any command output to the logger
-		err = c.client.ExecuteCommand(id, []string{"ctr", "image", "import", "/images/" + i}, nil, "/", c.log.StandardWriter(&hclog.StandardLoggerOptions{ForceLevel: hclog.Debug}))
+		err = c.client.ExecuteCommand(id, c.importCommand(i), nil, "/", c.log.StandardWriter(&hclog.StandardLoggerOptions{ForceLevel: hclog.Debug}))
 		if err != nil {
 			return err
 		}
@@ -304,10 +441,11 @@ func (c *K8sCluster) ImportLocalDockerImages(name string, id string, images []co
 	return nil
 }
 
-func (c *K8sCluster) destroyK3s() error {
+// destroyNodes removes the server and agent containers
+func (c *K8sCluster) destroyNodes() error {
 	c.log.Info("Destroy Cluster", "ref", c.config.Name)
 
-	ids, err := c.client.FindContainerIDs(fmt.Sprintf("server.%s", c.config.Name), c.config.Type)
+	ids, err := c.Lookup()
 	if err != nil {
 		return err
 	}
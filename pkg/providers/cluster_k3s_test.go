@@ -2,6 +2,7 @@ package providers
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -74,7 +75,7 @@ func TestClusterK3ErrorsWhenUnableToLookupIDs(t *testing.T) {
 	mk := &mocks.MockKubernetes{}
 	p := NewK8sCluster(clusterConfig, md, mk, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -85,7 +86,7 @@ func TestClusterK3ErrorsWhenClusterExists(t *testing.T) {
 	mk := &mocks.MockKubernetes{}
 	p := NewK8sCluster(clusterConfig, md, mk, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -95,7 +96,7 @@ func TestClusterK3PullsImage(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "PullImage", config.Image{Name: "rancher/k3s:v1.0.0"}, false)
 }
@@ -106,7 +107,7 @@ func TestClusterK3CreatesANewVolume(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "CreateVolume", utils.ImageVolumeName)
 }
@@ -120,7 +121,7 @@ func TestClusterK3FailsWhenUnableToCreatesANewVolume(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 	md.AssertCalled(t, "CreateVolume", utils.ImageVolumeName)
 }
@@ -131,7 +132,7 @@ func TestClusterK3CreatesAServer(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
@@ -158,6 +159,83 @@ func TestClusterK3CreatesAServer(t *testing.T) {
 	assert.Contains(t, params.Command[2], "traefik")
 }
 
+func TestClusterK3CreatesAgentsWhenNodesSet(t *testing.T) {
+	cc, md, mk, cleanup := setupClusterMocks()
+	defer cleanup()
+
+	cc.Nodes = 3
+	cc.Resources = &config.Resources{Memory: 1024}
+
+	// each node reads the logs to check that it has started
+	removeOn(&md.Mock, "ContainerLogs")
+	for i := 0; i < 3; i++ {
+		md.On("ContainerLogs", mock.Anything, true, true).Return(
+			ioutil.NopCloser(bytes.NewBufferString("Running kubelet")),
+			nil,
+		).Once()
+	}
+
+	p := NewK8sCluster(cc, md, mk, nil, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	calls := getCalls(&md.Mock, "CreateContainer")
+	assert.Len(t, calls, 3)
+
+	server := calls[0].Arguments[0].(*config.Container)
+	assert.Equal(t, 1024, server.Resources.Memory)
+
+	agent := calls[2].Arguments[0].(*config.Container)
+	assert.Equal(t, "agent-2.test", agent.Name)
+	assert.Equal(t, []string{"agent"}, agent.Command)
+	assert.True(t, agent.Privileged)
+	assert.Equal(t, "/images", agent.Volumes[0].Destination)
+	assert.Equal(t, 1024, agent.Resources.Memory)
+	assert.Equal(t, "K3S_URL", agent.Environment[0].Key)
+	assert.Equal(t, fmt.Sprintf("https://server.test.k8s_cluster.shipyard.run:%s", server.Ports[0].Local), agent.Environment[0].Value)
+}
+
+func TestClusterK3sDestroyRemovesAgents(t *testing.T) {
+	cc, md, mk, cleanup := setupClusterMocks()
+	defer cleanup()
+
+	cc.Nodes = 2
+
+	removeOn(&md.Mock, "FindContainerIDs")
+	md.On("FindContainerIDs", "server.test", mock.Anything).Return([]string{"server"}, nil)
+	md.On("FindContainerIDs", "agent-1.test", mock.Anything).Return([]string{"agent"}, nil)
+
+	p := NewK8sCluster(cc, md, mk, nil, hclog.NewNullLogger())
+
+	err := p.Destroy()
+	assert.NoError(t, err)
+	md.AssertCalled(t, "RemoveContainer", "server")
+	md.AssertCalled(t, "RemoveContainer", "agent")
+}
+
+func TestClusterK3sMountsRegistriesWhenImageCacheExists(t *testing.T) {
+	cc, md, mk, cleanup := setupClusterMocks()
+	defer cleanup()
+
+	ic := config.NewImageCache("docker")
+	ic.Networks = cc.Networks
+	cc.Config.AddResource(ic)
+
+	p := NewK8sCluster(cc, md, mk, nil, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+	assert.Equal(t, "/etc/rancher/k3s/registries.yaml", params.Volumes[1].Destination)
+
+	d, err := ioutil.ReadFile(params.Volumes[1].Source)
+	assert.NoError(t, err)
+	assert.Contains(t, string(d), `"docker.io"`)
+	assert.Contains(t, string(d), `"http://docker.image_cache.shipyard.run:5000"`)
+}
+
 func TestClusterK3sErrorsIfServerNOTStart(t *testing.T) {
 	cc, md, mk, cleanup := setupClusterMocks()
 	defer cleanup()
@@ -171,7 +249,7 @@ func TestClusterK3sErrorsIfServerNOTStart(t *testing.T) {
 	p := NewK8sCluster(cc, md, mk, nil, hclog.NewNullLogger())
 	startTimeout = 10 * time.Millisecond // reset the startTimeout, do not want to wait 120s
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -181,7 +259,7 @@ func TestClusterK3sDownloadsConfig(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	_, destPath, _ := utils.CreateKubeConfigPath(clusterConfig.Name)
@@ -200,7 +278,7 @@ func TestClusterK3sRaisesErrorWhenUnableToDownloadConfig(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -210,7 +288,7 @@ func TestClusterK3sCreatesDockerConfig(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	// check the kubeconfig file for docker uses a network ip not localhost
@@ -227,13 +305,37 @@ func TestClusterK3sCreatesDockerConfig(t *testing.T) {
 	assert.Contains(t, string(d), fmt.Sprintf("server.%s", utils.FQDN(clusterConfig.Name, string(clusterConfig.Type))))
 }
 
+func TestClusterK3sOnRemoteDockerHostUsesRemoteAddress(t *testing.T) {
+	cc, md, mk, cleanup := setupClusterMocks()
+	defer cleanup()
+
+	cc.DockerHost = "tcp://10.5.0.2:2376"
+
+	p := NewK8sCluster(cc, md, mk, nil, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+	assert.Contains(t, params.Command, "--tls-san=10.5.0.2")
+
+	_, destPath, dockerPath := utils.CreateKubeConfigPath(clusterConfig.Name)
+	d, err := ioutil.ReadFile(destPath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(d), "server: https://10.5.0.2:64674")
+
+	d, err = ioutil.ReadFile(dockerPath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(d), fmt.Sprintf("server: https://server.%s:64674", utils.FQDN(clusterConfig.Name, string(clusterConfig.Type))))
+}
+
 func TestClusterK3sCreatesKubeClient(t *testing.T) {
 	cc, md, mk, cleanup := setupClusterMocks()
 	defer cleanup()
 
 	p := NewK8sCluster(cc, md, mk, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	mk.AssertCalled(t, "SetConfig", mock.Anything)
 }
@@ -247,7 +349,7 @@ func TestClusterK3sErrorsWhenFailedToCreateKubeClient(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -257,7 +359,7 @@ func TestClusterK3sWaitsForPods(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	mk.AssertCalled(t, "HealthCheckPods", []string{""}, startTimeout)
 }
@@ -270,7 +372,7 @@ func TestClusterK3sErrorsWhenWaitsForPodsFail(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -280,19 +382,34 @@ func TestClusterK3sImportDockerImagesPullsImages(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "PullImage", clusterConfig.Images[0], false)
 	md.AssertCalled(t, "PullImage", clusterConfig.Images[1], false)
 }
 
+func TestClusterK3sImportDockerImagesPullErrorReturnsError(t *testing.T) {
+	cc, md, mk, cleanup := setupClusterMocks()
+	defer cleanup()
+
+	removeOn(&md.Mock, "PullImage")
+	md.On("PullImage", clusterConfig.Images[1], false).Return(fmt.Errorf("boom"))
+	md.On("PullImage", mock.Anything, mock.Anything).Return(nil)
+
+	p := NewK8sCluster(cc, md, mk, nil, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+	md.AssertNotCalled(t, "CopyLocalDockerImageToVolume", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestClusterK3sImportDockerCopiesImages(t *testing.T) {
 	cc, md, mk, cleanup := setupClusterMocks()
 	defer cleanup()
 
 	p := NewK8sCluster(cc, md, mk, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "CopyLocalDockerImageToVolume", []string{"consul:1.6.1", "vault:1.6.1"}, utils.FQDNVolumeName(utils.ImageVolumeName), false)
 }
@@ -304,7 +421,7 @@ func TestClusterK3sImportDockerCopyImageFailReturnsError(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -313,7 +430,7 @@ func TestClusterK3sImportDockerRunsExecCommand(t *testing.T) {
 	defer cleanup()
 
 	p := NewK8sCluster(cc, md, mk, nil, hclog.NewNullLogger())
-	err := p.Create()
+	err := p.Create(context.Background())
 
 	assert.NoError(t, err)
 	md.AssertCalled(t, "ExecuteCommand", "containerid", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
@@ -327,7 +444,7 @@ func TestClusterK3sImportDockerExecFailReturnsError(t *testing.T) {
 
 	p := NewK8sCluster(cc, md, mk, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -0,0 +1,298 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"golang.org/x/xerrors"
+)
+
+// https://github.com/kubernetes-sigs/kind/tree/master/pkg/cluster/internal/providers/docker
+
+const kindBaseImage = "kindest/node"
+
+// kindAPIPort is the port the API server listens on inside the node
+const kindAPIPort = 6443
+
+// kindToken is the bootstrap token agents use to join the server
+const kindToken = "abcdef.0123456789abcdef"
+
+// kindPodSubnet is the subnet used by the default CNI
+const kindPodSubnet = "10.244.0.0/16"
+
+// kindKubeConfig is the kubeadm config for the control plane node
+const kindKubeConfig = `apiVersion: kubeadm.k8s.io/v1beta2
+kind: ClusterConfiguration
+kubernetesVersion: %s
+clusterName: %s
+controlPlaneEndpoint: %s:%d
+apiServer:
+  certSANs:
+  - localhost
+  - %s
+  - %s
+networking:
+  podSubnet: %s
+---
+apiVersion: kubeadm.k8s.io/v1beta2
+kind: InitConfiguration
+bootstrapTokens:
+- token: %s
+nodeRegistration:
+  criSocket: unix:///run/containerd/containerd.sock
+  kubeletExtraArgs:
+    fail-swap-on: "false"
+---
+apiVersion: kubelet.config.k8s.io/v1beta1
+kind: KubeletConfiguration
+failSwapOn: false
+evictionHard:
+  imagefs.available: "0%%"
+  nodefs.available: "0%%"
+  nodefs.inodesFree: "0%%"
+`
+
+// kindWaitForContainerd waits for systemd in the node to start containerd
+const kindWaitForContainerd = `for i in $(seq 1 60); do systemctl is-active --quiet containerd && exit 0; sleep 1; done; exit 1`
+
+func (c *K8sCluster) createKind(ctx context.Context) error {
+	c.log.Info("Creating Cluster", "ref", c.config.Name, "driver", "kind")
+
+	// check the cluster does not already exist
+	ids, err := c.client.FindContainerIDs(fmt.Sprintf("server.%s", c.config.Name), c.config.Type)
+	if err != nil {
+		return err
+	}
+
+	if len(ids) > 0 {
+		return ErrorClusterExists
+	}
+
+	image := fmt.Sprintf("%s:%s", kindBaseImage, c.config.Version)
+
+	err = c.client.PullImage(ctx, config.Image{Name: image}, false)
+	if err != nil {
+		return err
+	}
+
+	volID, err := c.client.CreateVolume("images")
+	if err != nil {
+		return err
+	}
+
+	// write the kubeadm config to the config folder for the cluster
+	dir, _, _ := utils.CreateKubeConfigPath(c.config.Name)
+	kubeadmConfig := filepath.Join(dir, "kubeadm.yaml")
+
+	err = ioutil.WriteFile(kubeadmConfig, []byte(c.kindKubeadmConfig()), 0644)
+	if err != nil {
+		return xerrors.Errorf("Unable to write kubeadm config: %w", err)
+	}
+
+	// set the API server port to a random number 64000 - 65000
+	apiPort := rand.Intn(1000) + 64000
+
+	sc, err := c.createKindNode(fmt.Sprintf("server.%s", c.config.Name), image, volID)
+	if err != nil {
+		return err
+	}
+
+	sc.Volumes = append(sc.Volumes, config.Volume{Source: kubeadmConfig, Destination: "/kind/shipyard-kubeadm.yaml"})
+	sc.Ports = []config.Port{
+		config.Port{
+			Local:    fmt.Sprintf("%d", kindAPIPort),
+			Host:     fmt.Sprintf("%d", apiPort),
+			Protocol: "tcp",
+		},
+	}
+
+	id, err := c.client.CreateContainer(sc)
+	if err != nil {
+		return err
+	}
+
+	err = c.kindExec(id,
+		kindWaitForContainerd,
+		"kubeadm init --ignore-preflight-errors=all --config=/kind/shipyard-kubeadm.yaml --skip-token-print",
+		fmt.Sprintf("sed 's|{{ .PodSubnet }}|%s|g' /kind/manifests/default-cni.yaml | kubectl --kubeconfig=/etc/kubernetes/admin.conf apply -f -", kindPodSubnet),
+		"kubectl --kubeconfig=/etc/kubernetes/admin.conf apply -f /kind/manifests/default-storage.yaml",
+	)
+	if err != nil {
+		return xerrors.Errorf("Unable to start control plane: %w", err)
+	}
+
+	nodeIDs := []string{id}
+	for i := 1; i <= c.config.Agents(); i++ {
+		ac, err := c.createKindNode(fmt.Sprintf("agent-%d.%s", i, c.config.Name), image, volID)
+		if err != nil {
+			return err
+		}
+
+		aid, err := c.client.CreateContainer(ac)
+		if err != nil {
+			return xerrors.Errorf("Unable to create agent: %w", err)
+		}
+
+		err = c.kindExec(aid,
+			kindWaitForContainerd,
+			fmt.Sprintf(
+				"kubeadm join %s:%d --token %s --discovery-token-unsafe-skip-ca-verification --ignore-preflight-errors=all",
+				c.serverFQDN(), kindAPIPort, kindToken,
+			),
+		)
+		if err != nil {
+			return xerrors.Errorf("Unable to join agent to cluster: %w", err)
+		}
+
+		nodeIDs = append(nodeIDs, aid)
+	}
+
+	// workloads can only be scheduled on the control plane of a single
+	// node cluster
+	if c.config.Agents() == 0 {
+		c.kindExec(id, "kubectl --kubeconfig=/etc/kubernetes/admin.conf taint nodes --all node-role.kubernetes.io/master- || true")
+	}
+
+	kc, err := c.copyKindKubeConfig(id, apiPort)
+	if err != nil {
+		return xerrors.Errorf("Error copying Kubernetes config: %w", err)
+	}
+
+	err = c.kubeClient.SetConfig(kc)
+	if err != nil {
+		return err
+	}
+
+	err = c.kubeClient.HealthCheckPods([]string{""}, startTimeout)
+	if err != nil {
+		return xerrors.Errorf("Error while waiting for Kubernetes default pods: %w", err)
+	}
+
+	if len(c.config.Images) > 0 {
+		for _, nid := range nodeIDs {
+			err := c.ImportLocalDockerImages(ctx, utils.ImageVolumeName, nid, c.config.Images, false)
+			if err != nil {
+				return xerrors.Errorf("Error importing Docker images: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// createKindNode returns the container config for a kind node, nodes run
+// systemd and store the container images in a volume as overlayfs can not
+// be nested
+func (c *K8sCluster) createKindNode(name, image, imagesVolume string) (*config.Container, error) {
+	varVol, err := c.client.CreateVolume(fmt.Sprintf("%s-var", name))
+	if err != nil {
+		return nil, err
+	}
+
+	cc := config.NewContainer(name)
+	c.config.ResourceInfo.AddChild(cc)
+
+	cc.Image = config.Image{Name: image}
+	cc.Networks = c.config.Networks
+	cc.Privileged = true
+	cc.Resources = c.config.Resources
+	cc.Volumes = []config.Volume{
+		config.Volume{Source: imagesVolume, Destination: "/images", Type: "volume"},
+		config.Volume{Source: varVol, Destination: "/var", Type: "volume"},
+		config.Volume{Destination: "/tmp", Type: "tmpfs"},
+		config.Volume{Destination: "/run", Type: "tmpfs"},
+	}
+
+	// kernel modules are only available when Docker runs on a Linux host
+	if _, err := os.Stat("/lib/modules"); err == nil {
+		cc.Volumes = append(cc.Volumes, config.Volume{Source: "/lib/modules", Destination: "/lib/modules"})
+	}
+
+	return cc, nil
+}
+
+func (c *K8sCluster) kindExec(id string, commands ...string) error {
+	for _, cmd := range commands {
+		c.log.Debug("Executing command in node", "ref", c.config.Name, "command", cmd)
+
+		err := c.client.ExecuteCommand(id, []string{"sh", "-c", cmd}, nil, "/", c.log.StandardWriter(&hclog.StandardLoggerOptions{ForceLevel: hclog.Debug}))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// copyKindKubeConfig copies the admin config from the control plane, the
+// server in the local config is the API port on the host and the server
+// in the Docker config is the API port of the node
+func (c *K8sCluster) copyKindKubeConfig(id string, apiPort int) (string, error) {
+	_, destPath, dockerPath := utils.CreateKubeConfigPath(c.config.Name)
+
+	err := c.client.CopyFromContainer(id, "/etc/kubernetes/admin.conf", dockerPath)
+	if err != nil {
+		return "", err
+	}
+
+	d, err := ioutil.ReadFile(dockerPath)
+	if err != nil {
+		return "", err
+	}
+
+	local := strings.Replace(
+		string(d),
+		fmt.Sprintf("server: https://%s:%d", c.serverFQDN(), kindAPIPort),
+		fmt.Sprintf("server: https://%s:%d", c.apiHost(), apiPort),
+		-1,
+	)
+
+	err = ioutil.WriteFile(destPath, []byte(local), 0644)
+	if err != nil {
+		return "", err
+	}
+
+	return destPath, nil
+}
+
+func (c *K8sCluster) kindKubeadmConfig() string {
+	fqdn := c.serverFQDN()
+
+	return fmt.Sprintf(kindKubeConfig,
+		c.config.Version, c.config.Name, fqdn, kindAPIPort, c.apiHost(), fqdn, kindPodSubnet, kindToken,
+	)
+}
+
+func (c *K8sCluster) serverFQDN() string {
+	return fmt.Sprintf("server.%s", utils.FQDN(c.config.Name, string(c.config.Type)))
+}
+
+// apiHost returns the address the API server port is published on, for
+// clusters on a remote Docker engine this is the remote machine
+func (c *K8sCluster) apiHost() string {
+	return utils.GetDockerHostAddress(c.config.DockerHost)
+}
+
+func (c *K8sCluster) destroyKind() error {
+	err := c.destroyNodes()
+	if err != nil {
+		return err
+	}
+
+	for _, n := range c.nodeNames() {
+		err := c.client.RemoveVolume(fmt.Sprintf("%s-var", n))
+		if err != nil {
+			c.log.Debug("Unable to remove volume", "ref", c.config.Name, "node", n, "error", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package providers
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupKindClusterMocks(t *testing.T) (*config.K8sCluster, *mocks.MockContainerTasks, *mocks.MockKubernetes, func()) {
+	cc, md, mk, cleanup := setupClusterMocks()
+	cc.Driver = "kind"
+	cc.Version = "v1.19.1"
+
+	// the admin config written by kubeadm
+	removeOn(&md.Mock, "CopyFromContainer")
+	md.On("CopyFromContainer", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		ioutil.WriteFile(args.String(2), []byte("server: https://server.test.k8s_cluster.shipyard.run:6443"), 0644)
+	}).Return(nil)
+
+	return cc, md, mk, cleanup
+}
+
+func TestClusterKindCreatesAServer(t *testing.T) {
+	cc, md, mk, cleanup := setupKindClusterMocks(t)
+	defer cleanup()
+
+	p := NewK8sCluster(cc, md, mk, nil, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	md.AssertCalled(t, "PullImage", config.Image{Name: "kindest/node:v1.19.1"}, false)
+
+	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+	assert.Equal(t, "server.test", params.Name)
+	assert.True(t, params.Privileged)
+	assert.Equal(t, "/var", params.Volumes[1].Destination)
+	assert.Equal(t, "tmpfs", params.Volumes[2].Type)
+	assert.Equal(t, "6443", params.Ports[0].Local)
+
+	// kubeadm config is mounted into the node
+	kc := params.Volumes[len(params.Volumes)-1]
+	assert.Equal(t, "/kind/shipyard-kubeadm.yaml", kc.Destination)
+
+	d, err := ioutil.ReadFile(kc.Source)
+	require.NoError(t, err)
+	assert.Contains(t, string(d), "kubernetesVersion: v1.19.1")
+	assert.Contains(t, string(d), "controlPlaneEndpoint: server.test.k8s_cluster.shipyard.run:6443")
+	assert.Contains(t, string(d), `nodefs.available: "0%"`)
+
+	exec := getCalls(&md.Mock, "ExecuteCommand")[1].Arguments[1].([]string)
+	assert.Contains(t, exec[2], "kubeadm init")
+}
+
+func TestClusterKindWritesKubeConfigs(t *testing.T) {
+	cc, md, mk, cleanup := setupKindClusterMocks(t)
+	defer cleanup()
+
+	p := NewK8sCluster(cc, md, mk, nil, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+	_, local, docker := utils.CreateKubeConfigPath(cc.Name)
+
+	d, err := ioutil.ReadFile(local)
+	require.NoError(t, err)
+	assert.Equal(t, "server: https://127.0.0.1:"+params.Ports[0].Host, string(d))
+
+	d, err = ioutil.ReadFile(docker)
+	require.NoError(t, err)
+	assert.Equal(t, "server: https://server.test.k8s_cluster.shipyard.run:6443", string(d))
+
+	mk.AssertCalled(t, "SetConfig", local)
+}
+
+func TestClusterKindJoinsAgents(t *testing.T) {
+	cc, md, mk, cleanup := setupKindClusterMocks(t)
+	defer cleanup()
+
+	cc.Nodes = 2
+
+	p := NewK8sCluster(cc, md, mk, nil, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	params := getCalls(&md.Mock, "CreateContainer")[1].Arguments[0].(*config.Container)
+	assert.Equal(t, "agent-1.test", params.Name)
+
+	joined := false
+	for _, c := range getCalls(&md.Mock, "ExecuteCommand") {
+		if cmd := c.Arguments[1].([]string); len(cmd) == 3 && strings.HasPrefix(cmd[2], "kubeadm join server.test.k8s_cluster.shipyard.run:6443") {
+			joined = true
+		}
+	}
+
+	assert.True(t, joined)
+}
+
+func TestClusterKindImportsImagesIntoKubernetesNamespace(t *testing.T) {
+	cc, md, mk, cleanup := setupKindClusterMocks(t)
+	defer cleanup()
+
+	p := NewK8sCluster(cc, md, mk, nil, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	calls := getCalls(&md.Mock, "ExecuteCommand")
+	cmd := calls[len(calls)-1].Arguments[1].([]string)
+	assert.Equal(t, []string{"ctr", "--namespace=k8s.io", "images", "import"}, cmd[:4])
+}
+
+func TestClusterKindDestroyRemovesVolumes(t *testing.T) {
+	cc, md, mk, cleanup := setupKindClusterMocks(t)
+	defer cleanup()
+
+	p := NewK8sCluster(cc, md, mk, nil, hclog.NewNullLogger())
+
+	err := p.Destroy()
+	require.NoError(t, err)
+
+	md.AssertCalled(t, "RemoveVolume", "server.test-var")
+}
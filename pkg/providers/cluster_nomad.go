@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 
@@ -19,17 +20,24 @@ type NomadCluster struct {
 	config      *config.NomadCluster
 	client      clients.ContainerTasks
 	nomadClient clients.Nomad
+	httpClient  clients.HTTP
 	log         hclog.Logger
 }
 
 // NewNomadCluster creates a new Nomad cluster provider
-func NewNomadCluster(c *config.NomadCluster, cc clients.ContainerTasks, hc clients.Nomad, l hclog.Logger) *NomadCluster {
-	return &NomadCluster{c, cc, hc, l}
+func NewNomadCluster(c *config.NomadCluster, cc clients.ContainerTasks, nc clients.Nomad, hc clients.HTTP, l hclog.Logger) *NomadCluster {
+	return &NomadCluster{c, cc, nc, hc, l}
 }
 
 // Create implements interface method to create a cluster of the specified type
-func (c *NomadCluster) Create() error {
-	return c.createNomad()
+func (c *NomadCluster) Create(ctx context.Context) error {
+	err := c.createNomad(ctx)
+	if err != nil {
+		return err
+	}
+
+	// exec checks run in the server node
+	return runHealthCheck(c.config.HealthCheck, c.httpClient, execInContainer(c.client, fmt.Sprintf("server.%s", c.config.Name), c.config.Type, c.log), c.log)
 }
 
 // Destroy implements interface method to destroy a cluster
@@ -42,7 +50,17 @@ func (c *NomadCluster) Lookup() ([]string, error) {
 	return c.client.FindContainerIDs(fmt.Sprintf("server.%s", c.config.Name), c.config.Type)
 }
 
-func (c *NomadCluster) createNomad() error {
+// Drifted returns true when the server container has been removed
+func (c *NomadCluster) Drifted() (bool, error) {
+	ids, err := c.Lookup()
+	if err != nil {
+		return false, err
+	}
+
+	return len(ids) == 0, nil
+}
+
+func (c *NomadCluster) createNomad(ctx context.Context) error {
 	c.log.Info("Creating Cluster", "ref", c.config.Name)
 
 	// check the cluster does not already exist
@@ -64,7 +82,7 @@ func (c *NomadCluster) createNomad() error {
 	image := fmt.Sprintf("%s:%s", nomadBaseImage, c.config.Version)
 
 	// pull the container image
-	err = c.client.PullImage(config.Image{Name: image}, false)
+	err = c.client.PullImage(ctx, config.Image{Name: image}, false)
 	if err != nil {
 		return err
 	}
@@ -98,6 +116,22 @@ func (c *NomadCluster) createNomad() error {
 		cc.Volumes = append(cc.Volumes, v)
 	}
 
+	// pull images through the image cache when the cluster shares a network with one
+	if ic := findImageCache(&c.config.ResourceInfo, c.config.Networks); ic != nil {
+		if imageCacheRegistry(ic) != "docker.io" {
+			c.log.Warn("Image cache is not used, Docker only supports caching images from Docker Hub", "ref", c.config.Name, "cache", ic.Name)
+		} else {
+			dir, _ := utils.CreateNomadConfigPath(c.config.Name)
+
+			dc, err := writeDockerDaemonConfig(dir, ic)
+			if err != nil {
+				return xerrors.Errorf("Unable to write Docker config for image cache: %w", err)
+			}
+
+			cc.Volumes = append(cc.Volumes, config.Volume{Source: dc, Destination: "/etc/docker/daemon.json"})
+		}
+	}
+
 	// set the environment variables for the K3S_KUBECONFIG_OUTPUT and K3S_CLUSTER_SECRET
 	cc.Environment = c.config.Environment
 
@@ -119,7 +153,14 @@ func (c *NomadCluster) createNomad() error {
 	}
 
 	// generate the config file
-	nomadConfig := clients.NomadConfig{Location: fmt.Sprintf("http://localhost:%d", apiPort), NodeCount: 1}
+	// the API of a cluster on a remote Docker engine is reached using the
+	// address of the remote machine
+	apiHost := "localhost"
+	if c.config.DockerHost != "" {
+		apiHost = utils.GetDockerHostAddress(c.config.DockerHost)
+	}
+
+	nomadConfig := clients.NomadConfig{Location: fmt.Sprintf("http://%s:%d", apiHost, apiPort), NodeCount: 1}
 	_, configPath := utils.CreateNomadConfigPath(c.config.Name)
 
 	err = nomadConfig.Save(configPath)
@@ -137,7 +178,7 @@ func (c *NomadCluster) createNomad() error {
 	// import the images to the servers container d instance
 	// importing images means that k3s does not need to pull from a remote docker hub
 	if c.config.Images != nil && len(c.config.Images) > 0 {
-		err := c.ImportLocalDockerImages("images", id, c.config.Images, false)
+		err := c.ImportLocalDockerImages(ctx, "images", id, c.config.Images, false)
 		if err != nil {
 			return xerrors.Errorf("Error importing Docker images: %w", err)
 		}
@@ -147,15 +188,14 @@ func (c *NomadCluster) createNomad() error {
 }
 
 // ImportLocalDockerImages fetches Docker images stored on the local client and imports them into the cluster
-func (c *NomadCluster) ImportLocalDockerImages(name string, id string, images []config.Image, force bool) error {
-	imgs := []string{}
+func (c *NomadCluster) ImportLocalDockerImages(ctx context.Context, name string, id string, images []config.Image, force bool) error {
+	err := pullImages(ctx, c.client, images)
+	if err != nil {
+		return err
+	}
 
+	imgs := []string{}
 	for _, i := range images {
-		err := c.client.PullImage(i, false)
-		if err != nil {
-			return err
-		}
-
 		imgs = append(imgs, i.Name)
 	}
 
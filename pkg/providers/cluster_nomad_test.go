@@ -2,6 +2,7 @@ package providers
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -62,9 +63,9 @@ func TestClusterNomadErrorsWhenUnableToLookupIDs(t *testing.T) {
 	md := &mocks.MockContainerTasks{}
 	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("boom"))
 
-	p := NewNomadCluster(clusterNomadConfig, md, nil, hclog.NewNullLogger())
+	p := NewNomadCluster(clusterNomadConfig, md, nil, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -72,9 +73,9 @@ func TestClusterNomadErrorsWhenClusterExists(t *testing.T) {
 	md := &mocks.MockContainerTasks{}
 	md.On("FindContainerIDs", "server."+clusterNomadConfig.Name, mock.Anything).Return([]string{"abc"}, nil)
 
-	p := NewNomadCluster(clusterNomadConfig, md, nil, hclog.NewNullLogger())
+	p := NewNomadCluster(clusterNomadConfig, md, nil, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -82,9 +83,9 @@ func TestClusterNomadPullsImage(t *testing.T) {
 	cc, md, mh, cleanup := setupNomadClusterMocks()
 	defer cleanup()
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "PullImage", config.Image{Name: "shipyardrun/nomad:v1.0.0"}, false)
 }
@@ -94,9 +95,9 @@ func TestClusterNomadPullsImageWithDefault(t *testing.T) {
 	cc.Version = "" // reset the version
 	defer cleanup()
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "PullImage", config.Image{Name: "shipyardrun/nomad:" + nomadBaseVersion}, false)
 }
@@ -105,9 +106,9 @@ func TestClusterNomadCreatesANewVolume(t *testing.T) {
 	cc, md, mh, cleanup := setupNomadClusterMocks()
 	defer cleanup()
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "CreateVolume", utils.ImageVolumeName)
 }
@@ -119,9 +120,9 @@ func TestClusterNomadFailsWhenUnableToCreatesANewVolume(t *testing.T) {
 	removeOn(&md.Mock, "CreateVolume")
 	md.On("CreateVolume", mock.Anything, mock.Anything).Return("", fmt.Errorf("boom"))
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 	md.AssertCalled(t, "CreateVolume", utils.ImageVolumeName)
 }
@@ -132,9 +133,9 @@ func TestClusterNomadCreatesAServer(t *testing.T) {
 
 	cc.Volumes = []config.Volume{config.Volume{Source: "./files", Destination: "/files"}}
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
@@ -166,9 +167,9 @@ func TestClusterNomadGeneratesConfig(t *testing.T) {
 	cc, md, mh, cleanup := setupNomadClusterMocks()
 	defer cleanup()
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	_, configPath := utils.CreateNomadConfigPath(cc.Name)
@@ -179,10 +180,10 @@ func TestClusterNomadHealthChecksAPI(t *testing.T) {
 	cc, md, mh, cleanup := setupNomadClusterMocks()
 	defer cleanup()
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, nil, hclog.NewNullLogger())
 	startTimeout = 10 * time.Millisecond // reset the startTimeout, do not want to wait 120s
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	mh.AssertCalled(t, "HealthCheckAPI", mock.Anything)
@@ -195,10 +196,10 @@ func TestClusterNomadErrorsIfHealthFails(t *testing.T) {
 	removeOn(&mh.Mock, "HealthCheckAPI")
 	mh.On("HealthCheckAPI", mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, nil, hclog.NewNullLogger())
 	startTimeout = 10 * time.Millisecond // reset the startTimeout, do not want to wait 120s
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -206,9 +207,9 @@ func TestClusterNomadImportDockerImagesPullsImages(t *testing.T) {
 	cc, md, mh, cleanup := setupNomadClusterMocks()
 	defer cleanup()
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "PullImage", clusterConfig.Images[0], false)
 	md.AssertCalled(t, "PullImage", clusterConfig.Images[1], false)
@@ -218,9 +219,9 @@ func TestClusterNomadImportDockerCopiesImages(t *testing.T) {
 	cc, md, mh, cleanup := setupNomadClusterMocks()
 	defer cleanup()
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "CopyLocalDockerImageToVolume", []string{"consul:1.6.1", "vault:1.6.1"}, "images.volume.shipyard.run", false)
 }
@@ -231,9 +232,9 @@ func TestClusterNomadImportDockerCopyImageFailReturnsError(t *testing.T) {
 	md.On("CopyLocalDockerImageToVolume", mock.Anything, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("boom"))
 	defer cleanup()
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -242,9 +243,9 @@ func TestClusterNomadImportDockerRunsExecCommand(t *testing.T) {
 	cc, md, mh, cleanup := setupNomadClusterMocks()
 	defer cleanup()
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	importCommand := []string{"docker", "load", "-i", "/images/file.tar.gz"}
@@ -258,9 +259,9 @@ func TestClusterNomadImportDockerExecFailReturnsError(t *testing.T) {
 	md.On("ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
 	defer cleanup()
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, nil, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -269,7 +270,7 @@ func TestClusterNomadDestroyGetsIDr(t *testing.T) {
 	cc, md, mh, cleanup := setupNomadClusterMocks()
 	defer cleanup()
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, nil, hclog.NewNullLogger())
 
 	err := p.Destroy()
 	assert.NoError(t, err)
@@ -282,7 +283,7 @@ func TestClusterNomadDestroyWithFindIDErrorReturnsError(t *testing.T) {
 	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("boom"))
 	defer cleanup()
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, nil, hclog.NewNullLogger())
 
 	err := p.Destroy()
 	assert.Error(t, err)
@@ -294,7 +295,7 @@ func TestClusterNomadDestroyWithNoIDReturns(t *testing.T) {
 	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return(nil, nil)
 	defer cleanup()
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, nil, hclog.NewNullLogger())
 
 	err := p.Destroy()
 	assert.NoError(t, err)
@@ -307,7 +308,7 @@ func TestClusterNomadDestroyRemovesContainer(t *testing.T) {
 	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return([]string{"found"}, nil)
 	defer cleanup()
 
-	p := NewNomadCluster(cc, md, mh, hclog.NewNullLogger())
+	p := NewNomadCluster(cc, md, mh, nil, hclog.NewNullLogger())
 
 	err := p.Destroy()
 	assert.NoError(t, err)
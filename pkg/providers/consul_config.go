@@ -0,0 +1,188 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/hcl2/hclparse"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+	"golang.org/x/xerrors"
+)
+
+// ConsulConfig is a provider for writing Consul config entries
+type ConsulConfig struct {
+	config     *config.ConsulConfig
+	httpClient clients.HTTP
+	log        hclog.Logger
+}
+
+// NewConsulConfig creates a new Consul config entry provider
+func NewConsulConfig(c *config.ConsulConfig, hc clients.HTTP, l hclog.Logger) *ConsulConfig {
+	return &ConsulConfig{c, hc, l}
+}
+
+// Create waits for the Consul agent and writes the config entries, entries
+// are retried until the timeout as the agent may not have elected a leader
+func (c *ConsulConfig) Create(ctx context.Context) error {
+	c.log.Info("Writing Consul config entries", "ref", c.config.Name, "address", c.config.Address)
+
+	timeout, err := time.ParseDuration(c.config.Timeout)
+	if err != nil {
+		return xerrors.Errorf("Unable to parse timeout %s: %w", c.config.Timeout, err)
+	}
+
+	entries, err := c.readEntries()
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(timeout)
+
+	err = c.httpClient.HealthCheckHTTP(fmt.Sprintf("%s/v1/status/leader", c.config.Address), timeout)
+	if err != nil {
+		return xerrors.Errorf("Consul agent is not reachable: %w", err)
+	}
+
+	for _, e := range entries {
+		for {
+			err = c.request(http.MethodPut, "config", e.body)
+			if err == nil {
+				break
+			}
+
+			if time.Now().After(deadline) {
+				return xerrors.Errorf("Unable to write config entry %s %s: %w", e.kind, e.name, err)
+			}
+
+			c.log.Debug("Unable to write config entry, retrying", "ref", c.config.Name, "kind", e.kind, "name", e.name, "error", err)
+			time.Sleep(time.Second)
+		}
+	}
+
+	return nil
+}
+
+// Destroy deletes the config entries, errors are ignored as the agent may
+// already have been removed
+func (c *ConsulConfig) Destroy() error {
+	c.log.Info("Destroy Consul config entries", "ref", c.config.Name)
+
+	entries, err := c.readEntries()
+	if err != nil {
+		c.log.Debug("Unable to read config entries, ignoring", "ref", c.config.Name, "error", err)
+		return nil
+	}
+
+	for _, e := range entries {
+		err := c.request(http.MethodDelete, fmt.Sprintf("config/%s/%s", e.kind, e.name), nil)
+		if err != nil {
+			c.log.Debug("Unable to delete config entry, ignoring", "ref", c.config.Name, "kind", e.kind, "name", e.name, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// Lookup returns nothing
+func (c *ConsulConfig) Lookup() ([]string, error) {
+	return []string{}, nil
+}
+
+type consulConfigEntry struct {
+	kind string
+	name string
+	body []byte
+}
+
+func (c *ConsulConfig) readEntries() ([]consulConfigEntry, error) {
+	entries := []consulConfigEntry{}
+
+	for _, f := range c.config.Entries {
+		d, err := readConsulConfigEntry(f)
+		if err != nil {
+			return nil, xerrors.Errorf("Unable to read config entry %s: %w", f, err)
+		}
+
+		e := struct {
+			Kind string
+			Name string
+		}{}
+
+		err = json.Unmarshal(d, &e)
+		if err != nil {
+			return nil, xerrors.Errorf("Unable to read config entry %s: %w", f, err)
+		}
+
+		if e.Kind == "" || e.Name == "" {
+			return nil, fmt.Errorf("Config entry %s must set Kind and Name", f)
+		}
+
+		entries = append(entries, consulConfigEntry{e.Kind, e.Name, d})
+	}
+
+	return entries, nil
+}
+
+// readConsulConfigEntry returns the config entry in the file as JSON, files
+// with the extension .hcl are converted to JSON
+func readConsulConfigEntry(file string) ([]byte, error) {
+	if filepath.Ext(file) != ".hcl" {
+		return ioutil.ReadFile(file)
+	}
+
+	f, diag := hclparse.NewParser().ParseHCLFile(file)
+	if diag.HasErrors() {
+		return nil, diag
+	}
+
+	attrs, diag := f.Body.JustAttributes()
+	if diag.HasErrors() {
+		return nil, diag
+	}
+
+	vals := map[string]cty.Value{}
+	for n, a := range attrs {
+		v, diag := a.Expr.Value(nil)
+		if diag.HasErrors() {
+			return nil, diag
+		}
+
+		vals[n] = v
+	}
+
+	return ctyjson.SimpleJSONValue{Value: cty.ObjectVal(vals)}.MarshalJSON()
+}
+
+func (c *ConsulConfig) request(method, path string, body []byte) error {
+	rq, err := http.NewRequest(method, fmt.Sprintf("%s/v1/%s", c.config.Address, path), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	if c.config.Token != "" {
+		rq.Header.Set("X-Consul-Token", c.config.Token)
+	}
+
+	resp, err := c.httpClient.Do(rq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		rb, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("Consul returned status %d: %s", resp.StatusCode, string(rb))
+	}
+
+	return nil
+}
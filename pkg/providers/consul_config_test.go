@@ -0,0 +1,121 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupConsulConfig(t *testing.T) (*config.ConsulConfig, *mocks.MockHTTP, func()) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+
+	defaults := filepath.Join(dir, "web-defaults.hcl")
+	ioutil.WriteFile(defaults, []byte(consulServiceDefaults), os.ModePerm)
+
+	intentions := filepath.Join(dir, "intentions.json")
+	ioutil.WriteFile(intentions, []byte(consulIntentions), os.ModePerm)
+
+	cc := config.NewConsulConfig("mesh")
+	cc.Address = "http://localhost:8500"
+	cc.Token = "secret"
+	cc.Entries = []string{defaults, intentions}
+
+	hc := &mocks.MockHTTP{}
+	hc.On("HealthCheckHTTP", mock.Anything, mock.Anything).Return(nil)
+	hc.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte("true"))),
+	}, nil)
+
+	return cc, hc, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+func TestConsulConfigWritesEntries(t *testing.T) {
+	cc, hc, cleanup := setupConsulConfig(t)
+	defer cleanup()
+
+	p := NewConsulConfig(cc, hc, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	hc.AssertCalled(t, "HealthCheckHTTP", "http://localhost:8500/v1/status/leader", mock.Anything)
+
+	calls := getCalls(&hc.Mock, "Do")
+	require.Len(t, calls, 2)
+
+	rq := calls[0].Arguments[0].(*http.Request)
+	assert.Equal(t, http.MethodPut, rq.Method)
+	assert.Equal(t, "http://localhost:8500/v1/config", rq.URL.String())
+	assert.Equal(t, "secret", rq.Header.Get("X-Consul-Token"))
+
+	d, _ := ioutil.ReadAll(rq.Body)
+	assert.JSONEq(t, `{"Kind": "service-defaults", "Name": "web", "Protocol": "http"}`, string(d))
+
+	rq = calls[1].Arguments[0].(*http.Request)
+	d, _ = ioutil.ReadAll(rq.Body)
+	assert.JSONEq(t, consulIntentions, string(d))
+}
+
+func TestConsulConfigReturnsErrorWhenWriteFails(t *testing.T) {
+	cc, hc, cleanup := setupConsulConfig(t)
+	defer cleanup()
+
+	cc.Timeout = "1ns"
+	removeOn(&hc.Mock, "Do")
+	hc.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusInternalServerError,
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte("No cluster leader"))),
+	}, nil)
+
+	p := NewConsulConfig(cc, hc, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+}
+
+func TestConsulConfigDestroyDeletesEntries(t *testing.T) {
+	cc, hc, cleanup := setupConsulConfig(t)
+	defer cleanup()
+
+	p := NewConsulConfig(cc, hc, hclog.NewNullLogger())
+
+	err := p.Destroy()
+	require.NoError(t, err)
+
+	calls := getCalls(&hc.Mock, "Do")
+	require.Len(t, calls, 2)
+
+	rq := calls[0].Arguments[0].(*http.Request)
+	assert.Equal(t, http.MethodDelete, rq.Method)
+	assert.Equal(t, "http://localhost:8500/v1/config/service-defaults/web", rq.URL.String())
+
+	rq = calls[1].Arguments[0].(*http.Request)
+	assert.Equal(t, "http://localhost:8500/v1/config/service-intentions/api", rq.URL.String())
+}
+
+const consulServiceDefaults = `
+Kind     = "service-defaults"
+Name     = "web"
+Protocol = "http"
+`
+
+const consulIntentions = `{
+	"Kind": "service-intentions",
+	"Name": "api",
+	"Sources": [{"Name": "web", "Action": "allow"}]
+}`
@@ -1,8 +1,7 @@
 package providers
 
 import (
-	"time"
-
+	"context"
 	hclog "github.com/hashicorp/go-hclog"
 	"github.com/shipyard-run/shipyard/pkg/clients"
 	"github.com/shipyard-run/shipyard/pkg/config"
@@ -40,11 +39,11 @@ func NewContainerSidecar(cs *config.Sidecar, cl clients.ContainerTasks, hc clien
 }
 
 // Create implements provider method and creates a Docker container with the given config
-func (c *Container) Create() error {
+func (c *Container) Create(ctx context.Context) error {
 	c.log.Info("Creating Container", "ref", c.config.Name)
 
 	// pull any images needed for this container
-	err := c.client.PullImage(c.config.Image, false)
+	err := c.client.PullImage(ctx, c.config.Image, false)
 	if err != nil {
 		c.log.Error("Error pulling container image", "ref", c.config.Name, "image", c.config.Image.Name)
 
@@ -52,22 +51,12 @@ func (c *Container) Create() error {
 	}
 
 	_, err = c.client.CreateContainer(c.config)
-
-	if c.config.HealthCheck == nil {
+	if err != nil {
 		return err
 	}
 
 	// check the health of the container
-	if hc := c.config.HealthCheck.HTTP; hc != "" {
-		d, err := time.ParseDuration(c.config.HealthCheck.Timeout)
-		if err != nil {
-			return err
-		}
-
-		return c.httpClient.HealthCheckHTTP(hc, d)
-	}
-
-	return nil
+	return runHealthCheck(c.config.HealthCheck, c.httpClient, execInContainer(c.client, c.config.Name, c.config.Type, c.log), c.log)
 }
 
 // Destroy stops and removes the container
@@ -104,3 +93,13 @@ func (c *Container) Destroy() error {
 func (c *Container) Lookup() ([]string, error) {
 	return c.client.FindContainerIDs(c.config.Name, c.config.Type)
 }
+
+// Drifted returns true when the container has been removed
+func (c *Container) Drifted() (bool, error) {
+	ids, err := c.Lookup()
+	if err != nil {
+		return false, err
+	}
+
+	return len(ids) == 0, nil
+}
@@ -0,0 +1,47 @@
+package providers
+
+import (
+	"context"
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"golang.org/x/xerrors"
+)
+
+// ContainerBuild is a provider for building Docker images
+type ContainerBuild struct {
+	config *config.ContainerBuild
+	client clients.ContainerTasks
+	log    hclog.Logger
+}
+
+// NewContainerBuild creates a new image build provider
+func NewContainerBuild(cb *config.ContainerBuild, cl clients.ContainerTasks, l hclog.Logger) *ContainerBuild {
+	return &ContainerBuild{cb, cl, l}
+}
+
+// Create builds the image
+func (c *ContainerBuild) Create(ctx context.Context) error {
+	c.log.Info("Building Container Image", "ref", c.config.Name, "tag", c.config.Tag)
+
+	_, err := c.client.BuildContainer(ctx, c.config)
+	if err != nil {
+		return xerrors.Errorf("Unable to build image %s: %w", c.config.Tag, err)
+	}
+
+	return nil
+}
+
+// Destroy does nothing, built images are kept in the local Docker cache
+// so that the next build can use the cached layers, images are removed
+// using the purge command
+func (c *ContainerBuild) Destroy() error {
+	c.log.Info("Destroy Container Build", "ref", c.config.Name)
+
+	return nil
+}
+
+// Lookup returns the name of the built image
+func (c *ContainerBuild) Lookup() ([]string, error) {
+	return []string{c.config.Tag}, nil
+}
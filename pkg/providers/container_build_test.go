@@ -0,0 +1,44 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupContainerBuild() (*config.ContainerBuild, *mocks.MockContainerTasks) {
+	cb := config.NewContainerBuild("app")
+	cb.Context = "/tmp/app"
+
+	md := &mocks.MockContainerTasks{}
+	md.On("BuildContainer", mock.Anything).Return(cb.Tag, nil)
+
+	return cb, md
+}
+
+func TestContainerBuildCreateBuildsImage(t *testing.T) {
+	cb, md := setupContainerBuild()
+	p := NewContainerBuild(cb, md, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	md.AssertCalled(t, "BuildContainer", cb)
+}
+
+func TestContainerBuildCreateReturnsErrorWhenBuildFails(t *testing.T) {
+	cb, md := setupContainerBuild()
+	removeOn(&md.Mock, "BuildContainer")
+	md.On("BuildContainer", mock.Anything).Return("", fmt.Errorf("boom"))
+
+	p := NewContainerBuild(cb, md, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+}
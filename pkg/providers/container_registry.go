@@ -0,0 +1,105 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"golang.org/x/xerrors"
+)
+
+// ContainerRegistry is a provider for creating a local Docker registry
+type ContainerRegistry struct {
+	config     *config.ContainerRegistry
+	client     clients.ContainerTasks
+	httpClient clients.HTTP
+	log        hclog.Logger
+}
+
+// NewContainerRegistry creates a new registry provider
+func NewContainerRegistry(cr *config.ContainerRegistry, cl clients.ContainerTasks, hc clients.HTTP, l hclog.Logger) *ContainerRegistry {
+	return &ContainerRegistry{cr, cl, hc, l}
+}
+
+// Create the registry container and push the images
+func (c *ContainerRegistry) Create(ctx context.Context) error {
+	c.log.Info("Creating Container Registry", "ref", c.config.Name, "address", c.config.Address)
+
+	ids, err := c.Lookup()
+	if err != nil {
+		return xerrors.Errorf("Unable to lookup registry: %w", err)
+	}
+
+	if len(ids) == 0 {
+		cc := config.NewContainer(c.config.Name)
+		c.config.ResourceInfo.AddChild(cc)
+
+		cc.Image = config.Image{Name: registryImage}
+		cc.Networks = c.config.Networks
+		cc.Ports = []config.Port{
+			config.Port{
+				Local:    fmt.Sprintf("%d", registryPort),
+				Host:     fmt.Sprintf("%d", c.config.Port),
+				Protocol: "tcp",
+			},
+		}
+
+		err = c.client.PullImage(ctx, cc.Image, false)
+		if err != nil {
+			return err
+		}
+
+		_, err = c.client.CreateContainer(cc)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = c.httpClient.HealthCheckHTTP(fmt.Sprintf("http://%s/v2/", c.config.Address), 60*time.Second)
+	if err != nil {
+		return xerrors.Errorf("Registry did not start: %w", err)
+	}
+
+	for _, i := range c.config.Images {
+		err := c.client.PullImage(ctx, i, false)
+		if err != nil {
+			return xerrors.Errorf("Unable to pull image %s: %w", i.Name, err)
+		}
+
+		name, err := c.client.PushImage(i.Name, c.config.Address)
+		if err != nil {
+			return xerrors.Errorf("Unable to push image %s to registry: %w", i.Name, err)
+		}
+
+		c.log.Debug("Pushed image to registry", "ref", c.config.Name, "image", name)
+	}
+
+	return nil
+}
+
+// Destroy the registry container, images in the registry are removed
+func (c *ContainerRegistry) Destroy() error {
+	c.log.Info("Destroy Container Registry", "ref", c.config.Name)
+
+	ids, err := c.Lookup()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		err := c.client.RemoveContainer(id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Lookup the ID of the registry container
+func (c *ContainerRegistry) Lookup() ([]string, error) {
+	return c.client.FindContainerIDs(c.config.Name, c.config.Type)
+}
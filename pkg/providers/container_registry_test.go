@@ -0,0 +1,66 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupContainerRegistry() (*config.ContainerRegistry, *mocks.MockContainerTasks, *mocks.MockHTTP) {
+	cr := config.NewContainerRegistry("local")
+	cr.Address = "localhost:5000"
+	cr.Images = []config.Image{config.Image{Name: "consul:1.8.1"}}
+
+	md := &mocks.MockContainerTasks{}
+	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return(nil, nil)
+	md.On("PullImage", mock.Anything, mock.Anything).Return(nil)
+	md.On("CreateContainer", mock.Anything).Return("abc", nil)
+	md.On("PushImage", mock.Anything, mock.Anything).Return("localhost:5000/consul:1.8.1", nil)
+
+	hc := &mocks.MockHTTP{}
+	hc.On("HealthCheckHTTP", mock.Anything, mock.Anything).Return(nil)
+
+	return cr, md, hc
+}
+
+func TestContainerRegistryCreatesContainerWithPort(t *testing.T) {
+	cr, md, hc := setupContainerRegistry()
+	p := NewContainerRegistry(cr, md, hc, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	cc := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+	assert.Equal(t, registryImage, cc.Image.Name)
+	assert.Equal(t, "5000", cc.Ports[0].Host)
+
+	hc.AssertCalled(t, "HealthCheckHTTP", "http://localhost:5000/v2/", mock.Anything)
+}
+
+func TestContainerRegistryPushesImages(t *testing.T) {
+	cr, md, hc := setupContainerRegistry()
+	p := NewContainerRegistry(cr, md, hc, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	md.AssertCalled(t, "PullImage", cr.Images[0], false)
+	md.AssertCalled(t, "PushImage", "consul:1.8.1", "localhost:5000")
+}
+
+func TestContainerRegistryReturnsErrorWhenPushFails(t *testing.T) {
+	cr, md, hc := setupContainerRegistry()
+	removeOn(&md.Mock, "PushImage")
+	md.On("PushImage", mock.Anything, mock.Anything).Return("", fmt.Errorf("boom"))
+
+	p := NewContainerRegistry(cr, md, hc, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+}
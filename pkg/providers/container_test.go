@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -24,7 +25,7 @@ func TestContainerCreatesSuccessfully(t *testing.T) {
 	// check calls CreateContainer with the config
 	md.On("CreateContainer", cc).Once().Return("", nil)
 
-	err := c.Create()
+	err := c.Create(context.Background())
 	assert.NoError(t, err)
 
 	hc.AssertNotCalled(t, "HealthCheckHTTP", mock.Anything, mock.Anything)
@@ -46,7 +47,7 @@ func TestContainerRunsHTTPChecks(t *testing.T) {
 
 	hc.On("HealthCheckHTTP", mock.Anything, mock.Anything).Return(nil)
 
-	err := c.Create()
+	err := c.Create(context.Background())
 	assert.NoError(t, err)
 
 	hc.AssertCalled(t, "HealthCheckHTTP", "http://localhost:8500", 30*time.Second)
@@ -65,7 +66,7 @@ func TestContainerDoesNOTCreateWhenPullImageFail(t *testing.T) {
 	// check does not call CreateContainer with the config
 	md.On("CreateContainer", cc).Times(0)
 
-	err := c.Create()
+	err := c.Create(context.Background())
 	assert.Equal(t, imageErr, err)
 }
 
@@ -125,3 +126,27 @@ func TestContainerLooksupIDs(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, []string{"abc"}, ids)
 }
+
+func TestContainerDriftedReturnsTrueWhenContainerRemoved(t *testing.T) {
+	cc := config.NewContainer("tests")
+	md := &mocks.MockContainerTasks{}
+	c := NewContainer(cc, md, &mocks.MockHTTP{}, hclog.NewNullLogger())
+
+	md.On("FindContainerIDs", "tests", config.TypeContainer).Return(nil, nil)
+
+	drifted, err := c.Drifted()
+	assert.NoError(t, err)
+	assert.True(t, drifted)
+}
+
+func TestContainerDriftedReturnsFalseWhenContainerExists(t *testing.T) {
+	cc := config.NewContainer("tests")
+	md := &mocks.MockContainerTasks{}
+	c := NewContainer(cc, md, &mocks.MockHTTP{}, hclog.NewNullLogger())
+
+	md.On("FindContainerIDs", "tests", config.TypeContainer).Return([]string{"abc"}, nil)
+
+	drifted, err := c.Drifted()
+	assert.NoError(t, err)
+	assert.False(t, drifted)
+}
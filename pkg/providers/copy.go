@@ -0,0 +1,65 @@
+package providers
+
+import (
+	"context"
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"golang.org/x/xerrors"
+)
+
+// Copy is a provider for copying files into running resources
+type Copy struct {
+	config *config.Copy
+	client clients.ContainerTasks
+	log    hclog.Logger
+}
+
+// NewCopy creates a new copy provider
+func NewCopy(cp *config.Copy, cl clients.ContainerTasks, l hclog.Logger) *Copy {
+	return &Copy{cp, cl, l}
+}
+
+// Create copies the source to the target
+func (c *Copy) Create(ctx context.Context) error {
+	c.log.Info("Copying files", "ref", c.config.Name, "source", c.config.Source, "target", c.config.Target, "destination", c.config.Destination)
+
+	target, err := c.config.FindDependentResource(c.config.Target)
+	if err != nil {
+		return xerrors.Errorf("Unable to find target: %w", err)
+	}
+
+	switch target.Info().Type {
+	case config.TypeContainer, config.TypeSidecar, config.TypeK8sCluster, config.TypeNomadCluster:
+	default:
+		return xerrors.Errorf("Unable to copy files to %s, files can only be copied to containers, sidecars and clusters", c.config.Target)
+	}
+
+	ids, err := c.client.FindContainerIDs(target.Info().Name, target.Info().Type)
+	if err != nil {
+		return xerrors.Errorf("Unable to find copy target: %w", err)
+	}
+
+	if len(ids) != 1 {
+		return xerrors.Errorf("Unable to find copy target %s", c.config.Target)
+	}
+
+	err = c.client.CopyToContainer(ids[0], c.config.Source, c.config.Destination)
+	if err != nil {
+		return xerrors.Errorf("Unable to copy files to %s: %w", c.config.Target, err)
+	}
+
+	return nil
+}
+
+// Destroy does nothing, the copied files are removed with the target
+func (c *Copy) Destroy() error {
+	c.log.Info("Destroy Copy", "ref", c.config.Name)
+
+	return nil
+}
+
+// Lookup returns nothing
+func (c *Copy) Lookup() ([]string, error) {
+	return nil, nil
+}
@@ -0,0 +1,63 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupCopy(target string) (*config.Copy, *mocks.MockContainerTasks) {
+	cp := config.NewCopy("config")
+	cp.Target = target
+	cp.Source = "/tmp/config"
+	cp.Destination = "/etc/consul"
+
+	c := config.New()
+	c.AddResource(config.NewContainer("consul"))
+	c.AddResource(config.NewNetwork("local"))
+	c.AddResource(cp)
+
+	md := &mocks.MockContainerTasks{}
+	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return([]string{"abc"}, nil)
+	md.On("CopyToContainer", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	return cp, md
+}
+
+func TestCopyCreateCopiesFilesToTarget(t *testing.T) {
+	cp, md := setupCopy("container.consul")
+	p := NewCopy(cp, md, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	md.AssertCalled(t, "FindContainerIDs", "consul", config.TypeContainer)
+	md.AssertCalled(t, "CopyToContainer", "abc", "/tmp/config", "/etc/consul")
+}
+
+func TestCopyCreateReturnsErrorWhenTargetNotContainer(t *testing.T) {
+	cp, md := setupCopy("network.local")
+	p := NewCopy(cp, md, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+
+	md.AssertNotCalled(t, "CopyToContainer", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCopyCreateReturnsErrorWhenCopyFails(t *testing.T) {
+	cp, md := setupCopy("container.consul")
+	removeOn(&md.Mock, "CopyToContainer")
+	md.On("CopyToContainer", mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
+
+	p := NewCopy(cp, md, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+}
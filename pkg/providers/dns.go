@@ -0,0 +1,152 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"golang.org/x/xerrors"
+)
+
+const dnsImage = "coredns/coredns:1.8.0"
+
+// dnsResourceTypes are the types of resource which can be resolved using
+// the type in the name e.g. envoy.sidecar.shipyard.local
+var dnsResourceTypes = []string{"container", "sidecar", "ingress"}
+
+// DNS is a provider for running a CoreDNS server
+type DNS struct {
+	config *config.DNS
+	client clients.ContainerTasks
+	log    hclog.Logger
+}
+
+// NewDNS creates a new DNS provider
+func NewDNS(d *config.DNS, cl clients.ContainerTasks, l hclog.Logger) *DNS {
+	return &DNS{d, cl, l}
+}
+
+// Create writes the CoreDNS config and starts the server
+func (d *DNS) Create(ctx context.Context) error {
+	d.log.Info("Creating DNS", "ref", d.config.Name, "domain", d.config.Domain, "address", d.config.Address)
+
+	dir := d.configDir()
+
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return xerrors.Errorf("Unable to create folder for DNS config: %w", err)
+	}
+
+	err = ioutil.WriteFile(filepath.Join(dir, "Corefile"), []byte(d.corefile()), 0644)
+	if err != nil {
+		return xerrors.Errorf("Unable to write DNS config: %w", err)
+	}
+
+	err = ioutil.WriteFile(filepath.Join(dir, "hosts"), []byte(d.hosts()), 0644)
+	if err != nil {
+		return xerrors.Errorf("Unable to write DNS records: %w", err)
+	}
+
+	cc := config.NewContainer(d.config.Name)
+	d.config.ResourceInfo.AddChild(cc)
+
+	cc.Image = config.Image{Name: dnsImage}
+	cc.Networks = d.config.Networks
+	cc.Command = []string{"-conf", "/etc/coredns/Corefile"}
+	cc.Volumes = []config.Volume{
+		config.Volume{
+			Source:      dir,
+			Destination: "/etc/coredns",
+		},
+	}
+
+	err = d.client.PullImage(ctx, cc.Image, false)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.client.CreateContainer(cc)
+	return err
+}
+
+// Destroy the DNS container and config
+func (d *DNS) Destroy() error {
+	d.log.Info("Destroy DNS", "ref", d.config.Name)
+
+	ids, err := d.Lookup()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		err := d.client.RemoveContainer(id)
+		if err != nil {
+			return err
+		}
+	}
+
+	os.RemoveAll(d.configDir())
+
+	return nil
+}
+
+// Lookup the ID of the DNS container
+func (d *DNS) Lookup() ([]string, error) {
+	return d.client.FindContainerIDs(d.config.Name, d.config.Type)
+}
+
+func (d *DNS) configDir() string {
+	return filepath.Join(utils.ShipyardTemp(), "dns", d.config.Name)
+}
+
+// corefile returns the CoreDNS config, names in the domain are resolved
+// from the records and then rewritten to the names of the shipyard
+// containers which are resolved by the Docker DNS server
+func (d *DNS) corefile() string {
+	domain := strings.Replace(d.config.Domain, ".", `\.`, -1)
+	types := strings.Join(dnsResourceTypes, "|")
+
+	b := &bytes.Buffer{}
+
+	fmt.Fprintf(b, "%s:53 {\n", d.config.Domain)
+	fmt.Fprintf(b, "  errors\n")
+	fmt.Fprintf(b, "  hosts /etc/coredns/hosts {\n")
+	fmt.Fprintf(b, "    fallthrough\n")
+	fmt.Fprintf(b, "  }\n")
+	fmt.Fprintf(b, "  rewrite stop {\n")
+	fmt.Fprintf(b, "    name regex (.+)\\.(%s)\\.%s {1}.{2}.shipyard.run\n", types, domain)
+	fmt.Fprintf(b, "    answer name (.+)\\.(%s)\\.shipyard\\.run {1}.{2}.%s\n", types, d.config.Domain)
+	fmt.Fprintf(b, "  }\n")
+	fmt.Fprintf(b, "  rewrite stop {\n")
+	fmt.Fprintf(b, "    name regex (.+)\\.%s {1}.container.shipyard.run\n", domain)
+	fmt.Fprintf(b, "    answer name (.+)\\.container\\.shipyard\\.run {1}.%s\n", d.config.Domain)
+	fmt.Fprintf(b, "  }\n")
+	fmt.Fprintf(b, "  forward . 127.0.0.11\n")
+	fmt.Fprintf(b, "}\n")
+
+	fmt.Fprintf(b, "\n.:53 {\n")
+	fmt.Fprintf(b, "  errors\n")
+	fmt.Fprintf(b, "  forward . %s\n", strings.Join(d.config.Upstream, " "))
+	fmt.Fprintf(b, "}\n")
+
+	return b.String()
+}
+
+// hosts returns the records in hosts file format
+func (d *DNS) hosts() string {
+	b := &bytes.Buffer{}
+
+	for _, r := range d.config.Records {
+		fmt.Fprintf(b, "%s %s\n", r.IP, r.Name)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,81 @@
+package providers
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupDNS(t *testing.T) (*config.DNS, *mocks.MockContainerTasks, func()) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+
+	home := os.Getenv("HOME")
+	os.Setenv("HOME", dir)
+
+	d := config.NewDNS("local")
+	d.Networks = []config.NetworkAttachment{{Name: "network.local", IPAddress: "10.5.0.53"}}
+	d.Records = []config.DNSRecord{{Name: "api.example.com", IP: "10.5.0.100"}}
+
+	md := &mocks.MockContainerTasks{}
+	md.On("PullImage", mock.Anything, mock.Anything).Return(nil)
+	md.On("CreateContainer", mock.Anything).Return("abc", nil)
+	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return([]string{"abc"}, nil)
+	md.On("RemoveContainer", mock.Anything).Return(nil)
+
+	return d, md, func() {
+		os.Setenv("HOME", home)
+		os.RemoveAll(dir)
+	}
+}
+
+func TestDNSCreatesContainerWithConfig(t *testing.T) {
+	d, md, cleanup := setupDNS(t)
+	defer cleanup()
+
+	p := NewDNS(d, md, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	cc := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+	assert.Equal(t, dnsImage, cc.Image.Name)
+	assert.Equal(t, d.Networks, cc.Networks)
+	require.Len(t, cc.Volumes, 1)
+	assert.Equal(t, "/etc/coredns", cc.Volumes[0].Destination)
+
+	cf, err := ioutil.ReadFile(filepath.Join(cc.Volumes[0].Source, "Corefile"))
+	require.NoError(t, err)
+	assert.Contains(t, string(cf), "shipyard.local:53 {")
+	assert.Contains(t, string(cf), `name regex (.+)\.shipyard\.local {1}.container.shipyard.run`)
+	assert.Contains(t, string(cf), "forward . 8.8.8.8 8.8.4.4")
+
+	h, err := ioutil.ReadFile(filepath.Join(cc.Volumes[0].Source, "hosts"))
+	require.NoError(t, err)
+	assert.Equal(t, "10.5.0.100 api.example.com\n", string(h))
+}
+
+func TestDNSDestroyRemovesContainerAndConfig(t *testing.T) {
+	d, md, cleanup := setupDNS(t)
+	defer cleanup()
+
+	p := NewDNS(d, md, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	err = p.Destroy()
+	require.NoError(t, err)
+
+	md.AssertCalled(t, "RemoveContainer", "abc")
+	assert.NoDirExists(t, p.configDir())
+}
@@ -0,0 +1,45 @@
+package providers
+
+import (
+	"context"
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+)
+
+// DockerVolume is a provider for creating named Docker volumes
+type DockerVolume struct {
+	config *config.DockerVolume
+	client clients.ContainerTasks
+	log    hclog.Logger
+}
+
+// NewDockerVolume creates a new DockerVolume provider
+func NewDockerVolume(v *config.DockerVolume, cl clients.ContainerTasks, l hclog.Logger) *DockerVolume {
+	return &DockerVolume{v, cl, l}
+}
+
+// Create the volume, an existing volume and its data is reused
+func (v *DockerVolume) Create(ctx context.Context) error {
+	v.log.Info("Creating Volume", "ref", v.config.Name)
+
+	_, err := v.client.CreateVolume(v.config.Name)
+	return err
+}
+
+// Destroy the volume unless keep_on_destroy is set
+func (v *DockerVolume) Destroy() error {
+	if v.config.KeepOnDestroy {
+		v.log.Info("Keeping Volume", "ref", v.config.Name, "volume", v.config.VolumeName)
+		return nil
+	}
+
+	v.log.Info("Destroy Volume", "ref", v.config.Name)
+
+	return v.client.RemoveVolume(v.config.Name)
+}
+
+// Lookup returns nothing, volumes do not have an ID
+func (v *DockerVolume) Lookup() ([]string, error) {
+	return []string{}, nil
+}
@@ -0,0 +1,53 @@
+package providers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupDockerVolume() (*config.DockerVolume, *mocks.MockContainerTasks) {
+	v := config.NewDockerVolume("data")
+
+	md := &mocks.MockContainerTasks{}
+	md.On("CreateVolume", mock.Anything).Return("data.volume.shipyard.run", nil)
+	md.On("RemoveVolume", mock.Anything).Return(nil)
+
+	return v, md
+}
+
+func TestDockerVolumeCreatesVolume(t *testing.T) {
+	v, md := setupDockerVolume()
+	p := NewDockerVolume(v, md, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	md.AssertCalled(t, "CreateVolume", "data")
+}
+
+func TestDockerVolumeDestroyRemovesVolume(t *testing.T) {
+	v, md := setupDockerVolume()
+	p := NewDockerVolume(v, md, hclog.NewNullLogger())
+
+	err := p.Destroy()
+	assert.NoError(t, err)
+
+	md.AssertCalled(t, "RemoveVolume", "data")
+}
+
+func TestDockerVolumeDestroyKeepsVolume(t *testing.T) {
+	v, md := setupDockerVolume()
+	v.KeepOnDestroy = true
+	p := NewDockerVolume(v, md, hclog.NewNullLogger())
+
+	err := p.Destroy()
+	assert.NoError(t, err)
+
+	md.AssertNotCalled(t, "RemoveVolume", mock.Anything)
+}
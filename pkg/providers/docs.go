@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"html/template"
 	"io/ioutil"
@@ -31,17 +32,17 @@ func NewDocs(c *config.Docs, cc clients.ContainerTasks, l hclog.Logger) *Docs {
 }
 
 // Create a new documentation container
-func (i *Docs) Create() error {
+func (i *Docs) Create(ctx context.Context) error {
 	i.log.Info("Creating Documentation", "ref", i.config.Name)
 
 	// create the documentation container
-	err := i.createDocsContainer()
+	err := i.createDocsContainer(ctx)
 	if err != nil {
 		return err
 	}
 
 	// create the terminal server container
-	err = i.createTerminalContainer()
+	err = i.createTerminalContainer(ctx)
 	if err != nil {
 		return err
 	}
@@ -52,7 +53,7 @@ func (i *Docs) Create() error {
 	return nil
 }
 
-func (i *Docs) createDocsContainer() error {
+func (i *Docs) createDocsContainer(ctx context.Context) error {
 	// create the container config
 	cc := config.NewContainer(i.config.Name)
 	i.config.ResourceInfo.AddChild(cc)
@@ -67,7 +68,7 @@ func (i *Docs) createDocsContainer() error {
 	}
 
 	// pull the docker image
-	err := i.client.PullImage(cc.Image, false)
+	err := i.client.PullImage(ctx, cc.Image, false)
 	if err != nil {
 		return err
 	}
@@ -121,7 +122,7 @@ func (i *Docs) createDocsContainer() error {
 	return err
 }
 
-func (i *Docs) createTerminalContainer() error {
+func (i *Docs) createTerminalContainer(ctx context.Context) error {
 	// create the container config
 	cc := config.NewContainer("terminal")
 	i.config.ResourceInfo.AddChild(cc)
@@ -130,7 +131,7 @@ func (i *Docs) createTerminalContainer() error {
 	cc.Image = config.Image{Name: fmt.Sprintf("%s:%s", terminalImageName, terminalVersion)}
 
 	// pull the image
-	err := i.client.PullImage(cc.Image, false)
+	err := i.client.PullImage(ctx, cc.Image, false)
 	if err != nil {
 		return err
 	}
@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"testing"
@@ -34,7 +35,7 @@ func setupDocs() (*Docs, *mocks.MockContainerTasks) {
 func TestDocsPullsDocsContainer(t *testing.T) {
 	d, md := setupDocs()
 
-	err := d.Create()
+	err := d.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "PullImage")[0].Arguments[0].(config.Image)
@@ -44,7 +45,7 @@ func TestDocsPullsDocsContainer(t *testing.T) {
 func TestDocsMountsMarkdown(t *testing.T) {
 	d, md := setupDocs()
 
-	err := d.Create()
+	err := d.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
@@ -58,7 +59,7 @@ func TestDocsMountsMarkdown(t *testing.T) {
 func TestDocsGeneratesDocusaurusConfig(t *testing.T) {
 	d, md := setupDocs()
 
-	err := d.Create()
+	err := d.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
@@ -80,7 +81,7 @@ func TestDocsGeneratesDocusaurusConfig(t *testing.T) {
 func TestDocsSetsDocsPorts(t *testing.T) {
 	d, md := setupDocs()
 
-	err := d.Create()
+	err := d.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
@@ -99,7 +100,7 @@ func TestDocsSetsDocsPorts(t *testing.T) {
 func TestDocsPullsTerminalContainer(t *testing.T) {
 	d, md := setupDocs()
 
-	err := d.Create()
+	err := d.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "PullImage")[1].Arguments[0].(config.Image)
@@ -109,7 +110,7 @@ func TestDocsPullsTerminalContainer(t *testing.T) {
 func TestDocsMountsDockerSock(t *testing.T) {
 	d, md := setupDocs()
 
-	err := d.Create()
+	err := d.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CreateContainer")[1].Arguments[0].(*config.Container)
@@ -123,7 +124,7 @@ func TestDocsMountsDockerSock(t *testing.T) {
 func TestDocsSetsTerminalPorts(t *testing.T) {
 	d, md := setupDocs()
 
-	err := d.Create()
+	err := d.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CreateContainer")[1].Arguments[0].(*config.Container)
@@ -136,7 +137,7 @@ func TestDocsSetsTerminalPorts(t *testing.T) {
 func TestDestroyRemovesContainers(t *testing.T) {
 	d, md := setupDocs()
 
-	err := d.Create()
+	err := d.Create(context.Background())
 	assert.NoError(t, err)
 
 	err = d.Destroy()
@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"os"
 
@@ -23,7 +24,7 @@ func NewExecLocal(c *config.ExecLocal, ex clients.Command, l hclog.Logger) *Exec
 }
 
 // Create a new exec
-func (c *ExecLocal) Create() error {
+func (c *ExecLocal) Create(ctx context.Context) error {
 	if c.config.Command != "" {
 		return fmt.Errorf("Only Script execution is currently implemented for Local Exec")
 	}
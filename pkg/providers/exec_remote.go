@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 
 	hclog "github.com/hashicorp/go-hclog"
@@ -23,21 +24,15 @@ func NewRemoteExec(c *config.ExecRemote, ex clients.ContainerTasks, l hclog.Logg
 }
 
 // Create a new execution instance
-func (c *ExecRemote) Create() error {
+func (c *ExecRemote) Create(ctx context.Context) error {
 	c.log.Info("Remote executing command", "ref", c.config.Name, "command", c.config.Command, "args", c.config.Arguments, "image", c.config.Image)
 
-	/*
-		if c.config.Script != "" {
-			return fmt.Errorf("Remote execution of Scripts are not currently implemented: %s", c.config.Script)
-		}
-	*/
-
 	// execution target id
 	targetID := ""
 
 	if c.config.Target == "" {
 		// Not using existing target create new container
-		id, err := c.createRemoteExecContainer()
+		id, err := c.createRemoteExecContainer(ctx)
 		if err != nil {
 			return xerrors.Errorf("Unable to create container for remote exec: %w", err)
 		}
@@ -73,8 +68,14 @@ func (c *ExecRemote) Create() error {
 
 	// execute the script in the container
 	command := []string{}
-	command = append(command, c.config.Command)
-	command = append(command, c.config.Arguments...)
+	if c.config.Script != "" {
+		// inline scripts are passed to the shell so that they do not need
+		// to be copied into the container
+		command = append(command, "sh", "-c", c.config.Script)
+	} else {
+		command = append(command, c.config.Command)
+		command = append(command, c.config.Arguments...)
+	}
 
 	// build the environment variables
 	envs := []string{}
@@ -95,7 +96,7 @@ func (c *ExecRemote) Create() error {
 	return err
 }
 
-func (c *ExecRemote) createRemoteExecContainer() (string, error) {
+func (c *ExecRemote) createRemoteExecContainer(ctx context.Context) (string, error) {
 	// first create a new container
 	cc := config.NewContainer("remote_exec_temp")
 	c.config.ResourceInfo.AddChild(cc)
@@ -106,7 +107,7 @@ func (c *ExecRemote) createRemoteExecContainer() (string, error) {
 	cc.Volumes = c.config.Volumes
 
 	// pull any images needed for this container
-	err := c.client.PullImage(cc.Image, false)
+	err := c.client.PullImage(ctx, cc.Image, false)
 	if err != nil {
 		c.log.Error("Error pulling container image", "ref", cc.Name, "image", cc.Image.Name)
 
@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -41,22 +42,24 @@ func testRemoteExecSetupMocks() (*config.ExecRemote, *config.Network, *mocks.Moc
 	return trex, net, md
 }
 
-/*
-func TestRemoteExecThrowsErrorIfScript(t *testing.T) {
+func TestRemoteExecExecutesInlineScriptUsingShell(t *testing.T) {
 	trex, _, md := testRemoteExecSetupMocks()
-	trex.Script = "./script.sh"
+	trex.Command = ""
+	trex.Script = "vault status\nvault secrets list\n"
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
-	assert.Error(t, err)
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	params := getCalls(&md.Mock, "ExecuteCommand")[0].Arguments[1].([]string)
+	assert.Equal(t, []string{"sh", "-c", trex.Script}, params)
 }
-*/
 
 func TestRemoteExecPullsImageWhenNoTarget(t *testing.T) {
 	trex, _, md := testRemoteExecSetupMocks()
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "PullImage", mock.Anything, mock.Anything)
 }
@@ -68,7 +71,7 @@ func TestRemoteExecPullsImageReturnsErrorWhenError(t *testing.T) {
 
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -76,7 +79,7 @@ func TestRemoteExecCreatesContainerWhenNoTarget(t *testing.T) {
 	trex, _, md := testRemoteExecSetupMocks()
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "CreateContainer", mock.Anything)
 }
@@ -88,7 +91,7 @@ func TestRemoteExecCreatesContainerFailsReturnError(t *testing.T) {
 
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -97,7 +100,7 @@ func TestRemoteExecWithTargetLooksupID(t *testing.T) {
 	trex.Target = "container.test"
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "FindContainerIDs", "test", config.TypeContainer)
 }
@@ -109,7 +112,7 @@ func TestRemoteExecWithTargetLooksupIDNotFoundReturnsError(t *testing.T) {
 	md.On("FindContainerIDs", "test", config.TypeContainer).Return([]string{}, nil)
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -117,7 +120,7 @@ func TestRemoteExecExecutesCommand(t *testing.T) {
 	trex, _, md := testRemoteExecSetupMocks()
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 
@@ -137,7 +140,7 @@ func TestRemoteExecExecutesCommandFailReturnsError(t *testing.T) {
 
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -145,7 +148,7 @@ func TestRemoteExecRemovesContainer(t *testing.T) {
 	trex, _, md := testRemoteExecSetupMocks()
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "RemoveContainer", "1234")
 }
@@ -158,7 +161,7 @@ func TestRemoteExecRemoveContainerFailReturnsError(t *testing.T) {
 
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 */
@@ -167,7 +170,7 @@ func TestRemoteExecDoesNOTRemovesContainerWhenTarget(t *testing.T) {
 	trex.Target = "container.test"
 	p := NewRemoteExec(trex, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertNotCalled(t, "RemoveContainer", mock.Anything)
 }
@@ -0,0 +1,244 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"golang.org/x/xerrors"
+)
+
+// gitOpsTools are the Helm charts used to install each tool and the custom
+// resource definitions which must exist before the repository is registered
+var gitOpsTools = map[string]struct {
+	repository string
+	chart      string
+	crds       []string
+}{
+	"argocd": {
+		"https://argoproj.github.io/argo-helm",
+		"argo-cd",
+		[]string{"applications.argoproj.io"},
+	},
+	"flux": {
+		"https://fluxcd-community.github.io/helm-charts",
+		"flux2",
+		[]string{"gitrepositories.source.toolkit.fluxcd.io", "kustomizations.kustomize.toolkit.fluxcd.io"},
+	},
+}
+
+const argoApplication = `apiVersion: argoproj.io/v1alpha1
+kind: Application
+metadata:
+  name: %s
+  namespace: %s
+spec:
+  project: default
+  source:
+    repoURL: %s
+    targetRevision: %s
+    path: %s
+  destination:
+    server: https://kubernetes.default.svc
+    namespace: %s
+  syncPolicy:
+    automated:
+      prune: true
+      selfHeal: true
+`
+
+const fluxSource = `apiVersion: source.toolkit.fluxcd.io/v1beta1
+kind: GitRepository
+metadata:
+  name: %s
+  namespace: %s
+spec:
+  interval: 1m
+  url: %s
+  ref:
+    branch: %s
+---
+apiVersion: kustomize.toolkit.fluxcd.io/v1beta1
+kind: Kustomization
+metadata:
+  name: %s
+  namespace: %s
+spec:
+  interval: 1m
+  path: %s
+  prune: true
+  targetNamespace: %s
+  sourceRef:
+    kind: GitRepository
+    name: %s
+`
+
+// GitOps is a provider which installs ArgoCD or Flux and registers a Git
+// repository to sync
+type GitOps struct {
+	config     *config.GitOps
+	kubeClient clients.Kubernetes
+	helmClient clients.Helm
+	log        hclog.Logger
+}
+
+// NewGitOps creates a new GitOps provider
+func NewGitOps(c *config.GitOps, kc clients.Kubernetes, hc clients.Helm, l hclog.Logger) *GitOps {
+	return &GitOps{c, kc, hc, l}
+}
+
+// Create installs the tool with Helm, waits for the custom resource
+// definitions and then applies the resources which sync the repository
+func (g *GitOps) Create(ctx context.Context) error {
+	g.log.Info("Creating GitOps", "ref", g.config.Name, "tool", g.config.Tool, "repository", g.config.Repository)
+
+	tool, ok := gitOpsTools[g.config.Tool]
+	if !ok {
+		return fmt.Errorf("Unknown GitOps tool %s, tool must be argocd or flux", g.config.Tool)
+	}
+
+	timeout, err := time.ParseDuration(g.config.Timeout)
+	if err != nil {
+		return xerrors.Errorf("Unable to parse timeout %s: %w", g.config.Timeout, err)
+	}
+
+	kcPath, err := g.getKubeConfigPath()
+	if err != nil {
+		return err
+	}
+
+	err = g.kubeClient.SetConfig(kcPath)
+	if err != nil {
+		return xerrors.Errorf("unable to create Kubernetes client: %w", err)
+	}
+
+	// Helm 3.1 does not create the namespace for the release
+	err = g.kubeClient.CreateNamespace(g.config.Namespace, nil, nil)
+	if err != nil {
+		return xerrors.Errorf("Unable to create namespace %s: %w", g.config.Namespace, err)
+	}
+
+	g.log.Debug("Installing chart", "ref", g.config.Name, "chart", tool.chart, "version", g.config.Version)
+	err = g.helmClient.CreateFromRepository(
+		kcPath,
+		g.config.Tool,
+		g.config.Namespace,
+		&config.HelmRepository{URL: tool.repository},
+		tool.chart,
+		g.config.Version,
+		"",
+		nil,
+	)
+	if err != nil {
+		return xerrors.Errorf("Unable to install %s: %w", g.config.Tool, err)
+	}
+
+	err = g.kubeClient.WaitForCRDs(tool.crds, timeout)
+	if err != nil {
+		return xerrors.Errorf("Custom resource definitions for %s not established: %w", g.config.Tool, err)
+	}
+
+	file, err := g.writeManifest()
+	if err != nil {
+		return err
+	}
+
+	err = g.kubeClient.Apply([]string{file}, false)
+	if err != nil {
+		return xerrors.Errorf("Unable to register repository: %w", err)
+	}
+
+	return nil
+}
+
+// Destroy removes the resources which sync the repository and uninstalls
+// the tool, resources created by the sync are not removed
+func (g *GitOps) Destroy() error {
+	g.log.Info("Destroy GitOps", "ref", g.config.Name, "tool", g.config.Tool)
+
+	kcPath, err := g.getKubeConfigPath()
+	if err != nil {
+		return err
+	}
+
+	err = g.kubeClient.SetConfig(kcPath)
+	if err != nil {
+		return xerrors.Errorf("unable to create Kubernetes client: %w", err)
+	}
+
+	file, err := g.writeManifest()
+	if err == nil {
+		err = g.kubeClient.Delete([]string{file})
+	}
+
+	if err != nil {
+		g.log.Debug("There was a problem removing the repository, logging message but ignoring error", "ref", g.config.Name, "error", err)
+	}
+
+	err = g.helmClient.Destroy(kcPath, g.config.Tool, g.config.Namespace)
+	if err != nil {
+		g.log.Debug("There was a problem destroying Helm chart, logging message but ignoring error", "ref", g.config.Name, "error", err)
+	}
+
+	os.Remove(file)
+
+	return nil
+}
+
+// Lookup implements the provider Lookup method
+func (g *GitOps) Lookup() ([]string, error) {
+	return []string{}, nil
+}
+
+// writeManifest writes the resources which sync the repository and returns
+// the path of the file
+func (g *GitOps) writeManifest() (string, error) {
+	dir := filepath.Join(utils.ShipyardTemp(), "gitops")
+
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return "", xerrors.Errorf("Unable to create folder for GitOps manifest: %w", err)
+	}
+
+	file := filepath.Join(dir, fmt.Sprintf("%s.yaml", g.config.Name))
+
+	err = ioutil.WriteFile(file, []byte(g.manifest()), 0644)
+	if err != nil {
+		return "", xerrors.Errorf("Unable to write GitOps manifest: %w", err)
+	}
+
+	return file, nil
+}
+
+func (g *GitOps) manifest() string {
+	// Kubernetes names can not contain underscores
+	name := strings.Replace(g.config.Name, "_", "-", -1)
+	c := g.config
+
+	if c.Tool == "flux" {
+		return fmt.Sprintf(fluxSource,
+			name, c.Namespace, c.Repository, c.Revision,
+			name, c.Namespace, c.Path, c.TargetNamespace, name,
+		)
+	}
+
+	return fmt.Sprintf(argoApplication, name, c.Namespace, c.Repository, c.Revision, c.Path, c.TargetNamespace)
+}
+
+func (g *GitOps) getKubeConfigPath() (string, error) {
+	target, err := g.config.FindDependentResource(g.config.Cluster)
+	if err != nil {
+		return "", xerrors.Errorf("Unable to find cluster: %w", err)
+	}
+
+	_, destPath, _ := utils.CreateKubeConfigPath(target.Info().Name)
+	return destPath, nil
+}
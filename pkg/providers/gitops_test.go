@@ -0,0 +1,130 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupGitOps(t *testing.T, tool string) (*config.GitOps, *mocks.MockKubernetes, *mocks.MockHelm, func()) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+
+	home := os.Getenv("HOME")
+	os.Setenv("HOME", dir)
+
+	g := config.NewGitOps("demo_app")
+	g.Cluster = "k8s_cluster.k3s"
+	g.Tool = tool
+	g.Namespace = "gitops"
+	g.Repository = "https://github.com/shipyard-run/demo.git"
+	g.Revision = "main"
+	g.Path = "deploy"
+
+	c := config.New()
+	c.AddResource(config.NewK8sCluster("k3s"))
+	c.AddResource(g)
+
+	mk := &mocks.MockKubernetes{}
+	mk.On("SetConfig", mock.Anything).Return(nil)
+	mk.On("CreateNamespace", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mk.On("WaitForCRDs", mock.Anything, mock.Anything).Return(nil)
+	mk.On("Apply", mock.Anything, mock.Anything).Return(nil)
+	mk.On("Delete", mock.Anything).Return(nil)
+
+	mh := &mocks.MockHelm{}
+	mh.On("CreateFromRepository", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mh.On("Destroy", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	return g, mk, mh, func() {
+		os.Setenv("HOME", home)
+		os.RemoveAll(dir)
+	}
+}
+
+func TestGitOpsInstallsArgoCD(t *testing.T) {
+	g, mk, mh, cleanup := setupGitOps(t, "argocd")
+	defer cleanup()
+
+	p := NewGitOps(g, mk, mh, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	mk.AssertCalled(t, "CreateNamespace", "gitops", mock.Anything, mock.Anything)
+
+	params := getCalls(&mh.Mock, "CreateFromRepository")[0].Arguments
+	assert.Equal(t, "argocd", params[1])
+	assert.Equal(t, "gitops", params[2])
+	assert.Equal(t, "https://argoproj.github.io/argo-helm", params[3].(*config.HelmRepository).URL)
+	assert.Equal(t, "argo-cd", params[4])
+
+	mk.AssertCalled(t, "WaitForCRDs", []string{"applications.argoproj.io"}, mock.Anything)
+
+	file := getCalls(&mk.Mock, "Apply")[0].Arguments[0].([]string)[0]
+	d, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(d), "kind: Application")
+	assert.Contains(t, string(d), "name: demo-app")
+	assert.Contains(t, string(d), "repoURL: https://github.com/shipyard-run/demo.git")
+	assert.Contains(t, string(d), "path: deploy")
+}
+
+func TestGitOpsInstallsFlux(t *testing.T) {
+	g, mk, mh, cleanup := setupGitOps(t, "flux")
+	defer cleanup()
+
+	p := NewGitOps(g, mk, mh, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	params := getCalls(&mh.Mock, "CreateFromRepository")[0].Arguments
+	assert.Equal(t, "flux2", params[4])
+
+	file := getCalls(&mk.Mock, "Apply")[0].Arguments[0].([]string)[0]
+	d, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(d), "kind: GitRepository")
+	assert.Contains(t, string(d), "kind: Kustomization")
+	assert.Contains(t, string(d), "branch: main")
+}
+
+func TestGitOpsReturnsErrorWhenCRDsNotEstablished(t *testing.T) {
+	g, mk, mh, cleanup := setupGitOps(t, "argocd")
+	defer cleanup()
+
+	removeOn(&mk.Mock, "WaitForCRDs")
+	mk.On("WaitForCRDs", mock.Anything, mock.Anything).Return(fmt.Errorf("timeout"))
+
+	p := NewGitOps(g, mk, mh, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+
+	mk.AssertNotCalled(t, "Apply", mock.Anything, mock.Anything)
+}
+
+func TestGitOpsDestroyRemovesRepositoryAndChart(t *testing.T) {
+	g, mk, mh, cleanup := setupGitOps(t, "argocd")
+	defer cleanup()
+
+	p := NewGitOps(g, mk, mh, hclog.NewNullLogger())
+
+	err := p.Destroy()
+	require.NoError(t, err)
+
+	mk.AssertNumberOfCalls(t, "Delete", 1)
+	mh.AssertCalled(t, "Destroy", mock.Anything, "argocd", "gitops")
+}
@@ -0,0 +1,87 @@
+package providers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"golang.org/x/xerrors"
+)
+
+// healthCheckInterval is the time to wait between failed exec health checks
+var healthCheckInterval = 1 * time.Second
+
+// execInContainer returns a function which runs the command for an exec health
+// check in the container with the given name
+func execInContainer(client clients.ContainerTasks, name string, typ config.ResourceType, l hclog.Logger) func([]string) error {
+	return func(command []string) error {
+		ids, err := client.FindContainerIDs(name, typ)
+		if err != nil {
+			return err
+		}
+
+		if len(ids) == 0 {
+			return fmt.Errorf("Unable to find container %s", name)
+		}
+
+		return client.ExecuteCommand(ids[0], command, []string{}, "/", l.StandardWriter(&hclog.StandardLoggerOptions{ForceLevel: hclog.Debug}))
+	}
+}
+
+// runHealthCheck runs the http, tcp, and exec checks of a health_check block,
+// each check is retried until the timeout elapses. exec runs the command for
+// an exec check and is nil for resources which do not run in a container
+func runHealthCheck(hc *config.HealthCheck, httpClient clients.HTTP, exec func(command []string) error, l hclog.Logger) error {
+	if hc == nil {
+		return nil
+	}
+
+	timeout, err := time.ParseDuration(hc.Timeout)
+	if err != nil {
+		return xerrors.Errorf("Unable to parse health check timeout: %w", err)
+	}
+
+	if hc.HTTP != "" {
+		l.Debug("Performing HTTP health check", "address", hc.HTTP)
+
+		err := httpClient.HealthCheckHTTP(hc.HTTP, timeout)
+		if err != nil {
+			return xerrors.Errorf("HTTP health check failed: %w", err)
+		}
+	}
+
+	if hc.TCP != "" {
+		l.Debug("Performing TCP health check", "address", hc.TCP)
+
+		err := httpClient.HealthCheckTCP(hc.TCP, timeout)
+		if err != nil {
+			return xerrors.Errorf("TCP health check failed: %w", err)
+		}
+	}
+
+	if len(hc.Exec) == 0 {
+		return nil
+	}
+
+	if exec == nil {
+		return fmt.Errorf("Exec health checks are only supported for resources which run in a container")
+	}
+
+	l.Debug("Performing exec health check", "command", hc.Exec)
+
+	deadline := time.Now().Add(timeout)
+	for {
+		err := exec(hc.Exec)
+		if err == nil {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return xerrors.Errorf("Exec health check failed: %w", err)
+		}
+
+		time.Sleep(healthCheckInterval)
+	}
+}
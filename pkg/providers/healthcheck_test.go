@@ -0,0 +1,91 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHealthCheckRunsHTTPAndTCPChecks(t *testing.T) {
+	hc := &mocks.MockHTTP{}
+	hc.On("HealthCheckHTTP", mock.Anything, mock.Anything).Return(nil)
+	hc.On("HealthCheckTCP", mock.Anything, mock.Anything).Return(nil)
+
+	err := runHealthCheck(
+		&config.HealthCheck{Timeout: "10s", HTTP: "http://localhost:8500", TCP: "localhost:8300"},
+		hc, nil, hclog.NewNullLogger(),
+	)
+	assert.NoError(t, err)
+
+	hc.AssertCalled(t, "HealthCheckHTTP", "http://localhost:8500", 10*time.Second)
+	hc.AssertCalled(t, "HealthCheckTCP", "localhost:8300", 10*time.Second)
+}
+
+func TestHealthCheckTCPFailReturnsError(t *testing.T) {
+	hc := &mocks.MockHTTP{}
+	hc.On("HealthCheckTCP", mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
+
+	err := runHealthCheck(&config.HealthCheck{Timeout: "10s", TCP: "localhost:8300"}, hc, nil, hclog.NewNullLogger())
+	assert.Error(t, err)
+}
+
+func TestHealthCheckRetriesExecUntilSuccess(t *testing.T) {
+	healthCheckInterval = time.Millisecond
+	defer func() { healthCheckInterval = 1 * time.Second }()
+
+	calls := 0
+	exec := func(command []string) error {
+		calls++
+		if calls < 3 {
+			return fmt.Errorf("boom")
+		}
+
+		return nil
+	}
+
+	err := runHealthCheck(&config.HealthCheck{Timeout: "10s", Exec: []string{"consul", "members"}}, nil, exec, hclog.NewNullLogger())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestHealthCheckExecTimeoutReturnsError(t *testing.T) {
+	healthCheckInterval = time.Millisecond
+	defer func() { healthCheckInterval = 1 * time.Second }()
+
+	exec := func(command []string) error {
+		return fmt.Errorf("boom")
+	}
+
+	err := runHealthCheck(&config.HealthCheck{Timeout: "10ms", Exec: []string{"consul", "members"}}, nil, exec, hclog.NewNullLogger())
+	assert.Error(t, err)
+}
+
+func TestHealthCheckExecWithoutContainerReturnsError(t *testing.T) {
+	err := runHealthCheck(&config.HealthCheck{Timeout: "10s", Exec: []string{"consul", "members"}}, nil, nil, hclog.NewNullLogger())
+	assert.Error(t, err)
+}
+
+func TestContainerRunsExecChecksInContainer(t *testing.T) {
+	cc := config.NewContainer("tests")
+	cc.HealthCheck = &config.HealthCheck{Timeout: "10s", Exec: []string{"consul", "members"}}
+
+	md := &mocks.MockContainerTasks{}
+	c := NewContainer(cc, md, &mocks.MockHTTP{}, hclog.NewNullLogger())
+
+	md.On("PullImage", cc.Image, false).Once().Return(nil)
+	md.On("CreateContainer", cc).Once().Return("abc", nil)
+	md.On("FindContainerIDs", "tests", config.TypeContainer).Return([]string{"abc"}, nil)
+	md.On("ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	err := c.Create(context.Background())
+	assert.NoError(t, err)
+
+	md.AssertCalled(t, "ExecuteCommand", "abc", []string{"consul", "members"}, []string{}, "/", mock.Anything)
+}
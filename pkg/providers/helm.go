@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"path/filepath"
 	"strings"
 	"time"
@@ -17,16 +18,17 @@ type Helm struct {
 	kubeClient   clients.Kubernetes
 	helmClient   clients.Helm
 	getterClient clients.Getter
+	httpClient   clients.HTTP
 	log          hclog.Logger
 }
 
 // NewHelm creates a new Helm provider
-func NewHelm(c *config.Helm, kc clients.Kubernetes, hc clients.Helm, g clients.Getter, l hclog.Logger) *Helm {
-	return &Helm{c, kc, hc, g, l}
+func NewHelm(c *config.Helm, kc clients.Kubernetes, hc clients.Helm, g clients.Getter, httpc clients.HTTP, l hclog.Logger) *Helm {
+	return &Helm{c, kc, hc, g, httpc, l}
 }
 
 // Create implements the provider Create method
-func (h *Helm) Create() error {
+func (h *Helm) Create(ctx context.Context) error {
 	h.log.Info("Creating Helm chart", "ref", h.config.Name)
 
 	// get the target cluster
@@ -41,7 +43,7 @@ func (h *Helm) Create() error {
 	}
 
 	// is the source a helm repo which should be downloaded?
-	if !utils.IsLocalFolder(h.config.Chart) {
+	if h.config.Repository == "" && !utils.IsLocalFolder(h.config.Chart) {
 		h.log.Debug("Fetching remote Helm chart", "ref", h.config.Name, "chart", h.config.Chart)
 
 		helmFolder := filepath.Join(utils.GetHelmLocalFolder(""), strings.Replace(h.config.Chart, "//", "/", -1))
@@ -63,9 +65,22 @@ func (h *Helm) Create() error {
 		return xerrors.Errorf("unable to create Kubernetes client: %w", err)
 	}
 
-	err = h.helmClient.Create(kcPath, h.config.Name, h.config.Namespace, h.config.Chart, h.config.Values, h.config.ValuesString)
-	if err != nil {
-		return err
+	if h.config.Repository != "" {
+		repo, err := h.getRepository()
+		if err != nil {
+			return err
+		}
+
+		h.log.Debug("Installing chart from repository", "ref", h.config.Name, "repository", repo.URL, "chart", h.config.Chart, "version", h.config.Version)
+		err = h.helmClient.CreateFromRepository(kcPath, h.config.Name, h.config.Namespace, repo, h.config.Chart, h.config.Version, h.config.Values, h.config.ValuesString)
+		if err != nil {
+			return err
+		}
+	} else {
+		err = h.helmClient.Create(kcPath, h.config.Name, h.config.Namespace, h.config.Chart, h.config.Values, h.config.ValuesString)
+		if err != nil {
+			return err
+		}
 	}
 
 	// we can now health check the install
@@ -81,7 +96,8 @@ func (h *Helm) Create() error {
 		}
 	}
 
-	return nil
+	// charts do not run in a container so exec checks are not supported
+	return runHealthCheck(h.config.HealthCheck, h.httpClient, nil, h.log)
 }
 
 // Destroy implements the provider Destroy method
@@ -112,6 +128,21 @@ func (h *Helm) Lookup() ([]string, error) {
 	return []string{}, nil
 }
 
+// getRepository returns the repository to install the chart from, the
+// repository is either a helm_repository resource or a url
+func (h *Helm) getRepository() (*config.HelmRepository, error) {
+	if !strings.HasPrefix(h.config.Repository, string(config.TypeHelmRepository)+".") {
+		return &config.HelmRepository{URL: h.config.Repository}, nil
+	}
+
+	r, err := h.config.FindDependentResource(h.config.Repository)
+	if err != nil {
+		return nil, xerrors.Errorf("Unable to find Helm repository: %w", err)
+	}
+
+	return r.(*config.HelmRepository), nil
+}
+
 func (h *Helm) getKubeConfigPath() (string, error) {
 	target, err := h.config.FindDependentResource(h.config.Cluster)
 	if err != nil {
@@ -0,0 +1,40 @@
+package providers
+
+import (
+	"context"
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+)
+
+// HelmRepository is a provider for Helm repositories
+type HelmRepository struct {
+	config     *config.HelmRepository
+	helmClient clients.Helm
+	log        hclog.Logger
+}
+
+// NewHelmRepository creates a new Helm repository provider
+func NewHelmRepository(c *config.HelmRepository, hc clients.Helm, l hclog.Logger) *HelmRepository {
+	return &HelmRepository{c, hc, l}
+}
+
+// Create downloads the index for the repository, this checks that the
+// repository can be reached before any charts are installed
+func (h *HelmRepository) Create(ctx context.Context) error {
+	h.log.Info("Updating Helm repository", "ref", h.config.Name, "url", h.config.URL)
+
+	return h.helmClient.UpdateRepository(h.config)
+}
+
+// Destroy does nothing
+func (h *HelmRepository) Destroy() error {
+	h.log.Info("Destroy Helm repository", "ref", h.config.Name)
+
+	return nil
+}
+
+// Lookup returns nothing
+func (h *HelmRepository) Lookup() ([]string, error) {
+	return []string{}, nil
+}
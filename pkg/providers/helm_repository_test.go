@@ -0,0 +1,39 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHelmRepositoryCreateUpdatesRepository(t *testing.T) {
+	hr := config.NewHelmRepository("hashicorp")
+	hr.URL = "https://helm.releases.hashicorp.com"
+
+	mh := &mocks.MockHelm{}
+	mh.On("UpdateRepository", mock.Anything).Return(nil)
+
+	p := NewHelmRepository(hr, mh, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+	mh.AssertCalled(t, "UpdateRepository", hr)
+}
+
+func TestHelmRepositoryCreateReturnsErrorWhenUpdateFails(t *testing.T) {
+	hr := config.NewHelmRepository("hashicorp")
+
+	mh := &mocks.MockHelm{}
+	mh.On("UpdateRepository", mock.Anything).Return(fmt.Errorf("boom"))
+
+	p := NewHelmRepository(hr, mh, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+}
@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -17,6 +18,7 @@ import (
 func setupHelm() (*clients.MockHelm, *clients.MockKubernetes, *clients.Getter, *config.Config, *Helm) {
 	mh := &clients.MockHelm{}
 	mh.On("Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mh.On("CreateFromRepository", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	mh.On("Destroy", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	kc := &clients.MockKubernetes{}
@@ -34,7 +36,7 @@ func setupHelm() (*clients.MockHelm, *clients.MockKubernetes, *clients.Getter, *
 	c.AddResource(cl)
 	c.AddResource(ch)
 
-	p := NewHelm(ch, kc, mh, mg, hclog.NewNullLogger())
+	p := NewHelm(ch, kc, mh, mg, &clients.MockHTTP{}, hclog.NewNullLogger())
 
 	return mh, kc, mg, c, p
 }
@@ -43,7 +45,7 @@ func TestHelmCreateCantFindClusterReturnsError(t *testing.T) {
 	_, _, _, c, p := setupHelm()
 	c.RemoveResource(c.Resources[0])
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -53,17 +55,51 @@ func TestHelmCreateGetsRemoteRepo(t *testing.T) {
 	hc.(*config.Helm).Chart = "github.com/shipyard-run/blueprints//vault-k8s"
 	helmFolder := filepath.Join(utils.ShipyardHome(), "helm_charts", strings.Replace(hc.(*config.Helm).Chart, "//", "/", -1))
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	mg.AssertCalled(t, "Get", mock.Anything, helmFolder)
 	mh.AssertCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything, helmFolder, mock.Anything, mock.Anything)
 }
 
+func TestHelmCreateInstallsChartFromRepositoryResource(t *testing.T) {
+	mh, _, mg, c, p := setupHelm()
+
+	hr := config.NewHelmRepository("hashicorp")
+	hr.URL = "https://helm.releases.hashicorp.com"
+	c.AddResource(hr)
+
+	hc, _ := c.FindResource("helm.test")
+	hc.(*config.Helm).Repository = "helm_repository.hashicorp"
+	hc.(*config.Helm).Chart = "consul"
+	hc.(*config.Helm).Version = "0.30.0"
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	mg.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+	mh.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mh.AssertCalled(t, "CreateFromRepository", mock.Anything, "test", "default", hr, "consul", "0.30.0", mock.Anything, mock.Anything)
+}
+
+func TestHelmCreateInstallsChartFromRepositoryURL(t *testing.T) {
+	mh, _, _, c, p := setupHelm()
+
+	hc, _ := c.FindResource("helm.test")
+	hc.(*config.Helm).Repository = "https://helm.releases.hashicorp.com"
+	hc.(*config.Helm).Chart = "consul"
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	repo := getCalls(&mh.Mock, "CreateFromRepository")[0].Arguments[3].(*config.HelmRepository)
+	assert.Equal(t, "https://helm.releases.hashicorp.com", repo.URL)
+}
+
 func TestHelmCreateSetsConfig(t *testing.T) {
 	_, kc, mg, _, p := setupHelm()
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	_, fp, _ := utils.CreateKubeConfigPath("tester")
@@ -76,14 +112,14 @@ func TestHelmCreateConfigSetFailReturnsError(t *testing.T) {
 	removeOn(&kc.Mock, "SetConfig")
 	kc.On("SetConfig", mock.Anything).Return(fmt.Errorf("boom"))
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
 func TestHelmCreateCallsCreateWithDefaultNamespace(t *testing.T) {
 	hm, _, _, _, p := setupHelm()
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	hm.AssertCalled(
@@ -102,7 +138,7 @@ func TestHelmCreateCallsCreateWithCustomNamespace(t *testing.T) {
 	hm, _, _, _, p := setupHelm()
 	p.config.Namespace = "custom"
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	hm.AssertCalled(
@@ -122,14 +158,14 @@ func TestHelmCreateCallCreateFailReturnsError(t *testing.T) {
 	removeOn(&hm.Mock, "Create")
 	hm.On("Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
 func TestHelmDoesNotHealthChecksPodswhenNotSet(t *testing.T) {
 	_, kc, _, _, p := setupHelm()
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	kc.AssertNotCalled(t, "HealthCheckPods", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
@@ -139,7 +175,7 @@ func TestHelmHealthChecksPodswhenSet(t *testing.T) {
 	_, kc, _, _, p := setupHelm()
 	p.config.HealthCheck = &config.HealthCheck{Timeout: "1s", Pods: []string{"consul=release"}}
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	kc.AssertCalled(t, "HealthCheckPods", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
@@ -151,7 +187,7 @@ func TestHelmCreateHealthCheckPodsFailReturnsError(t *testing.T) {
 	removeOn(&kc.Mock, "HealthCheckPods")
 	kc.On("HealthCheckPods", mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 func TestHelmDestroyCantFindClusterReturnsError(t *testing.T) {
@@ -0,0 +1,55 @@
+package providers
+
+import (
+	"context"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"golang.org/x/xerrors"
+)
+
+// HTTPCheck is a provider which waits for a HTTP or TCP endpoint to become healthy
+type HTTPCheck struct {
+	config     *config.HTTPCheck
+	httpClient clients.HTTP
+	log        hclog.Logger
+}
+
+// NewHTTPCheck creates a provider which polls a HTTP or TCP endpoint
+func NewHTTPCheck(c *config.HTTPCheck, hc clients.HTTP, l hclog.Logger) *HTTPCheck {
+	return &HTTPCheck{c, hc, l}
+}
+
+// Create blocks until the endpoint is healthy or the timeout expires
+func (h *HTTPCheck) Create(ctx context.Context) error {
+	h.log.Info("Checking endpoint health", "ref", h.config.Name, "http", h.config.HTTP, "tcp", h.config.TCP)
+
+	timeout, err := time.ParseDuration(h.config.Timeout)
+	if err != nil {
+		return xerrors.Errorf("Unable to parse timeout %s: %w", h.config.Timeout, err)
+	}
+
+	if h.config.TCP != "" {
+		err = h.httpClient.HealthCheckTCP(h.config.TCP, timeout)
+	} else {
+		err = h.httpClient.HealthCheckHTTPResponse(h.config.HTTP, h.config.Method, h.config.StatusCodes, h.config.Body, timeout)
+	}
+
+	if err != nil {
+		return xerrors.Errorf("Endpoint is not healthy: %w", err)
+	}
+
+	return nil
+}
+
+// Destroy does nothing, there is nothing to remove
+func (h *HTTPCheck) Destroy() error {
+	return nil
+}
+
+// Lookup returns nothing
+func (h *HTTPCheck) Lookup() ([]string, error) {
+	return []string{}, nil
+}
@@ -0,0 +1,58 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupHTTPCheck() (*config.HTTPCheck, *mocks.MockHTTP) {
+	hc := config.NewHTTPCheck("consul")
+	hc.HTTP = "http://localhost:8500/v1/status/leader"
+	hc.Body = "8300"
+
+	mh := &mocks.MockHTTP{}
+	mh.On("HealthCheckHTTPResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mh.On("HealthCheckTCP", mock.Anything, mock.Anything).Return(nil)
+
+	return hc, mh
+}
+
+func TestHTTPCheckChecksHTTPEndpoint(t *testing.T) {
+	hc, mh := setupHTTPCheck()
+	p := NewHTTPCheck(hc, mh, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	mh.AssertCalled(t, "HealthCheckHTTPResponse", hc.HTTP, "GET", []int{200}, "8300", mock.Anything)
+	mh.AssertNotCalled(t, "HealthCheckTCP", mock.Anything, mock.Anything)
+}
+
+func TestHTTPCheckChecksTCPEndpoint(t *testing.T) {
+	hc, mh := setupHTTPCheck()
+	hc.HTTP = ""
+	hc.TCP = "localhost:5432"
+	p := NewHTTPCheck(hc, mh, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	mh.AssertCalled(t, "HealthCheckTCP", "localhost:5432", mock.Anything)
+}
+
+func TestHTTPCheckReturnsErrorWhenUnhealthy(t *testing.T) {
+	hc, mh := setupHTTPCheck()
+	removeOn(&mh.Mock, "HealthCheckHTTPResponse")
+	mh.On("HealthCheckHTTPResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("timeout"))
+	p := NewHTTPCheck(hc, mh, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+}
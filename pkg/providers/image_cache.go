@@ -0,0 +1,165 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"golang.org/x/xerrors"
+)
+
+const registryImage = "registry:2"
+const registryPort = 5000
+
+// ImageCache is a provider for creating a pull through registry cache
+type ImageCache struct {
+	config *config.ImageCache
+	client clients.ContainerTasks
+	log    hclog.Logger
+}
+
+// NewImageCache creates a new image cache provider
+func NewImageCache(ic *config.ImageCache, cl clients.ContainerTasks, l hclog.Logger) *ImageCache {
+	return &ImageCache{ic, cl, l}
+}
+
+// Create the registry container, cached images are stored in
+// $HOME/.shipyard/cache/[name] so that they are kept when the
+// cache is destroyed
+func (i *ImageCache) Create(ctx context.Context) error {
+	i.log.Info("Creating Image Cache", "ref", i.config.Name, "remote", i.config.RemoteURL)
+
+	ids, err := i.Lookup()
+	if err != nil {
+		return xerrors.Errorf("Unable to lookup image cache: %w", err)
+	}
+
+	if len(ids) > 0 {
+		i.log.Debug("Image cache already exists", "ref", i.config.Name)
+		return nil
+	}
+
+	cc := config.NewContainer(i.config.Name)
+	i.config.ResourceInfo.AddChild(cc)
+
+	cc.Image = config.Image{Name: registryImage}
+	cc.Networks = i.config.Networks
+
+	err = i.client.PullImage(ctx, cc.Image, false)
+	if err != nil {
+		return err
+	}
+
+	dir := imageCacheDir(i.config.Name)
+	err = os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return xerrors.Errorf("Unable to create image cache folder %s: %w", dir, err)
+	}
+
+	cc.Volumes = []config.Volume{
+		config.Volume{
+			Source:      dir,
+			Destination: "/var/lib/registry",
+		},
+	}
+
+	cc.Environment = []config.KV{
+		config.KV{Key: "REGISTRY_PROXY_REMOTEURL", Value: i.config.RemoteURL},
+	}
+
+	_, err = i.client.CreateContainer(cc)
+	return err
+}
+
+// Destroy the registry container, the cached images are not removed
+func (i *ImageCache) Destroy() error {
+	i.log.Info("Destroy Image Cache", "ref", i.config.Name)
+
+	ids, err := i.Lookup()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		err := i.client.RemoveContainer(id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Lookup the ID of the registry container
+func (i *ImageCache) Lookup() ([]string, error) {
+	return i.client.FindContainerIDs(i.config.Name, i.config.Type)
+}
+
+// imageCacheDir returns the folder used to store the images for the cache
+func imageCacheDir(name string) string {
+	return filepath.Join(utils.ShipyardHome(), "cache", name)
+}
+
+// imageCacheAddress returns the address of the cache which can be used
+// by containers attached to the same network
+func imageCacheAddress(ic *config.ImageCache) string {
+	return fmt.Sprintf("http://%s:%d", utils.FQDN(ic.Name, string(ic.Type)), registryPort)
+}
+
+// findImageCache returns the image cache which shares a network with
+// the resource, nil is returned when there is no image cache
+func findImageCache(ri *config.ResourceInfo, networks []config.NetworkAttachment) *config.ImageCache {
+	if ri.Config == nil {
+		return nil
+	}
+
+	return ri.Config.FindImageCache(networks)
+}
+
+// imageCacheRegistry returns the name of the registry which is cached,
+// images from Docker Hub use the name docker.io
+func imageCacheRegistry(ic *config.ImageCache) string {
+	u, err := url.Parse(ic.RemoteURL)
+	if err != nil || u.Host == "" {
+		return ic.RemoteURL
+	}
+
+	if u.Host == "registry-1.docker.io" {
+		return "docker.io"
+	}
+
+	return u.Host
+}
+
+// writeK3sRegistries writes a k3s registries.yaml file to the folder which
+// configures containerd to use the image cache as a mirror
+func writeK3sRegistries(dir string, ic *config.ImageCache) (string, error) {
+	reg := fmt.Sprintf("mirrors:\n  %q:\n    endpoint:\n      - %q\n", imageCacheRegistry(ic), imageCacheAddress(ic))
+
+	path := filepath.Join(dir, "registries.yaml")
+	return path, ioutil.WriteFile(path, []byte(reg), 0644)
+}
+
+// writeDockerDaemonConfig writes a Docker daemon.json file to the folder
+// which configures Docker to use the image cache as a registry mirror,
+// Docker only supports mirrors for Docker Hub
+func writeDockerDaemonConfig(dir string, ic *config.ImageCache) (string, error) {
+	d, err := json.MarshalIndent(map[string][]string{
+		"registry-mirrors":    []string{imageCacheAddress(ic)},
+		"insecure-registries": []string{fmt.Sprintf("%s:%d", utils.FQDN(ic.Name, string(ic.Type)), registryPort)},
+	}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, "daemon.json")
+	return path, ioutil.WriteFile(path, d, 0644)
+}
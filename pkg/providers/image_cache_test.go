@@ -0,0 +1,79 @@
+package providers
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupImageCache(t *testing.T, ids []string) (*ImageCache, *mocks.MockContainerTasks, func()) {
+	ic := config.NewImageCache("docker")
+	ic.Networks = []config.NetworkAttachment{{Name: "network.cloud"}}
+
+	md := &mocks.MockContainerTasks{}
+	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return(ids, nil)
+	md.On("PullImage", mock.Anything, mock.Anything).Return(nil)
+	md.On("CreateContainer", mock.Anything).Return("abc", nil)
+	md.On("RemoveContainer", mock.Anything).Return(nil)
+
+	tmpDir, _ := ioutil.TempDir("", "")
+	currentHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+
+	return NewImageCache(ic, md, hclog.NewNullLogger()), md, func() {
+		os.Setenv("HOME", currentHome)
+		os.RemoveAll(tmpDir)
+	}
+}
+
+func TestImageCacheCreatesRegistry(t *testing.T) {
+	p, md, cleanup := setupImageCache(t, nil)
+	defer cleanup()
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+	assert.Equal(t, "docker", params.Name)
+	assert.Equal(t, config.TypeImageCache, params.Type)
+	assert.Equal(t, registryImage, params.Image.Name)
+	assert.Equal(t, "network.cloud", params.Networks[0].Name)
+	assert.Equal(t, imageCacheDir("docker"), params.Volumes[0].Source)
+	assert.DirExists(t, params.Volumes[0].Source)
+	assert.Equal(t, config.KV{Key: "REGISTRY_PROXY_REMOTEURL", Value: "https://registry-1.docker.io"}, params.Environment[0])
+}
+
+func TestImageCacheDoesNotCreateWhenExists(t *testing.T) {
+	p, md, cleanup := setupImageCache(t, []string{"abc"})
+	defer cleanup()
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	md.AssertNotCalled(t, "CreateContainer", mock.Anything)
+}
+
+func TestImageCacheDestroyRemovesContainer(t *testing.T) {
+	p, md, cleanup := setupImageCache(t, []string{"abc"})
+	defer cleanup()
+
+	err := p.Destroy()
+	assert.NoError(t, err)
+
+	md.AssertCalled(t, "RemoveContainer", "abc")
+}
+
+func TestImageCacheRegistryReturnsDockerHubName(t *testing.T) {
+	ic := config.NewImageCache("docker")
+	assert.Equal(t, "docker.io", imageCacheRegistry(ic))
+
+	ic.RemoteURL = "https://quay.io"
+	assert.Equal(t, "quay.io", imageCacheRegistry(ic))
+}
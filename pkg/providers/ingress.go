@@ -1,7 +1,9 @@
 package providers
 
 import (
+	"context"
 	"fmt"
+	"strings"
 
 	hclog "github.com/hashicorp/go-hclog"
 	"github.com/shipyard-run/shipyard/pkg/clients"
@@ -12,6 +14,10 @@ import (
 
 const ingressImage = "shipyardrun/ingress:latest"
 
+// tcpIngressImage is used for ingress with the tcp protocol, connections
+// are passed through to the target by socat
+const tcpIngressImage = "alpine/socat:1.7.4.1-r1"
+
 type Ingress struct {
 	config *config.Ingress
 	client clients.ContainerTasks
@@ -30,6 +36,7 @@ func NewContainerIngress(ci *config.ContainerIngress, cc clients.ContainerTasks,
 	c.Networks = ci.Networks
 	c.Target = ci.Target
 	c.Ports = ci.Ports
+	c.Protocol = ci.Protocol
 	c.Config = ci.Config
 
 	return &Ingress{c, cc, l}
@@ -42,6 +49,7 @@ func NewNomadIngress(ci *config.NomadIngress, cc clients.ContainerTasks, l hclog
 	c.Networks = ci.Networks
 	c.Target = ci.Cluster
 	c.Ports = ci.Ports
+	c.Protocol = ci.Protocol
 	c.Config = ci.Config
 
 	return &Ingress{c, cc, l}
@@ -70,6 +78,7 @@ func NewK8sIngress(kc *config.K8sIngress, cc clients.ContainerTasks, l hclog.Log
 
 	c.Namespace = kc.Namespace
 	c.Ports = kc.Ports
+	c.Protocol = kc.Protocol
 
 	c.Config = kc.Config
 
@@ -77,7 +86,7 @@ func NewK8sIngress(kc *config.K8sIngress, cc clients.ContainerTasks, l hclog.Log
 }
 
 // Create the ingress
-func (i *Ingress) Create() error {
+func (i *Ingress) Create(ctx context.Context) error {
 	i.log.Info("Creating Ingress", "ref", i.config.Name)
 
 	// check the ingress does not already exist
@@ -91,17 +100,10 @@ func (i *Ingress) Create() error {
 		return xerrors.Errorf("Unable to lookup ingress id: %w", err)
 	}
 
-	// pull any images needed for this container
-	err = i.client.PullImage(config.Image{Name: ingressImage}, false)
-	if err != nil {
-		i.log.Error("Error pulling container image", "ref", i.config.Name, "image", ingressImage)
-
-		return err
-	}
-
 	var serviceName string
 	var volumes []config.Volume
 	var env []config.KV
+	var kubeProxy bool
 	command := make([]string, 0)
 
 	target, err := i.config.FindDependentResource(i.config.Target)
@@ -114,46 +116,66 @@ func (i *Ingress) Create() error {
 		serviceName = utils.FQDN(target.Info().Name, string(target.Info().Type))
 	case config.TypeNomadCluster:
 		serviceName = utils.FQDN(fmt.Sprintf("server.%s", target.Info().Name), string(target.Info().Type))
-	case config.TypeK8sCluster:
-		v := target.(*config.K8sCluster)
+	case config.TypeK8sCluster, config.TypeK8sClusterExternal:
 		// determine the type of cluster
-		// if this is a k3s cluster we need to add the kubeconfig and
-		// make sure that the proxy runs in kube mode
-		if v.Driver == "k3s" {
-			serviceName = i.config.Service
-			_, _, kubeConfigPath := utils.CreateKubeConfigPath(v.Name)
-			volumes = append(volumes, config.Volume{
-				Source:      kubeConfigPath,
-				Destination: "/.kube/kubeconfig.yml",
-			})
-
-			env = append(env, config.KV{Key: "KUBECONFIG", Value: "/.kube/kubeconfig.yml"})
-
-			command = append(command, "--proxy-type")
-			command = append(command, "kubernetes")
-
-			// if the namespace is not present assume default
-			if i.config.Namespace == "" {
-				i.config.Namespace = "default"
-			}
-
-			command = append(command, "--namespace")
-			command = append(command, i.config.Namespace)
-		} else {
+		// if this is a k3s, kind, or external cluster we need to add the
+		// kubeconfig and make sure that the proxy runs in kube mode
+		if v, ok := target.(*config.K8sCluster); ok && v.Driver != "k3s" && v.Driver != "kind" {
 			serviceName = fmt.Sprintf("server.%s", utils.FQDN(v.Name, string(v.Type)))
+			break
 		}
 
+		kubeProxy = true
+		serviceName = i.config.Service
+		_, _, kubeConfigPath := utils.CreateKubeConfigPath(target.Info().Name)
+		volumes = append(volumes, config.Volume{
+			Source:      kubeConfigPath,
+			Destination: "/.kube/kubeconfig.yml",
+		})
+
+		env = append(env, config.KV{Key: "KUBECONFIG", Value: "/.kube/kubeconfig.yml"})
+
+		command = append(command, "--proxy-type")
+		command = append(command, "kubernetes")
+
+		// if the namespace is not present assume default
+		if i.config.Namespace == "" {
+			i.config.Namespace = "default"
+		}
+
+		command = append(command, "--namespace")
+		command = append(command, i.config.Namespace)
+
 	default:
 		return fmt.Errorf("Only Containers, Kubernetes clusters, and Nomad clusters are supported at present")
 	}
 
-	command = append(command, "--service-name")
-	command = append(command, serviceName)
+	image := ingressImage
+	var entrypoint []string
+
+	// the Kubernetes proxy forwards ports using the Kubernetes API which
+	// already passes through raw TCP connections
+	if i.config.Protocol == "tcp" && !kubeProxy {
+		image = tcpIngressImage
+		entrypoint = []string{"sh", "-c"}
+		command = []string{tcpProxyCommand(serviceName, i.config.Ports)}
+	} else {
+		command = append(command, "--service-name")
+		command = append(command, serviceName)
+
+		// add the ports
+		for _, p := range i.config.Ports {
+			command = append(command, "--ports")
+			command = append(command, fmt.Sprintf("%s:%s", p.Local, p.Remote))
+		}
+	}
+
+	// pull any images needed for this container
+	err = i.client.PullImage(ctx, config.Image{Name: image}, false)
+	if err != nil {
+		i.log.Error("Error pulling container image", "ref", i.config.Name, "image", image)
 
-	// add the ports
-	for _, p := range i.config.Ports {
-		command = append(command, "--ports")
-		command = append(command, fmt.Sprintf("%s:%s", p.Local, p.Remote))
+		return err
 	}
 
 	// ingress simply crease a container with specific options
@@ -162,7 +184,8 @@ func (i *Ingress) Create() error {
 
 	c.Networks = i.config.Networks
 	c.Ports = i.config.Ports
-	c.Image = config.Image{Name: ingressImage}
+	c.Image = config.Image{Name: image}
+	c.Entrypoint = entrypoint
 	c.Command = command
 	c.Volumes = volumes
 	c.Environment = env
@@ -178,6 +201,18 @@ func (i *Ingress) Create() error {
 	return nil
 }
 
+// tcpProxyCommand returns a shell command which runs a socat process for
+// each port, connections to the local port are passed to the remote port
+// of the service
+func tcpProxyCommand(service string, ports []config.Port) string {
+	procs := []string{}
+	for _, p := range ports {
+		procs = append(procs, fmt.Sprintf("socat TCP-LISTEN:%s,fork,reuseaddr TCP:%s:%s &", p.Local, service, p.Remote))
+	}
+
+	return strings.Join(append(procs, "wait"), " ")
+}
+
 // Destroy the ingress
 func (i *Ingress) Destroy() error {
 	i.log.Info("Destroy Ingress", "ref", i.config.Name, "type", i.config.Type)
@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -38,7 +39,7 @@ func TestIngressK8sErrorsWhenUnableToLookupIDs(t *testing.T) {
 
 	p := NewK8sIngress(&testK8sIngressConfig, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -48,7 +49,7 @@ func TestIngressK8sErrorsWhenClusterExists(t *testing.T) {
 
 	p := NewK8sIngress(&testK8sIngressConfig, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -56,7 +57,7 @@ func TestIngressK8sTargetPullsImage(t *testing.T) {
 	md := testIngressCreateMocks()
 	p := NewK8sIngress(&testK8sIngressConfig, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 	md.AssertCalled(t, "PullImage", config.Image{Name: ingressImage}, false)
 }
@@ -65,7 +66,7 @@ func TestIngressK8sTargetConfiguresCommand(t *testing.T) {
 	md := testIngressCreateMocks()
 	p := NewK8sIngress(&testK8sIngressConfig, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
@@ -85,7 +86,7 @@ func TestIngressK8sTargetConfiguresKubeConfig(t *testing.T) {
 	md := testIngressCreateMocks()
 	p := NewK8sIngress(&testK8sIngressConfig, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
@@ -99,13 +100,34 @@ func TestIngressK8sTargetConfiguresKubeConfig(t *testing.T) {
 	assert.Equal(t, "/.kube/kubeconfig.yml", params.Environment[0].Value)
 }
 
+func TestIngressExternalK8sTargetUsesKubernetesProxy(t *testing.T) {
+	md := testIngressCreateMocks()
+
+	ec := config.NewK8sClusterExternal("staging")
+	testCluster.Config.AddResource(ec)
+
+	tc := testK8sIngressConfig
+	tc.Cluster = "k8s_cluster_external.staging"
+
+	p := NewK8sIngress(&tc, md, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+	assert.Equal(t, "kubernetes", params.Command[1])
+
+	_, _, path := utils.CreateKubeConfigPath("staging")
+	assert.Equal(t, path, params.Volumes[0].Source)
+}
+
 func TestIngressK8sTargetWithNamespaceConfiguresCommand(t *testing.T) {
 	md := testIngressCreateMocks()
 	tc := testK8sIngressConfig
 	tc.Namespace = "mine"
 	p := NewK8sIngress(&tc, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
@@ -120,7 +142,7 @@ func TestIngressK8sTargetWithServiceConfiguresCommand(t *testing.T) {
 	tc.Service = "myservice"
 	p := NewK8sIngress(&tc, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
@@ -136,7 +158,7 @@ func TestIngressK8sTargetWithPodConfiguresCommand(t *testing.T) {
 	tc.Pod = "mypod"
 	p := NewK8sIngress(&tc, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
@@ -152,7 +174,7 @@ func TestIngressK8sTargetWithDeploymentConfiguresCommand(t *testing.T) {
 	tc.Deployment = "mydeployment"
 	p := NewK8sIngress(&tc, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
@@ -165,7 +187,7 @@ func TestIngressContainerTargetConfiguresCommand(t *testing.T) {
 	md := testIngressCreateMocks()
 	p := NewContainerIngress(&testIngressContainerConfig, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
@@ -178,7 +200,7 @@ func TestIngressContainerAddsPorts(t *testing.T) {
 	md := testIngressCreateMocks()
 	p := NewContainerIngress(&testIngressContainerConfig, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
@@ -192,13 +214,48 @@ func TestIngressContainerAddsPorts(t *testing.T) {
 	assert.Equal(t, testIngressContainerConfig.Ports, params.Ports)
 }
 
+func TestIngressContainerWithTCPProtocolUsesPassthroughProxy(t *testing.T) {
+	md := testIngressCreateMocks()
+	tc := testIngressContainerConfig
+	tc.Protocol = "tcp"
+	p := NewContainerIngress(&tc, md, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+	assert.Equal(t, tcpIngressImage, params.Image.Name)
+	assert.Equal(t, []string{"sh", "-c"}, params.Entrypoint)
+	assert.Equal(t, []string{
+		"socat TCP-LISTEN:8080,fork,reuseaddr TCP:test.container.shipyard.run:8081 & " +
+			"socat TCP-LISTEN:9080,fork,reuseaddr TCP:test.container.shipyard.run:9081 & wait",
+	}, params.Command)
+	assert.Equal(t, tc.Ports, params.Ports)
+
+	md.AssertCalled(t, "PullImage", config.Image{Name: tcpIngressImage}, false)
+}
+
+func TestIngressK8sTargetWithTCPProtocolUsesKubernetesProxy(t *testing.T) {
+	md := testIngressCreateMocks()
+	tc := testK8sIngressConfig
+	tc.Protocol = "tcp"
+	p := NewK8sIngress(&tc, md, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+	assert.Equal(t, ingressImage, params.Image.Name)
+	assert.Equal(t, "kubernetes", params.Command[1])
+}
+
 func TestIngressContainerFailReturnsError(t *testing.T) {
 	md := testIngressCreateMocks()
 	removeOn(&md.Mock, "CreateContainer")
 	md.On("CreateContainer", mock.Anything).Return("", fmt.Errorf("boom"))
 	p := NewContainerIngress(&testIngressContainerConfig, md, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
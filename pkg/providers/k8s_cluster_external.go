@@ -0,0 +1,117 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"golang.org/x/xerrors"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	clientcmdlatest "k8s.io/client-go/tools/clientcmd/api/latest"
+	clientcmdapiv1 "k8s.io/client-go/tools/clientcmd/api/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// K8sClusterExternal is a provider for existing Kubernetes clusters, the
+// config for the context is written to the same location as the config for
+// clusters created by Shipyard so that other resources can target it
+type K8sClusterExternal struct {
+	config     *config.K8sClusterExternal
+	kubeClient clients.Kubernetes
+	log        hclog.Logger
+}
+
+// NewK8sClusterExternal creates a new K8sClusterExternal provider
+func NewK8sClusterExternal(c *config.K8sClusterExternal, kc clients.Kubernetes, l hclog.Logger) *K8sClusterExternal {
+	return &K8sClusterExternal{c, kc, l}
+}
+
+// Create writes the config for the context and creates a client for the
+// cluster
+func (k *K8sClusterExternal) Create(ctx context.Context) error {
+	k.log.Info("Connecting to external Cluster", "ref", k.config.Name, "kubeconfig", k.config.KubeConfig, "context", k.config.Context)
+
+	kc, err := clientcmd.LoadFromFile(k.config.KubeConfig)
+	if err != nil {
+		return xerrors.Errorf("Unable to load Kubernetes config %s: %w", k.config.KubeConfig, err)
+	}
+
+	if k.config.Context != "" {
+		if _, ok := kc.Contexts[k.config.Context]; !ok {
+			return fmt.Errorf("Context %s does not exist in Kubernetes config %s", k.config.Context, k.config.KubeConfig)
+		}
+
+		kc.CurrentContext = k.config.Context
+	}
+
+	// only keep the context and embed any certificates so that the config
+	// can be mounted into containers
+	err = clientcmdapi.MinifyConfig(kc)
+	if err != nil {
+		return xerrors.Errorf("Unable to read context from Kubernetes config: %w", err)
+	}
+
+	err = clientcmdapi.FlattenConfig(kc)
+	if err != nil {
+		return xerrors.Errorf("Unable to read certificates for Kubernetes config: %w", err)
+	}
+
+	d, err := marshalKubeConfig(kc)
+	if err != nil {
+		return xerrors.Errorf("Unable to write Kubernetes config: %w", err)
+	}
+
+	_, destPath, dockerPath := utils.CreateKubeConfigPath(k.config.Name)
+
+	for _, p := range []string{destPath, dockerPath} {
+		err := ioutil.WriteFile(p, d, 0600)
+		if err != nil {
+			return xerrors.Errorf("Unable to write Kubernetes config: %w", err)
+		}
+	}
+
+	err = k.kubeClient.SetConfig(destPath)
+	if err != nil {
+		return xerrors.Errorf("Unable to create Kubernetes client: %w", err)
+	}
+
+	return nil
+}
+
+// Destroy removes the config written by Create, the cluster is not changed
+func (k *K8sClusterExternal) Destroy() error {
+	k.log.Info("Disconnecting from external Cluster", "ref", k.config.Name)
+
+	_, destPath, dockerPath := utils.CreateKubeConfigPath(k.config.Name)
+	os.Remove(destPath)
+	os.Remove(dockerPath)
+
+	return nil
+}
+
+// Lookup implements the provider Lookup method
+func (k *K8sClusterExternal) Lookup() ([]string, error) {
+	return []string{}, nil
+}
+
+// marshalKubeConfig converts the config to the versioned type and returns
+// the YAML
+func marshalKubeConfig(kc *clientcmdapi.Config) ([]byte, error) {
+	v := &clientcmdapiv1.Config{}
+
+	err := clientcmdlatest.Scheme.Convert(kc, v, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	v.APIVersion = "v1"
+	v.Kind = "Config"
+
+	return yaml.Marshal(v)
+}
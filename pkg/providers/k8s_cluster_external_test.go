@@ -0,0 +1,122 @@
+package providers
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+func setupK8sClusterExternal(t *testing.T) (*config.K8sClusterExternal, *mocks.MockKubernetes, func()) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+
+	home := os.Getenv("HOME")
+	os.Setenv("HOME", dir)
+
+	kc := filepath.Join(dir, "config")
+	err = ioutil.WriteFile(kc, []byte(externalKubeConfig), 0644)
+	require.NoError(t, err)
+
+	c := config.NewK8sClusterExternal("staging")
+	c.KubeConfig = kc
+	c.Context = "staging"
+
+	mk := &mocks.MockKubernetes{}
+	mk.On("SetConfig", mock.Anything).Return(nil)
+
+	return c, mk, func() {
+		os.Setenv("HOME", home)
+		os.RemoveAll(dir)
+	}
+}
+
+func TestK8sClusterExternalWritesConfigForContext(t *testing.T) {
+	c, mk, cleanup := setupK8sClusterExternal(t)
+	defer cleanup()
+
+	p := NewK8sClusterExternal(c, mk, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	_, destPath, dockerPath := utils.CreateKubeConfigPath("staging")
+	mk.AssertCalled(t, "SetConfig", destPath)
+
+	for _, f := range []string{destPath, dockerPath} {
+		d, err := ioutil.ReadFile(f)
+		require.NoError(t, err)
+
+		assert.Contains(t, string(d), "https://staging.example.com")
+		assert.Contains(t, string(d), "current-context: staging")
+		assert.NotContains(t, string(d), "https://production.example.com")
+	}
+
+	kc, err := clientcmd.LoadFromFile(destPath)
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", kc.AuthInfos["admin"].Token)
+}
+
+func TestK8sClusterExternalWithMissingContextReturnsError(t *testing.T) {
+	c, mk, cleanup := setupK8sClusterExternal(t)
+	defer cleanup()
+
+	c.Context = "dev"
+
+	p := NewK8sClusterExternal(c, mk, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+}
+
+func TestK8sClusterExternalDestroyRemovesConfig(t *testing.T) {
+	c, mk, cleanup := setupK8sClusterExternal(t)
+	defer cleanup()
+
+	p := NewK8sClusterExternal(c, mk, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	err = p.Destroy()
+	require.NoError(t, err)
+
+	_, destPath, _ := utils.CreateKubeConfigPath("staging")
+	assert.NoFileExists(t, destPath)
+}
+
+const externalKubeConfig = `
+apiVersion: v1
+kind: Config
+current-context: production
+clusters:
+- name: production
+  cluster:
+    server: https://production.example.com
+- name: staging
+  cluster:
+    server: https://staging.example.com
+contexts:
+- name: production
+  context:
+    cluster: production
+    user: admin
+- name: staging
+  context:
+    cluster: staging
+    user: admin
+users:
+- name: admin
+  user:
+    token: abc123
+`
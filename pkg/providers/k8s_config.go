@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	hclog "github.com/hashicorp/go-hclog"
 	"github.com/shipyard-run/shipyard/pkg/clients"
 	"github.com/shipyard-run/shipyard/pkg/config"
@@ -20,7 +21,7 @@ func NewK8sConfig(c *config.K8sConfig, kc clients.Kubernetes, l hclog.Logger) *K
 }
 
 // Create the Kubernetes resources defined by the config
-func (c *K8sConfig) Create() error {
+func (c *K8sConfig) Create(ctx context.Context) error {
 	c.log.Info("Applying Kubernetes configuration", "ref", c.config.Name, "config", c.config.Paths)
 
 	err := c.setup()
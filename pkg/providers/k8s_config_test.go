@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -35,7 +36,7 @@ func setupK8sConfig() (*clients.MockKubernetes, *K8sConfig) {
 func TestCreatesCorrectly(t *testing.T) {
 	mk, p := setupK8sConfig()
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
 	_, destPath, _ := utils.CreateKubeConfigPath("testcluster")
@@ -48,7 +49,7 @@ func TestCreateSetupErrorReturnsError(t *testing.T) {
 	removeOn(&mk.Mock, "SetConfig")
 	mk.On("SetConfig", mock.Anything).Return(fmt.Errorf("boom"))
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -56,7 +57,7 @@ func TestCreateNoClusterErrorReturnsError(t *testing.T) {
 	_, p := setupK8sConfig()
 	p.config.Config.RemoveResource(p.config.Config.Resources[1])
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -0,0 +1,65 @@
+package providers
+
+import (
+	"context"
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"golang.org/x/xerrors"
+)
+
+// K8sConfigMap is a provider for creating Kubernetes ConfigMaps
+type K8sConfigMap struct {
+	config *config.K8sConfigMap
+	client clients.Kubernetes
+	log    hclog.Logger
+}
+
+// NewK8sConfigMap creates a provider which can create and destroy Kubernetes ConfigMaps
+func NewK8sConfigMap(c *config.K8sConfigMap, kc clients.Kubernetes, l hclog.Logger) *K8sConfigMap {
+	return &K8sConfigMap{c, kc, l}
+}
+
+// Create the ConfigMap, the data of an existing ConfigMap is replaced
+func (m *K8sConfigMap) Create(ctx context.Context) error {
+	m.log.Info("Creating Kubernetes ConfigMap", "ref", m.config.Name, "cluster", m.config.Cluster, "namespace", m.config.Namespace)
+
+	data, err := readK8sData(m.config.Files, m.config.Data)
+	if err != nil {
+		return err
+	}
+
+	err = setupK8sClient(&m.config.ResourceInfo, m.config.Cluster, m.client)
+	if err != nil {
+		return err
+	}
+
+	err = m.client.CreateConfigMap(m.config.Namespace, m.config.Name, data)
+	if err != nil {
+		return xerrors.Errorf("Unable to create ConfigMap %s: %w", m.config.Name, err)
+	}
+
+	return nil
+}
+
+// Destroy the ConfigMap
+func (m *K8sConfigMap) Destroy() error {
+	m.log.Info("Destroy Kubernetes ConfigMap", "ref", m.config.Name, "cluster", m.config.Cluster, "namespace", m.config.Namespace)
+
+	err := setupK8sClient(&m.config.ResourceInfo, m.config.Cluster, m.client)
+	if err != nil {
+		return err
+	}
+
+	err = m.client.DeleteConfigMap(m.config.Namespace, m.config.Name)
+	if err != nil {
+		m.log.Debug("There was a problem destroying Kubernetes ConfigMap, logging message but ignoring error", "ref", m.config.Name, "error", err)
+	}
+
+	return nil
+}
+
+// Lookup returns nothing
+func (m *K8sConfigMap) Lookup() ([]string, error) {
+	return []string{}, nil
+}
@@ -0,0 +1,50 @@
+package providers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupK8sConfigMap() (*config.K8sConfigMap, *mocks.MockKubernetes) {
+	cm := config.NewK8sConfigMap("app")
+	cm.Cluster = "k8s_cluster.k3s"
+	cm.Namespace = "apps"
+	cm.Data = map[string]string{"LOG_LEVEL": "debug"}
+
+	c := config.New()
+	c.AddResource(config.NewK8sCluster("k3s"))
+	c.AddResource(cm)
+
+	mk := &mocks.MockKubernetes{}
+	mk.On("SetConfig", mock.Anything).Return(nil)
+	mk.On("CreateConfigMap", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mk.On("DeleteConfigMap", mock.Anything, mock.Anything).Return(nil)
+
+	return cm, mk
+}
+
+func TestK8sConfigMapCreatesConfigMap(t *testing.T) {
+	cm, mk := setupK8sConfigMap()
+	p := NewK8sConfigMap(cm, mk, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	mk.AssertCalled(t, "CreateConfigMap", "apps", "app", map[string][]byte{"LOG_LEVEL": []byte("debug")})
+}
+
+func TestK8sConfigMapDestroyDeletesConfigMap(t *testing.T) {
+	cm, mk := setupK8sConfigMap()
+	p := NewK8sConfigMap(cm, mk, hclog.NewNullLogger())
+
+	err := p.Destroy()
+	assert.NoError(t, err)
+
+	mk.AssertCalled(t, "DeleteConfigMap", "apps", "app")
+}
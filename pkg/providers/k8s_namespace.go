@@ -0,0 +1,77 @@
+package providers
+
+import (
+	"context"
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"golang.org/x/xerrors"
+)
+
+// K8sNamespace is a provider for creating Kubernetes namespaces
+type K8sNamespace struct {
+	config *config.K8sNamespace
+	client clients.Kubernetes
+	log    hclog.Logger
+}
+
+// NewK8sNamespace creates a provider which can create and destroy Kubernetes namespaces
+func NewK8sNamespace(c *config.K8sNamespace, kc clients.Kubernetes, l hclog.Logger) *K8sNamespace {
+	return &K8sNamespace{c, kc, l}
+}
+
+// Create the namespace, the labels and annotations of an existing
+// namespace are updated
+func (n *K8sNamespace) Create(ctx context.Context) error {
+	n.log.Info("Creating Kubernetes namespace", "ref", n.config.Name, "cluster", n.config.Cluster)
+
+	err := n.setup()
+	if err != nil {
+		return err
+	}
+
+	err = n.client.CreateNamespace(n.config.Name, n.config.Labels, n.config.Annotations)
+	if err != nil {
+		return xerrors.Errorf("Unable to create namespace %s: %w", n.config.Name, err)
+	}
+
+	return nil
+}
+
+// Destroy the namespace and all the resources in it
+func (n *K8sNamespace) Destroy() error {
+	n.log.Info("Destroy Kubernetes namespace", "ref", n.config.Name, "cluster", n.config.Cluster)
+
+	err := n.setup()
+	if err != nil {
+		return err
+	}
+
+	err = n.client.DeleteNamespace(n.config.Name)
+	if err != nil {
+		n.log.Debug("There was a problem destroying Kubernetes namespace, logging message but ignoring error", "ref", n.config.Name, "error", err)
+	}
+
+	return nil
+}
+
+// Lookup returns nothing
+func (n *K8sNamespace) Lookup() ([]string, error) {
+	return []string{}, nil
+}
+
+func (n *K8sNamespace) setup() error {
+	cluster, err := n.config.FindDependentResource(n.config.Cluster)
+	if err != nil {
+		return xerrors.Errorf("Unable to find associated cluster: %w", err)
+	}
+
+	_, destPath, _ := utils.CreateKubeConfigPath(cluster.Info().Name)
+	err = n.client.SetConfig(destPath)
+	if err != nil {
+		return xerrors.Errorf("unable to create Kubernetes client: %w", err)
+	}
+
+	return nil
+}
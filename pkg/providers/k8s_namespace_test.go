@@ -0,0 +1,61 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupK8sNamespace() (*config.K8sNamespace, *mocks.MockKubernetes) {
+	ns := config.NewK8sNamespace("vault")
+	ns.Cluster = "k8s_cluster.k3s"
+	ns.Labels = map[string]string{"team": "platform"}
+
+	c := config.New()
+	c.AddResource(config.NewK8sCluster("k3s"))
+	c.AddResource(ns)
+
+	mk := &mocks.MockKubernetes{}
+	mk.On("SetConfig", mock.Anything).Return(nil)
+	mk.On("CreateNamespace", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mk.On("DeleteNamespace", mock.Anything).Return(nil)
+
+	return ns, mk
+}
+
+func TestK8sNamespaceCreatesNamespace(t *testing.T) {
+	ns, mk := setupK8sNamespace()
+	p := NewK8sNamespace(ns, mk, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	mk.AssertCalled(t, "CreateNamespace", "vault", ns.Labels, mock.Anything)
+}
+
+func TestK8sNamespaceCreateReturnsErrorWhenCreateFails(t *testing.T) {
+	ns, mk := setupK8sNamespace()
+	removeOn(&mk.Mock, "CreateNamespace")
+	mk.On("CreateNamespace", mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
+
+	p := NewK8sNamespace(ns, mk, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+}
+
+func TestK8sNamespaceDestroyDeletesNamespace(t *testing.T) {
+	ns, mk := setupK8sNamespace()
+	p := NewK8sNamespace(ns, mk, hclog.NewNullLogger())
+
+	err := p.Destroy()
+	assert.NoError(t, err)
+
+	mk.AssertCalled(t, "DeleteNamespace", "vault")
+}
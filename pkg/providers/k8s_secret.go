@@ -0,0 +1,106 @@
+package providers
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"golang.org/x/xerrors"
+)
+
+// K8sSecret is a provider for creating Kubernetes Secrets
+type K8sSecret struct {
+	config *config.K8sSecret
+	client clients.Kubernetes
+	log    hclog.Logger
+}
+
+// NewK8sSecret creates a provider which can create and destroy Kubernetes Secrets
+func NewK8sSecret(c *config.K8sSecret, kc clients.Kubernetes, l hclog.Logger) *K8sSecret {
+	return &K8sSecret{c, kc, l}
+}
+
+// Create the Secret, the data of an existing Secret is replaced
+func (s *K8sSecret) Create(ctx context.Context) error {
+	s.log.Info("Creating Kubernetes Secret", "ref", s.config.Name, "cluster", s.config.Cluster, "namespace", s.config.Namespace)
+
+	data, err := readK8sData(s.config.Files, s.config.Data)
+	if err != nil {
+		return err
+	}
+
+	err = setupK8sClient(&s.config.ResourceInfo, s.config.Cluster, s.client)
+	if err != nil {
+		return err
+	}
+
+	err = s.client.CreateSecret(s.config.Namespace, s.config.Name, s.config.SecretType, data)
+	if err != nil {
+		return xerrors.Errorf("Unable to create Secret %s: %w", s.config.Name, err)
+	}
+
+	return nil
+}
+
+// Destroy the Secret
+func (s *K8sSecret) Destroy() error {
+	s.log.Info("Destroy Kubernetes Secret", "ref", s.config.Name, "cluster", s.config.Cluster, "namespace", s.config.Namespace)
+
+	err := setupK8sClient(&s.config.ResourceInfo, s.config.Cluster, s.client)
+	if err != nil {
+		return err
+	}
+
+	err = s.client.DeleteSecret(s.config.Namespace, s.config.Name)
+	if err != nil {
+		s.log.Debug("There was a problem destroying Kubernetes Secret, logging message but ignoring error", "ref", s.config.Name, "error", err)
+	}
+
+	return nil
+}
+
+// Lookup returns nothing
+func (s *K8sSecret) Lookup() ([]string, error) {
+	return []string{}, nil
+}
+
+// readK8sData returns the data for a Secret or ConfigMap, the contents of
+// each file is added using the name of the file as the key
+func readK8sData(files []string, data map[string]string) (map[string][]byte, error) {
+	d := map[string][]byte{}
+
+	for k, v := range data {
+		d[k] = []byte(v)
+	}
+
+	for _, f := range files {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			return nil, xerrors.Errorf("Unable to read file %s: %w", f, err)
+		}
+
+		d[filepath.Base(f)] = b
+	}
+
+	return d, nil
+}
+
+// setupK8sClient configures the client for the cluster
+func setupK8sClient(ri *config.ResourceInfo, cluster string, kc clients.Kubernetes) error {
+	cl, err := ri.FindDependentResource(cluster)
+	if err != nil {
+		return xerrors.Errorf("Unable to find associated cluster: %w", err)
+	}
+
+	_, destPath, _ := utils.CreateKubeConfigPath(cl.Info().Name)
+	err = kc.SetConfig(destPath)
+	if err != nil {
+		return xerrors.Errorf("unable to create Kubernetes client: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupK8sSecret(t *testing.T) (*config.K8sSecret, *mocks.MockKubernetes, func()) {
+	dir, err := ioutil.TempDir("", "")
+	assert.NoError(t, err)
+
+	cert := filepath.Join(dir, "tls.crt")
+	ioutil.WriteFile(cert, []byte("cert"), 0644)
+
+	sec := config.NewK8sSecret("tls")
+	sec.Cluster = "k8s_cluster.k3s"
+	sec.Namespace = "default"
+	sec.SecretType = "kubernetes.io/tls"
+	sec.Files = []string{cert}
+	sec.Data = map[string]string{"tls.key": "key"}
+
+	c := config.New()
+	c.AddResource(config.NewK8sCluster("k3s"))
+	c.AddResource(sec)
+
+	mk := &mocks.MockKubernetes{}
+	mk.On("SetConfig", mock.Anything).Return(nil)
+	mk.On("CreateSecret", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mk.On("DeleteSecret", mock.Anything, mock.Anything).Return(nil)
+
+	return sec, mk, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+func TestK8sSecretCreatesSecretFromFilesAndData(t *testing.T) {
+	sec, mk, cleanup := setupK8sSecret(t)
+	defer cleanup()
+
+	p := NewK8sSecret(sec, mk, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	mk.AssertCalled(t, "CreateSecret", "default", "tls", "kubernetes.io/tls", map[string][]byte{
+		"tls.crt": []byte("cert"),
+		"tls.key": []byte("key"),
+	})
+}
+
+func TestK8sSecretReturnsErrorWhenFileMissing(t *testing.T) {
+	sec, mk, cleanup := setupK8sSecret(t)
+	defer cleanup()
+
+	sec.Files = []string{"/missing/tls.crt"}
+	p := NewK8sSecret(sec, mk, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+	mk.AssertNotCalled(t, "CreateSecret", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestK8sSecretCreateReturnsErrorWhenCreateFails(t *testing.T) {
+	sec, mk, cleanup := setupK8sSecret(t)
+	defer cleanup()
+
+	removeOn(&mk.Mock, "CreateSecret")
+	mk.On("CreateSecret", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
+
+	p := NewK8sSecret(sec, mk, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+}
+
+func TestK8sSecretDestroyDeletesSecret(t *testing.T) {
+	sec, mk, cleanup := setupK8sSecret(t)
+	defer cleanup()
+
+	p := NewK8sSecret(sec, mk, hclog.NewNullLogger())
+
+	err := p.Destroy()
+	assert.NoError(t, err)
+
+	mk.AssertCalled(t, "DeleteSecret", "default", "tls")
+}
@@ -0,0 +1,82 @@
+package providers
+
+import (
+	"context"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"golang.org/x/xerrors"
+)
+
+// K8sWait is a provider which waits for conditions in a Kubernetes cluster
+type K8sWait struct {
+	config *config.K8sWait
+	client clients.Kubernetes
+	log    hclog.Logger
+}
+
+// NewK8sWait creates a provider which waits for pods, custom resource
+// definitions and jobs in a Kubernetes cluster
+func NewK8sWait(c *config.K8sWait, kc clients.Kubernetes, l hclog.Logger) *K8sWait {
+	return &K8sWait{c, kc, l}
+}
+
+// Create blocks until all the conditions are met or the timeout expires,
+// the timeout applies to all conditions
+func (w *K8sWait) Create(ctx context.Context) error {
+	w.log.Info("Waiting for Kubernetes resources", "ref", w.config.Name, "cluster", w.config.Cluster)
+
+	timeout, err := time.ParseDuration(w.config.Timeout)
+	if err != nil {
+		return xerrors.Errorf("Unable to parse timeout %s: %w", w.config.Timeout, err)
+	}
+
+	cluster, err := w.config.FindDependentResource(w.config.Cluster)
+	if err != nil {
+		return xerrors.Errorf("Unable to find associated cluster: %w", err)
+	}
+
+	_, destPath, _ := utils.CreateKubeConfigPath(cluster.Info().Name)
+	err = w.client.SetConfig(destPath)
+	if err != nil {
+		return xerrors.Errorf("unable to create Kubernetes client: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+
+	if len(w.config.CRDs) > 0 {
+		err = w.client.WaitForCRDs(w.config.CRDs, time.Until(deadline))
+		if err != nil {
+			return xerrors.Errorf("Custom resource definitions not established: %w", err)
+		}
+	}
+
+	if len(w.config.Pods) > 0 {
+		err = w.client.HealthCheckPods(w.config.Pods, time.Until(deadline))
+		if err != nil {
+			return xerrors.Errorf("Pods not ready: %w", err)
+		}
+	}
+
+	if len(w.config.Jobs) > 0 {
+		err = w.client.WaitForJobs(w.config.Jobs, time.Until(deadline))
+		if err != nil {
+			return xerrors.Errorf("Jobs not complete: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Destroy does nothing, there is nothing to remove
+func (w *K8sWait) Destroy() error {
+	return nil
+}
+
+// Lookup returns nothing
+func (w *K8sWait) Lookup() ([]string, error) {
+	return []string{}, nil
+}
@@ -0,0 +1,77 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupK8sWait() (*config.K8sWait, *mocks.MockKubernetes) {
+	w := config.NewK8sWait("cert-manager")
+	w.Cluster = "k8s_cluster.k3s"
+	w.Pods = []string{"app=cert-manager"}
+	w.CRDs = []string{"certificates.cert-manager.io"}
+	w.Jobs = []string{"app=migrate"}
+
+	c := config.New()
+	c.AddResource(config.NewK8sCluster("k3s"))
+	c.AddResource(w)
+
+	mk := &mocks.MockKubernetes{}
+	mk.On("SetConfig", mock.Anything).Return(nil)
+	mk.On("HealthCheckPods", mock.Anything, mock.Anything).Return(nil)
+	mk.On("WaitForCRDs", mock.Anything, mock.Anything).Return(nil)
+	mk.On("WaitForJobs", mock.Anything, mock.Anything).Return(nil)
+
+	return w, mk
+}
+
+func TestK8sWaitChecksAllConditions(t *testing.T) {
+	w, mk := setupK8sWait()
+	p := NewK8sWait(w, mk, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	mk.AssertCalled(t, "HealthCheckPods", w.Pods, mock.Anything)
+	mk.AssertCalled(t, "WaitForCRDs", w.CRDs, mock.Anything)
+	mk.AssertCalled(t, "WaitForJobs", w.Jobs, mock.Anything)
+}
+
+func TestK8sWaitSkipsEmptyConditions(t *testing.T) {
+	w, mk := setupK8sWait()
+	w.Jobs = nil
+	p := NewK8sWait(w, mk, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	mk.AssertNotCalled(t, "WaitForJobs", mock.Anything, mock.Anything)
+}
+
+func TestK8sWaitReturnsErrorWhenTimeoutInvalid(t *testing.T) {
+	w, mk := setupK8sWait()
+	w.Timeout = "soon"
+	p := NewK8sWait(w, mk, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+}
+
+func TestK8sWaitReturnsErrorWhenPodsNotReady(t *testing.T) {
+	w, mk := setupK8sWait()
+	removeOn(&mk.Mock, "HealthCheckPods")
+	mk.On("HealthCheckPods", mock.Anything, mock.Anything).Return(fmt.Errorf("timeout"))
+	p := NewK8sWait(w, mk, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+
+	mk.AssertNotCalled(t, "WaitForJobs", mock.Anything, mock.Anything)
+}
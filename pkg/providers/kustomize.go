@@ -0,0 +1,99 @@
+package providers
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"golang.org/x/xerrors"
+)
+
+// Kustomize is a provider for applying kustomizations to a Kubernetes cluster
+type Kustomize struct {
+	config *config.Kustomize
+	client clients.Kubernetes
+	log    hclog.Logger
+}
+
+// NewKustomize creates a provider which can apply and delete kustomizations
+func NewKustomize(c *config.Kustomize, kc clients.Kubernetes, l hclog.Logger) *Kustomize {
+	return &Kustomize{c, kc, l}
+}
+
+// Create builds the kustomization and applies the generated config
+func (k *Kustomize) Create(ctx context.Context) error {
+	k.log.Info("Applying Kustomization", "ref", k.config.Name, "path", k.config.Path)
+
+	f, err := k.build()
+	if err != nil {
+		return err
+	}
+
+	err = k.client.Apply([]string{f}, k.config.WaitUntilReady)
+	if err != nil {
+		return xerrors.Errorf("Unable to apply kustomization %s: %w", k.config.Path, err)
+	}
+
+	return nil
+}
+
+// Destroy deletes the resources generated by the kustomization
+func (k *Kustomize) Destroy() error {
+	k.log.Info("Destroy Kustomization", "ref", k.config.Name, "path", k.config.Path)
+
+	f, err := k.build()
+	if err != nil {
+		return err
+	}
+
+	err = k.client.Delete([]string{f})
+	if err != nil {
+		k.log.Debug("There was a problem destroying Kustomization, logging message but ignoring error", "ref", k.config.Name, "error", err)
+	}
+
+	return nil
+}
+
+// Lookup returns nothing
+func (k *Kustomize) Lookup() ([]string, error) {
+	return []string{}, nil
+}
+
+// build runs kustomize build and writes the output to a file in the
+// shipyard temp folder, the path to the file is returned
+func (k *Kustomize) build() (string, error) {
+	cluster, err := k.config.FindDependentResource(k.config.Cluster)
+	if err != nil {
+		return "", xerrors.Errorf("Unable to find associated cluster: %w", err)
+	}
+
+	_, destPath, _ := utils.CreateKubeConfigPath(cluster.Info().Name)
+	err = k.client.SetConfig(destPath)
+	if err != nil {
+		return "", xerrors.Errorf("unable to create Kubernetes client: %w", err)
+	}
+
+	d, err := clients.BuildKustomize(k.config.Path)
+	if err != nil {
+		return "", xerrors.Errorf("Unable to build kustomization %s: %w", k.config.Path, err)
+	}
+
+	dir := filepath.Join(utils.ShipyardTemp(), "kustomize")
+	err = os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return "", err
+	}
+
+	f := filepath.Join(dir, k.config.Name+".yaml")
+	err = ioutil.WriteFile(f, d, 0644)
+	if err != nil {
+		return "", xerrors.Errorf("Unable to write kustomization output: %w", err)
+	}
+
+	return f, nil
+}
@@ -0,0 +1,84 @@
+package providers
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupKustomize(t *testing.T) (*config.Kustomize, *mocks.MockKubernetes, func()) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+
+	home := os.Getenv("HOME")
+	os.Setenv("HOME", dir)
+
+	ioutil.WriteFile(filepath.Join(dir, "kustomization.yaml"), []byte("resources:\n- config.yaml\n"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "config.yaml"), []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\n"), 0644)
+
+	k := config.NewKustomize("app")
+	k.Cluster = "k8s_cluster.k3s"
+	k.Path = dir
+
+	c := config.New()
+	c.AddResource(config.NewK8sCluster("k3s"))
+	c.AddResource(k)
+
+	mk := &mocks.MockKubernetes{}
+	mk.On("SetConfig", mock.Anything).Return(nil)
+	mk.On("Apply", mock.Anything, mock.Anything).Return(nil)
+	mk.On("Delete", mock.Anything).Return(nil)
+
+	return k, mk, func() {
+		os.Setenv("HOME", home)
+		os.RemoveAll(dir)
+	}
+}
+
+func TestKustomizeAppliesBuiltConfig(t *testing.T) {
+	k, mk, cleanup := setupKustomize(t)
+	defer cleanup()
+
+	p := NewKustomize(k, mk, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	files := getCalls(&mk.Mock, "Apply")[0].Arguments[0].([]string)
+	d, err := ioutil.ReadFile(files[0])
+	assert.NoError(t, err)
+	assert.Contains(t, string(d), "kind: ConfigMap")
+}
+
+func TestKustomizeReturnsErrorWhenBuildFails(t *testing.T) {
+	k, mk, cleanup := setupKustomize(t)
+	defer cleanup()
+
+	os.Remove(filepath.Join(k.Path, "kustomization.yaml"))
+
+	p := NewKustomize(k, mk, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+	mk.AssertNotCalled(t, "Apply", mock.Anything, mock.Anything)
+}
+
+func TestKustomizeDestroyDeletesConfig(t *testing.T) {
+	k, mk, cleanup := setupKustomize(t)
+	defer cleanup()
+
+	p := NewKustomize(k, mk, hclog.NewNullLogger())
+
+	err := p.Destroy()
+	assert.NoError(t, err)
+	mk.AssertNumberOfCalls(t, "Delete", 1)
+}
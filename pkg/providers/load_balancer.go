@@ -0,0 +1,159 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"golang.org/x/xerrors"
+)
+
+const loadBalancerImage = "haproxy:2.3"
+
+// LoadBalancer is a provider for running a HAProxy load balancer
+type LoadBalancer struct {
+	config *config.LoadBalancer
+	client clients.ContainerTasks
+	log    hclog.Logger
+}
+
+// NewLoadBalancer creates a new load balancer provider
+func NewLoadBalancer(lb *config.LoadBalancer, cl clients.ContainerTasks, l hclog.Logger) *LoadBalancer {
+	return &LoadBalancer{lb, cl, l}
+}
+
+// Create writes the HAProxy config and starts the load balancer container
+func (l *LoadBalancer) Create(ctx context.Context) error {
+	l.log.Info("Creating Load Balancer", "ref", l.config.Name, "targets", l.config.Targets)
+
+	servers := []string{}
+	for _, t := range l.config.Targets {
+		r, err := l.config.FindDependentResource(t)
+		if err != nil {
+			return xerrors.Errorf("Unable to find target: %w", err)
+		}
+
+		addr, err := loadBalancerTarget(r)
+		if err != nil {
+			return err
+		}
+
+		servers = append(servers, addr)
+	}
+
+	f := l.configPath()
+
+	err := os.MkdirAll(filepath.Dir(f), os.ModePerm)
+	if err != nil {
+		return xerrors.Errorf("Unable to create folder for load balancer config: %w", err)
+	}
+
+	err = ioutil.WriteFile(f, []byte(l.haproxyConfig(servers)), 0644)
+	if err != nil {
+		return xerrors.Errorf("Unable to write load balancer config %s: %w", f, err)
+	}
+
+	cc := config.NewContainer(l.config.Name)
+	l.config.ResourceInfo.AddChild(cc)
+
+	cc.Image = config.Image{Name: loadBalancerImage}
+	cc.Networks = l.config.Networks
+	cc.Ports = l.config.Ports
+	cc.Volumes = []config.Volume{
+		config.Volume{
+			Source:      f,
+			Destination: "/usr/local/etc/haproxy/haproxy.cfg",
+		},
+	}
+
+	err = l.client.PullImage(ctx, cc.Image, false)
+	if err != nil {
+		return err
+	}
+
+	_, err = l.client.CreateContainer(cc)
+	return err
+}
+
+// Destroy the load balancer container and config
+func (l *LoadBalancer) Destroy() error {
+	l.log.Info("Destroy Load Balancer", "ref", l.config.Name)
+
+	ids, err := l.Lookup()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		err := l.client.RemoveContainer(id)
+		if err != nil {
+			return err
+		}
+	}
+
+	os.Remove(l.configPath())
+
+	return nil
+}
+
+// Lookup the ID of the load balancer container
+func (l *LoadBalancer) Lookup() ([]string, error) {
+	return l.client.FindContainerIDs(l.config.Name, l.config.Type)
+}
+
+func (l *LoadBalancer) configPath() string {
+	return filepath.Join(utils.ShipyardTemp(), "load_balancer", fmt.Sprintf("%s.cfg", l.config.Name))
+}
+
+// haproxyConfig returns the HAProxy config with a frontend and backend for
+// each port
+func (l *LoadBalancer) haproxyConfig(servers []string) string {
+	b := &bytes.Buffer{}
+
+	fmt.Fprintf(b, "defaults\n")
+	fmt.Fprintf(b, "  mode %s\n", l.config.Protocol)
+	fmt.Fprintf(b, "  timeout connect 5s\n")
+	fmt.Fprintf(b, "  timeout client 30s\n")
+	fmt.Fprintf(b, "  timeout server 30s\n")
+	// targets which can not be resolved when the load balancer starts are
+	// marked down until they are healthy
+	fmt.Fprintf(b, "  default-server init-addr last,libc,none inter %s\n", l.config.CheckInterval)
+
+	for _, p := range l.config.Ports {
+		fmt.Fprintf(b, "\nfrontend port_%s\n", p.Local)
+		fmt.Fprintf(b, "  bind *:%s\n", p.Local)
+		fmt.Fprintf(b, "  default_backend port_%s\n", p.Local)
+
+		fmt.Fprintf(b, "\nbackend port_%s\n", p.Local)
+		fmt.Fprintf(b, "  balance %s\n", l.config.Balance)
+
+		if l.config.CheckPath != "" {
+			fmt.Fprintf(b, "  option httpchk GET %s\n", l.config.CheckPath)
+		}
+
+		for i, s := range servers {
+			fmt.Fprintf(b, "  server target_%d %s:%s check\n", i, s, p.Remote)
+		}
+	}
+
+	return b.String()
+}
+
+// loadBalancerTarget returns the address of the target resource
+func loadBalancerTarget(r config.Resource) (string, error) {
+	switch r.Info().Type {
+	case config.TypeContainer:
+		return utils.FQDN(r.Info().Name, string(r.Info().Type)), nil
+	case config.TypeK8sCluster, config.TypeNomadCluster:
+		return fmt.Sprintf("server.%s", utils.FQDN(r.Info().Name, string(r.Info().Type))), nil
+	}
+
+	return "", fmt.Errorf("Unable to balance traffic to %s.%s, only containers, Kubernetes clusters and Nomad clusters are supported", r.Info().Type, r.Info().Name)
+}
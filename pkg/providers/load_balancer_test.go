@@ -0,0 +1,101 @@
+package providers
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupLoadBalancer(t *testing.T) (*config.LoadBalancer, *mocks.MockContainerTasks, func()) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+
+	home := os.Getenv("HOME")
+	os.Setenv("HOME", dir)
+
+	lb := config.NewLoadBalancer("web")
+	lb.Targets = []string{"container.web1", "container.web2"}
+	lb.Ports = []config.Port{config.Port{Local: "80", Remote: "8080", Host: "18080"}}
+	lb.CheckPath = "/health"
+
+	c := config.New()
+	c.AddResource(config.NewContainer("web1"))
+	c.AddResource(config.NewContainer("web2"))
+	c.AddResource(lb)
+
+	md := &mocks.MockContainerTasks{}
+	md.On("PullImage", mock.Anything, mock.Anything).Return(nil)
+	md.On("CreateContainer", mock.Anything).Return("abc", nil)
+	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return([]string{"abc"}, nil)
+	md.On("RemoveContainer", mock.Anything).Return(nil)
+
+	return lb, md, func() {
+		os.Setenv("HOME", home)
+		os.RemoveAll(dir)
+	}
+}
+
+func TestLoadBalancerCreatesContainerWithConfig(t *testing.T) {
+	lb, md, cleanup := setupLoadBalancer(t)
+	defer cleanup()
+
+	p := NewLoadBalancer(lb, md, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	cc := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+	assert.Equal(t, loadBalancerImage, cc.Image.Name)
+	assert.Equal(t, lb.Ports, cc.Ports)
+	require.Len(t, cc.Volumes, 1)
+	assert.Equal(t, "/usr/local/etc/haproxy/haproxy.cfg", cc.Volumes[0].Destination)
+
+	d, err := ioutil.ReadFile(cc.Volumes[0].Source)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(d), "mode http")
+	assert.Contains(t, string(d), "bind *:80")
+	assert.Contains(t, string(d), "balance roundrobin")
+	assert.Contains(t, string(d), "option httpchk GET /health")
+	assert.Contains(t, string(d), "server target_0 web1.container.shipyard.run:8080 check")
+	assert.Contains(t, string(d), "server target_1 web2.container.shipyard.run:8080 check")
+}
+
+func TestLoadBalancerWithUnsupportedTargetReturnsError(t *testing.T) {
+	lb, md, cleanup := setupLoadBalancer(t)
+	defer cleanup()
+
+	lb.Config.AddResource(config.NewNetwork("local"))
+	lb.Targets = []string{"network.local"}
+
+	p := NewLoadBalancer(lb, md, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+
+	md.AssertNotCalled(t, "CreateContainer", mock.Anything)
+}
+
+func TestLoadBalancerDestroyRemovesContainerAndConfig(t *testing.T) {
+	lb, md, cleanup := setupLoadBalancer(t)
+	defer cleanup()
+
+	p := NewLoadBalancer(lb, md, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	err = p.Destroy()
+	require.NoError(t, err)
+
+	md.AssertCalled(t, "RemoveContainer", "abc")
+	assert.NoFileExists(t, p.configPath())
+}
@@ -0,0 +1,168 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"golang.org/x/xerrors"
+)
+
+// localIngressService is a Service without a selector, the Endpoints route
+// traffic for the Service to the ip address of the ingress container
+const localIngressService = `apiVersion: v1
+kind: Service
+metadata:
+  name: %s
+  namespace: %s
+spec:
+  ports:
+%s---
+apiVersion: v1
+kind: Endpoints
+metadata:
+  name: %s
+  namespace: %s
+subsets:
+- addresses:
+  - ip: %s
+  ports:
+%s`
+
+// LocalIngress is a provider which exposes a service on the local machine
+// to the environment, a socat container forwards connections to the
+// address of the local machine
+type LocalIngress struct {
+	config     *config.LocalIngress
+	client     clients.ContainerTasks
+	kubeClient clients.Kubernetes
+	log        hclog.Logger
+}
+
+// NewLocalIngress creates a new LocalIngress provider
+func NewLocalIngress(c *config.LocalIngress, cc clients.ContainerTasks, kc clients.Kubernetes, l hclog.Logger) *LocalIngress {
+	return &LocalIngress{c, cc, kc, l}
+}
+
+// Create the proxy container and the Service in the cluster
+func (i *LocalIngress) Create(ctx context.Context) error {
+	i.log.Info("Creating Local Ingress", "ref", i.config.Name, "address", i.config.Address)
+
+	cc := config.NewContainer(i.config.Name)
+	i.config.ResourceInfo.AddChild(cc)
+
+	cc.Image = config.Image{Name: tcpIngressImage}
+	cc.Networks = i.config.Networks
+	cc.Entrypoint = []string{"sh", "-c"}
+	cc.Command = []string{tcpProxyCommand(utils.GetDockerIP(), i.config.Ports)}
+
+	err := i.client.PullImage(ctx, cc.Image, false)
+	if err != nil {
+		return err
+	}
+
+	_, err = i.client.CreateContainer(cc)
+	if err != nil {
+		return err
+	}
+
+	if i.config.Cluster == "" {
+		return nil
+	}
+
+	file, err := i.writeService()
+	if err != nil {
+		return err
+	}
+
+	err = setupK8sClient(&i.config.ResourceInfo, i.config.Cluster, i.kubeClient)
+	if err != nil {
+		return err
+	}
+
+	err = i.kubeClient.Apply([]string{file}, false)
+	if err != nil {
+		return xerrors.Errorf("Unable to create Service for local ingress: %w", err)
+	}
+
+	return nil
+}
+
+// Destroy the Service and the proxy container
+func (i *LocalIngress) Destroy() error {
+	i.log.Info("Destroy Local Ingress", "ref", i.config.Name)
+
+	if i.config.Cluster != "" {
+		err := setupK8sClient(&i.config.ResourceInfo, i.config.Cluster, i.kubeClient)
+		if err == nil {
+			err = i.kubeClient.Delete([]string{i.serviceFile()})
+		}
+
+		if err != nil {
+			i.log.Debug("There was a problem destroying the Service, logging message but ignoring error", "ref", i.config.Name, "error", err)
+		}
+
+		os.RemoveAll(filepath.Dir(i.serviceFile()))
+	}
+
+	ids, err := i.Lookup()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		err := i.client.RemoveContainer(id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Lookup the ID of the proxy container
+func (i *LocalIngress) Lookup() ([]string, error) {
+	return i.client.FindContainerIDs(i.config.Name, i.config.Type)
+}
+
+func (i *LocalIngress) serviceFile() string {
+	return filepath.Join(utils.ShipyardTemp(), "local_ingress", i.config.Name, "service.yaml")
+}
+
+// writeService writes the manifest for the Service and Endpoints
+func (i *LocalIngress) writeService() (string, error) {
+	servicePorts := strings.Builder{}
+	endpointPorts := strings.Builder{}
+
+	for _, p := range i.config.Ports {
+		fmt.Fprintf(&servicePorts, "  - name: port-%s\n    port: %s\n    protocol: TCP\n", p.Local, p.Local)
+		fmt.Fprintf(&endpointPorts, "  - name: port-%s\n    port: %s\n    protocol: TCP\n", p.Local, p.Local)
+	}
+
+	manifest := fmt.Sprintf(
+		localIngressService,
+		i.config.Name, i.config.Namespace, servicePorts.String(),
+		i.config.Name, i.config.Namespace, i.config.IPAddress(), endpointPorts.String(),
+	)
+
+	file := i.serviceFile()
+
+	err := os.MkdirAll(filepath.Dir(file), os.ModePerm)
+	if err != nil {
+		return "", xerrors.Errorf("Unable to create folder for Service: %w", err)
+	}
+
+	err = ioutil.WriteFile(file, []byte(manifest), 0644)
+	if err != nil {
+		return "", xerrors.Errorf("Unable to write Service: %w", err)
+	}
+
+	return file, nil
+}
@@ -0,0 +1,91 @@
+package providers
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupLocalIngress(t *testing.T) (*config.LocalIngress, *mocks.MockContainerTasks, *mocks.MockKubernetes, func()) {
+	home := os.Getenv("HOME")
+	tmp, _ := ioutil.TempDir("", "")
+	os.Setenv("HOME", tmp)
+
+	i := config.NewLocalIngress("app")
+	i.Networks = []config.NetworkAttachment{config.NetworkAttachment{Name: "network.local", IPAddress: "10.5.0.100"}}
+	i.Ports = []config.Port{config.Port{Local: "8080", Remote: "3000"}}
+
+	c := config.New()
+	c.AddResource(config.NewK8sCluster("k3s"))
+	c.AddResource(i)
+
+	md := &mocks.MockContainerTasks{}
+	md.On("PullImage", mock.Anything, mock.Anything).Return(nil)
+	md.On("CreateContainer", mock.Anything).Return("abc", nil)
+	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return([]string{"abc"}, nil)
+	md.On("RemoveContainer", mock.Anything).Return(nil)
+
+	mk := &mocks.MockKubernetes{}
+	mk.On("SetConfig", mock.Anything).Return(nil)
+	mk.On("Apply", mock.Anything, mock.Anything).Return(nil)
+	mk.On("Delete", mock.Anything).Return(nil)
+
+	return i, md, mk, func() {
+		os.Setenv("HOME", home)
+		os.RemoveAll(tmp)
+	}
+}
+
+func TestLocalIngressForwardsToLocalMachine(t *testing.T) {
+	i, md, mk, cleanup := setupLocalIngress(t)
+	defer cleanup()
+
+	p := NewLocalIngress(i, md, mk, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	params := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+	assert.Equal(t, tcpIngressImage, params.Image.Name)
+	assert.Equal(t, i.Networks, params.Networks)
+	assert.Contains(t, params.Command[0], "TCP-LISTEN:8080")
+	assert.Contains(t, params.Command[0], utils.GetDockerIP()+":3000")
+
+	mk.AssertNotCalled(t, "Apply", mock.Anything, mock.Anything)
+}
+
+func TestLocalIngressCreatesServiceInCluster(t *testing.T) {
+	i, md, mk, cleanup := setupLocalIngress(t)
+	defer cleanup()
+
+	i.Cluster = "k8s_cluster.k3s"
+	i.Namespace = "default"
+
+	p := NewLocalIngress(i, md, mk, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	files := getCalls(&mk.Mock, "Apply")[0].Arguments[0].([]string)
+	d, err := ioutil.ReadFile(files[0])
+	assert.NoError(t, err)
+
+	assert.Contains(t, string(d), "kind: Endpoints")
+	assert.Contains(t, string(d), "ip: 10.5.0.100")
+	assert.Contains(t, string(d), "port: 8080")
+
+	err = p.Destroy()
+	assert.NoError(t, err)
+
+	mk.AssertCalled(t, "Delete", files)
+	md.AssertCalled(t, "RemoveContainer", "abc")
+	assert.NoFileExists(t, files[0])
+}
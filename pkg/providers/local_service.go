@@ -0,0 +1,151 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"golang.org/x/xerrors"
+)
+
+// localServiceStartup is the time a process must keep running before it
+// is considered started when no health check is defined
+var localServiceStartup = 2 * time.Second
+
+// LocalService is a provider which runs a process on the local machine
+type LocalService struct {
+	config     *config.LocalService
+	client     clients.Command
+	httpClient clients.HTTP
+	log        hclog.Logger
+}
+
+// NewLocalService creates a new LocalService provider
+func NewLocalService(c *config.LocalService, cc clients.Command, hc clients.HTTP, l hclog.Logger) *LocalService {
+	return &LocalService{c, cc, hc, l}
+}
+
+// Create starts the process and records the process id in the config, when
+// the process is already running it is not started again
+func (l *LocalService) Create(ctx context.Context) error {
+	l.log.Info("Creating Local Service", "ref", l.config.Name, "command", l.config.Command)
+
+	if l.running() {
+		l.log.Debug("Local service already running", "ref", l.config.Name, "pid", l.config.PID)
+		return nil
+	}
+
+	env := []string{}
+	for _, kv := range l.config.Environment {
+		env = append(env, fmt.Sprintf("%s=%s", kv.Key, kv.Value))
+	}
+
+	pid, err := l.client.Start(l.config.Command, l.config.Arguments, env, l.config.WorkingDirectory, l.config.LogFile)
+	if err != nil {
+		return xerrors.Errorf("Unable to start local service %s: %w", l.config.Name, err)
+	}
+
+	l.config.PID = pid
+	l.log.Debug("Started local service", "ref", l.config.Name, "pid", pid, "log_file", l.config.LogFile)
+
+	l.config.StartTime, err = l.client.StartTime(pid)
+	if err != nil {
+		l.log.Debug("Unable to read the start time of the local service", "ref", l.config.Name, "pid", pid, "error", err)
+	}
+
+	err = l.healthCheck()
+	if err != nil {
+		return xerrors.Errorf("Local service %s did not start, check the log file %s for details: %w", l.config.Name, l.config.LogFile, err)
+	}
+
+	return nil
+}
+
+// Destroy stops the process when it is still running
+func (l *LocalService) Destroy() error {
+	l.log.Info("Destroy Local Service", "ref", l.config.Name, "pid", l.config.PID)
+
+	if l.config.PID == 0 {
+		return nil
+	}
+
+	// the process id could have been reused by another process since the
+	// service exited, only stop the process which was started
+	if !l.running() {
+		l.log.Debug("Local service is not running", "ref", l.config.Name, "pid", l.config.PID)
+		l.config.PID = 0
+		return nil
+	}
+
+	err := l.client.Kill(l.config.PID)
+	if err != nil {
+		return xerrors.Errorf("Unable to stop local service %s: %w", l.config.Name, err)
+	}
+
+	l.config.PID = 0
+
+	return nil
+}
+
+// Lookup returns the process id when the process is running
+func (l *LocalService) Lookup() ([]string, error) {
+	if !l.running() {
+		return []string{}, nil
+	}
+
+	return []string{fmt.Sprintf("%d", l.config.PID)}, nil
+}
+
+// running returns true when the process which was started for the service
+// is still running
+func (l *LocalService) running() bool {
+	if l.config.PID == 0 || !l.client.Running(l.config.PID) {
+		return false
+	}
+
+	st, err := l.client.StartTime(l.config.PID)
+	if err != nil {
+		return false
+	}
+
+	return st == l.config.StartTime
+}
+
+func (l *LocalService) healthCheck() error {
+	hc := l.config.HealthCheck
+	if hc == nil {
+		// without a health check make sure the process does not exit
+		// immediately
+		time.Sleep(localServiceStartup)
+
+		if !l.client.Running(l.config.PID) {
+			return fmt.Errorf("process %d exited", l.config.PID)
+		}
+
+		return nil
+	}
+
+	d, err := time.ParseDuration(hc.Timeout)
+	if err != nil {
+		return xerrors.Errorf("Unable to parse health check timeout %s: %w", hc.Timeout, err)
+	}
+
+	if hc.HTTP != "" {
+		err := l.httpClient.HealthCheckHTTP(hc.HTTP, d)
+		if err != nil {
+			return err
+		}
+	}
+
+	if hc.TCP != "" {
+		err := l.httpClient.HealthCheckTCP(hc.TCP, d)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
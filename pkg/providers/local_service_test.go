@@ -0,0 +1,131 @@
+package providers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupLocalService(t *testing.T) (*config.LocalService, *mocks.MockCommand, *mocks.MockHTTP) {
+	localServiceStartup = 0
+
+	ls := config.NewLocalService("api")
+	ls.Command = "go"
+	ls.Arguments = []string{"run", "main.go"}
+	ls.WorkingDirectory = "/src/api"
+	ls.Environment = []config.KV{config.KV{Key: "DB_ADDR", Value: "localhost:5432"}}
+	ls.LogFile = "/tmp/api.log"
+
+	mc := &mocks.MockCommand{}
+	mc.On("Start", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(1234, nil)
+	mc.On("Kill", mock.Anything).Return(nil)
+	mc.On("StartTime", mock.Anything).Return("Sat Oct 17 10:00:00 2026", nil)
+
+	mh := &mocks.MockHTTP{}
+	mh.On("HealthCheckHTTP", mock.Anything, mock.Anything).Return(nil)
+
+	return ls, mc, mh
+}
+
+func TestLocalServiceCreateStartsProcess(t *testing.T) {
+	ls, mc, mh := setupLocalService(t)
+	mc.On("Running", 1234).Return(true)
+
+	p := NewLocalService(ls, mc, mh, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	mc.AssertCalled(t, "Start", "go", []string{"run", "main.go"}, []string{"DB_ADDR=localhost:5432"}, "/src/api", "/tmp/api.log")
+	assert.Equal(t, 1234, ls.PID)
+	assert.Equal(t, "Sat Oct 17 10:00:00 2026", ls.StartTime)
+}
+
+func TestLocalServiceCreateReturnsErrorWhenProcessExits(t *testing.T) {
+	ls, mc, mh := setupLocalService(t)
+	mc.On("Running", 1234).Return(false)
+
+	p := NewLocalService(ls, mc, mh, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+}
+
+func TestLocalServiceCreateDoesNotStartRunningProcess(t *testing.T) {
+	ls, mc, mh := setupLocalService(t)
+	ls.PID = 999
+	ls.StartTime = "Sat Oct 17 10:00:00 2026"
+	mc.On("Running", 999).Return(true)
+
+	p := NewLocalService(ls, mc, mh, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	mc.AssertNotCalled(t, "Start", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, 999, ls.PID)
+}
+
+func TestLocalServiceCreateStartsProcessWhenPIDHasBeenReused(t *testing.T) {
+	ls, mc, mh := setupLocalService(t)
+	ls.PID = 999
+	ls.StartTime = "Fri Oct 16 10:00:00 2026"
+	mc.On("Running", mock.Anything).Return(true)
+
+	p := NewLocalService(ls, mc, mh, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	mc.AssertCalled(t, "Start", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, 1234, ls.PID)
+}
+
+func TestLocalServiceCreateWithHealthCheckChecksHealth(t *testing.T) {
+	ls, mc, mh := setupLocalService(t)
+	ls.HealthCheck = &config.HealthCheck{Timeout: "30s", HTTP: "http://localhost:8080/health"}
+
+	p := NewLocalService(ls, mc, mh, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	mh.AssertCalled(t, "HealthCheckHTTP", "http://localhost:8080/health", 30*time.Second)
+}
+
+func TestLocalServiceDestroyKillsProcess(t *testing.T) {
+	ls, mc, mh := setupLocalService(t)
+	ls.PID = 1234
+	ls.StartTime = "Sat Oct 17 10:00:00 2026"
+	mc.On("Running", 1234).Return(true)
+
+	p := NewLocalService(ls, mc, mh, hclog.NewNullLogger())
+
+	err := p.Destroy()
+	require.NoError(t, err)
+
+	mc.AssertCalled(t, "Kill", 1234)
+	assert.Equal(t, 0, ls.PID)
+}
+
+func TestLocalServiceDestroyDoesNotKillProcessWhenPIDHasBeenReused(t *testing.T) {
+	ls, mc, mh := setupLocalService(t)
+	ls.PID = 1234
+	ls.StartTime = "Fri Oct 16 10:00:00 2026"
+	mc.On("Running", 1234).Return(true)
+
+	p := NewLocalService(ls, mc, mh, hclog.NewNullLogger())
+
+	err := p.Destroy()
+	require.NoError(t, err)
+
+	mc.AssertNotCalled(t, "Kill", mock.Anything)
+	assert.Equal(t, 0, ls.PID)
+}
@@ -0,0 +1,168 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"golang.org/x/xerrors"
+)
+
+const minioImage = "minio/minio"
+const minioClientImage = "minio/mc:RELEASE.2021-01-16T02-45-34Z"
+const minioPort = 9000
+
+// minioAlias is the name the server is registered with in the client
+const minioAlias = "shipyard"
+
+// MinIO is a provider for running a MinIO server
+type MinIO struct {
+	config     *config.MinIO
+	client     clients.ContainerTasks
+	httpClient clients.HTTP
+	log        hclog.Logger
+}
+
+// NewMinIO creates a new MinIO provider
+func NewMinIO(m *config.MinIO, cl clients.ContainerTasks, hc clients.HTTP, l hclog.Logger) *MinIO {
+	return &MinIO{m, cl, hc, l}
+}
+
+// Create the MinIO container, buckets and users
+func (m *MinIO) Create(ctx context.Context) error {
+	m.log.Info("Creating MinIO", "ref", m.config.Name, "address", m.config.Address)
+
+	cc := config.NewContainer(m.config.Name)
+	m.config.ResourceInfo.AddChild(cc)
+
+	cc.Image = config.Image{Name: fmt.Sprintf("%s:%s", minioImage, m.config.Version)}
+	cc.Networks = m.config.Networks
+	cc.Command = []string{"server", "/data"}
+	cc.Environment = []config.KV{
+		config.KV{Key: "MINIO_ROOT_USER", Value: m.config.AccessKey},
+		config.KV{Key: "MINIO_ROOT_PASSWORD", Value: m.config.SecretKey},
+	}
+	cc.Ports = []config.Port{
+		config.Port{
+			Local:    fmt.Sprintf("%d", minioPort),
+			Host:     fmt.Sprintf("%d", m.config.Port),
+			Protocol: "tcp",
+		},
+	}
+
+	err := m.client.PullImage(ctx, cc.Image, false)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.client.CreateContainer(cc)
+	if err != nil {
+		return err
+	}
+
+	err = m.httpClient.HealthCheckHTTP(fmt.Sprintf("%s/minio/health/live", m.config.Address), 60*time.Second)
+	if err != nil {
+		return xerrors.Errorf("MinIO did not start: %w", err)
+	}
+
+	if len(m.config.Buckets) == 0 && len(m.config.Users) == 0 {
+		return nil
+	}
+
+	id, err := m.createClientContainer(ctx)
+	if err != nil {
+		return xerrors.Errorf("Unable to create container for MinIO client: %w", err)
+	}
+
+	err = m.bootstrap(id)
+
+	m.client.RemoveContainer(id)
+
+	return err
+}
+
+// Destroy the MinIO container, all data in the server is lost
+func (m *MinIO) Destroy() error {
+	m.log.Info("Destroy MinIO", "ref", m.config.Name)
+
+	ids, err := m.Lookup()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		err := m.client.RemoveContainer(id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Lookup the ID of the MinIO container
+func (m *MinIO) Lookup() ([]string, error) {
+	return m.client.FindContainerIDs(m.config.Name, m.config.Type)
+}
+
+// bootstrap creates the buckets and users using the MinIO client
+func (m *MinIO) bootstrap(id string) error {
+	err := m.exec(id, []string{"mc", "alias", "set", minioAlias, m.config.InternalAddress, m.config.AccessKey, m.config.SecretKey})
+	if err != nil {
+		return xerrors.Errorf("Unable to connect to MinIO: %w", err)
+	}
+
+	for _, b := range m.config.Buckets {
+		m.log.Debug("Creating bucket", "ref", m.config.Name, "bucket", b)
+
+		err := m.exec(id, []string{"mc", "mb", "--ignore-existing", fmt.Sprintf("%s/%s", minioAlias, b)})
+		if err != nil {
+			return xerrors.Errorf("Unable to create bucket %s: %w", b, err)
+		}
+	}
+
+	for _, u := range m.config.Users {
+		m.log.Debug("Creating user", "ref", m.config.Name, "access_key", u.AccessKey)
+
+		err := m.exec(id, []string{"mc", "admin", "user", "add", minioAlias, u.AccessKey, u.SecretKey})
+		if err != nil {
+			return xerrors.Errorf("Unable to create user %s: %w", u.AccessKey, err)
+		}
+
+		err = m.exec(id, []string{"mc", "admin", "policy", "set", minioAlias, u.Policy, fmt.Sprintf("user=%s", u.AccessKey)})
+		if err != nil {
+			return xerrors.Errorf("Unable to set policy %s for user %s: %w", u.Policy, u.AccessKey, err)
+		}
+	}
+
+	return nil
+}
+
+func (m *MinIO) exec(id string, command []string) error {
+	return m.client.ExecuteCommand(id, command, []string{}, "/", m.log.StandardWriter(&hclog.StandardLoggerOptions{ForceLevel: hclog.Debug}))
+}
+
+func (m *MinIO) createClientContainer(ctx context.Context) (string, error) {
+	cc := config.NewContainer("minio_temp")
+	m.config.ResourceInfo.AddChild(cc)
+
+	for _, n := range m.config.Networks {
+		cc.Networks = append(cc.Networks, config.NetworkAttachment{Name: n.Name})
+	}
+
+	cc.Image = config.Image{Name: minioClientImage}
+	cc.Entrypoint = []string{"tail"}
+	cc.Command = []string{"-f", "/dev/null"} // ensure container does not immediately exit
+
+	err := m.client.PullImage(ctx, cc.Image, false)
+	if err != nil {
+		m.log.Error("Error pulling container image", "ref", cc.Name, "image", cc.Image.Name)
+
+		return "", err
+	}
+
+	return m.client.CreateContainer(cc)
+}
@@ -0,0 +1,92 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupMinIO() (*config.MinIO, *mocks.MockContainerTasks, *mocks.MockHTTP) {
+	m := config.NewMinIO("storage")
+	m.Buckets = []string{"images"}
+	m.Users = []config.MinIOUser{
+		config.MinIOUser{AccessKey: "app", SecretKey: "password", Policy: "readonly"},
+	}
+	m.Address = "http://localhost:9000"
+	m.InternalAddress = "http://storage.minio.shipyard.run:9000"
+
+	md := &mocks.MockContainerTasks{}
+	md.On("PullImage", mock.Anything, mock.Anything).Return(nil)
+	md.On("CreateContainer", mock.Anything).Return("abc", nil)
+	md.On("RemoveContainer", mock.Anything).Return(nil)
+	md.On("ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	hc := &mocks.MockHTTP{}
+	hc.On("HealthCheckHTTP", mock.Anything, mock.Anything).Return(nil)
+
+	return m, md, hc
+}
+
+func TestMinIOCreatesServerContainer(t *testing.T) {
+	m, md, hc := setupMinIO()
+	p := NewMinIO(m, md, hc, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	cc := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+	assert.Equal(t, "minio/minio:RELEASE.2021-01-16T02-19-44Z", cc.Image.Name)
+	assert.Contains(t, cc.Environment, config.KV{Key: "MINIO_ROOT_USER", Value: "minio"})
+	assert.Equal(t, "9000", cc.Ports[0].Host)
+
+	hc.AssertCalled(t, "HealthCheckHTTP", "http://localhost:9000/minio/health/live", mock.Anything)
+}
+
+func TestMinIOCreatesBucketsAndUsers(t *testing.T) {
+	m, md, hc := setupMinIO()
+	p := NewMinIO(m, md, hc, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	calls := getCalls(&md.Mock, "ExecuteCommand")
+	require.Len(t, calls, 4)
+	assert.Equal(t, []string{"mc", "alias", "set", "shipyard", "http://storage.minio.shipyard.run:9000", "minio", "minio123"}, calls[0].Arguments[1])
+	assert.Equal(t, []string{"mc", "mb", "--ignore-existing", "shipyard/images"}, calls[1].Arguments[1])
+	assert.Equal(t, []string{"mc", "admin", "user", "add", "shipyard", "app", "password"}, calls[2].Arguments[1])
+	assert.Equal(t, []string{"mc", "admin", "policy", "set", "shipyard", "readonly", "user=app"}, calls[3].Arguments[1])
+
+	md.AssertCalled(t, "RemoveContainer", "abc")
+}
+
+func TestMinIOWithoutBucketsDoesNotCreateClient(t *testing.T) {
+	m, md, hc := setupMinIO()
+	m.Buckets = nil
+	m.Users = nil
+	p := NewMinIO(m, md, hc, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	md.AssertNumberOfCalls(t, "CreateContainer", 1)
+	md.AssertNotCalled(t, "ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMinIOReturnsErrorWhenBucketFails(t *testing.T) {
+	m, md, hc := setupMinIO()
+	removeOn(&md.Mock, "ExecuteCommand")
+	md.On("ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("exit code 1"))
+	p := NewMinIO(m, md, hc, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+
+	md.AssertCalled(t, "RemoveContainer", "abc")
+}
@@ -0,0 +1,162 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"golang.org/x/xerrors"
+)
+
+// OpenAPI specs are served by Prism, routes are served by WireMock
+const mockAPISpecImage = "stoplight/prism:4"
+const mockAPIRoutesImage = "wiremock/wiremock:2.32.0"
+
+// MockAPI is a provider for serving mock HTTP APIs
+type MockAPI struct {
+	config *config.MockAPI
+	client clients.ContainerTasks
+	log    hclog.Logger
+}
+
+// NewMockAPI creates a new MockAPI provider
+func NewMockAPI(m *config.MockAPI, cl clients.ContainerTasks, l hclog.Logger) *MockAPI {
+	return &MockAPI{m, cl, l}
+}
+
+// Create the container which serves the API
+func (m *MockAPI) Create(ctx context.Context) error {
+	m.log.Info("Creating Mock API", "ref", m.config.Name, "address", m.config.InternalAddress)
+
+	cc := config.NewContainer(m.config.Name)
+	m.config.ResourceInfo.AddChild(cc)
+
+	cc.Networks = m.config.Networks
+
+	if m.config.Spec != "" {
+		spec := fmt.Sprintf("/spec/%s", filepath.Base(m.config.Spec))
+
+		cc.Image = config.Image{Name: mockAPISpecImage}
+		cc.Command = []string{"mock", "-h", "0.0.0.0", spec}
+		cc.Volumes = []config.Volume{
+			config.Volume{
+				Source:      m.config.Spec,
+				Destination: spec,
+			},
+		}
+	} else {
+		err := m.writeMappings()
+		if err != nil {
+			return err
+		}
+
+		cc.Image = config.Image{Name: mockAPIRoutesImage}
+		cc.Volumes = []config.Volume{
+			config.Volume{
+				Source:      filepath.Join(m.configDir(), "mappings"),
+				Destination: "/home/wiremock/mappings",
+			},
+		}
+	}
+
+	if m.config.Port > 0 {
+		cc.Ports = []config.Port{
+			config.Port{
+				Local:    fmt.Sprintf("%d", m.config.ListenPort()),
+				Host:     fmt.Sprintf("%d", m.config.Port),
+				Protocol: "tcp",
+			},
+		}
+	}
+
+	err := m.client.PullImage(ctx, cc.Image, false)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.client.CreateContainer(cc)
+	return err
+}
+
+// Destroy the container and the generated mappings
+func (m *MockAPI) Destroy() error {
+	m.log.Info("Destroy Mock API", "ref", m.config.Name)
+
+	ids, err := m.Lookup()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		err := m.client.RemoveContainer(id)
+		if err != nil {
+			return err
+		}
+	}
+
+	os.RemoveAll(m.configDir())
+
+	return nil
+}
+
+// Lookup the ID of the container
+func (m *MockAPI) Lookup() ([]string, error) {
+	return m.client.FindContainerIDs(m.config.Name, m.config.Type)
+}
+
+func (m *MockAPI) configDir() string {
+	return filepath.Join(utils.ShipyardTemp(), "mock_api", m.config.Name)
+}
+
+// writeMappings writes the routes as WireMock stub mappings
+func (m *MockAPI) writeMappings() error {
+	dir := filepath.Join(m.configDir(), "mappings")
+
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return xerrors.Errorf("Unable to create folder for mock API mappings: %w", err)
+	}
+
+	type request struct {
+		Method  string `json:"method"`
+		URLPath string `json:"urlPath"`
+	}
+
+	type response struct {
+		Status  int               `json:"status"`
+		Body    string            `json:"body,omitempty"`
+		Headers map[string]string `json:"headers,omitempty"`
+	}
+
+	type mapping struct {
+		Request  request  `json:"request"`
+		Response response `json:"response"`
+	}
+
+	mappings := []mapping{}
+	for _, r := range m.config.Routes {
+		mappings = append(mappings, mapping{
+			Request:  request{Method: r.Method, URLPath: r.Path},
+			Response: response{Status: r.Status, Body: r.Body, Headers: r.Headers},
+		})
+	}
+
+	d, err := json.MarshalIndent(map[string]interface{}{"mappings": mappings}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = ioutil.WriteFile(filepath.Join(dir, "routes.json"), d, 0644)
+	if err != nil {
+		return xerrors.Errorf("Unable to write mock API mappings: %w", err)
+	}
+
+	return nil
+}
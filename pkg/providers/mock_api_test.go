@@ -0,0 +1,104 @@
+package providers
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupMockAPI(t *testing.T) (*config.MockAPI, *mocks.MockContainerTasks, func()) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+
+	home := os.Getenv("HOME")
+	os.Setenv("HOME", dir)
+
+	m := config.NewMockAPI("payments")
+	m.Networks = []config.NetworkAttachment{{Name: "network.local"}}
+	m.Routes = []config.MockRoute{
+		config.MockRoute{Method: "GET", Path: "/payments", Status: 200, Body: `[]`, Headers: map[string]string{"Content-Type": "application/json"}},
+		config.MockRoute{Method: "POST", Path: "/payments", Status: 201},
+	}
+	m.Port = 9090
+
+	md := &mocks.MockContainerTasks{}
+	md.On("PullImage", mock.Anything, mock.Anything).Return(nil)
+	md.On("CreateContainer", mock.Anything).Return("abc", nil)
+	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return([]string{"abc"}, nil)
+	md.On("RemoveContainer", mock.Anything).Return(nil)
+
+	return m, md, func() {
+		os.Setenv("HOME", home)
+		os.RemoveAll(dir)
+	}
+}
+
+func TestMockAPIWithRoutesWritesMappings(t *testing.T) {
+	m, md, cleanup := setupMockAPI(t)
+	defer cleanup()
+
+	p := NewMockAPI(m, md, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	cc := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+	assert.Equal(t, "wiremock/wiremock:2.32.0", cc.Image.Name)
+	assert.Equal(t, "/home/wiremock/mappings", cc.Volumes[0].Destination)
+	assert.Equal(t, "8080", cc.Ports[0].Local)
+	assert.Equal(t, "9090", cc.Ports[0].Host)
+
+	d, err := ioutil.ReadFile(filepath.Join(cc.Volumes[0].Source, "routes.json"))
+	require.NoError(t, err)
+
+	assert.Contains(t, string(d), `"urlPath": "/payments"`)
+	assert.Contains(t, string(d), `"method": "POST"`)
+	assert.Contains(t, string(d), `"status": 201`)
+	assert.Contains(t, string(d), `"Content-Type": "application/json"`)
+}
+
+func TestMockAPIWithSpecUsesPrism(t *testing.T) {
+	m, md, cleanup := setupMockAPI(t)
+	defer cleanup()
+
+	m.Routes = nil
+	m.Spec = "/tmp/api/openapi.yaml"
+	m.Port = 0
+
+	p := NewMockAPI(m, md, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	cc := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+	assert.Equal(t, "stoplight/prism:4", cc.Image.Name)
+	assert.Equal(t, []string{"mock", "-h", "0.0.0.0", "/spec/openapi.yaml"}, cc.Command)
+	assert.Equal(t, "/tmp/api/openapi.yaml", cc.Volumes[0].Source)
+	assert.Len(t, cc.Ports, 0)
+}
+
+func TestMockAPIDestroyRemovesMappings(t *testing.T) {
+	m, md, cleanup := setupMockAPI(t)
+	defer cleanup()
+
+	p := NewMockAPI(m, md, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	err = p.Destroy()
+	require.NoError(t, err)
+
+	md.AssertCalled(t, "RemoveContainer", "abc")
+	assert.NoDirExists(t, filepath.Join(utils.ShipyardTemp(), "mock_api", "payments"))
+}
@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"context"
+
 	"github.com/shipyard-run/shipyard/pkg/config"
 	"github.com/stretchr/testify/mock"
 )
@@ -14,7 +16,7 @@ func New(c config.Resource) *MockProvider {
 	return &MockProvider{c: c}
 }
 
-func (m *MockProvider) Create() error {
+func (m *MockProvider) Create(ctx context.Context) error {
 	args := m.Called()
 	return args.Error(0)
 }
@@ -29,6 +31,11 @@ func (m *MockProvider) Lookup() ([]string, error) {
 	return args.Get(0).([]string), args.Error(1)
 }
 
+func (m *MockProvider) Drifted() (bool, error) {
+	args := m.Called()
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *MockProvider) Config() config.Resource {
 	return m.c
 }
@@ -27,7 +27,7 @@ func NewNetwork(co *config.Network, cl clients.Docker, l hclog.Logger) *Network
 }
 
 // Create implements the provider interface method for creating new networks
-func (n *Network) Create() error {
+func (n *Network) Create(ctx context.Context) error {
 	n.log.Info("Creating Network", "ref", n.config.Name)
 
 	// validate the subnet
@@ -127,6 +127,31 @@ func (n *Network) Lookup() ([]string, error) {
 	return ids, nil
 }
 
+// Drifted returns true when the network has been removed or its subnet
+// no longer matches the config
+func (n *Network) Drifted() (bool, error) {
+	nets, err := n.getNetworks(n.config.Name)
+	if err != nil {
+		return false, err
+	}
+
+	for _, ne := range nets {
+		if ne.Name != n.config.Name {
+			continue
+		}
+
+		for _, ci := range ne.IPAM.Config {
+			if ci.Subnet != n.config.Subnet {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	}
+
+	return true, nil
+}
+
 func (n *Network) getNetworks(name string) ([]types.NetworkResource, error) {
 	args := filters.NewArgs()
 	args.Add("name", name)
@@ -0,0 +1,156 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"golang.org/x/xerrors"
+)
+
+const networkRouteImage = "nicolaka/netshoot:v0.1"
+
+// NetworkRoute is a provider which allows traffic between two networks.
+// Docker routes between networks on the host but drops the traffic using
+// iptables, rules are added to the DOCKER-USER chain which is evaluated
+// before the isolation rules.
+type NetworkRoute struct {
+	config *config.NetworkRoute
+	client clients.ContainerTasks
+	log    hclog.Logger
+}
+
+// NewNetworkRoute creates a new NetworkRoute provider
+func NewNetworkRoute(n *config.NetworkRoute, cl clients.ContainerTasks, l hclog.Logger) *NetworkRoute {
+	return &NetworkRoute{n, cl, l}
+}
+
+// Create a container in the host network and add the iptables rules
+func (n *NetworkRoute) Create(ctx context.Context) error {
+	n.log.Info("Creating Network Route", "ref", n.config.Name, "networks", n.config.Networks)
+
+	rules, err := n.rules()
+	if err != nil {
+		return err
+	}
+
+	cc := config.NewContainer(n.config.Name)
+	n.config.ResourceInfo.AddChild(cc)
+
+	cc.Image = config.Image{Name: networkRouteImage}
+	cc.HostNetwork = true
+	cc.Privileged = true
+	cc.Command = []string{"tail", "-f", "/dev/null"} // ensure container does not immediately exit
+
+	err = n.client.PullImage(ctx, cc.Image, false)
+	if err != nil {
+		return err
+	}
+
+	id, err := n.client.CreateContainer(cc)
+	if err != nil {
+		return err
+	}
+
+	// rules are inserted at the top of the chain so insert in reverse
+	for i := len(rules) - 1; i >= 0; i-- {
+		err := n.iptables(id, "-I", rules[i])
+		if err != nil {
+			return xerrors.Errorf("Unable to add route: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Destroy removes the iptables rules and the container
+func (n *NetworkRoute) Destroy() error {
+	n.log.Info("Destroy Network Route", "ref", n.config.Name)
+
+	ids, err := n.Lookup()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		rules, err := n.rules()
+		if err == nil {
+			for _, r := range rules {
+				err := n.iptables(id, "-D", r)
+				if err != nil {
+					n.log.Error("Unable to remove route", "ref", n.config.Name, "error", err)
+				}
+			}
+		}
+
+		err = n.client.RemoveContainer(id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Lookup the ID of the container
+func (n *NetworkRoute) Lookup() ([]string, error) {
+	return n.client.FindContainerIDs(n.config.Name, n.config.Type)
+}
+
+func (n *NetworkRoute) iptables(id, action string, rule []string) error {
+	command := append([]string{"iptables", action, "DOCKER-USER"}, rule...)
+
+	return n.client.ExecuteCommand(id, command, []string{}, "/", n.log.StandardWriter(&hclog.StandardLoggerOptions{ForceLevel: hclog.Debug}))
+}
+
+// rules returns the iptables rules for the route, replies are always allowed
+// so that connections can only be opened in one direction when one_way is set
+func (n *NetworkRoute) rules() ([][]string, error) {
+	subnets := []string{}
+	for _, name := range n.config.Networks {
+		r, err := n.config.FindDependentResource(name)
+		if err != nil {
+			return nil, xerrors.Errorf("Unable to find network: %w", err)
+		}
+
+		net, ok := r.(*config.Network)
+		if !ok {
+			return nil, fmt.Errorf("Unable to create route, %s is not a network", name)
+		}
+
+		subnets = append(subnets, net.Subnet)
+	}
+
+	if len(subnets) != 2 {
+		return nil, fmt.Errorf("Unable to create route, two networks are required")
+	}
+
+	rules := [][]string{}
+	rules = append(rules, n.allow(subnets[0], subnets[1])...)
+
+	if !n.config.OneWay {
+		rules = append(rules, n.allow(subnets[1], subnets[0])...)
+	}
+
+	rules = append(rules,
+		[]string{"-s", subnets[1], "-d", subnets[0], "-m", "conntrack", "--ctstate", "ESTABLISHED,RELATED", "-j", "ACCEPT"},
+		[]string{"-s", subnets[0], "-d", subnets[1], "-m", "conntrack", "--ctstate", "ESTABLISHED,RELATED", "-j", "ACCEPT"},
+	)
+
+	return rules, nil
+}
+
+func (n *NetworkRoute) allow(src, dst string) [][]string {
+	if len(n.config.Ports) == 0 {
+		return [][]string{[]string{"-s", src, "-d", dst, "-j", "ACCEPT"}}
+	}
+
+	rules := [][]string{}
+	for _, p := range n.config.Ports {
+		rules = append(rules, []string{"-s", src, "-d", dst, "-p", "tcp", "--dport", fmt.Sprintf("%d", p), "-j", "ACCEPT"})
+	}
+
+	return rules
+}
@@ -0,0 +1,105 @@
+package providers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupNetworkRoute() (*config.NetworkRoute, *mocks.MockContainerTasks) {
+	n1 := config.NewNetwork("onprem")
+	n1.Subnet = "10.5.0.0/16"
+
+	n2 := config.NewNetwork("cloud")
+	n2.Subnet = "10.6.0.0/16"
+
+	nr := config.NewNetworkRoute("vpn")
+	nr.Networks = []string{"network.onprem", "network.cloud"}
+
+	c := config.New()
+	c.AddResource(n1)
+	c.AddResource(n2)
+	c.AddResource(nr)
+
+	md := &mocks.MockContainerTasks{}
+	md.On("PullImage", mock.Anything, mock.Anything).Return(nil)
+	md.On("CreateContainer", mock.Anything).Return("abc", nil)
+	md.On("FindContainerIDs", mock.Anything, mock.Anything).Return([]string{"abc"}, nil)
+	md.On("RemoveContainer", mock.Anything).Return(nil)
+	md.On("ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	return nr, md
+}
+
+func TestNetworkRouteCreatesHostContainer(t *testing.T) {
+	nr, md := setupNetworkRoute()
+	p := NewNetworkRoute(nr, md, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	cc := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+	assert.True(t, cc.HostNetwork)
+	assert.True(t, cc.Privileged)
+}
+
+func TestNetworkRouteAllowsTrafficBothWays(t *testing.T) {
+	nr, md := setupNetworkRoute()
+	p := NewNetworkRoute(nr, md, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	calls := getCalls(&md.Mock, "ExecuteCommand")
+	require.Len(t, calls, 4)
+
+	// rules are inserted in reverse order
+	assert.Equal(t, []string{"iptables", "-I", "DOCKER-USER", "-s", "10.6.0.0/16", "-d", "10.5.0.0/16", "-j", "ACCEPT"}, calls[2].Arguments[1])
+	assert.Equal(t, []string{"iptables", "-I", "DOCKER-USER", "-s", "10.5.0.0/16", "-d", "10.6.0.0/16", "-j", "ACCEPT"}, calls[3].Arguments[1])
+}
+
+func TestNetworkRouteOneWayWithPorts(t *testing.T) {
+	nr, md := setupNetworkRoute()
+	nr.OneWay = true
+	nr.Ports = []int{5432}
+	p := NewNetworkRoute(nr, md, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	calls := getCalls(&md.Mock, "ExecuteCommand")
+	require.Len(t, calls, 3)
+	assert.Equal(t, []string{"iptables", "-I", "DOCKER-USER", "-s", "10.5.0.0/16", "-d", "10.6.0.0/16", "-p", "tcp", "--dport", "5432", "-j", "ACCEPT"}, calls[2].Arguments[1])
+}
+
+func TestNetworkRouteReturnsErrorWhenNotNetwork(t *testing.T) {
+	nr, md := setupNetworkRoute()
+	nr.Networks = []string{"network.onprem", "container.app"}
+	nr.Config.AddResource(config.NewContainer("app"))
+	p := NewNetworkRoute(nr, md, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+
+	md.AssertNotCalled(t, "CreateContainer", mock.Anything)
+}
+
+func TestNetworkRouteDestroyRemovesRules(t *testing.T) {
+	nr, md := setupNetworkRoute()
+	p := NewNetworkRoute(nr, md, hclog.NewNullLogger())
+
+	err := p.Destroy()
+	require.NoError(t, err)
+
+	calls := getCalls(&md.Mock, "ExecuteCommand")
+	require.Len(t, calls, 4)
+	assert.Equal(t, "-D", calls[0].Arguments[1].([]string)[1])
+
+	md.AssertCalled(t, "RemoveContainer", "abc")
+}
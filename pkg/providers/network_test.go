@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -57,7 +58,7 @@ func TestNetworkCreatesCorrectly(t *testing.T) {
 
 	md, p := setupNetworkTests(c)
 
-	p.Create()
+	p.Create(context.Background())
 
 	md.AssertCalled(t, "NetworkCreate", mock.Anything, mock.Anything, mock.Anything)
 
@@ -85,7 +86,7 @@ func TestNetworkDoesNOTCreateWhenExists(t *testing.T) {
 			},
 		}}, nil)
 
-	p.Create()
+	p.Create(context.Background())
 
 	md.AssertNotCalled(t, "NetworkCreate", mock.Anything, mock.Anything, mock.Anything)
 }
@@ -104,7 +105,7 @@ func TestCreateWithCorrectNameAndDifferentSubnetReturnsError(t *testing.T) {
 			},
 		}}, nil)
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -122,6 +123,55 @@ func TestCreateWithOverlappingSubnetReturnsError(t *testing.T) {
 			},
 		}}, nil)
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
+
+func TestNetworkDriftedReturnsFalseWhenNetworkMatches(t *testing.T) {
+	c := config.NewNetwork("testnet")
+	c.Subnet = "10.1.2.0/24"
+
+	md, p := setupNetworkTests(c)
+	removeOn(&md.Mock, "NetworkList")
+	md.On("NetworkList", mock.Anything, mock.Anything).Return([]types.NetworkResource{
+		types.NetworkResource{
+			Name: "testnet",
+			IPAM: network.IPAM{
+				Config: []network.IPAMConfig{network.IPAMConfig{Subnet: "10.1.2.0/24"}},
+			},
+		}}, nil)
+
+	drifted, err := p.Drifted()
+	assert.NoError(t, err)
+	assert.False(t, drifted)
+}
+
+func TestNetworkDriftedReturnsTrueWhenSubnetChanged(t *testing.T) {
+	c := config.NewNetwork("testnet")
+	c.Subnet = "10.1.2.0/24"
+
+	md, p := setupNetworkTests(c)
+	removeOn(&md.Mock, "NetworkList")
+	md.On("NetworkList", mock.Anything, mock.Anything).Return([]types.NetworkResource{
+		types.NetworkResource{
+			Name: "testnet",
+			IPAM: network.IPAM{
+				Config: []network.IPAMConfig{network.IPAMConfig{Subnet: "10.1.3.0/24"}},
+			},
+		}}, nil)
+
+	drifted, err := p.Drifted()
+	assert.NoError(t, err)
+	assert.True(t, drifted)
+}
+
+func TestNetworkDriftedReturnsTrueWhenRemoved(t *testing.T) {
+	c := config.NewNetwork("testnet")
+	c.Subnet = "10.1.2.0/24"
+
+	_, p := setupNetworkTests(c)
+
+	drifted, err := p.Drifted()
+	assert.NoError(t, err)
+	assert.True(t, drifted)
+}
@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"time"
 
 	"github.com/hashicorp/go-hclog"
@@ -24,7 +25,7 @@ func NewNomadJob(c *config.NomadJob, hc clients.Nomad, l hclog.Logger) *NomadJob
 }
 
 // Create the Nomad jobs defined by the config
-func (n *NomadJob) Create() error {
+func (n *NomadJob) Create(ctx context.Context) error {
 	n.log.Info("Create Nomad Job", "ref", n.config.Name, "files", n.config.Paths)
 
 	// find the cluster
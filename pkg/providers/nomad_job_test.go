@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -41,7 +42,7 @@ func TestNomadJobWithNonExistentClusterReturnsError(t *testing.T) {
 
 	p := NewNomadJob(jc, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -54,7 +55,7 @@ func TestNomadJobUnableToLoadConfigReturnsError(t *testing.T) {
 
 	p := NewNomadJob(jc, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 func TestNomadJobCreateReturnsError(t *testing.T) {
@@ -64,7 +65,7 @@ func TestNomadJobCreateReturnsError(t *testing.T) {
 
 	p := NewNomadJob(jc, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
 
@@ -73,6 +74,6 @@ func TestNomadJobValidatesConfig(t *testing.T) {
 
 	p := NewNomadJob(jc, mh, hclog.NewNullLogger())
 
-	err := p.Create()
+	err := p.Create(context.Background())
 	assert.NoError(t, err)
 }
@@ -0,0 +1,38 @@
+package providers
+
+import (
+	"context"
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/config"
+)
+
+// Null is a provider for null_resource resources, the resource only exists
+// in the state so the provider has nothing to create
+type Null struct {
+	config config.Resource
+	log    hclog.Logger
+}
+
+// NewNull creates a new null provider
+func NewNull(r config.Resource, l hclog.Logger) *Null {
+	return &Null{r, l}
+}
+
+// Create does nothing
+func (n *Null) Create(ctx context.Context) error {
+	n.log.Info("Creating Null Resource", "ref", n.config.Info().Name)
+
+	return nil
+}
+
+// Destroy does nothing
+func (n *Null) Destroy() error {
+	n.log.Info("Destroy Null Resource", "ref", n.config.Info().Name)
+
+	return nil
+}
+
+// Lookup returns nothing
+func (n *Null) Lookup() ([]string, error) {
+	return nil, nil
+}
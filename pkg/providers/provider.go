@@ -1,12 +1,24 @@
 package providers
 
+import "context"
+
 // Provider defines an interface to be implemented by providers
 type Provider interface {
-	Create() error
+	// Create creates the objects for the config, ctx contains the span for
+	// the call so that the spans of clients are children of it
+	Create(ctx context.Context) error
 	Destroy() error
 	Lookup() ([]string, error)
 }
 
+// Refresher is implemented by providers which can detect when the objects
+// they created have been deleted outside of Shipyard
+type Refresher interface {
+	// Drifted returns true when the objects created for the config have
+	// been removed from the runtime
+	Drifted() (bool, error)
+}
+
 // ConfigWrapper alows the provider config to be deserialized to a type
 type ConfigWrapper struct {
 	Type  string
@@ -0,0 +1,39 @@
+package providers
+
+import (
+	"context"
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/config"
+)
+
+// Random is a provider for random_password and random_id resources, the
+// values are generated when the config is parsed and stored in the state
+// so the provider has nothing to create
+type Random struct {
+	config config.Resource
+	log    hclog.Logger
+}
+
+// NewRandom creates a new random provider
+func NewRandom(r config.Resource, l hclog.Logger) *Random {
+	return &Random{r, l}
+}
+
+// Create does nothing, the value is stored in the state
+func (r *Random) Create(ctx context.Context) error {
+	r.log.Info("Creating Random Value", "ref", r.config.Info().Name, "type", r.config.Info().Type)
+
+	return nil
+}
+
+// Destroy does nothing, the value is removed with the state
+func (r *Random) Destroy() error {
+	r.log.Info("Destroy Random Value", "ref", r.config.Info().Name, "type", r.config.Info().Type)
+
+	return nil
+}
+
+// Lookup returns nothing
+func (r *Random) Lookup() ([]string, error) {
+	return nil, nil
+}
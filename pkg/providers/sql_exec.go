@@ -0,0 +1,206 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"golang.org/x/xerrors"
+)
+
+// sqlClients are the images and default ports used to connect to each
+// database driver
+var sqlClients = map[string]struct {
+	image string
+	port  string
+}{
+	"postgres": {"postgres:14-alpine", "5432"},
+	"mysql":    {"mysql:8", "3306"},
+}
+
+// SQLExec is a provider which runs SQL against a database in a container
+type SQLExec struct {
+	config *config.SQLExec
+	client clients.ContainerTasks
+	log    hclog.Logger
+}
+
+// NewSQLExec creates a provider which runs SQL files and statements
+func NewSQLExec(c *config.SQLExec, cc clients.ContainerTasks, l hclog.Logger) *SQLExec {
+	return &SQLExec{c, cc, l}
+}
+
+// Create waits for the database to accept connections and then runs the
+// files and inline SQL using the database client in a temporary container
+func (s *SQLExec) Create(ctx context.Context) error {
+	s.log.Info("Executing SQL", "ref", s.config.Name, "target", s.config.Target, "driver", s.config.Driver)
+
+	timeout, err := time.ParseDuration(s.config.Timeout)
+	if err != nil {
+		return xerrors.Errorf("Unable to parse timeout %s: %w", s.config.Timeout, err)
+	}
+
+	target, err := s.config.FindDependentResource(s.config.Target)
+	if err != nil {
+		return xerrors.Errorf("Unable to find target: %w", err)
+	}
+
+	co, ok := target.(*config.Container)
+	if !ok {
+		return fmt.Errorf("Unable to execute SQL, target %s is not a container", s.config.Target)
+	}
+
+	id, err := s.createClientContainer(ctx, co.Networks)
+	if err != nil {
+		return xerrors.Errorf("Unable to create container for database client: %w", err)
+	}
+
+	err = s.execute(id, utils.FQDN(co.Name, string(co.Type)), timeout)
+
+	s.client.RemoveContainer(id)
+
+	return err
+}
+
+// Destroy does nothing, SQL can not be reverted
+func (s *SQLExec) Destroy() error {
+	return nil
+}
+
+// Lookup returns nothing
+func (s *SQLExec) Lookup() ([]string, error) {
+	return []string{}, nil
+}
+
+func (s *SQLExec) execute(id, host string, timeout time.Duration) error {
+	port := s.config.Port
+	if port == "" {
+		port = sqlClients[s.config.Driver].port
+	}
+
+	env := []string{}
+	if s.config.Driver == "postgres" {
+		env = append(env, fmt.Sprintf("PGPASSWORD=%s", s.config.Password))
+	} else {
+		env = append(env, fmt.Sprintf("MYSQL_PWD=%s", s.config.Password))
+	}
+
+	st := time.Now()
+	for {
+		err := s.exec(id, s.readyCommand(host, port), env)
+		if err == nil {
+			break
+		}
+
+		if time.Since(st) > timeout {
+			return xerrors.Errorf("Timeout waiting for database %s: %w", s.config.Target, err)
+		}
+
+		time.Sleep(time.Second)
+	}
+
+	for _, f := range s.config.Files {
+		err := s.client.CopyToContainer(id, f, "/sql")
+		if err != nil {
+			return xerrors.Errorf("Unable to copy SQL file %s: %w", f, err)
+		}
+
+		dst := "/sql/" + filepath.Base(f)
+
+		err = s.exec(id, s.fileCommand(host, port, dst), env)
+		if err != nil {
+			return xerrors.Errorf("Unable to execute SQL file %s: %w", f, err)
+		}
+	}
+
+	if s.config.SQL != "" {
+		err := s.exec(id, s.sqlCommand(host, port, s.config.SQL), env)
+		if err != nil {
+			return xerrors.Errorf("Unable to execute SQL: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *SQLExec) exec(id string, command, env []string) error {
+	return s.client.ExecuteCommand(id, command, env, "/", s.log.StandardWriter(&hclog.StandardLoggerOptions{ForceLevel: hclog.Debug}))
+}
+
+func (s *SQLExec) readyCommand(host, port string) []string {
+	if s.config.Driver == "postgres" {
+		return append([]string{"pg_isready"}, s.postgresArgs(host, port)...)
+	}
+
+	return append([]string{"mysqladmin", "ping"}, s.mysqlArgs(host, port)...)
+}
+
+func (s *SQLExec) fileCommand(host, port, file string) []string {
+	if s.config.Driver == "postgres" {
+		return append([]string{"psql", "-v", "ON_ERROR_STOP=1"}, append(s.postgresArgs(host, port), "-f", file)...)
+	}
+
+	// the mysql client reads the file with the source command
+
+	return s.sqlCommand(host, port, fmt.Sprintf("source %s", file))
+}
+
+func (s *SQLExec) sqlCommand(host, port, sql string) []string {
+	if s.config.Driver == "postgres" {
+		return append([]string{"psql", "-v", "ON_ERROR_STOP=1"}, append(s.postgresArgs(host, port), "-c", sql)...)
+	}
+
+	return append([]string{"mysql"}, append(s.mysqlArgs(host, port), "-e", sql)...)
+}
+
+func (s *SQLExec) postgresArgs(host, port string) []string {
+	args := []string{"-h", host, "-p", port}
+	if s.config.Username != "" {
+		args = append(args, "-U", s.config.Username)
+	}
+
+	if s.config.Database != "" {
+		args = append(args, "-d", s.config.Database)
+	}
+
+	return args
+}
+
+func (s *SQLExec) mysqlArgs(host, port string) []string {
+	args := []string{"-h", host, "-P", port}
+	if s.config.Username != "" {
+		args = append(args, "-u", s.config.Username)
+	}
+
+	if s.config.Database != "" {
+		args = append(args, s.config.Database)
+	}
+
+	return args
+}
+
+func (s *SQLExec) createClientContainer(ctx context.Context, networks []config.NetworkAttachment) (string, error) {
+	cc := config.NewContainer("sql_exec_temp")
+	s.config.ResourceInfo.AddChild(cc)
+
+	for _, n := range networks {
+		cc.Networks = append(cc.Networks, config.NetworkAttachment{Name: n.Name})
+	}
+
+	cc.Image = config.Image{Name: sqlClients[s.config.Driver].image}
+	cc.Command = []string{"tail", "-f", "/dev/null"} // ensure container does not immediately exit
+
+	err := s.client.PullImage(ctx, cc.Image, false)
+	if err != nil {
+		s.log.Error("Error pulling container image", "ref", cc.Name, "image", cc.Image.Name)
+
+		return "", err
+	}
+
+	return s.client.CreateContainer(cc)
+}
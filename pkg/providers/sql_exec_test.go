@@ -0,0 +1,98 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupSQLExec() (*config.SQLExec, *mocks.MockContainerTasks) {
+	co := config.NewContainer("postgres")
+	co.Networks = []config.NetworkAttachment{{Name: "network.local", Aliases: []string{"db"}}}
+
+	se := config.NewSQLExec("seed")
+	se.Target = "container.postgres"
+	se.Driver = "postgres"
+	se.Username = "postgres"
+	se.Password = "password"
+	se.Database = "app"
+	se.Files = []string{"/tmp/sql/schema.sql"}
+	se.SQL = "INSERT INTO users VALUES (1)"
+
+	c := config.New()
+	c.AddResource(co)
+	c.AddResource(se)
+
+	md := &mocks.MockContainerTasks{}
+	md.On("PullImage", mock.Anything, mock.Anything).Return(nil)
+	md.On("CreateContainer", mock.Anything).Return("abc", nil)
+	md.On("RemoveContainer", mock.Anything).Return(nil)
+	md.On("CopyToContainer", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	md.On("ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	return se, md
+}
+
+func TestSQLExecRunsFilesAndSQL(t *testing.T) {
+	se, md := setupSQLExec()
+	p := NewSQLExec(se, md, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	cc := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+	assert.Equal(t, "postgres:14-alpine", cc.Image.Name)
+	assert.Equal(t, []config.NetworkAttachment{{Name: "network.local"}}, cc.Networks)
+
+	md.AssertCalled(t, "CopyToContainer", "abc", "/tmp/sql/schema.sql", "/sql")
+
+	calls := getCalls(&md.Mock, "ExecuteCommand")
+	require.Len(t, calls, 3)
+
+	host := "postgres.container.shipyard.run"
+	assert.Equal(t, []string{"pg_isready", "-h", host, "-p", "5432", "-U", "postgres", "-d", "app"}, calls[0].Arguments[1])
+	assert.Equal(t, []string{"psql", "-v", "ON_ERROR_STOP=1", "-h", host, "-p", "5432", "-U", "postgres", "-d", "app", "-f", "/sql/schema.sql"}, calls[1].Arguments[1])
+	assert.Equal(t, []string{"psql", "-v", "ON_ERROR_STOP=1", "-h", host, "-p", "5432", "-U", "postgres", "-d", "app", "-c", se.SQL}, calls[2].Arguments[1])
+	assert.Equal(t, []string{"PGPASSWORD=password"}, calls[2].Arguments[2])
+
+	md.AssertCalled(t, "RemoveContainer", "abc")
+}
+
+func TestSQLExecRunsMySQLFilesWithSource(t *testing.T) {
+	se, md := setupSQLExec()
+	se.Driver = "mysql"
+	se.SQL = ""
+	p := NewSQLExec(se, md, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	calls := getCalls(&md.Mock, "ExecuteCommand")
+	require.Len(t, calls, 2)
+
+	host := "postgres.container.shipyard.run"
+	assert.Equal(t, []string{"mysqladmin", "ping", "-h", host, "-P", "3306", "-u", "postgres", "app"}, calls[0].Arguments[1])
+	assert.Equal(t, []string{"mysql", "-h", host, "-P", "3306", "-u", "postgres", "app", "-e", "source /sql/schema.sql"}, calls[1].Arguments[1])
+	assert.Equal(t, []string{"MYSQL_PWD=password"}, calls[1].Arguments[2])
+}
+
+func TestSQLExecReturnsErrorWhenDatabaseNotReady(t *testing.T) {
+	se, md := setupSQLExec()
+	se.Timeout = "1ns"
+	removeOn(&md.Mock, "ExecuteCommand")
+	md.On("ExecuteCommand", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("no response"))
+	p := NewSQLExec(se, md, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+
+	md.AssertNotCalled(t, "CopyToContainer", mock.Anything, mock.Anything, mock.Anything)
+	md.AssertCalled(t, "RemoveContainer", "abc")
+}
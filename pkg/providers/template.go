@@ -0,0 +1,96 @@
+package providers
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/zclconf/go-cty/cty"
+	"golang.org/x/xerrors"
+)
+
+// Template is a provider for rendering templates to disk
+type Template struct {
+	config *config.Template
+	log    hclog.Logger
+}
+
+// NewTemplate creates a new template provider
+func NewTemplate(t *config.Template, l hclog.Logger) *Template {
+	return &Template{t, l}
+}
+
+// Create renders the template and writes it to the destination
+func (t *Template) Create(ctx context.Context) error {
+	t.log.Info("Creating Template", "ref", t.config.Name, "destination", t.config.Destination)
+
+	src := []byte(t.config.Source)
+	if !t.config.IsInline() {
+		d, err := ioutil.ReadFile(t.config.Source)
+		if err != nil {
+			return xerrors.Errorf("Unable to read template %s: %w", t.config.Source, err)
+		}
+
+		src = d
+	}
+
+	out, err := renderTemplate(src, t.config.Name, t.config.Vars)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(t.config.Destination), os.ModePerm)
+	if err != nil {
+		return xerrors.Errorf("Unable to create folder for %s: %w", t.config.Destination, err)
+	}
+
+	err = ioutil.WriteFile(t.config.Destination, []byte(out), 0644)
+	if err != nil {
+		return xerrors.Errorf("Unable to write template %s: %w", t.config.Destination, err)
+	}
+
+	return nil
+}
+
+// Destroy removes the rendered file
+func (t *Template) Destroy() error {
+	t.log.Info("Destroy Template", "ref", t.config.Name, "destination", t.config.Destination)
+
+	err := os.Remove(t.config.Destination)
+	if err != nil && !os.IsNotExist(err) {
+		return xerrors.Errorf("Unable to remove %s: %w", t.config.Destination, err)
+	}
+
+	return nil
+}
+
+// Lookup returns the path of the rendered file
+func (t *Template) Lookup() ([]string, error) {
+	return []string{t.config.Destination}, nil
+}
+
+// renderTemplate renders a template using the HCL template syntax, the
+// template can only reference the given variables
+func renderTemplate(src []byte, name string, vars map[string]string) (string, error) {
+	expr, diag := hclsyntax.ParseTemplate(src, name, hcl.Pos{Line: 1, Column: 1})
+	if diag.HasErrors() {
+		return "", xerrors.Errorf("Unable to parse template %s: %w", name, diag)
+	}
+
+	ctx := &hcl.EvalContext{Variables: map[string]cty.Value{}}
+	for k, v := range vars {
+		ctx.Variables[k] = cty.StringVal(v)
+	}
+
+	v, diag := expr.Value(ctx)
+	if diag.HasErrors() {
+		return "", xerrors.Errorf("Unable to render template %s: %w", name, diag)
+	}
+
+	return v.AsString(), nil
+}
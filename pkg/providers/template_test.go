@@ -0,0 +1,76 @@
+package providers
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupTemplate(t *testing.T) (*config.Template, string, func()) {
+	dir, err := ioutil.TempDir("", "template")
+	assert.NoError(t, err)
+
+	tmpl := config.NewTemplate("consul")
+	tmpl.Source = "datacenter = \"${dc}\"\n"
+	tmpl.Destination = filepath.Join(dir, "config", "consul.hcl")
+	tmpl.Vars = map[string]string{"dc": "dc1"}
+
+	return tmpl, dir, func() { os.RemoveAll(dir) }
+}
+
+func TestTemplateCreateRendersInlineTemplate(t *testing.T) {
+	tmpl, _, cleanup := setupTemplate(t)
+	defer cleanup()
+
+	err := NewTemplate(tmpl, hclog.NewNullLogger()).Create(context.Background())
+	assert.NoError(t, err)
+
+	d, err := ioutil.ReadFile(tmpl.Destination)
+	assert.NoError(t, err)
+	assert.Equal(t, "datacenter = \"dc1\"\n", string(d))
+}
+
+func TestTemplateCreateRendersTemplateFile(t *testing.T) {
+	tmpl, dir, cleanup := setupTemplate(t)
+	defer cleanup()
+
+	tmpl.Source = filepath.Join(dir, "consul.tmpl")
+	err := ioutil.WriteFile(tmpl.Source, []byte("dc=${dc}"), 0644)
+	assert.NoError(t, err)
+
+	err = NewTemplate(tmpl, hclog.NewNullLogger()).Create(context.Background())
+	assert.NoError(t, err)
+
+	d, err := ioutil.ReadFile(tmpl.Destination)
+	assert.NoError(t, err)
+	assert.Equal(t, "dc=dc1", string(d))
+}
+
+func TestTemplateCreateReturnsErrorForUnknownVariable(t *testing.T) {
+	tmpl, _, cleanup := setupTemplate(t)
+	defer cleanup()
+
+	tmpl.Vars = nil
+
+	err := NewTemplate(tmpl, hclog.NewNullLogger()).Create(context.Background())
+	assert.Error(t, err)
+}
+
+func TestTemplateDestroyRemovesFile(t *testing.T) {
+	tmpl, _, cleanup := setupTemplate(t)
+	defer cleanup()
+
+	p := NewTemplate(tmpl, hclog.NewNullLogger())
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	err = p.Destroy()
+	assert.NoError(t, err)
+	assert.NoFileExists(t, tmpl.Destination)
+}
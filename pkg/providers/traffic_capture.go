@@ -0,0 +1,127 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"golang.org/x/xerrors"
+)
+
+const trafficCaptureImage = "mitmproxy/mitmproxy:6.0.2"
+
+// TrafficCapture is a provider for running a recording HTTP proxy
+type TrafficCapture struct {
+	config     *config.TrafficCapture
+	client     clients.ContainerTasks
+	httpClient clients.HTTP
+	log        hclog.Logger
+}
+
+// NewTrafficCapture creates a new TrafficCapture provider
+func NewTrafficCapture(t *config.TrafficCapture, cl clients.ContainerTasks, hc clients.HTTP, l hclog.Logger) *TrafficCapture {
+	return &TrafficCapture{t, cl, hc, l}
+}
+
+// Create the proxy container, captured flows are written to a file in the
+// output folder which can be opened with mitmproxy
+func (t *TrafficCapture) Create(ctx context.Context) error {
+	t.log.Info("Creating Traffic Capture", "ref", t.config.Name, "proxy", t.config.ProxyAddress, "output", t.config.Output)
+
+	// the certificates are generated by mitmproxy in the output folder so
+	// that they can be added to clients
+	err := os.MkdirAll(filepath.Join(t.config.Output, "certs"), os.ModePerm)
+	if err != nil {
+		return xerrors.Errorf("Unable to create output folder: %w", err)
+	}
+
+	cc := config.NewContainer(t.config.Name)
+	t.config.ResourceInfo.AddChild(cc)
+
+	cc.Image = config.Image{Name: trafficCaptureImage}
+	cc.Networks = t.config.Networks
+	cc.Command = t.command()
+	cc.Volumes = []config.Volume{
+		config.Volume{
+			Source:      t.config.Output,
+			Destination: "/captures",
+		},
+	}
+	cc.Ports = []config.Port{
+		config.Port{
+			Local:    "8080",
+			Host:     fmt.Sprintf("%d", t.config.Port),
+			Protocol: "tcp",
+		},
+		config.Port{
+			Local:    "8081",
+			Host:     fmt.Sprintf("%d", t.config.WebPort),
+			Protocol: "tcp",
+		},
+	}
+
+	err = t.client.PullImage(ctx, cc.Image, false)
+	if err != nil {
+		return err
+	}
+
+	_, err = t.client.CreateContainer(cc)
+	if err != nil {
+		return err
+	}
+
+	err = t.httpClient.HealthCheckHTTP(t.config.Address, 60*time.Second)
+	if err != nil {
+		return xerrors.Errorf("Traffic capture proxy did not start: %w", err)
+	}
+
+	return nil
+}
+
+// Destroy the proxy container, the captures are not removed
+func (t *TrafficCapture) Destroy() error {
+	t.log.Info("Destroy Traffic Capture", "ref", t.config.Name)
+
+	ids, err := t.Lookup()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		err := t.client.RemoveContainer(id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Lookup the ID of the proxy container
+func (t *TrafficCapture) Lookup() ([]string, error) {
+	return t.client.FindContainerIDs(t.config.Name, t.config.Type)
+}
+
+func (t *TrafficCapture) command() []string {
+	cmd := []string{
+		"mitmweb",
+		"--no-web-open-browser",
+		"--web-host", "0.0.0.0",
+		"--web-port", "8081",
+		"--listen-port", "8080",
+		"--set", "confdir=/captures/certs",
+		"--set", fmt.Sprintf("save_stream_file=/captures/%s.flow", t.config.Name),
+		"--set", "block_global=false",
+	}
+
+	if t.config.Upstream != "" {
+		cmd = append(cmd, "--mode", fmt.Sprintf("upstream:%s", t.config.Upstream))
+	}
+
+	return cmd
+}
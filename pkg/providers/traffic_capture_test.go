@@ -0,0 +1,73 @@
+package providers
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTrafficCapture(t *testing.T) (*config.TrafficCapture, *mocks.MockContainerTasks, *mocks.MockHTTP, func()) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+
+	tc := config.NewTrafficCapture("debug")
+	tc.Networks = []config.NetworkAttachment{{Name: "network.local"}}
+	tc.Output = filepath.Join(dir, "captures")
+	tc.Address = "http://localhost:8081"
+
+	md := &mocks.MockContainerTasks{}
+	md.On("PullImage", mock.Anything, mock.Anything).Return(nil)
+	md.On("CreateContainer", mock.Anything).Return("abc", nil)
+
+	hc := &mocks.MockHTTP{}
+	hc.On("HealthCheckHTTP", mock.Anything, mock.Anything).Return(nil)
+
+	return tc, md, hc, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+func TestTrafficCaptureCreatesProxyContainer(t *testing.T) {
+	tc, md, hc, cleanup := setupTrafficCapture(t)
+	defer cleanup()
+
+	p := NewTrafficCapture(tc, md, hc, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	assert.DirExists(t, filepath.Join(tc.Output, "certs"))
+
+	cc := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+	assert.Equal(t, "mitmweb", cc.Command[0])
+	assert.Contains(t, cc.Command, "save_stream_file=/captures/debug.flow")
+	assert.NotContains(t, cc.Command, "--mode")
+	assert.Equal(t, tc.Output, cc.Volumes[0].Source)
+	assert.Equal(t, "8080", cc.Ports[0].Host)
+	assert.Equal(t, "8081", cc.Ports[1].Host)
+
+	hc.AssertCalled(t, "HealthCheckHTTP", "http://localhost:8081", mock.Anything)
+}
+
+func TestTrafficCaptureUsesUpstreamProxy(t *testing.T) {
+	tc, md, hc, cleanup := setupTrafficCapture(t)
+	defer cleanup()
+
+	tc.Upstream = "http://proxy.corp:3128"
+	p := NewTrafficCapture(tc, md, hc, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	cc := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+	assert.Contains(t, cc.Command, "upstream:http://proxy.corp:3128")
+}
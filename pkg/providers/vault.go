@@ -0,0 +1,186 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"golang.org/x/xerrors"
+)
+
+const vaultImage = "vault"
+const vaultPort = 8200
+
+// Vault is a provider for running a Vault server in dev mode
+type Vault struct {
+	config     *config.Vault
+	client     clients.ContainerTasks
+	httpClient clients.HTTP
+	log        hclog.Logger
+}
+
+// NewVault creates a new Vault provider
+func NewVault(v *config.Vault, cl clients.ContainerTasks, hc clients.HTTP, l hclog.Logger) *Vault {
+	return &Vault{v, cl, hc, l}
+}
+
+// Create the Vault container and write the policies and secrets
+func (v *Vault) Create(ctx context.Context) error {
+	v.log.Info("Creating Vault", "ref", v.config.Name, "address", v.config.Address)
+
+	cc := config.NewContainer(v.config.Name)
+	v.config.ResourceInfo.AddChild(cc)
+
+	cc.Image = config.Image{Name: fmt.Sprintf("%s:%s", vaultImage, v.config.Version)}
+	cc.Networks = v.config.Networks
+	cc.Command = []string{
+		"server",
+		"-dev",
+		fmt.Sprintf("-dev-root-token-id=%s", v.config.RootToken),
+		fmt.Sprintf("-dev-listen-address=0.0.0.0:%d", vaultPort),
+	}
+	cc.Environment = []config.KV{
+		config.KV{Key: "SKIP_SETCAP", Value: "true"},
+	}
+	cc.Ports = []config.Port{
+		config.Port{
+			Local:    fmt.Sprintf("%d", vaultPort),
+			Host:     fmt.Sprintf("%d", v.config.Port),
+			Protocol: "tcp",
+		},
+	}
+
+	err := v.client.PullImage(ctx, cc.Image, false)
+	if err != nil {
+		return err
+	}
+
+	_, err = v.client.CreateContainer(cc)
+	if err != nil {
+		return err
+	}
+
+	err = v.httpClient.HealthCheckHTTP(fmt.Sprintf("%s/v1/sys/health", v.config.Address), 60*time.Second)
+	if err != nil {
+		return xerrors.Errorf("Vault did not start: %w", err)
+	}
+
+	for _, p := range v.config.Policies {
+		err := v.writePolicy(p)
+		if err != nil {
+			return xerrors.Errorf("Unable to write policy %s: %w", p, err)
+		}
+	}
+
+	for _, s := range v.config.Secrets {
+		err := v.writeSecrets(s)
+		if err != nil {
+			return xerrors.Errorf("Unable to write secrets %s: %w", s, err)
+		}
+	}
+
+	return nil
+}
+
+// Destroy the Vault container, all data in the server is lost
+func (v *Vault) Destroy() error {
+	v.log.Info("Destroy Vault", "ref", v.config.Name)
+
+	ids, err := v.Lookup()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		err := v.client.RemoveContainer(id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Lookup the ID of the Vault container
+func (v *Vault) Lookup() ([]string, error) {
+	return v.client.FindContainerIDs(v.config.Name, v.config.Type)
+}
+
+// writePolicy writes the policy file to Vault, the name of the policy is the
+// name of the file without the extension
+func (v *Vault) writePolicy(file string) error {
+	d, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+
+	body, _ := json.Marshal(map[string]string{"policy": string(d)})
+
+	return v.request(fmt.Sprintf("sys/policies/acl/%s", name), body)
+}
+
+// writeSecrets writes the data in the secrets file to Vault, the file is an
+// object where the keys are paths e.g. secret/data/app
+func (v *Vault) writeSecrets(file string) error {
+	d, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	secrets := map[string]json.RawMessage{}
+	err = json.Unmarshal(d, &secrets)
+	if err != nil {
+		return xerrors.Errorf("Unable to parse secrets file: %w", err)
+	}
+
+	paths := []string{}
+	for p := range secrets {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		err := v.request(strings.TrimPrefix(p, "/"), secrets[p])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (v *Vault) request(path string, body []byte) error {
+	v.log.Debug("Writing to Vault", "ref", v.config.Name, "path", path)
+
+	rq, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/v1/%s", v.config.Address, path), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	rq.Header.Set("X-Vault-Token", v.config.RootToken)
+
+	resp, err := v.httpClient.Do(rq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		rb, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("Vault returned status %d writing %s: %s", resp.StatusCode, path, string(rb))
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupVault(t *testing.T) (*config.Vault, *mocks.MockContainerTasks, *mocks.MockHTTP, func()) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+
+	policy := filepath.Join(dir, "admin.hcl")
+	ioutil.WriteFile(policy, []byte(`path "*" { capabilities = ["read"] }`), os.ModePerm)
+
+	secrets := filepath.Join(dir, "secrets.json")
+	ioutil.WriteFile(secrets, []byte(`{"secret/data/app": {"data": {"password": "abc"}}}`), os.ModePerm)
+
+	v := config.NewVault("dev")
+	v.Address = "http://localhost:8200"
+	v.Policies = []string{policy}
+	v.Secrets = []string{secrets}
+
+	md := &mocks.MockContainerTasks{}
+	md.On("PullImage", mock.Anything, mock.Anything).Return(nil)
+	md.On("CreateContainer", mock.Anything).Return("abc", nil)
+
+	hc := &mocks.MockHTTP{}
+	hc.On("HealthCheckHTTP", mock.Anything, mock.Anything).Return(nil)
+	hc.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusNoContent,
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(""))),
+	}, nil)
+
+	return v, md, hc, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+func TestVaultCreatesContainerInDevMode(t *testing.T) {
+	v, md, hc, cleanup := setupVault(t)
+	defer cleanup()
+
+	p := NewVault(v, md, hc, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	cc := getCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*config.Container)
+	assert.Equal(t, "vault:1.6.1", cc.Image.Name)
+	assert.Contains(t, cc.Command, "-dev-root-token-id=root")
+	assert.Equal(t, "8200", cc.Ports[0].Host)
+
+	hc.AssertCalled(t, "HealthCheckHTTP", "http://localhost:8200/v1/sys/health", mock.Anything)
+}
+
+func TestVaultWritesPoliciesAndSecrets(t *testing.T) {
+	v, md, hc, cleanup := setupVault(t)
+	defer cleanup()
+
+	p := NewVault(v, md, hc, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	calls := getCalls(&hc.Mock, "Do")
+	require.Len(t, calls, 2)
+
+	rq := calls[0].Arguments[0].(*http.Request)
+	assert.Equal(t, "http://localhost:8200/v1/sys/policies/acl/admin", rq.URL.String())
+	assert.Equal(t, "root", rq.Header.Get("X-Vault-Token"))
+
+	rq = calls[1].Arguments[0].(*http.Request)
+	assert.Equal(t, "http://localhost:8200/v1/secret/data/app", rq.URL.String())
+
+	d, _ := ioutil.ReadAll(rq.Body)
+	assert.JSONEq(t, `{"data": {"password": "abc"}}`, string(d))
+}
+
+func TestVaultReturnsErrorWhenWriteFails(t *testing.T) {
+	v, md, hc, cleanup := setupVault(t)
+	defer cleanup()
+
+	removeOn(&hc.Mock, "Do")
+	hc.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusForbidden,
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte("permission denied"))),
+	}, nil)
+
+	p := NewVault(v, md, hc, hclog.NewNullLogger())
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+}
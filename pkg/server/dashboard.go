@@ -0,0 +1,170 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+)
+
+func (s *Server) handleDashboard(rw http.ResponseWriter, r *http.Request) {
+	rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(rw, dashboardHTML)
+}
+
+// dashboardHTML is a self contained single page application which polls the
+// API for the current state of the environment
+var dashboardHTML = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>Shipyard</title>
+  <style>
+    body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 0; background: #f5f6f8; color: #222; }
+    header { background: #1a1f36; color: #fff; padding: 12px 24px; display: flex; justify-content: space-between; align-items: center; }
+    header h1 { font-size: 20px; margin: 0; }
+    main { display: flex; padding: 24px; gap: 24px; }
+    section { background: #fff; border-radius: 4px; box-shadow: 0 1px 3px rgba(0,0,0,0.1); padding: 16px; }
+    #graph { flex: 2; overflow-x: auto; }
+    #logs { flex: 1; min-width: 320px; }
+    .layers { display: flex; gap: 24px; align-items: flex-start; }
+    .layer { display: flex; flex-direction: column; gap: 12px; }
+    .node { border: 2px solid #ccc; border-radius: 4px; padding: 8px 12px; min-width: 180px; cursor: pointer; background: #fff; }
+    .node.selected { box-shadow: 0 0 0 3px #8fa3ff; }
+    .node .type { font-size: 11px; color: #777; }
+    .node .deps { font-size: 11px; color: #999; margin-top: 4px; }
+    .node a { display: block; font-size: 12px; }
+    .applied { border-color: #2da44e; }
+    .failed { border-color: #cf222e; }
+    .pending_creation, .pending_modification, .pending_update { border-color: #bf8700; }
+    .status { font-size: 11px; font-weight: bold; text-transform: uppercase; }
+    pre { background: #111; color: #ddd; padding: 8px; height: 480px; overflow: auto; font-size: 12px; white-space: pre-wrap; }
+    #operation { font-size: 13px; }
+  </style>
+</head>
+<body>
+  <header>
+    <h1 id="title">Shipyard</h1>
+    <span id="operation"></span>
+  </header>
+  <main>
+    <section id="graph"><div class="layers" id="layers">Loading...</div></section>
+    <section id="logs">
+      <h3 id="logs-title">Logs</h3>
+      <pre id="logs-output">Select a resource to view the logs</pre>
+    </section>
+  </main>
+  <script>
+    var selected = null;
+
+    function layers(graph) {
+      // assign each node a depth based on the longest path from a root
+      var depth = {};
+      var deps = {};
+      graph.nodes.forEach(function(n) { deps[n.id] = []; });
+      graph.edges.forEach(function(e) { if (deps[e.to] && deps[e.from]) deps[e.to].push(e.from); });
+
+      function calc(id, seen) {
+        if (depth[id] !== undefined) return depth[id];
+        if (seen[id]) return 0;
+        seen[id] = true;
+        var d = 0;
+        deps[id].forEach(function(p) { d = Math.max(d, calc(p, seen) + 1); });
+        depth[id] = d;
+        return d;
+      }
+
+      var out = [];
+      graph.nodes.forEach(function(n) {
+        var d = calc(n.id, {});
+        out[d] = out[d] || [];
+        out[d].push(n);
+      });
+
+      return { layers: out, deps: deps };
+    }
+
+    function render(graph) {
+      if (graph.blueprint && graph.blueprint.title) {
+        document.getElementById("title").textContent = "Shipyard - " + graph.blueprint.title;
+      }
+
+      var el = document.getElementById("layers");
+      el.innerHTML = "";
+
+      if (graph.nodes.length === 0) {
+        el.textContent = "No resources have been created";
+        return;
+      }
+
+      var l = layers(graph);
+      l.layers.forEach(function(layer) {
+        var col = document.createElement("div");
+        col.className = "layer";
+
+        (layer || []).forEach(function(n) {
+          var div = document.createElement("div");
+          div.className = "node " + n.status + (n.id === selected ? " selected" : "");
+          div.innerHTML = "<div class='type'></div><div class='name'></div><div class='status'></div>";
+          div.querySelector(".type").textContent = n.type;
+          div.querySelector(".name").textContent = n.name;
+          div.querySelector(".status").textContent = (n.status || "").replace("_", " ");
+
+          (n.links || []).forEach(function(link) {
+            var a = document.createElement("a");
+            a.href = link;
+            a.target = "_blank";
+            a.textContent = link;
+            a.onclick = function(e) { e.stopPropagation(); };
+            div.appendChild(a);
+          });
+
+          if (l.deps[n.id].length > 0) {
+            var d = document.createElement("div");
+            d.className = "deps";
+            d.textContent = "depends on: " + l.deps[n.id].join(", ");
+            div.appendChild(d);
+          }
+
+          div.onclick = function() { selected = n.id; refresh(); };
+          col.appendChild(div);
+        });
+
+        el.appendChild(col);
+      });
+    }
+
+    function refreshLogs() {
+      if (!selected) return;
+
+      document.getElementById("logs-title").textContent = "Logs: " + selected;
+      fetch("/v1/resources/" + selected + "/logs").then(function(r) {
+        return r.text();
+      }).then(function(text) {
+        // only show the tail of the logs
+        var lines = text.split("\n");
+        var out = document.getElementById("logs-output");
+        out.textContent = lines.slice(Math.max(lines.length - 200, 0)).join("\n");
+        out.scrollTop = out.scrollHeight;
+      });
+    }
+
+    function refreshOperation() {
+      fetch("/v1/operation").then(function(r) {
+        return r.ok ? r.json() : null;
+      }).then(function(op) {
+        var el = document.getElementById("operation");
+        el.textContent = op ? op.type + ": " + op.status + (op.error ? " (" + op.error + ")" : "") : "";
+      });
+    }
+
+    function refresh() {
+      fetch("/v1/graph").then(function(r) { return r.json(); }).then(render);
+      refreshOperation();
+      refreshLogs();
+    }
+
+    refresh();
+    setInterval(refresh, 2000);
+  </script>
+</body>
+</html>
+`
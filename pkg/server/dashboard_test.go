@@ -0,0 +1,22 @@
+package server
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDashboardReturnsHTML(t *testing.T) {
+	ts, _, _, _, cleanup := setupServer(t, "")
+	defer cleanup()
+
+	resp, err := http.Get(ts.URL + "/")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "text/html")
+
+	d, _ := ioutil.ReadAll(resp.Body)
+	assert.Contains(t, string(d), "/v1/graph")
+}
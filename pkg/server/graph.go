@@ -0,0 +1,119 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+)
+
+// Node is a resource in the dependency graph
+type Node struct {
+	ID     string        `json:"id"`
+	Name   string        `json:"name"`
+	Type   string        `json:"type"`
+	Status config.Status `json:"status"`
+	// Module is the module which defined the resource
+	Module string `json:"module,omitempty"`
+	// Links are the URIs for any ports which are exposed on the local machine
+	Links []string `json:"links,omitempty"`
+}
+
+// Edge is a dependency between two resources, From must be created before To
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Graph is the dependency graph for the current environment
+type Graph struct {
+	Blueprint *config.Blueprint `json:"blueprint,omitempty"`
+	Nodes     []Node            `json:"nodes"`
+	Edges     []Edge            `json:"edges"`
+}
+
+func (s *Server) handleGraph(rw http.ResponseWriter, r *http.Request) {
+	c, err := s.loadState()
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(rw, http.StatusOK, buildGraph(c))
+}
+
+func buildGraph(c *config.Config) *Graph {
+	g := &Graph{Blueprint: c.Blueprint, Nodes: []Node{}, Edges: []Edge{}}
+
+	for _, r := range c.Resources {
+		id := config.ResourceID(r)
+
+		g.Nodes = append(g.Nodes, Node{
+			ID:     id,
+			Name:   r.Info().Name,
+			Type:   string(r.Info().Type),
+			Status: r.Info().Status,
+			Module: r.Info().Module,
+			Links:  resourceLinks(r),
+		})
+
+		// DependsOn can contain duplicates when references are
+		// resolved multiple times
+		seen := map[string]bool{}
+		for _, d := range r.Info().DependsOn {
+			// dependencies are resolved relative to the module of the resource
+			if dr, err := c.FindDependency(r, d); err == nil {
+				d = config.ResourceID(dr)
+			}
+
+			if seen[d] {
+				continue
+			}
+
+			seen[d] = true
+			g.Edges = append(g.Edges, Edge{From: d, To: id})
+		}
+	}
+
+	return g
+}
+
+// resourceLinks returns the browser links for any ports the resource
+// exposes on the local machine
+func resourceLinks(r config.Resource) []string {
+	ports := []config.Port{}
+
+	switch v := r.(type) {
+	case *config.Container:
+		ports = v.Ports
+	case *config.Ingress:
+		ports = v.Ports
+	case *config.ContainerIngress:
+		ports = v.Ports
+	case *config.NomadIngress:
+		ports = v.Ports
+	case *config.K8sIngress:
+		ports = v.Ports
+	case *config.Docs:
+		return []string{linkURI(r, strconv.Itoa(v.Port), "")}
+	}
+
+	links := []string{}
+	for _, p := range ports {
+		if p.Host != "" {
+			links = append(links, linkURI(r, p.Host, p.OpenInBrowser))
+		}
+	}
+
+	return links
+}
+
+func linkURI(r config.Resource, port, path string) string {
+	t := r.Info().Type
+	if t == config.TypeNomadIngress || t == config.TypeContainerIngress || t == config.TypeK8sIngress {
+		t = config.TypeIngress
+	}
+
+	return fmt.Sprintf("http://%s.%s.shipyard.run:%s%s", r.Info().Name, t, port, path)
+}
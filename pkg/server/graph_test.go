@@ -0,0 +1,79 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphReturnsNodesAndEdges(t *testing.T) {
+	ts, _, _, _, cleanup := setupServer(t, testState)
+	defer cleanup()
+
+	resp, err := http.Get(ts.URL + "/v1/graph")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	g := &Graph{}
+	err = json.NewDecoder(resp.Body).Decode(g)
+	assert.NoError(t, err)
+	assert.Len(t, g.Nodes, 2)
+	assert.Equal(t, "container.consul", g.Nodes[0].ID)
+	assert.Equal(t, config.Applied, g.Nodes[0].Status)
+}
+
+func TestGraphWithNoStateReturnsEmptyGraph(t *testing.T) {
+	ts, _, _, _, cleanup := setupServer(t, "")
+	defer cleanup()
+
+	resp, err := http.Get(ts.URL + "/v1/graph")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	g := &Graph{}
+	err = json.NewDecoder(resp.Body).Decode(g)
+	assert.NoError(t, err)
+	assert.Len(t, g.Nodes, 0)
+}
+
+func TestBuildGraphRemovesDuplicateEdges(t *testing.T) {
+	c := config.New()
+	n := config.NewNetwork("cloud")
+	ct := config.NewContainer("consul")
+	ct.DependsOn = []string{"network.cloud", "network.cloud"}
+
+	c.AddResource(n)
+	c.AddResource(ct)
+
+	g := buildGraph(c)
+	assert.Len(t, g.Nodes, 2)
+	assert.Len(t, g.Edges, 1)
+	assert.Equal(t, Edge{From: "network.cloud", To: "container.consul"}, g.Edges[0])
+}
+
+func TestResourceLinksReturnsHostPorts(t *testing.T) {
+	ct := config.NewContainer("consul")
+	ct.Ports = []config.Port{
+		config.Port{Local: "8500", Host: "18500", OpenInBrowser: "/ui"},
+		config.Port{Local: "8300"},
+	}
+
+	assert.Equal(t, []string{"http://consul.container.shipyard.run:18500/ui"}, resourceLinks(ct))
+}
+
+func TestResourceLinksUsesIngressTypeForIngressVariants(t *testing.T) {
+	i := config.NewContainerIngress("web")
+	i.Ports = []config.Port{config.Port{Local: "80", Host: "8080"}}
+
+	assert.Equal(t, []string{"http://web.ingress.shipyard.run:8080"}, resourceLinks(i))
+}
+
+func TestResourceLinksReturnsDocsPort(t *testing.T) {
+	d := config.NewDocs("docs")
+	d.Port = 8080
+
+	assert.Equal(t, []string{"http://docs.docs.shipyard.run:8080"}, resourceLinks(d))
+}
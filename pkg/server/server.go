@@ -0,0 +1,398 @@
+package server
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/gorilla/mux"
+	"github.com/hashicorp/go-hclog"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/metrics"
+	"github.com/shipyard-run/shipyard/pkg/shipyard"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+)
+
+// OperationApply is the type for an operation which creates an environment
+const OperationApply = "apply"
+
+// OperationDestroy is the type for an operation which destroys an environment
+const OperationDestroy = "destroy"
+
+// OperationRunning means the operation has not yet completed
+const OperationRunning = "running"
+
+// OperationComplete means the operation completed successfully
+const OperationComplete = "complete"
+
+// OperationFailed means the operation completed with an error
+const OperationFailed = "failed"
+
+// Operation describes a long running task which has been submitted
+// to the server
+type Operation struct {
+	Type        string     `json:"type"`
+	Source      string     `json:"source,omitempty"`
+	Status      string     `json:"status"`
+	Error       string     `json:"error,omitempty"`
+	StartedAt   time.Time  `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// ApplyRequest is the request body for creating an environment
+type ApplyRequest struct {
+	// Source is the local path or remote URI of the blueprint to apply
+	Source string `json:"source"`
+	// Variables overrides the default values of variables defined in the blueprint
+	Variables map[string]string `json:"variables,omitempty"`
+}
+
+// Server exposes the Shipyard engine over a HTTP API so that environments
+// can be created, inspected, and destroyed by remote clients
+type Server struct {
+	engine shipyard.Engine
+	getter clients.Getter
+	tasks  clients.ContainerTasks
+	log    hclog.Logger
+
+	metrics bool
+
+	// token must be sent by requests which apply or destroy an environment
+	token string
+
+	// the engine is not safe for concurrent use, only one
+	// operation can be executed at any time
+	mutex     sync.Mutex
+	operation *Operation
+}
+
+// New creates a new Server
+func New(e shipyard.Engine, g clients.Getter, ct clients.ContainerTasks, l hclog.Logger) *Server {
+	return &Server{engine: e, getter: g, tasks: ct, log: l}
+}
+
+// EnableMetrics exposes Prometheus metrics on the /metrics endpoint
+func (s *Server) EnableMetrics() {
+	s.metrics = true
+}
+
+// RequireToken requires requests which apply or destroy an environment to
+// set the header Authorization: Bearer [token]
+func (s *Server) RequireToken(token string) {
+	s.token = token
+}
+
+// WriteToken generates a random token and writes it to path, only the
+// current user can read the file so that clients running as the user can
+// authenticate with the server
+func WriteToken(path string) (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", fmt.Errorf("Unable to generate token: %s", err)
+	}
+
+	token := hex.EncodeToString(b)
+
+	// WriteFile does not change the permissions of an existing file
+	os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	os.Remove(path)
+
+	err = ioutil.WriteFile(path, []byte(token), 0600)
+	if err != nil {
+		return "", fmt.Errorf("Unable to write token to %s: %s", path, err)
+	}
+
+	return token, nil
+}
+
+// ListenAndServe starts the API server on the given address
+func (s *Server) ListenAndServe(addr string) error {
+	s.log.Info("Starting API server", "address", addr)
+
+	return http.ListenAndServe(addr, s.Handler())
+}
+
+// Handler returns the http.Handler containing all the API routes
+func (s *Server) Handler() http.Handler {
+	r := mux.NewRouter()
+
+	r.HandleFunc("/", s.handleDashboard).Methods(http.MethodGet)
+	r.HandleFunc("/v1/health", s.handleHealth).Methods(http.MethodGet)
+	r.HandleFunc("/v1/apply", s.protect(s.handleApply)).Methods(http.MethodPost)
+	r.HandleFunc("/v1/destroy", s.protect(s.handleDestroy)).Methods(http.MethodPost)
+	r.HandleFunc("/v1/operation", s.handleOperation).Methods(http.MethodGet)
+	r.HandleFunc("/v1/status", s.handleStatus).Methods(http.MethodGet)
+	r.HandleFunc("/v1/graph", s.handleGraph).Methods(http.MethodGet)
+	r.HandleFunc("/v1/resources/{resource}", s.handleResource).Methods(http.MethodGet)
+	r.HandleFunc("/v1/resources/{resource}/logs", s.handleLogs).Methods(http.MethodGet)
+
+	if s.metrics {
+		r.Handle("/metrics", metrics.Handler()).Methods(http.MethodGet)
+	}
+
+	return r
+}
+
+// protect only allows JSON requests from the same origin which contain the
+// token, browsers send simple cross origin POST requests to localhost without
+// asking so any web page could otherwise apply or destroy the environment
+func (s *Server) protect(h http.HandlerFunc) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		mt, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil || mt != "application/json" {
+			http.Error(rw, "Content-Type must be application/json", http.StatusUnsupportedMediaType)
+			return
+		}
+
+		if o := r.Header.Get("Origin"); o != "" {
+			u, err := url.Parse(o)
+			if err != nil || u.Host != r.Host {
+				http.Error(rw, "Cross origin requests are not allowed", http.StatusForbidden)
+				return
+			}
+		}
+
+		if s.token != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+s.token)) != 1 {
+			http.Error(rw, "Request must contain a valid token", http.StatusUnauthorized)
+			return
+		}
+
+		h(rw, r)
+	}
+}
+
+func (s *Server) handleHealth(rw http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(rw, "OK")
+}
+
+func (s *Server) handleApply(rw http.ResponseWriter, r *http.Request) {
+	req := &ApplyRequest{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil || req.Source == "" {
+		http.Error(rw, "Request body must contain the blueprint source", http.StatusBadRequest)
+		return
+	}
+
+	op, ok := s.startOperation(OperationApply, req.Source)
+	if !ok {
+		http.Error(rw, "An operation is already running", http.StatusConflict)
+		return
+	}
+
+	go func() {
+		src, err := s.resolveSource(req.Source)
+		if err == nil {
+			_, err = s.engine.ApplyWithVariables(src, req.Variables)
+		}
+
+		s.completeOperation(err)
+	}()
+
+	writeJSON(rw, http.StatusAccepted, op)
+}
+
+func (s *Server) handleDestroy(rw http.ResponseWriter, r *http.Request) {
+	op, ok := s.startOperation(OperationDestroy, "")
+	if !ok {
+		http.Error(rw, "An operation is already running", http.StatusConflict)
+		return
+	}
+
+	go func() {
+		s.completeOperation(s.engine.Destroy("", true))
+	}()
+
+	writeJSON(rw, http.StatusAccepted, op)
+}
+
+func (s *Server) handleOperation(rw http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.operation == nil {
+		http.Error(rw, "No operations have been submitted", http.StatusNotFound)
+		return
+	}
+
+	writeJSON(rw, http.StatusOK, s.operation)
+}
+
+func (s *Server) handleStatus(rw http.ResponseWriter, r *http.Request) {
+	c, err := s.loadState()
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(rw, http.StatusOK, c)
+}
+
+func (s *Server) handleResource(rw http.ResponseWriter, r *http.Request) {
+	res, ok := s.findResource(rw, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(rw, http.StatusOK, res)
+}
+
+func (s *Server) handleLogs(rw http.ResponseWriter, r *http.Request) {
+	res, ok := s.findResource(rw, r)
+	if !ok {
+		return
+	}
+
+	ids, err := s.tasks.FindContainerIDs(containerName(res), res.Info().Type)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if len(ids) == 0 {
+		http.Error(rw, "No running containers found for resource", http.StatusNotFound)
+		return
+	}
+
+	logs, err := s.tasks.ContainerLogs(ids[0], true, true)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer logs.Close()
+
+	rw.Header().Set("Content-Type", "text/plain")
+	fw := &flushWriter{rw}
+
+	// the Docker log stream multiplexes stdout and stderr, remove the headers
+	// before writing to the client
+	_, err = stdcopy.StdCopy(fw, fw, logs)
+	if err != nil && err != io.EOF {
+		s.log.Error("Unable to stream logs", "resource", mux.Vars(r)["resource"], "error", err)
+	}
+}
+
+// findResource returns the resource named in the request, if the resource
+// does not exist an error is written to the response
+func (s *Server) findResource(rw http.ResponseWriter, r *http.Request) (config.Resource, bool) {
+	c, err := s.loadState()
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+
+	res, err := c.FindResource(mux.Vars(r)["resource"])
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusNotFound)
+		return nil, false
+	}
+
+	return res, true
+}
+
+// startOperation records a new operation, if an operation is already running
+// the function returns false
+func (s *Server) startOperation(t, source string) (Operation, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.operation != nil && s.operation.Status == OperationRunning {
+		return Operation{}, false
+	}
+
+	s.operation = &Operation{Type: t, Source: source, Status: OperationRunning, StartedAt: time.Now()}
+
+	return *s.operation, true
+}
+
+func (s *Server) completeOperation(err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	s.operation.CompletedAt = &now
+	s.operation.Status = OperationComplete
+
+	if err != nil {
+		s.log.Error("Operation failed", "type", s.operation.Type, "error", err)
+
+		s.operation.Status = OperationFailed
+		s.operation.Error = err.Error()
+	}
+}
+
+// resolveSource fetches remote blueprints and returns the local
+// folder which contains the blueprint files
+func (s *Server) resolveSource(src string) (string, error) {
+	if utils.IsLocalFolder(src) || utils.IsHCLFile(src) {
+		return src, nil
+	}
+
+	dst := utils.GetBlueprintLocalFolder(src)
+	err := s.getter.Get(src, dst)
+	if err != nil {
+		return "", fmt.Errorf("Unable to retrieve blueprint: %s", err)
+	}
+
+	return dst, nil
+}
+
+func (s *Server) loadState() (*config.Config, error) {
+	c := config.New()
+	err := c.FromJSON(utils.StatePath())
+	if err != nil && err != config.StateNotFoundError {
+		return nil, err
+	}
+
+	if c.Resources == nil {
+		c.Resources = []config.Resource{}
+	}
+
+	return c, nil
+}
+
+// containerName returns the name of the container for a resource, cluster
+// resources are prefixed with the name of the server node
+func containerName(r config.Resource) string {
+	switch r.Info().Type {
+	case config.TypeK8sCluster, config.TypeNomadCluster:
+		return fmt.Sprintf("server.%s", r.Info().Name)
+	}
+
+	return r.Info().Name
+}
+
+func writeJSON(rw http.ResponseWriter, status int, v interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	// the state contains sensitive values which must not be returned
+	json.NewEncoder(config.NewRedactWriter(rw)).Encode(v)
+}
+
+// flushWriter flushes the response after every write so that
+// logs are streamed to the client as they are read
+type flushWriter struct {
+	rw http.ResponseWriter
+}
+
+func (f *flushWriter) Write(p []byte) (int, error) {
+	n, err := f.rw.Write(p)
+	if fl, ok := f.rw.(http.Flusher); ok {
+		fl.Flush()
+	}
+
+	return n, err
+}
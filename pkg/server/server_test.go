@@ -0,0 +1,371 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	clientmocks "github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/shipyard/mocks"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupServer(t *testing.T, state string) (*httptest.Server, *mocks.Engine, *clientmocks.Getter, *clientmocks.MockContainerTasks, func()) {
+	home := os.Getenv("HOME")
+	dir, err := ioutil.TempDir("", "")
+	assert.NoError(t, err)
+	os.Setenv("HOME", dir)
+
+	if state != "" {
+		os.MkdirAll(utils.StateDir(), os.ModePerm)
+		ioutil.WriteFile(utils.StatePath(), []byte(state), os.ModePerm)
+	}
+
+	me := &mocks.Engine{}
+	me.On("ApplyWithVariables", mock.Anything, mock.Anything).Return(nil, nil)
+	me.On("Destroy", mock.Anything, mock.Anything).Return(nil)
+
+	mg := &clientmocks.Getter{}
+	mg.On("Get", mock.Anything, mock.Anything).Return(nil)
+
+	mt := &clientmocks.MockContainerTasks{}
+
+	s := New(me, mg, mt, hclog.NewNullLogger())
+	ts := httptest.NewServer(s.Handler())
+
+	return ts, me, mg, mt, func() {
+		ts.Close()
+		os.Setenv("HOME", home)
+		os.RemoveAll(dir)
+	}
+}
+
+func waitForOperation(t *testing.T, ts *httptest.Server) *Operation {
+	op := &Operation{}
+
+	assert.Eventually(t, func() bool {
+		resp, err := http.Get(ts.URL + "/v1/operation")
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+
+		json.NewDecoder(resp.Body).Decode(op)
+		return op.Status != OperationRunning
+	}, 1*time.Second, 10*time.Millisecond)
+
+	return op
+}
+
+func TestHealthReturnsOK(t *testing.T) {
+	ts, _, _, _, cleanup := setupServer(t, "")
+	defer cleanup()
+
+	resp, err := http.Get(ts.URL + "/v1/health")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestApplyWithNoSourceReturnsBadRequest(t *testing.T) {
+	ts, _, _, _, cleanup := setupServer(t, "")
+	defer cleanup()
+
+	resp, err := http.Post(ts.URL+"/v1/apply", "application/json", bytes.NewBufferString(`{}`))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestApplyWithLocalSourceCallsEngine(t *testing.T) {
+	ts, me, mg, _, cleanup := setupServer(t, "")
+	defer cleanup()
+
+	resp, err := http.Post(ts.URL+"/v1/apply", "application/json", bytes.NewBufferString(`{"source": "/tmp"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	op := waitForOperation(t, ts)
+	assert.Equal(t, OperationComplete, op.Status)
+	assert.Equal(t, OperationApply, op.Type)
+
+	me.AssertCalled(t, "ApplyWithVariables", "/tmp", map[string]string(nil))
+	mg.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+}
+
+func TestApplyWithVariablesPassesVariablesToEngine(t *testing.T) {
+	ts, me, _, _, cleanup := setupServer(t, "")
+	defer cleanup()
+
+	resp, err := http.Post(ts.URL+"/v1/apply", "application/json", bytes.NewBufferString(`{"source": "/tmp", "variables": {"version": "1.7.2"}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	waitForOperation(t, ts)
+
+	me.AssertCalled(t, "ApplyWithVariables", "/tmp", map[string]string{"version": "1.7.2"})
+}
+
+func TestApplyWithRemoteSourceFetchesBlueprint(t *testing.T) {
+	ts, me, mg, _, cleanup := setupServer(t, "")
+	defer cleanup()
+
+	src := "github.com/shipyard-run/blueprints//vault-k8s"
+	resp, err := http.Post(ts.URL+"/v1/apply", "application/json", bytes.NewBufferString(fmt.Sprintf(`{"source": "%s"}`, src)))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	waitForOperation(t, ts)
+
+	mg.AssertCalled(t, "Get", src, utils.GetBlueprintLocalFolder(src))
+	me.AssertCalled(t, "ApplyWithVariables", utils.GetBlueprintLocalFolder(src), map[string]string(nil))
+}
+
+func TestApplyEngineErrorSetsOperationFailed(t *testing.T) {
+	ts, me, _, _, cleanup := setupServer(t, "")
+	defer cleanup()
+
+	me.ExpectedCalls = nil
+	me.On("ApplyWithVariables", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("boom"))
+
+	_, err := http.Post(ts.URL+"/v1/apply", "application/json", bytes.NewBufferString(`{"source": "/tmp"}`))
+	assert.NoError(t, err)
+
+	op := waitForOperation(t, ts)
+	assert.Equal(t, OperationFailed, op.Status)
+	assert.Equal(t, "boom", op.Error)
+}
+
+func TestDestroyCallsEngine(t *testing.T) {
+	ts, me, _, _, cleanup := setupServer(t, "")
+	defer cleanup()
+
+	resp, err := http.Post(ts.URL+"/v1/destroy", "application/json", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	op := waitForOperation(t, ts)
+	assert.Equal(t, OperationDestroy, op.Type)
+
+	me.AssertCalled(t, "Destroy", "", true)
+}
+
+func TestApplyWithoutJSONContentTypeReturnsError(t *testing.T) {
+	ts, me, _, _, cleanup := setupServer(t, "")
+	defer cleanup()
+
+	// browsers can send form posts cross origin without a preflight
+	resp, err := http.Post(ts.URL+"/v1/apply", "text/plain", bytes.NewBufferString(`{"source": "/tmp"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+
+	me.AssertNotCalled(t, "ApplyWithVariables", mock.Anything, mock.Anything)
+}
+
+func TestDestroyFromForeignOriginReturnsForbidden(t *testing.T) {
+	ts, me, _, _, cleanup := setupServer(t, "")
+	defer cleanup()
+
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+"/v1/destroy", nil)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Origin", "http://evil.example.com")
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	me.AssertNotCalled(t, "Destroy", mock.Anything, mock.Anything)
+}
+
+func TestDestroyFromSameOriginIsAccepted(t *testing.T) {
+	ts, _, _, _, cleanup := setupServer(t, "")
+	defer cleanup()
+
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+"/v1/destroy", nil)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Origin", ts.URL)
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	waitForOperation(t, ts)
+}
+
+func TestDestroyWithTokenRequiresToken(t *testing.T) {
+	me := &mocks.Engine{}
+	me.On("Destroy", mock.Anything, mock.Anything).Return(nil)
+
+	s := New(me, &clientmocks.Getter{}, &clientmocks.MockContainerTasks{}, hclog.NewNullLogger())
+	s.RequireToken("abc123")
+
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+"/v1/destroy", nil)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer nope")
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	req.Header.Set("Authorization", "Bearer abc123")
+
+	resp, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	waitForOperation(t, ts)
+}
+
+func TestWriteTokenWritesFileReadableByUser(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "shipyard", "daemon.token")
+
+	token, err := WriteToken(path)
+	assert.NoError(t, err)
+	assert.Len(t, token, 64)
+
+	d, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, token, string(d))
+
+	fi, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+
+	token2, err := WriteToken(path)
+	assert.NoError(t, err)
+	assert.NotEqual(t, token, token2)
+}
+
+func TestOperationWithNoOperationsReturnsNotFound(t *testing.T) {
+	ts, _, _, _, cleanup := setupServer(t, "")
+	defer cleanup()
+
+	resp, err := http.Get(ts.URL + "/v1/operation")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestStatusReturnsResources(t *testing.T) {
+	ts, _, _, _, cleanup := setupServer(t, testState)
+	defer cleanup()
+
+	resp, err := http.Get(ts.URL + "/v1/status")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	c := config.New()
+	err = json.NewDecoder(resp.Body).Decode(c)
+	assert.NoError(t, err)
+	assert.Len(t, c.Resources, 2)
+}
+
+func TestResourceReturnsResource(t *testing.T) {
+	ts, _, _, _, cleanup := setupServer(t, testState)
+	defer cleanup()
+
+	resp, err := http.Get(ts.URL + "/v1/resources/container.consul")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestResourceNotFoundReturnsNotFound(t *testing.T) {
+	ts, _, _, _, cleanup := setupServer(t, testState)
+	defer cleanup()
+
+	resp, err := http.Get(ts.URL + "/v1/resources/container.nope")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestLogsStreamsContainerLogs(t *testing.T) {
+	ts, _, _, mt, cleanup := setupServer(t, testState)
+	defer cleanup()
+
+	// build a multiplexed docker log stream, 8 byte header followed by the payload
+	payload := []byte("hello world\n")
+	stream := append([]byte{1, 0, 0, 0, 0, 0, 0, byte(len(payload))}, payload...)
+
+	mt.On("FindContainerIDs", "consul", config.TypeContainer).Return([]string{"abc"}, nil)
+	mt.On("ContainerLogs", "abc", true, true).Return(ioutil.NopCloser(bytes.NewReader(stream)), nil)
+
+	resp, err := http.Get(ts.URL + "/v1/resources/container.consul/logs")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	d, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(t, "hello world\n", string(d))
+}
+
+func TestLogsForClusterUsesServerName(t *testing.T) {
+	ts, _, _, mt, cleanup := setupServer(t, testState)
+	defer cleanup()
+
+	mt.On("FindContainerIDs", "server.k3s", config.TypeK8sCluster).Return(nil, nil)
+
+	resp, err := http.Get(ts.URL + "/v1/resources/k8s_cluster.k3s/logs")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	mt.AssertCalled(t, "FindContainerIDs", "server.k3s", config.TypeK8sCluster)
+}
+
+func TestMetricsNotEnabledReturnsNotFound(t *testing.T) {
+	ts, _, _, _, cleanup := setupServer(t, "")
+	defer cleanup()
+
+	resp, err := http.Get(ts.URL + "/metrics")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestMetricsEnabledReturnsMetrics(t *testing.T) {
+	_, _, _, _, cleanup := setupServer(t, testState)
+	defer cleanup()
+
+	s := New(&mocks.Engine{}, &clientmocks.Getter{}, &clientmocks.MockContainerTasks{}, hclog.NewNullLogger())
+	s.EnableMetrics()
+
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/metrics")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	d, _ := ioutil.ReadAll(resp.Body)
+	assert.Contains(t, string(d), `shipyard_resources{status="applied",type="container"} 1`)
+}
+
+var testState = `
+{
+  "blueprint": null,
+  "resources": [
+	{
+      "name": "consul",
+      "status": "applied",
+      "type": "container"
+	},
+	{
+      "name": "k3s",
+      "status": "applied",
+      "type": "k8s_cluster"
+	}
+  ]
+}
+`
@@ -0,0 +1,159 @@
+package shipyard
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/shipyard-run/shipyard/pkg/clients"
+	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"golang.org/x/xerrors"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// registerDataSources registers the data sources which can be used in data
+// blocks to query the runtime
+func registerDataSources(cl *Clients) {
+	config.RegisterDataSource("docker_image", dockerImageDataSource(cl.Docker))
+	config.RegisterDataSource("docker_container", dockerContainerDataSource(cl.Docker))
+	config.RegisterDataSource("k8s_service", k8sServiceDataSource(cl.Kubernetes))
+}
+
+// dockerImageDataSource returns details of an image in the local Docker cache
+//
+//	data "docker_image" "consul" {
+//	  name = "consul:1.7.2"
+//	}
+func dockerImageDataSource(d clients.Docker) config.DataSourceFunc {
+	return func(attrs map[string]string) (map[string]interface{}, error) {
+		name := attrs["name"]
+		if name == "" {
+			return nil, fmt.Errorf("attribute name is required")
+		}
+
+		args := filters.NewArgs()
+		args.Add("reference", name)
+
+		sum, err := d.ImageList(context.Background(), types.ImageListOptions{Filters: args})
+		if err != nil {
+			return nil, xerrors.Errorf("unable to list images in local Docker cache: %w", err)
+		}
+
+		out := map[string]interface{}{"name": name, "present": false, "id": ""}
+		if len(sum) > 0 {
+			out["present"] = true
+			out["id"] = sum[0].ID
+		}
+
+		return out, nil
+	}
+}
+
+// dockerContainerDataSource returns details of a running container created
+// by a container resource
+//
+//	data "docker_container" "consul" {
+//	  name = "consul"
+//	}
+func dockerContainerDataSource(d clients.Docker) config.DataSourceFunc {
+	return func(attrs map[string]string) (map[string]interface{}, error) {
+		name := attrs["name"]
+		if name == "" {
+			return nil, fmt.Errorf("attribute name is required")
+		}
+
+		args := filters.NewArgs()
+		args.Add("name", fmt.Sprintf("^/%s$", utils.FQDN(name, string(config.TypeContainer))))
+
+		cl, err := d.ContainerList(context.Background(), types.ContainerListOptions{Filters: args})
+		if err != nil {
+			return nil, xerrors.Errorf("unable to list containers: %w", err)
+		}
+
+		out := map[string]interface{}{"name": name, "present": false, "id": "", "ip_address": "", "networks": map[string]string{}}
+		if len(cl) == 0 {
+			return out, nil
+		}
+
+		out["present"] = true
+		out["id"] = cl[0].ID
+
+		if cl[0].NetworkSettings != nil {
+			networks := map[string]string{}
+			for n, s := range cl[0].NetworkSettings.Networks {
+				networks[n] = s.IPAddress
+
+				if out["ip_address"] == "" {
+					out["ip_address"] = s.IPAddress
+				}
+			}
+
+			out["networks"] = networks
+		}
+
+		return out, nil
+	}
+}
+
+// k8sServiceDataSource returns details of a service running in a Kubernetes
+// cluster created by a k8s_cluster resource
+//
+//	data "k8s_service" "consul" {
+//	  cluster   = "k3s"
+//	  name      = "consul-server"
+//	  namespace = "default"
+//	}
+func k8sServiceDataSource(kc clients.Kubernetes) config.DataSourceFunc {
+	return func(attrs map[string]string) (map[string]interface{}, error) {
+		name := attrs["name"]
+		if name == "" || attrs["cluster"] == "" {
+			return nil, fmt.Errorf("attributes cluster and name are required")
+		}
+
+		namespace := attrs["namespace"]
+		if namespace == "" {
+			namespace = "default"
+		}
+
+		out := map[string]interface{}{"name": name, "namespace": namespace, "present": false, "cluster_ip": "", "ports": []interface{}{}}
+
+		// if the cluster has not been created there is no config
+		_, kubeConfig, _ := utils.CreateKubeConfigPath(attrs["cluster"])
+		if _, err := os.Stat(kubeConfig); err != nil {
+			return out, nil
+		}
+
+		err := kc.SetConfig(kubeConfig)
+		if err != nil {
+			return nil, xerrors.Errorf("unable to create Kubernetes client for cluster %s: %w", attrs["cluster"], err)
+		}
+
+		s, err := kc.GetService(name, namespace)
+		if errors.IsNotFound(err) {
+			return out, nil
+		}
+
+		if err != nil {
+			return nil, xerrors.Errorf("unable to get service %s: %w", name, err)
+		}
+
+		ports := []interface{}{}
+		for _, p := range s.Spec.Ports {
+			ports = append(ports, map[string]interface{}{
+				"name":        p.Name,
+				"port":        p.Port,
+				"target_port": p.TargetPort.String(),
+				"node_port":   p.NodePort,
+			})
+		}
+
+		out["present"] = true
+		out["cluster_ip"] = s.Spec.ClusterIP
+		out["ports"] = ports
+
+		return out, nil
+	}
+}
@@ -0,0 +1,135 @@
+// +build !race
+
+package shipyard
+
+import (
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/network"
+	"github.com/shipyard-run/shipyard/pkg/clients/mocks"
+	"github.com/shipyard-run/shipyard/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestDockerImageDataSourceReturnsPresentWhenImageExists(t *testing.T) {
+	md := &mocks.MockDocker{}
+	md.On("ImageList", mock.Anything, mock.Anything).Return([]types.ImageSummary{{ID: "sha256:abc"}}, nil)
+
+	out, err := dockerImageDataSource(md)(map[string]string{"name": "consul:1.7.2"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, true, out["present"])
+	assert.Equal(t, "sha256:abc", out["id"])
+
+	opts := md.Calls[0].Arguments[1].(types.ImageListOptions)
+	assert.Equal(t, "consul:1.7.2", opts.Filters.Get("reference")[0])
+}
+
+func TestDockerImageDataSourceReturnsNotPresentWhenNoImage(t *testing.T) {
+	md := &mocks.MockDocker{}
+	md.On("ImageList", mock.Anything, mock.Anything).Return(nil, nil)
+
+	out, err := dockerImageDataSource(md)(map[string]string{"name": "consul:1.7.2"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, false, out["present"])
+}
+
+func TestDockerImageDataSourceReturnsErrorWhenListFails(t *testing.T) {
+	md := &mocks.MockDocker{}
+	md.On("ImageList", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("boom"))
+
+	_, err := dockerImageDataSource(md)(map[string]string{"name": "consul:1.7.2"})
+	assert.Error(t, err)
+}
+
+func TestDockerContainerDataSourceReturnsIPAddress(t *testing.T) {
+	md := &mocks.MockDocker{}
+	md.On("ContainerList", mock.Anything, mock.Anything).Return(
+		[]types.Container{
+			{
+				ID: "abc",
+				NetworkSettings: &types.SummaryNetworkSettings{
+					Networks: map[string]*network.EndpointSettings{"cloud": {IPAddress: "10.5.0.2"}},
+				},
+			},
+		},
+		nil,
+	)
+
+	out, err := dockerContainerDataSource(md)(map[string]string{"name": "consul"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, true, out["present"])
+	assert.Equal(t, "10.5.0.2", out["ip_address"])
+	assert.Equal(t, map[string]string{"cloud": "10.5.0.2"}, out["networks"])
+
+	opts := md.Calls[0].Arguments[1].(types.ContainerListOptions)
+	assert.Equal(t, "^/consul.container.shipyard.run$", opts.Filters.Get("name")[0])
+}
+
+func TestK8sServiceDataSourceReturnsNotPresentWhenNoCluster(t *testing.T) {
+	defer setupState("")()
+
+	mk := &mocks.MockKubernetes{}
+
+	out, err := k8sServiceDataSource(mk)(map[string]string{"cluster": "k3s", "name": "consul"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, false, out["present"])
+	mk.AssertNotCalled(t, "SetConfig", mock.Anything)
+}
+
+func setupKubeConfig(t *testing.T) string {
+	_, kc, _ := utils.CreateKubeConfigPath("k3s")
+	err := ioutil.WriteFile(kc, []byte(""), 0644)
+	assert.NoError(t, err)
+
+	return kc
+}
+
+func TestK8sServiceDataSourceReturnsService(t *testing.T) {
+	defer setupState("")()
+	kc := setupKubeConfig(t)
+
+	mk := &mocks.MockKubernetes{}
+	mk.On("SetConfig", kc).Return(nil)
+	mk.On("GetService", "consul", "default").Return(
+		&v1.Service{
+			Spec: v1.ServiceSpec{
+				ClusterIP: "10.43.0.10",
+				Ports:     []v1.ServicePort{{Name: "http", Port: 8500, TargetPort: intstr.FromInt(8500)}},
+			},
+		},
+		nil,
+	)
+
+	out, err := k8sServiceDataSource(mk)(map[string]string{"cluster": "k3s", "name": "consul"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, true, out["present"])
+	assert.Equal(t, "10.43.0.10", out["cluster_ip"])
+	assert.Len(t, out["ports"], 1)
+}
+
+func TestK8sServiceDataSourceReturnsNotPresentWhenNoService(t *testing.T) {
+	defer setupState("")()
+	kc := setupKubeConfig(t)
+
+	mk := &mocks.MockKubernetes{}
+	mk.On("SetConfig", kc).Return(nil)
+	mk.On("GetService", "consul", "consul").Return(nil, errors.NewNotFound(schema.GroupResource{Resource: "services"}, "consul"))
+
+	out, err := k8sServiceDataSource(mk)(map[string]string{"cluster": "k3s", "name": "consul", "namespace": "consul"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, false, out["present"])
+}
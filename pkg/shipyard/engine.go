@@ -4,9 +4,11 @@ import (
 
 	// "fmt"
 
+	"context"
 	"fmt"
 	"log"
 	"os"
+	"path"
 	"sync"
 	"time"
 
@@ -18,8 +20,12 @@ import (
 	// "github.com/mitchellh/mapstructure"
 	"github.com/shipyard-run/shipyard/pkg/clients"
 	"github.com/shipyard-run/shipyard/pkg/config"
+	"github.com/shipyard-run/shipyard/pkg/metrics"
 	"github.com/shipyard-run/shipyard/pkg/providers"
+	"github.com/shipyard-run/shipyard/pkg/tracing"
 	"github.com/shipyard-run/shipyard/pkg/utils"
+	"go.opentelemetry.io/otel/api/kv"
+	"go.opentelemetry.io/otel/api/trace"
 )
 
 // Clients contains clients which are responsible for creating and destrying reources
@@ -35,15 +41,134 @@ type Clients struct {
 	Getter         clients.Getter
 	Browser        clients.System
 	ImageLog       clients.ImageLog
+
+	// clients for resources created on a remote Docker engine
+	dockerHosts      map[string]*Clients
+	dockerHostsMutex sync.Mutex
+}
+
+// ForDockerHost returns clients which create containers using the Docker
+// engine at host, clients are only created once for each host
+func (cc *Clients) ForDockerHost(host string) (*Clients, error) {
+	if host == "" {
+		return cc, nil
+	}
+
+	cc.dockerHostsMutex.Lock()
+	defer cc.dockerHostsMutex.Unlock()
+
+	if hc, ok := cc.dockerHosts[host]; ok {
+		return hc, nil
+	}
+
+	dc, err := clients.NewDockerForHost(host)
+	if err != nil {
+		return nil, err
+	}
+
+	hc := &Clients{
+		ContainerTasks: clients.NewDockerTasks(dc, cc.ImageLog, cc.Logger),
+		Docker:         dc,
+		Kubernetes:     cc.Kubernetes,
+		Helm:           cc.Helm,
+		Command:        cc.Command,
+		HTTP:           cc.HTTP,
+		Nomad:          cc.Nomad,
+		Logger:         cc.Logger,
+		Getter:         cc.Getter,
+		Browser:        cc.Browser,
+		ImageLog:       cc.ImageLog,
+	}
+
+	if cc.dockerHosts == nil {
+		cc.dockerHosts = map[string]*Clients{}
+	}
+
+	cc.dockerHosts[host] = hc
+
+	return hc, nil
 }
 
 // Engine defines an interface for the Shipyard engine
 type Engine interface {
 	GetClients() *Clients
 	Apply(string) ([]config.Resource, error)
+	ApplyWithVariables(string, map[string]string) ([]config.Resource, error)
+	ApplyWithOptions(string, map[string]string, Options) ([]config.Resource, error)
 	Destroy(string, bool) error
+	DestroyWithOptions(string, bool, Options) error
 	ResourceCount() int
 	Blueprint() *config.Blueprint
+	Outputs() []*config.Output
+}
+
+// DefaultParallelism is the number of resources which are created or
+// destroyed at the same time when the options are not set
+const DefaultParallelism = 10
+
+// Options control which resources Apply and Destroy change and how many
+// are changed at the same time
+type Options struct {
+	// Parallelism is the maximum number of resources which are created or
+	// destroyed at the same time, resources which do not depend on each other
+	// are processed concurrently. When 0 the number is not limited
+	Parallelism int
+
+	// Targets restricts Apply to the matching resources and their dependencies,
+	// and Destroy to the matching resources and the resources which depend on them.
+	// Targets are written as [type].[name] and can contain wildcards e.g. container.*
+	Targets []string
+
+	// Replace is a list of resources which Apply destroys and creates again along
+	// with the resources which depend on them, e.g. k8s_cluster.k3s
+	Replace []string
+
+	// Parse controls how the config files for the blueprint are parsed
+	Parse config.ParseOptions
+}
+
+// DefaultOptions returns the options used by Apply, ApplyWithVariables and
+// Destroy
+func DefaultOptions() Options {
+	return Options{Parallelism: DefaultParallelism}
+}
+
+// defaultRetries is the number of times creating a resource is retried when
+// the resource does not set retries, these resource types are prone to transient
+// failures such as image pull timeouts and slow API servers
+var defaultRetries = map[config.ResourceType]int{
+	config.TypeContainer: 2,
+	config.TypeSidecar:   2,
+	config.TypeHelm:      2,
+	config.TypeK8sConfig: 2,
+	config.TypeNomadJob:  2,
+}
+
+// defaultRetryInterval is the time to wait before the first retry when the
+// resource does not set retry_interval
+const defaultRetryInterval = 5 * time.Second
+
+// semaphore limits the number of provider calls which run at the same time
+type semaphore chan struct{}
+
+func newSemaphore(n int) semaphore {
+	if n <= 0 {
+		return nil
+	}
+
+	return make(semaphore, n)
+}
+
+func (s semaphore) acquire() {
+	if s != nil {
+		s <- struct{}{}
+	}
+}
+
+func (s semaphore) release() {
+	if s != nil {
+		<-s
+	}
 }
 
 // EngineImpl is responsible for creating and destroying resources
@@ -52,7 +177,9 @@ type EngineImpl struct {
 	config      *config.Config
 	log         hclog.Logger
 	getProvider getProviderFunc
-	sync        sync.Mutex
+	// getRefresher returns the provider used to check a resource for drift
+	getRefresher getProviderFunc
+	sync         sync.Mutex
 }
 
 // defines a function which is used for generating providers
@@ -105,6 +232,7 @@ func New(l hclog.Logger) (Engine, error) {
 	e := &EngineImpl{}
 	e.log = l
 	e.getProvider = generateProviderImpl
+	e.getRefresher = generateProviderImpl
 
 	// Set the standard writer to our logger as the DAG uses the standard library log.
 	log.SetOutput(l.StandardWriter(&hclog.StandardLoggerOptions{ForceLevel: hclog.Trace}))
@@ -117,6 +245,9 @@ func New(l hclog.Logger) (Engine, error) {
 
 	e.clients = cl
 
+	// data blocks query the runtime using the clients
+	registerDataSources(cl)
+
 	return e, nil
 }
 
@@ -127,49 +258,105 @@ func (e *EngineImpl) GetClients() *Clients {
 
 // Apply the current config creating the resources
 func (e *EngineImpl) Apply(path string) ([]config.Resource, error) {
-	d, err := e.readConfig(path)
+	return e.ApplyWithVariables(path, nil)
+}
+
+// ApplyWithVariables applies the current config creating the resources,
+// variables overrides the default values of variables defined in the config
+func (e *EngineImpl) ApplyWithVariables(path string, variables map[string]string) ([]config.Resource, error) {
+	return e.ApplyWithOptions(path, variables, DefaultOptions())
+}
+
+// ApplyWithOptions applies the current config creating the resources, opts
+// selects the resources which are created
+func (e *EngineImpl) ApplyWithOptions(path string, variables map[string]string, opts Options) (res []config.Resource, err error) {
+	start := time.Now()
+	defer func() { metrics.ObserveOperation("apply", start, err) }()
+
+	ctx, span := tracing.Tracer().Start(context.Background(), "apply", trace.WithAttributes(kv.String("path", path)))
+	defer span.End()
+
+	d, err := e.readConfig(path, variables, opts.Parse)
 	if err != nil {
+		tracing.RecordError(ctx, span, err)
+		return nil, err
+	}
+
+	targets, err := targetResources(d, opts.Targets, false)
+	if err != nil {
+		tracing.RecordError(ctx, span, err)
+		return nil, err
+	}
+
+	e.refresh()
+
+	err = e.replaceResources(d, opts.Replace)
+	if err != nil {
+		tracing.RecordError(ctx, span, err)
 		return nil, err
 	}
 
 	createdResource := []config.Resource{}
+	createdMutex := sync.Mutex{}
+	sem := newSemaphore(opts.Parallelism)
 
-	// walk the dag and apply the config
+	// walk the dag and apply the config, the walker calls the callback
+	// concurrently for resources which do not depend on each other
 	w := dag.Walker{}
 	w.Callback = func(v dag.Vertex) (diags tfdiags.Diagnostics) {
 		// check if the resource needs to be created and if so create
-		if r, ok := v.(config.Resource); ok &&
+		if r, ok := v.(config.Resource); ok && targets.contains(r) &&
 			(r.Info().Status == config.PendingCreation ||
 				r.Info().Status == config.PendingModification ||
 				r.Info().Status == config.Failed) {
 
+			sem.acquire()
+			defer sem.release()
+
+			rctx, rspan := startResourceSpan(ctx, r)
+			defer rspan.End()
+
 			// get the provider to create the resource
 			p := e.getProvider(r, e.clients)
 			if p == nil {
 				r.Info().Status = config.Failed
-				return diags.Append(fmt.Errorf("Unable to create provider for resource Name: %s, Type: %s", r.Info().Name, r.Info().Type))
+				err := fmt.Errorf("Unable to create provider for resource Name: %s, Type: %s", r.Info().Name, r.Info().Type)
+				tracing.RecordError(rctx, rspan, err)
+				return diags.Append(err)
+			}
+
+			// resources pending creation may have been left behind by a run which
+			// was interrupted before the state was saved
+			if r.Info().Status == config.PendingCreation && e.exists(r) {
+				e.log.Info("Resource already exists, it will be re-created", "ref", r.Info().Name, "type", r.Info().Type)
+				r.Info().Status = config.PendingModification
 			}
 
 			// if we are pending modification or failed try remove the old instance and
 			// create again
 			if r.Info().Status == config.PendingModification || r.Info().Status == config.Failed {
-				err = p.Destroy()
+				err := e.destroyResource(rctx, r, p)
 				if err != nil {
 					r.Info().Status = config.Failed
+					tracing.RecordError(rctx, rspan, err)
 					return diags.Append(err)
 				}
 			}
 
 			// create the resource
-			err = p.Create()
+			err := e.createResource(rctx, r, p)
 			if err != nil {
 				r.Info().Status = config.Failed
+				tracing.RecordError(rctx, rspan, err)
 				return diags.Append(err)
 			}
 
 			// set the status
 			r.Info().Status = config.Applied
+
+			createdMutex.Lock()
 			createdResource = append(createdResource, r)
+			createdMutex.Unlock()
 		}
 
 		return nil
@@ -179,6 +366,18 @@ func (e *EngineImpl) Apply(path string) ([]config.Resource, error) {
 	tf := w.Wait()
 	if tf.Err() != nil {
 		err = tf.Err()
+		tracing.RecordError(ctx, span, err)
+	}
+
+	// outputs are evaluated once all the resources have been created
+	if err == nil {
+		for _, o := range e.config.Outputs {
+			err = o.Evaluate()
+			if err != nil {
+				tracing.RecordError(ctx, span, err)
+				break
+			}
+		}
 	}
 
 	// update the status of anything which is pending update as this
@@ -190,7 +389,7 @@ func (e *EngineImpl) Apply(path string) ([]config.Resource, error) {
 		}
 	}
 
-	if len(e.config.Resources) > 0 {
+	if len(e.config.Resources) > 0 || len(e.config.Outputs) > 0 {
 		// save the state regardless of error
 		jerr := e.config.ToJSON(utils.StatePath())
 		if jerr != nil {
@@ -200,40 +399,81 @@ func (e *EngineImpl) Apply(path string) ([]config.Resource, error) {
 		return createdResource, err
 	}
 
-	return nil, tf.Err()
+	return nil, err
 }
 
 // Destroy the resources defined by the config
 func (e *EngineImpl) Destroy(path string, allResources bool) error {
-	d, err := e.readConfig(path)
+	return e.DestroyWithOptions(path, allResources, DefaultOptions())
+}
+
+// DestroyWithOptions destroys the resources in the current config, opts
+// selects the resources which are destroyed
+func (e *EngineImpl) DestroyWithOptions(path string, allResources bool, opts Options) (err error) {
+	start := time.Now()
+	defer func() { metrics.ObserveOperation("destroy", start, err) }()
+
+	ctx, span := tracing.Tracer().Start(context.Background(), "destroy", trace.WithAttributes(kv.String("path", path)))
+	defer span.End()
+
+	d, err := e.readConfig(path, nil, opts.Parse)
+	if err != nil {
+		tracing.RecordError(ctx, span, err)
+		return err
+	}
+
+	targets, err := targetResources(d, opts.Targets, true)
 	if err != nil {
+		tracing.RecordError(ctx, span, err)
 		return err
 	}
 
-	// make sure we destroy everything
+	// make sure we destroy everything, when targets are set only the
+	// targeted resources are changed
 	if allResources {
 		for _, i := range e.config.Resources {
-			i.Info().Status = config.PendingUpdate
+			if targets.contains(i) {
+				i.Info().Status = config.PendingUpdate
+			}
 		}
 	}
 
+	// merging the config with the state changes the status of every
+	// resource, resources which are not targeted keep their status from
+	// the state
+	state := config.New()
+	if targets != nil {
+		state.FromJSON(utils.StatePath())
+	}
+
+	sem := newSemaphore(opts.Parallelism)
+
 	// walk the dag and apply the config
 	w := dag.Walker{}
 	w.Reverse = true
 	w.Callback = func(v dag.Vertex) (diags tfdiags.Diagnostics) {
 		// check if the resource needs to be created and if so create
-		if r, ok := v.(config.Resource); ok && r.Info().Status == config.PendingUpdate {
+		if r, ok := v.(config.Resource); ok && targets.contains(r) && r.Info().Status == config.PendingUpdate {
+			sem.acquire()
+			defer sem.release()
+
+			rctx, rspan := startResourceSpan(ctx, r)
+			defer rspan.End()
+
 			// get the provider to create the resource
 			p := e.getProvider(r, e.clients)
 			if p == nil {
 				r.Info().Status = config.Failed
-				return diags.Append(fmt.Errorf("Unable to create provider for resource Name: %s, Type: %s", r.Info().Name, r.Info().Type))
+				err := fmt.Errorf("Unable to create provider for resource Name: %s, Type: %s", r.Info().Name, r.Info().Type)
+				tracing.RecordError(rctx, rspan, err)
+				return diags.Append(err)
 			}
 
 			// execute
-			err = p.Destroy()
+			err := e.destroyResource(rctx, r, p)
 			if err != nil {
 				r.Info().Status = config.Failed
+				tracing.RecordError(rctx, rspan, err)
 				return diags.Append(err)
 			}
 
@@ -248,14 +488,27 @@ func (e *EngineImpl) Destroy(path string, allResources bool) error {
 	tf := w.Wait()
 	if tf.Err() != nil {
 		err = tf.Err()
+		tracing.RecordError(ctx, span, err)
 	}
 
 	// remove any destroyed nodes from the state
 	cn := config.New()
 	for _, i := range e.config.Resources {
-		if i.Info().Status != config.Destroyed {
-			cn.AddResource(i)
+		if i.Info().Status == config.Destroyed {
+			continue
 		}
+
+		if !targets.contains(i) {
+			// resources which are only in the config are not added
+			sr := stateResource(state, i)
+			if sr == nil {
+				continue
+			}
+
+			i.Info().Status = sr.Info().Status
+		}
+
+		cn.AddResource(i)
 	}
 
 	// save the state regardless of error
@@ -272,6 +525,262 @@ func (e *EngineImpl) Destroy(path string, allResources bool) error {
 	return tf.Err()
 }
 
+// stateResource returns the resource in the state with the same id as r
+func stateResource(state *config.Config, r config.Resource) config.Resource {
+	for _, sr := range state.Resources {
+		if config.ResourceID(sr) == config.ResourceID(r) {
+			return sr
+		}
+	}
+
+	return nil
+}
+
+// targetSet is the set of resources selected with Options.Targets, a nil set
+// contains every resource
+type targetSet map[dag.Vertex]bool
+
+func (t targetSet) contains(r config.Resource) bool {
+	return t == nil || t[r]
+}
+
+// targetResources returns the resources matching targets along with their
+// dependencies, or the resources which depend on them when dependents is true
+func targetResources(d *dag.AcyclicGraph, targets []string, dependents bool) (targetSet, error) {
+	if len(targets) == 0 {
+		return nil, nil
+	}
+
+	set := targetSet{}
+	for _, t := range targets {
+		found := false
+
+		for _, v := range d.Vertices() {
+			r, ok := v.(config.Resource)
+			if !ok {
+				continue
+			}
+
+			match, err := resourceMatches(t, r)
+			if err != nil {
+				return nil, xerrors.Errorf("Invalid target %s: %w", t, err)
+			}
+
+			if !match {
+				continue
+			}
+
+			found = true
+			set[v] = true
+
+			// edges go from a dependency to the resource which depends on it
+			var related *dag.Set
+			if dependents {
+				related, err = d.Ancestors(v)
+			} else {
+				related, err = d.Descendents(v)
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+			for _, rv := range related.List() {
+				set[rv] = true
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("Target %s does not match any resources", t)
+		}
+	}
+
+	return set, nil
+}
+
+// resourceMatches returns true when the [type].[name] of the resource matches
+// pattern, patterns can contain wildcards e.g. container.*
+func resourceMatches(pattern string, r config.Resource) (bool, error) {
+	match, err := path.Match(pattern, config.ResourceID(r))
+	if err != nil || match {
+		return match, err
+	}
+
+	// resources in modules can also be matched without the module
+	return path.Match(pattern, fmt.Sprintf("%s.%s", r.Info().Type, r.Info().Name))
+}
+
+// replaceResources marks the resources in replace as PendingModification,
+// resources which depend on a resource pending modification are also marked
+// so that they are created again with the new instance
+func (e *EngineImpl) replaceResources(d *dag.AcyclicGraph, replace []string) error {
+	for _, rp := range replace {
+		found := false
+
+		for _, r := range e.config.Resources {
+			match, err := resourceMatches(rp, r)
+			if err != nil {
+				return xerrors.Errorf("Invalid resource to replace %s: %w", rp, err)
+			}
+
+			if !match {
+				continue
+			}
+
+			found = true
+
+			// resources which have not been created are created anyway
+			if r.Info().Status == config.PendingUpdate {
+				r.Info().Status = config.PendingModification
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("Resource to replace %s does not match any resources", rp)
+		}
+	}
+
+	for _, r := range e.config.Resources {
+		if r.Info().Status != config.PendingModification {
+			continue
+		}
+
+		// edges go from a dependency to the resource which depends on it
+		deps, err := d.Ancestors(r)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range deps.List() {
+			if dr, ok := v.(config.Resource); ok && dr.Info().Status == config.PendingUpdate {
+				e.log.Debug("Resource depends on a resource which will be re-created", "ref", dr.Info().Name, "type", dr.Info().Type, "dependency", r.Info().Name)
+				dr.Info().Status = config.PendingModification
+			}
+		}
+	}
+
+	return nil
+}
+
+// createResource calls create for the provider, failures are retried with an
+// exponential backoff. The resource is destroyed before each retry to remove
+// anything which was partially created
+func (e *EngineImpl) createResource(ctx context.Context, r config.Resource, p providers.Provider) error {
+	err := e.runHooks(r, "on_create", r.Info().OnCreate, config.HookBefore)
+	if err != nil {
+		return err
+	}
+
+	retries := defaultRetries[r.Info().Type]
+	if r.Info().Retries != nil {
+		retries = *r.Info().Retries
+	}
+
+	interval := defaultRetryInterval
+	if d, err := time.ParseDuration(r.Info().RetryInterval); err == nil {
+		interval = d
+	}
+
+	err = traceProviderCall(ctx, r, "create", p.Create)
+	for i := 1; i <= retries && err != nil; i++ {
+		e.log.Info("Unable to create resource, retrying", "ref", r.Info().Name, "type", r.Info().Type, "attempt", i, "retries", retries, "interval", interval, "error", err)
+
+		time.Sleep(interval)
+		interval *= 2
+
+		derr := traceProviderCall(ctx, r, "destroy", withoutContext(p.Destroy))
+		if derr != nil {
+			return xerrors.Errorf("Unable to clean up after failed create (%s): %w", err, derr)
+		}
+
+		err = traceProviderCall(ctx, r, "create", p.Create)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return e.runHooks(r, "on_create", r.Info().OnCreate, config.HookAfter)
+}
+
+// destroyResource calls destroy for the provider and runs the on_destroy hooks
+func (e *EngineImpl) destroyResource(ctx context.Context, r config.Resource, p providers.Provider) error {
+	err := e.runHooks(r, "on_destroy", r.Info().OnDestroy, config.HookBefore)
+	if err != nil {
+		return err
+	}
+
+	err = traceProviderCall(ctx, r, "destroy", withoutContext(p.Destroy))
+	if err != nil {
+		return err
+	}
+
+	return e.runHooks(r, "on_destroy", r.Info().OnDestroy, config.HookAfter)
+}
+
+// runHooks executes the hooks for a resource which run at when, a hook which
+// fails stops the remaining hooks and fails the resource
+func (e *EngineImpl) runHooks(r config.Resource, typ string, hooks []config.Hook, when string) error {
+	for _, h := range hooks {
+		if !h.Runs(when) {
+			continue
+		}
+
+		e.log.Info("Running hook", "ref", r.Info().Name, "type", r.Info().Type, "hook", typ, "when", when, "cmd", h.Command)
+
+		err := e.clients.Command.Execute(h.Command, h.Arguments...)
+		if err != nil {
+			return xerrors.Errorf("Unable to run %s hook for %s.%s: %w", typ, r.Info().Type, r.Info().Name, err)
+		}
+	}
+
+	return nil
+}
+
+// exists returns true when the runtime objects for a resource can be found,
+// only providers which implement Refresher are checked
+func (e *EngineImpl) exists(r config.Resource) bool {
+	p := e.getRefresher(r, e.clients)
+	if _, ok := p.(providers.Refresher); !ok {
+		return false
+	}
+
+	ids, err := p.Lookup()
+	if err != nil {
+		e.log.Debug("Unable to lookup resource", "ref", r.Info().Name, "type", r.Info().Type, "error", err)
+		return false
+	}
+
+	return len(ids) > 0
+}
+
+// refresh inspects the runtime for resources which have already been created,
+// resources which have been deleted outside of Shipyard are marked
+// PendingModification so that they are created again
+func (e *EngineImpl) refresh() {
+	for _, r := range e.config.Resources {
+		if r.Info().Status != config.PendingUpdate {
+			continue
+		}
+
+		p, ok := e.getRefresher(r, e.clients).(providers.Refresher)
+		if !ok {
+			continue
+		}
+
+		drifted, err := p.Drifted()
+		if err != nil {
+			e.log.Debug("Unable to refresh resource", "ref", r.Info().Name, "type", r.Info().Type, "error", err)
+			continue
+		}
+
+		if drifted {
+			e.log.Info("Resource has changed outside of Shipyard, it will be re-created", "ref", r.Info().Name, "type", r.Info().Type)
+			r.Info().Status = config.PendingModification
+		}
+	}
+}
+
 // ResourceCount defines the number of resources in a plan
 func (e *EngineImpl) ResourceCount() int {
 	return e.config.ResourceCount()
@@ -282,17 +791,25 @@ func (e *EngineImpl) Blueprint() *config.Blueprint {
 	return e.config.Blueprint
 }
 
-func (e *EngineImpl) readConfig(path string) (*dag.AcyclicGraph, error) {
+// Outputs returns the outputs for the current config
+func (e *EngineImpl) Outputs() []*config.Output {
+	return e.config.Outputs
+}
+
+func (e *EngineImpl) readConfig(path string, variables map[string]string, opts config.ParseOptions) (*dag.AcyclicGraph, error) {
 	// load the new config
 	cc := config.New()
 	if path != "" {
 		if utils.IsHCLFile(path) {
-			err := config.ParseHCLFile(path, cc)
+			err := config.ParseHCLFileWithOptions(path, cc, variables, opts)
 			if err != nil {
 				return nil, err
 			}
 		} else {
-			err := config.ParseFolder(path, cc)
+			files, _ := config.ConfigFiles(path, opts.MaxFolderDepth)
+			e.log.Debug("Parsing configuration", "path", path, "files", files)
+
+			err := config.ParseFolderWithOptions(path, cc, variables, opts)
 			if err != nil {
 				return nil, err
 			}
@@ -332,40 +849,164 @@ func (e *EngineImpl) readConfig(path string) (*dag.AcyclicGraph, error) {
 	return d, nil
 }
 
+// startResourceSpan starts a new span for the given resource as a child of ctx
+func startResourceSpan(ctx context.Context, r config.Resource) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(
+		ctx,
+		fmt.Sprintf("%s.%s", r.Info().Type, r.Info().Name),
+		trace.WithAttributes(tracing.ResourceAttributes(r.Info().Name, string(r.Info().Type))...),
+	)
+}
+
+// traceProviderCall executes the provider function f inside a span with the given name,
+// errors are recorded in the span and the provider error metrics. The context passed
+// to f contains the span so that spans created by f are its children
+func traceProviderCall(ctx context.Context, r config.Resource, name string, f func(context.Context) error) error {
+	ctx, span := tracing.Tracer().Start(ctx, fmt.Sprintf("provider.%s", name))
+	defer span.End()
+
+	err := f(ctx)
+	if err != nil {
+		tracing.RecordError(ctx, span, err)
+		metrics.IncProviderError(r.Info().Type, name)
+	}
+
+	return err
+}
+
+// withoutContext adapts a provider function which does not use the context
+// so that it can be called with traceProviderCall
+func withoutContext(f func() error) func(context.Context) error {
+	return func(context.Context) error {
+		return f()
+	}
+}
+
 // generateProviderImpl returns providers grouped together in order of execution
 func generateProviderImpl(c config.Resource, cc *Clients) providers.Provider {
+	// resources on a remote Docker engine use the clients for that engine
+	if h := resourceDockerHost(c); h != "" {
+		hc, err := cc.ForDockerHost(h)
+		if err != nil {
+			cc.Logger.Error("Unable to create Docker client", "ref", c.Info().Name, "docker_host", h, "error", err)
+			return nil
+		}
+
+		cc = hc
+	}
+
 	switch c.Info().Type {
 	case config.TypeContainer:
 		return providers.NewContainer(c.(*config.Container), cc.ContainerTasks, cc.HTTP, cc.Logger)
+	case config.TypeCertificateCA:
+		return providers.NewCertificateCA(c.(*config.CertificateCA), cc.Logger)
+	case config.TypeCertificateLeaf:
+		return providers.NewCertificateLeaf(c.(*config.CertificateLeaf), cc.Logger)
+	case config.TypeTemplate:
+		return providers.NewTemplate(c.(*config.Template), cc.Logger)
+	case config.TypeContainerBuild:
+		return providers.NewContainerBuild(c.(*config.ContainerBuild), cc.ContainerTasks, cc.Logger)
 	case config.TypeContainerIngress:
 		return providers.NewContainerIngress(c.(*config.ContainerIngress), cc.ContainerTasks, cc.Logger)
 	case config.TypeSidecar:
 		return providers.NewContainerSidecar(c.(*config.Sidecar), cc.ContainerTasks, cc.HTTP, cc.Logger)
+	case config.TypeCopy:
+		return providers.NewCopy(c.(*config.Copy), cc.ContainerTasks, cc.Logger)
+	case config.TypeNullResource:
+		return providers.NewNull(c, cc.Logger)
+	case config.TypeRandomPassword, config.TypeRandomID:
+		return providers.NewRandom(c, cc.Logger)
+	case config.TypeLoadBalancer:
+		return providers.NewLoadBalancer(c.(*config.LoadBalancer), cc.ContainerTasks, cc.Logger)
+	case config.TypeDNS:
+		return providers.NewDNS(c.(*config.DNS), cc.ContainerTasks, cc.Logger)
+	case config.TypeAWS:
+		return providers.NewAWS(c.(*config.AWS), cc.ContainerTasks, cc.HTTP, cc.Logger)
+	case config.TypeNetworkRoute:
+		return providers.NewNetworkRoute(c.(*config.NetworkRoute), cc.ContainerTasks, cc.Logger)
+	case config.TypeTrafficCapture:
+		return providers.NewTrafficCapture(c.(*config.TrafficCapture), cc.ContainerTasks, cc.HTTP, cc.Logger)
+	case config.TypeChaos:
+		return providers.NewChaos(c.(*config.Chaos), cc.ContainerTasks, cc.Logger)
+	case config.TypeMockAPI:
+		return providers.NewMockAPI(c.(*config.MockAPI), cc.ContainerTasks, cc.Logger)
+	case config.TypeMinIO:
+		return providers.NewMinIO(c.(*config.MinIO), cc.ContainerTasks, cc.HTTP, cc.Logger)
+	case config.TypeVault:
+		return providers.NewVault(c.(*config.Vault), cc.ContainerTasks, cc.HTTP, cc.Logger)
+	case config.TypeContainerRegistry:
+		return providers.NewContainerRegistry(c.(*config.ContainerRegistry), cc.ContainerTasks, cc.HTTP, cc.Logger)
 	case config.TypeDocs:
 		return providers.NewDocs(c.(*config.Docs), cc.ContainerTasks, cc.Logger)
 	case config.TypeExecRemote:
 		return providers.NewRemoteExec(c.(*config.ExecRemote), cc.ContainerTasks, cc.Logger)
 	case config.TypeExecLocal:
 		return providers.NewExecLocal(c.(*config.ExecLocal), cc.Command, cc.Logger)
+	case config.TypeLocalService:
+		return providers.NewLocalService(c.(*config.LocalService), cc.Command, cc.HTTP, cc.Logger)
+	case config.TypeHelmRepository:
+		return providers.NewHelmRepository(c.(*config.HelmRepository), cc.Helm, cc.Logger)
 	case config.TypeHelm:
-		return providers.NewHelm(c.(*config.Helm), cc.Kubernetes, cc.Helm, cc.Getter, cc.Logger)
+		return providers.NewHelm(c.(*config.Helm), cc.Kubernetes, cc.Helm, cc.Getter, cc.HTTP, cc.Logger)
+	case config.TypeImageCache:
+		return providers.NewImageCache(c.(*config.ImageCache), cc.ContainerTasks, cc.Logger)
 	case config.TypeIngress:
 		return providers.NewIngress(c.(*config.Ingress), cc.ContainerTasks, cc.Logger)
+	case config.TypeKustomize:
+		return providers.NewKustomize(c.(*config.Kustomize), cc.Kubernetes, cc.Logger)
+	case config.TypeK8sNamespace:
+		return providers.NewK8sNamespace(c.(*config.K8sNamespace), cc.Kubernetes, cc.Logger)
+	case config.TypeK8sSecret:
+		return providers.NewK8sSecret(c.(*config.K8sSecret), cc.Kubernetes, cc.Logger)
+	case config.TypeK8sConfigMap:
+		return providers.NewK8sConfigMap(c.(*config.K8sConfigMap), cc.Kubernetes, cc.Logger)
+	case config.TypeHTTPCheck:
+		return providers.NewHTTPCheck(c.(*config.HTTPCheck), cc.HTTP, cc.Logger)
+	case config.TypeConsulConfig:
+		return providers.NewConsulConfig(c.(*config.ConsulConfig), cc.HTTP, cc.Logger)
+	case config.TypeSQLExec:
+		return providers.NewSQLExec(c.(*config.SQLExec), cc.ContainerTasks, cc.Logger)
+	case config.TypeGitOps:
+		return providers.NewGitOps(c.(*config.GitOps), cc.Kubernetes, cc.Helm, cc.Logger)
+	case config.TypeK8sWait:
+		return providers.NewK8sWait(c.(*config.K8sWait), cc.Kubernetes, cc.Logger)
 	case config.TypeK8sCluster:
 		return providers.NewK8sCluster(c.(*config.K8sCluster), cc.ContainerTasks, cc.Kubernetes, cc.HTTP, cc.Logger)
+	case config.TypeK8sClusterExternal:
+		return providers.NewK8sClusterExternal(c.(*config.K8sClusterExternal), cc.Kubernetes, cc.Logger)
 	case config.TypeK8sConfig:
 		return providers.NewK8sConfig(c.(*config.K8sConfig), cc.Kubernetes, cc.Logger)
 	case config.TypeK8sIngress:
 		return providers.NewK8sIngress(c.(*config.K8sIngress), cc.ContainerTasks, cc.Logger)
 	case config.TypeNomadCluster:
-		return providers.NewNomadCluster(c.(*config.NomadCluster), cc.ContainerTasks, cc.Nomad, cc.Logger)
+		return providers.NewNomadCluster(c.(*config.NomadCluster), cc.ContainerTasks, cc.Nomad, cc.HTTP, cc.Logger)
+	case config.TypeLocalIngress:
+		return providers.NewLocalIngress(c.(*config.LocalIngress), cc.ContainerTasks, cc.Kubernetes, cc.Logger)
 	case config.TypeNomadIngress:
 		return providers.NewNomadIngress(c.(*config.NomadIngress), cc.ContainerTasks, cc.Logger)
 	case config.TypeNomadJob:
 		return providers.NewNomadJob(c.(*config.NomadJob), cc.Nomad, cc.Logger)
 	case config.TypeNetwork:
 		return providers.NewNetwork(c.(*config.Network), cc.Docker, cc.Logger)
+	case config.TypeDockerVolume:
+		return providers.NewDockerVolume(c.(*config.DockerVolume), cc.ContainerTasks, cc.Logger)
 	}
 
 	return nil
 }
+
+// resourceDockerHost returns the Docker engine a resource is created on
+func resourceDockerHost(c config.Resource) string {
+	switch v := c.(type) {
+	case *config.Container:
+		return v.DockerHost
+	case *config.Network:
+		return v.DockerHost
+	case *config.K8sCluster:
+		return v.DockerHost
+	case *config.NomadCluster:
+		return v.DockerHost
+	}
+
+	return ""
+}
@@ -11,15 +11,19 @@ import (
 	"path/filepath"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/hashicorp/go-hclog"
+	clientmocks "github.com/shipyard-run/shipyard/pkg/clients/mocks"
 	"github.com/shipyard-run/shipyard/pkg/config"
 	"github.com/shipyard-run/shipyard/pkg/providers"
 	"github.com/shipyard-run/shipyard/pkg/providers/mocks"
 	"github.com/shipyard-run/shipyard/pkg/utils"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 var lock = sync.Mutex{}
@@ -69,9 +73,10 @@ func setupTestsBase(returnVals map[string]error, state string) (Engine, *config.
 
 	cl := &Clients{}
 	e := &EngineImpl{
-		clients:     cl,
-		log:         hclog.NewNullLogger(),
-		getProvider: generateProviderMock(p, returnVals),
+		clients:      cl,
+		log:          hclog.NewNullLogger(),
+		getProvider:  generateProviderMock(p, returnVals),
+		getRefresher: generateRefresherMock(nil, nil),
 	}
 
 	return e, nil, p, setupState(state)
@@ -93,6 +98,23 @@ func generateProviderMock(mp *[]*mocks.MockProvider, returnVals map[string]error
 	}
 }
 
+// generateRefresherMock returns providers which report drift for the
+// resources named in drifted and find the resources named in existing
+func generateRefresherMock(drifted, existing map[string]bool) getProviderFunc {
+	return func(c config.Resource, cc *Clients) providers.Provider {
+		m := mocks.New(c)
+		m.On("Drifted").Return(drifted[c.Info().Name], nil)
+
+		ids := []string{}
+		if existing[c.Info().Name] {
+			ids = append(ids, c.Info().Name)
+		}
+		m.On("Lookup").Return(ids, nil)
+
+		return m
+	}
+}
+
 func getTestFiles(tests string) string {
 	e, err := os.Executable()
 	if err != nil {
@@ -120,6 +142,316 @@ func TestApplyCallsProviderInCorrectOrder(t *testing.T) {
 	assert.Contains(t, []string{"consul-http", "consul", "vault", "vault-http"}, (*mp)[5].Config().Info().Name)
 }
 
+func TestApplyWithTargetOnlyCreatesTargetAndDependencies(t *testing.T) {
+	e, _, mp, cleanup := setupTests(nil)
+	defer cleanup()
+
+	opts := DefaultOptions()
+	opts.Targets = []string{"helm.consul"}
+
+	_, err := e.ApplyWithOptions("../../functional_tests/test_fixtures/single_k3s_cluster", nil, opts)
+	assert.NoError(t, err)
+
+	require.Len(t, *mp, 3)
+	assert.Equal(t, "cloud", (*mp)[0].Config().Info().Name)
+	assert.Equal(t, "k3s", (*mp)[1].Config().Info().Name)
+	assert.Equal(t, "consul", (*mp)[2].Config().Info().Name)
+}
+
+func TestApplyWithWildcardTargetCreatesMatchingResources(t *testing.T) {
+	e, _, mp, cleanup := setupTests(nil)
+	defer cleanup()
+
+	opts := DefaultOptions()
+	opts.Targets = []string{"helm.*"}
+
+	_, err := e.ApplyWithOptions("../../functional_tests/test_fixtures/single_k3s_cluster", nil, opts)
+	assert.NoError(t, err)
+
+	testAssertMethodCalled(t, mp, "Create", 4)
+}
+
+func TestApplyOptionsDoNotApplyToLaterCalls(t *testing.T) {
+	e, _, mp, cleanup := setupTests(nil)
+	defer cleanup()
+
+	opts := DefaultOptions()
+	opts.Targets = []string{"network.cloud"}
+
+	_, err := e.ApplyWithOptions("../../functional_tests/test_fixtures/single_k3s_cluster", nil, opts)
+	require.NoError(t, err)
+	testAssertMethodCalled(t, mp, "Create", 1)
+
+	_, err = e.Apply("../../functional_tests/test_fixtures/single_k3s_cluster")
+	assert.NoError(t, err)
+
+	// the remaining resources are created by the second apply
+	testAssertMethodCalled(t, mp, "Create", 6)
+}
+
+func TestApplyWithUnknownTargetReturnsError(t *testing.T) {
+	e, _, mp, cleanup := setupTests(nil)
+	defer cleanup()
+
+	opts := DefaultOptions()
+	opts.Targets = []string{"container.missing"}
+
+	_, err := e.ApplyWithOptions("../../functional_tests/test_fixtures/single_k3s_cluster", nil, opts)
+	assert.Error(t, err)
+
+	testAssertMethodCalled(t, mp, "Create", 0)
+}
+
+func TestApplyWithReplaceRecreatesResourceAndDependents(t *testing.T) {
+	e, _, mp, cleanup := setupTests(nil)
+	defer cleanup()
+
+	_, err := e.Apply("../../functional_tests/test_fixtures/single_k3s_cluster")
+	require.NoError(t, err)
+
+	opts := DefaultOptions()
+	opts.Replace = []string{"k8s_cluster.k3s"}
+
+	_, err = e.ApplyWithOptions("../../functional_tests/test_fixtures/single_k3s_cluster", nil, opts)
+	assert.NoError(t, err)
+
+	// the cluster, the helm charts, and the ingresses are re-created, the
+	// network is not
+	testAssertMethodCalled(t, mp, "Destroy", 5)
+	testAssertMethodCalled(t, mp, "Create", 11)
+}
+
+func TestApplyRecreatesDependentsOfTaintedResources(t *testing.T) {
+	e, _, mp, cleanup := setupTests(nil)
+	defer cleanup()
+
+	_, err := e.Apply("../../functional_tests/test_fixtures/single_k3s_cluster")
+	require.NoError(t, err)
+
+	c := config.New()
+	require.NoError(t, c.FromJSON(utils.StatePath()))
+
+	r, err := c.FindResource("network.cloud")
+	require.NoError(t, err)
+	r.Info().Status = config.PendingModification
+	require.NoError(t, c.ToJSON(utils.StatePath()))
+
+	_, err = e.Apply("../../functional_tests/test_fixtures/single_k3s_cluster")
+	assert.NoError(t, err)
+
+	// every resource depends on the network
+	testAssertMethodCalled(t, mp, "Destroy", 6)
+}
+
+func setupRetryTest(t *testing.T, failures int) (Engine, *mocks.MockProvider, string, func()) {
+	e, _, _, cleanup := setupTests(nil)
+
+	dir, err := ioutils.TempDir("", "")
+	require.NoError(t, err)
+
+	ioutil.WriteFile(filepath.Join(dir, "config.hcl"), []byte(`
+network "cloud" {
+  subnet         = "10.5.0.0/16"
+  retries        = 2
+  retry_interval = "1ms"
+}
+`), os.ModePerm)
+
+	m := mocks.New(config.NewNetwork("cloud"))
+	if failures > 0 {
+		m.On("Create").Times(failures).Return(fmt.Errorf("boom"))
+	}
+	m.On("Create").Return(nil)
+	m.On("Destroy").Return(nil)
+
+	e.(*EngineImpl).getProvider = func(c config.Resource, cc *Clients) providers.Provider {
+		return m
+	}
+
+	return e, m, dir, func() {
+		cleanup()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestApplyRetriesFailedResources(t *testing.T) {
+	e, m, dir, cleanup := setupRetryTest(t, 2)
+	defer cleanup()
+
+	_, err := e.Apply(dir)
+	assert.NoError(t, err)
+
+	// partially created resources are removed before each retry
+	m.AssertNumberOfCalls(t, "Create", 3)
+	m.AssertNumberOfCalls(t, "Destroy", 2)
+}
+
+func TestApplyReturnsErrorWhenRetriesExhausted(t *testing.T) {
+	e, m, dir, cleanup := setupRetryTest(t, 3)
+	defer cleanup()
+
+	_, err := e.Apply(dir)
+	assert.Error(t, err)
+
+	m.AssertNumberOfCalls(t, "Create", 3)
+}
+
+func TestApplyReturnsCreateErrorWhenCleanupFails(t *testing.T) {
+	e, m, dir, cleanup := setupRetryTest(t, 0)
+	defer cleanup()
+
+	m.ExpectedCalls = nil
+	m.On("Create").Return(fmt.Errorf("create boom"))
+	m.On("Destroy").Return(fmt.Errorf("destroy boom"))
+
+	_, err := e.Apply(dir)
+	require.Error(t, err)
+
+	assert.Contains(t, err.Error(), "create boom")
+	assert.Contains(t, err.Error(), "destroy boom")
+
+	m.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func setupHooksTest(t *testing.T, hookErr error) (Engine, *mocks.MockProvider, *clientmocks.MockCommand, string, func()) {
+	e, _, _, cleanup := setupTests(nil)
+
+	dir, err := ioutils.TempDir("", "")
+	require.NoError(t, err)
+
+	ioutil.WriteFile(filepath.Join(dir, "config.hcl"), []byte(`
+network "cloud" {
+  subnet = "10.5.0.0/16"
+
+  on_create {
+    when = "before"
+    cmd  = "setup"
+  }
+
+  on_create {
+    cmd  = "seed"
+    args = ["--all"]
+  }
+
+  on_destroy {
+    cmd = "cleanup"
+  }
+}
+`), os.ModePerm)
+
+	m := mocks.New(config.NewNetwork("cloud"))
+	m.On("Create").Return(nil)
+	m.On("Destroy").Return(nil)
+
+	e.(*EngineImpl).getProvider = func(c config.Resource, cc *Clients) providers.Provider {
+		return m
+	}
+
+	mc := &clientmocks.MockCommand{}
+	mc.On("Execute", mock.Anything, mock.Anything).Return(hookErr)
+	e.(*EngineImpl).clients.Command = mc
+
+	return e, m, mc, dir, func() {
+		cleanup()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestApplyRunsCreateHooks(t *testing.T) {
+	e, _, mc, dir, cleanup := setupHooksTest(t, nil)
+	defer cleanup()
+
+	_, err := e.Apply(dir)
+	require.NoError(t, err)
+
+	mc.AssertNumberOfCalls(t, "Execute", 2)
+	assert.Equal(t, "setup", mc.Calls[0].Arguments.String(0))
+	assert.Equal(t, "seed", mc.Calls[1].Arguments.String(0))
+	assert.Equal(t, []string{"--all"}, mc.Calls[1].Arguments.Get(1))
+}
+
+func TestApplyFailsResourceWhenHookFails(t *testing.T) {
+	e, m, _, dir, cleanup := setupHooksTest(t, fmt.Errorf("boom"))
+	defer cleanup()
+
+	_, err := e.Apply(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "on_create hook")
+
+	// before hooks stop the resource from being created
+	m.AssertNotCalled(t, "Create")
+
+	r, err := e.(*EngineImpl).config.FindResource("network.cloud")
+	require.NoError(t, err)
+	assert.Equal(t, config.Failed, r.Info().Status)
+}
+
+func TestDestroyRunsDestroyHooks(t *testing.T) {
+	e, m, mc, dir, cleanup := setupHooksTest(t, nil)
+	defer cleanup()
+
+	_, err := e.Apply(dir)
+	require.NoError(t, err)
+
+	err = e.Destroy(dir, true)
+	require.NoError(t, err)
+
+	m.AssertNumberOfCalls(t, "Destroy", 1)
+	mc.AssertNumberOfCalls(t, "Execute", 3)
+	assert.Equal(t, "cleanup", mc.Calls[2].Arguments.String(0))
+}
+
+func setupParallelismTest() (Engine, *int, func()) {
+	e, _, _, cleanup := setupTests(nil)
+
+	running := 0
+	maxRunning := 0
+	m := sync.Mutex{}
+
+	e.(*EngineImpl).getProvider = func(c config.Resource, cc *Clients) providers.Provider {
+		p := mocks.New(c)
+		p.On("Create").Run(func(args mock.Arguments) {
+			m.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			m.Unlock()
+
+			time.Sleep(20 * time.Millisecond)
+
+			m.Lock()
+			running--
+			m.Unlock()
+		}).Return(nil)
+
+		return p
+	}
+
+	return e, &maxRunning, cleanup
+}
+
+func TestApplyCreatesIndependentResourcesConcurrently(t *testing.T) {
+	e, maxRunning, cleanup := setupParallelismTest()
+	defer cleanup()
+
+	_, err := e.Apply("../../functional_tests/test_fixtures/single_k3s_cluster")
+	assert.NoError(t, err)
+
+	assert.Greater(t, *maxRunning, 1)
+}
+
+func TestApplyLimitsConcurrentResources(t *testing.T) {
+	e, maxRunning, cleanup := setupParallelismTest()
+	defer cleanup()
+
+	res, err := e.ApplyWithOptions("../../functional_tests/test_fixtures/single_k3s_cluster", nil, Options{Parallelism: 1})
+	assert.NoError(t, err)
+
+	assert.Len(t, res, 6)
+	assert.Equal(t, 1, *maxRunning)
+}
+
 func TestApplyCallsProviderCreateForEachProvider(t *testing.T) {
 	e, _, mp, cleanup := setupTests(nil)
 	defer cleanup()
@@ -190,6 +522,55 @@ func TestApplySetsStatusForEachResource(t *testing.T) {
 	assert.Len(t, *mp, 0)
 }
 
+func TestApplyRecreatesResourcesWhichHaveDrifted(t *testing.T) {
+	e, _, mp, cleanup := setupTestsWithState(nil, mergedState)
+	defer cleanup()
+
+	e.(*EngineImpl).getRefresher = generateRefresherMock(map[string]bool{"dc1": true}, nil)
+
+	_, err := e.Apply("")
+	assert.NoError(t, err)
+
+	testAssertMethodCalled(t, mp, "Destroy", 1)
+	testAssertMethodCalled(t, mp, "Create", 1)
+}
+
+func TestApplyRecreatesResourcesPendingCreationWhichExist(t *testing.T) {
+	e, _, mp, cleanup := setupTests(nil)
+	defer cleanup()
+
+	e.(*EngineImpl).getRefresher = generateRefresherMock(nil, map[string]bool{"k3s": true})
+
+	_, err := e.Apply("../../functional_tests/test_fixtures/single_k3s_cluster")
+	assert.NoError(t, err)
+
+	testAssertMethodCalled(t, mp, "Destroy", 1)
+	testAssertMethodCalled(t, mp, "Create", 6)
+}
+
+func TestApplyResumesAfterFailure(t *testing.T) {
+	e, _, mp, cleanup := setupTests(map[string]error{"k3s": fmt.Errorf("boom")})
+	defer cleanup()
+
+	_, err := e.Apply("../../functional_tests/test_fixtures/single_k3s_cluster")
+	assert.Error(t, err)
+
+	// the network is created, the cluster fails, and nothing which depends
+	// on the cluster is created
+	testAssertMethodCalled(t, mp, "Create", 2)
+
+	*mp = []*mocks.MockProvider{}
+	e.(*EngineImpl).getProvider = generateProviderMock(mp, nil)
+
+	_, err = e.Apply("../../functional_tests/test_fixtures/single_k3s_cluster")
+	assert.NoError(t, err)
+
+	// the failed cluster is removed and created along with the resources
+	// which were not created, the network is skipped
+	testAssertMethodCalled(t, mp, "Destroy", 1)
+	testAssertMethodCalled(t, mp, "Create", 5)
+}
+
 func TestDestroyCallsProviderDestroyForEachProvider(t *testing.T) {
 	e, _, mp, cleanup := setupTests(nil)
 	defer cleanup()
@@ -243,6 +624,40 @@ func TestDestroyCallsProviderDestroyInCorrectOrder(t *testing.T) {
 
 }
 
+func TestDestroyWithTargetDestroysTargetAndDependents(t *testing.T) {
+	e, _, mp, cleanup := setupTests(nil)
+	defer cleanup()
+
+	opts := DefaultOptions()
+	opts.Targets = []string{"k8s_cluster.k3s"}
+
+	err := e.DestroyWithOptions("../../functional_tests/test_fixtures/single_k3s_cluster", true, opts)
+	assert.NoError(t, err)
+
+	// the network does not depend on the cluster
+	require.Len(t, *mp, 5)
+	assert.Equal(t, "k3s", (*mp)[4].Config().Info().Name)
+}
+
+func TestDestroyWithTargetDoesNotChangeStatusOfOtherResources(t *testing.T) {
+	e, _, _, cleanup := setupTestsWithState(nil, appliedNetworkState)
+	defer cleanup()
+
+	opts := DefaultOptions()
+	opts.Targets = []string{"k8s_cluster.k3s"}
+
+	err := e.DestroyWithOptions("../../functional_tests/test_fixtures/single_k3s_cluster", true, opts)
+	assert.NoError(t, err)
+
+	sc := config.New()
+	err = sc.FromJSON(utils.StatePath())
+	require.NoError(t, err)
+
+	n, err := sc.FindResource("network.cloud")
+	require.NoError(t, err)
+	assert.Equal(t, config.Applied, n.Info().Status)
+}
+
 func testAssertMethodCalled(t *testing.T, p *[]*mocks.MockProvider, method string, n int, args ...interface{}) {
 	callCount := 0
 
@@ -287,3 +702,34 @@ var mergedState = `
   ]
 }
 `
+
+func TestForDockerHostReturnsClientsForEachHost(t *testing.T) {
+	cl := &Clients{Logger: hclog.NewNullLogger()}
+
+	lc, err := cl.ForDockerHost("")
+	assert.NoError(t, err)
+	assert.Same(t, cl, lc)
+
+	rc, err := cl.ForDockerHost("tcp://10.5.0.2:2376")
+	assert.NoError(t, err)
+	assert.NotSame(t, cl, rc)
+	assert.NotNil(t, rc.ContainerTasks)
+
+	rc2, err := cl.ForDockerHost("tcp://10.5.0.2:2376")
+	assert.NoError(t, err)
+	assert.Same(t, rc, rc2)
+}
+
+var appliedNetworkState = `
+{
+  "blueprint": null,
+  "resources": [
+	{
+      "name": "cloud",
+      "status": "applied",
+      "subnet": "10.5.0.0/16",
+      "type": "network"
+	}
+  ]
+}
+`
@@ -32,11 +32,37 @@ func (e *Engine) Apply(path string) ([]config.Resource, error) {
 	return nil, args.Error(1)
 }
 
+func (e *Engine) ApplyWithVariables(path string, vars map[string]string) ([]config.Resource, error) {
+	args := e.Called(path, vars)
+
+	if r, ok := args.Get(0).([]config.Resource); ok {
+		return r, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (e *Engine) ApplyWithOptions(path string, vars map[string]string, opts shipyard.Options) ([]config.Resource, error) {
+	args := e.Called(path, vars, opts)
+
+	if r, ok := args.Get(0).([]config.Resource); ok {
+		return r, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
 func (e *Engine) Destroy(path string, all bool) error {
 	args := e.Called(path, all)
 
 	return args.Error(0)
 }
+
+func (e *Engine) DestroyWithOptions(path string, all bool, opts shipyard.Options) error {
+	args := e.Called(path, all, opts)
+
+	return args.Error(0)
+}
 func (e *Engine) ResourceCount() int {
 	return e.Called().Int(0)
 }
@@ -47,3 +73,11 @@ func (e *Engine) Blueprint() *config.Blueprint {
 
 	return nil
 }
+
+func (e *Engine) Outputs() []*config.Output {
+	if o, ok := e.Called().Get(0).([]*config.Output); ok {
+		return o
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package tracing
+
+import (
+	"context"
+	"os"
+
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/kv"
+	"go.opentelemetry.io/otel/api/standard"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+)
+
+// Name is the instrumentation name used for all spans created by Shipyard
+const Name = "github.com/shipyard-run/shipyard"
+
+// EnvOTLPEndpoint is the environment variable which contains the address
+// of the OTLP collector spans are exported to e.g. localhost:55680.
+// When the variable is not set tracing is disabled.
+const EnvOTLPEndpoint = "OTEL_EXPORTER_OTLP_ENDPOINT"
+
+// Setup configures the global trace provider to export spans to the OTLP collector
+// defined by the environment variable OTEL_EXPORTER_OTLP_ENDPOINT.
+// If the variable is not set Setup does nothing and all spans are discarded.
+//
+// The returned function must be called before the process exits to ensure the
+// connection to the collector is closed cleanly.
+func Setup(version string) (func(), error) {
+	addr := os.Getenv(EnvOTLPEndpoint)
+	if addr == "" {
+		return func() {}, nil
+	}
+
+	exp, err := otlp.NewExporter(otlp.WithInsecure(), otlp.WithAddress(addr))
+	if err != nil {
+		return nil, xerrors.Errorf("Unable to create OTLP exporter for %s: %w", addr, err)
+	}
+
+	tp, err := sdktrace.NewProvider(
+		sdktrace.WithConfig(sdktrace.Config{DefaultSampler: sdktrace.AlwaysSample()}),
+		sdktrace.WithResource(resource.New(
+			standard.ServiceNameKey.String("shipyard"),
+			standard.ServiceVersionKey.String(version),
+		)),
+	)
+	if err != nil {
+		exp.Stop()
+		return nil, xerrors.Errorf("Unable to create trace provider: %w", err)
+	}
+
+	// use a simple processor rather than a batcher, shipyard is a short
+	// lived process and spans must be sent before the command exits
+	sp := sdktrace.NewSimpleSpanProcessor(exp)
+	tp.RegisterSpanProcessor(sp)
+
+	global.SetTraceProvider(tp)
+
+	return func() {
+		// unregistering shuts down the processor so that no spans are
+		// exported once the exporter is stopped
+		tp.UnregisterSpanProcessor(sp)
+		exp.Stop()
+	}, nil
+}
+
+// Tracer returns the tracer used to create Shipyard spans
+func Tracer() trace.Tracer {
+	return global.Tracer(Name)
+}
+
+// RecordError records the error on the span and marks the span as failed
+func RecordError(ctx context.Context, span trace.Span, err error) {
+	span.RecordError(ctx, err, trace.WithErrorStatus(codes.Internal))
+}
+
+// ResourceAttributes returns the standard set of span attributes
+// which identify a resource
+func ResourceAttributes(name, typeName string) []kv.KeyValue {
+	return []kv.KeyValue{
+		kv.String("resource.name", name),
+		kv.String("resource.type", typeName),
+	}
+}
@@ -0,0 +1,46 @@
+package tracing
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestSetupWithNoEndpointDoesNotConfigureProvider(t *testing.T) {
+	os.Unsetenv(EnvOTLPEndpoint)
+
+	shutdown, err := Setup("dev")
+	assert.NoError(t, err)
+	assert.NotNil(t, shutdown)
+
+	_, ok := global.TraceProvider().(*sdktrace.Provider)
+	assert.False(t, ok)
+
+	shutdown()
+}
+
+func TestSetupWithEndpointConfiguresProvider(t *testing.T) {
+	os.Setenv(EnvOTLPEndpoint, "localhost:55680")
+	defer os.Unsetenv(EnvOTLPEndpoint)
+
+	shutdown, err := Setup("dev")
+	assert.NoError(t, err)
+	defer shutdown()
+
+	_, ok := global.TraceProvider().(*sdktrace.Provider)
+	assert.True(t, ok)
+
+	global.SetTraceProvider(trace.NoopProvider{})
+}
+
+func TestResourceAttributesReturnsNameAndType(t *testing.T) {
+	attrs := ResourceAttributes("consul", "container")
+
+	assert.Len(t, attrs, 2)
+	assert.Equal(t, "consul", attrs[0].Value.AsString())
+	assert.Equal(t, "container", attrs[1].Value.AsString())
+}
@@ -0,0 +1,300 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/go-getter"
+)
+
+const (
+	ociManifestType        = "application/vnd.oci.image.manifest.v1+json"
+	dockerManifestType     = "application/vnd.docker.distribution.manifest.v2+json"
+	ociTitleAnnotation     = "org.opencontainers.image.title"
+	defaultOCIReferenceTag = "latest"
+)
+
+// Getters returns the go-getter getters used to fetch blueprints, this is
+// the default set of getters with the addition of the oci getter
+func Getters() map[string]getter.Getter {
+	g := map[string]getter.Getter{}
+	for k, v := range getter.Getters {
+		g[k] = v
+	}
+
+	g["oci"] = &OCIGetter{}
+
+	return g
+}
+
+// OCIGetter fetches a blueprint stored as an OCI artifact in a container
+// registry e.g. oci://ghcr.io/org/blueprint:v1.2, tar.gz layers are
+// extracted to the destination folder and any other layers are written as
+// files using the title annotation for the name. Registries on localhost
+// are accessed over http, all other registries use https.
+type OCIGetter struct {
+	client *getter.Client
+}
+
+type ociManifest struct {
+	Layers []ociDescriptor `json:"layers"`
+}
+
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+// ClientMode returns the mode for the getter, artifacts are always
+// fetched to a folder
+func (g *OCIGetter) ClientMode(u *url.URL) (getter.ClientMode, error) {
+	return getter.ClientModeDir, nil
+}
+
+// SetClient sets the go-getter client
+func (g *OCIGetter) SetClient(c *getter.Client) {
+	g.client = c
+}
+
+// GetFile is not supported, artifacts can only be fetched to a folder
+func (g *OCIGetter) GetFile(dst string, u *url.URL) error {
+	return fmt.Errorf("OCI artifacts can only be fetched to a folder")
+}
+
+// Get fetches the artifact referenced by the url and writes the layers
+// to the folder dst
+func (g *OCIGetter) Get(dst string, u *url.URL) error {
+	repo, ref := parseOCIReference(u.Path)
+	if repo == "" {
+		return fmt.Errorf("Invalid OCI reference %s, references must be in the form oci://registry/repository:tag", u.String())
+	}
+
+	r := &ociRegistry{
+		base:   registryURL(u.Host),
+		repo:   repo,
+		client: http.DefaultClient,
+	}
+
+	m, err := r.manifest(ref)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(dst, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	for _, l := range m.Layers {
+		err := r.extractLayer(l, dst)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseOCIReference splits the path of an oci url into the repository
+// and the tag or digest
+func parseOCIReference(path string) (string, string) {
+	path = strings.TrimPrefix(path, "/")
+
+	if i := strings.Index(path, "@"); i > 0 {
+		return path[:i], path[i+1:]
+	}
+
+	// the tag is after the last colon as long as it is not part of the path
+	if i := strings.LastIndex(path, ":"); i > 0 && !strings.Contains(path[i:], "/") {
+		return path[:i], path[i+1:]
+	}
+
+	return path, defaultOCIReferenceTag
+}
+
+func registryURL(host string) string {
+	h, _, err := net.SplitHostPort(host)
+	if err != nil {
+		h = host
+	}
+
+	if h == "localhost" || h == "127.0.0.1" {
+		return "http://" + host
+	}
+
+	return "https://" + host
+}
+
+type ociRegistry struct {
+	base   string
+	repo   string
+	token  string
+	client *http.Client
+}
+
+func (r *ociRegistry) manifest(ref string) (*ociManifest, error) {
+	resp, err := r.get(fmt.Sprintf("%s/v2/%s/manifests/%s", r.base, r.repo, ref), ociManifestType+", "+dockerManifestType)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	m := &ociManifest{}
+	err = json.NewDecoder(resp.Body).Decode(m)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to decode manifest for %s:%s: %s", r.repo, ref, err)
+	}
+
+	return m, nil
+}
+
+func (r *ociRegistry) extractLayer(l ociDescriptor, dst string) error {
+	resp, err := r.get(fmt.Sprintf("%s/v2/%s/blobs/%s", r.base, r.repo, l.Digest), "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	tmp, err := ioutil.TempFile("", "oci-layer")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, resp.Body)
+	tmp.Close()
+	if err != nil {
+		return fmt.Errorf("Unable to download layer %s: %s", l.Digest, err)
+	}
+
+	if strings.HasSuffix(l.MediaType, "tar+gzip") || strings.HasSuffix(l.MediaType, "tar.gzip") {
+		return (&getter.TarGzipDecompressor{}).Decompress(dst, tmp.Name(), true)
+	}
+
+	name := l.Annotations[ociTitleAnnotation]
+	if name == "" || filepath.Base(name) != name {
+		return fmt.Errorf("Unable to extract layer %s, the layer is not a tar.gz archive and does not have a valid %s annotation", l.Digest, ociTitleAnnotation)
+	}
+
+	d, err := ioutil.ReadFile(tmp.Name())
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(dst, name), d, 0644)
+}
+
+// get performs a request against the registry, when the registry requires
+// authentication an anonymous token is requested and the request retried
+func (r *ociRegistry) get(uri, accept string) (*http.Response, error) {
+	resp, err := r.do(uri, accept)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized && r.token == "" {
+		resp.Body.Close()
+
+		err := r.authenticate(resp.Header.Get("WWW-Authenticate"))
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err = r.do(uri, accept)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("Unable to fetch %s, registry returned status %d", uri, resp.StatusCode)
+	}
+
+	return resp, nil
+}
+
+func (r *ociRegistry) do(uri, accept string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to fetch %s: %s", uri, err)
+	}
+
+	return resp, nil
+}
+
+var authParamRegexp = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// authenticate requests an anonymous token using the challenge returned
+// by the registry e.g. Bearer realm="https://ghcr.io/token",scope="..."
+func (r *ociRegistry) authenticate(challenge string) error {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return fmt.Errorf("Unable to authenticate with registry, unsupported challenge %q", challenge)
+	}
+
+	params := url.Values{}
+	realm := ""
+
+	for _, m := range authParamRegexp.FindAllStringSubmatch(challenge, -1) {
+		if m[1] == "realm" {
+			realm = m[2]
+			continue
+		}
+
+		params.Set(m[1], m[2])
+	}
+
+	if realm == "" {
+		return fmt.Errorf("Unable to authenticate with registry, challenge does not contain a realm")
+	}
+
+	resp, err := r.client.Get(realm + "?" + params.Encode())
+	if err != nil {
+		return fmt.Errorf("Unable to authenticate with registry: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Unable to authenticate with registry, token request returned status %d", resp.StatusCode)
+	}
+
+	t := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+
+	err = json.NewDecoder(resp.Body).Decode(&t)
+	if err != nil {
+		return fmt.Errorf("Unable to decode registry token: %s", err)
+	}
+
+	r.token = t.Token
+	if r.token == "" {
+		r.token = t.AccessToken
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createLayer(t *testing.T, files map[string]string) []byte {
+	buf := &bytes.Buffer{}
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+
+	for name, contents := range files {
+		err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(contents))})
+		require.NoError(t, err)
+
+		_, err = tw.Write([]byte(contents))
+		require.NoError(t, err)
+	}
+
+	tw.Close()
+	gw.Close()
+
+	return buf.Bytes()
+}
+
+func setupRegistry(t *testing.T, token string) (*httptest.Server, *url.URL) {
+	layer := createLayer(t, map[string]string{"main.hcl": `network "cloud" {}`})
+
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			fmt.Fprintf(rw, `{"token": "%s"}`, token)
+			return
+		}
+
+		if token != "" && r.Header.Get("Authorization") != "Bearer "+token {
+			rw.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test",scope="repository:org/bp:pull"`, ts.URL))
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v2/org/bp/manifests/v1.2":
+			fmt.Fprint(rw, `{"layers": [
+				{"mediaType": "application/vnd.oci.image.layer.v1.tar+gzip", "digest": "sha256:layer"},
+				{"mediaType": "text/markdown", "digest": "sha256:readme", "annotations": {"org.opencontainers.image.title": "README.md"}}
+			]}`)
+		case "/v2/org/bp/blobs/sha256:layer":
+			rw.Write(layer)
+		case "/v2/org/bp/blobs/sha256:readme":
+			fmt.Fprint(rw, "# Blueprint")
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	u, _ := url.Parse(ts.URL)
+
+	return ts, u
+}
+
+func TestParseOCIReference(t *testing.T) {
+	repo, ref := parseOCIReference("/org/bp:v1.2")
+	assert.Equal(t, "org/bp", repo)
+	assert.Equal(t, "v1.2", ref)
+
+	repo, ref = parseOCIReference("/org/bp@sha256:abc")
+	assert.Equal(t, "org/bp", repo)
+	assert.Equal(t, "sha256:abc", ref)
+
+	repo, ref = parseOCIReference("/org/bp")
+	assert.Equal(t, "org/bp", repo)
+	assert.Equal(t, "latest", ref)
+}
+
+func TestOCIGetterExtractsLayers(t *testing.T) {
+	ts, u := setupRegistry(t, "")
+	defer ts.Close()
+
+	dst, err := ioutil.TempDir("", "oci")
+	require.NoError(t, err)
+	defer os.RemoveAll(dst)
+
+	u.Path = "/org/bp:v1.2"
+	err = (&OCIGetter{}).Get(dst, u)
+	require.NoError(t, err)
+
+	d, err := ioutil.ReadFile(filepath.Join(dst, "main.hcl"))
+	assert.NoError(t, err)
+	assert.Equal(t, `network "cloud" {}`, string(d))
+
+	d, err = ioutil.ReadFile(filepath.Join(dst, "README.md"))
+	assert.NoError(t, err)
+	assert.Equal(t, "# Blueprint", string(d))
+}
+
+func TestOCIGetterRequestsTokenWhenUnauthorized(t *testing.T) {
+	ts, u := setupRegistry(t, "abc123")
+	defer ts.Close()
+
+	dst, err := ioutil.TempDir("", "oci")
+	require.NoError(t, err)
+	defer os.RemoveAll(dst)
+
+	u.Path = "/org/bp:v1.2"
+	err = (&OCIGetter{}).Get(dst, u)
+	require.NoError(t, err)
+
+	assert.FileExists(t, filepath.Join(dst, "main.hcl"))
+}
+
+func TestOCIGetterWithMissingTagReturnsError(t *testing.T) {
+	ts, u := setupRegistry(t, "")
+	defer ts.Close()
+
+	dst, err := ioutil.TempDir("", "oci")
+	require.NoError(t, err)
+	defer os.RemoveAll(dst)
+
+	u.Path = "/org/bp:v9"
+	err = (&OCIGetter{}).Get(dst, u)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+}
@@ -2,8 +2,10 @@ package utils
 
 import (
 	"io/ioutil"
+	"net"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/gosuri/uitable/util/strutil"
@@ -177,3 +179,70 @@ func TestDockerSockReturnsCorrectValue(t *testing.T) {
 	ds := GetDockerSock()
 	assert.Equal(t, "/var/run/docker.sock", ds)
 }
+
+func TestDockerHostReturnsDefaultSocket(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows uses a named pipe")
+	}
+
+	dh := os.Getenv("DOCKER_HOST")
+	os.Unsetenv("DOCKER_HOST")
+	defer os.Setenv("DOCKER_HOST", dh)
+
+	assert.Equal(t, "unix:///var/run/docker.sock", GetDockerHost())
+}
+
+func TestDockerHostAndIPUseDockerHostEnv(t *testing.T) {
+	dh := os.Getenv("DOCKER_HOST")
+	os.Setenv("DOCKER_HOST", "tcp://10.5.0.2:2376")
+	defer os.Setenv("DOCKER_HOST", dh)
+
+	assert.Equal(t, "tcp://10.5.0.2:2376", GetDockerHost())
+	assert.Equal(t, "10.5.0.2", GetDockerIP())
+}
+
+func TestDockerIPReturnsBridgeIPForLocalDocker(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Docker Desktop uses host.docker.internal")
+	}
+
+	dh := os.Getenv("DOCKER_HOST")
+	os.Setenv("DOCKER_HOST", "tcp://localhost:2375")
+	defer os.Setenv("DOCKER_HOST", dh)
+
+	ip := net.ParseIP(GetDockerIP())
+	assert.NotNil(t, ip)
+	assert.NotNil(t, ip.To4())
+}
+
+func TestDockerHostAddressReturnsRemoteHost(t *testing.T) {
+	assert.Equal(t, "10.5.0.2", GetDockerHostAddress("tcp://10.5.0.2:2376"))
+	assert.Equal(t, "build.example.com", GetDockerHostAddress("ssh://nic@build.example.com:2222"))
+}
+
+func TestDockerHostAddressReturnsLoopbackForLocalEngine(t *testing.T) {
+	assert.Equal(t, "127.0.0.1", GetDockerHostAddress(""))
+	assert.Equal(t, "127.0.0.1", GetDockerHostAddress("unix:///var/run/docker.sock"))
+}
+
+func TestContainerEngineUsesEnv(t *testing.T) {
+	ce := os.Getenv("SHIPYARD_CONTAINER_ENGINE")
+	os.Setenv("SHIPYARD_CONTAINER_ENGINE", EnginePodman)
+	defer os.Setenv("SHIPYARD_CONTAINER_ENGINE", ce)
+
+	assert.Equal(t, EnginePodman, GetContainerEngine())
+	assert.Equal(t, GetPodmanSock(), GetDockerSock())
+	assert.Equal(t, "host.containers.internal", GetDockerIP())
+}
+
+func TestPodmanSockUsesRuntimeDirWhenRootless(t *testing.T) {
+	if os.Getuid() == 0 {
+		t.Skip("Rootful Podman uses /run/podman")
+	}
+
+	rd := os.Getenv("XDG_RUNTIME_DIR")
+	os.Setenv("XDG_RUNTIME_DIR", "/run/user/1000")
+	defer os.Setenv("XDG_RUNTIME_DIR", rd)
+
+	assert.Equal(t, "/run/user/1000/podman/podman.sock", GetPodmanSock())
+}
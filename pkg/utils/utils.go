@@ -2,6 +2,8 @@ package utils
 
 import (
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -126,6 +128,12 @@ func ShipyardTemp() string {
 	return dir
 }
 
+// DataDir returns the location of the persistent data for blueprints,
+// usually $HOME/.shipyard/data
+func DataDir() string {
+	return filepath.Join(ShipyardHome(), "data")
+}
+
 // StateDir returns the location of the shipyard
 // state, usually $HOME/.shipyard/state
 func StateDir() string {
@@ -142,6 +150,12 @@ func ImageCacheLog() string {
 	return fmt.Sprintf("%s/images.log", ShipyardHome())
 }
 
+// DaemonTokenPath returns the location of the token used to authenticate
+// requests which change the environment with the API server
+func DaemonTokenPath() string {
+	return fmt.Sprintf("%s/daemon.token", ShipyardHome())
+}
+
 // IsLocalFolder tests if the given path is a localfolder and can
 // exist in the current filesystem
 // TODO make more robust with error messages
@@ -161,6 +175,7 @@ func IsLocalFolder(path string) bool {
 }
 
 // IsHCLFile tests if the given path resolves to a HCL config file
+// written in either the native or the JSON syntax
 func IsHCLFile(path string) bool {
 	s, err := os.Stat(path)
 	if err != nil {
@@ -171,7 +186,7 @@ func IsHCLFile(path string) bool {
 		return false
 	}
 
-	if filepath.Ext(s.Name()) != ".hcl" {
+	if filepath.Ext(s.Name()) != ".hcl" && filepath.Ext(s.Name()) != ".json" {
 		return false
 	}
 
@@ -205,6 +220,48 @@ func GetHelmLocalFolder(blueprint string) string {
 	return filepath.Join(ShipyardHome(), "helm_charts", blueprint)
 }
 
+// Container engines which can be used to create resources
+const (
+	EngineDocker = "docker"
+	EnginePodman = "podman"
+)
+
+const dockerSock = "/var/run/docker.sock"
+
+// GetContainerEngine returns the container engine resources are created
+// with, this is the value of SHIPYARD_CONTAINER_ENGINE when set otherwise
+// Podman is used when its socket exists and the Docker socket does not
+func GetContainerEngine() string {
+	if e := os.Getenv("SHIPYARD_CONTAINER_ENGINE"); e != "" {
+		return e
+	}
+
+	if _, err := os.Stat(dockerSock); err == nil {
+		return EngineDocker
+	}
+
+	if _, err := os.Stat(GetPodmanSock()); err == nil {
+		return EnginePodman
+	}
+
+	return EngineDocker
+}
+
+// GetPodmanSock returns the location of the socket for the Docker compatible
+// API of Podman, rootless Podman serves the API from the user runtime folder
+func GetPodmanSock() string {
+	if os.Getuid() == 0 {
+		return "/run/podman/podman.sock"
+	}
+
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" {
+		dir = fmt.Sprintf("/run/user/%d", os.Getuid())
+	}
+
+	return filepath.Join(dir, "podman", "podman.sock")
+}
+
 // GetDockerSock returns the location of the Docker sock depending on the platform
 func GetDockerSock() string {
 	//TODO: need to think about what happens if Docker is running at a TCP address rather than a socket
@@ -214,5 +271,82 @@ func GetDockerSock() string {
 		}
 	*/
 
-	return "/var/run/docker.sock"
+	if GetContainerEngine() == EnginePodman {
+		return GetPodmanSock()
+	}
+
+	return dockerSock
+}
+
+// defaultBridgeIP is the address of the default Docker bridge network
+const defaultBridgeIP = "172.17.0.1"
+
+// GetDockerHost returns the address of the Docker daemon, this is the
+// value of DOCKER_HOST when set otherwise the default for the platform
+func GetDockerHost() string {
+	if dh := os.Getenv("DOCKER_HOST"); dh != "" {
+		return dh
+	}
+
+	if runtime.GOOS == "windows" {
+		return "npipe:////./pipe/docker_engine"
+	}
+
+	return "unix://" + GetDockerSock()
+}
+
+// GetDockerIP returns the address containers can use to reach the machine
+// running Docker. For a remote Docker daemon this is the host from
+// DOCKER_HOST, for Docker Desktop on Mac and Windows this is the special
+// host.docker.internal name, on Linux this is the IP of the docker0 bridge.
+func GetDockerIP() string {
+	if u, err := url.Parse(os.Getenv("DOCKER_HOST")); err == nil && u.Scheme == "tcp" {
+		h := u.Hostname()
+		if h != "localhost" && h != "127.0.0.1" {
+			if ips, err := net.LookupIP(h); err == nil && len(ips) > 0 {
+				return ips[0].String()
+			}
+
+			return h
+		}
+	}
+
+	// Podman adds the host to /etc/hosts in each container
+	if GetContainerEngine() == EnginePodman {
+		return "host.containers.internal"
+	}
+
+	if runtime.GOOS != "linux" {
+		return "host.docker.internal"
+	}
+
+	i, err := net.InterfaceByName("docker0")
+	if err != nil {
+		return defaultBridgeIP
+	}
+
+	addrs, err := i.Addrs()
+	if err != nil {
+		return defaultBridgeIP
+	}
+
+	for _, a := range addrs {
+		if ipn, ok := a.(*net.IPNet); ok && ipn.IP.To4() != nil {
+			return ipn.IP.String()
+		}
+	}
+
+	return defaultBridgeIP
+}
+
+// GetDockerHostAddress returns the address ports published by the Docker
+// engine at host can be reached on from this machine, this is the host
+// name for tcp:// and ssh:// engines and 127.0.0.1 for a local engine
+func GetDockerHostAddress(host string) string {
+	u, err := url.Parse(host)
+	if err != nil || (u.Scheme != "tcp" && u.Scheme != "ssh") || u.Hostname() == "" {
+		return "127.0.0.1"
+	}
+
+	return u.Hostname()
 }